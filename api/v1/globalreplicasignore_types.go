@@ -58,7 +58,7 @@ type GlobalReplicasIgnoreStatus struct {
 	// +optional
 	IgnoredDeployments []IgnoredDeployment `json:"ignoredDeployments,omitempty"`
 
-	// LastUpdateTime is the last time the status was updated
+	// LastUpdateTime is the last time the status changed
 	// +optional
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 
@@ -77,6 +77,11 @@ type IgnoredDeployment struct {
 
 	// Reason why this deployment is being ignored
 	Reason string `json:"reason"`
+
+	// OverriddenBy is the name of the ReplicasOverride scaling the deployment
+	// anyway, its ignorePolicy being override
+	// +optional
+	OverriddenBy string `json:"overriddenBy,omitempty"`
 }
 
 // +kubebuilder:object:root=true
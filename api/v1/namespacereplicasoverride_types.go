@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceReplicasOverrideSpec scales every workload of its namespace by one
+// percentage, without selectors. It wins over the global config and the
+// NamespaceScalingDefault, ReplicasOverrides targeting a workload still win
+// over it, and ignored workloads are left alone.
+type NamespaceReplicasOverrideSpec struct {
+	// ReplicasPercentage is the percentage applied to every workload of the
+	// namespace, e.g. 50 runs a staging namespace at half its replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default:=100
+	ReplicasPercentage int32 `json:"replicasPercentage"`
+
+	// MinReplicas is the minimum number of replicas of the workloads, instead of the global minReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of replicas of the workloads, instead of the global maxReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// NamespaceReplicasOverrideStatus defines the observed state of NamespaceReplicasOverride
+type NamespaceReplicasOverrideStatus struct {
+	// TargetedDeployments is the number of deployments of the namespace the
+	// override applies to, those not ignored nor targeted by a ReplicasOverride
+	// +optional
+	TargetedDeployments int32 `json:"targetedDeployments,omitempty"`
+
+	// Conditions represent the latest available observations of the override
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=nro
+// +kubebuilder:printcolumn:name="Percentage",type="integer",JSONPath=".spec.replicasPercentage"
+// +kubebuilder:printcolumn:name="Deployments",type="integer",JSONPath=".status.targetedDeployments"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NamespaceReplicasOverride is the Schema for the namespacereplicasoverrides API.
+// Only the oldest NamespaceReplicasOverride of a namespace is used.
+type NamespaceReplicasOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceReplicasOverrideSpec   `json:"spec,omitempty"`
+	Status NamespaceReplicasOverrideStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceReplicasOverrideList contains a list of NamespaceReplicasOverride
+type NamespaceReplicasOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceReplicasOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceReplicasOverride{}, &NamespaceReplicasOverrideList{})
+}
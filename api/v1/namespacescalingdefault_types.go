@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceScalingDefaultSpec defines the scaling defaults of a namespace. They
+// are layered between the global config and explicit ReplicasOverrides: unset
+// fields keep the global value, and overrides still win for their targets.
+type NamespaceScalingDefaultSpec struct {
+	// ReplicasPercentage is the percentage applied to workloads of the namespace
+	// not targeted by an override, instead of the global percentage.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	ReplicasPercentage *int32 `json:"replicasPercentage,omitempty"`
+
+	// MinReplicas is the minimum number of replicas in the namespace, instead of the global minReplicas.
+	// It is kept within the global minReplicas and maxReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the maximum number of replicas in the namespace, instead of the global maxReplicas.
+	// It is kept within the global minReplicas and maxReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// Schedules replace ReplicasPercentage while they are active, the first active one wins.
+	// +optional
+	Schedules []ScalingSchedule `json:"schedules,omitempty"`
+}
+
+// ScalingSchedule is a recurring window (Schedule and Duration) or a one-off
+// time range (Start and End) with its own percentage
+type ScalingSchedule struct {
+	// Name describes the schedule in status and logs
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Schedule is a cron expression (e.g. "0 20 * * 1-5") marking the start of a recurring window
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is the length of a recurring window
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// TimeZone is the IANA time zone of Schedule, UTC if empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Start is the beginning of a one-off window
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the end of a one-off window
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// ReplicasPercentage is the percentage applied while the schedule is active
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	ReplicasPercentage int32 `json:"replicasPercentage"`
+}
+
+// Window returns the time window of the schedule
+func (s ScalingSchedule) Window() BlackoutWindow {
+	return BlackoutWindow{
+		Name:     s.Name,
+		Schedule: s.Schedule,
+		Duration: s.Duration,
+		TimeZone: s.TimeZone,
+		Start:    s.Start,
+		End:      s.End,
+	}
+}
+
+// NamespaceScalingDefaultStatus defines the observed state of NamespaceScalingDefault
+type NamespaceScalingDefaultStatus struct {
+	// ActiveSchedule is the name of the schedule currently in effect, if any
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+
+	// EffectivePercentage is the percentage currently applied to the namespace
+	// +optional
+	EffectivePercentage int32 `json:"effectivePercentage,omitempty"`
+
+	// Conditions represent the latest available observations of the defaults
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=nsd
+// +kubebuilder:printcolumn:name="Percentage",type="integer",JSONPath=".status.effectivePercentage"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".status.activeSchedule"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NamespaceScalingDefault is the Schema for the namespacescalingdefaults API.
+// Only the oldest NamespaceScalingDefault of a namespace is used.
+type NamespaceScalingDefault struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NamespaceScalingDefaultSpec   `json:"spec,omitempty"`
+	Status NamespaceScalingDefaultStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespaceScalingDefaultList contains a list of NamespaceScalingDefault
+type NamespaceScalingDefaultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NamespaceScalingDefault `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NamespaceScalingDefault{}, &NamespaceScalingDefaultList{})
+}
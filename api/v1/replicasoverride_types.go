@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -34,13 +36,26 @@ type ReplicasOverrideSpec struct {
 	// +optional
 	DeploymentRef *DeploymentReference `json:"deploymentRef,omitempty"`
 
-	// HPARef allows direct reference to a specific HPA.
+	// HPARef allows direct reference to a specific HPA in the namespace of the
+	// override. Its limits are scaled whatever kind it scales, including kinds
+	// the controller does not otherwise manage. The HPAReferenceResolved
+	// condition reports whether the HPA exists.
 	// +optional
 	HPARef *HPAReference `json:"hpaRef,omitempty"`
 
+	// TargetKinds restricts the override to the given kinds of scaled objects,
+	// e.g. only HorizontalPodAutoscaler to manage just the HPA limits of the
+	// selected deployments while leaving those without HPA alone, or only
+	// Deployment to scale raw replicas and leave HPA-managed deployments alone.
+	// Empty means every kind the controller manages.
+	// +optional
+	TargetKinds []TargetKind `json:"targetKinds,omitempty"`
+
 	// OverrideType specifies how the scaling should be applied.
-	// Valid values are "override" or "additive".
-	// +kubebuilder:validation:Enum=override;additive
+	// Valid values are "override" or "additive", or "bounds" to apply no
+	// percentage and only keep the replicas others set on the deployments,
+	// without HPA, between MinReplicas and MaxReplicas.
+	// +kubebuilder:validation:Enum=override;additive;bounds
 	// +kubebuilder:default:=override
 	OverrideType string `json:"overrideType"`
 
@@ -51,6 +66,50 @@ type ReplicasOverrideSpec struct {
 	// +kubebuilder:default:=100
 	ReplicasPercentage int32 `json:"replicasPercentage"`
 
+	// ScalingBasis is what the percentage applies to on targets with an HPA.
+	// Original scales the original min/max replicas of the HPA. HPADesired keeps
+	// the HPA min at the percentage of the replicas its metrics currently ask
+	// for, e.g. 120 always keeps 20% headroom above demand, and is recalculated
+	// whenever the HPA status changes.
+	// +kubebuilder:default:=Original
+	// +optional
+	ScalingBasis ScalingBasis `json:"scalingBasis,omitempty"`
+
+	// HeadroomReplicas is a number of warm spare replicas kept on top of the
+	// demand, for services with slow cold starts that need constant headroom
+	// during traffic ramps. The HPA min is kept at least that many replicas
+	// above what its metrics ask for, and targets without HPA get them on top
+	// of their scaled replicas.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HeadroomReplicas int32 `json:"headroomReplicas,omitempty"`
+
+	// Environments targets the workloads of identical per-environment stacks
+	// by the value of an environment label, each with its own percentage, so a
+	// single override covers e.g. prod and staging. Only workloads whose label
+	// value is listed are targeted; the selector or deploymentRef, when set,
+	// must match as well. Without either, the environments alone select the
+	// targets in the namespace of the override.
+	// +optional
+	Environments *EnvironmentTargeting `json:"environments,omitempty"`
+
+	// PercentageFrom reads the percentage of each target from one of its own
+	// labels or annotations, so one override can apply a different factor per
+	// workload. Targets without a valid value use ReplicasPercentage.
+	// +optional
+	PercentageFrom *PercentageSource `json:"percentageFrom,omitempty"`
+
+	// PercentageExpression is a CEL expression computing the percentage instead of
+	// ReplicasPercentage, e.g. `hour(now) < 8 ? 50 : 100`. It can read now,
+	// percentage (ReplicasPercentage, or the PercentageFrom value), namespace, name, labels and annotations of
+	// the workload, and triggers (latest value of each mapped trigger by name).
+	// ReplicasPercentage is used when the expression fails. The expression is
+	// compiled once per generation, the PercentageExpressionValid condition
+	// reports whether it compiles.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	PercentageExpression string `json:"percentageExpression,omitempty"`
+
 	// MinReplicas specifies the minimum number of replicas allowed.
 	// If not specified, the global minReplicas from the config will be used.
 	// +optional
@@ -62,6 +121,507 @@ type ReplicasOverrideSpec struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// RatioOf caps the replicas of the targets at a factor of the current
+	// replicas of another deployment they depend on, e.g. workers at most 4x
+	// the database proxies they connect through, recomputed whenever it
+	// scales. HPAs get their maxReplicas capped. The RatioOfResolved
+	// condition reports whether the deployment exists.
+	// +optional
+	RatioOf *RatioReference `json:"ratioOf,omitempty"`
+
+	// PreserveHPAMinForExternalMetrics keeps the original minReplicas of HPAs using
+	// External or Pods metrics and only scales their maxReplicas. Overrides the
+	// global config setting when set.
+	// +optional
+	PreserveHPAMinForExternalMetrics *bool `json:"preserveHPAMinForExternalMetrics,omitempty"`
+
+	// HPAPolicy chooses which limits of the HPAs of the targets are scaled,
+	// e.g. keeping a minReplicas that encodes an SLA floor while raising the
+	// maxReplicas for headroom. Both are scaled when unset.
+	// +optional
+	HPAPolicy *HPAPolicy `json:"hpaPolicy,omitempty"`
+
+	// CanaryScope restricts the override to one side of a Flagger canary while
+	// its analysis runs, for capacity experiments: Stable scales only the
+	// <name>-primary deployment serving stable traffic, Canary only the canary
+	// deployment. The deploymentRef or selector designates the canary target
+	// deployment, and outside the analysis window the override scales nothing.
+	// Empty scales the selected deployments regardless of canaries.
+	// +optional
+	CanaryScope CanaryScope `json:"canaryScope,omitempty"`
+
+	// IgnorePolicy controls whether the override scales the deployments a
+	// GlobalReplicasIgnore excludes: respect, the default, leaves them alone,
+	// override scales them anyway. Each exception is recorded in the status of
+	// the override and of the ignore rules excluding the deployment.
+	// +kubebuilder:validation:Enum=respect;override
+	// +optional
+	IgnorePolicy IgnorePolicy `json:"ignorePolicy,omitempty"`
+
+	// Mode is Enforce, the default, to scale the targets, or Monitor to leave
+	// them as they are and only report in monitoredTargets, and the
+	// monitored_replica_delta metric, the replicas the override would set on
+	// its deployments. Policies are rolled out team by team in Monitor mode
+	// before they are enforced.
+	// +kubebuilder:validation:Enum=Enforce;Monitor
+	// +optional
+	Mode OverrideMode `json:"mode,omitempty"`
+
+	// BaselineRefresh controls when the original replicas and HPA limits of the
+	// targets are re-captured, so a workload legitimately resized since it was
+	// first scaled stops scaling from a stale baseline. Overrides the global
+	// config setting when set.
+	// +optional
+	BaselineRefresh *BaselineRefresh `json:"baselineRefresh,omitempty"`
+
+	// ApplyStrategy controls how fast the override changes its targets.
+	// Immediate, the default, changes them all in the same pass. Rolling
+	// changes at most maxTargetsPerMinute of them per minute, those of the
+	// highest PriorityClass first, so an override selecting hundreds of
+	// deployments does not reconfigure them in one burst.
+	// +optional
+	ApplyStrategy *ApplyStrategy `json:"applyStrategy,omitempty"`
+
+	// MinWorkloadAge leaves targets created less than this long ago untouched,
+	// letting their initial rollout settle and their HPA collect metrics before
+	// they are scaled. Overrides the global config setting when set.
+	// +optional
+	MinWorkloadAge *metav1.Duration `json:"minWorkloadAge,omitempty"`
+
+	// WarmUp temporarily boosts the deployments of the override after a new
+	// ReplicaSet finished rolling out, smoothing cold-cache and JIT warm-up
+	// periods, and reverts automatically once it expires.
+	// +optional
+	WarmUp *WarmUpBoost `json:"warmUp,omitempty"`
+
+	// ClusterAutoscalerProtection keeps cluster-autoscaler from removing the
+	// nodes of the deployments of the override for a while after it scaled them
+	// up, so a pre-scale event is not undone by an aggressive node scale-down.
+	// +optional
+	ClusterAutoscalerProtection *ClusterAutoscalerProtection `json:"clusterAutoscalerProtection,omitempty"`
+
+	// Placeholder reserves the capacity of the computed replicas with a
+	// Deployment of pause pods requesting the resources of the target pods,
+	// instead of scaling the deployments of the override. The scheduler
+	// preempts the placeholders when the deployments burst, so capacity is
+	// reserved without running extra application replicas.
+	// +optional
+	Placeholder *Placeholder `json:"placeholder,omitempty"`
+
+	// Verification checks the availability of deployments after the override
+	// scaled them down and reverts the scale-down if availability drops. A
+	// reverted scale-down is not retried until the override changes.
+	// +optional
+	Verification *ScaleDownVerification `json:"verification,omitempty"`
+
+	// PreDownscaleDelay gives the pods a scale-down removes time to drain
+	// long-lived connections, e.g. of websocket gateways. The pods chosen are
+	// annotated with the end of the delay and given the lowest deletion cost,
+	// an optional webhook is told about them, and the replicas are only
+	// reduced once the delay passed. Applies to replicas scaled directly.
+	// +optional
+	PreDownscaleDelay *PreDownscaleDelay `json:"preDownscaleDelay,omitempty"`
+
+	// TrafficShift keeps the weight of the deployments among the backends of
+	// a Gateway API HTTPRoute in line with their ready capacity: traffic is
+	// shifted away before a scale-down removes replicas, and back as the
+	// replicas of a scale-up become ready. Service meshes implementing the
+	// Gateway API (GAMMA) honor the same weights. Applies to replicas scaled
+	// directly.
+	// +optional
+	TrafficShift *TrafficShift `json:"trafficShift,omitempty"`
+
+	// BlackoutWindows are periods during which the override must not change
+	// replicas at all, regardless of schedules or triggers (change freezes).
+	// +optional
+	BlackoutWindows []BlackoutWindow `json:"blackoutWindows,omitempty"`
+
+	// Notifications are destinations told about changes this override makes to
+	// its targets, so application teams get notified about their own services.
+	// +optional
+	Notifications []NotificationTarget `json:"notifications,omitempty"`
+
+	// Group names a set of overrides, possibly in several namespaces, applied
+	// and reverted as a unit for coordinated multi-service capacity changes.
+	// Either every target of the group is updated or none: when one cannot be
+	// updated the targets changed in the same pass are put back. Pausing,
+	// freezing or rolling back one member holds or rolls back the whole group.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// CanaryScope is the side of a canary an override scales
+// +kubebuilder:validation:Enum=Stable;Canary
+type CanaryScope string
+
+const (
+	// CanaryScopeStable scales the deployment serving stable traffic
+	CanaryScopeStable CanaryScope = "Stable"
+	// CanaryScopeCanary scales the canary deployment
+	CanaryScopeCanary CanaryScope = "Canary"
+)
+
+// IgnorePolicy is how an override treats the deployments ignore rules exclude
+type IgnorePolicy string
+
+const (
+	// IgnorePolicyRespect leaves ignored deployments alone
+	IgnorePolicyRespect IgnorePolicy = "respect"
+	// IgnorePolicyOverride scales ignored deployments the override targets
+	IgnorePolicyOverride IgnorePolicy = "override"
+)
+
+// OverrideTypeBounds is the override type only enforcing the min/max replicas
+// of deployments without HPA, whatever replicas others set
+const OverrideTypeBounds = "bounds"
+
+// OverrideMode is whether an override scales its targets or only reports
+type OverrideMode string
+
+const (
+	// OverrideModeEnforce scales the targets
+	OverrideModeEnforce OverrideMode = "Enforce"
+	// OverrideModeMonitor leaves the targets alone and reports the replicas it would set
+	OverrideModeMonitor OverrideMode = "Monitor"
+)
+
+// BaselineRefreshPolicy is when the originals of a target are re-captured
+// +kubebuilder:validation:Enum=Never;OnSpecChangeByOtherManager;Periodic
+type BaselineRefreshPolicy string
+
+const (
+	// BaselineRefreshNever keeps the originals captured the first time a target was scaled
+	BaselineRefreshNever BaselineRefreshPolicy = "Never"
+	// BaselineRefreshOnSpecChange re-captures an original as soon as another
+	// field manager (kubectl, a GitOps tool) writes the replicas or HPA limits
+	BaselineRefreshOnSpecChange BaselineRefreshPolicy = "OnSpecChangeByOtherManager"
+	// BaselineRefreshPeriodic re-captures an original from a change by another
+	// field manager only once the baseline is IntervalDays old, changes made
+	// before are reverted as with Never
+	BaselineRefreshPeriodic BaselineRefreshPolicy = "Periodic"
+)
+
+// BaselineRefresh configures when the originals of a target are re-captured
+type BaselineRefresh struct {
+	// Policy is Never, OnSpecChangeByOtherManager or Periodic
+	Policy BaselineRefreshPolicy `json:"policy"`
+
+	// IntervalDays is the minimum age of a baseline before the Periodic policy refreshes it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalDays int32 `json:"intervalDays,omitempty"`
+}
+
+// ApplyStrategyType is how an override applies its changes to its targets
+// +kubebuilder:validation:Enum=Immediate;Rolling
+type ApplyStrategyType string
+
+const (
+	// ApplyStrategyImmediate changes every target in the same pass
+	ApplyStrategyImmediate ApplyStrategyType = "Immediate"
+	// ApplyStrategyRolling changes a bounded number of targets per minute
+	ApplyStrategyRolling ApplyStrategyType = "Rolling"
+)
+
+// ApplyStrategy paces the changes of an override across its targets
+type ApplyStrategy struct {
+	// Type is Immediate or Rolling
+	// +kubebuilder:default:=Immediate
+	Type ApplyStrategyType `json:"type"`
+
+	// MaxTargetsPerMinute is the number of targets a Rolling override changes
+	// per minute, 10 by default
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTargetsPerMinute int32 `json:"maxTargetsPerMinute,omitempty"`
+}
+
+// HPAPolicy selects the HPA limits an override scales
+type HPAPolicy struct {
+	// AdjustMin scales the minReplicas, kept at its original value when false
+	// +optional
+	AdjustMin *bool `json:"adjustMin,omitempty"`
+
+	// AdjustMax scales the maxReplicas, kept at its original value when false
+	// +optional
+	AdjustMax *bool `json:"adjustMax,omitempty"`
+}
+
+// WarmUpBoost raises the percentage of a deployment for a while after a rollout
+type WarmUpBoost struct {
+	// BoostPercentage is added to the percentage of the deployment during the
+	// warm-up, e.g. 20 scales a deployment at 100% to 120%
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	BoostPercentage int32 `json:"boostPercentage"`
+
+	// Duration is how long the boost lasts once the rollout completed
+	Duration metav1.Duration `json:"duration"`
+}
+
+// ClusterAutoscalerProtection marks the pods of a scaled-up deployment as not
+// safe to evict, which cluster-autoscaler honors by keeping their nodes
+type ClusterAutoscalerProtection struct {
+	// Duration is how long the pods are protected once the override scaled the
+	// deployment, or the minReplicas of its HPA, above the original replicas
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Placeholder configures the overprovisioning Deployment kept for each target
+type Placeholder struct {
+	// PriorityClassName is the PriorityClass of the placeholder pods. Its value
+	// must be lower than the priority of the deployments, typically negative,
+	// so their pods preempt the placeholders.
+	PriorityClassName string `json:"priorityClassName"`
+
+	// Image of the placeholder containers (default registry.k8s.io/pause:3.10)
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// PreDownscaleDelay configures the drain period before a scale-down
+type PreDownscaleDelay struct {
+	// Duration is how long the pods about to be removed are given to drain
+	Duration metav1.Duration `json:"duration"`
+
+	// Webhook is sent the pods about to be removed when the delay starts, e.g.
+	// to take them out of a connection-aware load balancer. The scale-down
+	// waits until the webhook accepted the request.
+	// +optional
+	Webhook *DrainWebhook `json:"webhook,omitempty"`
+}
+
+// DrainWebhook is an HTTP endpoint receiving a POST request with the pods
+// about to be removed by a scale-down
+type DrainWebhook struct {
+	// URL requested, reachable from the controller and in allowedURLs
+	// of the global config. Redirects are not followed
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// TimeoutSeconds is the timeout of the request (default 5)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TrafficShift configures the HTTPRoute whose backend weights follow the capacity of the targets
+type TrafficShift struct {
+	// HTTPRoute is the name of the HTTPRoute, in the namespace of the override
+	// +kubebuilder:validation:MinLength=1
+	HTTPRoute string `json:"httpRoute"`
+
+	// BackendName is the Service backendRef serving the deployment (default
+	// the name of the deployment). Set it only for overrides with one target.
+	// +optional
+	BackendName string `json:"backendName,omitempty"`
+
+	// SettleDuration is how long the lowered weight is given to take effect
+	// before a scale-down removes replicas (default 30s). A longer
+	// preDownscaleDelay of the override takes precedence.
+	// +optional
+	SettleDuration *metav1.Duration `json:"settleDuration,omitempty"`
+}
+
+// ScaleDownVerification configures the availability checks run after a scale-down
+type ScaleDownVerification struct {
+	// Window is how long availability is checked after a scale-down (default 5m)
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+
+	// MinReadyEndpoints is the number of ready endpoints every Service selecting
+	// the pods of the deployment must keep (default 1)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadyEndpoints *int32 `json:"minReadyEndpoints,omitempty"`
+
+	// HTTPProbe is an optional endpoint that must keep answering with a 2xx or
+	// 3xx status, e.g. the health check of the service behind its ingress
+	// +optional
+	HTTPProbe *HTTPProbe `json:"httpProbe,omitempty"`
+
+	// ErrorRate reverts a scale-down when the error rate of the service read
+	// from Prometheus exceeds its budget during the window, and marks the
+	// override Degraded. Prometheus is configured by errorBudget in the global config.
+	// +optional
+	ErrorRate *ErrorRateCheck `json:"errorRate,omitempty"`
+}
+
+// ErrorRateCheck is the error rate a service must stay under after a
+// scale-down, from a PromQL query or a Sloth PrometheusServiceLevel
+type ErrorRateCheck struct {
+	// Query is a PromQL expression returning the error ratio of the service,
+	// between 0 and 1
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// SLORef reads the error ratio query and the objective of an SLO of a
+	// Sloth PrometheusServiceLevel in the namespace of the override
+	// +optional
+	SLORef *SLOReference `json:"sloRef,omitempty"`
+
+	// MaxErrorRate is the highest tolerated error ratio, e.g. "0.01". It
+	// defaults to the error budget of the SLO referenced by SLORef, e.g. 0.001
+	// for an objective of 99.9, and is required with Query.
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	// +optional
+	MaxErrorRate string `json:"maxErrorRate,omitempty"`
+}
+
+// SLOReference references an SLO of a Sloth PrometheusServiceLevel
+type SLOReference struct {
+	// Name of the PrometheusServiceLevel
+	Name string `json:"name"`
+
+	// SLO is the name of the SLO in the PrometheusServiceLevel, the first one when omitted
+	// +optional
+	SLO string `json:"slo,omitempty"`
+}
+
+// HTTPProbe is an HTTP GET request checking the availability of a service
+type HTTPProbe struct {
+	// URL requested, reachable from the controller and in allowedURLs
+	// of the global config. Redirects are not followed
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// TimeoutSeconds is the timeout of the request (default 5)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// BlackoutWindow is either a recurring window (Schedule and Duration) or a
+// one-off time range (Start and End)
+type BlackoutWindow struct {
+	// Name describes the window in status and logs
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Schedule is a cron expression (e.g. "0 18 * * 5") marking the start of a recurring window
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is the length of a recurring window
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// TimeZone is the IANA time zone of Schedule, UTC if empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Start is the beginning of a one-off window
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the end of a one-off window
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+}
+
+// PercentageSource selects the label or annotation of a target holding its percentage.
+// When both are set the label wins.
+type PercentageSource struct {
+	// LabelKey is the key of the label holding the percentage, e.g. scaling/weight
+	// +optional
+	LabelKey string `json:"labelKey,omitempty"`
+
+	// AnnotationKey is the key of the annotation holding the percentage
+	// +optional
+	AnnotationKey string `json:"annotationKey,omitempty"`
+}
+
+// EnvironmentTargeting selects targets by an environment label
+type EnvironmentTargeting struct {
+	// Label is the key of the label naming the environment of a workload, e.g. env
+	// +kubebuilder:validation:MinLength=1
+	Label string `json:"label"`
+
+	// Values are the targeted environments and their percentages
+	// +kubebuilder:validation:MinItems=1
+	Values []EnvironmentPercentage `json:"values"`
+}
+
+// EnvironmentPercentage is the percentage of the workloads of one environment
+type EnvironmentPercentage struct {
+	// Value of the environment label, e.g. prod
+	Value string `json:"value"`
+
+	// ReplicasPercentage replaces the ReplicasPercentage of the override for
+	// the workloads of this environment
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000
+	ReplicasPercentage int32 `json:"replicasPercentage"`
+}
+
+// TargetKind is a kind of object an override can scale
+// +kubebuilder:validation:Enum=Deployment;HorizontalPodAutoscaler;StatefulSet;ReplicaSet;ReplicationController;Job;ScaledObject
+type TargetKind string
+
+const (
+	// TargetKindDeployment scales the replicas of deployments without autoscaler
+	TargetKindDeployment TargetKind = "Deployment"
+	// TargetKindHPA scales the min/max replicas of the HPA of a deployment
+	TargetKindHPA TargetKind = "HorizontalPodAutoscaler"
+	// TargetKindStatefulSet scales the replicas of StatefulSets
+	TargetKindStatefulSet TargetKind = "StatefulSet"
+	// TargetKindReplicaSet scales the replicas of ReplicaSets not owned by a deployment
+	TargetKindReplicaSet TargetKind = "ReplicaSet"
+	// TargetKindReplicationController scales the replicas of ReplicationControllers
+	TargetKindReplicationController TargetKind = "ReplicationController"
+	// TargetKindJob scales the parallelism of Jobs
+	TargetKindJob TargetKind = "Job"
+	// TargetKindScaledObject scales the min/max replica counts of KEDA ScaledObjects
+	TargetKindScaledObject TargetKind = "ScaledObject"
+)
+
+// ScalingBasis is what the percentage of an override applies to
+// +kubebuilder:validation:Enum=Original;HPADesired
+type ScalingBasis string
+
+const (
+	// ScalingBasisOriginal scales the original replicas or HPA limits
+	ScalingBasisOriginal ScalingBasis = "Original"
+	// ScalingBasisHPADesired scales the replicas the HPA metrics currently ask for
+	ScalingBasisHPADesired ScalingBasis = "HPADesired"
+)
+
+// NotificationEvent is a kind of change a notification target can subscribe to
+// +kubebuilder:validation:Enum=Scaled;Failed;RolledBack
+type NotificationEvent string
+
+const (
+	// NotificationScaled is sent when a target was scaled
+	NotificationScaled NotificationEvent = "Scaled"
+	// NotificationFailed is sent when scaling a target failed
+	NotificationFailed NotificationEvent = "Failed"
+	// NotificationRolledBack is sent when a target was restored by a rollback
+	// or by a failed scale-down verification
+	NotificationRolledBack NotificationEvent = "RolledBack"
+)
+
+// NotificationTarget is a Slack channel or a webhook notified about the changes
+// of an override
+type NotificationTarget struct {
+	// Type is the kind of destination
+	// +kubebuilder:validation:Enum=slack;webhook
+	Type string `json:"type"`
+
+	// Channel is the Slack channel to post to, required for slack targets
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// SecretRef selects the key of a Secret in the override namespace holding
+	// the Slack bot token, or the webhook URL, which must be in allowedURLs of
+	// the global config
+	SecretRef corev1.SecretKeySelector `json:"secretRef"`
+
+	// Events limits the notified events, all events are sent when empty
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
 }
 
 // TargetSelector defines how to select deployments for scaling
@@ -91,6 +651,22 @@ type HPAReference struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// RatioReference references the deployment the replicas of the targets of an
+// override are capped by
+type RatioReference struct {
+	// Name of the deployment
+	Name string `json:"name"`
+
+	// Namespace of the deployment, the namespace of the override when empty
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Factor is the most replicas a target may have per replica of the
+	// deployment, e.g. "4" or "0.5"
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Factor string `json:"factor"`
+}
+
 // ReplicasOverrideStatus defines the observed state of ReplicasOverride
 type ReplicasOverrideStatus struct {
 	// AffectedDeployments contains the list of deployments affected by this override
@@ -104,6 +680,142 @@ type ReplicasOverrideStatus struct {
 	// Conditions represent the latest available observations of the override's state
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// EstimatedCost is the estimated hourly cost delta of the current scaling state
+	// compared to the original replicas of the affected deployments
+	// +optional
+	EstimatedCost *CostEstimate `json:"estimatedCost,omitempty"`
+
+	// Conflicts lists the workloads this override targets along with other
+	// overrides, and which override is applied to each of them
+	// +optional
+	Conflicts []ScalingConflict `json:"conflicts,omitempty"`
+
+	// IgnoreExceptions lists the deployments this override scales although a
+	// GlobalReplicasIgnore excludes them, as allowed by its ignorePolicy
+	// +optional
+	IgnoreExceptions []IgnoreException `json:"ignoreExceptions,omitempty"`
+
+	// MonitoredTargets lists the deployments of an override in Monitor mode
+	// and the replicas, or HPA limits, it would set on them
+	// +optional
+	MonitoredTargets []MonitoredTarget `json:"monitoredTargets,omitempty"`
+
+	// Group reports the state of the group of the override, the same on every member
+	// +optional
+	Group *OverrideGroupStatus `json:"group,omitempty"`
+
+	// UnmatchedSince is when the override was first seen targeting no workload,
+	// unset while it targets some. The TargetsMatched condition turns False once
+	// it matched nothing for the configured time, which usually means a typo.
+	// +optional
+	UnmatchedSince *metav1.Time `json:"unmatchedSince,omitempty"`
+}
+
+// OverrideGroupPhase is the outcome of the last pass applying a group
+// +kubebuilder:validation:Enum=Applied;Held;Reverted
+type OverrideGroupPhase string
+
+const (
+	// OverrideGroupApplied means every target of the group was updated
+	OverrideGroupApplied OverrideGroupPhase = "Applied"
+	// OverrideGroupHeld means a paused, frozen or rolled back member holds the group
+	OverrideGroupHeld OverrideGroupPhase = "Held"
+	// OverrideGroupReverted means a target could not be updated and the
+	// targets changed in the same pass were put back
+	OverrideGroupReverted OverrideGroupPhase = "Reverted"
+)
+
+// OverrideGroupStatus is the state of a group of overrides
+type OverrideGroupStatus struct {
+	// Name of the group
+	Name string `json:"name"`
+
+	// Members are the namespace/name of the overrides of the group
+	Members []string `json:"members"`
+
+	// Phase is the outcome of the last pass applying the group
+	Phase OverrideGroupPhase `json:"phase"`
+
+	// Message explains a held or reverted group
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is when the phase last changed
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ScalingConflict reports a workload targeted by more than one override
+type ScalingConflict struct {
+	// Kind of the workload
+	Kind string `json:"kind"`
+
+	// Name of the workload
+	Name string `json:"name"`
+
+	// Namespace of the workload
+	Namespace string `json:"namespace"`
+
+	// Overrides are the names of every override targeting the workload, in
+	// the order they are evaluated
+	Overrides []string `json:"overrides"`
+
+	// Winner is the name of the override applied to the workload
+	Winner string `json:"winner"`
+}
+
+// IgnoreException is a deployment scaled by an override despite ignore rules
+type IgnoreException struct {
+	// Name of the deployment
+	Name string `json:"name"`
+
+	// Namespace of the deployment
+	Namespace string `json:"namespace"`
+
+	// IgnoredBy are the names of the GlobalReplicasIgnores excluding the deployment
+	IgnoredBy []string `json:"ignoredBy"`
+}
+
+// MonitoredTarget is a deployment an override in Monitor mode would scale
+type MonitoredTarget struct {
+	// Name of the deployment
+	Name string `json:"name"`
+
+	// Namespace of the deployment
+	Namespace string `json:"namespace"`
+
+	// HPA is the name of the HPA scaling the deployment, whose limits would be
+	// changed instead
+	// +optional
+	HPA string `json:"hpa,omitempty"`
+
+	// CurrentReplicas and DesiredReplicas are the replicas, or the HPA
+	// minReplicas, now and once enforced
+	CurrentReplicas int32 `json:"currentReplicas"`
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// CurrentMaxReplicas and DesiredMaxReplicas are the HPA maxReplicas now
+	// and once enforced
+	// +optional
+	CurrentMaxReplicas int32 `json:"currentMaxReplicas,omitempty"`
+	// +optional
+	DesiredMaxReplicas int32 `json:"desiredMaxReplicas,omitempty"`
+
+	// Explanation tells which rule produced the replicas, or why they would be left alone
+	// +optional
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// CostEstimate contains the estimated cost impact of an override
+type CostEstimate struct {
+	// HourlyDelta is the estimated hourly cost difference against the original replicas,
+	// formatted as a decimal string (e.g. "-12.50" when scaling down saves money)
+	HourlyDelta string `json:"hourlyDelta"`
+
+	// Currency of the estimate, as configured in the global config
+	// +optional
+	Currency string `json:"currency,omitempty"`
 }
 
 // AffectedDeployment contains information about a deployment affected by the override
@@ -114,20 +826,143 @@ type AffectedDeployment struct {
 	// Namespace of the deployment
 	Namespace string `json:"namespace"`
 
-	// OriginalReplicas is the number of replicas before the override
+	// UID of the deployment the entry was recorded for. A deployment deleted
+	// and recreated with the same name gets a new entry, its originals are
+	// recorded again.
+	// +optional
+	UID types.UID `json:"uid,omitempty"`
+
+	// OriginalReplicas is the number of replicas before the override. It backs
+	// up the original-replicas annotation, which is restored from it if deleted.
 	OriginalReplicas int32 `json:"originalReplicas"`
 
+	// HPAName is the name of the HPA scaling the deployment, if any
+	// +optional
+	HPAName string `json:"hpaName,omitempty"`
+
+	// OriginalHPAMinReplicas backs up the original minReplicas of the HPA
+	// +optional
+	OriginalHPAMinReplicas *int32 `json:"originalHPAMinReplicas,omitempty"`
+
+	// OriginalHPAMaxReplicas backs up the original maxReplicas of the HPA
+	// +optional
+	OriginalHPAMaxReplicas *int32 `json:"originalHPAMaxReplicas,omitempty"`
+
+	// WarmUpRevision is the revision of the deployment whose completed rollout
+	// started the last warm-up boost
+	// +optional
+	WarmUpRevision string `json:"warmUpRevision,omitempty"`
+
+	// WarmUpUntil is the end of the warm-up boost of that revision
+	// +optional
+	WarmUpUntil *metav1.Time `json:"warmUpUntil,omitempty"`
+
 	// CurrentReplicas is the current number of replicas after the override
 	CurrentReplicas int32 `json:"currentReplicas"`
 
 	// CurrentPercentage is the current percentage applied
 	CurrentPercentage int32 `json:"currentPercentage"`
+
+	// ReadyReplicas is the number of Ready replicas last observed
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// UnschedulablePods is the number of pods the scheduler could not place
+	// while a scale-up is waiting for its replicas
+	// +optional
+	UnschedulablePods int32 `json:"unschedulablePods,omitempty"`
+
+	// ScaleUpStartTime is when the replicas were last raised above the Ready
+	// replicas, cleared once they are all Ready
+	// +optional
+	ScaleUpStartTime *metav1.Time `json:"scaleUpStartTime,omitempty"`
+
+	// VerificationStartTime is when the last scale-down started being
+	// verified, cleared once its verification window passed
+	// +optional
+	VerificationStartTime *metav1.Time `json:"verificationStartTime,omitempty"`
+
+	// VerifiedFromReplicas is the number of replicas before the scale-down
+	// being verified, restored if availability drops
+	// +optional
+	VerifiedFromReplicas int32 `json:"verifiedFromReplicas,omitempty"`
+
+	// RevertedGeneration is the generation of the override whose scale-down
+	// was reverted, the deployment is held until the override changes
+	// +optional
+	RevertedGeneration int64 `json:"revertedGeneration,omitempty"`
+
+	// VerificationFailure is why the last scale-down was reverted
+	// +optional
+	VerificationFailure string `json:"verificationFailure,omitempty"`
+
+	// BrokenHPA is the HPA of the deployment that cannot scale and why, while
+	// its replicas are scaled directly instead of its limits
+	// +optional
+	BrokenHPA string `json:"brokenHPA,omitempty"`
+
+	// PlaceholderReplicas is the number of placeholder pods reserving capacity
+	// for the deployment, in placeholder mode
+	// +optional
+	PlaceholderReplicas int32 `json:"placeholderReplicas,omitempty"`
+
+	// DecisionStages are the stages of the decision chain that changed the
+	// percentage or the replicas of the deployment, in order
+	// +optional
+	DecisionStages []DecisionStage `json:"decisionStages,omitempty"`
+
+	// History holds the last replica changes the controller made to the
+	// deployment, oldest first, as many as the replicaHistoryLimit of the
+	// global config
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	History []ReplicaChange `json:"history,omitempty"`
+}
+
+// ReplicaChange is a change of the replicas of a deployment
+type ReplicaChange struct {
+	// Time of the change
+	Time metav1.Time `json:"time"`
+
+	// From and To are the replicas before and after the change
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+
+	// Override is the override that made the change, global for the global config
+	// +optional
+	Override string `json:"override,omitempty"`
+
+	// Trigger is what produced the change, e.g. override, rollback or verification
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// Percentage is the percentage applied, unset when recorded replicas were restored
+	// +optional
+	Percentage *int32 `json:"percentage,omitempty"`
+}
+
+// DecisionStage is the percentage and replicas after a stage of the decision chain
+type DecisionStage struct {
+	// Stage is the name of the stage, e.g. schedule, trigger or policy-clamp
+	Stage string `json:"stage"`
+
+	// Percentage is the percentage after the stage
+	Percentage int32 `json:"percentage"`
+
+	// Replicas is the replica count after the stage, once computed
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.overrideType"
 // +kubebuilder:printcolumn:name="Percentage",type="integer",JSONPath=".spec.replicasPercentage"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode",priority=1
+// +kubebuilder:printcolumn:name="Cost Delta",type="string",JSONPath=".status.estimatedCost.hourlyDelta",priority=1
+// +kubebuilder:printcolumn:name="Stalled",type="string",JSONPath=".status.conditions[?(@.type==\"ScaleUpStalled\")].status",priority=1
+// +kubebuilder:printcolumn:name="Conflict",type="string",JSONPath=".status.conditions[?(@.type==\"OverrideConflict\")].status",priority=1
+// +kubebuilder:printcolumn:name="Group",type="string",JSONPath=".status.group.phase",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ReplicasOverride is the Schema for the replicasoverrides API
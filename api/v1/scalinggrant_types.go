@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScalingGrantSpec lets ReplicasOverrides of other namespaces target the
+// deployments of the namespace of the grant by deploymentRef, so a central
+// team manages overrides while tenants keep control over what is scaled.
+// Without a grant, deployments are only targeted from their own namespace.
+type ScalingGrantSpec struct {
+	// From lists the namespaces, and optionally the overrides of each, allowed
+	// to target the deployments of the namespace of the grant
+	// +kubebuilder:validation:MinItems=1
+	From []ScalingGrantFrom `json:"from"`
+
+	// Deployments restricts the grant to the deployments with these names,
+	// every deployment of the namespace is granted when empty
+	// +optional
+	Deployments []string `json:"deployments,omitempty"`
+}
+
+// ScalingGrantFrom is a namespace whose overrides are granted
+type ScalingGrantFrom struct {
+	// Namespace of the granted overrides
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Overrides restricts the grant to the overrides with these names, every
+	// override of the namespace is granted when empty
+	// +optional
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=sg
+// +kubebuilder:printcolumn:name="From",type="string",JSONPath=".spec.from[*].namespace"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ScalingGrant is the Schema for the scalinggrants API
+type ScalingGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScalingGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScalingGrantList contains a list of ScalingGrant
+type ScalingGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ScalingGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ScalingGrant{}, &ScalingGrantList{})
+}
@@ -28,6 +28,42 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AffectedDeployment) DeepCopyInto(out *AffectedDeployment) {
 	*out = *in
+	if in.OriginalHPAMinReplicas != nil {
+		in, out := &in.OriginalHPAMinReplicas, &out.OriginalHPAMinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OriginalHPAMaxReplicas != nil {
+		in, out := &in.OriginalHPAMaxReplicas, &out.OriginalHPAMaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WarmUpUntil != nil {
+		in, out := &in.WarmUpUntil, &out.WarmUpUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.ScaleUpStartTime != nil {
+		in, out := &in.ScaleUpStartTime, &out.ScaleUpStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.VerificationStartTime != nil {
+		in, out := &in.VerificationStartTime, &out.VerificationStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DecisionStages != nil {
+		in, out := &in.DecisionStages, &out.DecisionStages
+		*out = make([]DecisionStage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ReplicaChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AffectedDeployment.
@@ -40,6 +76,115 @@ func (in *AffectedDeployment) DeepCopy() *AffectedDeployment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyStrategy) DeepCopyInto(out *ApplyStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyStrategy.
+func (in *ApplyStrategy) DeepCopy() *ApplyStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaselineRefresh) DeepCopyInto(out *BaselineRefresh) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineRefresh.
+func (in *BaselineRefresh) DeepCopy() *BaselineRefresh {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineRefresh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutWindow) DeepCopyInto(out *BlackoutWindow) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutWindow.
+func (in *BlackoutWindow) DeepCopy() *BlackoutWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAutoscalerProtection) DeepCopyInto(out *ClusterAutoscalerProtection) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAutoscalerProtection.
+func (in *ClusterAutoscalerProtection) DeepCopy() *ClusterAutoscalerProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscalerProtection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionStage) DeepCopyInto(out *DecisionStage) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionStage.
+func (in *DecisionStage) DeepCopy() *DecisionStage {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionStage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentReference) DeepCopyInto(out *DeploymentReference) {
 	*out = *in
@@ -55,6 +200,76 @@ func (in *DeploymentReference) DeepCopy() *DeploymentReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainWebhook) DeepCopyInto(out *DrainWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainWebhook.
+func (in *DrainWebhook) DeepCopy() *DrainWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentPercentage) DeepCopyInto(out *EnvironmentPercentage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentPercentage.
+func (in *EnvironmentPercentage) DeepCopy() *EnvironmentPercentage {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentPercentage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentTargeting) DeepCopyInto(out *EnvironmentTargeting) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]EnvironmentPercentage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentTargeting.
+func (in *EnvironmentTargeting) DeepCopy() *EnvironmentTargeting {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentTargeting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorRateCheck) DeepCopyInto(out *ErrorRateCheck) {
+	*out = *in
+	if in.SLORef != nil {
+		in, out := &in.SLORef, &out.SLORef
+		*out = new(SLOReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorRateCheck.
+func (in *ErrorRateCheck) DeepCopy() *ErrorRateCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorRateCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalReplicasIgnore) DeepCopyInto(out *GlobalReplicasIgnore) {
 	*out = *in
@@ -177,6 +392,31 @@ func (in *GlobalReplicasIgnoreStatus) DeepCopy() *GlobalReplicasIgnoreStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HPAPolicy) DeepCopyInto(out *HPAPolicy) {
+	*out = *in
+	if in.AdjustMin != nil {
+		in, out := &in.AdjustMin, &out.AdjustMin
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdjustMax != nil {
+		in, out := &in.AdjustMax, &out.AdjustMax
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HPAPolicy.
+func (in *HPAPolicy) DeepCopy() *HPAPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HPAPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HPAReference) DeepCopyInto(out *HPAReference) {
 	*out = *in
@@ -192,6 +432,41 @@ func (in *HPAReference) DeepCopy() *HPAReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProbe) DeepCopyInto(out *HTTPProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProbe.
+func (in *HTTPProbe) DeepCopy() *HTTPProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnoreException) DeepCopyInto(out *IgnoreException) {
+	*out = *in
+	if in.IgnoredBy != nil {
+		in, out := &in.IgnoredBy, &out.IgnoredBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnoreException.
+func (in *IgnoreException) DeepCopy() *IgnoreException {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnoreException)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IgnoredDeployment) DeepCopyInto(out *IgnoredDeployment) {
 	*out = *in
@@ -223,7 +498,22 @@ func (in *IgnoredResource) DeepCopy() *IgnoredResource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicasOverride) DeepCopyInto(out *ReplicasOverride) {
+func (in *MonitoredTarget) DeepCopyInto(out *MonitoredTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoredTarget.
+func (in *MonitoredTarget) DeepCopy() *MonitoredTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoredTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceReplicasOverride) DeepCopyInto(out *NamespaceReplicasOverride) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -231,18 +521,18 @@ func (in *ReplicasOverride) DeepCopyInto(out *ReplicasOverride) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverride.
-func (in *ReplicasOverride) DeepCopy() *ReplicasOverride {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceReplicasOverride.
+func (in *NamespaceReplicasOverride) DeepCopy() *NamespaceReplicasOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicasOverride)
+	out := new(NamespaceReplicasOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ReplicasOverride) DeepCopyObject() runtime.Object {
+func (in *NamespaceReplicasOverride) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -250,31 +540,31 @@ func (in *ReplicasOverride) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicasOverrideList) DeepCopyInto(out *ReplicasOverrideList) {
+func (in *NamespaceReplicasOverrideList) DeepCopyInto(out *NamespaceReplicasOverrideList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ReplicasOverride, len(*in))
+		*out = make([]NamespaceReplicasOverride, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideList.
-func (in *ReplicasOverrideList) DeepCopy() *ReplicasOverrideList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceReplicasOverrideList.
+func (in *NamespaceReplicasOverrideList) DeepCopy() *NamespaceReplicasOverrideList {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicasOverrideList)
+	out := new(NamespaceReplicasOverrideList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ReplicasOverrideList) DeepCopyObject() runtime.Object {
+func (in *NamespaceReplicasOverrideList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -282,23 +572,8 @@ func (in *ReplicasOverrideList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicasOverrideSpec) DeepCopyInto(out *ReplicasOverrideSpec) {
+func (in *NamespaceReplicasOverrideSpec) DeepCopyInto(out *NamespaceReplicasOverrideSpec) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(TargetSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DeploymentRef != nil {
-		in, out := &in.DeploymentRef, &out.DeploymentRef
-		*out = new(DeploymentReference)
-		**out = **in
-	}
-	if in.HPARef != nil {
-		in, out := &in.HPARef, &out.HPARef
-		*out = new(HPAReference)
-		**out = **in
-	}
 	if in.MinReplicas != nil {
 		in, out := &in.MinReplicas, &out.MinReplicas
 		*out = new(int32)
@@ -311,28 +586,19 @@ func (in *ReplicasOverrideSpec) DeepCopyInto(out *ReplicasOverrideSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideSpec.
-func (in *ReplicasOverrideSpec) DeepCopy() *ReplicasOverrideSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceReplicasOverrideSpec.
+func (in *NamespaceReplicasOverrideSpec) DeepCopy() *NamespaceReplicasOverrideSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicasOverrideSpec)
+	out := new(NamespaceReplicasOverrideSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ReplicasOverrideStatus) DeepCopyInto(out *ReplicasOverrideStatus) {
+func (in *NamespaceReplicasOverrideStatus) DeepCopyInto(out *NamespaceReplicasOverrideStatus) {
 	*out = *in
-	if in.AffectedDeployments != nil {
-		in, out := &in.AffectedDeployments, &out.AffectedDeployments
-		*out = make([]AffectedDeployment, len(*in))
-		copy(*out, *in)
-	}
-	if in.LastUpdateTime != nil {
-		in, out := &in.LastUpdateTime, &out.LastUpdateTime
-		*out = (*in).DeepCopy()
-	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -342,34 +608,777 @@ func (in *ReplicasOverrideStatus) DeepCopyInto(out *ReplicasOverrideStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideStatus.
-func (in *ReplicasOverrideStatus) DeepCopy() *ReplicasOverrideStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceReplicasOverrideStatus.
+func (in *NamespaceReplicasOverrideStatus) DeepCopy() *NamespaceReplicasOverrideStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ReplicasOverrideStatus)
+	out := new(NamespaceReplicasOverrideStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TargetSelector) DeepCopyInto(out *TargetSelector) {
+func (in *NamespaceScalingDefault) DeepCopyInto(out *NamespaceScalingDefault) {
 	*out = *in
-	if in.MatchLabels != nil {
-		in, out := &in.MatchLabels, &out.MatchLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSelector.
-func (in *TargetSelector) DeepCopy() *TargetSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceScalingDefault.
+func (in *NamespaceScalingDefault) DeepCopy() *NamespaceScalingDefault {
 	if in == nil {
 		return nil
 	}
-	out := new(TargetSelector)
+	out := new(NamespaceScalingDefault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceScalingDefault) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceScalingDefaultList) DeepCopyInto(out *NamespaceScalingDefaultList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespaceScalingDefault, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceScalingDefaultList.
+func (in *NamespaceScalingDefaultList) DeepCopy() *NamespaceScalingDefaultList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceScalingDefaultList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespaceScalingDefaultList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceScalingDefaultSpec) DeepCopyInto(out *NamespaceScalingDefaultSpec) {
+	*out = *in
+	if in.ReplicasPercentage != nil {
+		in, out := &in.ReplicasPercentage, &out.ReplicasPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceScalingDefaultSpec.
+func (in *NamespaceScalingDefaultSpec) DeepCopy() *NamespaceScalingDefaultSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceScalingDefaultSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceScalingDefaultStatus) DeepCopyInto(out *NamespaceScalingDefaultStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceScalingDefaultStatus.
+func (in *NamespaceScalingDefaultStatus) DeepCopy() *NamespaceScalingDefaultStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceScalingDefaultStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTarget) DeepCopyInto(out *NotificationTarget) {
+	*out = *in
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTarget.
+func (in *NotificationTarget) DeepCopy() *NotificationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverrideGroupStatus) DeepCopyInto(out *OverrideGroupStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverrideGroupStatus.
+func (in *OverrideGroupStatus) DeepCopy() *OverrideGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OverrideGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PercentageSource) DeepCopyInto(out *PercentageSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PercentageSource.
+func (in *PercentageSource) DeepCopy() *PercentageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PercentageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Placeholder) DeepCopyInto(out *Placeholder) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Placeholder.
+func (in *Placeholder) DeepCopy() *Placeholder {
+	if in == nil {
+		return nil
+	}
+	out := new(Placeholder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreDownscaleDelay) DeepCopyInto(out *PreDownscaleDelay) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(DrainWebhook)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreDownscaleDelay.
+func (in *PreDownscaleDelay) DeepCopy() *PreDownscaleDelay {
+	if in == nil {
+		return nil
+	}
+	out := new(PreDownscaleDelay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RatioReference) DeepCopyInto(out *RatioReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RatioReference.
+func (in *RatioReference) DeepCopy() *RatioReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RatioReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaChange) DeepCopyInto(out *ReplicaChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaChange.
+func (in *ReplicaChange) DeepCopy() *ReplicaChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasOverride) DeepCopyInto(out *ReplicasOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverride.
+func (in *ReplicasOverride) DeepCopy() *ReplicasOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicasOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasOverrideList) DeepCopyInto(out *ReplicasOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReplicasOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideList.
+func (in *ReplicasOverrideList) DeepCopy() *ReplicasOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReplicasOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasOverrideSpec) DeepCopyInto(out *ReplicasOverrideSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(TargetSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentRef != nil {
+		in, out := &in.DeploymentRef, &out.DeploymentRef
+		*out = new(DeploymentReference)
+		**out = **in
+	}
+	if in.HPARef != nil {
+		in, out := &in.HPARef, &out.HPARef
+		*out = new(HPAReference)
+		**out = **in
+	}
+	if in.TargetKinds != nil {
+		in, out := &in.TargetKinds, &out.TargetKinds
+		*out = make([]TargetKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environments != nil {
+		in, out := &in.Environments, &out.Environments
+		*out = new(EnvironmentTargeting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PercentageFrom != nil {
+		in, out := &in.PercentageFrom, &out.PercentageFrom
+		*out = new(PercentageSource)
+		**out = **in
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RatioOf != nil {
+		in, out := &in.RatioOf, &out.RatioOf
+		*out = new(RatioReference)
+		**out = **in
+	}
+	if in.PreserveHPAMinForExternalMetrics != nil {
+		in, out := &in.PreserveHPAMinForExternalMetrics, &out.PreserveHPAMinForExternalMetrics
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HPAPolicy != nil {
+		in, out := &in.HPAPolicy, &out.HPAPolicy
+		*out = new(HPAPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BaselineRefresh != nil {
+		in, out := &in.BaselineRefresh, &out.BaselineRefresh
+		*out = new(BaselineRefresh)
+		**out = **in
+	}
+	if in.ApplyStrategy != nil {
+		in, out := &in.ApplyStrategy, &out.ApplyStrategy
+		*out = new(ApplyStrategy)
+		**out = **in
+	}
+	if in.MinWorkloadAge != nil {
+		in, out := &in.MinWorkloadAge, &out.MinWorkloadAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(WarmUpBoost)
+		**out = **in
+	}
+	if in.ClusterAutoscalerProtection != nil {
+		in, out := &in.ClusterAutoscalerProtection, &out.ClusterAutoscalerProtection
+		*out = new(ClusterAutoscalerProtection)
+		**out = **in
+	}
+	if in.Placeholder != nil {
+		in, out := &in.Placeholder, &out.Placeholder
+		*out = new(Placeholder)
+		**out = **in
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(ScaleDownVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreDownscaleDelay != nil {
+		in, out := &in.PreDownscaleDelay, &out.PreDownscaleDelay
+		*out = new(PreDownscaleDelay)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficShift != nil {
+		in, out := &in.TrafficShift, &out.TrafficShift
+		*out = new(TrafficShift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlackoutWindows != nil {
+		in, out := &in.BlackoutWindows, &out.BlackoutWindows
+		*out = make([]BlackoutWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideSpec.
+func (in *ReplicasOverrideSpec) DeepCopy() *ReplicasOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicasOverrideStatus) DeepCopyInto(out *ReplicasOverrideStatus) {
+	*out = *in
+	if in.AffectedDeployments != nil {
+		in, out := &in.AffectedDeployments, &out.AffectedDeployments
+		*out = make([]AffectedDeployment, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EstimatedCost != nil {
+		in, out := &in.EstimatedCost, &out.EstimatedCost
+		*out = new(CostEstimate)
+		**out = **in
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]ScalingConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IgnoreExceptions != nil {
+		in, out := &in.IgnoreExceptions, &out.IgnoreExceptions
+		*out = make([]IgnoreException, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MonitoredTargets != nil {
+		in, out := &in.MonitoredTargets, &out.MonitoredTargets
+		*out = make([]MonitoredTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Group != nil {
+		in, out := &in.Group, &out.Group
+		*out = new(OverrideGroupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UnmatchedSince != nil {
+		in, out := &in.UnmatchedSince, &out.UnmatchedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicasOverrideStatus.
+func (in *ReplicasOverrideStatus) DeepCopy() *ReplicasOverrideStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicasOverrideStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOReference) DeepCopyInto(out *SLOReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOReference.
+func (in *SLOReference) DeepCopy() *SLOReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDownVerification) DeepCopyInto(out *ScaleDownVerification) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinReadyEndpoints != nil {
+		in, out := &in.MinReadyEndpoints, &out.MinReadyEndpoints
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HTTPProbe != nil {
+		in, out := &in.HTTPProbe, &out.HTTPProbe
+		*out = new(HTTPProbe)
+		**out = **in
+	}
+	if in.ErrorRate != nil {
+		in, out := &in.ErrorRate, &out.ErrorRate
+		*out = new(ErrorRateCheck)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDownVerification.
+func (in *ScaleDownVerification) DeepCopy() *ScaleDownVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDownVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingConflict) DeepCopyInto(out *ScalingConflict) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingConflict.
+func (in *ScalingConflict) DeepCopy() *ScalingConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingGrant) DeepCopyInto(out *ScalingGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingGrant.
+func (in *ScalingGrant) DeepCopy() *ScalingGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScalingGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingGrantFrom) DeepCopyInto(out *ScalingGrantFrom) {
+	*out = *in
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingGrantFrom.
+func (in *ScalingGrantFrom) DeepCopy() *ScalingGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingGrantList) DeepCopyInto(out *ScalingGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScalingGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingGrantList.
+func (in *ScalingGrantList) DeepCopy() *ScalingGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScalingGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingGrantSpec) DeepCopyInto(out *ScalingGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ScalingGrantFrom, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingGrantSpec.
+func (in *ScalingGrantSpec) DeepCopy() *ScalingGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingSchedule) DeepCopyInto(out *ScalingSchedule) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingSchedule.
+func (in *ScalingSchedule) DeepCopy() *ScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetSelector) DeepCopyInto(out *TargetSelector) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSelector.
+func (in *TargetSelector) DeepCopy() *TargetSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficShift) DeepCopyInto(out *TrafficShift) {
+	*out = *in
+	if in.SettleDuration != nil {
+		in, out := &in.SettleDuration, &out.SettleDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficShift.
+func (in *TrafficShift) DeepCopy() *TrafficShift {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficShift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WarmUpBoost) DeepCopyInto(out *WarmUpBoost) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmUpBoost.
+func (in *WarmUpBoost) DeepCopy() *WarmUpBoost {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmUpBoost)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/adopt"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// runAdopt scans a brownfield cluster, optionally records the current replicas
+// as originals, and writes the suggested overrides and ignore rules
+func runAdopt(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("adopt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster clusterFlags
+	cluster.bind(fs)
+	var opts adopt.Options
+	var exclude, output, configMap, controllerNamespace string
+	var record, dryRun bool
+	fs.StringVar(&opts.GroupLabel, "group-label", adopt.DefaultGroupLabel, "Label grouping the deployments of a namespace into one suggested override")
+	fs.StringVar(&exclude, "exclude-namespaces", strings.Join(adopt.DefaultExcludeNamespaces, ","), "Comma-separated namespaces to leave alone and ignore")
+	fs.BoolVar(&record, "record", false, "Record the current replicas as originals on the workloads and HPAs without them")
+	fs.BoolVar(&dryRun, "dry-run", false, "Validate the recorded originals and the ConfigMap on the API server without persisting them")
+	fs.StringVar(&output, "o", "-", "File to write the suggested manifests to, - for stdout")
+	fs.StringVar(&configMap, "configmap", "", "Write the suggested manifests to this ConfigMap instead of -o")
+	fs.StringVar(&controllerNamespace, "controller-namespace", config.DefaultConfigMapNamespace, "Namespace of the --configmap ConfigMap")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	for _, namespace := range strings.Split(exclude, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			opts.ExcludeNamespaces = append(opts.ExcludeNamespaces, namespace)
+		}
+	}
+
+	c, err := cluster.client()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	plan, err := adopt.Scan(ctx, c, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	code := exitOK
+	if record {
+		result, err := adopt.Record(ctx, c, plan, dryRun)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		for _, err := range result.Errors {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			code = exitError
+		}
+		fmt.Fprintf(stderr, "recorded the originals of %d objects, %d already had them, %d failed\n",
+			result.Updated, result.Skipped, len(result.Errors))
+	} else {
+		fmt.Fprintf(stderr, "%d objects have no recorded originals, run with --record to record their current values\n", len(plan.Originals))
+	}
+
+	if configMap != "" {
+		if err := writeConfigMap(ctx, c, plan, configMap, controllerNamespace, dryRun); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		fmt.Fprintf(stderr, "wrote %d suggested overrides and %d ignore rules to ConfigMap %s/%s\n",
+			len(plan.Overrides), len(plan.IgnoreRules), controllerNamespace, configMap)
+		return code
+	}
+
+	manifests, err := plan.Manifests()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	if output == "-" {
+		_, err = stdout.Write(manifests)
+	} else {
+		err = os.WriteFile(output, manifests, 0o600)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	return code
+}
+
+// writeConfigMap creates or replaces the ConfigMap holding the suggested manifests
+func writeConfigMap(ctx context.Context, c client.Client, plan *adopt.Plan, name, namespace string, dryRun bool) error {
+	desired, err := plan.ConfigMap(name, namespace)
+	if err != nil {
+		return err
+	}
+	var createOpts []client.CreateOption
+	var updateOpts []client.UpdateOption
+	if dryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
+
+	existing := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return c.Create(ctx, desired, createOpts...)
+	}
+	existing.Data = desired.Data
+	return c.Update(ctx, existing, updateOpts...)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+	pkgmanifests "github.com/KubeDynamicScaler/kubedynamicscaler/pkg/manifests"
+)
+
+// ANSI colors of the diff
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorFaint = "\x1b[2m"
+	colorReset = "\x1b[0m"
+)
+
+// runDiff evaluates the ReplicasOverrides of a file against the live
+// workloads, or those of rendered manifests, and the ignore rules, and prints
+// the replicas they would change
+func runDiff(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster evaluationFlags
+	cluster.bind(fs)
+	var file string
+	var noColor bool
+	var preview controller.ManifestsRef
+	fs.StringVar(&file, "f", "", "File with the ReplicasOverrides to evaluate, - for stdin")
+	fs.StringVar(&file, "filename", "", "Same as -f")
+	fs.BoolVar(&noColor, "no-color", os.Getenv("NO_COLOR") != "", "Do not colorize the diff")
+	fs.StringVar(&preview.ConfigMap, "manifests-configmap", "",
+		"Preview the workloads of the rendered manifests in this [namespace/]ConfigMap instead of the live ones")
+	fs.StringVar(&preview.OCI, "manifests-oci", "",
+		"Preview the workloads of the rendered manifests in this OCI artifact instead of the live ones")
+	fs.StringVar(&preview.Revision, "revision", "", "Git revision the previewed manifests were rendered from")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if file == "" {
+		fmt.Fprintln(stderr, "error: -f is required")
+		return exitError
+	}
+
+	overrides, err := readOverrides(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	r, namespace, err := cluster.connect(ctx, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	var manifests *controller.ManifestsRef
+	if preview.ConfigMap != "" || preview.OCI != "" {
+		manifests = &preview
+		// Artifacts are pulled from the machine of the user, any registry is allowed
+		r.ManifestPuller = pkgmanifests.NewPuller(nil)
+	}
+
+	color := !noColor && isTerminal(stdout)
+	code := exitOK
+	for i := range overrides {
+		override := &overrides[i]
+		if override.Namespace == "" {
+			override.Namespace = namespace
+		}
+		before, err := r.Simulate(ctx, controller.SimulationRequest{Namespace: override.Namespace, Manifests: manifests})
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		after, err := r.Simulate(ctx, controller.SimulationRequest{Override: override, Manifests: manifests})
+		if err != nil {
+			fmt.Fprintf(stderr, "error: ReplicasOverride %s/%s: %v\n", override.Namespace, override.Name, err)
+			return exitError
+		}
+		if printDiff(stdout, override, before.Workloads, after.Workloads, color) {
+			code = exitChanges
+		}
+	}
+	return code
+}
+
+// readOverrides decodes the ReplicasOverrides of a YAML or JSON file with one
+// or more documents. Documents of other kinds are rejected.
+func readOverrides(file string) ([]dynamicscalingv1.ReplicasOverride, error) {
+	var in io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var overrides []dynamicscalingv1.ReplicasOverride
+	decoder := utilyaml.NewYAMLOrJSONDecoder(in, 4096)
+	for {
+		var override dynamicscalingv1.ReplicasOverride
+		if err := decoder.Decode(&override); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if override.Kind == "" && override.Name == "" {
+			continue
+		}
+		if override.Kind != "ReplicasOverride" {
+			return nil, fmt.Errorf("%s: %s %s is not a ReplicasOverride", file, override.Kind, override.Name)
+		}
+		overrides = append(overrides, override)
+	}
+	if len(overrides) == 0 {
+		return nil, fmt.Errorf("%s: no ReplicasOverride found", file)
+	}
+	return overrides, nil
+}
+
+// printDiff prints the workloads whose replicas differ once the override is
+// applied, and those it targets but leaves alone with the reason. before and
+// after are simulations of the namespace without and with the override. It
+// returns true if anything changes.
+func printDiff(w io.Writer, override *dynamicscalingv1.ReplicasOverride, before, after []controller.SimulatedWorkload, color bool) bool {
+	paint := func(code, s string) string {
+		if !color {
+			return s
+		}
+		return code + s + colorReset
+	}
+	key := func(workload controller.SimulatedWorkload) string {
+		return workload.Kind + "/" + workload.Namespace + "/" + workload.Name
+	}
+	baseline := make(map[string]controller.SimulatedWorkload, len(before))
+	for _, workload := range before {
+		baseline[key(workload)] = workload
+	}
+
+	fmt.Fprintf(w, "ReplicasOverride %s/%s\n", override.Namespace, override.Name)
+	changed, unchanged := 0, 0
+	for _, workload := range after {
+		name := fmt.Sprintf("%s %s/%s", workload.Kind, workload.Namespace, workload.Name)
+		if workload.HPA != "" {
+			name += fmt.Sprintf(" (HPA %s)", workload.HPA)
+		}
+		previous, ok := baseline[key(workload)]
+		if !ok {
+			previous = workload
+		}
+
+		if previous.Replicas == workload.Replicas && previous.MaxReplicas == workload.MaxReplicas {
+			unchanged++
+			leftAlone := strings.HasPrefix(workload.Explanation, "left alone") || strings.HasPrefix(workload.Explanation, "not simulated")
+			if workload.Override == override.Name && leftAlone {
+				fmt.Fprintln(w, paint(colorFaint, fmt.Sprintf("  %s: %s", name, workload.Explanation)))
+			}
+			continue
+		}
+
+		changed++
+		sign, code := "+", colorGreen
+		if workload.Replicas < previous.Replicas || workload.Replicas == previous.Replicas && workload.MaxReplicas < previous.MaxReplicas {
+			sign, code = "-", colorRed
+		}
+		change := fmt.Sprintf("%d -> %d", previous.Replicas, workload.Replicas)
+		if workload.HPA != "" {
+			change = fmt.Sprintf("min %d -> %d, max %d -> %d", previous.Replicas, workload.Replicas, previous.MaxReplicas, workload.MaxReplicas)
+		}
+		if workload.Clamped {
+			change += " (clamped)"
+		}
+		fmt.Fprintln(w, paint(code, fmt.Sprintf("%s %s: %s", sign, name, change)))
+		fmt.Fprintln(w, paint(colorFaint, "    "+workload.Explanation))
+	}
+	fmt.Fprintf(w, "%d workloads changed, %d unchanged\n", changed, unchanged)
+	return changed > 0
+}
+
+// isTerminal returns true if w is a terminal, where the diff is colorized by default
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
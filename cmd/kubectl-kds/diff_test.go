@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+)
+
+func TestReadOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "overrides.yaml")
+	manifest := `apiVersion: kubedynamicscaler.io/v1
+kind: ReplicasOverride
+metadata:
+  name: sale
+spec:
+  replicasPercentage: 150
+---
+apiVersion: kubedynamicscaler.io/v1
+kind: ReplicasOverride
+metadata:
+  name: night
+  namespace: batch
+spec:
+  replicasPercentage: 50
+`
+	if err := os.WriteFile(file, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := readOverrides(file)
+	if err != nil {
+		t.Fatalf("readOverrides() failed: %v", err)
+	}
+	if len(overrides) != 2 || overrides[0].Name != "sale" || overrides[1].Namespace != "batch" || overrides[1].Spec.ReplicasPercentage != 50 {
+		t.Errorf("readOverrides() = %+v", overrides)
+	}
+
+	if err := os.WriteFile(file, []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readOverrides(file); err == nil {
+		t.Error("expected other kinds to be rejected")
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	override := &dynamicscalingv1.ReplicasOverride{ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"}}
+	before := []controller.SimulatedWorkload{
+		{Kind: "Deployment", Namespace: "shop", Name: "web", CurrentReplicas: 4, Replicas: 4},
+		{Kind: "Deployment", Namespace: "shop", Name: "api", HPA: "api", CurrentReplicas: 2, Replicas: 2, CurrentMaxReplicas: 10, MaxReplicas: 10},
+		{Kind: "Deployment", Namespace: "shop", Name: "db", CurrentReplicas: 1, Replicas: 1},
+	}
+	after := []controller.SimulatedWorkload{
+		{Kind: "Deployment", Namespace: "shop", Name: "web", Override: "sale", CurrentReplicas: 4, Replicas: 6, Changed: true, Explanation: "150% from override"},
+		{Kind: "Deployment", Namespace: "shop", Name: "api", HPA: "api", Override: "sale", CurrentReplicas: 2, Replicas: 1, CurrentMaxReplicas: 10, MaxReplicas: 5, Changed: true},
+		{Kind: "Deployment", Namespace: "shop", Name: "db", Override: "sale", CurrentReplicas: 1, Replicas: 1, Explanation: "left alone: scaled to zero"},
+	}
+
+	var out bytes.Buffer
+	if !printDiff(&out, override, before, after, false) {
+		t.Error("printDiff() = false, want changes")
+	}
+	for _, want := range []string{
+		"+ Deployment shop/web: 4 -> 6",
+		"- Deployment shop/api (HPA api): min 2 -> 1, max 10 -> 5",
+		"  Deployment shop/db: left alone: scaled to zero",
+		"2 workloads changed, 1 unchanged",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("printDiff() output misses %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if printDiff(&out, override, before, before, true) {
+		t.Errorf("printDiff() = true without changes:\n%s", out.String())
+	}
+}
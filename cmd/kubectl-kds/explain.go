@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+)
+
+// runExplain prints the full decision of the controller for a Deployment,
+// from the ignores and overrides matching it to its target replicas
+func runExplain(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster evaluationFlags
+	cluster.bind(fs)
+	var output string
+	fs.StringVar(&output, "o", "text", "Output format, text or json")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	// The workload comes first, like kubectl explain deployment/foo -n bar
+	target := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[min(1, fs.NArg()):]); err != nil {
+		return exitError
+	}
+	if target == "" || fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "error: exactly one deployment/NAME is required")
+		return exitError
+	}
+	name, err := deploymentName(target)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	if output != "text" && output != "json" {
+		fmt.Fprintf(stderr, "error: unknown output format %q\n", output)
+		return exitError
+	}
+
+	r, namespace, err := cluster.connect(ctx, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	explained, err := r.Explain(ctx, namespace, name)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	if output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(explained); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	printExplanation(stdout, explained)
+	return exitOK
+}
+
+// deploymentName returns the name of a deployment/NAME argument, the kind
+// accepting the kubectl spellings
+func deploymentName(target string) (string, error) {
+	kind, name, found := strings.Cut(target, "/")
+	if !found || name == "" {
+		return "", fmt.Errorf("%q is not of the form deployment/NAME", target)
+	}
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy", "deployment.apps", "deployments.apps":
+		return name, nil
+	}
+	return "", fmt.Errorf("only deployments can be explained, not %q", kind)
+}
+
+// printExplanation prints each part of the decision in order, rules and
+// stages as tables
+func printExplanation(w io.Writer, explained *controller.WorkloadExplanation) {
+	fmt.Fprintf(w, "%s %s/%s\n\n", explained.Kind, explained.Namespace, explained.Name)
+
+	fmt.Fprintf(w, "Ignores:    %s\n", listOrNone(explained.Ignores))
+	overrides := listOrNone(explained.Overrides)
+	if explained.Override != "" {
+		overrides = fmt.Sprintf("%s (applied: %s)", overrides, explained.Override)
+	}
+	fmt.Fprintf(w, "Overrides:  %s\n", overrides)
+
+	cfg := explained.Config
+	fmt.Fprintf(w, "Config:     %s, enabled %t, %d%%, replicas %d-%d\n", cfg.Source, cfg.Enabled,
+		cfg.GlobalPercentage, cfg.MinReplicas, cfg.MaxReplicas)
+	if cfg.NamespaceDefault != "" {
+		schedule := "no schedule active"
+		if cfg.Schedule != "" {
+			schedule = fmt.Sprintf("schedule %s active", cfg.Schedule)
+		}
+		fmt.Fprintf(w, "Default:    NamespaceScalingDefault %s, %s\n", cfg.NamespaceDefault, schedule)
+	}
+	triggers := make([]string, 0, len(explained.Triggers))
+	for name, value := range explained.Triggers {
+		triggers = append(triggers, fmt.Sprintf("%s=%g", name, value))
+	}
+	sort.Strings(triggers)
+	fmt.Fprintf(w, "Triggers:   %s\n", listOrNone(triggers))
+
+	if decision := explained.Decision; decision != nil {
+		fmt.Fprintln(w, "\nRules:")
+		table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "  LAYER\tSOURCE\tPERCENTAGE")
+		for _, step := range decision.Steps {
+			fmt.Fprintf(table, "  %s\t%s\t%d%%\n", step.Layer, step.Source, step.Percentage)
+		}
+		_ = table.Flush()
+
+		if len(decision.Stages) > 0 {
+			fmt.Fprintln(w, "\nStages:")
+			table = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(table, "  STAGE\tPERCENTAGE\tREPLICAS")
+			for _, stage := range decision.Stages {
+				replicas := "-"
+				if stage.Replicas != nil {
+					replicas = fmt.Sprintf("%d", *stage.Replicas)
+				}
+				fmt.Fprintf(table, "  %s\t%d%%\t%s\n", stage.Stage, stage.Percentage, replicas)
+			}
+			_ = table.Flush()
+		}
+
+		limits := "global config"
+		if decision.LimitsFrom != "" {
+			limits = decision.LimitsFrom
+		}
+		fmt.Fprintf(w, "\nLimits:     %d-%d from %s", decision.MinReplicas, decision.MaxReplicas, limits)
+		if explained.Clamped {
+			fmt.Fprint(w, ", clamped")
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w)
+	if explained.HPA != "" {
+		fmt.Fprintf(w, "Target:     HPA %s minReplicas %d -> %d, maxReplicas %d -> %d\n", explained.HPA,
+			explained.CurrentReplicas, explained.Replicas, explained.CurrentMaxReplicas, explained.MaxReplicas)
+	} else {
+		fmt.Fprintf(w, "Target:     %d replicas, %d now, %d originally\n", explained.Replicas,
+			explained.CurrentReplicas, explained.OriginalReplicas)
+	}
+	fmt.Fprintf(w, "Because:    %s\n", explained.Explanation)
+}
+
+// listOrNone joins values, or returns none when there are none
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
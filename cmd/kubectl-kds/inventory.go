@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+)
+
+// runInventory lists the workloads under management with their owning rule
+// and their original and current replicas
+func runInventory(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("inventory", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster evaluationFlags
+	cluster.bind(fs)
+	var allNamespaces bool
+	var output string
+	fs.BoolVar(&allNamespaces, "A", false, "List the managed workloads of all namespaces")
+	fs.BoolVar(&allNamespaces, "all-namespaces", false, "Same as -A")
+	fs.StringVar(&output, "o", "table", "Output format, table or json")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if output != "table" && output != "json" {
+		fmt.Fprintf(stderr, "error: unknown output format %q\n", output)
+		return exitError
+	}
+
+	r, namespace, err := cluster.connect(ctx, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	if allNamespaces {
+		namespace = ""
+	}
+	inventory, err := r.Inventory(ctx, namespace)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	if output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(inventory); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	for _, condition := range inventory.Conditions {
+		fmt.Fprintf(stdout, "Global config %s=%s (%s): %s\n\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+	}
+	printInventory(stdout, inventory.Workloads)
+	if len(inventory.Unscalable) > 0 {
+		fmt.Fprintf(stdout, "\nNot scalable (%d pods):\n", inventory.UnscalablePods)
+		printUnscalable(stdout, inventory.Unscalable)
+	}
+	return exitOK
+}
+
+// printInventory prints the managed workloads as a table, the HPA limits
+// following the replicas in hpa mode
+func printInventory(w io.Writer, workloads []controller.ManagedWorkload) {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAMESPACE\tKIND\tNAME\tMODE\tRULE\tORIGINAL\tCURRENT\tHPA LIMITS")
+	for _, workload := range workloads {
+		limits := "-"
+		if workload.HPA != "" {
+			limits = fmt.Sprintf("%s %d-%d (was %d-%d)", workload.HPA, workload.MinReplicas, workload.MaxReplicas,
+				workload.OriginalMinReplicas, workload.OriginalMaxReplicas)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\n", workload.Namespace, workload.Kind, workload.Name,
+			workload.Mode, workload.Rule, workload.OriginalReplicas, workload.Replicas, limits)
+	}
+	_ = table.Flush()
+}
+
+// printUnscalable prints the workloads the controller cannot scale as a
+// table, with the overrides selecting them
+func printUnscalable(w io.Writer, workloads []controller.UnscalableWorkload) {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAMESPACE\tKIND\tNAME\tNODES\tPODS\tSELECTED BY")
+	for _, workload := range workloads {
+		overrides := "-"
+		if len(workload.Overrides) > 0 {
+			overrides = strings.Join(workload.Overrides, ",")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%d\t%d\t%s\n", workload.Namespace, workload.Kind, workload.Name,
+			workload.Nodes, workload.Pods, overrides)
+	}
+	_ = table.Flush()
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command kubectl-kds is a kubectl plugin to review KubeDynamicScaler changes
+// against a live cluster, e.g. `kubectl kds diff -f override.yaml`.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// Exit codes, following kubectl diff
+const (
+	exitOK      = 0
+	exitChanges = 1
+	exitError   = 2
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(dynamicscalingv1.AddToScheme(scheme))
+}
+
+const usage = `kubectl kds reviews KubeDynamicScaler changes against the live cluster.
+
+Usage:
+  kubectl kds diff -f FILE [flags]     Show the replicas a ReplicasOverride would change before it is applied
+  kubectl kds export [-o FILE] [flags] Export the overrides and original replicas of the cluster
+  kubectl kds import -f FILE [flags]   Restore an export in a rebuilt cluster
+  kubectl kds adopt [flags]            Record the current replicas of an existing cluster and suggest overrides
+  kubectl kds inventory [-A] [flags]   List the workloads under management, their rule and original replicas
+  kubectl kds explain deployment/NAME  Explain how the controller decides the replicas of a deployment
+  kubectl kds rbac [-f FILE] [flags]   Render the controller ClusterRole limited to the enabled features
+  kubectl kds policies [flags]         Generate Kyverno or Gatekeeper policies from the controller configuration
+
+Run 'kubectl kds COMMAND -h' for the flags of a command.
+`
+
+func main() {
+	// The controller packages log through controller-runtime, commands report on their own
+	log.SetLogger(zap.New(zap.WriteTo(io.Discard)))
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	switch os.Args[1] {
+	case "diff":
+		os.Exit(runDiff(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "export":
+		os.Exit(runExport(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "import":
+		os.Exit(runImport(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "adopt":
+		os.Exit(runAdopt(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "inventory":
+		os.Exit(runInventory(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "explain":
+		os.Exit(runExplain(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "rbac":
+		os.Exit(runRBAC(os.Args[2:], os.Stdout, os.Stderr))
+	case "policies":
+		os.Exit(runPolicies(context.Background(), os.Args[2:], os.Stdout, os.Stderr))
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitError)
+	}
+}
+
+// clusterFlags are the flags of the commands connecting to the cluster
+type clusterFlags struct {
+	kubeconfig string
+	context    string
+}
+
+func (f *clusterFlags) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to $KUBECONFIG or ~/.kube/config")
+	fs.StringVar(&f.context, "context", "", "The kubeconfig context to use")
+}
+
+// clientConfig returns the kubeconfig selected by the flags
+func (f *clusterFlags) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = f.kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: f.context})
+}
+
+// client returns a client for the cluster of the kubeconfig. It reads the
+// state of objects in both annotation formats and writes the legacy one, the
+// controller migrating them to its own format.
+func (f *clusterFlags) client() (client.Client, error) {
+	restConfig, err := f.clientConfig().ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewStateClient(c, utils.AnnotationFormatLegacy)
+}
+
+// evaluationFlags are the flags of the commands evaluating changes like the controller
+type evaluationFlags struct {
+	clusterFlags
+	namespace           string
+	controllerNamespace string
+}
+
+func (f *evaluationFlags) bind(fs *flag.FlagSet) {
+	f.clusterFlags.bind(fs)
+	fs.StringVar(&f.namespace, "namespace", "", "Namespace of objects without one, defaults to the namespace of the context")
+	fs.StringVar(&f.namespace, "n", "", "Shorthand for --namespace")
+	fs.StringVar(&f.controllerNamespace, "controller-namespace", config.DefaultConfigMapNamespace,
+		"Namespace of the controller configuration ConfigMap")
+}
+
+// connect returns a reconciler loaded with the live global config to evaluate
+// changes with, and the namespace of objects without one
+func (f *evaluationFlags) connect(ctx context.Context, stderr io.Writer) (*controller.ReplicasOverrideReconciler, string, error) {
+	namespace := f.namespace
+	if namespace == "" {
+		var err error
+		if namespace, _, err = f.clientConfig().Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	c, err := f.client()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The ConfigMap namespace is read from the environment like in the controller
+	if err := os.Setenv(config.EnvConfigNamespace, f.controllerNamespace); err != nil {
+		return nil, "", err
+	}
+	configManager := config.NewManager(c)
+	if err := configManager.RefreshConfig(ctx); err != nil {
+		fmt.Fprintf(stderr, "warning: using the default global config: %v\n", err)
+	}
+
+	return &controller.ReplicasOverrideReconciler{Client: c, Scheme: scheme, Config: configManager}, namespace, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/policy"
+)
+
+// defaultControllerServiceAccount is the service account of the controller installed by config/default
+const defaultControllerServiceAccount = "kubedynamicscaler-controller-manager"
+
+// runPolicies writes the admission policies of an engine derived from the
+// controller configuration, read from the cluster unless -f is set
+func runPolicies(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("policies", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster clusterFlags
+	cluster.bind(fs)
+	var file, engine, controllerNamespace, serviceAccount, productionSelector, output string
+	fs.StringVar(&file, "f", "", "Controller configuration, the replicas-controller-config ConfigMap or its config.yaml, instead of the live one")
+	fs.StringVar(&engine, "engine", string(policy.EngineKyverno),
+		fmt.Sprintf("Policy engine, %s or %s", policy.EngineKyverno, policy.EngineGatekeeper))
+	fs.StringVar(&controllerNamespace, "controller-namespace", config.DefaultConfigMapNamespace,
+		"Namespace of the controller and of its configuration ConfigMap")
+	fs.StringVar(&serviceAccount, "controller-service-account", defaultControllerServiceAccount,
+		"Service account of the controller, whose writes are always allowed")
+	fs.StringVar(&productionSelector, "production-selector", "",
+		"Label selector of the production namespaces whose overrides must set maxReplicas, e.g. env=prod")
+	fs.StringVar(&output, "o", "-", "File to write the policies to, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	selector, err := labels.ConvertSelectorToLabelsMap(productionSelector)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: invalid --production-selector: %v\n", err)
+		return exitError
+	}
+
+	var cfg *config.GlobalConfig
+	if file != "" {
+		cfg, err = readControllerConfig(file)
+	} else {
+		cfg, err = readLiveControllerConfig(ctx, cluster, controllerNamespace)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	policies, err := policy.Generate(policy.Engine(engine), cfg, policy.Options{
+		ControllerNamespace:      controllerNamespace,
+		ControllerServiceAccount: serviceAccount,
+		ProductionSelector:       selector,
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	var buf bytes.Buffer
+	for i, object := range policies {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	if output == "-" {
+		_, err = stdout.Write(buf.Bytes())
+	} else {
+		err = os.WriteFile(output, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// readLiveControllerConfig reads the controller configuration from its ConfigMap in the cluster
+func readLiveControllerConfig(ctx context.Context, cluster clusterFlags, namespace string) (*config.GlobalConfig, error) {
+	c, err := cluster.client()
+	if err != nil {
+		return nil, err
+	}
+	// The ConfigMap namespace is read from the environment like in the controller
+	if err := os.Setenv(config.EnvConfigNamespace, namespace); err != nil {
+		return nil, err
+	}
+	configManager := config.NewManager(c)
+	if err := configManager.RefreshConfig(ctx); err != nil {
+		return nil, fmt.Errorf("reading the controller configuration: %w", err)
+	}
+	return configManager.GetConfig(), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/rbac"
+)
+
+// runRBAC writes the ClusterRole of the controller limited to the features
+// turned on by a controller configuration and the --features flag
+func runRBAC(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("rbac", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var file, featureList, name, output string
+	fs.StringVar(&file, "f", "", "Controller configuration, the replicas-controller-config ConfigMap or its config.yaml")
+	fs.StringVar(&featureList, "features", "",
+		fmt.Sprintf("Comma-separated features to grant in addition to those of the configuration, or all: %v", rbac.Features()))
+	fs.StringVar(&name, "name", rbac.DefaultRoleName, "Name of the ClusterRole")
+	fs.StringVar(&output, "o", "-", "File to write the ClusterRole to, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	features, err := rbac.ParseFeatures(featureList)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	if file != "" {
+		cfg, err := readControllerConfig(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return exitError
+		}
+		features = append(features, rbac.FeaturesFromConfig(cfg)...)
+	}
+
+	data, err := yaml.Marshal(rbac.ClusterRole(name, features))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	if output == "-" {
+		_, err = stdout.Write(data)
+	} else {
+		err = os.WriteFile(output, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// readControllerConfig reads a controller configuration from the ConfigMap
+// manifest holding it or from the bare config.yaml
+func readControllerConfig(file string) (*config.GlobalConfig, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(data, configMap); err == nil && configMap.Kind == "ConfigMap" {
+		content, exists := configMap.Data[config.ConfigMapKey]
+		if !exists {
+			return nil, fmt.Errorf("no %s key in ConfigMap %s", config.ConfigMapKey, configMap.Name)
+		}
+		data = []byte(content)
+	}
+
+	cfg := config.DefaultConfig()
+	if err := yamlv3.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+	return cfg, nil
+}
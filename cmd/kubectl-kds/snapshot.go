@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/snapshot"
+)
+
+// runExport writes the scaling state of the cluster to a portable manifest
+func runExport(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster clusterFlags
+	cluster.bind(fs)
+	var output string
+	fs.StringVar(&output, "o", "-", "File to write the export to, - for stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+
+	c, err := cluster.client()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	s, err := snapshot.Export(ctx, c, time.Now())
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	if output == "-" {
+		_, err = stdout.Write(data)
+	} else {
+		err = os.WriteFile(output, data, 0o600)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	fmt.Fprintf(stderr, "exported %d overrides, %d namespace defaults, %d ignore rules and the original values of %d objects\n",
+		len(s.Overrides), len(s.NamespaceDefaults), len(s.IgnoreRules), len(s.Originals))
+	return exitOK
+}
+
+// runImport restores an export in the cluster
+func runImport(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var cluster clusterFlags
+	cluster.bind(fs)
+	var file string
+	var opts snapshot.ImportOptions
+	fs.StringVar(&file, "f", "", "File of the export to restore, - for stdin")
+	fs.BoolVar(&opts.Overwrite, "overwrite", false, "Replace existing resources and original values instead of keeping them")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "Validate every change on the API server without persisting it")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if file == "" {
+		fmt.Fprintln(stderr, "error: -f is required")
+		return exitError
+	}
+
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	s := &snapshot.Snapshot{}
+	if err := yaml.UnmarshalStrict(data, s); err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", file, err)
+		return exitError
+	}
+
+	c, err := cluster.client()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+	result, err := snapshot.Import(ctx, c, s, opts)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+
+	for _, err := range result.Errors {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+	}
+	suffix := ""
+	if opts.DryRun {
+		suffix = " (dry run)"
+	}
+	fmt.Fprintf(stdout, "%d created, %d updated, %d kept, %d not found, %d failed%s\n",
+		result.Created, result.Updated, result.Skipped, result.Missing, len(result.Errors), suffix)
+	if len(result.Errors) > 0 {
+		return exitError
+	}
+	return exitOK
+}
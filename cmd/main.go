@@ -17,10 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"flag"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -29,8 +35,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -39,7 +47,20 @@ import (
 
 	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/authorizer"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/carbon"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/chaos"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/disruption"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/httpauth"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/manifests"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/notify"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/pressure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/report"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/tracing"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/trigger"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -64,6 +85,17 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableLegacyWorkloads bool
+	var enableJobParallelism bool
+	var printPrometheusRule bool
+	var enableGatekeeperProvider bool
+	var gatekeeperClientCA string
+	var checkpointWindow time.Duration
+	var enableOCIManifests bool
+	var enableStartupAudit bool
+	var annotationFormat string
+	var listPageSize int64
+	var enableFaultInjection bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -82,6 +114,31 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableLegacyWorkloads, "enable-legacy-workloads", false,
+		"If set, ReplicationControllers and ReplicaSets not owned by a Deployment are scaled as well")
+	flag.BoolVar(&enableJobParallelism, "enable-job-parallelism", false,
+		"If set, the parallelism of running Jobs is scaled as well and restored when their override is paused")
+	flag.BoolVar(&enableGatekeeperProvider, "enable-gatekeeper-provider", false,
+		"If set, the webhook server serves the state of workloads to Gatekeeper as an external data provider")
+	flag.StringVar(&gatekeeperClientCA, "gatekeeper-client-ca", "",
+		"Path of the CA certificate of Gatekeeper, required from the clients of the webhook server when set")
+	flag.DurationVar(&checkpointWindow, "checkpoint-window", time.Minute,
+		"With leader election, how long a new leader trusts the checkpoint of the previous one instead of re-applying every target, 0 to disable")
+	flag.BoolVar(&enableOCIManifests, "enable-oci-manifests", false,
+		"If set, simulations may preview the manifests of OCI artifacts, pulled anonymously by the controller")
+	flag.BoolVar(&enableStartupAudit, "startup-audit", true,
+		"If set, the leader audits the annotations, overrides and live replicas of managed workloads once started and reports the inconsistencies found and fixed")
+	flag.StringVar(&annotationFormat, "annotation-format", utils.AnnotationFormatLegacy,
+		"Format of the state recorded on scaled objects: legacy for one annotation per field, v1 for the versioned kubedynamicscaler.io/state annotation. "+
+			"Both are read, objects are migrated to the format written once the controller started")
+	flag.Int64Var(&listPageSize, "list-page-size", 0,
+		"If set, the deployments of the whole cluster, and the objects whose annotations are migrated, are listed from the API server in pages of this many objects instead of all at once, "+
+			"bounding the memory of the controller on clusters with tens of thousands of workloads")
+	flag.BoolVar(&enableFaultInjection, "enable-fault-injection", false,
+		"If set, the controller injects the conflicts, API errors and slow responses configured on the "+chaos.Path+" endpoint "+
+			"into its requests, to exercise its resilience in CI and staging. Requires --metrics-secure. Never set it in production")
+	flag.BoolVar(&printPrometheusRule, "print-prometheus-rule", false,
+		"If set, print a PrometheusRule alerting on runaway scaling, clamping, scaling errors and work queue backlogs, then exit")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -90,6 +147,22 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// The fault injection endpoint is only protected by the authentication and authorization of the secure metrics server
+	if enableFaultInjection && !secureMetrics {
+		setupLog.Error(errors.New("--enable-fault-injection requires --metrics-secure"), "refusing to serve the fault injection endpoint without authentication")
+		os.Exit(1)
+	}
+
+	if printPrometheusRule {
+		data, err := metrics.PrometheusRuleYAML(metrics.DefaultAlertOptions())
+		if err != nil {
+			setupLog.Error(err, "unable to generate PrometheusRule")
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -105,6 +178,11 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	// Client certificates and authorization of the served endpoints follow the endpoints
+	// section of the global config, the guard is completed once the config manager exists
+	endpointGuard := &httpauth.Guard{}
+	tlsOpts = append(tlsOpts, endpointGuard.TLSOption)
+
 	// Create watchers for metrics and webhooks certificates
 	var metricsCertWatcher, webhookCertWatcher *certwatcher.CertWatcher
 
@@ -130,6 +208,24 @@ func main() {
 		})
 	}
 
+	// Only Gatekeeper, authenticated by its client certificate, may query the provider
+	if len(gatekeeperClientCA) > 0 {
+		caData, err := os.ReadFile(gatekeeperClientCA)
+		if err != nil {
+			setupLog.Error(err, "Failed to read the Gatekeeper client CA")
+			os.Exit(1)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caData) {
+			setupLog.Error(nil, "No certificate found in the Gatekeeper client CA", "gatekeeper-client-ca", gatekeeperClientCA)
+			os.Exit(1)
+		}
+		webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
+			config.ClientCAs = clientCAs
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		})
+	}
+
 	webhookServer := webhook.NewServer(webhook.Options{
 		TLSOpts: webhookTLSOpts,
 	})
@@ -142,6 +238,11 @@ func main() {
 		BindAddress:   metricsAddr,
 		SecureServing: secureMetrics,
 		TLSOpts:       tlsOpts,
+		// Serve the OpenMetrics format on a separate path so exemplars linking
+		// scaling metrics to trace IDs are visible to Prometheus and Grafana.
+		ExtraHandlers: map[string]http.Handler{
+			metrics.OpenMetricsPath: metrics.OpenMetricsHandler(),
+		},
 	}
 
 	if secureMetrics {
@@ -149,7 +250,15 @@ func main() {
 		// These configurations ensure that only authorized users and service accounts
 		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
 		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.2/pkg/metrics/filters#WithAuthenticationAndAuthorization
-		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+		// Requests with a client certificate verified against the clientCA of the endpoints
+		// config are authorized for its user instead of a bearer token.
+		metricsServerOptions.FilterProvider = func(c *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+			byToken, err := filters.WithAuthenticationAndAuthorization(c, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			return endpointGuard.CertificateFilter(byToken), nil
+		}
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -205,27 +314,197 @@ func main() {
 
 	// Setup ConfigManager first
 	configManager := config.NewManager(mgr.GetClient())
+	// Secrets referenced by the configuration are read uncached, on reload
+	configManager.SetSecretReader(mgr.GetAPIReader())
 	if err = configManager.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to setup config manager")
 		os.Exit(1)
 	}
 
-	if err = (&controller.ReplicasOverrideReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Config: configManager, // Use the same instance
-	}).SetupWithManager(mgr); err != nil {
+	endpointGuard.Client = mgr.GetClient()
+	endpointGuard.Config = configManager
+
+	// Reconcile passes are traced, and the scaling metrics carry their trace ID as
+	// exemplar, when an OTLP traces endpoint is set in the environment
+	tracer, err := tracing.Setup(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	if tracer != nil {
+		if err := mgr.Add(tracer); err != nil {
+			setupLog.Error(err, "unable to add tracer provider to manager")
+			os.Exit(1)
+		}
+	}
+
+	// Push-based metrics sinks (StatsD, OTLP) are reconfigured on every config reload
+	metricsPusher := metrics.NewPusher()
+	configManager.AddListener(metricsPusher.ApplyConfig)
+	if err := mgr.Add(metricsPusher); err != nil {
+		setupLog.Error(err, "unable to add metrics pusher to manager")
+		os.Exit(1)
+	}
+
+	// Scaling events are journaled for the periodic summary report
+	reportRecorder := report.NewRecorder()
+	if err := mgr.Add(report.NewReporter(mgr.GetClient(), configManager, reportRecorder)); err != nil {
+		setupLog.Error(err, "unable to add scaling reporter to manager")
+		os.Exit(1)
+	}
+
+	// Override notifications are sent in the background, Secrets are read uncached,
+	// webhook URLs must be in allowedURLs
+	notifier := notify.NewNotifier(mgr.GetClient(), mgr.GetAPIReader(), configManager)
+	if err := mgr.Add(notifier); err != nil {
+		setupLog.Error(err, "unable to add notifier to manager")
+		os.Exit(1)
+	}
+
+	// Carbon intensity is polled only when carbonAware is enabled in the config
+	carbonMonitor := carbon.NewMonitor(configManager)
+	if err := mgr.Add(carbonMonitor); err != nil {
+		setupLog.Error(err, "unable to add carbon intensity monitor to manager")
+		os.Exit(1)
+	}
+
+	// Node pressure is evaluated only when nodePressure is enabled in the config
+	pressureMonitor := pressure.NewMonitor(mgr.GetClient(), mgr.GetAPIReader(), configManager)
+	if err := mgr.Add(pressureMonitor); err != nil {
+		setupLog.Error(err, "unable to add node pressure monitor to manager")
+		os.Exit(1)
+	}
+
+	// External triggers are polled only when configured
+	triggerManager := trigger.NewManager(configManager)
+	if err := mgr.Add(triggerManager); err != nil {
+		setupLog.Error(err, "unable to add trigger manager to manager")
+		os.Exit(1)
+	}
+
+	// Workloads on drained or interrupted nodes are tracked for a temporary capacity boost
+	disruptionTracker := disruption.NewTracker()
+
+	// Faults injected in CI and staging go through the same client as the scaling
+	baseClient := mgr.GetClient()
+	var faultInjector *chaos.Injector
+	if enableFaultInjection {
+		setupLog.Info("Fault injection is enabled, faults set on the endpoint make requests fail", "path", chaos.Path)
+		faultInjector = chaos.NewInjector()
+		baseClient = chaos.NewClient(baseClient, faultInjector)
+	}
+
+	// Writes carry their own field manager so baselineRefresh can tell them from resizes by others
+	writer := client.WithFieldOwner(baseClient, utils.FieldManager)
+	stateClient, err := utils.NewStateClient(writer, annotationFormat)
+	if err != nil {
+		setupLog.Error(err, "invalid annotation format")
+		os.Exit(1)
+	}
+	// Cluster-wide lists are read from the API server in pages with --list-page-size
+	pager := utils.NewPager(mgr.GetAPIReader(), listPageSize)
+	if err := mgr.Add(controller.AnnotationMigration(mgr.GetAPIReader(), pager, writer, annotationFormat)); err != nil {
+		setupLog.Error(err, "unable to add annotation migration to manager")
+		os.Exit(1)
+	}
+
+	overrideReconciler := &controller.ReplicasOverrideReconciler{
+		Client:          stateClient,
+		Scheme:          mgr.GetScheme(),
+		Config:          configManager, // Use the same instance
+		Recorder:        reportRecorder,
+		Notifier:        notifier,
+		Carbon:          carbonMonitor,
+		Pressure:        pressureMonitor,
+		Triggers:        triggerManager,
+		Disruption:      disruptionTracker,
+		LegacyWorkloads: enableLegacyWorkloads,
+		JobParallelism:  enableJobParallelism,
+		Budget:          controller.NewScaleBudget(),
+		Rolling:         controller.NewRollingApply(),
+		Authorizer:      authorizer.New(),
+		Pager:           pager,
+	}
+	if enableLeaderElection && checkpointWindow > 0 {
+		overrideReconciler.Checkpoint = controller.NewCheckpoint(mgr.GetClient(), configManager.Namespace(), checkpointWindow)
+	}
+	if enableOCIManifests {
+		// Registries, their redirects and token realms are requested from inside the cluster
+		overrideReconciler.ManifestPuller = manifests.NewPuller(func(raw string) error {
+			return configManager.GetConfig().URLAllowed(raw)
+		})
+	}
+	// Scaling waits for the config and the ignore rules to be read once the caches synced
+	overrideReconciler.Startup = controller.NewStartupBarrier()
+	if err := mgr.Add(overrideReconciler.StartupRunnable()); err != nil {
+		setupLog.Error(err, "unable to add startup barrier to manager")
+		os.Exit(1)
+	}
+	if enableStartupAudit {
+		// Verifies upgrades: the report is served with the inventory and exported as metrics
+		overrideReconciler.Audit = controller.NewStartupAudit(controllerVersion())
+		if err := mgr.Add(overrideReconciler.AuditRunnable()); err != nil {
+			setupLog.Error(err, "unable to add startup audit to manager")
+			os.Exit(1)
+		}
+	}
+	if err = overrideReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ReplicasOverride")
 		os.Exit(1)
 	}
 
+	// What-if simulations, the managed workloads inventory and workload explanations are served next to the metrics,
+	// behind the same authn/authz and the endpoints config. They read every managed workload, so they are not served
+	// by an insecure metrics server.
+	if secureMetrics {
+		extraHandlers := []struct {
+			path    string
+			handler http.Handler
+		}{
+			{controller.SimulationPath, overrideReconciler.SimulationHandler()},
+			{controller.InventoryPath, overrideReconciler.InventoryHandler()},
+			{controller.ExplainPath, overrideReconciler.ExplainHandler()},
+		}
+		for _, extra := range extraHandlers {
+			if err := mgr.AddMetricsServerExtraHandler(extra.path, endpointGuard.Handler(extra.handler)); err != nil {
+				setupLog.Error(err, "unable to add endpoint", "path", extra.path)
+				os.Exit(1)
+			}
+		}
+	} else {
+		setupLog.Info("not serving the simulation, inventory and explain endpoints without --metrics-secure")
+	}
+	if faultInjector != nil {
+		if err := mgr.AddMetricsServerExtraHandler(chaos.Path, faultInjector.Handler()); err != nil {
+			setupLog.Error(err, "unable to add fault injection endpoint")
+			os.Exit(1)
+		}
+	}
+
+	// Admission policies read whether workloads are managed and their targets from the webhook server
+	if enableGatekeeperProvider {
+		mgr.GetWebhookServer().Register(controller.ExternalDataPath, endpointGuard.Handler(overrideReconciler.ExternalDataHandler()))
+	}
+
 	if err = (&controller.GlobalReplicasIgnoreReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
+		Pager:  pager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GlobalReplicasIgnore")
 		os.Exit(1)
 	}
+
+	if err = (&controller.NodeDisruptionReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Config:    configManager,
+		Tracker:   disruptionTracker,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NodeDisruption")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -252,6 +531,10 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := mgr.AddReadyzCheck("startup", overrideReconciler.Startup.Checker); err != nil {
+		setupLog.Error(err, "unable to set up startup ready check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -259,3 +542,12 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// controllerVersion returns the module version the binary was built from,
+// (devel) for local builds
+func controllerVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
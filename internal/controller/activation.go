@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// GlobalScalingReadyConditionType is the inventory condition reporting
+// whether the global config scales workloads
+const GlobalScalingReadyConditionType = "Ready"
+
+// globalScalingActive returns true if the global config is enabled and the
+// startup grace period is over, otherwise when it will be, zero while disabled
+func (r *ReplicasOverrideReconciler) globalScalingActive(now time.Time) (bool, time.Time) {
+	cfg := r.Config.GetConfig()
+	if cfg == nil || !cfg.Enabled {
+		return false, time.Time{}
+	}
+	if readyAt := r.StartedAt.Add(cfg.StartupGracePeriod); !r.StartedAt.IsZero() && now.Before(readyAt) {
+		return false, readyAt
+	}
+	return true, time.Time{}
+}
+
+// globalScalingCondition returns the Ready condition of the global config
+func (r *ReplicasOverrideReconciler) globalScalingCondition(now time.Time) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    GlobalScalingReadyConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Enforcing",
+		Message: "The global config scales the workloads no override or namespace rule targets",
+	}
+	switch active, readyAt := r.globalScalingActive(now); {
+	case active:
+	case readyAt.IsZero():
+		condition.Status, condition.Reason = metav1.ConditionFalse, "NotEnabled"
+		condition.Message = "Set enabled: true in the global config to let it scale workloads no override or namespace rule targets"
+	default:
+		condition.Status, condition.Reason = metav1.ConditionFalse, "StartupGracePeriod"
+		condition.Message = fmt.Sprintf("The global config starts scaling workloads at %s", readyAt.UTC().Format(time.RFC3339))
+	}
+	return condition
+}
+
+// onlyGlobalScaling returns true if nothing but the global config would scale
+// workload: no override targets it, its namespace has no NamespaceScalingDefault
+// or NamespaceReplicasOverride, and the controller does not manage it yet.
+// Managed workloads stay managed so deleting their override still restores them.
+func (r *ReplicasOverrideReconciler) onlyGlobalScaling(ctx context.Context, workload client.Object, override *dynamicscalingv1.ReplicasOverride) bool {
+	if override != nil || isManaged(workload) {
+		return false
+	}
+	namespace := workload.GetNamespace()
+	return r.namespaceDefault(ctx, namespace) == nil && r.namespaceOverride(ctx, namespace) == nil
+}
+
+// globalScalingHeld returns true if workload must be left alone because only
+// the global config would scale it and it is not active yet
+func (r *ReplicasOverrideReconciler) globalScalingHeld(ctx context.Context, workload client.Object, override *dynamicscalingv1.ReplicasOverride) bool {
+	if active, _ := r.globalScalingActive(time.Now()); active {
+		return false
+	}
+	return r.onlyGlobalScaling(ctx, workload, override)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestGlobalScalingActivation(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(name string, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Annotations: annotations},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		}
+	}
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		deployment("new", nil),
+		deployment("managed", map[string]string{utils.GlobalConfigManagedAnnotation: "true", utils.OriginalReplicasAnnotation: "4"}),
+	).Build()
+	cfg := &config.GlobalConfig{GlobalPercentage: 200, MinReplicas: 1, MaxReplicas: 100}
+	started := time.Now()
+	r := newTestReconciler(c, cfg)
+	r.StartedAt = started
+	replicasOf := func(name string) int32 {
+		var d appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, &d); err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		return *d.Spec.Replicas
+	}
+
+	if condition := r.globalScalingCondition(started); condition.Status != metav1.ConditionFalse || condition.Reason != "NotEnabled" {
+		t.Errorf("condition while disabled = %s/%s, want False/NotEnabled", condition.Status, condition.Reason)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := replicasOf("new"); got != 2 {
+		t.Errorf("new deployment replicas while disabled = %d, want 2 left alone", got)
+	}
+	if got := replicasOf("managed"); got != 8 {
+		t.Errorf("managed deployment replicas while disabled = %d, want 8 still managed", got)
+	}
+
+	cfg.Enabled, cfg.StartupGracePeriod = true, time.Hour
+	if condition := r.globalScalingCondition(started.Add(time.Minute)); condition.Status != metav1.ConditionFalse || condition.Reason != "StartupGracePeriod" {
+		t.Errorf("condition during the grace period = %s/%s, want False/StartupGracePeriod", condition.Status, condition.Reason)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := replicasOf("new"); got != 2 {
+		t.Errorf("new deployment replicas during the grace period = %d, want 2 left alone", got)
+	}
+
+	r.StartedAt = started.Add(-2 * time.Hour)
+	if condition := r.globalScalingCondition(time.Now()); condition.Status != metav1.ConditionTrue {
+		t.Errorf("condition once enabled = %s/%s, want True", condition.Status, condition.Reason)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := replicasOf("new"); got != 4 {
+		t.Errorf("new deployment replicas once enabled = %d, want 4", got)
+	}
+}
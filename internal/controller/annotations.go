@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// InvalidStateConditionType is the ReplicasOverride condition reporting
+// targets whose recorded originals were corrupt and had to be repaired
+const InvalidStateConditionType = "InvalidState"
+
+const (
+	// maxInvalidStateEntries caps the repairs listed in the InvalidState condition
+	maxInvalidStateEntries = 10
+
+	// invalidStatePrefix starts the message of a true InvalidState condition
+	invalidStatePrefix = "Recorded the originals again from the status backup or the current spec: "
+)
+
+// originalAnnotations are the annotations recording the original values of a
+// target, which must hold a non-negative replica count
+var originalAnnotations = []string{
+	utils.OriginalReplicasAnnotation,
+	utils.OriginalMinReplicasAnnotation,
+	utils.OriginalMaxReplicasAnnotation,
+	utils.OriginalParallelismAnnotation,
+}
+
+// dropInvalidOriginals removes the original annotations of obj that do not
+// hold a valid replica count, or were recorded on another object, so they are
+// recorded again from the override status backup or the current spec, and
+// returns a description of each
+func dropInvalidOriginals(obj metav1.Object) []string {
+	var problems []string
+	if recordedOn := dropStaleOriginals(obj); recordedOn != "" {
+		problems = append(problems, fmt.Sprintf("originals recorded on a previous object %s", recordedOn))
+	}
+	annotations := obj.GetAnnotations()
+	for _, key := range originalAnnotations {
+		value, exists := annotations[key]
+		if !exists {
+			continue
+		}
+		if _, ok := utils.ParseReplicas(value); !ok {
+			problems = append(problems, fmt.Sprintf("%s=%q", key, value))
+			delete(annotations, key)
+		}
+	}
+	if len(problems) > 0 {
+		// Unstructured objects return a copy of their annotations
+		obj.SetAnnotations(annotations)
+	}
+	return problems
+}
+
+// repairOriginals drops the corrupt original annotations of a target of the
+// given kind before they are used, and reports them on the override. It
+// returns true if the target must be written to persist the repair.
+func (r *ReplicasOverrideReconciler) repairOriginals(ctx context.Context, kind string, obj metav1.Object, override *dynamicscalingv1.ReplicasOverride) bool {
+	problems := dropInvalidOriginals(obj)
+	if len(problems) == 0 {
+		return false
+	}
+
+	target := fmt.Sprintf("%s %s/%s", kind, obj.GetNamespace(), obj.GetName())
+	log.FromContext(ctx).Error(fmt.Errorf("invalid replica counts: %s", strings.Join(problems, ", ")),
+		"Repairing corrupt original annotations", "target", target)
+	if override == nil {
+		return true
+	}
+
+	if setInvalidStateCondition(override, fmt.Sprintf("%s had %s", target, strings.Join(problems, ", "))) {
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+	return true
+}
+
+// setInvalidStateCondition adds a repair to the InvalidState condition of the
+// override, keeping the repairs reported since the current generation. It
+// returns true if the condition changed.
+func setInvalidStateCondition(override *dynamicscalingv1.ReplicasOverride, repair string) bool {
+	entries := []string{repair}
+	if existing := meta.FindStatusCondition(override.Status.Conditions, InvalidStateConditionType); existing != nil &&
+		existing.Status == metav1.ConditionTrue && existing.ObservedGeneration == override.Generation {
+		for _, entry := range strings.Split(strings.TrimPrefix(existing.Message, invalidStatePrefix), "; ") {
+			if entry != repair && len(entries) < maxInvalidStateEntries {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	sort.Strings(entries)
+
+	return meta.SetStatusCondition(&override.Status.Conditions, metav1.Condition{
+		Type:               InvalidStateConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CorruptAnnotationsRepaired",
+		Message:            invalidStatePrefix + strings.Join(entries, "; "),
+		ObservedGeneration: override.Generation,
+	})
+}
+
+// clearInvalidState marks the InvalidState condition of the override false
+// once its spec changed since the repairs were reported
+func clearInvalidState(override *dynamicscalingv1.ReplicasOverride) {
+	existing := meta.FindStatusCondition(override.Status.Conditions, InvalidStateConditionType)
+	if existing == nil || existing.Status == metav1.ConditionFalse || existing.ObservedGeneration == override.Generation {
+		return
+	}
+	meta.SetStatusCondition(&override.Status.Conditions, metav1.Condition{
+		Type:               InvalidStateConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AnnotationsValid",
+		ObservedGeneration: override.Generation,
+	})
+}
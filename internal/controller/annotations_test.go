@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestRepairOriginals(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		annotation string
+		backup     bool
+		wantRepair bool
+		want       int32
+	}{
+		{name: "valid annotation", annotation: "4", want: 2},
+		{name: "not a number restored from the status backup", annotation: "four", backup: true, wantRepair: true, want: 4},
+		{name: "negative restored from the spec", annotation: "-3", wantRepair: true, want: 3},
+		{name: "overflowing restored from the spec", annotation: "99999999999", wantRepair: true, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := int32(6)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{utils.OriginalReplicasAnnotation: tt.annotation}},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}
+			override := &dynamicscalingv1.ReplicasOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "night", Namespace: "shop", Generation: 2},
+				Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 50},
+			}
+			if tt.backup {
+				override.Status.AffectedDeployments = []dynamicscalingv1.AffectedDeployment{{Name: "web", Namespace: "shop", OriginalReplicas: 8}}
+			}
+			c := fakeclient.NewBuilder(deployment, override).WithStatusSubresource(override).Build()
+			r := newTestReconciler(c, nil)
+
+			if err := r.processDeployment(ctx, deployment, override); err != nil {
+				t.Fatalf("processDeployment() failed: %v", err)
+			}
+			got := &appsv1.Deployment{}
+			if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if *got.Spec.Replicas != tt.want {
+				t.Errorf("replicas = %d, want %d (original %q)", *got.Spec.Replicas, tt.want, got.Annotations[utils.OriginalReplicasAnnotation])
+			}
+			if _, ok := utils.ParseReplicas(got.Annotations[utils.OriginalReplicasAnnotation]); !ok {
+				t.Errorf("original replicas annotation = %q, want a valid count", got.Annotations[utils.OriginalReplicasAnnotation])
+			}
+
+			condition := meta.FindStatusCondition(override.Status.Conditions, InvalidStateConditionType)
+			if !tt.wantRepair {
+				if condition != nil {
+					t.Errorf("unexpected %s condition: %s", InvalidStateConditionType, condition.Message)
+				}
+				return
+			}
+			if condition == nil || condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, "Deployment shop/web") {
+				t.Fatalf("%s condition = %+v, want it true for the deployment", InvalidStateConditionType, condition)
+			}
+			override.Generation++
+			clearInvalidState(override)
+			if condition := meta.FindStatusCondition(override.Status.Conditions, InvalidStateConditionType); condition.Status != metav1.ConditionFalse {
+				t.Errorf("%s condition after a change of the override = %s, want False", InvalidStateConditionType, condition.Status)
+			}
+		})
+	}
+}
+
+func TestRecreatedDeployment(t *testing.T) {
+	ctx := context.Background()
+	// Redeployed from the manifest of the previous deployment, annotations included
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", UID: "new", Annotations: map[string]string{
+			utils.OriginalReplicasAnnotation: "10",
+			utils.OriginalsUIDAnnotation:     "old",
+		}},
+		Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+			OverrideType:       "override",
+			ReplicasPercentage: 200,
+		},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+			{Name: "api", Namespace: "shop", UID: "old", OriginalReplicas: 10, CurrentReplicas: 20, CurrentPercentage: 200},
+		}},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+		Build()
+	r := newTestReconciler(c, nil)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatal(err)
+	}
+	// The originals of the previous deployment are dropped and recorded again from the spec
+	if *deployment.Spec.Replicas != 6 {
+		t.Errorf("replicas = %d, want 6", *deployment.Spec.Replicas)
+	}
+	if got := deployment.Annotations[utils.OriginalReplicasAnnotation]; got != "3" {
+		t.Errorf("original replicas = %q, want 3", got)
+	}
+	if got := deployment.Annotations[utils.OriginalsUIDAnnotation]; got != "new" {
+		t.Errorf("originals recorded on %q, want new", got)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	want := dynamicscalingv1.AffectedDeployment{Name: "api", Namespace: "shop", UID: "new", OriginalReplicas: 3, CurrentReplicas: 6, CurrentPercentage: 200}
+	if len(override.Status.AffectedDeployments) != 1 {
+		t.Fatalf("affectedDeployments = %+v, want one entry", override.Status.AffectedDeployments)
+	}
+	got := override.Status.AffectedDeployments[0]
+	if got.UID != want.UID || got.OriginalReplicas != want.OriginalReplicas || got.CurrentReplicas != want.CurrentReplicas {
+		t.Errorf("affected deployment = %+v, want %+v", got, want)
+	}
+}
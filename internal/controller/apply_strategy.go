@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
+
+const (
+	// rollingWindow is the window the targets changed by Rolling overrides are counted over
+	rollingWindow = time.Minute
+	// defaultMaxTargetsPerMinute is the pace of Rolling overrides without maxTargetsPerMinute
+	defaultMaxTargetsPerMinute = 10
+)
+
+// errRollingPaced is returned when a change is deferred because its Rolling
+// override already changed its targets of the minute
+var errRollingPaced = failure.New(failure.CapacityExceeded, "change deferred, the override changed its targets of this minute")
+
+// RollingApply counts the targets each override with a Rolling applyStrategy
+// changed over the last minute, and defers further changes once it changed
+// maxTargetsPerMinute of them
+type RollingApply struct {
+	mu      sync.Mutex
+	changes map[types.UID][]time.Time
+	// pacedUntil is when the last deferred change fits again
+	pacedUntil time.Time
+}
+
+// NewRollingApply returns a pacer that has seen no change
+func NewRollingApply() *RollingApply {
+	return &RollingApply{changes: make(map[types.UID][]time.Time)}
+}
+
+// rolling returns true if override changes its targets with a Rolling applyStrategy
+func rolling(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override != nil && override.Spec.ApplyStrategy != nil && override.Spec.ApplyStrategy.Type == dynamicscalingv1.ApplyStrategyRolling
+}
+
+// allow counts a change of a target of override and returns true, or returns
+// false and when the change fits again if the override changed its targets of
+// the minute. A nil pacer or an Immediate override allows everything.
+func (a *RollingApply) allow(override *dynamicscalingv1.ReplicasOverride, now time.Time) (bool, time.Time) {
+	if a == nil || !rolling(override) {
+		return true, time.Time{}
+	}
+	limit := int(override.Spec.ApplyStrategy.MaxTargetsPerMinute)
+	if limit <= 0 {
+		limit = defaultMaxTargetsPerMinute
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	since := now.Add(-rollingWindow)
+	for uid, changes := range a.changes {
+		kept := 0
+		for kept < len(changes) && changes[kept].Before(since) {
+			kept++
+		}
+		if kept == len(changes) {
+			delete(a.changes, uid)
+		} else {
+			a.changes[uid] = changes[kept:]
+		}
+	}
+
+	changes := a.changes[override.UID]
+	if len(changes) < limit {
+		a.changes[override.UID] = append(changes, now)
+		return true, time.Time{}
+	}
+	until := changes[len(changes)-limit].Add(rollingWindow)
+	if until.After(a.pacedUntil) {
+		a.pacedUntil = until
+	}
+	return false, until
+}
+
+// paced returns when the changes deferred by Rolling overrides are retried,
+// zero when none are
+func (a *RollingApply) paced(now time.Time) time.Time {
+	if a == nil {
+		return time.Time{}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !now.Before(a.pacedUntil) {
+		return time.Time{}
+	}
+	return a.pacedUntil
+}
+
+// paceChange counts a change of a target of override against its Rolling
+// applyStrategy, returning errRollingPaced if it is deferred
+func (r *ReplicasOverrideReconciler) paceChange(ctx context.Context, labels metrics.ScalingLabels, override *dynamicscalingv1.ReplicasOverride, name string) error {
+	allowed, until := r.Rolling.allow(override, time.Now())
+	if allowed {
+		return nil
+	}
+	log.FromContext(ctx).Info("Rolling override changed its targets of the minute, deferring change",
+		"override", override.Name,
+		"kind", labels.TargetKind,
+		"target", fmt.Sprintf("%s/%s", labels.Namespace, name),
+		"until", until)
+	return errRollingPaced
+}
+
+// hasRollingOverride returns true if one of overrides has a Rolling applyStrategy
+func hasRollingOverride(overrides []dynamicscalingv1.ReplicasOverride) bool {
+	for i := range overrides {
+		if rolling(&overrides[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByPriority orders deployments by the value of the PriorityClass of
+// their pods, highest first, so Rolling overrides change the most important
+// targets first. Pods without a PriorityClass, or one that cannot be read,
+// run at priority 0.
+func (r *ReplicasOverrideReconciler) sortByPriority(ctx context.Context, deployments []appsv1.Deployment) {
+	values := make(map[string]int32)
+	priority := func(deployment *appsv1.Deployment) int32 {
+		name := deployment.Spec.Template.Spec.PriorityClassName
+		if name == "" {
+			return 0
+		}
+		if value, read := values[name]; read {
+			return value
+		}
+		priorityClass := &schedulingv1.PriorityClass{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, priorityClass); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to get PriorityClass, ordering the deployment at priority 0",
+				"priorityClass", name, "error", err.Error())
+		}
+		values[name] = priorityClass.Value
+		return priorityClass.Value
+	}
+	sort.SliceStable(deployments, func(i, j int) bool {
+		return priority(&deployments[i]) > priority(&deployments[j])
+	})
+}
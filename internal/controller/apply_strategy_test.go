@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestRollingApplyStrategy(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(name, priorityClass string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"team": "shop"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		}
+		d.Spec.Template.Spec.PriorityClassName = priorityClass
+		return d
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop", UID: "sale-uid"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "shop"}},
+			OverrideType:       "override",
+			ReplicasPercentage: 200,
+			ApplyStrategy:      &dynamicscalingv1.ApplyStrategy{Type: dynamicscalingv1.ApplyStrategyRolling, MaxTargetsPerMinute: 2},
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "checkout-critical"}, Value: 1000},
+		deployment("api", ""), deployment("batch", ""), deployment("checkout", "checkout-critical"), override).
+		Build()
+	r := newTestReconciler(c, nil)
+	r.Rolling = NewRollingApply()
+
+	result, err := r.Reconcile(ctx, ctrl.Request{})
+	if err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	// The deployment of the highest PriorityClass goes first, the third waits for the next minute
+	want := map[string]int32{"checkout": 4, "api": 4, "batch": 2}
+	for name, replicas := range want {
+		got := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, got); err != nil {
+			t.Fatal(err)
+		}
+		if *got.Spec.Replicas != replicas {
+			t.Errorf("%s replicas = %d, want %d", name, *got.Spec.Replicas, replicas)
+		}
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > rollingWindow {
+		t.Errorf("RequeueAfter = %v, want the deferred change retried within a minute", result.RequeueAfter)
+	}
+
+	// The pace frees up as the changes leave the window
+	now := time.Now()
+	if allowed, _ := r.Rolling.allow(override, now); allowed {
+		t.Errorf("allow() within the minute succeeded")
+	}
+	if allowed, _ := r.Rolling.allow(override, now.Add(rollingWindow+time.Second)); !allowed {
+		t.Errorf("allow() after the minute failed")
+	}
+	immediate := override.DeepCopy()
+	immediate.Spec.ApplyStrategy = nil
+	if allowed, _ := r.Rolling.allow(immediate, now); !allowed {
+		t.Errorf("allow() of an Immediate override failed")
+	}
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// AuditConditionType is the inventory condition reporting the result of the
+// audit run when the controller starts
+const AuditConditionType = "Audited"
+
+// Checks of the startup audit, the check label of StartupAuditInconsistencies
+const (
+	// auditCorruptOriginal is a managed deployment whose original annotations
+	// do not hold a replica count, fixed by recording them again
+	auditCorruptOriginal = "corrupt-original"
+	// auditMissingOriginal is a managed deployment without original replicas,
+	// fixed from the status backup of its override when there is one
+	auditMissingOriginal = "missing-original"
+	// auditOrphaned is a deployment marked managed by an override whose
+	// status does not list it
+	auditOrphaned = "orphaned"
+	// auditReplicaDrift is a deployment scaled directly whose replicas differ
+	// from those the controller last applied
+	auditReplicaDrift = "replica-drift"
+	// auditStaleStatus is an override status listing a deployment that no
+	// longer exists, fixed by dropping the entry
+	auditStaleStatus = "stale-status"
+	// auditStatusDrift is an override status whose current replicas differ
+	// from the live replicas, fixed by updating the status
+	auditStatusDrift = "status-drift"
+)
+
+// auditChecks are every check of the startup audit, so a check finding
+// nothing is reported as zero
+var auditChecks = []string{auditCorruptOriginal, auditMissingOriginal, auditOrphaned, auditReplicaDrift, auditStaleStatus, auditStatusDrift}
+
+// AuditFinding is an inconsistency found by the startup audit
+type AuditFinding struct {
+	Check     string `json:"check"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Detail    string `json:"detail"`
+	// Fixed is true if the audit repaired the inconsistency
+	Fixed bool `json:"fixed"`
+}
+
+// AuditReport is the result of the startup audit
+type AuditReport struct {
+	// Version is the version of the controller that ran the audit
+	Version     string         `json:"version"`
+	CompletedAt metav1.Time    `json:"completedAt"`
+	Findings    []AuditFinding `json:"findings"`
+	// Error is set when the audit could not complete
+	Error string `json:"error,omitempty"`
+}
+
+// StartupAudit compares the annotations of managed deployments, the status of
+// the overrides and the live replicas once the leader started, typically
+// after an upgrade, fixes what can be fixed safely and reports the rest
+type StartupAudit struct {
+	version string
+
+	mu     sync.Mutex
+	report *AuditReport
+}
+
+// NewStartupAudit returns the startup audit of a controller of version
+func NewStartupAudit(version string) *StartupAudit {
+	return &StartupAudit{version: version}
+}
+
+// Report returns the report of the audit, nil until it completed
+func (a *StartupAudit) Report() *AuditReport {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.report
+}
+
+// AuditRunnable returns the runnable running the startup audit once, on the
+// leader after the caches synced and the startup barrier passed
+func (r *ReplicasOverrideReconciler) AuditRunnable() manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		if r.Audit == nil {
+			return nil
+		}
+		// The audit compares the workloads to the config, read first
+		if err := r.Startup.Wait(ctx); err != nil {
+			return nil
+		}
+		report := r.runAudit(ctx)
+		r.Audit.mu.Lock()
+		r.Audit.report = report
+		r.Audit.mu.Unlock()
+		return nil
+	})
+}
+
+// runAudit runs every check of the startup audit and publishes the counts
+func (r *ReplicasOverrideReconciler) runAudit(ctx context.Context) *AuditReport {
+	logger := log.FromContext(ctx).WithName("startup-audit")
+	report := &AuditReport{Version: r.Audit.version, Findings: []AuditFinding{}}
+	findings, err := r.auditDeployments(ctx)
+	report.Findings = findings
+	report.CompletedAt = metav1.NewTime(time.Now().UTC())
+	if err != nil {
+		logger.Error(err, "Startup audit failed")
+		report.Error = err.Error()
+	}
+
+	found := make(map[string]int, len(auditChecks))
+	fixed := make(map[string]int, len(auditChecks))
+	for _, finding := range report.Findings {
+		found[finding.Check]++
+		if finding.Fixed {
+			fixed[finding.Check]++
+		}
+		logger.Info("Inconsistency found",
+			"check", finding.Check,
+			"target", fmt.Sprintf("%s %s/%s", finding.Kind, finding.Namespace, finding.Name),
+			"detail", finding.Detail,
+			"fixed", finding.Fixed)
+	}
+	for _, check := range auditChecks {
+		metrics.SetAuditInconsistencies(check, found[check], fixed[check])
+	}
+	logger.Info("Startup audit completed", "version", report.Version, "inconsistencies", len(report.Findings))
+	return report
+}
+
+// auditDeployments checks the managed deployments against the overrides
+// listing them in their status
+func (r *ReplicasOverrideReconciler) auditDeployments(ctx context.Context) ([]AuditFinding, error) {
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrides); err != nil {
+		return nil, fmt.Errorf("failed to list overrides: %w", err)
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	live := make(map[types.NamespacedName]*appsv1.Deployment, len(deployments.Items))
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		live[client.ObjectKeyFromObject(deployment)] = deployment
+	}
+
+	// Overrides backing up the originals of each deployment
+	listedBy := make(map[types.NamespacedName]*dynamicscalingv1.ReplicasOverride)
+	var findings []AuditFinding
+	for i := range overrides.Items {
+		override := &overrides.Items[i]
+		statusFindings := auditOverrideStatus(override, live)
+		if len(statusFindings) > 0 {
+			if err := r.Status().Update(ctx, override); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to fix override status",
+					"override", override.Name, "namespace", override.Namespace)
+				for j := range statusFindings {
+					statusFindings[j].Fixed = false
+				}
+			}
+			findings = append(findings, statusFindings...)
+		}
+		for _, affected := range override.Status.AffectedDeployments {
+			listedBy[types.NamespacedName{Namespace: affected.Namespace, Name: affected.Name}] = override
+		}
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !isManaged(deployment) {
+			continue
+		}
+		override := listedBy[client.ObjectKeyFromObject(deployment)]
+		deploymentFindings := auditDeployment(deployment, override)
+		if len(deploymentFindings) == 0 {
+			continue
+		}
+		if changed := auditFixes(deploymentFindings); changed {
+			if err := r.Update(ctx, deployment); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to fix deployment annotations",
+					"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+				for j := range deploymentFindings {
+					deploymentFindings[j].Fixed = false
+				}
+			}
+		}
+		findings = append(findings, deploymentFindings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return findings, nil
+}
+
+// auditFixes returns true if a finding was fixed in memory and must be written
+func auditFixes(findings []AuditFinding) bool {
+	for _, finding := range findings {
+		if finding.Fixed {
+			return true
+		}
+	}
+	return false
+}
+
+// auditOverrideStatus drops the deployments of the status of an override that
+// no longer exist and updates the current replicas of the others in memory
+func auditOverrideStatus(override *dynamicscalingv1.ReplicasOverride, live map[types.NamespacedName]*appsv1.Deployment) []AuditFinding {
+	var findings []AuditFinding
+	kept := override.Status.AffectedDeployments[:0]
+	for _, affected := range override.Status.AffectedDeployments {
+		finding := AuditFinding{
+			Kind:      "ReplicasOverride",
+			Namespace: override.Namespace,
+			Name:      override.Name,
+			Fixed:     true,
+		}
+		deployment := live[types.NamespacedName{Namespace: affected.Namespace, Name: affected.Name}]
+		if deployment == nil {
+			finding.Check = auditStaleStatus
+			finding.Detail = fmt.Sprintf("deployment %s/%s no longer exists", affected.Namespace, affected.Name)
+			findings = append(findings, finding)
+			continue
+		}
+		if !recordedFor(&affected, deployment) {
+			finding.Check = auditStaleStatus
+			finding.Detail = fmt.Sprintf("deployment %s/%s was recreated", affected.Namespace, affected.Name)
+			findings = append(findings, finding)
+			continue
+		}
+		if deployment.Spec.Replicas != nil && affected.CurrentReplicas != *deployment.Spec.Replicas {
+			finding.Check = auditStatusDrift
+			finding.Detail = fmt.Sprintf("deployment %s/%s has %d replicas, the status recorded %d",
+				affected.Namespace, affected.Name, *deployment.Spec.Replicas, affected.CurrentReplicas)
+			findings = append(findings, finding)
+			affected.CurrentReplicas = *deployment.Spec.Replicas
+		}
+		kept = append(kept, affected)
+	}
+	override.Status.AffectedDeployments = kept
+	return findings
+}
+
+// auditDeployment checks the annotations of a managed deployment, and its
+// replicas when scaled directly, fixing the originals in memory
+func auditDeployment(deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) []AuditFinding {
+	finding := func(check, detail string, fixed bool) AuditFinding {
+		return AuditFinding{
+			Check:     check,
+			Kind:      "Deployment",
+			Namespace: deployment.Namespace,
+			Name:      deployment.Name,
+			Detail:    detail,
+			Fixed:     fixed,
+		}
+	}
+	var findings []AuditFinding
+	backup := deploymentBackup(override, deployment.Namespace, deployment.Name)
+
+	if problems := dropInvalidOriginals(deployment); len(problems) > 0 {
+		// Without a backup the next pass records the originals from the spec
+		findings = append(findings, finding(auditCorruptOriginal, strings.Join(problems, ", "), true))
+		if backup != nil && backup.OriginalReplicas > 0 {
+			deployment.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(backup.OriginalReplicas), 10)
+		}
+	} else if _, exists := deployment.Annotations[utils.OriginalReplicasAnnotation]; !exists {
+		fixed := backup != nil && backup.OriginalReplicas > 0
+		if fixed {
+			deployment.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(backup.OriginalReplicas), 10)
+		}
+		findings = append(findings, finding(auditMissingOriginal, "no original replicas recorded", fixed))
+	}
+
+	if deployment.Annotations[utils.ManagedAnnotation] == "true" && override == nil {
+		findings = append(findings, finding(auditOrphaned, "marked managed by an override whose status does not list it", false))
+	}
+
+	// A pending scale-down is expected to differ from the replicas applied last
+	if deployment.Annotations[utils.ManagementModeAnnotation] == "direct" && deployment.Spec.Replicas != nil &&
+		deployment.Annotations[utils.DownscaleDrainAnnotation] == "" {
+		var explanation precedence.Explanation
+		if err := json.Unmarshal([]byte(deployment.Annotations[utils.ExplainAnnotation]), &explanation); err == nil &&
+			explanation.Replicas != nil && *explanation.Replicas != *deployment.Spec.Replicas {
+			findings = append(findings, finding(auditReplicaDrift,
+				fmt.Sprintf("%d replicas, the controller last applied %d", *deployment.Spec.Replicas, *explanation.Replicas), false))
+		}
+	}
+	return findings
+}
+
+// auditCondition returns the Audited condition of the startup audit
+func (r *ReplicasOverrideReconciler) auditCondition() metav1.Condition {
+	condition := metav1.Condition{
+		Type:    AuditConditionType,
+		Status:  metav1.ConditionUnknown,
+		Reason:  "AuditPending",
+		Message: "The startup audit has not completed yet",
+	}
+	if r.Audit == nil {
+		condition.Reason, condition.Message = "AuditDisabled", "The startup audit is disabled"
+		return condition
+	}
+	report := r.Audit.Report()
+	if report == nil {
+		return condition
+	}
+	condition.LastTransitionTime = report.CompletedAt
+	if report.Error != "" {
+		condition.Status, condition.Reason = metav1.ConditionFalse, "AuditFailed"
+		condition.Message = fmt.Sprintf("The startup audit of version %s failed: %s", report.Version, report.Error)
+		return condition
+	}
+
+	unfixed := 0
+	for _, finding := range report.Findings {
+		if !finding.Fixed {
+			unfixed++
+		}
+	}
+	condition.Status, condition.Reason = metav1.ConditionTrue, "Consistent"
+	condition.Message = fmt.Sprintf("The startup audit of version %s found %d inconsistencies, all fixed", report.Version, len(report.Findings))
+	if unfixed > 0 {
+		condition.Status, condition.Reason = metav1.ConditionFalse, "InconsistenciesFound"
+		condition.Message = fmt.Sprintf("The startup audit of version %s found %d inconsistencies, %d left unfixed", report.Version, len(report.Findings), unfixed)
+	}
+	return condition
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestStartupAudit(t *testing.T) {
+	ctx := context.Background()
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+			{Name: "web", Namespace: "shop", OriginalReplicas: 6, CurrentReplicas: 2},
+			{Name: "gone", Namespace: "shop", OriginalReplicas: 1, CurrentReplicas: 1},
+		}},
+	}
+	c := fakeclient.NewBuilder().WithStatusSubresource(override).WithObjects(
+		override,
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{
+				utils.ManagedAnnotation:          "true",
+				utils.ManagementModeAnnotation:   "direct",
+				utils.OriginalReplicasAnnotation: "six",
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Annotations: map[string]string{
+				utils.GlobalConfigManagedAnnotation: "true",
+				utils.ManagementModeAnnotation:      "direct",
+				utils.OriginalReplicasAnnotation:    "5",
+				utils.ExplainAnnotation:             `{"percentage":80,"steps":[],"minReplicas":1,"maxReplicas":100,"replicas":4}`,
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(5)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "shop", Annotations: map[string]string{
+				utils.ManagedAnnotation:          "true",
+				utils.OriginalReplicasAnnotation: "2",
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+	).Build()
+	r := newTestReconciler(c, nil)
+	r.Audit = NewStartupAudit("v1.4.0")
+
+	if condition := r.auditCondition(); condition.Status != metav1.ConditionUnknown {
+		t.Errorf("condition before the audit = %s, want Unknown", condition.Status)
+	}
+	if err := r.AuditRunnable().Start(ctx); err != nil {
+		t.Fatalf("startup audit failed: %v", err)
+	}
+
+	report := r.Audit.Report()
+	var got []string
+	for _, finding := range report.Findings {
+		got = append(got, fmt.Sprintf("%s %s/%s fixed=%v", finding.Check, finding.Kind, finding.Name, finding.Fixed))
+	}
+	want := []string{
+		"corrupt-original Deployment/web fixed=true",
+		"orphaned Deployment/legacy fixed=false",
+		"replica-drift Deployment/api fixed=false",
+		"stale-status ReplicasOverride/sale fixed=true",
+		"status-drift ReplicasOverride/sale fixed=true",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	web := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, web); err != nil {
+		t.Fatal(err)
+	}
+	if original := web.Annotations[utils.OriginalReplicasAnnotation]; original != "6" {
+		t.Errorf("original replicas of web = %q, want 6 from the status backup", original)
+	}
+	fixed := &dynamicscalingv1.ReplicasOverride{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), fixed); err != nil {
+		t.Fatal(err)
+	}
+	if affected := fixed.Status.AffectedDeployments; len(affected) != 1 || affected[0].CurrentReplicas != 3 {
+		t.Errorf("override status = %+v, want only web at 3 replicas", affected)
+	}
+
+	if got := testutil.ToFloat64(metrics.StartupAuditInconsistencies.WithLabelValues(auditStatusDrift, metrics.AuditResultFixed)); got != 1 {
+		t.Errorf("fixed status drifts metric = %v, want 1", got)
+	}
+	if condition := r.auditCondition(); condition.Status != metav1.ConditionFalse || condition.Reason != "InconsistenciesFound" {
+		t.Errorf("condition = %s/%s, want False/InconsistenciesFound with the orphan and drift left", condition.Status, condition.Reason)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// safeToEvictAnnotation is the pod annotation cluster-autoscaler reads before
+// removing a node, "false" keeps the node of the pod
+const safeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list;patch
+
+// scaledAboveOriginal reports whether a deployment, or the minReplicas of the
+// HPA scaling it, is above its original replicas
+func scaledAboveOriginal(deployment *appsv1.Deployment, hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	if hpa != nil {
+		originalMin, _ := utils.GetOriginalHPALimits(hpa)
+		return hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas > originalMin
+	}
+	return deployment.Spec.Replicas != nil && *deployment.Spec.Replicas > utils.GetOriginalReplicas(deployment)
+}
+
+// protectionExpired records that the protection window of a deployment still
+// scaled up ended, so it is not protected again until it is scaled back
+const protectionExpired = "expired"
+
+// protectionState returns the next protection state of a deployment from the
+// recorded one: empty without protection, the RFC3339 end of the window, or
+// protectionExpired. The window starts the first time the override is seen
+// scaling the deployment up. protect tells whether its pods must be protected.
+func protectionState(recorded string, duration time.Duration, enabled, scaledUp bool, now time.Time) (state string, protect bool) {
+	if !enabled || !scaledUp {
+		return "", false
+	}
+	if recorded == protectionExpired {
+		return protectionExpired, false
+	}
+	until, err := time.Parse(time.RFC3339, recorded)
+	if err != nil {
+		until = now.Add(duration)
+	}
+	if !now.Before(until) {
+		return protectionExpired, false
+	}
+	return until.Format(time.RFC3339), true
+}
+
+// protectFromScaleDown marks the pods of a deployment scaled up by an override
+// with clusterAutoscalerProtection as not safe to evict until its window ends,
+// and unmarks them afterwards or once the deployment is scaled back. It
+// returns when the protection must be lifted, zero when there is none.
+func (r *ReplicasOverrideReconciler) protectFromScaleDown(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, now time.Time) time.Time {
+	recorded := deployment.Annotations[utils.ScaleDownProtectedUntilAnnotation]
+	enabled := override != nil && override.Spec.ClusterAutoscalerProtection != nil
+	if recorded == "" && !enabled {
+		return time.Time{}
+	}
+	log := log.FromContext(ctx).WithValues("deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+
+	var hpa *autoscalingv2.HorizontalPodAutoscaler
+	if deployment.Annotations[utils.ManagementModeAnnotation] == "hpa" {
+		hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := r.List(ctx, hpas, client.InNamespace(deployment.Namespace)); err != nil {
+			log.Error(err, "Failed to list HPAs for cluster-autoscaler protection")
+			return time.Time{}
+		}
+		for i := range hpas.Items {
+			if scalesDeployment(&hpas.Items[i]) && hpas.Items[i].Spec.ScaleTargetRef.Name == deployment.Name {
+				hpa = &hpas.Items[i]
+				break
+			}
+		}
+	}
+
+	var duration time.Duration
+	if enabled {
+		duration = override.Spec.ClusterAutoscalerProtection.Duration.Duration
+	}
+	state, protect := protectionState(recorded, duration, enabled, scaledAboveOriginal(deployment, hpa), now)
+	if state == recorded && !protect && (state == "" || state == protectionExpired) {
+		return time.Time{}
+	}
+
+	// Pods are protected again on every pass, covering those created since
+	if protect || recorded != protectionExpired {
+		if err := r.markSafeToEvict(ctx, deployment, !protect); err != nil {
+			log.Error(err, "Failed to update the cluster-autoscaler protection of pods")
+			return time.Time{}
+		}
+	}
+	if state != recorded {
+		if err := client.IgnoreNotFound(r.updateDeploymentAnnotations(ctx, deployment, map[string]string{utils.ScaleDownProtectedUntilAnnotation: state})); err != nil {
+			log.Error(err, "Failed to record the cluster-autoscaler protection window")
+		}
+	}
+	if !protect {
+		return time.Time{}
+	}
+	until, _ := time.Parse(time.RFC3339, state)
+	return until
+}
+
+// markSafeToEvict protects the pods of a deployment from cluster-autoscaler,
+// or lifts the protection from the pods it marked. Pods annotated by their
+// owners are left alone.
+func (r *ReplicasOverrideReconciler) markSafeToEvict(ctx context.Context, deployment *appsv1.Deployment, safe bool) error {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		marked := pod.Annotations[utils.ScaleDownProtectedAnnotation] == "true"
+		if pod.DeletionTimestamp != nil || (safe && !marked) || (!safe && (marked || pod.Annotations[safeToEvictAnnotation] != "")) {
+			continue
+		}
+		patch := client.MergeFrom(pod.DeepCopy())
+		if safe {
+			delete(pod.Annotations, safeToEvictAnnotation)
+			delete(pod.Annotations, utils.ScaleDownProtectedAnnotation)
+		} else {
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[safeToEvictAnnotation] = "false"
+			pod.Annotations[utils.ScaleDownProtectedAnnotation] = "true"
+		}
+		if err := client.IgnoreNotFound(r.Patch(ctx, pod, patch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
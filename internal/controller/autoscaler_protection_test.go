@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestClusterAutoscalerProtection(t *testing.T) {
+	labels := map[string]string{"app": "checkout"}
+	pod := func(name string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels, Annotations: annotations}}
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", Annotations: map[string]string{utils.OriginalReplicasAnnotation: "2"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(6), Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}
+	c := fakeclient.NewBuilder(
+		deployment,
+		pod("checkout-a", nil),
+		pod("checkout-b", map[string]string{safeToEvictAnnotation: "true"}),
+	).Build()
+	r := newTestReconciler(c, nil)
+	override := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{
+		ClusterAutoscalerProtection: &dynamicscalingv1.ClusterAutoscalerProtection{Duration: metav1.Duration{Duration: time.Hour}},
+	}}
+	ctx := context.Background()
+	get := func(obj client.Object, name string) client.Object {
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, obj); err != nil {
+			t.Fatal(err)
+		}
+		return obj
+	}
+
+	now := time.Date(2025, 11, 28, 8, 0, 0, 0, time.UTC)
+	if until := r.protectFromScaleDown(ctx, override, deployment, now); !until.Equal(now.Add(time.Hour)) {
+		t.Fatalf("protectFromScaleDown() = %v, want the end of the window", until)
+	}
+	if a := get(&corev1.Pod{}, "checkout-a").GetAnnotations(); a[safeToEvictAnnotation] != "false" || a[utils.ScaleDownProtectedAnnotation] != "true" {
+		t.Errorf("checkout-a annotations = %v, want it protected", a)
+	}
+	if b := get(&corev1.Pod{}, "checkout-b").GetAnnotations(); b[safeToEvictAnnotation] != "true" {
+		t.Errorf("checkout-b annotations = %v, want the annotation of its owner kept", b)
+	}
+
+	// Once the window ends the pods are released, and a deployment still scaled up is not protected again
+	deployment = get(&appsv1.Deployment{}, "checkout").(*appsv1.Deployment)
+	if until := r.protectFromScaleDown(ctx, override, deployment, now.Add(2*time.Hour)); !until.IsZero() {
+		t.Errorf("protectFromScaleDown() after the window = %v, want zero", until)
+	}
+	if a := get(&corev1.Pod{}, "checkout-a").GetAnnotations(); a[safeToEvictAnnotation] != "" || a[utils.ScaleDownProtectedAnnotation] != "" {
+		t.Errorf("checkout-a annotations = %v, want the protection lifted", a)
+	}
+	deployment = get(&appsv1.Deployment{}, "checkout").(*appsv1.Deployment)
+	if state := deployment.Annotations[utils.ScaleDownProtectedUntilAnnotation]; state != protectionExpired {
+		t.Errorf("protection state = %q, want %q", state, protectionExpired)
+	}
+
+	// Scaling back resets the state for the next scale-up
+	deployment.Spec.Replicas = int32Ptr(2)
+	r.protectFromScaleDown(ctx, override, deployment, now.Add(3*time.Hour))
+	if state := get(&appsv1.Deployment{}, "checkout").GetAnnotations()[utils.ScaleDownProtectedUntilAnnotation]; state != "" {
+		t.Errorf("protection state after scaling back = %q, want none", state)
+	}
+}
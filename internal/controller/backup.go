@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// deploymentBackup returns the entry of the override status backing up the
+// original replicas of a deployment, or nil
+func deploymentBackup(override *dynamicscalingv1.ReplicasOverride, namespace, name string) *dynamicscalingv1.AffectedDeployment {
+	if override == nil {
+		return nil
+	}
+	for i := range override.Status.AffectedDeployments {
+		affected := &override.Status.AffectedDeployments[i]
+		if affected.Namespace == namespace && affected.Name == name {
+			return affected
+		}
+	}
+	return nil
+}
+
+// hpaBackup returns the entry of the override status backing up the original
+// limits of an HPA, or nil
+func hpaBackup(override *dynamicscalingv1.ReplicasOverride, hpa *autoscalingv2.HorizontalPodAutoscaler) *dynamicscalingv1.AffectedDeployment {
+	if override == nil {
+		return nil
+	}
+	for i := range override.Status.AffectedDeployments {
+		affected := &override.Status.AffectedDeployments[i]
+		if affected.Namespace == hpa.Namespace && affected.HPAName == hpa.Name &&
+			affected.OriginalHPAMinReplicas != nil && affected.OriginalHPAMaxReplicas != nil {
+			return affected
+		}
+	}
+	return nil
+}
+
+// originalHPALimits returns the original limits of an HPA from its
+// annotations, or from the backup in the override status if they were deleted
+func originalHPALimits(override *dynamicscalingv1.ReplicasOverride, hpa *autoscalingv2.HorizontalPodAutoscaler) (int32, int32) {
+	originalMin, originalMax := utils.GetOriginalHPALimits(hpa)
+	if backup := hpaBackup(override, hpa); backup != nil {
+		if _, exists := hpa.Annotations[utils.OriginalMinReplicasAnnotation]; !exists {
+			originalMin = *backup.OriginalHPAMinReplicas
+		}
+		if _, exists := hpa.Annotations[utils.OriginalMaxReplicasAnnotation]; !exists {
+			originalMax = *backup.OriginalHPAMaxReplicas
+		}
+	}
+	return originalMin, originalMax
+}
+
+// backupHPAOriginals copies the original limits recorded on the HPA scaling
+// the deployment into its entry of the override status
+func (r *ReplicasOverrideReconciler) backupHPAOriginals(ctx context.Context, affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler) {
+	for i := range hpas {
+		if !scalesDeployment(&hpas[i]) || hpas[i].Spec.ScaleTargetRef.Name != deployment.Name {
+			continue
+		}
+
+		// The listed HPA predates this reconcile, which may have recorded its originals
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		if err := r.Get(ctx, types.NamespacedName{Name: hpas[i].Name, Namespace: hpas[i].Namespace}, hpa); err != nil {
+			return
+		}
+		affected.HPAName = hpa.Name
+		if value, ok := utils.ParseReplicas(hpa.Annotations[utils.OriginalMinReplicasAnnotation]); ok {
+			affected.OriginalHPAMinReplicas = &value
+		}
+		if value, ok := utils.ParseReplicas(hpa.Annotations[utils.OriginalMaxReplicasAnnotation]); ok {
+			affected.OriginalHPAMaxReplicas = &value
+		}
+		return
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestOriginalHPALimits(t *testing.T) {
+	minReplicas, maxReplicas := int32(2), int32(10)
+	override := &dynamicscalingv1.ReplicasOverride{
+		Status: dynamicscalingv1.ReplicasOverrideStatus{
+			AffectedDeployments: []dynamicscalingv1.AffectedDeployment{{
+				Name: "web", Namespace: "shop", OriginalReplicas: 3,
+				HPAName: "web-hpa", OriginalHPAMinReplicas: &minReplicas, OriginalHPAMaxReplicas: &maxReplicas,
+			}},
+		},
+	}
+	scaledMin := int32(4)
+	hpa := func(annotations map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-hpa", Namespace: "shop", Annotations: annotations},
+			Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: &scaledMin, MaxReplicas: 20},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		override *dynamicscalingv1.ReplicasOverride
+		hpa      *autoscalingv2.HorizontalPodAutoscaler
+		wantMin  int32
+		wantMax  int32
+	}{
+		{name: "annotations win", override: override, hpa: hpa(map[string]string{utils.OriginalMinReplicasAnnotation: "1", utils.OriginalMaxReplicasAnnotation: "5"}), wantMin: 1, wantMax: 5},
+		{name: "deleted annotations come from the status", override: override, hpa: hpa(nil), wantMin: 2, wantMax: 10},
+		{name: "one deleted annotation", override: override, hpa: hpa(map[string]string{utils.OriginalMinReplicasAnnotation: "1"}), wantMin: 1, wantMax: 10},
+		{name: "no backup", override: &dynamicscalingv1.ReplicasOverride{}, hpa: hpa(nil), wantMin: 4, wantMax: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMin, gotMax := originalHPALimits(tt.override, tt.hpa)
+			if gotMin != tt.wantMin || gotMax != tt.wantMax {
+				t.Errorf("originalHPALimits() = (%d, %d), want (%d, %d)", gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+
+	if backup := deploymentBackup(override, "shop", "web"); backup == nil || backup.OriginalReplicas != 3 {
+		t.Errorf("deploymentBackup() = %v, want the entry of shop/web", backup)
+	}
+	if backup := deploymentBackup(override, "billing", "web"); backup != nil {
+		t.Errorf("deploymentBackup() = %v for another namespace, want nil", backup)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// baselineField is a spec field of a target, its current value and the
+// annotation holding its original value
+type baselineField struct {
+	field      string
+	annotation string
+	value      int32
+}
+
+// baselineRefresh returns the baseline refresh policy of the targets of an
+// override, the one of the global config if the override sets none, and the
+// minimum age of a baseline refreshed periodically
+func baselineRefresh(cfg *config.GlobalConfig, override *dynamicscalingv1.ReplicasOverride) (dynamicscalingv1.BaselineRefreshPolicy, time.Duration) {
+	if override != nil && override.Spec.BaselineRefresh != nil {
+		refresh := config.BaselineRefreshConfig{IntervalDays: override.Spec.BaselineRefresh.IntervalDays}
+		return override.Spec.BaselineRefresh.Policy, refresh.GetInterval()
+	}
+	if cfg == nil {
+		return dynamicscalingv1.BaselineRefreshNever, 0
+	}
+	return dynamicscalingv1.BaselineRefreshPolicy(cfg.BaselineRefresh.Policy), cfg.BaselineRefresh.GetInterval()
+}
+
+// ownedSpecFields returns the fields among fields that a field manager other
+// than the controller wrote in the spec of obj after since
+func ownedSpecFields(obj metav1.Object, since time.Time, fields []string) map[string]bool {
+	owned := make(map[string]bool)
+	for _, entry := range obj.GetManagedFields() {
+		if utils.IsControllerFieldManager(entry.Manager) || entry.FieldsV1 == nil {
+			continue
+		}
+		if entry.Time == nil || !entry.Time.After(since) {
+			continue
+		}
+		var set map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &set); err != nil {
+			continue
+		}
+		for _, field := range fields {
+			if _, ok := set["f:spec"]["f:"+field]; ok {
+				owned[field] = true
+			}
+		}
+	}
+	return owned
+}
+
+// refreshBaseline re-captures the original value of the fields of obj that
+// another field manager changed since the baseline was recorded, as allowed
+// by the policy, and returns the refreshed fields and whether the annotations
+// of obj changed. Baselines recorded before a policy was set only start
+// their clock, the controller may have written their fields under another
+// field manager name.
+func refreshBaseline(policy dynamicscalingv1.BaselineRefreshPolicy, interval time.Duration, obj metav1.Object, now time.Time, fields ...baselineField) ([]string, bool) {
+	if policy != dynamicscalingv1.BaselineRefreshOnSpecChange && policy != dynamicscalingv1.BaselineRefreshPeriodic {
+		return nil, false
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	stamp := now.UTC().Format(time.RFC3339)
+	recordedAt, err := time.Parse(time.RFC3339, annotations[utils.BaselineRecordedAnnotation])
+	if err != nil {
+		annotations[utils.BaselineRecordedAnnotation] = stamp
+		obj.SetAnnotations(annotations)
+		return nil, true
+	}
+	if policy == dynamicscalingv1.BaselineRefreshPeriodic && now.Sub(recordedAt) < interval {
+		return nil, false
+	}
+
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.field)
+	}
+	owned := ownedSpecFields(obj, recordedAt, names)
+	var refreshed []string
+	for _, f := range fields {
+		if owned[f.field] && annotations[f.annotation] != strconv.FormatInt(int64(f.value), 10) {
+			annotations[f.annotation] = strconv.FormatInt(int64(f.value), 10)
+			refreshed = append(refreshed, f.field)
+		}
+	}
+	if len(owned) == 0 {
+		return nil, false
+	}
+	// A change keeping the baseline still restarts the clock of the Periodic policy
+	annotations[utils.BaselineRecordedAnnotation] = stamp
+	obj.SetAnnotations(annotations)
+	return refreshed, true
+}
+
+// refreshTargetBaseline applies the baseline refresh policy of the override
+// to a target and logs the originals it re-captured
+func (r *ReplicasOverrideReconciler) refreshTargetBaseline(ctx context.Context, kind string, obj metav1.Object, override *dynamicscalingv1.ReplicasOverride, fields ...baselineField) bool {
+	policy, interval := baselineRefresh(r.configFor(ctx, obj.GetNamespace()), override)
+	refreshed, changed := refreshBaseline(policy, interval, obj, time.Now(), fields...)
+	if len(refreshed) > 0 {
+		log.FromContext(ctx).Info("Refreshed the baseline changed by another field manager",
+			"kind", kind,
+			"target", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()),
+			"fields", refreshed,
+			"policy", policy)
+	}
+	return changed
+}
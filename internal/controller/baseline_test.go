@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestRefreshBaseline(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	recorded := now.Add(-48 * time.Hour)
+	replicasFields := metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)}
+	imageFields := metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{}}}`)}
+	entry := func(manager string, at time.Time, fields metav1.FieldsV1) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{Manager: manager, Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: at}, FieldsV1: &fields}
+	}
+
+	tests := []struct {
+		name          string
+		policy        dynamicscalingv1.BaselineRefreshPolicy
+		recordedAt    string
+		managedFields []metav1.ManagedFieldsEntry
+		wantOriginal  string
+		wantChanged   bool
+	}{
+		{name: "never", policy: dynamicscalingv1.BaselineRefreshNever, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("argocd-controller", now.Add(-time.Hour), replicasFields)}, wantOriginal: "3"},
+		{name: "resized by another manager", policy: dynamicscalingv1.BaselineRefreshOnSpecChange, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("argocd-controller", now.Add(-time.Hour), replicasFields)}, wantOriginal: "5", wantChanged: true},
+		{name: "written by the controller", policy: dynamicscalingv1.BaselineRefreshOnSpecChange, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry(utils.FieldManager, now.Add(-time.Hour), replicasFields)}, wantOriginal: "3"},
+		{name: "written by the controller for an override", policy: dynamicscalingv1.BaselineRefreshOnSpecChange, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry(utils.ChangeFieldManager(metrics.TriggerOverride, "sale"), now.Add(-time.Hour), replicasFields)}, wantOriginal: "3"},
+		{name: "other field changed", policy: dynamicscalingv1.BaselineRefreshOnSpecChange, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("argocd-controller", now.Add(-time.Hour), imageFields)}, wantOriginal: "3"},
+		{name: "changed before the baseline", policy: dynamicscalingv1.BaselineRefreshOnSpecChange, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("kubectl", recorded.Add(-time.Hour), replicasFields)}, wantOriginal: "3"},
+		{name: "no recorded baseline starts the clock", policy: dynamicscalingv1.BaselineRefreshOnSpecChange,
+			managedFields: []metav1.ManagedFieldsEntry{entry("manager", now.Add(-time.Hour), replicasFields)}, wantOriginal: "3", wantChanged: true},
+		{name: "periodic baseline too recent", policy: dynamicscalingv1.BaselineRefreshPeriodic, recordedAt: recorded.Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("kubectl", now.Add(-time.Hour), replicasFields)}, wantOriginal: "3"},
+		{name: "periodic baseline old enough", policy: dynamicscalingv1.BaselineRefreshPeriodic, recordedAt: now.Add(-31 * 24 * time.Hour).Format(time.RFC3339),
+			managedFields: []metav1.ManagedFieldsEntry{entry("kubectl", now.Add(-time.Hour), replicasFields)}, wantOriginal: "5", wantChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{utils.OriginalReplicasAnnotation: "3"}
+			if tt.recordedAt != "" {
+				annotations[utils.BaselineRecordedAnnotation] = tt.recordedAt
+			}
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: annotations, ManagedFields: tt.managedFields}}
+
+			_, changed := refreshBaseline(tt.policy, config.DefaultBaselineRefreshInterval, deployment, now,
+				baselineField{field: "replicas", annotation: utils.OriginalReplicasAnnotation, value: 5})
+			if got := deployment.Annotations[utils.OriginalReplicasAnnotation]; got != tt.wantOriginal || changed != tt.wantChanged {
+				t.Errorf("refreshBaseline() = (original %s, changed %v), want (%s, %v)", got, changed, tt.wantOriginal, tt.wantChanged)
+			}
+			if changed && deployment.Annotations[utils.BaselineRecordedAnnotation] != now.Format(time.RFC3339) {
+				t.Errorf("baseline recorded at %s, want %s", deployment.Annotations[utils.BaselineRecordedAnnotation], now.Format(time.RFC3339))
+			}
+		})
+	}
+
+	override := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{
+		BaselineRefresh: &dynamicscalingv1.BaselineRefresh{Policy: dynamicscalingv1.BaselineRefreshPeriodic, IntervalDays: 7},
+	}}
+	cfg := &config.GlobalConfig{BaselineRefresh: config.BaselineRefreshConfig{Policy: config.BaselineRefreshOnSpecChange}}
+	if policy, interval := baselineRefresh(cfg, override); policy != dynamicscalingv1.BaselineRefreshPeriodic || interval != 7*24*time.Hour {
+		t.Errorf("baselineRefresh() = (%s, %s), want the policy of the override", policy, interval)
+	}
+	if policy, _ := baselineRefresh(cfg, nil); policy != dynamicscalingv1.BaselineRefreshOnSpecChange {
+		t.Errorf("baselineRefresh() = %s, want the policy of the global config", policy)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// scalingActiveCondition returns the ScalingActive condition of an HPA, nil
+// before the HPA controller reported it
+func scalingActiveCondition(hpa *autoscalingv2.HorizontalPodAutoscaler) *autoscalingv2.HorizontalPodAutoscalerCondition {
+	for i := range hpa.Status.Conditions {
+		if hpa.Status.Conditions[i].Type == autoscalingv2.ScalingActive {
+			return &hpa.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// brokenHPA returns a description of the HPA of a deployment while direct
+// mode manages the deployment instead, or "" to keep scaling the HPA limits.
+// current is the description recorded when direct mode took over: it takes
+// over once the HPA has been failing for takeOverAfter, and hands the
+// deployment back once the HPA has been scaling again for handBackAfter.
+func brokenHPA(cfg *config.GlobalConfig, hpa *autoscalingv2.HorizontalPodAutoscaler, current string, now time.Time) string {
+	if cfg == nil || hpa == nil || cfg.BrokenHPAs.GetMode() != config.BrokenHPAModeDirect {
+		return ""
+	}
+	condition := scalingActiveCondition(hpa)
+	if condition == nil {
+		return ""
+	}
+	since := now.Sub(condition.LastTransitionTime.Time)
+
+	switch condition.Status {
+	case corev1.ConditionFalse:
+		if current != "" || since >= cfg.BrokenHPAs.TakeOverAfter {
+			return fmt.Sprintf("%s (%s: %s)", hpa.Name, condition.Reason, condition.Message)
+		}
+	case corev1.ConditionTrue:
+		if current != "" && since < cfg.BrokenHPAs.HandBackAfter {
+			return current
+		}
+	}
+	return ""
+}
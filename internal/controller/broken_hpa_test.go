@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestBrokenHPA(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		mode         string
+		inactive     bool
+		wantReplicas int32
+		wantMax      int32
+		wantBroken   bool
+	}{
+		{name: "defer to a broken HPA by default", inactive: true, wantReplicas: 2, wantMax: 20},
+		{name: "scale directly while the HPA is broken", mode: config.BrokenHPAModeDirect, inactive: true, wantReplicas: 4, wantMax: 10, wantBroken: true},
+		{name: "scale a working HPA", mode: config.BrokenHPAModeDirect, wantReplicas: 2, wantMax: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas, minReplicas := int32(2), int32(1)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+					MinReplicas:    &minReplicas,
+					MaxReplicas:    10,
+				},
+			}
+			if tt.inactive {
+				hpa.Status.Conditions = []autoscalingv2.HorizontalPodAutoscalerCondition{{
+					Type:    autoscalingv2.ScalingActive,
+					Status:  corev1.ConditionFalse,
+					Reason:  "FailedGetResourceMetric",
+					Message: "the server could not find the requested resource (get pods.metrics.k8s.io)",
+				}}
+			}
+			override := &dynamicscalingv1.ReplicasOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+				Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 200},
+			}
+			c := fakeclient.NewBuilder(deployment, hpa).Build()
+			cfg := config.DefaultConfig()
+			cfg.BrokenHPAs.Mode = tt.mode
+			r := newTestReconciler(c, cfg)
+
+			if err := r.processDeployment(ctx, deployment, override); err != nil {
+				t.Fatalf("processDeployment() failed: %v", err)
+			}
+			gotDeployment := &appsv1.Deployment{}
+			gotHPA := &autoscalingv2.HorizontalPodAutoscaler{}
+			if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, gotDeployment); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, gotHPA); err != nil {
+				t.Fatal(err)
+			}
+			if *gotDeployment.Spec.Replicas != tt.wantReplicas || gotHPA.Spec.MaxReplicas != tt.wantMax {
+				t.Errorf("got %d replicas and HPA max %d, want %d and %d", *gotDeployment.Spec.Replicas, gotHPA.Spec.MaxReplicas, tt.wantReplicas, tt.wantMax)
+			}
+			broken := gotDeployment.Annotations[utils.BrokenHPAAnnotation]
+			if (broken != "") != tt.wantBroken || tt.wantBroken && !strings.Contains(broken, "FailedGetResourceMetric") {
+				t.Errorf("%s annotation = %q, want it set: %v", utils.BrokenHPAAnnotation, broken, tt.wantBroken)
+			}
+		})
+	}
+}
+
+func TestBrokenHPATakeOver(t *testing.T) {
+	now := time.Now()
+	cfg := config.DefaultConfig()
+	cfg.BrokenHPAs = config.BrokenHPAConfig{Mode: config.BrokenHPAModeDirect, TakeOverAfter: 10 * time.Minute, HandBackAfter: 5 * time.Minute}
+	hpa := func(status corev1.ConditionStatus, since time.Duration) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{{
+				Type:               autoscalingv2.ScalingActive,
+				Status:             status,
+				Reason:             "FailedGetResourceMetric",
+				LastTransitionTime: metav1.NewTime(now.Add(-since)),
+			}}},
+		}
+	}
+	const current = "web (FailedGetResourceMetric: no metrics)"
+
+	tests := []struct {
+		name     string
+		hpa      *autoscalingv2.HorizontalPodAutoscaler
+		current  string
+		wantTake bool
+	}{
+		{name: "failing for less than takeOverAfter", hpa: hpa(corev1.ConditionFalse, 5*time.Minute)},
+		{name: "failing for longer than takeOverAfter", hpa: hpa(corev1.ConditionFalse, 15*time.Minute), wantTake: true},
+		{name: "still failing after the take over", hpa: hpa(corev1.ConditionFalse, time.Minute), current: current, wantTake: true},
+		{name: "recovered for less than handBackAfter", hpa: hpa(corev1.ConditionTrue, time.Minute), current: current, wantTake: true},
+		{name: "recovered for longer than handBackAfter", hpa: hpa(corev1.ConditionTrue, 10*time.Minute), current: current},
+		{name: "working HPA", hpa: hpa(corev1.ConditionTrue, time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := brokenHPA(cfg, tt.hpa, tt.current, now); (got != "") != tt.wantTake {
+				t.Errorf("brokenHPA() = %q, want a take over: %v", got, tt.wantTake)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+const (
+	// flaggerPrimarySuffix is appended by Flagger to the name of the target
+	// deployment to name the deployment serving stable traffic
+	flaggerPrimarySuffix = "-primary"
+
+	// flaggerPhaseProgressing is the phase of a Flagger Canary running its analysis
+	flaggerPhaseProgressing = "Progressing"
+)
+
+// canaryListGVK is the Flagger Canary list, handled as unstructured so Flagger is not a build dependency
+var canaryListGVK = schema.GroupVersionKind{Group: "flagger.app", Version: "v1beta1", Kind: "CanaryList"}
+
+// canaryRole is the side of a canary under analysis a deployment is on
+type canaryRole struct {
+	// target is the name of the deployment referenced by the Canary
+	target string
+	scope  dynamicscalingv1.CanaryScope
+}
+
+// +kubebuilder:rbac:groups=flagger.app,resources=canaries,verbs=get;list;watch
+
+// analyzingCanaries returns the role of the deployments of the Flagger canaries
+// of a namespace running their analysis, by deployment name. It is empty when
+// Flagger is not installed.
+func (r *ReplicasOverrideReconciler) analyzingCanaries(ctx context.Context, namespace string) map[string]canaryRole {
+	canaries := &unstructured.UnstructuredList{}
+	canaries.SetGroupVersionKind(canaryListGVK)
+	if err := r.List(ctx, canaries, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+	return canaryRoles(canaries.Items)
+}
+
+// canaryRoles maps the deployments of the canaries running their analysis to their role
+func canaryRoles(canaries []unstructured.Unstructured) map[string]canaryRole {
+	roles := make(map[string]canaryRole)
+	for _, canary := range canaries {
+		phase, _, _ := unstructured.NestedString(canary.Object, "status", "phase")
+		kind, _, _ := unstructured.NestedString(canary.Object, "spec", "targetRef", "kind")
+		name, _, _ := unstructured.NestedString(canary.Object, "spec", "targetRef", "name")
+		if phase != flaggerPhaseProgressing || kind != "Deployment" || name == "" {
+			continue
+		}
+		roles[name] = canaryRole{target: name, scope: dynamicscalingv1.CanaryScopeCanary}
+		roles[name+flaggerPrimarySuffix] = canaryRole{target: name, scope: dynamicscalingv1.CanaryScopeStable}
+	}
+	return roles
+}
+
+// canaryOverrides returns the canary-scoped overrides applying to a deployment
+// on one side of a canary under analysis. They select the canary target
+// deployment, also when the deployment is its primary.
+func canaryOverrides(overrides []dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, deployments []appsv1.Deployment, roles map[string]canaryRole) []*dynamicscalingv1.ReplicasOverride {
+	role, ok := roles[deployment.Name]
+	if !ok {
+		return nil
+	}
+	target := deployment
+	if role.target != deployment.Name {
+		target = nil
+		for i := range deployments {
+			if deployments[i].Name == role.target {
+				target = &deployments[i]
+				break
+			}
+		}
+		if target == nil {
+			return nil
+		}
+	}
+
+	var matches []*dynamicscalingv1.ReplicasOverride
+	for i := range overrides {
+		if overrides[i].Spec.CanaryScope == role.scope && shouldProcessDeployment(target, &overrides[i]) {
+			matches = append(matches, &overrides[i])
+		}
+	}
+	return matches
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestCanaryOverrides(t *testing.T) {
+	canary := func(name, phase string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]any{
+			"spec":   map[string]any{"targetRef": map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "name": name}},
+			"status": map[string]any{"phase": phase},
+		}}
+	}
+	roles := canaryRoles([]unstructured.Unstructured{canary("web", "Progressing"), canary("api", "Succeeded")})
+	if len(roles) != 2 || roles["web-primary"].scope != dynamicscalingv1.CanaryScopeStable || roles["web"].scope != dynamicscalingv1.CanaryScopeCanary {
+		t.Fatalf("canaryRoles() = %v, want both sides of the canary under analysis only", roles)
+	}
+
+	deployments := []appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-primary", Namespace: "shop", Labels: map[string]string{"app": "web-primary"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"app": "api"}}},
+	}
+	scoped := func(name string, scope dynamicscalingv1.CanaryScope) dynamicscalingv1.ReplicasOverride {
+		return dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:    &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"app": "web"}},
+				CanaryScope: scope,
+			},
+		}
+	}
+	overrides := []dynamicscalingv1.ReplicasOverride{
+		scoped("stable", dynamicscalingv1.CanaryScopeStable),
+		scoped("canary", dynamicscalingv1.CanaryScopeCanary),
+		scoped("whole", ""),
+	}
+	names := func(matches []*dynamicscalingv1.ReplicasOverride) []string {
+		var names []string
+		for _, o := range matches {
+			names = append(names, o.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		deployment *appsv1.Deployment
+		want       []string
+	}{
+		{deployment: &deployments[0], want: []string{"canary"}},
+		{deployment: &deployments[1], want: []string{"stable"}},
+		{deployment: &deployments[2]},
+	}
+	for _, tt := range tests {
+		if got := names(canaryOverrides(overrides, tt.deployment, deployments, roles)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("canaryOverrides(%s) = %v, want %v", tt.deployment.Name, got, tt.want)
+		}
+	}
+	if got := names(deploymentOverrides(overrides, nil, &deployments[0])); !reflect.DeepEqual(got, []string{"whole"}) {
+		t.Errorf("deploymentOverrides() = %v, want canary-scoped overrides left out", got)
+	}
+	if got := canaryOverrides(overrides, &deployments[1], deployments, nil); got != nil {
+		t.Errorf("canaryOverrides() without analysis = %v, want none", names(got))
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// changeReason is why a write changed the replicas or limits of a target. It
+// is stored in the change-reason annotation of the target and in the field
+// manager of the write, so the audit log entry of a replica change names the
+// override and trigger behind it without the controller logs.
+type changeReason struct {
+	Override string `json:"override"`
+	Trigger  string `json:"trigger"`
+	// Percentage is the computed percentage, unset when the write restores
+	// recorded replicas rather than applying a percentage
+	Percentage *int32 `json:"percentage,omitempty"`
+}
+
+// newChangeReason returns the reason of a write from its metrics labels
+func newChangeReason(labels metrics.ScalingLabels, percentage *int32) changeReason {
+	override := labels.Override
+	if override == "" {
+		override = metrics.GlobalOverride
+	}
+	return changeReason{Override: override, Trigger: labels.Trigger, Percentage: percentage}
+}
+
+// record stores the reason in the annotations of obj and returns the field
+// owner option of the write carrying it
+func (c changeReason) record(obj client.Object) client.FieldOwner {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	data, _ := json.Marshal(c)
+	annotations[utils.ChangeReasonAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return client.FieldOwner(utils.ChangeFieldManager(c.Trigger, c.Override))
+}
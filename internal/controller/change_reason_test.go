@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestChangeReason(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 150},
+	}
+	c := fakeclient.NewBuilder(deployment).Build()
+	r := newTestReconciler(c, nil)
+
+	if err := r.processDeployment(ctx, deployment, override); err != nil {
+		t.Fatalf("processDeployment() failed: %v", err)
+	}
+	got := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, got); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"override":"sale","trigger":"override","percentage":150}`; got.Annotations[utils.ChangeReasonAnnotation] != want {
+		t.Errorf("%s annotation = %s, want %s", utils.ChangeReasonAnnotation, got.Annotations[utils.ChangeReasonAnnotation], want)
+	}
+
+	// Restores carry no percentage, and the field manager names the reason
+	restore := &appsv1.Deployment{}
+	owner := newChangeReason(metrics.ScalingLabels{Trigger: metrics.TriggerRollback}, nil).record(restore)
+	if owner != "kubedynamicscaler/rollback/global" {
+		t.Errorf("field owner = %s, want kubedynamicscaler/rollback/global", owner)
+	}
+	if want := `{"override":"global","trigger":"rollback"}`; restore.Annotations[utils.ChangeReasonAnnotation] != want {
+		t.Errorf("%s annotation = %s, want %s", utils.ChangeReasonAnnotation, restore.Annotations[utils.ChangeReasonAnnotation], want)
+	}
+	if !utils.IsControllerFieldManager(string(owner)) || utils.IsControllerFieldManager("kubedynamicscaler-other") {
+		t.Error("IsControllerFieldManager() must match the change field managers only")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// CheckpointName is the ConfigMap the leader records what it last applied in
+	CheckpointName = "kubedynamicscaler-checkpoint"
+
+	// checkpointKey is the key of the ConfigMap holding the checkpoint
+	checkpointKey = "checkpoint.json"
+)
+
+// checkpointState is what a reconcile pass applies: the generations of the
+// overrides, ignore rules and namespace defaults, and a hash of the config
+type checkpointState struct {
+	Overrides          map[string]int64 `json:"overrides,omitempty"`
+	Ignores            map[string]int64 `json:"ignores,omitempty"`
+	Defaults           map[string]int64 `json:"defaults,omitempty"`
+	NamespaceOverrides map[string]int64 `json:"namespaceOverrides,omitempty"`
+	Config             string           `json:"config"`
+}
+
+// Checkpoint records the state the leader last applied in a ConfigMap of the
+// controller namespace. A new leader whose state matches it trusts the
+// previous leader for Window after taking over, instead of re-applying every
+// target once per event of its cold cache. Changes made during the window are
+// still applied, the targets themselves are checked again once it ends.
+type Checkpoint struct {
+	client    client.Client
+	namespace string
+	window    time.Duration
+
+	mu sync.Mutex
+	// until is the end of the handoff window, zero before the first pass
+	until time.Time
+	// trusted is true if the checkpoint matched when leadership was taken
+	trusted bool
+	// written is the state last recorded
+	written string
+}
+
+// NewCheckpoint returns a checkpoint kept in the CheckpointName ConfigMap of namespace
+func NewCheckpoint(c client.Client, namespace string, window time.Duration) *Checkpoint {
+	return &Checkpoint{client: c, namespace: namespace, window: window}
+}
+
+// handoff returns true if the pass applying state can be skipped because the
+// previous leader checkpointed it and the handoff window has not ended, and
+// when the window ends. The checkpoint is read on the first pass of a leader.
+func (c *Checkpoint) handoff(ctx context.Context, state string, now time.Time) (bool, time.Time) {
+	if c == nil {
+		return false, time.Time{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.until.IsZero() {
+		c.until = now.Add(c.window)
+		checkpoint := &corev1.ConfigMap{}
+		err := c.client.Get(ctx, types.NamespacedName{Name: CheckpointName, Namespace: c.namespace}, checkpoint)
+		if err != nil && client.IgnoreNotFound(err) != nil {
+			log.FromContext(ctx).Error(err, "Failed to read the checkpoint of the previous leader")
+		}
+		recorded := checkpoint.Data[checkpointKey]
+		c.trusted = recorded != "" && recorded == state
+		c.written = recorded
+		if c.trusted {
+			log.FromContext(ctx).Info("Checkpoint of the previous leader matches, skipping the cold start",
+				"until", c.until)
+		}
+	}
+	if !c.trusted || state != c.written || !now.Before(c.until) {
+		return false, time.Time{}
+	}
+	return true, c.until
+}
+
+// save records state once a pass applied it, when it changed
+func (c *Checkpoint) save(ctx context.Context, state string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if state == c.written {
+		return
+	}
+
+	checkpoint := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: CheckpointName, Namespace: c.namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c.client, checkpoint, func() error {
+		if checkpoint.Labels == nil {
+			checkpoint.Labels = make(map[string]string)
+		}
+		checkpoint.Labels["app.kubernetes.io/managed-by"] = "kubedynamicscaler"
+		checkpoint.Data = map[string]string{checkpointKey: state}
+		return nil
+	}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to save the checkpoint")
+		return
+	}
+	c.written = state
+}
+
+// checkpointState returns the serialized state a pass started now applies
+func (r *ReplicasOverrideReconciler) checkpointState(ctx context.Context) (string, error) {
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrides); err != nil {
+		return "", err
+	}
+	ignores := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := r.List(ctx, ignores); err != nil {
+		return "", err
+	}
+	defaults := &dynamicscalingv1.NamespaceScalingDefaultList{}
+	if err := r.List(ctx, defaults); err != nil {
+		return "", err
+	}
+	namespaceOverrides := &dynamicscalingv1.NamespaceReplicasOverrideList{}
+	if err := r.List(ctx, namespaceOverrides); err != nil {
+		return "", err
+	}
+
+	state := checkpointState{
+		Overrides:          make(map[string]int64, len(overrides.Items)),
+		Ignores:            make(map[string]int64, len(ignores.Items)),
+		Defaults:           make(map[string]int64, len(defaults.Items)),
+		NamespaceOverrides: make(map[string]int64, len(namespaceOverrides.Items)),
+	}
+	for _, o := range overrides.Items {
+		state.Overrides[o.Namespace+"/"+o.Name] = o.Generation
+	}
+	for _, ignore := range ignores.Items {
+		state.Ignores[ignore.Name] = ignore.Generation
+	}
+	for _, def := range defaults.Items {
+		state.Defaults[def.Namespace+"/"+def.Name] = def.Generation
+	}
+	for _, o := range namespaceOverrides.Items {
+		state.NamespaceOverrides[o.Namespace+"/"+o.Name] = o.Generation
+	}
+	state.Config = configHash(r.Config.GetConfig())
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// configHash returns a hash of the global config
+func configHash(cfg *config.GlobalConfig) string {
+	data, _ := json.Marshal(cfg)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop", Generation: 1},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 150},
+	}
+	c := fakeclient.NewBuilder(override).Build()
+	r := newTestReconciler(c, nil)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	state, err := r.checkpointState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := NewCheckpoint(c, "kds", time.Minute)
+	if skip, _ := first.handoff(ctx, state, now); skip {
+		t.Fatal("the first leader has no checkpoint to trust")
+	}
+	first.save(ctx, state)
+
+	// The next leader skips its cold start while nothing changed
+	next := NewCheckpoint(c, "kds", time.Minute)
+	if skip, until := next.handoff(ctx, state, now.Add(time.Hour)); !skip || !until.Equal(now.Add(time.Hour+time.Minute)) {
+		t.Errorf("handoff() = %v, %v, want to skip until the end of the window", skip, until)
+	}
+	if skip, _ := next.handoff(ctx, state, now.Add(time.Hour+2*time.Minute)); skip {
+		t.Error("handoff() must not skip passes once the window ended")
+	}
+
+	override.Generation = 2
+	r.Client = fakeclient.NewBuilder(override).Build()
+	changed, err := r.checkpointState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == state {
+		t.Fatal("checkpointState() must change with the generation of an override")
+	}
+	if skip, _ := NewCheckpoint(c, "kds", time.Minute).handoff(ctx, changed, now); skip {
+		t.Error("handoff() must not skip a pass applying a change")
+	}
+}
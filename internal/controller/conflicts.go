@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// ConflictConditionType is the ReplicasOverride condition reporting whether
+// it targets workloads along with other overrides
+const ConflictConditionType = "OverrideConflict"
+
+// deploymentOverrides returns every override of overrides targeting the
+// deployment, in the order they are evaluated: by deploymentRef or selector
+// first, then by the hpaRef of an HPA scaling it, each the oldest first. The
+// first one is applied. Canary-scoped overrides are matched by canaryOverrides
+// instead.
+func deploymentOverrides(overrides []dynamicscalingv1.ReplicasOverride, hpas []autoscalingv2.HorizontalPodAutoscaler, deployment *appsv1.Deployment) []*dynamicscalingv1.ReplicasOverride {
+	var matches, byHPA []*dynamicscalingv1.ReplicasOverride
+	for i := range overrides {
+		if overrides[i].Spec.CanaryScope == "" && shouldProcessDeployment(deployment, &overrides[i]) {
+			matches = append(matches, &overrides[i])
+		}
+	}
+	for i := range hpas {
+		hpa := &hpas[i]
+		if !scalesDeployment(hpa) || hpa.Spec.ScaleTargetRef.Name != deployment.Name {
+			continue
+		}
+		for j := range overrides {
+			o := &overrides[j]
+			if o.Spec.CanaryScope == "" && o.Spec.HPARef != nil && hpaRefKey(o) == client.ObjectKeyFromObject(hpa) {
+				byHPA = append(byHPA, o)
+			}
+		}
+	}
+	oldestFirst(deployment.Namespace, matches)
+	oldestFirst(deployment.Namespace, byHPA)
+	return append(matches, byHPA...)
+}
+
+// oldestFirst sorts the overrides of namespace before those granted from
+// other namespaces, then by creation time and name, so the same override wins
+// whatever the order of the cache
+func oldestFirst(namespace string, overrides []*dynamicscalingv1.ReplicasOverride) {
+	sort.SliceStable(overrides, func(i, j int) bool {
+		a, b := overrides[i], overrides[j]
+		if (a.Namespace == namespace) != (b.Namespace == namespace) {
+			return a.Namespace == namespace
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+}
+
+// findConflicts returns the deployments of a namespace targeted by more than
+// one override. Ignored deployments are skipped.
+func findConflicts(overrides []dynamicscalingv1.ReplicasOverride, hpas []autoscalingv2.HorizontalPodAutoscaler, deployments []appsv1.Deployment, ignored map[string]bool) []dynamicscalingv1.ScalingConflict {
+	var conflicts []dynamicscalingv1.ScalingConflict
+	for i := range deployments {
+		deployment := &deployments[i]
+		if ignored[deployment.Namespace+"/"+deployment.Name] {
+			continue
+		}
+		matches := deploymentOverrides(overrides, hpas, deployment)
+		if len(matches) < 2 {
+			continue
+		}
+		conflict := dynamicscalingv1.ScalingConflict{
+			Kind:      "Deployment",
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Winner:    matches[0].Name,
+		}
+		for _, o := range matches {
+			conflict.Overrides = append(conflict.Overrides, o.Name)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts
+}
+
+// conflictsOf returns the conflicts override is involved in
+func conflictsOf(override *dynamicscalingv1.ReplicasOverride, conflicts []dynamicscalingv1.ScalingConflict) []dynamicscalingv1.ScalingConflict {
+	var involved []dynamicscalingv1.ScalingConflict
+	for _, conflict := range conflicts {
+		for _, name := range conflict.Overrides {
+			if name == override.Name {
+				involved = append(involved, conflict)
+				break
+			}
+		}
+	}
+	return involved
+}
+
+// conflictCondition summarizes the conflicts of override
+func conflictCondition(override *dynamicscalingv1.ReplicasOverride, conflicts []dynamicscalingv1.ScalingConflict) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ConflictConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NoConflict",
+		Message:            "No workload is targeted by another override",
+		ObservedGeneration: override.Generation,
+	}
+	if len(conflicts) == 0 {
+		return condition
+	}
+
+	var won, lost []string
+	for _, conflict := range conflicts {
+		target := fmt.Sprintf("%s %s/%s", conflict.Kind, conflict.Namespace, conflict.Name)
+		if conflict.Winner == override.Name {
+			won = append(won, target)
+		} else {
+			lost = append(lost, fmt.Sprintf("%s (applied: %s)", target, conflict.Winner))
+		}
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "OverlappingTargets"
+	if len(lost) > 0 {
+		condition.Reason = "OverriddenByOther"
+	}
+	var parts []string
+	if len(won) > 0 {
+		parts = append(parts, "applied over other overrides to "+strings.Join(won, ", "))
+	}
+	if len(lost) > 0 {
+		parts = append(parts, "not applied to "+strings.Join(lost, ", "))
+	}
+	condition.Message = fmt.Sprintf("%d workloads targeted by other overrides: %s", len(conflicts), strings.Join(parts, "; "))
+	return condition
+}
+
+// reportConflicts records in the status of every override of a namespace the
+// workloads it shares with other overrides, and which override won them
+func (r *ReplicasOverrideReconciler) reportConflicts(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride, conflicts []dynamicscalingv1.ScalingConflict) {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		involved := conflictsOf(override, conflicts)
+		changed := meta.SetStatusCondition(&override.Status.Conditions, conflictCondition(override, involved))
+		if !equality.Semantic.DeepEqual(override.Status.Conflicts, involved) {
+			override.Status.Conflicts = involved
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+
+		if len(involved) > 0 {
+			log.Info("Override targets workloads along with other overrides",
+				"override", override.Name,
+				"namespace", override.Namespace,
+				"conflicts", len(involved))
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestDeploymentOverrides(t *testing.T) {
+	hpa := func(name, kind, target string) autoscalingv2.HorizontalPodAutoscaler {
+		return autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: kind, Name: target},
+			},
+		}
+	}
+	hpas := []autoscalingv2.HorizontalPodAutoscaler{hpa("web-hpa", "Deployment", "web"), hpa("db-hpa", "StatefulSet", "web")}
+	overrides := []dynamicscalingv1.ReplicasOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "by-hpa", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{HPARef: &dynamicscalingv1.HPAReference{Name: "web-hpa", Namespace: "shop"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{HPARef: &dynamicscalingv1.HPAReference{Name: "db-hpa"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "by-ref", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "by-team", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{Selector: &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "web"}}}},
+	}
+	deployment := func(name string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels}}
+	}
+
+	names := func(matches []*dynamicscalingv1.ReplicasOverride) []string {
+		var got []string
+		for _, o := range matches {
+			got = append(got, o.Name)
+		}
+		return got
+	}
+	if got := names(deploymentOverrides(overrides, hpas, deployment("web", map[string]string{"team": "web"}))); !reflect.DeepEqual(got, []string{"by-ref", "by-team", "by-hpa"}) {
+		t.Errorf("deploymentOverrides() = %v, want deploymentRef and selector matches before hpaRef ones", got)
+	}
+	if got := names(deploymentOverrides(overrides[:2], hpas, deployment("web", nil))); !reflect.DeepEqual(got, []string{"by-hpa"}) {
+		t.Errorf("deploymentOverrides() = %v, want only the override referencing the HPA of the deployment", got)
+	}
+	if got := deploymentOverrides(overrides[1:2], hpas, deployment("web", nil)); len(got) != 0 {
+		t.Errorf("deploymentOverrides() = %v, want none when only the HPA of a StatefulSet is referenced", names(got))
+	}
+}
+
+func TestFindConflicts(t *testing.T) {
+	overrides := []dynamicscalingv1.ReplicasOverride{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "shop"}, Spec: dynamicscalingv1.ReplicasOverrideSpec{Selector: &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "web"}}}},
+	}
+	deployments := []appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"team": "web"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"team": "web"}}},
+	}
+
+	conflicts := findConflicts(overrides, nil, deployments, nil)
+	if len(conflicts) != 1 || conflicts[0].Name != "web" || conflicts[0].Winner != "sale" ||
+		!reflect.DeepEqual(conflicts[0].Overrides, []string{"sale", "team"}) {
+		t.Fatalf("findConflicts() = %+v, want web won by sale over team", conflicts)
+	}
+	if ignored := findConflicts(overrides, nil, deployments, map[string]bool{"shop/web": true}); len(ignored) != 0 {
+		t.Errorf("findConflicts() = %+v, want ignored deployments skipped", ignored)
+	}
+
+	winner := conflictCondition(&overrides[0], conflictsOf(&overrides[0], conflicts))
+	loser := conflictCondition(&overrides[1], conflictsOf(&overrides[1], conflicts))
+	if winner.Status != metav1.ConditionTrue || winner.Reason != "OverlappingTargets" {
+		t.Errorf("winner condition = (%s, %s), want (True, OverlappingTargets)", winner.Status, winner.Reason)
+	}
+	if loser.Status != metav1.ConditionTrue || loser.Reason != "OverriddenByOther" {
+		t.Errorf("loser condition = (%s, %s), want (True, OverriddenByOther)", loser.Status, loser.Reason)
+	}
+	if none := conflictCondition(&overrides[0], nil); none.Status != metav1.ConditionFalse {
+		t.Errorf("condition without conflicts = %s, want False", none.Status)
+	}
+
+	// The oldest override wins whatever the order of the cache, then the first by name
+	created := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	reordered := []dynamicscalingv1.ReplicasOverride{*overrides[1].DeepCopy(), *overrides[0].DeepCopy()}
+	reordered[0].CreationTimestamp = metav1.NewTime(created.Add(time.Hour))
+	reordered[1].CreationTimestamp = metav1.NewTime(created)
+	if got := findConflicts(reordered, nil, deployments[:1], nil); len(got) != 1 || got[0].Winner != "sale" {
+		t.Errorf("findConflicts() = %+v, want web won by the oldest override sale", got)
+	}
+	reordered[0].CreationTimestamp = reordered[1].CreationTimestamp
+	if got := findConflicts(reordered, nil, deployments[:1], nil); len(got) != 1 || got[0].Winner != "sale" ||
+		!reflect.DeepEqual(got[0].Overrides, []string{"sale", "team"}) {
+		t.Errorf("findConflicts() = %+v, want web won by sale, first by name among overrides created together", got)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/expression"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// DefaultDecisionChain returns the stages deciding the percentage and replicas
+// of a workload: schedule, trigger, carbon, pressure, then for workloads
+// scaled directly boost, replicas, ratio, policy-clamp and quota-clamp.
+// Constraints are added by inserting stages into it and setting it as
+// Decisions.
+func (r *ReplicasOverrideReconciler) DefaultDecisionChain() *decision.Chain {
+	return decision.NewChain(
+		decision.Stage{Name: decision.StageSchedule, Decide: r.scheduleStage},
+		decision.Stage{Name: decision.StageTrigger, Decide: r.triggerStage},
+		decision.Stage{Name: decision.StageCarbon, Decide: r.carbonStage},
+		decision.Stage{Name: decision.StagePressure, Decide: r.pressureStage},
+		decision.Stage{Name: decision.StageBoost, Direct: true, Decide: r.boostStage},
+		decision.Stage{Name: decision.StageReplicas, Direct: true, Decide: replicasStage},
+		decision.Stage{Name: decision.StageRatio, Direct: true, Decide: r.ratioStage},
+		decision.Stage{Name: decision.StagePolicyClamp, Direct: true, Decide: policyClampStage},
+		decision.Stage{Name: decision.StageQuotaClamp, Direct: true, Decide: r.quotaClampStage},
+	)
+}
+
+// decisionChain returns Decisions, or the default chain when unset
+func (r *ReplicasOverrideReconciler) decisionChain() *decision.Chain {
+	if r.Decisions != nil {
+		return r.Decisions
+	}
+	return r.DefaultDecisionChain()
+}
+
+// scheduleStage resolves the global config, namespace default and its active
+// schedule, override and workload annotation, in increasing order of precedence
+func (r *ReplicasOverrideReconciler) scheduleStage(ctx context.Context, in *decision.Input, e *precedence.Explanation) {
+	*e = precedence.Resolve(r.precedenceRules(ctx, in.Workload, in.Override)...)
+}
+
+// triggerStage applies the triggers and the percentage expression of the
+// override. They refine the override, they never beat the workload annotation.
+func (r *ReplicasOverrideReconciler) triggerStage(ctx context.Context, in *decision.Input, e *precedence.Explanation) {
+	override := in.Override
+	if override == nil || e.Layer != precedence.LayerOverride {
+		return
+	}
+	base := e.Percentage
+
+	// External signals mapped to the override replace its percentage while active
+	if triggered, triggerType, active := r.Triggers.Percentage(override.Namespace, override.Name); active {
+		e.Adjust(fmt.Sprintf("trigger %s", triggerType), triggerType, triggered)
+	}
+
+	// A percentage expression takes precedence, it can read the trigger values itself.
+	// One that does not compile is reported by the PercentageExpressionValid condition.
+	if override.Spec.PercentageExpression != "" && !expressionInvalid(override) {
+		evaluated, err := expression.Evaluate(override.Spec.PercentageExpression, expression.Variables{
+			Now:         time.Now(),
+			Percentage:  base,
+			Namespace:   in.Workload.GetNamespace(),
+			Name:        in.Workload.GetName(),
+			Labels:      in.Workload.GetLabels(),
+			Annotations: in.Workload.GetAnnotations(),
+			Triggers:    r.Triggers.Values(override.Namespace, override.Name),
+		})
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Falling back to replicasPercentage",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		} else {
+			e.Adjust("percentageExpression", metrics.TriggerExpression, evaluated)
+		}
+	}
+}
+
+// carbonStage lowers flexible workloads while grid carbon intensity is high
+func (r *ReplicasOverrideReconciler) carbonStage(_ context.Context, in *decision.Input, e *precedence.Explanation) {
+	if adjusted, lowered := r.Carbon.AdjustPercentage(in.Workload.GetLabels(), e.Percentage); lowered {
+		e.Adjust("carbon intensity", metrics.TriggerCarbon, adjusted)
+	}
+}
+
+// pressureStage sheds low-priority tiers while nodes are under pressure
+func (r *ReplicasOverrideReconciler) pressureStage(ctx context.Context, in *decision.Input, e *precedence.Explanation) {
+	if adjusted, shed := r.Pressure.AdjustPercentage(ctx, in.Workload.GetLabels(), in.Template.Spec.PriorityClassName, e.Percentage); shed {
+		e.Adjust("node pressure", metrics.TriggerNodePressure, adjusted)
+	}
+}
+
+// boostStage adds the node disruption and warm-up boosts. HPAs apply them to
+// their min and max separately.
+func (r *ReplicasOverrideReconciler) boostStage(_ context.Context, in *decision.Input, e *precedence.Explanation) {
+	if boost := r.disruptionBoost(in.Config, in.Workload); boost > 0 {
+		e.Adjust("node disruption boost", metrics.TriggerNodeDisruption, utils.AddBounded(e.Percentage, boost))
+	}
+	if boost := warmUpBoost(in.Override, in.Workload, time.Now()); boost > 0 {
+		e.Adjust("warm-up after rollout", metrics.TriggerWarmUp, utils.AddBounded(e.Percentage, boost))
+	}
+}
+
+// replicasStage applies the percentage to the original replicas and adds the
+// headroom of the override. Bounds overrides keep the current replicas.
+func replicasStage(_ context.Context, in *decision.Input, e *precedence.Explanation) {
+	if enforcesBounds(in.Override) {
+		e.SetReplicas(in.Original, false)
+		return
+	}
+	desired := utils.PercentOf(in.Original, e.Percentage)
+	if headroom := headroomReplicas(in.Override); headroom > 0 {
+		desired = utils.AddBounded(desired, headroom)
+		e.Headroom = headroom
+	}
+	e.SetReplicas(desired, false)
+}
+
+// policyClampStage bounds the replicas to the min/max replicas of the config,
+// or of a bounds override
+func policyClampStage(_ context.Context, in *decision.Input, e *precedence.Explanation) {
+	if e.Replicas == nil {
+		return
+	}
+	minReplicas, maxReplicas := in.Config.MinReplicas, in.Config.MaxReplicas
+	if enforcesBounds(in.Override) {
+		if in.Override.Spec.MinReplicas != nil {
+			minReplicas = *in.Override.Spec.MinReplicas
+		}
+		if in.Override.Spec.MaxReplicas != nil {
+			maxReplicas = *in.Override.Spec.MaxReplicas
+		}
+	}
+	desired, clamped := *e.Replicas, e.Clamped
+	if desired < minReplicas {
+		desired, clamped = minReplicas, true
+	}
+	if desired > maxReplicas {
+		desired, clamped = maxReplicas, true
+	}
+	e.SetReplicas(desired, clamped)
+}
+
+// quotaClampStage caps a scale-up at the pods the ResourceQuotas of the
+// namespace still admit, instead of a target whose extra pods the API server
+// rejects. Scale-downs and workloads without a pod template are left alone.
+func (r *ReplicasOverrideReconciler) quotaClampStage(ctx context.Context, in *decision.Input, e *precedence.Explanation) {
+	if e.Replicas == nil || *e.Replicas <= in.Current || in.Template == nil || len(in.Template.Spec.Containers) == 0 {
+		return
+	}
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(in.Workload.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list resource quotas", "namespace", in.Workload.GetNamespace())
+		return
+	}
+	if admitted, limited := podsAdmitted(quotas.Items, &in.Template.Spec); limited && *e.Replicas-in.Current > admitted {
+		e.SetReplicas(in.Current+admitted, true)
+	}
+}
+
+// decisionStages returns the stages recorded in the explain annotation of a
+// deployment whose replicas are scaled directly, for the override status
+func decisionStages(deployment *appsv1.Deployment) []dynamicscalingv1.DecisionStage {
+	if deployment.Annotations[utils.ManagementModeAnnotation] != "direct" {
+		return nil
+	}
+	var explanation precedence.Explanation
+	if err := json.Unmarshal([]byte(deployment.Annotations[utils.ExplainAnnotation]), &explanation); err != nil {
+		return nil
+	}
+	var stages []dynamicscalingv1.DecisionStage
+	for _, result := range explanation.Stages {
+		stages = append(stages, dynamicscalingv1.DecisionStage{
+			Stage:      result.Stage,
+			Percentage: result.Percentage,
+			Replicas:   result.Replicas,
+		})
+	}
+	return stages
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestDecisionChain(t *testing.T) {
+	r := newTestReconciler(fakeclient.NewBuilder().Build(), nil)
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 300},
+	}
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+
+	// A team cap inserted after the policy clamp bounds the replicas further
+	chain := r.DefaultDecisionChain()
+	if err := chain.InsertAfter(decision.StagePolicyClamp, decision.Stage{Name: "team-cap", Direct: true,
+		Decide: func(_ context.Context, _ *decision.Input, e *precedence.Explanation) {
+			if *e.Replicas > 10 {
+				e.SetReplicas(10, true)
+			}
+		}}); err != nil {
+		t.Fatalf("InsertAfter() failed: %v", err)
+	}
+	r.Decisions = chain
+
+	replicas, explanation := r.desiredReplicas(context.Background(), config.DefaultConfig(), workload, &workload.Spec.Template, override, 4, 4)
+	if replicas != 10 || !explanation.Clamped {
+		t.Errorf("desiredReplicas() = (%d, clamped %v), want (10, clamped)", replicas, explanation.Clamped)
+	}
+	var stages []string
+	for _, result := range explanation.Stages {
+		stages = append(stages, result.Stage)
+	}
+	if want := []string{decision.StageSchedule, decision.StageReplicas, "team-cap"}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("desiredReplicas() recorded stages %v, want %v", stages, want)
+	}
+
+	deployment := workload.DeepCopy()
+	deployment.Annotations = map[string]string{
+		utils.ManagementModeAnnotation: "direct",
+		utils.ExplainAnnotation:        explanation.JSON(),
+	}
+	if got := decisionStages(deployment); len(got) != 3 || got[2].Stage != "team-cap" || *got[2].Replicas != 10 {
+		t.Errorf("decisionStages() = %+v, want the 3 stages of the explanation", got)
+	}
+}
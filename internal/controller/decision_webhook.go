@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/authorizer"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// errScalingDenied is returned when the decision webhook denies a replica change
+var errScalingDenied = failure.New(failure.Forbidden, "replica change denied by the decision webhook")
+
+// changeDeferred returns true if err only defers a replica change to a later
+// pass, because the scale budget is exhausted, the decision webhook denied it
+// or its Rolling override changed its targets of the minute
+func changeDeferred(err error) bool {
+	return errors.Is(err, errScaleBudgetExceeded) || errors.Is(err, errScalingDenied) || errors.Is(err, errRollingPaced)
+}
+
+// authorizeChange submits the change of a target from previous to target
+// replicas to the decision webhook of the global config, if any. It returns
+// the replicas to apply, modified by the webhook, or errScalingDenied.
+func (r *ReplicasOverrideReconciler) authorizeChange(ctx context.Context, labels metrics.ScalingLabels, name string, previous, target int32) (int32, error) {
+	cfg := r.Config.GetConfig()
+	if cfg == nil || !cfg.DecisionWebhook.Enabled() || r.Authorizer == nil {
+		return target, nil
+	}
+	log := log.FromContext(ctx)
+
+	override := labels.Override
+	if override == "" {
+		override = metrics.GlobalOverride
+	}
+	decision := r.Authorizer.Authorize(ctx, cfg.DecisionWebhook, authorizer.Request{
+		Kind:             labels.TargetKind,
+		Namespace:        labels.Namespace,
+		Name:             name,
+		Override:         override,
+		Trigger:          labels.Trigger,
+		CurrentReplicas:  previous,
+		ProposedReplicas: target,
+	})
+	metrics.RecordDecision(labels, decision.Verdict)
+
+	keysAndValues := []interface{}{
+		"kind", labels.TargetKind,
+		"target", fmt.Sprintf("%s/%s", labels.Namespace, name),
+		"previous", previous,
+		"proposed", target,
+		"reason", decision.Reason,
+	}
+	switch {
+	case decision.Verdict == authorizer.VerdictError:
+		log.Error(fmt.Errorf("%s", decision.Reason), "Decision webhook failed",
+			append(keysAndValues, "applied", decision.Allowed)...)
+	case !decision.Allowed:
+		log.Info("Decision webhook denied replica change", keysAndValues...)
+	case decision.Verdict == authorizer.VerdictModified:
+		log.Info("Decision webhook modified replica change", append(keysAndValues, "replicas", decision.Replicas)...)
+	}
+	if !decision.Allowed {
+		return previous, errScalingDenied
+	}
+	return decision.Replicas, nil
+}
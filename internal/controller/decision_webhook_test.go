@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/authorizer"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestDecisionWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name     string
+		response string
+		want     int32
+	}{
+		{name: "allowed", response: `{"allowed": true}`, want: 6},
+		{name: "modified", response: `{"allowed": true, "replicas": 5, "reason": "namespace quota"}`, want: 5},
+		{name: "denied", response: `{"allowed": false, "reason": "change freeze"}`, want: 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var submitted authorizer.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&submitted)
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+			}
+			override := &dynamicscalingv1.ReplicasOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop"},
+				Spec: dynamicscalingv1.ReplicasOverrideSpec{
+					DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+					OverrideType:       "override",
+					ReplicasPercentage: 200,
+				},
+			}
+			c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+				Build()
+			cfg := config.DefaultConfig()
+			cfg.DecisionWebhook.URL = server.URL
+			r := newTestReconciler(c, cfg)
+			r.Authorizer = authorizer.New()
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+				t.Fatalf("Reconcile() failed: %v", err)
+			}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+				t.Fatal(err)
+			}
+			if *deployment.Spec.Replicas != tt.want {
+				t.Errorf("replicas = %d, want %d", *deployment.Spec.Replicas, tt.want)
+			}
+			want := authorizer.Request{Kind: "Deployment", Namespace: "shop", Name: "api", Override: "api-sale", Trigger: "override", CurrentReplicas: 3, ProposedReplicas: 6}
+			if submitted != want {
+				t.Errorf("submitted %+v, want %+v", submitted, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// podDeletionCostAnnotation is the pod annotation ReplicaSets read to pick
+	// the pods removed by a scale-down, lowest cost first
+	podDeletionCostAnnotation = "controller.kubernetes.io/pod-deletion-cost"
+
+	// drainDeletionCost is the deletion cost given to the pods being drained
+	drainDeletionCost = "-2147483648"
+)
+
+// errDownscaleDraining is returned by processDeployment while the pods a
+// scale-down removes are given their preDownscaleDelay to drain
+var errDownscaleDraining = errors.New("scale-down held while pods drain")
+
+// drainNotice is the body of the request sent to the drain webhook
+type drainNotice struct {
+	Override        string   `json:"override"`
+	Namespace       string   `json:"namespace"`
+	Deployment      string   `json:"deployment"`
+	CurrentReplicas int32    `json:"currentReplicas"`
+	TargetReplicas  int32    `json:"targetReplicas"`
+	Pods            []string `json:"pods"`
+	DrainUntil      string   `json:"drainUntil"`
+}
+
+// parseDrain returns the target replicas and end of a recorded drain period
+func parseDrain(recorded string) (int32, time.Time, bool) {
+	replicas, end, found := strings.Cut(recorded, "@")
+	if !found {
+		return 0, time.Time{}, false
+	}
+	target, ok := utils.ParseReplicas(replicas)
+	if !ok {
+		return 0, time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return target, until, true
+}
+
+// drainUntil returns the end of the drain period of a deployment, zero without one
+func drainUntil(deployment *appsv1.Deployment) time.Time {
+	_, until, _ := parseDrain(deployment.Annotations[utils.DownscaleDrainAnnotation])
+	return until
+}
+
+// drainBeforeScaleDown holds a scale-down of a deployment to target replicas
+// until the pods it removes had the preDownscaleDelay of the override to
+// drain, and traffic shifted away by its trafficShift settled. It returns true
+// while the scale-down must wait, and whether the drain annotation of the
+// deployment changed and must be written.
+func (r *ReplicasOverrideReconciler) drainBeforeScaleDown(ctx context.Context, deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride, target int32, now time.Time) (bool, bool, error) {
+	recorded := deployment.Annotations[utils.DownscaleDrainAnnotation]
+	var current int32
+	if deployment.Spec.Replicas != nil {
+		current = *deployment.Spec.Replicas
+	}
+	if override == nil || (override.Spec.PreDownscaleDelay == nil && override.Spec.TrafficShift == nil) || target >= current {
+		if recorded == "" {
+			return false, false, nil
+		}
+		// The scale-down was cancelled, the pods keep serving
+		if err := r.markDraining(ctx, deployment, nil, ""); err != nil {
+			return false, false, err
+		}
+		delete(deployment.Annotations, utils.DownscaleDrainAnnotation)
+		return false, true, nil
+	}
+
+	if drained, until, ok := parseDrain(recorded); ok && drained == target {
+		if now.Before(until) {
+			return true, false, nil
+		}
+		delete(deployment.Annotations, utils.DownscaleDrainAnnotation)
+		return false, true, nil
+	}
+
+	// Start the drain period, again if the target changed meanwhile
+	delay, shift := override.Spec.PreDownscaleDelay, override.Spec.TrafficShift
+	if delay != nil && delay.Webhook != nil {
+		if err := r.Config.GetConfig().URLAllowed(delay.Webhook.URL); err != nil {
+			return false, false, failure.Wrap(failure.InvalidConfig, fmt.Errorf("drain webhook is refused: %w", err))
+		}
+	}
+	var wait time.Duration
+	if delay != nil {
+		wait = delay.Duration.Duration
+	}
+	if shift != nil {
+		wait = max(wait, trafficSettleDuration(shift))
+		if _, err := r.shiftTraffic(ctx, override, deployment, target); err != nil {
+			return false, false, err
+		}
+	}
+	until := now.Add(wait).UTC()
+	var pods []corev1.Pod
+	if delay != nil {
+		var err error
+		if pods, err = r.podsToRemove(ctx, deployment, current-target); err != nil {
+			return false, false, err
+		}
+		if err := r.markDraining(ctx, deployment, pods, until.Format(time.RFC3339)); err != nil {
+			return false, false, err
+		}
+	}
+	if delay != nil && delay.Webhook != nil {
+		notice := drainNotice{
+			Override:        override.Name,
+			Namespace:       deployment.Namespace,
+			Deployment:      deployment.Name,
+			CurrentReplicas: current,
+			TargetReplicas:  target,
+			Pods:            []string{},
+			DrainUntil:      until.Format(time.RFC3339),
+		}
+		for _, pod := range pods {
+			notice.Pods = append(notice.Pods, pod.Name)
+		}
+		if err := sendDrainNotice(ctx, delay.Webhook, notice); err != nil {
+			return false, false, err
+		}
+	}
+	log.FromContext(ctx).Info("Draining pods before scaling down",
+		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+		"target", target,
+		"pods", len(pods),
+		"until", until)
+	deployment.Annotations[utils.DownscaleDrainAnnotation] = formatDrain(target, until)
+	return true, true, nil
+}
+
+// podsToRemove returns the count pods of a deployment a scale-down removes
+// first: those not ready, then the newest, the order ReplicaSets use
+func (r *ReplicasOverrideReconciler) podsToRemove(ctx context.Context, deployment *appsv1.Deployment, count int32) ([]corev1.Pod, error) {
+	pods, err := r.deploymentPods(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		if ready := podReady(&pods[i]); ready != podReady(&pods[j]) {
+			return !ready
+		}
+		if !pods[i].CreationTimestamp.Equal(&pods[j].CreationTimestamp) {
+			return pods[j].CreationTimestamp.Before(&pods[i].CreationTimestamp)
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	if int(count) < len(pods) {
+		pods = pods[:count]
+	}
+	return pods, nil
+}
+
+// deploymentPods returns the pods of a deployment that are not terminating
+func (r *ReplicasOverrideReconciler) deploymentPods(ctx context.Context, deployment *appsv1.Deployment) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &corev1.PodList{}
+	if err := r.List(ctx, list, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	pods := list.Items[:0]
+	for _, pod := range list.Items {
+		if pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// podReady returns true if the Ready condition of the pod is True
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// markDraining annotates pods with the end of their drain period and the
+// lowest deletion cost, and unmarks the other pods of the deployment marked
+// before. A deletion cost set by the owners of a pod is left alone.
+func (r *ReplicasOverrideReconciler) markDraining(ctx context.Context, deployment *appsv1.Deployment, draining []corev1.Pod, until string) error {
+	marked := make(map[string]bool, len(draining))
+	for _, pod := range draining {
+		marked[pod.Name] = true
+	}
+	pods, err := r.deploymentPods(ctx, deployment)
+	if err != nil {
+		return err
+	}
+	for i := range pods {
+		pod := &pods[i]
+		patch := client.MergeFrom(pod.DeepCopy())
+		if marked[pod.Name] {
+			if pod.Annotations == nil {
+				pod.Annotations = make(map[string]string)
+			}
+			pod.Annotations[utils.DrainUntilAnnotation] = until
+			if _, exists := pod.Annotations[podDeletionCostAnnotation]; !exists {
+				pod.Annotations[podDeletionCostAnnotation] = drainDeletionCost
+			}
+		} else if _, exists := pod.Annotations[utils.DrainUntilAnnotation]; exists {
+			delete(pod.Annotations, utils.DrainUntilAnnotation)
+			if pod.Annotations[podDeletionCostAnnotation] == drainDeletionCost {
+				delete(pod.Annotations, podDeletionCostAnnotation)
+			}
+		} else {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Patch(ctx, pod, patch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendDrainNotice posts the pods about to be removed to the drain webhook,
+// without following redirects
+func sendDrainNotice(ctx context.Context, webhook *dynamicscalingv1.DrainWebhook, notice drainNotice) error {
+	timeout := defaultProbeTimeout
+	if webhook.TimeoutSeconds > 0 {
+		timeout = time.Duration(webhook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("drain webhook %s is invalid: %w", webhook.URL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := config.NewHTTPClient(0).Do(req)
+	if err != nil {
+		return fmt.Errorf("drain webhook %s failed: %w", webhook.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("drain webhook %s returned %s", webhook.URL, resp.Status)
+	}
+	return nil
+}
+
+// formatDrain records the target replicas and end of a drain period
+func formatDrain(target int32, until time.Time) string {
+	return strconv.FormatInt(int64(target), 10) + "@" + until.UTC().Format(time.RFC3339)
+}
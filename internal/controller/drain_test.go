@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestPreDownscaleDelay(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(4)
+
+	var notice drainNotice
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&notice); err != nil {
+			t.Errorf("decoding drain notice: %v", err)
+		}
+	}))
+	defer webhook.Close()
+
+	labels := map[string]string{"app": "ws-gateway"}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "realtime"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+		},
+	}
+	objects := []client.Object{deployment}
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("gateway-%d", i),
+				Namespace:         "realtime",
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Minute)),
+			},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		})
+	}
+	c := fakeclient.NewBuilder(objects...).Build()
+	cfg := config.DefaultConfig()
+	r := newTestReconciler(c, cfg)
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway-night", Namespace: "realtime"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 50,
+			PreDownscaleDelay: &dynamicscalingv1.PreDownscaleDelay{
+				Duration: metav1.Duration{Duration: 15 * time.Minute},
+				Webhook:  &dynamicscalingv1.DrainWebhook{URL: webhook.URL},
+			},
+		},
+	}
+	get := func() *appsv1.Deployment {
+		got := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "gateway", Namespace: "realtime"}, got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// The webhook is refused until allowed, before any pod is marked
+	if err := r.processDeployment(ctx, get(), override); failure.Classify(err) != failure.InvalidConfig {
+		t.Fatalf("processDeployment() outside allowedURLs = %v, want an InvalidConfig failure", err)
+	}
+	if notice.TargetReplicas != 0 || get().Annotations[utils.DownscaleDrainAnnotation] != "" {
+		t.Fatal("expected a refused webhook to be neither called nor the drain to start")
+	}
+	cfg.AllowedURLs = []string{webhook.URL}
+
+	if err := r.processDeployment(ctx, get(), override); !errors.Is(err, errDownscaleDraining) {
+		t.Fatalf("processDeployment() = %v, want %v", err, errDownscaleDraining)
+	}
+	held := get()
+	if *held.Spec.Replicas != 4 {
+		t.Errorf("replicas while draining = %d, want 4", *held.Spec.Replicas)
+	}
+	if drainUntil(held).IsZero() {
+		t.Errorf("drain annotation = %q, want the target and end of the delay", held.Annotations[utils.DownscaleDrainAnnotation])
+	}
+	if !reflect.DeepEqual(notice.Pods, []string{"gateway-3", "gateway-2"}) || notice.TargetReplicas != 2 {
+		t.Errorf("drain notice = %+v, want the two newest pods and 2 target replicas", notice)
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods); err != nil {
+		t.Fatal(err)
+	}
+	for _, pod := range pods.Items {
+		_, draining := pod.Annotations[utils.DrainUntilAnnotation]
+		if want := pod.Name == "gateway-3" || pod.Name == "gateway-2"; draining != want {
+			t.Errorf("pod %s draining = %v, want %v", pod.Name, draining, want)
+		}
+		if draining && pod.Annotations[podDeletionCostAnnotation] != drainDeletionCost {
+			t.Errorf("pod %s deletion cost = %q, want %s", pod.Name, pod.Annotations[podDeletionCostAnnotation], drainDeletionCost)
+		}
+	}
+
+	// Still draining on the next pass
+	if err := r.processDeployment(ctx, get(), override); !errors.Is(err, errDownscaleDraining) {
+		t.Fatalf("processDeployment() during the delay = %v, want %v", err, errDownscaleDraining)
+	}
+
+	// Once the delay passed the replicas are reduced
+	expired := get()
+	expired.Annotations[utils.DownscaleDrainAnnotation] = formatDrain(2, time.Now().Add(-time.Second))
+	if err := c.Update(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.processDeployment(ctx, get(), override); err != nil {
+		t.Fatalf("processDeployment() after the delay failed: %v", err)
+	}
+	scaled := get()
+	if *scaled.Spec.Replicas != 2 {
+		t.Errorf("replicas after the delay = %d, want 2", *scaled.Spec.Replicas)
+	}
+	if _, exists := scaled.Annotations[utils.DownscaleDrainAnnotation]; exists {
+		t.Error("drain annotation kept after the scale-down")
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/trigger"
+)
+
+// errorRateWindow is the rate window substituted for {{.window}} in Sloth queries
+const errorRateWindow = "2m"
+
+// prometheusServiceLevelGVK is the kind of Sloth SLO definitions
+var prometheusServiceLevelGVK = schema.GroupVersionKind{Group: "sloth.slok.dev", Version: "v1", Kind: "PrometheusServiceLevel"}
+
+// +kubebuilder:rbac:groups=sloth.slok.dev,resources=prometheusservicelevels,verbs=get
+
+// errorRateProblem returns why the error rate of a service breaches its
+// budget after a scale-down, or "" while it stays within it. SLO references
+// are resolved in namespace, the namespace of the override.
+func (r *ReplicasOverrideReconciler) errorRateProblem(ctx context.Context, namespace string, check *dynamicscalingv1.ErrorRateCheck) (string, error) {
+	cfg := r.configFor(ctx, namespace)
+	if cfg == nil || cfg.ErrorBudget.PrometheusURL == "" {
+		return "", fmt.Errorf("errorRate verification requires errorBudget.prometheusURL in the global config")
+	}
+
+	query, budget, err := r.errorRateQuery(ctx, namespace, check)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultProbeTimeout)
+	defer cancel()
+	token := config.Credential(cfg.ErrorBudget.TokenSecretRef, cfg.ErrorBudget.TokenEnv)
+	rate, err := trigger.QueryPrometheus(ctx, http.DefaultClient, cfg.ErrorBudget.PrometheusURL, token, query)
+	if err != nil {
+		return "", err
+	}
+	if rate > budget {
+		return fmt.Sprintf("Error rate %s exceeds the budget of %s",
+			strconv.FormatFloat(rate, 'g', 4, 64), strconv.FormatFloat(budget, 'g', 4, 64)), nil
+	}
+	return "", nil
+}
+
+// errorRateQuery returns the error ratio query of check and the highest tolerated ratio
+func (r *ReplicasOverrideReconciler) errorRateQuery(ctx context.Context, namespace string, check *dynamicscalingv1.ErrorRateCheck) (string, float64, error) {
+	query, budget := check.Query, -1.0
+	if check.SLORef != nil {
+		serviceLevel := &unstructured.Unstructured{}
+		serviceLevel.SetGroupVersionKind(prometheusServiceLevelGVK)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: check.SLORef.Name}, serviceLevel); err != nil {
+			return "", 0, fmt.Errorf("PrometheusServiceLevel %s: %w", check.SLORef.Name, err)
+		}
+		sloQuery, objective, err := slothQuery(serviceLevel, check.SLORef.SLO)
+		if err != nil {
+			return "", 0, fmt.Errorf("PrometheusServiceLevel %s: %w", check.SLORef.Name, err)
+		}
+		if query == "" {
+			query = sloQuery
+		}
+		budget = 1 - objective/100
+	}
+	if query == "" {
+		return "", 0, fmt.Errorf("errorRate needs a query or an sloRef")
+	}
+
+	if check.MaxErrorRate != "" {
+		value, err := strconv.ParseFloat(check.MaxErrorRate, 64)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid maxErrorRate %q", check.MaxErrorRate)
+		}
+		budget = value
+	}
+	if budget < 0 {
+		return "", 0, fmt.Errorf("errorRate needs a maxErrorRate with a query")
+	}
+	return query, budget, nil
+}
+
+// slothQuery returns the error ratio query and the objective (percent) of
+// the SLO called name of a Sloth PrometheusServiceLevel, the first one when
+// name is empty. Event queries are combined into a ratio.
+func slothQuery(serviceLevel *unstructured.Unstructured, name string) (string, float64, error) {
+	slos, _, err := unstructured.NestedSlice(serviceLevel.Object, "spec", "slos")
+	if err != nil {
+		return "", 0, err
+	}
+	for _, item := range slos {
+		slo, ok := item.(map[string]interface{})
+		if !ok || name != "" && slo["name"] != name {
+			continue
+		}
+
+		var objective float64
+		switch value := slo["objective"].(type) {
+		case float64:
+			objective = value
+		case int64:
+			objective = float64(value)
+		default:
+			return "", 0, fmt.Errorf("SLO %v has no objective", slo["name"])
+		}
+
+		query, _, _ := unstructured.NestedString(slo, "sli", "raw", "errorRatioQuery")
+		if query == "" {
+			errors, _, _ := unstructured.NestedString(slo, "sli", "events", "errorQuery")
+			total, _, _ := unstructured.NestedString(slo, "sli", "events", "totalQuery")
+			if errors == "" || total == "" {
+				return "", 0, fmt.Errorf("SLO %v has neither a raw nor an events SLI", slo["name"])
+			}
+			query = fmt.Sprintf("(%s) / (%s)", strings.TrimSpace(errors), strings.TrimSpace(total))
+		}
+		window := strings.NewReplacer("{{.window}}", errorRateWindow, "{{ .window }}", errorRateWindow)
+		return window.Replace(strings.TrimSpace(query)), objective, nil
+	}
+	if name == "" {
+		return "", 0, fmt.Errorf("no SLO defined")
+	}
+	return "", 0, fmt.Errorf("no SLO called %q", name)
+}
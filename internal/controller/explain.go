@@ -0,0 +1,315 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// precedenceRules returns the rules of every layer that applies to a workload:
+// the global config, the namespace default, the namespace override, the
+// override and the percentage annotation of the workload. Overrides are
+// namespaced, so there is no cluster-wide override layer.
+func (r *ReplicasOverrideReconciler) precedenceRules(ctx context.Context, workload metav1.Object, override *dynamicscalingv1.ReplicasOverride) []precedence.Rule {
+	cfg := r.Config.ForNamespace(workload.GetNamespace())
+	if cfg == nil {
+		return nil
+	}
+	globalSource := "global config"
+	if r.Config.NamespaceConfig(workload.GetNamespace()) != nil {
+		globalSource = fmt.Sprintf("global config with ConfigMap %s/%s", workload.GetNamespace(), config.ConfigMapName)
+	}
+	globalPercentage, minReplicas, maxReplicas := cfg.GlobalPercentage, cfg.MinReplicas, cfg.MaxReplicas
+	rules := []precedence.Rule{{
+		Layer:       precedence.LayerGlobal,
+		Source:      globalSource,
+		Trigger:     metrics.TriggerGlobal,
+		Percentage:  &globalPercentage,
+		MinReplicas: &minReplicas,
+		MaxReplicas: &maxReplicas,
+	}}
+
+	if def := r.namespaceDefault(ctx, workload.GetNamespace()); def != nil {
+		rule := precedence.Rule{
+			Layer:       precedence.LayerNamespaceDefault,
+			Source:      fmt.Sprintf("NamespaceScalingDefault %s/%s", def.Namespace, def.Name),
+			Trigger:     metrics.TriggerGlobal,
+			MinReplicas: def.Spec.MinReplicas,
+			MaxReplicas: def.Spec.MaxReplicas,
+		}
+		layered, schedule, _ := applyNamespaceDefault(cfg, r.Config.GetConfig(), def, time.Now())
+		if schedule != "" {
+			rule.Source += fmt.Sprintf(" schedule %s", schedule)
+		}
+		if def.Spec.ReplicasPercentage != nil || schedule != "" {
+			rule.Percentage = &layered.GlobalPercentage
+		}
+		rules = append(rules, rule)
+	}
+
+	if o := r.namespaceOverride(ctx, workload.GetNamespace()); o != nil {
+		percentage := o.Spec.ReplicasPercentage
+		rules = append(rules, precedence.Rule{
+			Layer:       precedence.LayerNamespaceOverride,
+			Source:      fmt.Sprintf("NamespaceReplicasOverride %s/%s", o.Namespace, o.Name),
+			Trigger:     metrics.TriggerNamespace,
+			Percentage:  &percentage,
+			MinReplicas: o.Spec.MinReplicas,
+			MaxReplicas: o.Spec.MaxReplicas,
+		})
+	}
+
+	if override != nil {
+		percentage := override.Spec.ReplicasPercentage
+		rule := precedence.Rule{
+			Layer:      precedence.LayerOverride,
+			Source:     fmt.Sprintf("ReplicasOverride %s/%s", override.Namespace, override.Name),
+			Trigger:    metrics.TriggerOverride,
+			Percentage: &percentage,
+		}
+
+		// Each environment of the override has its own percentage
+		if environment := matcher.Environment(override, workload.GetLabels()); environment != nil {
+			percentage = environment.ReplicasPercentage
+			rule.Source += fmt.Sprintf(" environment %s", environment.Value)
+		}
+
+		// The target can carry its own percentage in a label or annotation
+		if override.Spec.PercentageFrom != nil {
+			if value, found, err := percentageFromWorkload(override.Spec.PercentageFrom, workload); err != nil {
+				log.FromContext(ctx).Error(err, "Ignoring invalid workload percentage",
+					"override", override.Name,
+					"workload", fmt.Sprintf("%s/%s", workload.GetNamespace(), workload.GetName()))
+			} else if found {
+				percentage = value
+				rule.Source += " percentageFrom"
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	// The percentage annotation of the workload beats every other layer
+	source := &dynamicscalingv1.PercentageSource{AnnotationKey: utils.PercentageAnnotation}
+	if value, found, err := percentageFromWorkload(source, workload); err != nil {
+		log.FromContext(ctx).Error(err, "Ignoring invalid percentage annotation",
+			"workload", fmt.Sprintf("%s/%s", workload.GetNamespace(), workload.GetName()))
+	} else if found {
+		rules = append(rules, precedence.Rule{
+			Layer:      precedence.LayerAnnotation,
+			Source:     fmt.Sprintf("annotation %s", utils.PercentageAnnotation),
+			Trigger:    metrics.TriggerAnnotation,
+			Percentage: &value,
+		})
+	}
+	return rules
+}
+
+// updateExplanation stores the explanation in the explain annotation of a
+// workload whose replicas are already right, so the annotation still follows
+// a change of the rule that produced them
+func (r *ReplicasOverrideReconciler) updateExplanation(ctx context.Context, obj client.Object, explanation precedence.Explanation) error {
+	explain := explanation.JSON()
+	if obj.GetAnnotations()[utils.ExplainAnnotation] == explain {
+		return nil
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[utils.ExplainAnnotation] = explain
+	obj.SetAnnotations(annotations)
+	return r.Patch(ctx, obj, patch)
+}
+
+// ExplainPath is the path of the workload explanation endpoint, served by the
+// metrics server behind the same authentication and authorization
+const ExplainPath = "/explain"
+
+// ExplainedConfig is the global config applying to a workload, layered with
+// the NamespaceScalingDefault of its namespace
+type ExplainedConfig struct {
+	Source           string `json:"source"`
+	Enabled          bool   `json:"enabled"`
+	GlobalPercentage int32  `json:"globalPercentage"`
+	MinReplicas      int32  `json:"minReplicas"`
+	MaxReplicas      int32  `json:"maxReplicas"`
+
+	// NamespaceDefault is the NamespaceScalingDefault of the namespace, and
+	// Schedule its schedule active now, if any
+	NamespaceDefault string `json:"namespaceDefault,omitempty"`
+	Schedule         string `json:"schedule,omitempty"`
+}
+
+// WorkloadExplanation is the full decision of the controller for a
+// Deployment, from the rules matching it to its target replicas
+type WorkloadExplanation struct {
+	// SimulatedWorkload holds the current and target replicas, the override
+	// applied and a summary of why
+	SimulatedWorkload
+
+	// OriginalReplicas are the replicas recorded before the controller scaled the Deployment
+	OriginalReplicas int32 `json:"originalReplicas"`
+
+	// Ignores are the GlobalReplicasIgnores matching the Deployment
+	Ignores []string `json:"ignores,omitempty"`
+
+	// Overrides are the overrides of the namespace targeting the Deployment,
+	// the first one applying
+	Overrides []string `json:"overrides,omitempty"`
+
+	Config ExplainedConfig `json:"config"`
+
+	// Triggers are the latest values of the triggers mapped to the override
+	Triggers map[string]float64 `json:"triggers,omitempty"`
+
+	// Decision is the percentage with the rules and stages that produced it,
+	// and the clamps applied, unset when the Deployment is left alone
+	Decision *precedence.Explanation `json:"decision,omitempty"`
+}
+
+// Explain returns why the Deployment name of namespace has, or would be
+// scaled to, its replicas: the ignores and overrides matching it, the config,
+// the schedule and triggers in effect and each stage of the decision chain.
+// Nothing is written to the cluster.
+func (r *ReplicasOverrideReconciler) Explain(ctx context.Context, namespace, name string) (*WorkloadExplanation, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, deployment); err != nil {
+		return nil, err
+	}
+	if deployment.Spec.Replicas == nil {
+		replicas := int32(1)
+		deployment.Spec.Replicas = &replicas
+	}
+	cfg := r.configFor(ctx, namespace)
+	if cfg == nil {
+		return nil, fmt.Errorf("global config not found")
+	}
+
+	ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := r.List(ctx, ignoreList); err != nil {
+		return nil, fmt.Errorf("failed to list ignore rules: %w", err)
+	}
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HPAs: %w", err)
+	}
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list overrides: %w", err)
+	}
+
+	explained := &WorkloadExplanation{
+		OriginalReplicas: utils.GetOriginalReplicas(deployment),
+		Config: ExplainedConfig{
+			Source:           "global config",
+			Enabled:          cfg.Enabled,
+			GlobalPercentage: cfg.GlobalPercentage,
+			MinReplicas:      cfg.MinReplicas,
+			MaxReplicas:      cfg.MaxReplicas,
+		},
+	}
+	if r.Config.NamespaceConfig(namespace) != nil {
+		explained.Config.Source = fmt.Sprintf("global config with ConfigMap %s/%s", namespace, config.ConfigMapName)
+	}
+	if def := r.namespaceDefault(ctx, namespace); def != nil {
+		explained.Config.NamespaceDefault = def.Name
+		_, explained.Config.Schedule, _ = applyNamespaceDefault(cfg, r.Config.GetConfig(), def, time.Now())
+	}
+	for i := range ignoreList.Items {
+		if isWorkloadIgnored("Deployment", deployment, ignoreList.Items[i:i+1]) {
+			explained.Ignores = append(explained.Ignores, ignoreList.Items[i].Name)
+		}
+	}
+	matches := deploymentOverrides(overrideList.Items, hpaList.Items, deployment)
+	for _, override := range matches {
+		explained.Overrides = append(explained.Overrides, override.Name)
+	}
+	if len(matches) > 0 {
+		explained.Triggers = r.Triggers.Values(namespace, matches[0].Name)
+	}
+
+	// Ignored deployments are only scaled by an override whose ignorePolicy is override
+	if len(explained.Ignores) > 0 && (len(matches) == 0 || matches[0].Spec.IgnorePolicy != dynamicscalingv1.IgnorePolicyOverride) {
+		explained.SimulatedWorkload = SimulatedWorkload{
+			Kind:            metrics.TargetKindDeployment,
+			Namespace:       namespace,
+			Name:            name,
+			CurrentReplicas: *deployment.Spec.Replicas,
+			Replicas:        *deployment.Spec.Replicas,
+			Explanation:     fmt.Sprintf("left alone: ignored by GlobalReplicasIgnore %s", strings.Join(explained.Ignores, ", ")),
+		}
+		return explained, nil
+	}
+
+	workload, err := r.simulateDeployment(ctx, deployment, hpaList.Items, overrideList.Items)
+	if err != nil {
+		return nil, err
+	}
+	explained.SimulatedWorkload, explained.Decision = workload, workload.decision
+	return explained, nil
+}
+
+// ExplainHandler serves Explain on GET requests for the Deployment of the
+// namespace and name query parameters
+func (r *ReplicasOverrideReconciler) ExplainHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "explanations are read with GET", http.StatusMethodNotAllowed)
+			return
+		}
+		namespace, name := req.URL.Query().Get("namespace"), req.URL.Query().Get("name")
+		if namespace == "" || name == "" {
+			http.Error(w, "the namespace and name query parameters are required", http.StatusBadRequest)
+			return
+		}
+
+		explained, err := r.Explain(req.Context(), namespace, name)
+		if apierrors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("deployment %s/%s not found", namespace, name), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.FromContext(req.Context()).Error(err, "Failed to explain the replicas of a deployment",
+				"deployment", fmt.Sprintf("%s/%s", namespace, name))
+			http.Error(w, fmt.Sprintf("explanation failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(explained)
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestExplain(t *testing.T) {
+	c := fakeclient.NewBuilder(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"team": "shop"},
+				Annotations: map[string]string{utils.OriginalReplicasAnnotation: "4"}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "shop", Labels: map[string]string{"team": "shop"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&dynamicscalingv1.GlobalReplicasIgnore{
+			ObjectMeta: metav1.ObjectMeta{Name: "frozen"},
+			Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+				IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "Deployment", Name: "legacy", Namespace: "shop"}},
+			},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "shop"}},
+				OverrideType:       "override",
+				ReplicasPercentage: 150,
+			},
+		},
+	).Build()
+	cfg := config.DefaultConfig()
+	cfg.MaxReplicas = 5
+	r := newTestReconciler(c, cfg)
+
+	recorder := httptest.NewRecorder()
+	r.ExplainHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ExplainPath+"?namespace=shop&name=web", nil))
+	var explained WorkloadExplanation
+	if err := json.NewDecoder(recorder.Body).Decode(&explained); err != nil {
+		t.Fatalf("decoding the response: %v", err)
+	}
+	if explained.Override != "sale" || !reflect.DeepEqual(explained.Overrides, []string{"sale"}) || len(explained.Ignores) != 0 {
+		t.Errorf("override = %q of %v, ignores %v, want sale alone", explained.Override, explained.Overrides, explained.Ignores)
+	}
+	// 150% of 4 replicas is clamped to the maxReplicas of the config
+	if explained.CurrentReplicas != 4 || explained.Replicas != 5 || !explained.Clamped || explained.Config.MaxReplicas != 5 {
+		t.Errorf("replicas %d -> %d clamped %t with config max %d, want 4 -> 5 clamped", explained.CurrentReplicas,
+			explained.Replicas, explained.Clamped, explained.Config.MaxReplicas)
+	}
+	if explained.Decision == nil || explained.Decision.Percentage != 150 || len(explained.Decision.Steps) != 2 {
+		t.Fatalf("decision = %+v, want 150%% over the global config", explained.Decision)
+	}
+	var stages []string
+	for _, stage := range explained.Decision.Stages {
+		stages = append(stages, stage.Stage)
+	}
+	if want := []string{"schedule", "replicas", "policy-clamp"}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+
+	// Ignored deployments are left alone, with the ignores matching them
+	explainedIgnored, err := r.Explain(context.Background(), "shop", "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(explainedIgnored.Ignores, []string{"frozen"}) || explainedIgnored.Replicas != 2 || explainedIgnored.Decision != nil {
+		t.Errorf("ignored deployment explained as %+v, want it left alone by frozen", explainedIgnored)
+	}
+
+	recorder = httptest.NewRecorder()
+	r.ExplainHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ExplainPath+"?namespace=shop&name=missing", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("missing deployment status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	recorder = httptest.NewRecorder()
+	r.ExplainHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ExplainPath+"?name=web", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("request without namespace status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/expression"
+)
+
+// ExpressionValidConditionType is the ReplicasOverride condition reporting
+// whether its percentageExpression compiles
+const ExpressionValidConditionType = "PercentageExpressionValid"
+
+// expressionCondition compiles the percentageExpression of override and
+// reports the result
+func expressionCondition(override *dynamicscalingv1.ReplicasOverride) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ExpressionValidConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Compiled",
+		Message:            "percentageExpression compiles to a number",
+		ObservedGeneration: override.Generation,
+	}
+	if err := expression.Compile(override.Spec.PercentageExpression); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidExpression"
+		condition.Message = err.Error() + ", replicasPercentage applies"
+	}
+	return condition
+}
+
+// expressionInvalid returns true if the percentageExpression of the current
+// generation of override was found not to compile
+func expressionInvalid(override *dynamicscalingv1.ReplicasOverride) bool {
+	condition := meta.FindStatusCondition(override.Status.Conditions, ExpressionValidConditionType)
+	return condition != nil && condition.Status == metav1.ConditionFalse && condition.ObservedGeneration == override.Generation
+}
+
+// checkPercentageExpressions compiles the percentageExpression of each
+// override once per generation, and sets its PercentageExpressionValid condition
+func (r *ReplicasOverrideReconciler) checkPercentageExpressions(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride) {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		if !override.DeletionTimestamp.IsZero() {
+			continue
+		}
+		condition := meta.FindStatusCondition(override.Status.Conditions, ExpressionValidConditionType)
+		if override.Spec.PercentageExpression == "" {
+			if !meta.RemoveStatusCondition(&override.Status.Conditions, ExpressionValidConditionType) {
+				continue
+			}
+		} else if condition != nil && condition.ObservedGeneration == override.Generation {
+			continue
+		} else if compiled := expressionCondition(override); meta.SetStatusCondition(&override.Status.Conditions, compiled) && compiled.Status == metav1.ConditionFalse {
+			log.Info("Override percentageExpression is invalid, replicasPercentage applies",
+				"override", override.Name,
+				"namespace", override.Namespace,
+				"error", compiled.Message)
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
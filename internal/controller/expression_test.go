@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestPercentageExpressionCondition(t *testing.T) {
+	ctx := context.Background()
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop", Generation: 1},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:        &dynamicscalingv1.DeploymentReference{Name: "api"},
+				OverrideType:         "override",
+				ReplicasPercentage:   200,
+				PercentageExpression: "hour(now) <",
+			},
+		},
+	).WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}).Build()
+	r := newTestReconciler(c, nil)
+	key := types.NamespacedName{Name: "api-sale", Namespace: "shop"}
+
+	reconcile := func() (*dynamicscalingv1.ReplicasOverride, int32) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		override := &dynamicscalingv1.ReplicasOverride{}
+		if err := c.Get(ctx, key, override); err != nil {
+			t.Fatal(err)
+		}
+		var deployment appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: "api", Namespace: "shop"}, &deployment); err != nil {
+			t.Fatal(err)
+		}
+		return override, *deployment.Spec.Replicas
+	}
+
+	// An expression that does not compile leaves replicasPercentage in effect
+	override, replicas := reconcile()
+	condition := meta.FindStatusCondition(override.Status.Conditions, ExpressionValidConditionType)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.ObservedGeneration != 1 {
+		t.Fatalf("%s condition = %+v, want False for generation 1", ExpressionValidConditionType, condition)
+	}
+	if replicas != 4 {
+		t.Errorf("replicas = %d, want 4 from replicasPercentage", replicas)
+	}
+	// It is not compiled again until the override changes
+	version := override.ResourceVersion
+	if override, _ = reconcile(); override.ResourceVersion != version {
+		t.Errorf("unchanged override status rewritten")
+	}
+
+	override.Spec.PercentageExpression = "300"
+	override.Generation = 2
+	if err := c.Update(ctx, override); err != nil {
+		t.Fatal(err)
+	}
+	override, replicas = reconcile()
+	condition = meta.FindStatusCondition(override.Status.Conditions, ExpressionValidConditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 2 {
+		t.Fatalf("%s condition = %+v, want True for generation 2", ExpressionValidConditionType, condition)
+	}
+	if replicas != 6 {
+		t.Errorf("replicas = %d, want 6 from the expression", replicas)
+	}
+}
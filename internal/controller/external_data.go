@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+const (
+	// ExternalDataPath is the path of the Gatekeeper external data provider,
+	// served by the webhook server over TLS
+	ExternalDataPath = "/external-data"
+
+	// externalDataAPIVersion is the version of the Gatekeeper provider protocol
+	externalDataAPIVersion = "externaldata.gatekeeper.sh/v1beta1"
+
+	// maxExternalDataRequestBytes bounds the size of a provider request
+	maxExternalDataRequestBytes = 1 << 20
+)
+
+// ExternalDataRequest is a Gatekeeper ProviderRequest. Keys are
+// Deployment/<namespace>/<name> or HorizontalPodAutoscaler/<namespace>/<name>.
+type ExternalDataRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Request    struct {
+		Keys []string `json:"keys"`
+	} `json:"request"`
+}
+
+// ExternalDataResponse is a Gatekeeper ProviderResponse
+type ExternalDataResponse struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Response   ExternalDataResult `json:"response"`
+}
+
+// ExternalDataResult is the response body of a ProviderResponse
+type ExternalDataResult struct {
+	Idempotent  bool               `json:"idempotent"`
+	Items       []ExternalDataItem `json:"items"`
+	SystemError string             `json:"systemError,omitempty"`
+}
+
+// ExternalDataItem is the state of the workload of one key, or why it could not be read
+type ExternalDataItem struct {
+	Key   string                `json:"key"`
+	Value *ManagedWorkloadState `json:"value,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+// ManagedWorkloadState tells an admission policy whether the controller manages
+// a Deployment and the replicas, or HPA limits, it currently scales it to
+type ManagedWorkloadState struct {
+	// Managed is false for workloads excluded by a GlobalReplicasIgnore
+	Managed bool `json:"managed"`
+	SimulatedWorkload
+}
+
+// workloadState returns the state of the workload of a provider key
+func (r *ReplicasOverrideReconciler) workloadState(ctx context.Context, key string) (*ManagedWorkloadState, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("invalid key %q, expected Deployment/<namespace>/<name> or HorizontalPodAutoscaler/<namespace>/<name>", key)
+	}
+	kind, namespace, name := parts[0], parts[1], parts[2]
+
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	switch kind {
+	case metrics.TargetKindDeployment:
+	case metrics.TargetKindHPA:
+		hpa := findHPA(hpaList.Items, name)
+		if hpa == nil {
+			return nil, fmt.Errorf("no HorizontalPodAutoscaler %s/%s", namespace, name)
+		}
+		if !scalesDeployment(hpa) {
+			return &ManagedWorkloadState{SimulatedWorkload: SimulatedWorkload{
+				Kind:        hpa.Spec.ScaleTargetRef.Kind,
+				Namespace:   namespace,
+				Name:        hpa.Spec.ScaleTargetRef.Name,
+				HPA:         hpa.Name,
+				Explanation: "not reported: only HPAs scaling a Deployment are",
+			}}, nil
+		}
+		name = hpa.Spec.ScaleTargetRef.Name
+	default:
+		return nil, fmt.Errorf("unsupported kind %q", kind)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("no Deployment %s/%s", namespace, name)
+		}
+		return nil, err
+	}
+	ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := r.List(ctx, ignoreList); err != nil {
+		return nil, err
+	}
+	if isWorkloadIgnored("Deployment", deployment, ignoreList.Items) || deployment.Spec.Replicas == nil {
+		return &ManagedWorkloadState{SimulatedWorkload: SimulatedWorkload{
+			Kind:        metrics.TargetKindDeployment,
+			Namespace:   namespace,
+			Name:        name,
+			Explanation: "left alone: ignored",
+		}}, nil
+	}
+
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	workload, err := r.simulateDeployment(ctx, deployment, hpaList.Items, overrideList.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &ManagedWorkloadState{Managed: true, SimulatedWorkload: workload}, nil
+}
+
+// findHPA returns the HPA of hpas with the given name
+func findHPA(hpas []autoscalingv2.HorizontalPodAutoscaler, name string) *autoscalingv2.HorizontalPodAutoscaler {
+	for i := range hpas {
+		if hpas[i].Name == name {
+			return &hpas[i]
+		}
+	}
+	return nil
+}
+
+// ExternalDataHandler serves the state of workloads to Gatekeeper as an external
+// data provider, so admission policies can reject changes conflicting with the
+// controller, e.g. HPA edits fighting an active override
+func (r *ReplicasOverrideReconciler) ExternalDataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "provider requests must be submitted with POST", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Answers change with overrides and the config, so Gatekeeper must not cache them
+		response := ExternalDataResponse{APIVersion: externalDataAPIVersion, Kind: "ProviderResponse"}
+		var request ExternalDataRequest
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxExternalDataRequestBytes)).Decode(&request); err != nil {
+			response.Response.SystemError = fmt.Sprintf("invalid provider request: %v", err)
+		}
+		for _, key := range request.Request.Keys {
+			item := ExternalDataItem{Key: key}
+			if state, err := r.workloadState(req.Context(), key); err != nil {
+				log.FromContext(req.Context()).V(1).Info("Failed to read workload state for Gatekeeper", "key", key, "error", err)
+				item.Error = err.Error()
+			} else {
+				item.Value = state
+			}
+			response.Response.Items = append(response.Response.Items, item)
+		}
+
+		// Gatekeeper reads errors from the body, which is always a ProviderResponse
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
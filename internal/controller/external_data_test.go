@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestExternalDataHandler(t *testing.T) {
+	c := fakeclient.NewBuilder(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-hpa", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MinReplicas:    int32Ptr(2),
+				MaxReplicas:    10,
+			},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "web"},
+				ReplicasPercentage: 150,
+			},
+		},
+	).Build()
+	r := newTestReconciler(c, nil)
+
+	body := `{"apiVersion":"externaldata.gatekeeper.sh/v1beta1","kind":"ProviderRequest","request":{"keys":["Deployment/shop/web","HorizontalPodAutoscaler/shop/api-hpa","Deployment/shop/missing","web"]}}`
+	recorder := httptest.NewRecorder()
+	r.ExternalDataHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, ExternalDataPath, strings.NewReader(body)))
+
+	var response ExternalDataResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("decoding the response: %v", err)
+	}
+	if response.Kind != "ProviderResponse" || response.Response.SystemError != "" || len(response.Response.Items) != 4 {
+		t.Fatalf("response = %+v, want a ProviderResponse with 4 items", response)
+	}
+	items := response.Response.Items
+	if web := items[0].Value; web == nil || !web.Managed || web.Override != "sale" || web.Replicas != 6 {
+		t.Errorf("web = %+v, want 6 replicas from override sale", items[0])
+	}
+	if api := items[1].Value; api == nil || !api.Managed || api.Name != "api" || api.HPA != "api-hpa" || api.MaxReplicas != 10 {
+		t.Errorf("api-hpa = %+v, want the state of deployment api", items[1])
+	}
+	for _, item := range items[2:] {
+		if item.Value != nil || item.Error == "" {
+			t.Errorf("%s = %+v, want an item error", item.Key, item)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	r.ExternalDataHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, ExternalDataPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// Built-in external scaler names
+	scalerKEDA         = "keda"
+	scalerArgoRollouts = "argo-rollouts"
+
+	// kedaScaledObjectLabel is set by KEDA on the HPA it creates for a ScaledObject
+	kedaScaledObjectLabel = "scaledobject.keda.sh/name"
+
+	// KEDA defaults when the ScaledObject leaves the counts unset
+	kedaDefaultMinReplicas = 0
+	kedaDefaultMaxReplicas = 100
+
+	// TargetKindScaledObject is the metrics target kind of KEDA ScaledObjects
+	targetKindScaledObject = "ScaledObject"
+)
+
+// External scaler kinds, handled as unstructured so they are not build dependencies
+var (
+	scaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+	rolloutListGVK  = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "RolloutList"}
+)
+
+// externalScaler is an autoscaler other than a plain HPA driving a deployment
+type externalScaler struct {
+	// name is keda, argo-rollouts or the name of a custom detection
+	name string
+	// mode is the configured management mode
+	mode string
+	// scaledObject is the KEDA ScaledObject name
+	scaledObject string
+	// rollout is the Argo Rollout referencing the deployment
+	rollout string
+}
+
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=argoproj.io,resources=rollouts,verbs=get;list;watch
+
+// rolloutForDeployment returns the Argo Rollout referencing the deployment through
+// spec.workloadRef, or "" when there is none or Argo Rollouts is not installed
+func (r *ReplicasOverrideReconciler) rolloutForDeployment(ctx context.Context, deployment *appsv1.Deployment) string {
+	rollouts := &unstructured.UnstructuredList{}
+	rollouts.SetGroupVersionKind(rolloutListGVK)
+	if err := r.List(ctx, rollouts, client.InNamespace(deployment.Namespace)); err != nil {
+		return ""
+	}
+	for _, rollout := range rollouts.Items {
+		kind, _, _ := unstructured.NestedString(rollout.Object, "spec", "workloadRef", "kind")
+		name, _, _ := unstructured.NestedString(rollout.Object, "spec", "workloadRef", "name")
+		if kind == "Deployment" && name == deployment.Name {
+			return rollout.GetName()
+		}
+	}
+	return ""
+}
+
+// detectExternalScaler returns the external autoscaler driving the deployment, if any
+func detectExternalScaler(cfg *config.GlobalConfig, deployment *appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, rollout string) *externalScaler {
+	for _, custom := range cfg.ExternalScalers.Custom {
+		if custom.Matches(deployment.Labels, deployment.Annotations) {
+			return &externalScaler{name: custom.Name, mode: custom.GetMode()}
+		}
+	}
+
+	for _, hpa := range hpas {
+		if name, ok := hpa.Labels[kedaScaledObjectLabel]; ok &&
+			hpa.Spec.ScaleTargetRef.Kind == "Deployment" && hpa.Spec.ScaleTargetRef.Name == deployment.Name {
+			return &externalScaler{name: scalerKEDA, mode: cfg.ExternalScalers.KEDA.GetMode(), scaledObject: name}
+		}
+	}
+
+	if rollout != "" {
+		return &externalScaler{name: scalerArgoRollouts, mode: cfg.ExternalScalers.ArgoRollouts.GetMode(), rollout: rollout}
+	}
+	return nil
+}
+
+// processExternalScaler manages a deployment driven by an external scaler in
+// limits-only mode: only the limits of the scaler are changed, never the replicas
+func (r *ReplicasOverrideReconciler) processExternalScaler(ctx context.Context, cfg *config.GlobalConfig, deployment *appsv1.Deployment, scaler *externalScaler, hpas []autoscalingv2.HorizontalPodAutoscaler, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+
+	if err := r.markExternalScaler(ctx, deployment, scaler); err != nil {
+		return err
+	}
+
+	switch scaler.name {
+	case scalerKEDA:
+		if !targetsKind(override, targetKindScaledObject) {
+			return errKindNotTargeted
+		}
+		return r.processScaledObject(ctx, cfg, deployment, scaler.scaledObject, override)
+	case scalerArgoRollouts:
+		// Argo Rollouts HPAs target the Rollout rather than the deployment
+		for i := range hpas {
+			hpa := &hpas[i]
+			if hpa.Spec.ScaleTargetRef.Kind == "Rollout" && hpa.Spec.ScaleTargetRef.Name == scaler.rollout {
+				if !targetsKind(override, metrics.TargetKindHPA) {
+					return errKindNotTargeted
+				}
+				return r.processHPA(ctx, hpa, deployment, &deployment.Spec.Template, override)
+			}
+		}
+	}
+
+	log.V(1).Info("Deployment is driven by an external scaler without known limits, leaving replicas untouched",
+		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+		"scaler", scaler.name)
+	return nil
+}
+
+// markExternalScaler records the detected scaler and limits-only mode on the deployment
+func (r *ReplicasOverrideReconciler) markExternalScaler(ctx context.Context, deployment *appsv1.Deployment, scaler *externalScaler) error {
+	if deployment.Annotations[utils.ManagementModeAnnotation] == config.ScalerModeLimitsOnly &&
+		deployment.Annotations[utils.ExternalScalerAnnotation] == scaler.name {
+		return nil
+	}
+	return r.updateDeploymentAnnotations(ctx, deployment, map[string]string{
+		utils.ManagementModeAnnotation: config.ScalerModeLimitsOnly,
+		utils.ExternalScalerAnnotation: scaler.name,
+	})
+}
+
+// processScaledObject scales the min/max replica counts of a KEDA ScaledObject
+func (r *ReplicasOverrideReconciler) processScaledObject(ctx context.Context, cfg *config.GlobalConfig, deployment *appsv1.Deployment, name string, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+
+	explanation := r.resolvePercentage(ctx, deployment, &deployment.Spec.Template, override)
+	percentage, trigger := explanation.Percentage, explanation.Trigger
+	labels := scalingLabels(deployment.Namespace, targetKindScaledObject, override)
+	labels.Trigger = trigger
+
+	var originalMin, previousMin, targetMin, targetMax int32
+	var clamped bool
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scaledObject := &unstructured.Unstructured{}
+		scaledObject.SetGroupVersionKind(scaledObjectGVK)
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: deployment.Namespace}, scaledObject); err != nil {
+			return err
+		}
+
+		r.repairOriginals(ctx, targetKindScaledObject, scaledObject, override)
+		annotations := scaledObject.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		currentMin := nestedInt32(scaledObject, "minReplicaCount", kedaDefaultMinReplicas)
+		currentMax := nestedInt32(scaledObject, "maxReplicaCount", kedaDefaultMaxReplicas)
+		if _, exists := annotations[utils.OriginalMinReplicasAnnotation]; !exists {
+			annotations[utils.OriginalMinReplicasAnnotation] = strconv.FormatInt(int64(currentMin), 10)
+		}
+		if _, exists := annotations[utils.OriginalMaxReplicasAnnotation]; !exists {
+			annotations[utils.OriginalMaxReplicasAnnotation] = strconv.FormatInt(int64(currentMax), 10)
+		}
+		parsedMin, _ := utils.ParseReplicas(annotations[utils.OriginalMinReplicasAnnotation])
+		parsedMax, _ := utils.ParseReplicas(annotations[utils.OriginalMaxReplicasAnnotation])
+		originalMin, previousMin = parsedMin, currentMin
+
+		targetMin = utils.PercentOf(parsedMin, percentage)
+		targetMax = utils.PercentOf(parsedMax, percentage)
+		// The ScaledObject limits are those of the HPA KEDA creates, kept as the hpaPolicy says
+		adjustMin, adjustMax := hpaPolicy(override)
+		if !adjustMin {
+			targetMin = parsedMin
+		}
+		if !adjustMax {
+			targetMax = parsedMax
+		}
+		clamped = false
+		// Scale-to-zero ScaledObjects keep their zero minimum
+		if parsedMin > 0 && targetMin < cfg.MinReplicas {
+			targetMin, clamped = cfg.MinReplicas, true
+		}
+		if targetMax > cfg.MaxReplicas {
+			targetMax, clamped = cfg.MaxReplicas, true
+		}
+		if targetMax < 1 {
+			targetMax = 1
+		}
+		if targetMin > targetMax {
+			targetMin = targetMax
+		}
+		explanation.SetReplicas(targetMin, clamped)
+		explain := explanation.JSON()
+		if targetMin == currentMin && targetMax == currentMax && annotations[utils.ExplainAnnotation] == explain {
+			return nil
+		}
+
+		annotations[utils.LastHPAUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		annotations[utils.ExplainAnnotation] = explain
+		scaledObject.SetAnnotations(annotations)
+		if err := unstructured.SetNestedField(scaledObject.Object, int64(targetMin), "spec", "minReplicaCount"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(scaledObject.Object, int64(targetMax), "spec", "maxReplicaCount"); err != nil {
+			return err
+		}
+		return r.Update(ctx, scaledObject, newChangeReason(labels, &percentage).record(scaledObject))
+	})
+	if err != nil {
+		log.Error(err, "Failed to update ScaledObject", "scaledObject", fmt.Sprintf("%s/%s", deployment.Namespace, name))
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(deployment.Namespace, name, labels, originalMin, previousMin, targetMin, clamped, err)
+		return err
+	}
+	if targetMin != previousMin {
+		metrics.RecordScaling(ctx, labels, previousMin, targetMin, percentage, clamped)
+		r.recordEvent(deployment.Namespace, name, labels, originalMin, previousMin, targetMin, clamped, nil)
+		log.Info("Updated ScaledObject limits",
+			"scaledObject", fmt.Sprintf("%s/%s", deployment.Namespace, name),
+			"min_replicas", targetMin,
+			"max_replicas", targetMax,
+			"percentage", percentage)
+	}
+	return nil
+}
+
+// nestedInt32 reads spec.<field> of an unstructured object, or def when unset,
+// bounded to a non-negative int32
+func nestedInt32(obj *unstructured.Unstructured, field string, def int32) int32 {
+	value, found, err := unstructured.NestedInt64(obj.Object, "spec", field)
+	if err != nil || !found {
+		return def
+	}
+	return utils.BoundReplicas(float64(value))
+}
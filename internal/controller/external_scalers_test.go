@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestDetectExternalScaler(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:      "worker",
+		Namespace: "default",
+		Labels:    map[string]string{"autoscaler": "in-house"},
+	}}
+	kedaHPA := autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "keda-hpa-worker", Labels: map[string]string{kedaScaledObjectLabel: "worker-so"}},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", Name: "worker"},
+		},
+	}
+	custom := config.CustomScalerDetection{Name: "in-house", Labels: map[string]string{"autoscaler": "in-house"}, Mode: config.ScalerModeIgnore}
+
+	tests := []struct {
+		name     string
+		cfg      *config.GlobalConfig
+		hpas     []autoscalingv2.HorizontalPodAutoscaler
+		rollout  string
+		want     string
+		wantMode string
+	}{
+		{name: "no external scaler", cfg: &config.GlobalConfig{}},
+		{name: "keda defaults to limits-only", cfg: &config.GlobalConfig{}, hpas: []autoscalingv2.HorizontalPodAutoscaler{kedaHPA}, want: scalerKEDA, wantMode: config.ScalerModeLimitsOnly},
+		{name: "argo rollouts mode is configurable", cfg: &config.GlobalConfig{ExternalScalers: config.ExternalScalersConfig{
+			ArgoRollouts: config.ExternalScalerDetection{Mode: config.ScalerModeManage},
+		}}, rollout: "worker", want: scalerArgoRollouts, wantMode: config.ScalerModeManage},
+		{name: "custom detection wins", cfg: &config.GlobalConfig{ExternalScalers: config.ExternalScalersConfig{
+			Custom: []config.CustomScalerDetection{custom},
+		}}, hpas: []autoscalingv2.HorizontalPodAutoscaler{kedaHPA}, want: "in-house", wantMode: config.ScalerModeIgnore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaler := detectExternalScaler(tt.cfg, deployment, tt.hpas, tt.rollout)
+			var name, mode string
+			if scaler != nil {
+				name, mode = scaler.name, scaler.mode
+			}
+			if name != tt.want || mode != tt.wantMode {
+				t.Errorf("detectExternalScaler() = (%q, %q), want (%q, %q)", name, mode, tt.want, tt.wantMode)
+			}
+		})
+	}
+}
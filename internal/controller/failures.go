@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// SyncedConditionType is the condition of overrides reporting whether the
+// last pass reconciled all their deployments, with the failure reason of one
+// that failed otherwise
+const SyncedConditionType = "Synced"
+
+// recordFailure classifies the failure of a target of override, nil for the
+// global config, counts it in reconcile_errors_total and returns its reason
+func recordFailure(namespace, targetKind string, override *dynamicscalingv1.ReplicasOverride, err error) failure.Reason {
+	reason := failure.Classify(err)
+	metrics.RecordReconcileError(scalingLabels(namespace, targetKind, override), string(reason))
+	return reason
+}
+
+// recordReconcileFailure counts a failure listing the objects of a pass in
+// namespace, empty for the whole cluster, in reconcile_errors_total
+func recordReconcileFailure(namespace string, err error) {
+	metrics.RecordReconcileError(metrics.ScalingLabels{Namespace: namespace}, string(failure.Classify(err)))
+}
+
+// setSyncedCondition marks the deployments of override reconciled
+func setSyncedCondition(override *dynamicscalingv1.ReplicasOverride) {
+	meta.SetStatusCondition(&override.Status.Conditions, metav1.Condition{
+		Type:               SyncedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "All deployments were reconciled",
+		ObservedGeneration: override.Generation,
+	})
+}
+
+// reportSyncFailure sets the Synced condition of override to the failure of
+// reason reconciling target, and writes the status when it changed
+func (r *ReplicasOverrideReconciler) reportSyncFailure(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, target client.Object, reason failure.Reason, err error) {
+	previous := override.Status.DeepCopy()
+	meta.SetStatusCondition(&override.Status.Conditions, metav1.Condition{
+		Type:               SyncedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             string(reason),
+		Message:            fmt.Sprintf("Failed to reconcile %s/%s: %v", target.GetNamespace(), target.GetName(), err),
+		ObservedGeneration: override.Generation,
+	})
+	if equality.Semantic.DeepEqual(previous, &override.Status) {
+		return
+	}
+	if err := r.Status().Update(ctx, override); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update override status",
+			"override", override.Name,
+			"namespace", override.Namespace)
+	}
+}
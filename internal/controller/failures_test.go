@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestSyncedCondition(t *testing.T) {
+	ctx := context.Background()
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "quota"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "quota"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+			OverrideType:       "override",
+			ReplicasPercentage: 200,
+		},
+	}
+	// The ResourceQuota of the namespace rejects the scale-up until it is raised
+	quotaExceeded := true
+	rejectScaleUp := func(obj client.Object) error {
+		if d, ok := obj.(*appsv1.Deployment); ok && quotaExceeded && *d.Spec.Replicas > 3 {
+			return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, d.Name,
+				fmt.Errorf("exceeded quota: compute, requested: pods=6, used: pods=3, limited: pods=4"))
+		}
+		return nil
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "quota"}}, deployment, override).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if err := rejectScaleUp(obj); err != nil {
+					return err
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if err := rejectScaleUp(obj); err != nil {
+					return err
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+	r := newTestReconciler(c, nil)
+	synced := func() *metav1.Condition {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+			t.Fatal(err)
+		}
+		return meta.FindStatusCondition(override.Status.Conditions, SyncedConditionType)
+	}
+
+	quotaErrors := metrics.ReconcileErrorsTotal.WithLabelValues("api-sale", "quota", metrics.TargetKindDeployment, metrics.TriggerOverride, "QuotaExceeded")
+	condition := synced()
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "QuotaExceeded" {
+		t.Fatalf("Synced condition = %+v, want False with reason QuotaExceeded", condition)
+	}
+	if !strings.Contains(condition.Message, "quota/api") {
+		t.Errorf("Synced message = %q, want the failed deployment", condition.Message)
+	}
+	if got := testutil.ToFloat64(quotaErrors); got != 1 {
+		t.Errorf("reconcile_errors_total{reason=QuotaExceeded} = %v, want 1", got)
+	}
+
+	quotaExceeded = false
+	if condition := synced(); condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "Synced" {
+		t.Errorf("Synced condition once the quota is raised = %+v, want True", condition)
+	}
+	if got := testutil.ToFloat64(quotaErrors); got != 1 {
+		t.Errorf("reconcile_errors_total{reason=QuotaExceeded} = %v after the recovery, want 1", got)
+	}
+}
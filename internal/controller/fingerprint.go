@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// recordedFor returns true if the status entry affected was recorded for
+// deployment and not for a previous deployment of the same name. Entries
+// recorded before UIDs were tracked are assumed to be.
+func recordedFor(affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment) bool {
+	return affected.UID == "" || deployment.UID == "" || affected.UID == deployment.UID
+}
+
+// forgetRecreated drops the entry of the override status recorded for a
+// previous deployment of the same name, e.g. deleted and recreated by a CI
+// redeploy, so its originals are not restored onto the new one. It returns
+// true if an entry was dropped.
+func forgetRecreated(override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment) bool {
+	for i := range override.Status.AffectedDeployments {
+		affected := &override.Status.AffectedDeployments[i]
+		if affected.Namespace == deployment.Namespace && affected.Name == deployment.Name && !recordedFor(affected, deployment) {
+			override.Status.AffectedDeployments = append(override.Status.AffectedDeployments[:i], override.Status.AffectedDeployments[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dropStaleOriginals removes the original annotations of obj recorded on
+// another object, e.g. copied from the manifest of a deleted object to the
+// one recreated from it. It returns the UID they were recorded on, or "".
+func dropStaleOriginals(obj metav1.Object) string {
+	annotations := obj.GetAnnotations()
+	recordedOn := annotations[utils.OriginalsUIDAnnotation]
+	if recordedOn == "" || obj.GetUID() == "" || recordedOn == string(obj.GetUID()) {
+		return ""
+	}
+	for _, key := range originalAnnotations {
+		delete(annotations, key)
+	}
+	delete(annotations, utils.BaselineRecordedAnnotation)
+	delete(annotations, utils.OriginalsUIDAnnotation)
+	// Unstructured objects return a copy of their annotations
+	obj.SetAnnotations(annotations)
+	return recordedOn
+}
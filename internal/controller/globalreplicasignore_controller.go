@@ -28,6 +28,10 @@ import (
 	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +39,8 @@ import (
 type GlobalReplicasIgnoreReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Pager lists the deployments of the cluster a page at a time (optional)
+	Pager *utils.Pager
 }
 
 // +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=globalreplicasignores,verbs=get;list;watch;create;update;patch;delete
@@ -42,6 +48,8 @@ type GlobalReplicasIgnoreReconciler struct {
 // +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=globalreplicasignores/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=replicasoverrides,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -59,34 +67,86 @@ func (r *GlobalReplicasIgnoreReconciler) Reconcile(ctx context.Context, req ctrl
 		ignore.Status.IgnoredDeployments = []dynamicscalingv1.IgnoredDeployment{}
 	}
 
-	// Get list of all deployments
-	deployments := &appsv1.DeploymentList{}
-	if err := r.List(ctx, deployments); err != nil {
-		log.Error(err, "Failed to list deployments")
+	// Overrides whose ignorePolicy is override scale ignored deployments anyway
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrides); err != nil {
+		log.Error(err, "Failed to list overrides")
 		return ctrl.Result{}, err
 	}
+	// HPAs are only needed in the namespaces where an override may do so
+	namespaceHPAs := make(map[string][]autoscalingv2.HorizontalPodAutoscaler)
+	hpasOf := func(namespace string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+		if hpas, listed := namespaceHPAs[namespace]; listed || !allowsIgnored(overrides.Items, namespace) {
+			return hpas, nil
+		}
+		hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := r.List(ctx, hpas, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		namespaceHPAs[namespace] = hpas.Items
+		return hpas.Items, nil
+	}
 
-	// Process each deployment
+	// Process the deployments a page at a time, keeping those the rule names to check its references
 	ignoredDeployments := []dynamicscalingv1.IgnoredDeployment{}
-	for _, deployment := range deployments.Items {
-		shouldIgnore, reason := utils.ShouldIgnoreDeployment(&deployment, ignore)
-		if shouldIgnore {
-			ignoredDeployments = append(ignoredDeployments, dynamicscalingv1.IgnoredDeployment{
-				Name:      deployment.Name,
-				Namespace: deployment.Namespace,
-				Reason:    reason,
-			})
+	var referenced []appsv1.Deployment
+	err := r.Pager.EachListItem(ctx, r.Client, &appsv1.DeploymentList{}, func(obj client.Object) error {
+		deployment := obj.(*appsv1.Deployment)
+		for _, resource := range ignore.Spec.IgnoreResources {
+			if resource.Kind == "Deployment" && deploymentExists([]appsv1.Deployment{*deployment}, resource) {
+				referenced = append(referenced, *deployment.DeepCopy())
+				break
+			}
 		}
-	}
 
-	// Update status
-	ignore.Status.IgnoredDeployments = ignoredDeployments
-	ignore.Status.LastUpdateTime = &metav1.Time{Time: time.Now()}
+		shouldIgnore, reason := utils.ShouldIgnoreDeployment(deployment, ignore)
+		if !shouldIgnore {
+			return nil
+		}
+		ignored := dynamicscalingv1.IgnoredDeployment{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			Reason:    reason,
+		}
+		hpas, err := hpasOf(deployment.Namespace)
+		if err != nil {
+			return err
+		}
+		if override := overridingIgnore(inNamespace(overrides.Items, deployment.Namespace), hpas, deployment); override != nil {
+			ignored.OverriddenBy = override.Name
+		}
+		ignoredDeployments = append(ignoredDeployments, ignored)
+		return nil
+	})
+	if err != nil {
+		log.Error(err, "Failed to list deployments")
+		return ctrl.Result{}, err
+	}
 
-	if err := r.Status().Update(ctx, ignore); err != nil {
-		log.Error(err, "Failed to update GlobalReplicasIgnore status")
+	// Warn about namespaces and deployments named by the rule that do not exist
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces); err != nil {
+		log.Error(err, "Failed to list namespaces")
 		return ctrl.Result{}, err
 	}
+	references := ignoreReferencesCondition(ignore, namespaces.Items, referenced)
+	if references.Status == metav1.ConditionFalse {
+		log.Info("Ignore rule references missing resources", "ignore", ignore.Name, "message", references.Message)
+	}
+	changed := meta.SetStatusCondition(&ignore.Status.Conditions, references)
+
+	// Update status, only when it changed so the periodic pass does not rewrite it
+	if !equality.Semantic.DeepEqual(ignore.Status.IgnoredDeployments, ignoredDeployments) {
+		ignore.Status.IgnoredDeployments = ignoredDeployments
+		changed = true
+	}
+	if changed || ignore.Status.LastUpdateTime == nil {
+		ignore.Status.LastUpdateTime = &metav1.Time{Time: time.Now()}
+		if err := r.Status().Update(ctx, ignore); err != nil {
+			log.Error(err, "Failed to update GlobalReplicasIgnore status")
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Requeue after 5 minutes
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
@@ -96,5 +156,6 @@ func (r *GlobalReplicasIgnoreReconciler) Reconcile(ctx context.Context, req ctrl
 func (r *GlobalReplicasIgnoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dynamicscalingv1.GlobalReplicasIgnore{}).
+		WithOptions(queueOptions()).
 		Complete(r)
 }
@@ -0,0 +1,283 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/blackout"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// overrideGroups indexes the overrides with a spec.group by group name
+func overrideGroups(overrides []dynamicscalingv1.ReplicasOverride) map[string][]*dynamicscalingv1.ReplicasOverride {
+	groups := make(map[string][]*dynamicscalingv1.ReplicasOverride)
+	for i := range overrides {
+		if group := overrides[i].Spec.Group; group != "" {
+			groups[group] = append(groups[group], &overrides[i])
+		}
+	}
+	return groups
+}
+
+// groupHold returns why the members of a group must not change their targets,
+// empty when every member may apply. A member that is paused, rolled back or
+// inside a blackout window holds the whole group.
+func groupHold(members []*dynamicscalingv1.ReplicasOverride, now time.Time) string {
+	for _, member := range members {
+		key := member.Namespace + "/" + member.Name
+		switch {
+		case isPaused(member):
+			return fmt.Sprintf("Member %s is paused", key)
+		case rollbackRequested(member):
+			return fmt.Sprintf("Member %s is rolled back", key)
+		}
+		if window, until, err := blackout.Active(member.Spec.BlackoutWindows, now); err == nil && window != nil {
+			return fmt.Sprintf("Member %s is frozen by window %q until %s", key, window.Name, until.UTC().Format(time.RFC3339))
+		}
+	}
+	return ""
+}
+
+// groupRollbackRequested returns true if a member of the group is annotated for rollback
+func groupRollbackRequested(members []*dynamicscalingv1.ReplicasOverride) bool {
+	for _, member := range members {
+		if rollbackRequested(member) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupTarget is a target of a group as it was before a pass processed it
+type groupTarget struct {
+	override   *dynamicscalingv1.ReplicasOverride
+	deployment types.NamespacedName
+	replicas   *int32
+	hpa        *autoscalingv2.HorizontalPodAutoscaler
+}
+
+// snapshotGroupTarget records the replicas of deployment and the limits of its HPA
+func snapshotGroupTarget(override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler) groupTarget {
+	target := groupTarget{override: override, deployment: client.ObjectKeyFromObject(deployment)}
+	if deployment.Spec.Replicas != nil {
+		replicas := *deployment.Spec.Replicas
+		target.replicas = &replicas
+	}
+	for i := range hpas {
+		if scalesDeployment(&hpas[i]) && hpas[i].Spec.ScaleTargetRef.Name == deployment.Name {
+			target.hpa = hpas[i].DeepCopy()
+			break
+		}
+	}
+	return target
+}
+
+// groupPass collects the targets of the groups a reconcile pass processed, so a
+// group whose targets could not all be updated is put back as a unit
+type groupPass struct {
+	targets map[string][]groupTarget
+	failed  map[string][]string
+}
+
+func newGroupPass() *groupPass {
+	return &groupPass{targets: make(map[string][]groupTarget), failed: make(map[string][]string)}
+}
+
+// record adds a processed target of a group, err being the outcome of processing it
+func (p *groupPass) record(target groupTarget, err error) {
+	group := target.override.Spec.Group
+	p.targets[group] = append(p.targets[group], target)
+	if err != nil {
+		p.failed[group] = append(p.failed[group], target.deployment.String())
+	}
+}
+
+// settleGroups puts back the targets of the groups with a failed target and
+// reports the outcome of the pass in the status of every member
+func (r *ReplicasOverrideReconciler) settleGroups(ctx context.Context, groups map[string][]*dynamicscalingv1.ReplicasOverride, held map[string]string, pass *groupPass, now time.Time) {
+	log := log.FromContext(ctx)
+
+	for name, members := range groups {
+		status := dynamicscalingv1.OverrideGroupStatus{Name: name, Phase: dynamicscalingv1.OverrideGroupApplied}
+		for _, member := range members {
+			status.Members = append(status.Members, member.Namespace+"/"+member.Name)
+		}
+		sort.Strings(status.Members)
+
+		if reason := held[name]; reason != "" {
+			status.Phase, status.Message = dynamicscalingv1.OverrideGroupHeld, reason
+		} else if failed := pass.failed[name]; len(failed) > 0 {
+			reverted := r.revertGroupTargets(ctx, pass.targets[name])
+			log.Info("Group could not be applied as a unit, put back its targets",
+				"group", name,
+				"failed", failed,
+				"reverted", reverted)
+			status.Phase = dynamicscalingv1.OverrideGroupReverted
+			status.Message = fmt.Sprintf("Could not update %s, put back %d targets changed in the same pass",
+				strings.Join(failed, ", "), reverted)
+		}
+
+		for _, member := range members {
+			if err := r.setGroupStatus(ctx, member, status, now); err != nil {
+				log.Error(err, "Failed to update override group status",
+					"override", member.Name,
+					"namespace", member.Namespace,
+					"group", name)
+			}
+		}
+	}
+}
+
+// revertGroupTargets restores the replicas and HPA limits the targets had
+// before the pass and returns how many were changed back
+func (r *ReplicasOverrideReconciler) revertGroupTargets(ctx context.Context, targets []groupTarget) int {
+	log := log.FromContext(ctx)
+
+	reverted := 0
+	for _, target := range targets {
+		changed, err := r.revertGroupTarget(ctx, target)
+		if err != nil {
+			log.Error(err, "Failed to put back group target", "deployment", target.deployment.String())
+		}
+		if changed {
+			reverted++
+		}
+	}
+	return reverted
+}
+
+// revertGroupTarget restores one target, returning true if it had changed
+func (r *ReplicasOverrideReconciler) revertGroupTarget(ctx context.Context, target groupTarget) (bool, error) {
+	changed := false
+	if target.hpa != nil {
+		labels := scalingLabels(target.hpa.Namespace, metrics.TargetKindHPA, target.override)
+		labels.Trigger = metrics.TriggerGroup
+		var previous, restored int32
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := &autoscalingv2.HorizontalPodAutoscaler{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(target.hpa), latest); err != nil {
+				return err
+			}
+			if equality.Semantic.DeepEqual(latest.Spec.MinReplicas, target.hpa.Spec.MinReplicas) &&
+				latest.Spec.MaxReplicas == target.hpa.Spec.MaxReplicas {
+				return nil
+			}
+			changed = true
+			previous, restored = hpaMinReplicas(latest), hpaMinReplicas(target.hpa)
+			latest.Spec.MinReplicas, latest.Spec.MaxReplicas = target.hpa.Spec.MinReplicas, target.hpa.Spec.MaxReplicas
+			return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+		})
+		if changed {
+			original, _ := utils.GetOriginalHPALimits(target.hpa)
+			r.recordGroupRevert(ctx, target.hpa.Namespace, target.hpa.Name, labels, previous, restored, original, err)
+		}
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	if target.replicas == nil {
+		return changed, nil
+	}
+	labels := scalingLabels(target.deployment.Namespace, metrics.TargetKindDeployment, target.override)
+	labels.Trigger = metrics.TriggerGroup
+	var previous, original int32
+	deploymentChanged := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &appsv1.Deployment{}
+		if err := r.Get(ctx, target.deployment, latest); err != nil {
+			return err
+		}
+		if latest.Spec.Replicas != nil && *latest.Spec.Replicas == *target.replicas {
+			return nil
+		}
+		deploymentChanged = true
+		if latest.Spec.Replicas != nil {
+			previous = *latest.Spec.Replicas
+		}
+		original = utils.GetOriginalReplicas(latest)
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		replicas := *target.replicas
+		latest.Spec.Replicas = &replicas
+		latest.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+	})
+	if deploymentChanged {
+		r.recordGroupRevert(ctx, target.deployment.Namespace, target.deployment.Name, labels, previous, *target.replicas, original, err)
+	}
+	return changed || deploymentChanged, err
+}
+
+// hpaMinReplicas returns the minReplicas of hpa, defaulted to 1 by the API server
+func hpaMinReplicas(hpa *autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if hpa.Spec.MinReplicas == nil {
+		return 1
+	}
+	return *hpa.Spec.MinReplicas
+}
+
+// recordGroupRevert reports a target put back in metrics and the scaling report
+func (r *ReplicasOverrideReconciler) recordGroupRevert(ctx context.Context, namespace, name string, labels metrics.ScalingLabels, previous, restored, original int32, err error) {
+	if err != nil {
+		metrics.RecordScalingError(ctx, labels)
+	} else {
+		percentage := int32(100)
+		if original > 0 {
+			percentage = restored * 100 / original
+		}
+		metrics.RecordScaling(ctx, labels, previous, restored, percentage, false)
+	}
+	r.recordEvent(namespace, name, labels, original, previous, restored, false, err)
+}
+
+// setGroupStatus writes the group status of member unless it is unchanged
+func (r *ReplicasOverrideReconciler) setGroupStatus(ctx context.Context, member *dynamicscalingv1.ReplicasOverride, status dynamicscalingv1.OverrideGroupStatus, now time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &dynamicscalingv1.ReplicasOverride{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(member), latest); err != nil {
+			return err
+		}
+		next := status.DeepCopy()
+		next.LastTransitionTime = &metav1.Time{Time: now}
+		if current := latest.Status.Group; current != nil && current.Phase == next.Phase {
+			next.LastTransitionTime = current.LastTransitionTime
+			if equality.Semantic.DeepEqual(current, next) {
+				return nil
+			}
+		}
+		latest.Status.Group = next
+		return r.Status().Update(ctx, latest)
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestOverrideGroups(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	member := func(namespace, name string) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 200, Group: "checkout"},
+		}
+	}
+	deployment := func(namespace, name string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+
+	paused := member("shop", "cart")
+	paused.Annotations = map[string]string{utils.PausedAnnotation: "true"}
+	groups := overrideGroups([]dynamicscalingv1.ReplicasOverride{*member("payments", "api"), *paused, {}})
+	if len(groups) != 1 || len(groups["checkout"]) != 2 {
+		t.Fatalf("overrideGroups() = %v, want the two members of checkout", groups)
+	}
+	if got := groupHold(groups["checkout"], now); got != "Member shop/cart is paused" {
+		t.Errorf("groupHold() = %q, want the paused member", got)
+	}
+	if got := groupHold([]*dynamicscalingv1.ReplicasOverride{member("payments", "api")}, now); got != "" {
+		t.Errorf("groupHold() = %q, want no hold", got)
+	}
+
+	api, cart := member("payments", "api"), member("shop", "cart")
+	apiDeployment, cartDeployment := deployment("payments", "api", 2), deployment("shop", "cart", 3)
+	c := fakeclient.NewBuilder(api, cart, apiDeployment, cartDeployment).
+		Build()
+	r := newTestReconciler(c, nil)
+	members := map[string][]*dynamicscalingv1.ReplicasOverride{"checkout": {api, cart}}
+
+	// The api deployment is scaled, the cart one fails: api is put back
+	pass := newGroupPass()
+	pass.record(snapshotGroupTarget(api, apiDeployment, nil), nil)
+	pass.record(snapshotGroupTarget(cart, cartDeployment, nil), fmt.Errorf("admission webhook denied the request"))
+	scaled := apiDeployment.DeepCopy()
+	doubled := int32(4)
+	scaled.Spec.Replicas = &doubled
+	if err := c.Update(ctx, scaled); err != nil {
+		t.Fatal(err)
+	}
+	r.settleGroups(ctx, members, map[string]string{}, pass, now)
+
+	got := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(apiDeployment), got); err != nil {
+		t.Fatal(err)
+	}
+	if *got.Spec.Replicas != 2 {
+		t.Errorf("api replicas = %d, want 2 put back by the failed group", *got.Spec.Replicas)
+	}
+	for _, override := range []*dynamicscalingv1.ReplicasOverride{api, cart} {
+		latest := &dynamicscalingv1.ReplicasOverride{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), latest); err != nil {
+			t.Fatal(err)
+		}
+		status := latest.Status.Group
+		if status == nil || status.Phase != dynamicscalingv1.OverrideGroupReverted ||
+			status.Message != "Could not update shop/cart, put back 1 targets changed in the same pass" ||
+			len(status.Members) != 2 || status.Members[0] != "payments/api" {
+			t.Errorf("group status of %s = %+v, want Reverted with both members", override.Name, status)
+		}
+	}
+
+	// A pass updating every target applies the group
+	r.settleGroups(ctx, members, map[string]string{}, newGroupPass(), now.Add(time.Minute))
+	latest := &dynamicscalingv1.ReplicasOverride{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(cart), latest); err != nil {
+		t.Fatal(err)
+	}
+	if status := latest.Status.Group; status == nil || status.Phase != dynamicscalingv1.OverrideGroupApplied ||
+		!status.LastTransitionTime.Time.Equal(now.Add(time.Minute)) {
+		t.Errorf("group status = %+v, want Applied since the last pass", status)
+	}
+}
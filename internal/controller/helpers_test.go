@@ -0,0 +1,33 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// newTestReconciler returns a reconciler of c serving cfg, or the default
+// configuration if nil. The fake clients of the tests are built with
+// fakeclient.NewBuilder, shared with pkg/testing.
+func newTestReconciler(c client.Client, cfg *config.GlobalConfig) *ReplicasOverrideReconciler {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return &ReplicasOverrideReconciler{Client: c, Scheme: c.Scheme(), Config: config.NewStaticManager(cfg)}
+}
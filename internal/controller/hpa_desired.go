@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// scalesHPADesired returns true if the override scales the current demand of
+// HPAs instead of their original limits
+func scalesHPADesired(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override != nil && override.Spec.ScalingBasis == dynamicscalingv1.ScalingBasisHPADesired
+}
+
+// hpaDemand returns the replicas the metrics of an HPA currently ask for,
+// within its original limits. The desiredReplicas of the HPA status cannot be
+// used as is: it is floored at the min the controller raised, which would
+// ratchet the min up on every status change. The demand is computed from the
+// current metrics instead, like the HPA does, and desiredReplicas is only
+// trusted when it is above the min.
+func hpaDemand(hpa *autoscalingv2.HorizontalPodAutoscaler, originalMinReplicas, originalMaxReplicas int32) int32 {
+	var demand int32
+	if current := hpa.Status.CurrentReplicas; current > 0 {
+		targets := make(map[string]autoscalingv2.MetricTarget, len(hpa.Spec.Metrics))
+		for _, metric := range hpa.Spec.Metrics {
+			if key, target, ok := metricSpecTarget(metric); ok {
+				targets[key] = target
+			}
+		}
+		for _, status := range hpa.Status.CurrentMetrics {
+			key, value, ok := metricStatusValue(status)
+			if !ok {
+				continue
+			}
+			target, ok := targets[key]
+			if !ok {
+				continue
+			}
+			if ratio, ok := usageRatio(target, value); ok {
+				demand = max(demand, utils.BoundReplicas(math.Ceil(float64(current)*ratio)))
+			}
+		}
+	}
+	if demand == 0 && (hpa.Spec.MinReplicas == nil || hpa.Status.DesiredReplicas > *hpa.Spec.MinReplicas) {
+		demand = hpa.Status.DesiredReplicas
+	}
+	return min(max(demand, originalMinReplicas), originalMaxReplicas)
+}
+
+// metricSpecTarget returns the key identifying a metric of an HPA and its target
+func metricSpecTarget(metric autoscalingv2.MetricSpec) (string, autoscalingv2.MetricTarget, bool) {
+	switch {
+	case metric.Resource != nil:
+		return "Resource/" + metric.Resource.Name.String(), metric.Resource.Target, true
+	case metric.ContainerResource != nil:
+		return "ContainerResource/" + metric.ContainerResource.Container + "/" + metric.ContainerResource.Name.String(), metric.ContainerResource.Target, true
+	case metric.Pods != nil:
+		return "Pods/" + metric.Pods.Metric.Name, metric.Pods.Target, true
+	case metric.Object != nil:
+		return "Object/" + metric.Object.DescribedObject.Kind + "/" + metric.Object.DescribedObject.Name + "/" + metric.Object.Metric.Name, metric.Object.Target, true
+	case metric.External != nil:
+		return "External/" + metric.External.Metric.Name, metric.External.Target, true
+	}
+	return "", autoscalingv2.MetricTarget{}, false
+}
+
+// metricStatusValue returns the key identifying a metric of an HPA status and its current value
+func metricStatusValue(status autoscalingv2.MetricStatus) (string, autoscalingv2.MetricValueStatus, bool) {
+	switch {
+	case status.Resource != nil:
+		return "Resource/" + status.Resource.Name.String(), status.Resource.Current, true
+	case status.ContainerResource != nil:
+		return "ContainerResource/" + status.ContainerResource.Container + "/" + status.ContainerResource.Name.String(), status.ContainerResource.Current, true
+	case status.Pods != nil:
+		return "Pods/" + status.Pods.Metric.Name, status.Pods.Current, true
+	case status.Object != nil:
+		return "Object/" + status.Object.DescribedObject.Kind + "/" + status.Object.DescribedObject.Name + "/" + status.Object.Metric.Name, status.Object.Current, true
+	case status.External != nil:
+		return "External/" + status.External.Metric.Name, status.External.Current, true
+	}
+	return "", autoscalingv2.MetricValueStatus{}, false
+}
+
+// usageRatio returns the ratio of the current value of a metric to its target
+func usageRatio(target autoscalingv2.MetricTarget, current autoscalingv2.MetricValueStatus) (float64, bool) {
+	switch target.Type {
+	case autoscalingv2.UtilizationMetricType:
+		if target.AverageUtilization != nil && *target.AverageUtilization > 0 && current.AverageUtilization != nil {
+			return float64(*current.AverageUtilization) / float64(*target.AverageUtilization), true
+		}
+	case autoscalingv2.AverageValueMetricType:
+		if target.AverageValue != nil && target.AverageValue.MilliValue() > 0 && current.AverageValue != nil {
+			return float64(current.AverageValue.MilliValue()) / float64(target.AverageValue.MilliValue()), true
+		}
+	case autoscalingv2.ValueMetricType:
+		if target.Value != nil && target.Value.MilliValue() > 0 && current.Value != nil {
+			return float64(current.Value.MilliValue()) / float64(target.Value.MilliValue()), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestHPADemand(t *testing.T) {
+	cpu := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name:   corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(50)},
+		},
+	}
+	cpuStatus := func(utilization int32) autoscalingv2.MetricStatus {
+		return autoscalingv2.MetricStatus{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name:    corev1.ResourceCPU,
+				Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(utilization)},
+			},
+		}
+	}
+	hpa := func(minReplicas, current, desired int32, statuses ...autoscalingv2.MetricStatus) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: int32Ptr(minReplicas), MaxReplicas: 20, Metrics: []autoscalingv2.MetricSpec{cpu}},
+			Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+				CurrentReplicas: current, DesiredReplicas: desired, CurrentMetrics: statuses,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		hpa  *autoscalingv2.HorizontalPodAutoscaler
+		want int32
+	}{
+		{name: "from metrics", hpa: hpa(2, 5, 5, cpuStatus(80)), want: 8},
+		{name: "metrics below the raised min", hpa: hpa(6, 6, 6, cpuStatus(25)), want: 3},
+		{name: "desired above the min without metrics", hpa: hpa(2, 4, 7), want: 7},
+		{name: "desired pinned at the raised min without metrics", hpa: hpa(6, 6, 6), want: 2},
+		{name: "bounded by the original max", hpa: hpa(2, 10, 10, cpuStatus(200)), want: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hpaDemand(tt.hpa, 2, 12); got != tt.want {
+				t.Errorf("hpaDemand() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// 120% of a demand of 8 keeps the min at 10
+	r := newTestReconciler(fakeclient.NewBuilder().Build(), nil)
+	override := &dynamicscalingv1.ReplicasOverride{
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 120, ScalingBasis: dynamicscalingv1.ScalingBasisHPADesired},
+	}
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+	minReplicas, maxReplicas, _, _ := r.desiredHPALimits(context.Background(), config.DefaultConfig(), hpa(2, 5, 5, cpuStatus(80)), workload, &workload.Spec.Template, override, 2, 12)
+	if minReplicas != 10 || maxReplicas != 14 {
+		t.Errorf("desiredHPALimits() = (%d, %d), want (10, 14)", minReplicas, maxReplicas)
+	}
+}
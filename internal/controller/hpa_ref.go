@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// HPAReferenceConditionType is the ReplicasOverride condition reporting
+// whether the HPA named by its hpaRef exists
+const HPAReferenceConditionType = "HPAReferenceResolved"
+
+// hpaRefKey returns the HPA named by the hpaRef of override, which defaults
+// to the namespace of the override
+func hpaRefKey(override *dynamicscalingv1.ReplicasOverride) types.NamespacedName {
+	namespace := override.Spec.HPARef.Namespace
+	if namespace == "" {
+		namespace = override.Namespace
+	}
+	return types.NamespacedName{Name: override.Spec.HPARef.Name, Namespace: namespace}
+}
+
+// scalesDeployment returns true if hpa scales an apps/v1 Deployment
+func scalesDeployment(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return hpa.Spec.ScaleTargetRef.Kind == "Deployment" && hpa.Spec.ScaleTargetRef.APIVersion == "apps/v1"
+}
+
+// hpaRefCondition reports whether the hpaRef of override resolves to hpa, nil
+// when the HPA was not found. Overrides only scale objects of their own namespace.
+func hpaRefCondition(override *dynamicscalingv1.ReplicasOverride, hpa *autoscalingv2.HorizontalPodAutoscaler) metav1.Condition {
+	key := hpaRefKey(override)
+	condition := metav1.Condition{
+		Type:               HPAReferenceConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Found",
+		ObservedGeneration: override.Generation,
+	}
+	switch {
+	case key.Namespace != override.Namespace:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NamespaceMismatch"
+		condition.Message = fmt.Sprintf("HorizontalPodAutoscaler %s is not in the namespace of the override", key)
+	case hpa == nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotFound"
+		condition.Message = fmt.Sprintf("HorizontalPodAutoscaler %s not found", key)
+	default:
+		target := hpa.Spec.ScaleTargetRef
+		condition.Message = fmt.Sprintf("HorizontalPodAutoscaler %s scales %s %s", key, target.Kind, target.Name)
+	}
+	return condition
+}
+
+// referencedHPA returns the HPA named by the hpaRef of override, or nil if the
+// override has none, it is in another namespace or it does not exist
+func (r *ReplicasOverrideReconciler) referencedHPA(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	if override.Spec.HPARef == nil {
+		return nil, nil
+	}
+	key := hpaRefKey(override)
+	if key.Namespace != override.Namespace {
+		return nil, nil
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := r.Get(ctx, key, hpa); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hpa, nil
+}
+
+// processHPARefs validates the hpaRef of every override and scales the
+// referenced HPAs that do not scale a Deployment, e.g. those of a StatefulSet
+// or of a custom resource exposing the scale subresource. HPAs of a
+// Deployment are scaled with their Deployment. It returns nextCheck, moved
+// earlier if a blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processHPARefs(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		if override.Spec.HPARef == nil || override.Spec.CanaryScope != "" || rollbackRequested(override) {
+			continue
+		}
+
+		hpa, err := r.referencedHPA(ctx, override)
+		if err != nil {
+			log.Error(err, "Failed to get referenced HPA",
+				"override", override.Name,
+				"namespace", override.Namespace)
+			continue
+		}
+		if meta.SetStatusCondition(&override.Status.Conditions, hpaRefCondition(override, hpa)) {
+			if err := r.Status().Update(ctx, override); err != nil {
+				log.Error(err, "Failed to update override status",
+					"override", override.Name,
+					"namespace", override.Namespace)
+			}
+		}
+
+		if hpa == nil || scalesDeployment(hpa) || !targetsKind(override, metrics.TargetKindHPA) ||
+			isWorkloadIgnored("HorizontalPodAutoscaler", hpa, ignoreList.Items) {
+			continue
+		}
+		if skip, until := r.overrideFrozen(ctx, override); skip {
+			if !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		// The HPA stands in for the workload it scales, which has no pod template we know of
+		if err := r.processHPA(ctx, hpa, hpa, &corev1.PodTemplateSpec{}, override); err != nil && !changeDeferred(err) {
+			log.Error(err, "Failed to process referenced HPA",
+				"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name),
+				"override", override.Name,
+				"reason", recordFailure(hpa.Namespace, metrics.TargetKindHPA, override, err))
+		}
+	}
+	return nextCheck
+}
+
+// restoreReferencedHPA restores the HPA named by the hpaRef of override if it
+// does not scale a Deployment, those are restored with their Deployment. It
+// returns false if there was nothing to restore.
+func (r *ReplicasOverrideReconciler) restoreReferencedHPA(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) (bool, error) {
+	hpa, err := r.referencedHPA(ctx, override)
+	if err != nil {
+		return false, err
+	}
+	if hpa == nil || scalesDeployment(hpa) {
+		return false, nil
+	}
+	if _, scaled := hpa.Annotations[utils.OriginalMinReplicasAnnotation]; !scaled {
+		return false, nil
+	}
+	_, err = r.restoreHPA(ctx, override, hpa)
+	return true, err
+}
+
+// hpaRefRequests maps an HPA that does not scale a Deployment to the
+// overrides referencing it by hpaRef
+func (r *ReplicasOverrideReconciler) hpaRefRequests(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) []reconcile.Request {
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(hpa.Namespace)); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range overrideList.Items {
+		o := &overrideList.Items[i]
+		if o.Spec.HPARef != nil && hpaRefKey(o) == client.ObjectKeyFromObject(hpa) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestHPARefCondition(t *testing.T) {
+	override := func(namespace string) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"},
+			Spec:       dynamicscalingv1.ReplicasOverrideSpec{HPARef: &dynamicscalingv1.HPAReference{Name: "db-hpa", Namespace: namespace}},
+		}
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-hpa", Namespace: "shop"},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "example.com/v1", Kind: "Worker", Name: "db"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		override   *dynamicscalingv1.ReplicasOverride
+		hpa        *autoscalingv2.HorizontalPodAutoscaler
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{name: "found", override: override(""), hpa: hpa, wantStatus: metav1.ConditionTrue, wantReason: "Found"},
+		{name: "not found", override: override("shop"), wantStatus: metav1.ConditionFalse, wantReason: "NotFound"},
+		{name: "other namespace", override: override("billing"), hpa: hpa, wantStatus: metav1.ConditionFalse, wantReason: "NamespaceMismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hpaRefCondition(tt.override, tt.hpa)
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("hpaRefCondition() = (%s, %s), want (%s, %s)", got.Status, got.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
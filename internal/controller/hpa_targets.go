@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// hpaTarget is the workload scaled by an HPA of a kind other than Deployment
+type hpaTarget struct {
+	kind string
+	// object is the target, or the HPA standing in for it when the controller
+	// may not read its kind
+	object metav1.Object
+	// template is the pod template of the target, empty when it has none
+	template *corev1.PodTemplateSpec
+}
+
+// resolveHPATarget reads the object scaled by hpa generically from its
+// scaleTargetRef, so StatefulSets, Rollouts and any custom resource exposing
+// the scale subresource are matched by their own labels. It returns nil when
+// the target does not exist.
+func (r *ReplicasOverrideReconciler) resolveHPATarget(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*hpaTarget, error) {
+	ref := hpa.Spec.ScaleTargetRef
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gv.WithKind(ref.Kind))
+	err = r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: hpa.Namespace}, object)
+	switch {
+	case errors.IsNotFound(err):
+		return nil, nil
+	case errors.IsForbidden(err) || meta.IsNoMatchError(err):
+		// Without access to the kind, the HPA stands in for the workload it scales
+		return &hpaTarget{kind: ref.Kind, object: hpa, template: &corev1.PodTemplateSpec{}}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	if spec, found, _ := unstructured.NestedMap(object.Object, "spec", "template"); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, template); err != nil {
+			template = &corev1.PodTemplateSpec{}
+		}
+	}
+	return &hpaTarget{kind: ref.Kind, object: object, template: template}, nil
+}
+
+// scaledElsewhere returns true if an HPA of a kind other than Deployment is
+// scaled through another path: with the Deployment its Rollout references
+// through spec.workloadRef, or through the hpaRef of an override
+func scaledElsewhere(hpa *autoscalingv2.HorizontalPodAutoscaler, target *hpaTarget, overrides []dynamicscalingv1.ReplicasOverride) bool {
+	if u, ok := target.object.(*unstructured.Unstructured); ok && target.kind == "Rollout" {
+		if _, found, _ := unstructured.NestedMap(u.Object, "spec", "workloadRef"); found {
+			return true
+		}
+	}
+	for i := range overrides {
+		if overrides[i].Spec.HPARef != nil && hpaRefKey(&overrides[i]) == client.ObjectKeyFromObject(hpa) {
+			return true
+		}
+	}
+	return false
+}
+
+// processHPATargets scales the HPAs of a namespace whose scaleTargetRef is not
+// a Deployment, e.g. a StatefulSet, an Argo Rollout or a scalable custom
+// resource, matching overrides and ignore rules against the object they
+// scale. StatefulSets are only considered when enabled in the config. It
+// returns nextCheck, moved earlier if a blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processHPATargets(ctx context.Context, cfg *config.GlobalConfig, namespace string, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list HPAs in namespace", "namespace", namespace)
+		return nextCheck
+	}
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return nextCheck
+	}
+
+	for i := range hpaList.Items {
+		hpa := &hpaList.Items[i]
+		ref := hpa.Spec.ScaleTargetRef
+		if scalesDeployment(hpa) || (ref.Kind == "StatefulSet" && !cfg.StatefulSets.Enabled) ||
+			isWorkloadIgnored("HorizontalPodAutoscaler", hpa, ignoreList.Items) {
+			continue
+		}
+
+		target, err := r.resolveHPATarget(ctx, hpa)
+		if err != nil {
+			log.Error(err, "Failed to get the target of HPA",
+				"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name),
+				"kind", ref.Kind,
+				"target", ref.Name)
+			continue
+		}
+		if target == nil || scaledElsewhere(hpa, target, overrideList.Items) ||
+			isWorkloadIgnored(target.kind, target.object, ignoreList.Items) {
+			continue
+		}
+
+		override := selectorOverride(overrideList.Items, target.object)
+		if !targetsKind(override, metrics.TargetKindHPA) || r.globalScalingHeld(ctx, hpa, override) {
+			continue
+		}
+		if skip, until := r.overrideFrozen(ctx, override); skip {
+			if !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+		if young, until := r.workloadTooYoung(ctx, target.kind, target.object, override, time.Now()); young {
+			if until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		if err := r.processHPA(ctx, hpa, target.object, target.template, override); err != nil && !changeDeferred(err) {
+			log.Error(err, "Failed to process HPA",
+				"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name),
+				"kind", ref.Kind,
+				"target", ref.Name,
+				"hasOverride", override != nil,
+				"reason", recordFailure(hpa.Namespace, metrics.TargetKindHPA, override, err))
+		}
+	}
+	return nextCheck
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestHPATargets(t *testing.T) {
+	ctx := context.Background()
+	minReplicas := int32(2)
+	hpa := func(name, apiVersion, kind, target string, labels map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: apiVersion, Kind: kind, Name: target},
+				MinReplicas:    &minReplicas,
+				MaxReplicas:    4,
+			},
+		}
+	}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop", Labels: map[string]string{"tier": "data"}}}
+	cache := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "shop", Labels: map[string]string{"tier": "data"}}}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 200,
+			Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "data"}},
+		},
+	}
+	ignore := &dynamicscalingv1.GlobalReplicasIgnore{Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+		IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "StatefulSet", Name: "cache", Namespace: "shop"}},
+	}}
+	c := fakeclient.NewBuilder(sts, cache, override,
+		hpa("db", "apps/v1", "StatefulSet", "db", nil),
+		hpa("cache", "apps/v1", "StatefulSet", "cache", nil),
+		// The controller may not read Workers, their HPA stands in for them
+		hpa("worker", "example.com/v1", "Worker", "worker", map[string]string{"tier": "data"}),
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "Worker" {
+				return apierrors.NewForbidden(schema.GroupResource{Group: "example.com", Resource: "workers"}, key.Name, nil)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	cfg := config.DefaultConfig()
+	cfg.StatefulSets.Enabled = true
+	r := newTestReconciler(c, cfg)
+
+	r.processHPATargets(ctx, cfg, "shop", &dynamicscalingv1.GlobalReplicasIgnoreList{Items: []dynamicscalingv1.GlobalReplicasIgnore{*ignore}}, time.Now().Add(time.Hour))
+	for name, want := range map[string]int32{"db": 4, "cache": 2, "worker": 4} {
+		got := &autoscalingv2.HorizontalPodAutoscaler{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, got); err != nil {
+			t.Fatal(err)
+		}
+		if *got.Spec.MinReplicas != want {
+			t.Errorf("HPA %s minReplicas = %d, want %d", name, *got.Spec.MinReplicas, want)
+		}
+		if want != minReplicas && got.Annotations[utils.OriginalMinReplicasAnnotation] != "2" {
+			t.Errorf("HPA %s original min = %q, want 2", name, got.Annotations[utils.OriginalMinReplicasAnnotation])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// allowsIgnored returns true if an override of namespace is allowed to scale
+// the deployments ignore rules exclude
+func allowsIgnored(overrides []dynamicscalingv1.ReplicasOverride, namespace string) bool {
+	for i := range overrides {
+		if overrides[i].Namespace == namespace && overrides[i].Spec.IgnorePolicy == dynamicscalingv1.IgnorePolicyOverride {
+			return true
+		}
+	}
+	return false
+}
+
+// overridingIgnore returns the override scaling deployment despite the ignore
+// rules excluding it: the first one targeting it, if its ignorePolicy is override
+func overridingIgnore(overrides []dynamicscalingv1.ReplicasOverride, hpas []autoscalingv2.HorizontalPodAutoscaler, deployment *appsv1.Deployment) *dynamicscalingv1.ReplicasOverride {
+	matches := deploymentOverrides(overrides, hpas, deployment)
+	if len(matches) == 0 || matches[0].Spec.IgnorePolicy != dynamicscalingv1.IgnorePolicyOverride {
+		return nil
+	}
+	return matches[0]
+}
+
+// inNamespace returns the objects of items in namespace
+func inNamespace[T any, PT interface {
+	*T
+	GetNamespace() string
+}](items []T, namespace string) []T {
+	var found []T
+	for i := range items {
+		if PT(&items[i]).GetNamespace() == namespace {
+			found = append(found, items[i])
+		}
+	}
+	return found
+}
+
+// findIgnoreExceptions returns the deployments of a namespace that are ignored
+// but scaled anyway by an override, keyed by the name of the override
+func findIgnoreExceptions(overrides []dynamicscalingv1.ReplicasOverride, hpas []autoscalingv2.HorizontalPodAutoscaler, deployments []appsv1.Deployment, ignores []dynamicscalingv1.GlobalReplicasIgnore, ignored func(*appsv1.Deployment) bool) map[string][]dynamicscalingv1.IgnoreException {
+	exceptions := make(map[string][]dynamicscalingv1.IgnoreException)
+	for i := range deployments {
+		deployment := &deployments[i]
+		if !ignored(deployment) {
+			continue
+		}
+		override := overridingIgnore(overrides, hpas, deployment)
+		if override == nil {
+			continue
+		}
+		exception := dynamicscalingv1.IgnoreException{
+			Name:      deployment.Name,
+			Namespace: deployment.Namespace,
+			IgnoredBy: []string{},
+		}
+		for j := range ignores {
+			if excluded, _ := utils.ShouldIgnoreDeployment(deployment, &ignores[j]); excluded {
+				exception.IgnoredBy = append(exception.IgnoredBy, ignores[j].Name)
+			}
+		}
+		exceptions[override.Name] = append(exceptions[override.Name], exception)
+	}
+	return exceptions
+}
+
+// reportIgnoreExceptions records in the status of every override of a
+// namespace the ignored deployments it scales anyway
+func (r *ReplicasOverrideReconciler) reportIgnoreExceptions(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride, exceptions map[string][]dynamicscalingv1.IgnoreException) {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		excepted := exceptions[override.Name]
+		if equality.Semantic.DeepEqual(override.Status.IgnoreExceptions, excepted) {
+			continue
+		}
+		override.Status.IgnoreExceptions = excepted
+
+		if len(excepted) > 0 {
+			log.Info("Override scales deployments excluded by ignore rules",
+				"override", override.Name,
+				"namespace", override.Namespace,
+				"deployments", len(excepted))
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestIgnorePolicy(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		}
+	}
+	ignore := &dynamicscalingv1.GlobalReplicasIgnore{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec:       dynamicscalingv1.GlobalReplicasIgnoreSpec{IgnoreNamespaces: []string{"shop"}},
+	}
+	override := func(name, target string, policy dynamicscalingv1.IgnorePolicy) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: target},
+				OverrideType:       "override",
+				ReplicasPercentage: 200,
+				IgnorePolicy:       policy,
+			},
+		}
+	}
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		deployment("api"), deployment("web"), ignore,
+		override("api-sale", "api", dynamicscalingv1.IgnorePolicyOverride),
+		override("web-sale", "web", ""),
+	).
+		WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}, &dynamicscalingv1.GlobalReplicasIgnore{}).
+		Build()
+	r := newTestReconciler(c, nil)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	for name, want := range map[string]int32{"api": 4, "web": 2} {
+		var d appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, &d); err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if *d.Spec.Replicas != want {
+			t.Errorf("replicas of %s = %d, want %d", name, *d.Spec.Replicas, want)
+		}
+	}
+
+	// The exception is recorded in the status of the override and of the ignore rule
+	var got dynamicscalingv1.ReplicasOverride
+	if err := c.Get(ctx, types.NamespacedName{Name: "api-sale", Namespace: "shop"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []dynamicscalingv1.IgnoreException{{Name: "api", Namespace: "shop", IgnoredBy: []string{"platform"}}}
+	if !reflect.DeepEqual(got.Status.IgnoreExceptions, want) {
+		t.Errorf("ignoreExceptions = %+v, want %+v", got.Status.IgnoreExceptions, want)
+	}
+	ignoreReconciler := &GlobalReplicasIgnoreReconciler{Client: c, Scheme: c.Scheme()}
+	if _, err := ignoreReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "platform"}}); err != nil {
+		t.Fatalf("Reconcile() of the ignore rule failed: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "platform"}, ignore); err != nil {
+		t.Fatal(err)
+	}
+	overridden := map[string]string{}
+	for _, ignored := range ignore.Status.IgnoredDeployments {
+		overridden[ignored.Name] = ignored.OverriddenBy
+	}
+	if !reflect.DeepEqual(overridden, map[string]string{"api": "api-sale", "web": ""}) {
+		t.Errorf("overriddenBy of the ignored deployments = %v", overridden)
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// InventoryPath is the path of the managed workloads endpoint, served by the
+// metrics server behind the same authentication and authorization
+const InventoryPath = "/inventory"
+
+// ManagedWorkload is a workload whose replicas, or HPA limits, the controller manages
+type ManagedWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Mode is direct when the replicas are scaled, hpa when the limits of HPA are
+	Mode string `json:"mode"`
+	HPA  string `json:"hpa,omitempty"`
+
+	// Rule is the override behind the last change, GlobalOverride for the
+	// global config, and Trigger what caused it
+	Rule    string `json:"rule"`
+	Trigger string `json:"trigger,omitempty"`
+
+	// OriginalReplicas and Replicas are the replicas recorded before the
+	// controller scaled the workload and its current replicas
+	OriginalReplicas int32 `json:"originalReplicas"`
+	Replicas         int32 `json:"replicas"`
+
+	// OriginalMinReplicas, OriginalMaxReplicas, MinReplicas and MaxReplicas
+	// are the recorded and current limits of the HPA in hpa mode
+	OriginalMinReplicas int32 `json:"originalMinReplicas,omitempty"`
+	OriginalMaxReplicas int32 `json:"originalMaxReplicas,omitempty"`
+	MinReplicas         int32 `json:"minReplicas,omitempty"`
+	MaxReplicas         int32 `json:"maxReplicas,omitempty"`
+
+	// LastUpdate is when the controller last scaled the workload, if recorded
+	LastUpdate string `json:"lastUpdate,omitempty"`
+}
+
+// UnscalableWorkload is a workload the controller cannot scale, listed so the
+// inventory accounts for all pods
+type UnscalableWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Reason is why the controller leaves the workload alone
+	Reason string `json:"reason"`
+
+	// Nodes is the number of nodes a DaemonSet should run a pod on, and Pods
+	// the number of pods it runs
+	Nodes int32 `json:"nodes"`
+	Pods  int32 `json:"pods"`
+
+	// Overrides are the overrides of the namespace selecting the workload,
+	// which leave it alone
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+// Inventory lists every workload under management, read from the annotations
+// the controller records on them
+type Inventory struct {
+	GeneratedAt metav1.Time `json:"generatedAt"`
+
+	// Conditions holds the Ready condition of the global config, False until
+	// it is enabled and its startup grace period is over, the Throttled
+	// condition of the scale budget and the Audited condition of the startup audit
+	Conditions []metav1.Condition `json:"conditions"`
+
+	// Audit is the report of the startup audit, once it completed
+	Audit *AuditReport `json:"audit,omitempty"`
+
+	Workloads []ManagedWorkload `json:"workloads"`
+
+	// Unscalable lists the DaemonSets, which run one pod per node and have no
+	// replicas to scale, and UnscalablePods counts their pods
+	Unscalable     []UnscalableWorkload `json:"unscalable"`
+	UnscalablePods int32                `json:"unscalablePods"`
+}
+
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=list;watch
+
+// Inventory returns the workloads under management in namespace, or in all
+// namespaces when it is empty, and the DaemonSets it cannot scale.
+// StatefulSets are only listed when they are scaled.
+func (r *ReplicasOverrideReconciler) Inventory(ctx context.Context, namespace string) (*Inventory, error) {
+	now := time.Now()
+	inventory := &Inventory{GeneratedAt: metav1.NewTime(now.UTC()), Workloads: []ManagedWorkload{}, Unscalable: []UnscalableWorkload{}}
+	inventory.Conditions = []metav1.Condition{r.globalScalingCondition(now), r.scaleBudgetCondition(now), r.auditCondition()}
+	inventory.Audit = r.Audit.Report()
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpas, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HPAs: %w", err)
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if !isManaged(deployment) {
+			continue
+		}
+		workload := managedWorkload("Deployment", deployment, deployment.Spec.Replicas)
+		workload.OriginalReplicas = utils.GetOriginalReplicas(deployment)
+		if workload.Mode == "hpa" {
+			for j := range hpas.Items {
+				hpa := &hpas.Items[j]
+				if hpa.Namespace == deployment.Namespace && scalesDeployment(hpa) && hpa.Spec.ScaleTargetRef.Name == deployment.Name {
+					workload.HPA = hpa.Name
+					workload.OriginalMinReplicas, workload.OriginalMaxReplicas = utils.GetOriginalHPALimits(hpa)
+					workload.MinReplicas, workload.MaxReplicas = hpaMinReplicas(hpa), hpa.Spec.MaxReplicas
+					break
+				}
+			}
+		}
+		inventory.Workloads = append(inventory.Workloads, workload)
+	}
+
+	if cfg := r.Config.GetConfig(); cfg != nil && cfg.StatefulSets.Enabled {
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := r.List(ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list StatefulSets: %w", err)
+		}
+		for i := range statefulSets.Items {
+			sts := &statefulSets.Items[i]
+			if !isManaged(sts) {
+				continue
+			}
+			workload := managedWorkload("StatefulSet", sts, sts.Spec.Replicas)
+			workload.OriginalReplicas, _ = utils.ParseReplicas(sts.Annotations[utils.OriginalReplicasAnnotation])
+			inventory.Workloads = append(inventory.Workloads, workload)
+		}
+	}
+
+	if err := r.listUnscalable(ctx, namespace, inventory); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(inventory.Workloads, func(i, j int) bool {
+		a, b := inventory.Workloads[i], inventory.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+	return inventory, nil
+}
+
+// listUnscalable adds the DaemonSets of namespace, all namespaces when empty,
+// to inventory with the overrides selecting them
+func (r *ReplicasOverrideReconciler) listUnscalable(ctx context.Context, namespace string, inventory *Inventory) error {
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := r.List(ctx, daemonSets, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list DaemonSets: %w", err)
+	}
+	if len(daemonSets.Items) == 0 {
+		return nil
+	}
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrides, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list overrides: %w", err)
+	}
+
+	for i := range daemonSets.Items {
+		ds := &daemonSets.Items[i]
+		workload := UnscalableWorkload{
+			Kind:      "DaemonSet",
+			Namespace: ds.Namespace,
+			Name:      ds.Name,
+			Reason:    "DaemonSets run one pod per matching node and have no replicas to scale",
+			Nodes:     ds.Status.DesiredNumberScheduled,
+			Pods:      ds.Status.CurrentNumberScheduled,
+		}
+		for j := range overrides.Items {
+			if selectsDaemonSet(&overrides.Items[j], ds) {
+				workload.Overrides = append(workload.Overrides, overrides.Items[j].Name)
+			}
+		}
+		inventory.Unscalable = append(inventory.Unscalable, workload)
+		inventory.UnscalablePods += workload.Pods
+	}
+	sort.Slice(inventory.Unscalable, func(i, j int) bool {
+		a, b := inventory.Unscalable[i], inventory.Unscalable[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return nil
+}
+
+// selectsDaemonSet returns true if override would target ds were it a
+// deployment, by its deploymentRef or selector
+func selectsDaemonSet(override *dynamicscalingv1.ReplicasOverride, ds *appsv1.DaemonSet) bool {
+	if override.Namespace != ds.Namespace {
+		return false
+	}
+	if ref := override.Spec.DeploymentRef; ref != nil {
+		return ref.Name == ds.Name && (ref.Namespace == "" || ref.Namespace == ds.Namespace)
+	}
+	return matcher.SelectsLabels(override, ds.Labels)
+}
+
+// isManaged returns true if the controller scaled obj for an override or the global config
+func isManaged(obj client.Object) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[utils.ManagedAnnotation] == "true" || annotations[utils.GlobalConfigManagedAnnotation] == "true"
+}
+
+// managedWorkload returns the inventory entry of obj, its owning rule being
+// read from the change reason of its last scaling
+func managedWorkload(kind string, obj client.Object, replicas *int32) ManagedWorkload {
+	annotations := obj.GetAnnotations()
+	workload := ManagedWorkload{
+		Kind:       kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Mode:       "direct",
+		Rule:       metrics.GlobalOverride,
+		Replicas:   1,
+		LastUpdate: annotations[utils.LastUpdateAnnotation],
+	}
+	if annotations[utils.ManagementModeAnnotation] == "hpa" {
+		workload.Mode = "hpa"
+	}
+	if replicas != nil {
+		workload.Replicas = *replicas
+	}
+
+	var reason changeReason
+	if err := json.Unmarshal([]byte(annotations[utils.ChangeReasonAnnotation]), &reason); err == nil && reason.Override != "" {
+		workload.Rule, workload.Trigger = reason.Override, reason.Trigger
+	}
+	return workload
+}
+
+// InventoryHandler serves Inventory on GET requests, restricted to the
+// namespace of the namespace query parameter if set
+func (r *ReplicasOverrideReconciler) InventoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "the inventory is read with GET", http.StatusMethodNotAllowed)
+			return
+		}
+
+		inventory, err := r.Inventory(req.Context(), req.URL.Query().Get("namespace"))
+		if err != nil {
+			log.FromContext(req.Context()).Error(err, "Failed to build the managed workloads inventory")
+			http.Error(w, fmt.Sprintf("inventory failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(inventory)
+	})
+}
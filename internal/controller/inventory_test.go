@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestInventory(t *testing.T) {
+	c := fakeclient.NewBuilder(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{
+				utils.ManagedAnnotation:          "true",
+				utils.ManagementModeAnnotation:   "direct",
+				utils.OriginalReplicasAnnotation: "4",
+				utils.ChangeReasonAnnotation:     `{"override":"sale","trigger":"schedule","percentage":150}`,
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(6)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Annotations: map[string]string{
+				utils.GlobalConfigManagedAnnotation: "true",
+				utils.ManagementModeAnnotation:      "hpa",
+				utils.OriginalReplicasAnnotation:    "2",
+			}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-hpa", Namespace: "shop", Annotations: map[string]string{
+				utils.OriginalMinReplicasAnnotation: "2",
+				utils.OriginalMaxReplicasAnnotation: "10",
+			}},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MinReplicas:    int32Ptr(3),
+				MaxReplicas:    15,
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+		},
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "log-agent", Namespace: "shop", Labels: map[string]string{"team": "shop"}},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 5, CurrentNumberScheduled: 4},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "shop"}},
+				OverrideType:       "override",
+				ReplicasPercentage: 150,
+			},
+		},
+	).Build()
+	r := newTestReconciler(c, nil)
+
+	recorder := httptest.NewRecorder()
+	r.InventoryHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, InventoryPath+"?namespace=shop", nil))
+	var inventory Inventory
+	if err := json.NewDecoder(recorder.Body).Decode(&inventory); err != nil {
+		t.Fatalf("decoding the response: %v", err)
+	}
+	want := []ManagedWorkload{
+		{Kind: "Deployment", Namespace: "shop", Name: "api", Mode: "hpa", HPA: "api-hpa", Rule: "global",
+			OriginalReplicas: 2, Replicas: 3, OriginalMinReplicas: 2, OriginalMaxReplicas: 10, MinReplicas: 3, MaxReplicas: 15},
+		{Kind: "Deployment", Namespace: "shop", Name: "web", Mode: "direct", Rule: "sale", Trigger: "schedule",
+			OriginalReplicas: 4, Replicas: 6},
+	}
+	if !reflect.DeepEqual(inventory.Workloads, want) {
+		t.Errorf("workloads = %+v, want %+v", inventory.Workloads, want)
+	}
+	// DaemonSets are reported as unscalable with their pods and the overrides selecting them
+	unscalable := []UnscalableWorkload{{Kind: "DaemonSet", Namespace: "shop", Name: "log-agent",
+		Reason: "DaemonSets run one pod per matching node and have no replicas to scale", Nodes: 5, Pods: 4, Overrides: []string{"sale"}}}
+	if !reflect.DeepEqual(inventory.Unscalable, unscalable) || inventory.UnscalablePods != 4 {
+		t.Errorf("unscalable = %+v with %d pods, want %+v with 4 pods", inventory.Unscalable, inventory.UnscalablePods, unscalable)
+	}
+
+	recorder = httptest.NewRecorder()
+	r.InventoryHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, InventoryPath, nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", recorder.Code, http.StatusMethodNotAllowed)
+	}
+}
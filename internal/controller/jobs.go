@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// targetKindJob is the metrics target kind of Jobs
+const targetKindJob = "Job"
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;update;patch
+
+// processJobs scales the parallelism of the running Jobs of a namespace
+// matched by an override selector, or by the global config. Jobs of a paused
+// or rolled back override get their original parallelism back. It returns
+// nextCheck, moved earlier if a blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processJobs(ctx context.Context, namespace string, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list jobs in namespace", "namespace", namespace)
+		return nextCheck
+	}
+	if len(jobs.Items) == 0 {
+		return nextCheck
+	}
+
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return nextCheck
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		// Suspended Jobs and Jobs parked at zero parallelism are left to their owner
+		if isJobFinished(job) || job.Spec.Parallelism == nil || *job.Spec.Parallelism == 0 ||
+			(job.Spec.Suspend != nil && *job.Spec.Suspend) || isWorkloadIgnored("Job", job, ignoreList.Items) {
+			continue
+		}
+
+		override := selectorOverride(overrideList.Items, job)
+		if override != nil && (isPaused(override) || rollbackRequested(override)) {
+			if err := r.restoreJob(ctx, override, job); err != nil {
+				log.Error(err, "Failed to restore job parallelism",
+					"job", fmt.Sprintf("%s/%s", job.Namespace, job.Name))
+			}
+			continue
+		}
+		if !targetsKind(override, targetKindJob) || r.globalScalingHeld(ctx, job, override) {
+			continue
+		}
+		if skip, until := r.overrideFrozen(ctx, override); skip {
+			if !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		if err := r.processJob(ctx, job, override); err != nil {
+			log.Error(err, "Failed to process job",
+				"job", fmt.Sprintf("%s/%s", job.Namespace, job.Name),
+				"hasOverride", override != nil,
+				"reason", recordFailure(job.Namespace, targetKindJob, override, err))
+		}
+	}
+	return nextCheck
+}
+
+// processJob scales the parallelism of a single Job
+func (r *ReplicasOverrideReconciler) processJob(ctx context.Context, job *batchv1.Job, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+
+	cfg := r.configFor(ctx, job.Namespace)
+	if cfg == nil {
+		return fmt.Errorf("global config not found")
+	}
+
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	current := *job.Spec.Parallelism
+	r.repairOriginals(ctx, "Job", job, override)
+	if _, exists := job.Annotations[utils.OriginalParallelismAnnotation]; !exists {
+		job.Annotations[utils.OriginalParallelismAnnotation] = strconv.FormatInt(int64(current), 10)
+	}
+	original := getOriginalParallelism(job)
+
+	desired, explanation := r.desiredReplicas(ctx, cfg, job, &job.Spec.Template, override, original, current)
+	percentage, trigger, clamped := explanation.Percentage, explanation.Trigger, explanation.Clamped
+	target := jobParallelism(job, desired)
+	if target != desired {
+		clamped = true
+	}
+	explanation.SetReplicas(target, clamped)
+	if target == current {
+		log.V(1).Info("Job already at desired parallelism, skipping update",
+			"job", fmt.Sprintf("%s/%s", job.Namespace, job.Name),
+			"parallelism", current)
+		return r.updateExplanation(ctx, job, explanation)
+	}
+
+	if override != nil {
+		job.Annotations[utils.OverrideControllerAnnotation] = "true"
+		job.Annotations[utils.ManagedAnnotation] = "true"
+	} else {
+		job.Annotations[utils.GlobalConfigManagedAnnotation] = "true"
+	}
+	job.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	job.Annotations[utils.ExplainAnnotation] = explanation.JSON()
+	job.Spec.Parallelism = &target
+
+	log.Info("Updating job parallelism",
+		"job", fmt.Sprintf("%s/%s", job.Namespace, job.Name),
+		"original", original,
+		"previous", current,
+		"target", target,
+		"percentage", percentage)
+
+	labels := scalingLabels(job.Namespace, targetKindJob, override)
+	labels.Trigger = trigger
+	if err := r.Update(ctx, job, newChangeReason(labels, &percentage).record(job)); err != nil {
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(job.Namespace, job.Name, labels, original, current, target, clamped, err)
+		return err
+	}
+	metrics.RecordScaling(ctx, labels, current, target, percentage, clamped)
+	r.recordEvent(job.Namespace, job.Name, labels, original, current, target, clamped, nil)
+	return nil
+}
+
+// restoreJob sets the parallelism of a Job back to its original value and
+// forgets it, so a later scaling starts from the value the owner set
+func (r *ReplicasOverrideReconciler) restoreJob(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, job *batchv1.Job) error {
+	if _, exists := job.Annotations[utils.OriginalParallelismAnnotation]; !exists {
+		return nil
+	}
+	original := getOriginalParallelism(job)
+
+	labels := scalingLabels(job.Namespace, targetKindJob, override)
+	labels.Trigger = metrics.TriggerRollback
+	var previous int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &batchv1.Job{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(job), latest); err != nil {
+			return err
+		}
+		if latest.Spec.Parallelism != nil {
+			previous = *latest.Spec.Parallelism
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		latest.Spec.Parallelism = &original
+		delete(latest.Annotations, utils.OriginalParallelismAnnotation)
+		latest.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+	})
+
+	r.recordRollback(ctx, job.Namespace, job.Name, labels, original, previous, err)
+	return err
+}
+
+// jobParallelism bounds the desired parallelism of a Job: at least one pod so
+// the Job keeps progressing, and no more pods than completions, since extra
+// pods of an indexed or fixed completion count Job would never get work
+func jobParallelism(job *batchv1.Job, desired int32) int32 {
+	if desired < 1 {
+		desired = 1
+	}
+	if job.Spec.Completions != nil && *job.Spec.Completions > 0 && desired > *job.Spec.Completions {
+		desired = *job.Spec.Completions
+	}
+	return desired
+}
+
+// getOriginalParallelism gets the original parallelism of a Job from its annotations
+func getOriginalParallelism(job *batchv1.Job) int32 {
+	if parsed, ok := utils.ParseReplicas(job.Annotations[utils.OriginalParallelismAnnotation]); ok {
+		return parsed
+	}
+	return *job.Spec.Parallelism
+}
+
+// isJobFinished returns true if the Job completed or failed
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) &&
+			condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobParallelism(t *testing.T) {
+	job := func(completions *int32) *batchv1.Job {
+		return &batchv1.Job{Spec: batchv1.JobSpec{Completions: completions}}
+	}
+	five := int32(5)
+
+	tests := []struct {
+		name    string
+		job     *batchv1.Job
+		desired int32
+		want    int32
+	}{
+		{name: "work queue job keeps desired", job: job(nil), desired: 12, want: 12},
+		{name: "never below one pod", job: job(nil), desired: 0, want: 1},
+		{name: "bounded by completions", job: job(&five), desired: 8, want: 5},
+		{name: "below completions", job: job(&five), desired: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jobParallelism(tt.job, tt.desired); got != tt.want {
+				t.Errorf("jobParallelism() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsJobFinished(t *testing.T) {
+	job := func(conditions ...batchv1.JobCondition) *batchv1.Job {
+		return &batchv1.Job{Status: batchv1.JobStatus{Conditions: conditions}}
+	}
+
+	if isJobFinished(job()) {
+		t.Error("expected a job without conditions to be running")
+	}
+	if isJobFinished(job(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionFalse})) {
+		t.Error("expected a job with a false Complete condition to be running")
+	}
+	if !isJobFinished(job(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue})) {
+		t.Error("expected a failed job to be finished")
+	}
+}
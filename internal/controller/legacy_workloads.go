@@ -0,0 +1,222 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// Metrics target kinds of legacy workloads
+	targetKindReplicationController = "ReplicationController"
+	targetKindReplicaSet            = "ReplicaSet"
+)
+
+// legacyWorkload is a ReplicationController or a ReplicaSet not owned by a Deployment
+type legacyWorkload struct {
+	kind     string
+	object   client.Object
+	template *corev1.PodTemplateSpec
+}
+
+// +kubebuilder:rbac:groups="",resources=replicationcontrollers,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=replicationcontrollers/scale,verbs=get;update
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets/scale,verbs=get;update
+
+// processLegacyWorkloads scales the ReplicationControllers and bare ReplicaSets
+// of a namespace matched by an override selector, or by the global config,
+// through their scale subresource. It returns nextCheck, moved earlier if a
+// blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processLegacyWorkloads(ctx context.Context, namespace string, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	workloads, err := r.listLegacyWorkloads(ctx, namespace)
+	if err != nil {
+		log.Error(err, "Failed to list legacy workloads in namespace", "namespace", namespace)
+		return nextCheck
+	}
+	if len(workloads) == 0 {
+		return nextCheck
+	}
+
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return nextCheck
+	}
+
+	for _, workload := range workloads {
+		if isWorkloadIgnored(workload.kind, workload.object, ignoreList.Items) {
+			continue
+		}
+
+		override := selectorOverride(overrideList.Items, workload.object)
+		if !targetsKind(override, workload.kind) || r.globalScalingHeld(ctx, workload.object, override) {
+			continue
+		}
+		if skip, until := r.overrideFrozen(ctx, override); skip {
+			if !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		if err := r.processLegacyWorkload(ctx, workload, override); err != nil && !changeDeferred(err) {
+			log.Error(err, "Failed to process legacy workload",
+				"kind", workload.kind,
+				"workload", fmt.Sprintf("%s/%s", namespace, workload.object.GetName()),
+				"hasOverride", override != nil,
+				"reason", recordFailure(namespace, workload.kind, override, err))
+		}
+	}
+	return nextCheck
+}
+
+// listLegacyWorkloads returns the ReplicationControllers and the ReplicaSets
+// without a controlling owner of a namespace
+func (r *ReplicasOverrideReconciler) listLegacyWorkloads(ctx context.Context, namespace string) ([]legacyWorkload, error) {
+	var workloads []legacyWorkload
+
+	controllers := &corev1.ReplicationControllerList{}
+	if err := r.List(ctx, controllers, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range controllers.Items {
+		rc := &controllers.Items[i]
+		template := rc.Spec.Template
+		if template == nil {
+			template = &corev1.PodTemplateSpec{}
+		}
+		workloads = append(workloads, legacyWorkload{kind: targetKindReplicationController, object: rc, template: template})
+	}
+
+	replicaSets := &appsv1.ReplicaSetList{}
+	if err := r.List(ctx, replicaSets, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if isBareReplicaSet(rs) {
+			workloads = append(workloads, legacyWorkload{kind: targetKindReplicaSet, object: rs, template: &rs.Spec.Template})
+		}
+	}
+	return workloads, nil
+}
+
+// processLegacyWorkload scales a single legacy workload through its scale subresource
+func (r *ReplicasOverrideReconciler) processLegacyWorkload(ctx context.Context, workload legacyWorkload, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+
+	cfg := r.configFor(ctx, workload.object.GetNamespace())
+	if cfg == nil {
+		return fmt.Errorf("global config not found")
+	}
+
+	scale := &autoscalingv1.Scale{}
+	if err := r.SubResource("scale").Get(ctx, workload.object, scale); err != nil {
+		return err
+	}
+	current := scale.Spec.Replicas
+
+	// Record the original replicas and management annotations on the workload itself
+	patch := client.MergeFrom(workload.object.DeepCopyObject().(client.Object))
+	r.repairOriginals(ctx, workload.kind, workload.object, override)
+	annotations := workload.object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, exists := annotations[utils.OriginalReplicasAnnotation]; !exists {
+		annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(current), 10)
+	}
+	originalReplicas, _ := utils.ParseReplicas(annotations[utils.OriginalReplicasAnnotation])
+
+	desired, explanation := r.desiredReplicas(ctx, cfg, workload.object, workload.template, override, originalReplicas, current)
+	percentage, trigger, clamped := explanation.Percentage, explanation.Trigger, explanation.Clamped
+	if desired == current {
+		log.V(1).Info("Legacy workload already at desired replicas, skipping update",
+			"kind", workload.kind,
+			"workload", fmt.Sprintf("%s/%s", workload.object.GetNamespace(), workload.object.GetName()),
+			"replicas", current)
+		return r.updateExplanation(ctx, workload.object, explanation)
+	}
+
+	labels := scalingLabels(workload.object.GetNamespace(), workload.kind, override)
+	labels.Trigger = trigger
+	if err := r.paceChange(ctx, labels, override, workload.object.GetName()); err != nil {
+		return err
+	}
+	desired, err := r.authorizeChange(ctx, labels, workload.object.GetName(), current, desired)
+	if err != nil {
+		return err
+	}
+	if err := r.spendBudget(ctx, labels, workload.object.GetName(), current, desired); err != nil {
+		return err
+	}
+
+	if override != nil {
+		annotations[utils.OverrideControllerAnnotation] = "true"
+		annotations[utils.ManagedAnnotation] = "true"
+	} else {
+		annotations[utils.GlobalConfigManagedAnnotation] = "true"
+	}
+	annotations[utils.ManagementModeAnnotation] = "direct"
+	annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	annotations[utils.ExplainAnnotation] = explanation.JSON()
+	workload.object.SetAnnotations(annotations)
+	owner := newChangeReason(labels, &percentage).record(workload.object)
+	if err := r.Patch(ctx, workload.object, patch, owner); err != nil {
+		return err
+	}
+
+	log.Info("Updating legacy workload replicas",
+		"kind", workload.kind,
+		"workload", fmt.Sprintf("%s/%s", workload.object.GetNamespace(), workload.object.GetName()),
+		"original", originalReplicas,
+		"previous", current,
+		"target", desired,
+		"percentage", percentage)
+
+	scale.Spec.Replicas = desired
+	if err := r.SubResource("scale").Update(ctx, workload.object, client.WithSubResourceBody(scale), owner); err != nil {
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(workload.object.GetNamespace(), workload.object.GetName(), labels, originalReplicas, current, desired, clamped, err)
+		return err
+	}
+	metrics.RecordScaling(ctx, labels, current, desired, percentage, clamped)
+	r.recordEvent(workload.object.GetNamespace(), workload.object.GetName(), labels, originalReplicas, current, desired, clamped, nil)
+	return nil
+}
+
+// isBareReplicaSet returns true if rs is not controlled by a Deployment or any other owner
+func isBareReplicaSet(rs *appsv1.ReplicaSet) bool {
+	return metav1.GetControllerOf(rs) == nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsBareReplicaSet(t *testing.T) {
+	controller := true
+	owned := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Controller: &controller},
+	}}}
+	referenced := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "settings"},
+	}}}
+
+	if isBareReplicaSet(owned) {
+		t.Error("expected a ReplicaSet controlled by a Deployment not to be bare")
+	}
+	if !isBareReplicaSet(referenced) {
+		t.Error("expected a ReplicaSet without a controlling owner to be bare")
+	}
+	if !isBareReplicaSet(&appsv1.ReplicaSet{}) {
+		t.Error("expected a ReplicaSet without owners to be bare")
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/manifests"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// ManifestsRef references the rendered manifests of a Git revision, e.g. the
+// output of kustomize build or helm template for a pending pull request,
+// whose Deployments and HPAs are simulated instead of the live ones
+type ManifestsRef struct {
+	// ConfigMap is the namespace/name of a ConfigMap whose values hold the
+	// manifests, the namespace of the simulation when omitted
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// OCI is an artifact whose layers hold the manifests, e.g.
+	// ghcr.io/org/app-manifests:pr-42 as pushed by flux push artifact
+	OCI string `json:"oci,omitempty"`
+
+	// Revision is the Git revision the manifests were rendered from, echoed in the response
+	Revision string `json:"revision,omitempty"`
+}
+
+// validate checks that exactly one source is set
+func (ref *ManifestsRef) validate() error {
+	if (ref.ConfigMap == "") == (ref.OCI == "") {
+		return fmt.Errorf("manifests must reference either a configMap or an oci artifact")
+	}
+	return nil
+}
+
+// loadManifests reads the Deployments and HPAs of ref. Workloads declaring no
+// namespace are placed in namespace, which is then required.
+func (r *ReplicasOverrideReconciler) loadManifests(ctx context.Context, ref *ManifestsRef, namespace string) (*manifests.Set, error) {
+	set := &manifests.Set{}
+	if ref.OCI != "" {
+		if r.ManifestPuller == nil {
+			return nil, fmt.Errorf("OCI manifests are not enabled on this controller")
+		}
+		pulled, err := r.ManifestPuller.Pull(ctx, ref.OCI)
+		if err != nil {
+			return nil, err
+		}
+		set = pulled
+	} else {
+		key := types.NamespacedName{Namespace: namespace, Name: ref.ConfigMap}
+		if ns, name, found := strings.Cut(ref.ConfigMap, "/"); found {
+			key = types.NamespacedName{Namespace: ns, Name: name}
+		}
+		if key.Namespace == "" {
+			return nil, fmt.Errorf("manifests configMap %q needs a namespace", ref.ConfigMap)
+		}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return nil, err
+		}
+		// Parse the keys in order so the workloads are listed the same way every time
+		keys := make([]string, 0, len(configMap.Data))
+		for k := range configMap.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := set.Parse([]byte(configMap.Data[k])); err != nil {
+				return nil, fmt.Errorf("ConfigMap %s key %s: %w", key, k, err)
+			}
+		}
+	}
+
+	if namespace != "" {
+		set.Default(namespace)
+	}
+	for _, deployment := range set.Deployments {
+		if deployment.Namespace == "" {
+			return nil, fmt.Errorf("Deployment %s of the manifests has no namespace, set the namespace of the simulation", deployment.Name)
+		}
+	}
+	// The controller records the originals itself, whatever the manifests say
+	for i := range set.Deployments {
+		delete(set.Deployments[i].Annotations, utils.OriginalReplicasAnnotation)
+	}
+	for i := range set.HPAs {
+		delete(set.HPAs[i].Annotations, utils.OriginalMinReplicasAnnotation)
+		delete(set.HPAs[i].Annotations, utils.OriginalMaxReplicasAnnotation)
+	}
+	return set, nil
+}
+
+// renderedWorkloads returns the Deployments and HPAs of set in namespace
+func renderedWorkloads(set *manifests.Set, namespace string) ([]appsv1.Deployment, []autoscalingv2.HorizontalPodAutoscaler) {
+	var deployments []appsv1.Deployment
+	for _, deployment := range set.Deployments {
+		if deployment.Namespace == namespace {
+			deployments = append(deployments, deployment)
+		}
+	}
+	var hpas []autoscalingv2.HorizontalPodAutoscaler
+	for _, hpa := range set.HPAs {
+		if hpa.Namespace == namespace {
+			hpas = append(hpas, hpa)
+		}
+	}
+	return deployments, hpas
+}
+
+// liveReplicas returns the replicas of the live Deployment called name, or
+// the limits of its HPA, zero when it is not deployed yet
+func liveReplicas(deployments []appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, name string) (int32, int32) {
+	for i := range hpas {
+		if scalesDeployment(&hpas[i]) && hpas[i].Spec.ScaleTargetRef.Name == name {
+			var minReplicas int32 = 1
+			if hpas[i].Spec.MinReplicas != nil {
+				minReplicas = *hpas[i].Spec.MinReplicas
+			}
+			return minReplicas, hpas[i].Spec.MaxReplicas
+		}
+	}
+	for _, deployment := range deployments {
+		if deployment.Name == name && deployment.Spec.Replicas != nil {
+			return *deployment.Spec.Replicas, 0
+		}
+	}
+	return 0, 0
+}
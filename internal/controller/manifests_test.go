@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestSimulateManifests(t *testing.T) {
+	// The pull request raises web to 6 replicas and adds worker, api is not part of the manifests
+	rendered := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    team: web
+  annotations:
+    kubedynamicscaler.io/original-replicas: "100"
+spec:
+  replicas: 6
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  replicas: 2
+`
+	c := fakeclient.NewBuilder(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"team": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "pr-42", Namespace: "gitops"},
+			Data:       map[string]string{"manifests.yaml": rendered},
+		},
+	).Build()
+	r := newTestReconciler(c, nil)
+
+	response, err := r.Simulate(context.Background(), SimulationRequest{
+		Namespace: "shop",
+		Override: &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "sale"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "web"}},
+				ReplicasPercentage: 150,
+			},
+		},
+		Manifests: &ManifestsRef{ConfigMap: "gitops/pr-42", Revision: "abc123"},
+	})
+	if err != nil {
+		t.Fatalf("Simulate() failed: %v", err)
+	}
+	if response.Revision != "abc123" || len(response.Workloads) != 2 {
+		t.Fatalf("Simulate() = revision %q with %d workloads, want abc123 with the 2 of the manifests", response.Revision, len(response.Workloads))
+	}
+
+	got := make(map[string]SimulatedWorkload)
+	for _, w := range response.Workloads {
+		got[w.Name] = w
+	}
+	if web := got["web"]; web.GitReplicas == nil || *web.GitReplicas != 6 || web.CurrentReplicas != 4 || web.Replicas != 9 || !web.Changed {
+		t.Errorf("web = %+v, want 150%% of the 6 replicas from Git over the 4 live ones", web)
+	}
+	if worker := got["worker"]; worker.CurrentReplicas != 0 || worker.Replicas != 2 || !worker.Changed {
+		t.Errorf("worker = %+v, want a new deployment of 2 replicas", worker)
+	}
+
+	if _, err := r.Simulate(context.Background(), SimulationRequest{Manifests: &ManifestsRef{OCI: "ghcr.io/org/app:pr-42"}}); err == nil {
+		t.Error("expected OCI manifests to be rejected without a puller")
+	}
+	if _, err := r.Simulate(context.Background(), SimulationRequest{Manifests: &ManifestsRef{}}); err == nil {
+		t.Error("expected manifests without a source to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// monitoring returns true if override is in Monitor mode, only reporting the
+// replicas it would set
+func monitoring(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override != nil && override.Spec.Mode == dynamicscalingv1.OverrideModeMonitor
+}
+
+// monitorDeployment records in monitored the replicas override, in Monitor
+// mode, would set on deployment if it were enforced, without changing anything
+func (r *ReplicasOverrideReconciler) monitorDeployment(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, monitored map[types.NamespacedName][]dynamicscalingv1.MonitoredTarget) {
+	key := client.ObjectKeyFromObject(override)
+	if _, found := monitored[key]; !found {
+		monitored[key] = nil
+	}
+
+	enforced := override.DeepCopy()
+	enforced.Spec.Mode = dynamicscalingv1.OverrideModeEnforce
+	workload, err := r.simulateDeployment(ctx, deployment, hpas, []dynamicscalingv1.ReplicasOverride{*enforced})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to evaluate monitored deployment",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"override", override.Name)
+		return
+	}
+	// Canary-scoped overrides only match while a canary runs, the simulation falls back to the global config
+	if workload.Override != override.Name {
+		return
+	}
+	monitored[key] = append(monitored[key], dynamicscalingv1.MonitoredTarget{
+		Name:               deployment.Name,
+		Namespace:          deployment.Namespace,
+		HPA:                workload.HPA,
+		CurrentReplicas:    workload.CurrentReplicas,
+		DesiredReplicas:    workload.Replicas,
+		CurrentMaxReplicas: workload.CurrentMaxReplicas,
+		DesiredMaxReplicas: workload.MaxReplicas,
+		Explanation:        workload.Explanation,
+	})
+}
+
+// reportMonitoredTargets writes the targets monitored during the pass to the
+// status of the overrides in Monitor mode, and clears them from the overrides
+// enforced again
+func (r *ReplicasOverrideReconciler) reportMonitoredTargets(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride, monitored map[types.NamespacedName][]dynamicscalingv1.MonitoredTarget) {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		if !monitoring(override) && len(override.Status.MonitoredTargets) == 0 {
+			continue
+		}
+		key := client.ObjectKeyFromObject(override)
+		targets := monitored[key]
+		sort.Slice(targets, func(i, j int) bool {
+			if targets[i].Namespace != targets[j].Namespace {
+				return targets[i].Namespace < targets[j].Namespace
+			}
+			return targets[i].Name < targets[j].Name
+		})
+
+		var delta int32
+		for _, target := range targets {
+			delta += target.DesiredReplicas - target.CurrentReplicas
+		}
+		metrics.SetMonitoredReplicaDelta(scalingLabels(override.Namespace, metrics.TargetKindDeployment, override), delta)
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			latest := &dynamicscalingv1.ReplicasOverride{}
+			if err := r.Get(ctx, key, latest); err != nil {
+				return err
+			}
+			if equality.Semantic.DeepEqual(latest.Status.MonitoredTargets, targets) {
+				return nil
+			}
+			latest.Status.MonitoredTargets = targets
+			return r.Status().Update(ctx, latest)
+		})
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
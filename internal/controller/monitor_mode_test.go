@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestMonitorMode(t *testing.T) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+			OverrideType:       "override",
+			ReplicasPercentage: 200,
+			Mode:               dynamicscalingv1.OverrideModeMonitor,
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+		Build()
+	r := newTestReconciler(c, nil)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatal(err)
+	}
+	// The deployment is left alone, the override only reports what it would do
+	if *deployment.Spec.Replicas != 3 {
+		t.Errorf("replicas = %d, want 3", *deployment.Spec.Replicas)
+	}
+	if _, recorded := deployment.Annotations[utils.OriginalReplicasAnnotation]; recorded {
+		t.Errorf("original replicas recorded in Monitor mode")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	if len(override.Status.MonitoredTargets) != 1 {
+		t.Fatalf("monitoredTargets = %+v, want one entry", override.Status.MonitoredTargets)
+	}
+	if got := override.Status.MonitoredTargets[0]; got.Name != "api" || got.CurrentReplicas != 3 || got.DesiredReplicas != 6 {
+		t.Errorf("monitored target = %+v, want api from 3 to 6 replicas", got)
+	}
+	if len(override.Status.AffectedDeployments) != 0 {
+		t.Errorf("affectedDeployments = %+v, want none", override.Status.AffectedDeployments)
+	}
+
+	// Once enforced, the deployment is scaled and the monitored targets cleared
+	override.Spec.Mode = dynamicscalingv1.OverrideModeEnforce
+	if err := c.Update(ctx, override); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		t.Fatal(err)
+	}
+	if *deployment.Spec.Replicas != 6 {
+		t.Errorf("replicas = %d, want 6", *deployment.Spec.Replicas)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	if len(override.Status.MonitoredTargets) != 0 {
+		t.Errorf("monitoredTargets = %+v, want none", override.Status.MonitoredTargets)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/blackout"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// NamespaceDefaultReadyConditionType is the NamespaceScalingDefault condition
+// reporting whether its schedules are valid
+const NamespaceDefaultReadyConditionType = "Ready"
+
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=namespacescalingdefaults,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=namespacescalingdefaults/status,verbs=get;update;patch
+
+// namespaceDefault returns the NamespaceScalingDefault in effect for a namespace,
+// the oldest one when there are several, or nil
+func (r *ReplicasOverrideReconciler) namespaceDefault(ctx context.Context, namespace string) *dynamicscalingv1.NamespaceScalingDefault {
+	defaults := &dynamicscalingv1.NamespaceScalingDefaultList{}
+	if err := r.List(ctx, defaults, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list namespace scaling defaults", "namespace", namespace)
+		return nil
+	}
+	if len(defaults.Items) == 0 {
+		return nil
+	}
+	sort.Slice(defaults.Items, func(i, j int) bool {
+		a, b := defaults.Items[i], defaults.Items[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+	return &defaults.Items[0]
+}
+
+// configFor returns the global config with the ConfigMap and the defaults of the namespace layered on top
+func (r *ReplicasOverrideReconciler) configFor(ctx context.Context, namespace string) *config.GlobalConfig {
+	cfg := r.Config.ForNamespace(namespace)
+	if cfg == nil {
+		return nil
+	}
+	def := r.namespaceDefault(ctx, namespace)
+	if def == nil {
+		return cfg
+	}
+	layered, _, _ := applyNamespaceDefault(cfg, r.Config.GetConfig(), def, time.Now())
+	return layered
+}
+
+// applyNamespaceDefault returns a copy of cfg with the fields set by def, the
+// name of the active schedule, if any, and an error for invalid schedules,
+// which are skipped. The min and max replicas of def stay within those of
+// global, the config without the namespace ConfigMap, so namespace admins
+// cannot lift the limits of the cluster.
+func applyNamespaceDefault(cfg, global *config.GlobalConfig, def *dynamicscalingv1.NamespaceScalingDefault, now time.Time) (*config.GlobalConfig, string, error) {
+	layered := *cfg
+	if def.Spec.ReplicasPercentage != nil {
+		layered.GlobalPercentage = *def.Spec.ReplicasPercentage
+	}
+	if def.Spec.MinReplicas != nil {
+		layered.MinReplicas = min(max(*def.Spec.MinReplicas, global.MinReplicas), global.MaxReplicas)
+	}
+	if def.Spec.MaxReplicas != nil {
+		layered.MaxReplicas = min(max(*def.Spec.MaxReplicas, global.MinReplicas), global.MaxReplicas)
+	}
+
+	var errs []error
+	for _, schedule := range def.Spec.Schedules {
+		window, _, err := blackout.Active([]dynamicscalingv1.BlackoutWindow{schedule.Window()}, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if window != nil {
+			layered.GlobalPercentage = schedule.ReplicasPercentage
+			return &layered, schedule.Name, nil
+		}
+	}
+	if len(errs) > 0 {
+		return &layered, "", fmt.Errorf("invalid schedules: %v", errs)
+	}
+	return &layered, "", nil
+}
+
+// scheduleTransition returns the next time a schedule of def starts or ends,
+// zero if none does
+func scheduleTransition(def *dynamicscalingv1.NamespaceScalingDefault, now time.Time) time.Time {
+	windows := make([]dynamicscalingv1.BlackoutWindow, 0, len(def.Spec.Schedules))
+	for _, schedule := range def.Spec.Schedules {
+		windows = append(windows, schedule.Window())
+	}
+	next := blackout.NextStart(windows, now)
+	for i := range windows {
+		if window, end, _ := blackout.Active(windows[i:i+1], now); window != nil && (next.IsZero() || end.Before(next)) {
+			next = end
+		}
+	}
+	return next
+}
+
+// syncNamespaceDefault records the active schedule and effective percentage
+// in the status of the NamespaceScalingDefault of a namespace, and returns
+// nextCheck moved up to its next schedule transition
+func (r *ReplicasOverrideReconciler) syncNamespaceDefault(ctx context.Context, namespace string, nextCheck time.Time) time.Time {
+	cfg := r.Config.ForNamespace(namespace)
+	def := r.namespaceDefault(ctx, namespace)
+	if cfg == nil || def == nil {
+		return nextCheck
+	}
+
+	now := time.Now()
+	if transition := scheduleTransition(def, now); !transition.IsZero() && transition.Before(nextCheck) {
+		nextCheck = transition
+	}
+	layered, active, err := applyNamespaceDefault(cfg, r.Config.GetConfig(), def, now)
+	condition := metav1.Condition{
+		Type:               NamespaceDefaultReadyConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DefaultsApplied",
+		ObservedGeneration: def.Generation,
+	}
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "InvalidSchedule", err.Error()
+	}
+
+	changed := meta.SetStatusCondition(&def.Status.Conditions, condition)
+	if def.Status.ActiveSchedule != active || def.Status.EffectivePercentage != layered.GlobalPercentage {
+		def.Status.ActiveSchedule = active
+		def.Status.EffectivePercentage = layered.GlobalPercentage
+		changed = true
+	}
+	if !changed {
+		return nextCheck
+	}
+	if err := r.Status().Update(ctx, def); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update namespace scaling default status",
+			"namespaceScalingDefault", def.Name,
+			"namespace", def.Namespace)
+	}
+	return nextCheck
+}
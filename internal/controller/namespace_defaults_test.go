@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestApplyNamespaceDefault(t *testing.T) {
+	global := &config.GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 50}
+	percentage, minReplicas := int32(80), int32(2)
+	now := time.Date(2025, 11, 28, 21, 0, 0, 0, time.UTC)
+
+	def := &dynamicscalingv1.NamespaceScalingDefault{Spec: dynamicscalingv1.NamespaceScalingDefaultSpec{
+		ReplicasPercentage: &percentage,
+		MinReplicas:        &minReplicas,
+	}}
+	nightly := dynamicscalingv1.ScalingSchedule{
+		Name:               "nightly",
+		Schedule:           "0 20 * * *",
+		Duration:           &metav1.Duration{Duration: 10 * time.Hour},
+		ReplicasPercentage: 40,
+	}
+	invalid := dynamicscalingv1.ScalingSchedule{Name: "broken", Schedule: "not a cron", ReplicasPercentage: 10}
+
+	tests := []struct {
+		name       string
+		schedules  []dynamicscalingv1.ScalingSchedule
+		now        time.Time
+		want       int32
+		wantActive string
+		wantErr    bool
+	}{
+		{name: "defaults without schedules", want: 80, now: now},
+		{name: "active schedule wins", schedules: []dynamicscalingv1.ScalingSchedule{nightly}, now: now, want: 40, wantActive: "nightly"},
+		{name: "inactive schedule", schedules: []dynamicscalingv1.ScalingSchedule{nightly}, now: now.Add(-3 * time.Hour), want: 80},
+		{name: "invalid schedule is skipped", schedules: []dynamicscalingv1.ScalingSchedule{invalid, nightly}, now: now, want: 40, wantActive: "nightly"},
+		{name: "invalid schedule is reported", schedules: []dynamicscalingv1.ScalingSchedule{invalid}, now: now, want: 80, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def.Spec.Schedules = tt.schedules
+			layered, active, err := applyNamespaceDefault(global, global, def, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyNamespaceDefault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if layered.GlobalPercentage != tt.want || active != tt.wantActive {
+				t.Errorf("applyNamespaceDefault() = (%d, %q), want (%d, %q)", layered.GlobalPercentage, active, tt.want, tt.wantActive)
+			}
+			if layered.MinReplicas != 2 || layered.MaxReplicas != 50 {
+				t.Errorf("applyNamespaceDefault() limits = [%d, %d], want [2, 50]", layered.MinReplicas, layered.MaxReplicas)
+			}
+		})
+	}
+	if global.GlobalPercentage != 100 || global.MinReplicas != 1 {
+		t.Error("applyNamespaceDefault() modified the global config")
+	}
+
+	// The namespace limits stay within the global ones
+	lifted, lowered := int32(500), int32(0)
+	def.Spec.Schedules = nil
+	def.Spec.MinReplicas, def.Spec.MaxReplicas = &lowered, &lifted
+	if layered, _, _ := applyNamespaceDefault(global, global, def, now); layered.MinReplicas != 1 || layered.MaxReplicas != 50 {
+		t.Errorf("applyNamespaceDefault() limits = [%d, %d], want the global [1, 50]", layered.MinReplicas, layered.MaxReplicas)
+	}
+
+	// A namespace ConfigMap raising maxReplicas does not lift the limits either
+	overlay := &config.GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 1000}
+	if layered, _, _ := applyNamespaceDefault(overlay, global, def, now); layered.MinReplicas != 1 || layered.MaxReplicas != 50 {
+		t.Errorf("applyNamespaceDefault() with an overlay limits = [%d, %d], want the global [1, 50]", layered.MinReplicas, layered.MaxReplicas)
+	}
+}
+
+func TestScheduleTransition(t *testing.T) {
+	evening := time.Date(2025, 11, 28, 19, 0, 0, 0, time.UTC)
+	def := &dynamicscalingv1.NamespaceScalingDefault{Spec: dynamicscalingv1.NamespaceScalingDefaultSpec{
+		Schedules: []dynamicscalingv1.ScalingSchedule{{
+			Name:               "nightly",
+			Schedule:           "0 20 * * *",
+			Duration:           &metav1.Duration{Duration: 10 * time.Hour},
+			ReplicasPercentage: 40,
+		}},
+	}}
+
+	if got, want := scheduleTransition(def, evening), evening.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("scheduleTransition() before the schedule = %v, want its start %v", got, want)
+	}
+	if got, want := scheduleTransition(def, evening.Add(2*time.Hour)), evening.Add(11*time.Hour); !got.Equal(want) {
+		t.Errorf("scheduleTransition() during the schedule = %v, want its end %v", got, want)
+	}
+	if got := scheduleTransition(&dynamicscalingv1.NamespaceScalingDefault{}, evening); !got.IsZero() {
+		t.Errorf("scheduleTransition() without schedules = %v, want zero", got)
+	}
+}
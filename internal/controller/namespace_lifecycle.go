@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// namespaceTerminating returns true if the namespace is being deleted
+func namespaceTerminating(namespace *corev1.Namespace) bool {
+	return namespace.DeletionTimestamp != nil || namespace.Status.Phase == corev1.NamespaceTerminating
+}
+
+// namespaceGone returns true if the namespace was deleted or is being deleted
+func (r *ReplicasOverrideReconciler) namespaceGone(ctx context.Context, name string) (bool, error) {
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return namespaceTerminating(namespace), nil
+}
+
+// terminatingNamespaceRequests requeues a namespace once it starts terminating so its work is dropped
+func terminatingNamespaceRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok || !namespaceTerminating(namespace) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "", Namespace: namespace.Name}}}
+}
+
+// forgetNamespace drops a deleted namespace from the disruption and report
+// caches and from the status of the overrides of other namespaces
+func (r *ReplicasOverrideReconciler) forgetNamespace(ctx context.Context, namespace string) {
+	log := log.FromContext(ctx)
+
+	r.Disruption.ForgetNamespace(namespace)
+	r.Recorder.ForgetNamespace(namespace)
+
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrides); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return
+	}
+	for i := range overrides.Items {
+		override := &overrides.Items[i]
+		if override.Namespace == namespace || !pruneNamespaceStatus(override, namespace) {
+			continue
+		}
+		if err := r.Status().Update(ctx, override); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+	log.V(1).Info("Dropped terminating namespace", "namespace", namespace)
+}
+
+// pruneNamespaceStatus removes the workloads of a namespace from the status of
+// an override and returns true if any was listed
+func pruneNamespaceStatus(override *dynamicscalingv1.ReplicasOverride, namespace string) bool {
+	pruned := false
+	affected := override.Status.AffectedDeployments[:0]
+	for _, deployment := range override.Status.AffectedDeployments {
+		if deployment.Namespace == namespace {
+			pruned = true
+			continue
+		}
+		affected = append(affected, deployment)
+	}
+	override.Status.AffectedDeployments = affected
+
+	conflicts := override.Status.Conflicts[:0]
+	for _, conflict := range override.Status.Conflicts {
+		if conflict.Namespace == namespace {
+			pruned = true
+			continue
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	override.Status.Conflicts = conflicts
+	return pruned
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestNamespaceLifecycle(t *testing.T) {
+	terminating := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "old"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}}
+	active := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}}
+	if requests := terminatingNamespaceRequests(context.Background(), terminating); len(requests) != 1 || requests[0].Namespace != "old" {
+		t.Errorf("terminatingNamespaceRequests() = %v, want the terminating namespace", requests)
+	}
+	if requests := terminatingNamespaceRequests(context.Background(), active); len(requests) != 0 {
+		t.Errorf("terminatingNamespaceRequests() = %v for an active namespace, want none", requests)
+	}
+
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "shop"},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{
+			AffectedDeployments: []dynamicscalingv1.AffectedDeployment{{Name: "web", Namespace: "shop"}, {Name: "web", Namespace: "old"}},
+			Conflicts:           []dynamicscalingv1.ScalingConflict{{Kind: "Deployment", Name: "web", Namespace: "old"}},
+		},
+	}
+	c := fakeclient.NewBuilder(terminating, active, override).
+		Build()
+	r := newTestReconciler(c, nil)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "old"}})
+	if err != nil || result.RequeueAfter != 0 {
+		t.Fatalf("Reconcile() = (%v, %v), want the request of the terminating namespace dropped", result, err)
+	}
+	var updated dynamicscalingv1.ReplicasOverride
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(override), &updated); err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Status.AffectedDeployments) != 1 || updated.Status.AffectedDeployments[0].Namespace != "shop" || len(updated.Status.Conflicts) != 0 {
+		t.Errorf("status = %+v, want the workloads of the terminating namespace pruned", updated.Status)
+	}
+
+	// A deleted namespace is dropped the same way
+	if result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "deleted"}}); err != nil || result.RequeueAfter != 0 {
+		t.Errorf("Reconcile() = (%v, %v), want the request of the deleted namespace dropped", result, err)
+	}
+}
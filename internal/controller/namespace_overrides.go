@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// NamespaceOverrideReadyConditionType is the NamespaceReplicasOverride
+// condition reporting whether it is the one in effect for its namespace
+const NamespaceOverrideReadyConditionType = "Ready"
+
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=namespacereplicasoverrides,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=namespacereplicasoverrides/status,verbs=get;update;patch
+
+// namespaceOverrides returns the NamespaceReplicasOverrides of a namespace,
+// oldest first, the first one being in effect
+func (r *ReplicasOverrideReconciler) namespaceOverrides(ctx context.Context, namespace string) []dynamicscalingv1.NamespaceReplicasOverride {
+	overrides := &dynamicscalingv1.NamespaceReplicasOverrideList{}
+	if err := r.List(ctx, overrides, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list namespace replicas overrides", "namespace", namespace)
+		return nil
+	}
+	sort.Slice(overrides.Items, func(i, j int) bool {
+		a, b := overrides.Items[i], overrides.Items[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+	return overrides.Items
+}
+
+// namespaceOverride returns the NamespaceReplicasOverride in effect for a
+// namespace, the oldest one when there are several, or nil
+func (r *ReplicasOverrideReconciler) namespaceOverride(ctx context.Context, namespace string) *dynamicscalingv1.NamespaceReplicasOverride {
+	overrides := r.namespaceOverrides(ctx, namespace)
+	if len(overrides) == 0 {
+		return nil
+	}
+	return &overrides[0]
+}
+
+// syncNamespaceOverride records the number of deployments the
+// NamespaceReplicasOverrides of a namespace apply to, and marks those
+// shadowed by an older one as not ready
+func (r *ReplicasOverrideReconciler) syncNamespaceOverride(ctx context.Context, namespace string, targeted int32) {
+	overrides := r.namespaceOverrides(ctx, namespace)
+	for i := range overrides {
+		o := &overrides[i]
+		condition := metav1.Condition{
+			Type:               NamespaceOverrideReadyConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Applied",
+			Message:            fmt.Sprintf("%d deployments at %d%%", targeted, o.Spec.ReplicasPercentage),
+			ObservedGeneration: o.Generation,
+		}
+		count := targeted
+		if i > 0 {
+			condition.Status, condition.Reason = metav1.ConditionFalse, "Superseded"
+			condition.Message = fmt.Sprintf("NamespaceReplicasOverride %s is older and applies instead", overrides[0].Name)
+			count = 0
+		}
+
+		changed := meta.SetStatusCondition(&o.Status.Conditions, condition)
+		if o.Status.TargetedDeployments != count {
+			o.Status.TargetedDeployments = count
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := r.Status().Update(ctx, o); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update namespace replicas override status",
+				"namespaceReplicasOverride", o.Name,
+				"namespace", o.Namespace)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestNamespaceReplicasOverride(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	minReplicas, defaultPercentage := int32(2), int32(80)
+	staging := &dynamicscalingv1.NamespaceReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "shop", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       dynamicscalingv1.NamespaceReplicasOverrideSpec{ReplicasPercentage: 50, MinReplicas: &minReplicas},
+	}
+	newer := &dynamicscalingv1.NamespaceReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "newer", Namespace: "shop", CreationTimestamp: metav1.NewTime(created.Add(time.Hour))},
+		Spec:       dynamicscalingv1.NamespaceReplicasOverrideSpec{ReplicasPercentage: 200},
+	}
+	def := &dynamicscalingv1.NamespaceScalingDefault{
+		ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "shop"},
+		Spec:       dynamicscalingv1.NamespaceScalingDefaultSpec{ReplicasPercentage: &defaultPercentage},
+	}
+	r := &ReplicasOverrideReconciler{
+		Client: fakeclient.NewBuilder(staging, newer, def).
+			WithStatusSubresource(&dynamicscalingv1.NamespaceReplicasOverride{}).
+			Build(),
+		Config: config.NewStaticManager(&config.GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 50}),
+	}
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+
+	explanation := precedence.Resolve(r.precedenceRules(ctx, workload, nil)...)
+	if explanation.Percentage != 50 || explanation.Layer != precedence.LayerNamespaceOverride || explanation.MinReplicas != 2 {
+		t.Errorf("precedence without override = (%d%%, %s, min %d), want the oldest namespace override (50%%, namespace-override, min 2)",
+			explanation.Percentage, explanation.Layer, explanation.MinReplicas)
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 150},
+	}
+	if explanation := precedence.Resolve(r.precedenceRules(ctx, workload, override)...); explanation.Percentage != 150 {
+		t.Errorf("precedence with override = %d%%, want the override 150%%", explanation.Percentage)
+	}
+
+	r.syncNamespaceOverride(ctx, "shop", 3)
+	for _, tt := range []struct {
+		name       string
+		wantStatus metav1.ConditionStatus
+		wantCount  int32
+	}{
+		{"staging", metav1.ConditionTrue, 3},
+		{"newer", metav1.ConditionFalse, 0},
+	} {
+		got := &dynamicscalingv1.NamespaceReplicasOverride{}
+		if err := r.Get(ctx, types.NamespacedName{Name: tt.name, Namespace: "shop"}, got); err != nil {
+			t.Fatalf("Get(%s) error = %v", tt.name, err)
+		}
+		ready := meta.FindStatusCondition(got.Status.Conditions, NamespaceOverrideReadyConditionType)
+		if ready == nil || ready.Status != tt.wantStatus || got.Status.TargetedDeployments != tt.wantCount {
+			t.Errorf("%s status = %+v, want Ready %s with %d deployments", tt.name, got.Status, tt.wantStatus, tt.wantCount)
+		}
+	}
+}
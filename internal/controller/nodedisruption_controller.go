@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/disruption"
+)
+
+// NodeDisruptionReconciler watches nodes for cordons, drains and spot interruptions
+// and records the workloads running on them so their capacity can be boosted
+type NodeDisruptionReconciler struct {
+	client.Client
+	// APIReader reads pods and replicasets without caching them cluster-wide
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	Config    *config.Manager
+	Tracker   *disruption.Tracker
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
+
+// Reconcile updates the disruption tracker from the state of a node
+func (r *NodeDisruptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	cfg := r.Config.GetConfig().NodeDisruption
+	cooldown := cfg.GetCooldown()
+
+	changed := r.Tracker.Expire(cooldown)
+	defer func() {
+		if changed {
+			r.Tracker.Notify(event.GenericEvent{Object: &corev1.Node{}})
+		}
+	}()
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, req.NamespacedName, node); err != nil {
+		if errors.IsNotFound(err) {
+			// The node was removed; keep the boost until replacements are ready
+			if r.Tracker.MarkReleased(req.Name) {
+				changed = true
+				return ctrl.Result{RequeueAfter: cooldown}, nil
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !cfg.Enabled || !isDisrupted(node, cfg) {
+		if r.Tracker.MarkReleased(node.Name) {
+			log.Info("Node disruption ended", "node", node.Name, "cooldown", cooldown)
+			changed = true
+			return ctrl.Result{RequeueAfter: cooldown}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	workloads, err := r.workloadsOnNode(ctx, node.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.Tracker.MarkDisrupted(node.Name, workloads) {
+		log.Info("Node disrupted, boosting affected workloads",
+			"node", node.Name,
+			"workloads", len(workloads),
+			"boost", cfg.BoostPercentage)
+		changed = true
+	}
+	return ctrl.Result{}, nil
+}
+
+// isDisrupted returns true if the node is cordoned or carries an interruption taint
+func isDisrupted(node *corev1.Node, cfg config.NodeDisruptionConfig) bool {
+	if node.Spec.Unschedulable && !cfg.IgnoreCordon {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range cfg.GetTaintKeys() {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// workloadsOnNode returns the deployments owning pods scheduled on the node
+func (r *NodeDisruptionReconciler) workloadsOnNode(ctx context.Context, nodeName string) ([]types.NamespacedName, error) {
+	pods := &corev1.PodList{}
+	if err := r.APIReader.List(ctx, pods, client.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[types.NamespacedName]bool)
+	var workloads []types.NamespacedName
+	for _, pod := range pods.Items {
+		owner := ownerNameOfKind(pod.OwnerReferences, "ReplicaSet")
+		if owner == "" {
+			continue
+		}
+		replicaSet := &appsv1.ReplicaSet{}
+		if err := r.APIReader.Get(ctx, types.NamespacedName{Name: owner, Namespace: pod.Namespace}, replicaSet); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		deployment := ownerNameOfKind(replicaSet.OwnerReferences, "Deployment")
+		if deployment == "" {
+			continue
+		}
+		key := types.NamespacedName{Name: deployment, Namespace: pod.Namespace}
+		if !seen[key] {
+			seen[key] = true
+			workloads = append(workloads, key)
+		}
+	}
+	return workloads, nil
+}
+
+// ownerNameOfKind returns the name of the controlling owner of the given kind, if any
+func ownerNameOfKind(owners []metav1.OwnerReference, kind string) string {
+	for _, owner := range owners {
+		if owner.Kind == kind && owner.Controller != nil && *owner.Controller {
+			return owner.Name
+		}
+	}
+	return ""
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeDisruptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Node{}).
+		Named("nodedisruption").
+		WithOptions(queueOptions()).
+		Complete(r)
+}
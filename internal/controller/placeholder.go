@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// placeholderForLabel is the UID of the deployment a placeholder reserves capacity for
+	placeholderForLabel = "kubedynamicscaler.io/placeholder-for"
+
+	// placeholderSuffix is appended to the name of a deployment to name its placeholder
+	placeholderSuffix = "-kds-placeholder"
+
+	// defaultPlaceholderImage is the image of placeholder containers, which do nothing
+	defaultPlaceholderImage = "registry.k8s.io/pause:3.10"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=create;delete
+
+// isPlaceholder reports whether a deployment is the placeholder of another one
+func isPlaceholder(deployment *appsv1.Deployment) bool {
+	_, ok := deployment.Labels[placeholderForLabel]
+	return ok
+}
+
+// withoutPlaceholders drops placeholder deployments, which are never scaled themselves
+func withoutPlaceholders(deployments []appsv1.Deployment) []appsv1.Deployment {
+	result := deployments[:0]
+	for _, deployment := range deployments {
+		if !isPlaceholder(&deployment) {
+			result = append(result, deployment)
+		}
+	}
+	return result
+}
+
+// placeholderName returns the name of the placeholder of a deployment
+func placeholderName(deployment *appsv1.Deployment) string {
+	return deployment.Name + placeholderSuffix
+}
+
+// podRequests returns the resources requested by the containers of a pod template
+func podRequests(template *corev1.PodTemplateSpec) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range template.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	return requests
+}
+
+// placeholderReplicas returns the number of placeholder pods reserving the
+// replicas a deployment would be scaled to above its current replicas
+func placeholderReplicas(deployment *appsv1.Deployment, desired int32) int32 {
+	return max(0, desired-specReplicas(deployment.Spec.Replicas))
+}
+
+// processPlaceholder keeps the placeholder Deployment of a deployment targeted
+// by an override in placeholder mode at the pods its percentage would add,
+// leaving the deployment itself unscaled, and returns the placeholder replicas
+func (r *ReplicasOverrideReconciler) processPlaceholder(ctx context.Context, deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) (int32, error) {
+	cfg := r.configFor(ctx, deployment.Namespace)
+	if cfg == nil {
+		return 0, fmt.Errorf("global config not found")
+	}
+	desired, explanation := r.desiredReplicas(ctx, cfg, deployment, &deployment.Spec.Template, override, utils.GetOriginalReplicas(deployment), specReplicas(deployment.Spec.Replicas))
+	replicas := placeholderReplicas(deployment, desired)
+
+	image := override.Spec.Placeholder.Image
+	if image == "" {
+		image = defaultPlaceholderImage
+	}
+	selector := map[string]string{placeholderForLabel: string(deployment.UID)}
+	placeholder := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: placeholderName(deployment), Namespace: deployment.Namespace}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, placeholder, func() error {
+		if placeholder.Labels == nil {
+			placeholder.Labels = make(map[string]string)
+		}
+		placeholder.Labels[placeholderForLabel] = string(deployment.UID)
+		placeholder.Labels["app.kubernetes.io/managed-by"] = "kubedynamicscaler"
+		if placeholder.Annotations == nil {
+			placeholder.Annotations = make(map[string]string)
+		}
+		placeholder.Annotations[utils.ExplainAnnotation] = explanation.JSON()
+
+		placeholder.Spec.Replicas = &replicas
+		if placeholder.CreationTimestamp.IsZero() {
+			placeholder.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+
+		// Only the fields set here are compared, the others keep their server defaults
+		template := &placeholder.Spec.Template
+		template.Labels = selector
+		zero := int64(0)
+		automount := false
+		template.Spec.TerminationGracePeriodSeconds = &zero
+		template.Spec.AutomountServiceAccountToken = &automount
+		// Placeholders are scheduled where the deployment runs and preempted first
+		template.Spec.PriorityClassName = override.Spec.Placeholder.PriorityClassName
+		template.Spec.NodeSelector = deployment.Spec.Template.Spec.NodeSelector
+		template.Spec.Affinity = deployment.Spec.Template.Spec.Affinity
+		template.Spec.Tolerations = deployment.Spec.Template.Spec.Tolerations
+		if len(template.Spec.Containers) != 1 {
+			template.Spec.Containers = []corev1.Container{{}}
+		}
+		container := &template.Spec.Containers[0]
+		container.Name = "placeholder"
+		container.Image = image
+		container.Resources.Requests = podRequests(&deployment.Spec.Template)
+		return controllerutil.SetControllerReference(override, placeholder, r.Client.Scheme())
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Updated placeholder",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"placeholder", placeholder.Name,
+			"replicas", replicas,
+			"operation", result)
+	}
+	return replicas, nil
+}
+
+// prunePlaceholders deletes the placeholders of a namespace no override in
+// placeholder mode targets anymore. Deleting the override garbage-collects them.
+func (r *ReplicasOverrideReconciler) prunePlaceholders(ctx context.Context, namespace string, kept map[string]bool) {
+	placeholders := &appsv1.DeploymentList{}
+	if err := r.List(ctx, placeholders, client.InNamespace(namespace), client.HasLabels{placeholderForLabel}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list placeholders", "namespace", namespace)
+		return
+	}
+	for i := range placeholders.Items {
+		placeholder := &placeholders.Items[i]
+		if kept[placeholder.Name] {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, placeholder)); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to delete placeholder",
+				"placeholder", fmt.Sprintf("%s/%s", placeholder.Namespace, placeholder.Name))
+			continue
+		}
+		log.FromContext(ctx).Info("Deleted placeholder",
+			"placeholder", fmt.Sprintf("%s/%s", placeholder.Namespace, placeholder.Name))
+	}
+}
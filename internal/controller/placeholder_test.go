@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestPlaceholder(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop", UID: "checkout-uid"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(4),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"pool": "web"},
+				Containers: []corev1.Container{
+					{Name: "app", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}}},
+					{Name: "proxy", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}},
+				},
+			}},
+		},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "capacity", Namespace: "shop", UID: "capacity-uid"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 150,
+			Placeholder:        &dynamicscalingv1.Placeholder{PriorityClassName: "overprovisioning"},
+		},
+	}
+	c := fakeclient.NewBuilder(deployment, override).Build()
+	r := newTestReconciler(c, nil)
+	ctx := context.Background()
+
+	pods, err := r.processPlaceholder(ctx, deployment, override)
+	if err != nil || pods != 2 {
+		t.Fatalf("processPlaceholder() = %d, %v, want 2 placeholder pods", pods, err)
+	}
+	placeholder := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "checkout-kds-placeholder", Namespace: "shop"}, placeholder); err != nil {
+		t.Fatal(err)
+	}
+	spec := placeholder.Spec.Template.Spec
+	if *placeholder.Spec.Replicas != 2 || spec.PriorityClassName != "overprovisioning" || spec.NodeSelector["pool"] != "web" {
+		t.Errorf("placeholder = %+v, want 2 replicas of overprovisioning pods on the web pool", placeholder.Spec)
+	}
+	if cpu := spec.Containers[0].Resources.Requests[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("600m")) != 0 {
+		t.Errorf("placeholder cpu request = %s, want the 600m of a checkout pod", cpu.String())
+	}
+	if owner := metav1.GetControllerOf(placeholder); owner == nil || owner.Name != "capacity" {
+		t.Errorf("placeholder owner = %v, want the override", owner)
+	}
+	if !isPlaceholder(placeholder) || len(withoutPlaceholders([]appsv1.Deployment{*deployment, *placeholder})) != 1 {
+		t.Error("placeholders must be recognized and left out of the deployments to scale")
+	}
+
+	var unchanged appsv1.Deployment
+	if err := c.Get(ctx, types.NamespacedName{Name: "checkout", Namespace: "shop"}, &unchanged); err != nil || *unchanged.Spec.Replicas != 4 {
+		t.Errorf("processPlaceholder() scaled the deployment: %v", err)
+	}
+
+	r.prunePlaceholders(ctx, "shop", map[string]bool{"checkout-kds-placeholder": true})
+	if err := c.Get(ctx, types.NamespacedName{Name: "checkout-kds-placeholder", Namespace: "shop"}, &appsv1.Deployment{}); err != nil {
+		t.Errorf("kept placeholder was deleted: %v", err)
+	}
+	r.prunePlaceholders(ctx, "shop", nil)
+	if err := c.Get(ctx, types.NamespacedName{Name: "checkout-kds-placeholder", Namespace: "shop"}, &appsv1.Deployment{}); err == nil {
+		t.Error("expected the placeholder no override targets to be deleted")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+
+// podsAdmitted returns how many more pods of spec the quotas admit, and false
+// if none of them limits such pods. Scoped quotas are skipped, whether they
+// count the pods depends on their priority class and lifetime.
+func podsAdmitted(quotas []corev1.ResourceQuota, spec *corev1.PodSpec) (int32, bool) {
+	requests, limits := podResources(spec)
+	admitted, limited := int64(math.MaxInt32), false
+	for _, quota := range quotas {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		for name, hard := range quota.Status.Hard {
+			perPod, ok := podUsage(name, requests, limits)
+			if !ok || perPod.Sign() <= 0 {
+				continue
+			}
+			free := hard.DeepCopy()
+			free.Sub(quota.Status.Used[name])
+			var pods int64
+			if free.Sign() > 0 {
+				pods = free.MilliValue() / perPod.MilliValue()
+			}
+			admitted, limited = min(admitted, pods), true
+		}
+	}
+	return int32(admitted), limited
+}
+
+// podUsage returns what one pod counts against the quota resource name
+func podUsage(name corev1.ResourceName, requests, limits corev1.ResourceList) (resource.Quantity, bool) {
+	switch {
+	case name == corev1.ResourcePods || name == "count/pods":
+		return resource.MustParse("1"), true
+	case strings.HasPrefix(string(name), "requests."):
+		return requests[corev1.ResourceName(strings.TrimPrefix(string(name), "requests."))], true
+	case strings.HasPrefix(string(name), "limits."):
+		return limits[corev1.ResourceName(strings.TrimPrefix(string(name), "limits."))], true
+	case name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage:
+		return requests[name], true
+	}
+	return resource.Quantity{}, false
+}
+
+// podResources returns the requests and limits of a pod the way quotas count
+// them: the sum of its containers, or its largest init container if higher
+func podResources(spec *corev1.PodSpec) (corev1.ResourceList, corev1.ResourceList) {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range spec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range spec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+// addResources adds the quantities of add to total
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// maxResources raises the quantities of total to those of other when higher
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
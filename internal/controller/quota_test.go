@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func quota(name string, hard, used corev1.ResourceList) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestPodsAdmitted(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}},
+			{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			}},
+		},
+		InitContainers: []corev1.Container{
+			{Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		quotas      []corev1.ResourceQuota
+		want        int32
+		wantLimited bool
+	}{
+		{name: "no quota", want: 0},
+		{
+			name:        "pod count",
+			quotas:      []corev1.ResourceQuota{quota("pods", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("7")})},
+			want:        3,
+			wantLimited: true,
+		},
+		{
+			name:        "containers add up",
+			quotas:      []corev1.ResourceQuota{quota("cpu", corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4")}, corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2")})},
+			want:        4,
+			wantLimited: true,
+		},
+		{
+			name:        "larger init container counts",
+			quotas:      []corev1.ResourceQuota{quota("memory", corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("5Gi")})},
+			want:        3,
+			wantLimited: true,
+		},
+		{
+			name: "tightest quota wins",
+			quotas: []corev1.ResourceQuota{
+				quota("pods", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("100")}, nil),
+				quota("limits", corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("2Gi")}, corev1.ResourceList{corev1.ResourceLimitsMemory: resource.MustParse("1Gi")}),
+			},
+			want:        2,
+			wantLimited: true,
+		},
+		{
+			name:        "exhausted quota",
+			quotas:      []corev1.ResourceQuota{quota("pods", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("6")})},
+			want:        0,
+			wantLimited: true,
+		},
+		{
+			name:   "unrelated resource",
+			quotas: []corev1.ResourceQuota{quota("services", corev1.ResourceList{corev1.ResourceServices: resource.MustParse("1")}, nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, limited := podsAdmitted(tt.quotas, spec)
+			if limited != tt.wantLimited || (limited && got != tt.want) {
+				t.Errorf("podsAdmitted() = (%d, %v), want (%d, %v)", got, limited, tt.want, tt.wantLimited)
+			}
+		})
+	}
+
+	scoped := quota("best-effort", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("1")}, nil)
+	scoped.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+	if _, limited := podsAdmitted([]corev1.ResourceQuota{scoped}, spec); limited {
+		t.Error("expected a scoped quota to be skipped")
+	}
+}
+
+func TestQuotaClampStage(t *testing.T) {
+	pods := quota("pods", corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}, corev1.ResourceList{corev1.ResourcePods: resource.MustParse("8")})
+	r := newTestReconciler(fakeclient.NewBuilder(&pods).Build(), nil)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(4),
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}}},
+		},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 200},
+	}
+
+	replicas, explanation := r.desiredReplicas(context.Background(), config.DefaultConfig(), deployment, &deployment.Spec.Template, override, 4, 4)
+	if replicas != 6 || !explanation.Clamped {
+		t.Errorf("desiredReplicas() = (%d, clamped %v), want the 2 pods the quota admits on top of 4", replicas, explanation.Clamped)
+	}
+	if last := explanation.Stages[len(explanation.Stages)-1]; last.Stage != decision.StageQuotaClamp {
+		t.Errorf("last stage = %s, want %s", last.Stage, decision.StageQuotaClamp)
+	}
+
+	// Scale-downs are never held back by the quota
+	override.Spec.ReplicasPercentage = 50
+	if replicas, _ := r.desiredReplicas(context.Background(), config.DefaultConfig(), deployment, &deployment.Spec.Template, override, 4, 4); replicas != 2 {
+		t.Errorf("desiredReplicas() = %d, want 2 for a scale-down", replicas)
+	}
+}
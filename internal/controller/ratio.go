@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+)
+
+// RatioOfConditionType is the ReplicasOverride condition reporting whether
+// the deployment named by its ratioOf exists
+const RatioOfConditionType = "RatioOfResolved"
+
+// ratioOfKey returns the deployment named by the ratioOf of override, which
+// defaults to the namespace of the override
+func ratioOfKey(override *dynamicscalingv1.ReplicasOverride) types.NamespacedName {
+	namespace := override.Spec.RatioOf.Namespace
+	if namespace == "" {
+		namespace = override.Namespace
+	}
+	return types.NamespacedName{Name: override.Spec.RatioOf.Name, Namespace: namespace}
+}
+
+// ratioCeiling returns the most replicas the targets of override may have:
+// the factor of its ratioOf times the current replicas of the deployment,
+// rounded down. It returns false when the override has no ratioOf, and an
+// error when the deployment cannot be read or the factor is invalid.
+func (r *ReplicasOverrideReconciler) ratioCeiling(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) (int32, bool, error) {
+	if override == nil || override.Spec.RatioOf == nil {
+		return 0, false, nil
+	}
+	factor, err := strconv.ParseFloat(override.Spec.RatioOf.Factor, 64)
+	if err != nil || factor < 0 {
+		return 0, false, fmt.Errorf("invalid ratioOf factor %q", override.Spec.RatioOf.Factor)
+	}
+	dependency := &appsv1.Deployment{}
+	if err := r.Get(ctx, ratioOfKey(override), dependency); err != nil {
+		return 0, false, err
+	}
+	var replicas int32 = 1
+	if dependency.Spec.Replicas != nil {
+		replicas = *dependency.Spec.Replicas
+	}
+	return int32(min(math.Floor(factor*float64(replicas)), math.MaxInt32)), true, nil
+}
+
+// ratioStage caps the replicas at the ratio of the deployment the override
+// depends on. A dependency that cannot be read caps nothing, it is reported
+// by the RatioOfResolved condition.
+func (r *ReplicasOverrideReconciler) ratioStage(ctx context.Context, in *decision.Input, e *precedence.Explanation) {
+	if e.Replicas == nil {
+		return
+	}
+	ceiling, found, err := r.ratioCeiling(ctx, in.Override)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("Not capping replicas by ratioOf",
+			"override", in.Override.Name,
+			"namespace", in.Override.Namespace,
+			"error", err.Error())
+		return
+	}
+	if found && *e.Replicas > ceiling {
+		e.SetReplicas(ceiling, true)
+	}
+}
+
+// ratioOfCondition reports whether the ratioOf of override resolves to a
+// deployment, with the ceiling it sets
+func (r *ReplicasOverrideReconciler) ratioOfCondition(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) metav1.Condition {
+	key := ratioOfKey(override)
+	condition := metav1.Condition{
+		Type:               RatioOfConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Found",
+		ObservedGeneration: override.Generation,
+	}
+	ceiling, _, err := r.ratioCeiling(ctx, override)
+	switch {
+	case errors.IsNotFound(err):
+		condition.Status, condition.Reason = metav1.ConditionFalse, "NotFound"
+		condition.Message = fmt.Sprintf("Deployment %s not found, replicas are not capped", key)
+	case err != nil:
+		condition.Status, condition.Reason = metav1.ConditionFalse, "Invalid"
+		condition.Message = fmt.Sprintf("Replicas are not capped: %v", err)
+	default:
+		condition.Message = fmt.Sprintf("Replicas are capped at %d, %s times those of Deployment %s", ceiling, override.Spec.RatioOf.Factor, key)
+	}
+	return condition
+}
+
+// ratioOfRequests maps a deployment to the overrides capping their targets
+// by its replicas, so they are recomputed when it scales
+func (r *ReplicasOverrideReconciler) ratioOfRequests(ctx context.Context, deployment *appsv1.Deployment) []reconcile.Request {
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range overrideList.Items {
+		o := &overrideList.Items[i]
+		if o.Spec.RatioOf != nil && ratioOfKey(o) == client.ObjectKeyFromObject(deployment) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestRatioOf(t *testing.T) {
+	ctx := context.Background()
+	workers := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	proxies := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-proxy", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers-sale", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "workers"},
+			OverrideType:       "override",
+			ReplicasPercentage: 300,
+			RatioOf:            &dynamicscalingv1.RatioReference{Name: "db-proxy", Factor: "1.5"},
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, workers, proxies, override).
+		Build()
+	r := newTestReconciler(c, nil)
+	reconcile := func() int32 {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(workers), workers); err != nil {
+			t.Fatal(err)
+		}
+		return *workers.Spec.Replicas
+	}
+
+	// 300% of 3 is capped at 1.5 times the 2 proxies
+	if got := reconcile(); got != 3 {
+		t.Errorf("replicas = %d, want 3 capped by the proxies", got)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	if condition := meta.FindStatusCondition(override.Status.Conditions, RatioOfConditionType); condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("%s condition = %+v, want True", RatioOfConditionType, condition)
+	}
+
+	// Scaling the proxies maps to the override and lifts the cap
+	if requests := r.ratioOfRequests(ctx, proxies); len(requests) != 1 || requests[0].Name != "workers-sale" {
+		t.Errorf("ratioOfRequests() = %v, want the override", requests)
+	}
+	proxies.Spec.Replicas = int32Ptr(10)
+	if err := c.Update(ctx, proxies); err != nil {
+		t.Fatal(err)
+	}
+	if got := reconcile(); got != 9 {
+		t.Errorf("replicas once the proxies scaled = %d, want 9", got)
+	}
+
+	// A missing dependency caps nothing and is reported
+	if err := c.Delete(ctx, proxies); err != nil {
+		t.Fatal(err)
+	}
+	reconcile()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	if condition := meta.FindStatusCondition(override.Status.Conditions, RatioOfConditionType); condition == nil || condition.Reason != "NotFound" {
+		t.Errorf("%s condition = %+v, want NotFound", RatioOfConditionType, condition)
+	}
+}
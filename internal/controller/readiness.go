@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// ScaleUpStalledConditionType is the ReplicasOverride condition reporting
+// deployments whose new replicas did not become Ready in time
+const ScaleUpStalledConditionType = "ScaleUpStalled"
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+
+// observeReadiness records the Ready replicas of an affected deployment after
+// it was scaled from previous replicas, and counts its unschedulable pods
+// while a scale-up is waiting for them
+func (r *ReplicasOverrideReconciler) observeReadiness(ctx context.Context, affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment, previous int32, now time.Time) {
+	affected.UnschedulablePods = 0
+	if !trackScaleUp(affected, previous, deployment.Status.ReadyReplicas, now) {
+		return
+	}
+
+	unschedulable, err := r.unschedulablePods(ctx, deployment)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to count unschedulable pods",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+		return
+	}
+	affected.UnschedulablePods = unschedulable
+}
+
+// trackScaleUp starts the scale-up clock of affected when its replicas were
+// raised from previous, and stops it once ready covers them. It returns true
+// while the scale-up is waiting for Ready replicas.
+func trackScaleUp(affected *dynamicscalingv1.AffectedDeployment, previous, ready int32, now time.Time) bool {
+	affected.ReadyReplicas = ready
+	if ready >= affected.CurrentReplicas {
+		affected.ScaleUpStartTime = nil
+		return false
+	}
+	if affected.CurrentReplicas > previous {
+		start := metav1.NewTime(now)
+		affected.ScaleUpStartTime = &start
+	}
+	return affected.ScaleUpStartTime != nil
+}
+
+// unschedulablePods counts the pods of a deployment the scheduler could not place
+func (r *ReplicasOverrideReconciler) unschedulablePods(ctx context.Context, deployment *appsv1.Deployment) (int32, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return 0, err
+	}
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(deployment.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return 0, err
+	}
+
+	var unschedulable int32
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+				condition.Reason == corev1.PodReasonUnschedulable {
+				unschedulable++
+				break
+			}
+		}
+	}
+	return unschedulable, nil
+}
+
+// scaleUpStalledCondition returns the ScaleUpStalled condition of an override
+// and, while a scale-up is still within the timeout, when it would stall
+func scaleUpStalledCondition(override *dynamicscalingv1.ReplicasOverride, timeout time.Duration, now time.Time) (metav1.Condition, time.Time) {
+	condition := metav1.Condition{
+		Type:               ScaleUpStalledConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ReplicasReady",
+		ObservedGeneration: override.Generation,
+	}
+
+	var stalled []string
+	var unschedulable int32
+	var deadline time.Time
+	for _, affected := range override.Status.AffectedDeployments {
+		if affected.ScaleUpStartTime == nil {
+			continue
+		}
+		if due := affected.ScaleUpStartTime.Add(timeout); now.Before(due) {
+			if deadline.IsZero() || due.Before(deadline) {
+				deadline = due
+			}
+			continue
+		}
+		description := fmt.Sprintf("%s/%s has %d/%d replicas ready", affected.Namespace, affected.Name, affected.ReadyReplicas, affected.CurrentReplicas)
+		if affected.UnschedulablePods > 0 {
+			description += fmt.Sprintf(", %d unschedulable", affected.UnschedulablePods)
+		}
+		stalled = append(stalled, description)
+		unschedulable += affected.UnschedulablePods
+	}
+	if len(stalled) == 0 {
+		if !deadline.IsZero() {
+			condition.Reason = "ScaleUpInProgress"
+		}
+		return condition, deadline
+	}
+
+	sort.Strings(stalled)
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "ReplicasNotReady"
+	if unschedulable > 0 {
+		condition.Reason = "PodsUnschedulable"
+	}
+	condition.Message = fmt.Sprintf("Scale-up not Ready after %s: %s", timeout, strings.Join(stalled, "; "))
+	return condition, deadline
+}
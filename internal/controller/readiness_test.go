@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestTrackScaleUp(t *testing.T) {
+	now := time.Date(2025, 11, 28, 6, 0, 0, 0, time.UTC)
+	earlier := metav1.NewTime(now.Add(-time.Minute))
+
+	tests := []struct {
+		name        string
+		current     int32
+		previous    int32
+		ready       int32
+		start       *metav1.Time
+		wantWaiting bool
+		wantStart   *metav1.Time
+	}{
+		{name: "scale-up starts the clock", current: 6, previous: 3, ready: 3, wantWaiting: true, wantStart: &metav1.Time{Time: now}},
+		{name: "waiting scale-up keeps its start", current: 6, previous: 6, ready: 4, start: &earlier, wantWaiting: true, wantStart: &earlier},
+		{name: "all ready stops the clock", current: 6, previous: 6, ready: 6, start: &earlier},
+		{name: "scale-down is not followed", current: 2, previous: 3, ready: 1},
+		{name: "unready workload without scale-up is not followed", current: 3, previous: 3, ready: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			affected := &dynamicscalingv1.AffectedDeployment{CurrentReplicas: tt.current, ScaleUpStartTime: tt.start}
+			if got := trackScaleUp(affected, tt.previous, tt.ready, now); got != tt.wantWaiting {
+				t.Errorf("trackScaleUp() = %v, want %v", got, tt.wantWaiting)
+			}
+			if affected.ReadyReplicas != tt.ready {
+				t.Errorf("ReadyReplicas = %d, want %d", affected.ReadyReplicas, tt.ready)
+			}
+			if (affected.ScaleUpStartTime == nil) != (tt.wantStart == nil) ||
+				(tt.wantStart != nil && !affected.ScaleUpStartTime.Equal(tt.wantStart)) {
+				t.Errorf("ScaleUpStartTime = %v, want %v", affected.ScaleUpStartTime, tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestScaleUpStalledCondition(t *testing.T) {
+	now := time.Date(2025, 11, 28, 6, 0, 0, 0, time.UTC)
+	stalledStart := metav1.NewTime(now.Add(-15 * time.Minute))
+	recentStart := metav1.NewTime(now.Add(-2 * time.Minute))
+
+	override := &dynamicscalingv1.ReplicasOverride{Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+		{Name: "web", Namespace: "shop", CurrentReplicas: 6, ReadyReplicas: 3, UnschedulablePods: 2, ScaleUpStartTime: &stalledStart},
+		{Name: "api", Namespace: "shop", CurrentReplicas: 4, ReadyReplicas: 2, ScaleUpStartTime: &recentStart},
+		{Name: "worker", Namespace: "shop", CurrentReplicas: 2, ReadyReplicas: 2},
+	}}}
+
+	condition, stallsAt := scaleUpStalledCondition(override, 10*time.Minute, now)
+	if condition.Status != metav1.ConditionTrue || condition.Reason != "PodsUnschedulable" {
+		t.Errorf("condition = %s/%s, want True/PodsUnschedulable", condition.Status, condition.Reason)
+	}
+	if condition.Message != "Scale-up not Ready after 10m0s: shop/web has 3/6 replicas ready, 2 unschedulable" {
+		t.Errorf("condition message = %q", condition.Message)
+	}
+	if !stallsAt.Equal(recentStart.Add(10 * time.Minute)) {
+		t.Errorf("stallsAt = %v, want the timeout of api", stallsAt)
+	}
+
+	condition, _ = scaleUpStalledCondition(override, time.Hour, now)
+	if condition.Status != metav1.ConditionFalse || condition.Reason != "ScaleUpInProgress" {
+		t.Errorf("condition within the timeout = %s/%s, want False/ScaleUpInProgress", condition.Status, condition.Reason)
+	}
+
+	override.Status.AffectedDeployments = override.Status.AffectedDeployments[2:]
+	if condition, _ = scaleUpStalledCondition(override, time.Hour, now); condition.Reason != "ReplicasReady" {
+		t.Errorf("condition without scale-up = %s, want ReplicasReady", condition.Reason)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+)
+
+// TargetsMatchedConditionType is the ReplicasOverride condition reporting
+// whether it targets any workload
+const TargetsMatchedConditionType = "TargetsMatched"
+
+// ReferencesResolvedConditionType is the GlobalReplicasIgnore condition
+// reporting whether the namespaces and resources it names exist
+const ReferencesResolvedConditionType = "ReferencesResolved"
+
+// overrideMatches returns true if override targets one of the deployments,
+// HPAs or StatefulSets of its namespace
+func overrideMatches(override *dynamicscalingv1.ReplicasOverride, deployments []appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, statefulSets []appsv1.StatefulSet) bool {
+	for i := range deployments {
+		if shouldProcessDeployment(&deployments[i], override) {
+			return true
+		}
+	}
+	if override.Spec.HPARef != nil {
+		key := hpaRefKey(override)
+		for i := range hpas {
+			if client.ObjectKeyFromObject(&hpas[i]) == key {
+				return true
+			}
+		}
+	}
+	if override.Spec.DeploymentRef == nil {
+		for i := range statefulSets {
+			if matcher.SelectsLabels(override, statefulSets[i].Labels) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trackTargetsMatched records since when override matches nothing and sets
+// its TargetsMatched condition, False once it matched nothing for longer than
+// after. It returns true if the status changed.
+func trackTargetsMatched(override *dynamicscalingv1.ReplicasOverride, matched bool, now time.Time, after time.Duration) bool {
+	changed := false
+	switch {
+	case matched && override.Status.UnmatchedSince != nil:
+		override.Status.UnmatchedSince = nil
+		changed = true
+	case !matched && override.Status.UnmatchedSince == nil:
+		override.Status.UnmatchedSince = &metav1.Time{Time: now}
+		changed = true
+	}
+
+	condition := metav1.Condition{
+		Type:               TargetsMatchedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Matched",
+		Message:            "The override targets at least one workload",
+		ObservedGeneration: override.Generation,
+	}
+	if since := override.Status.UnmatchedSince; since != nil {
+		condition.Reason = "NotMatchedYet"
+		condition.Message = fmt.Sprintf("No workload matched since %s", since.UTC().Format(time.RFC3339))
+		if !now.Before(since.Add(after)) {
+			condition.Status, condition.Reason = metav1.ConditionFalse, "NoTargets"
+			condition.Message += ", check the selector, deploymentRef or hpaRef for typos"
+		}
+	}
+	return meta.SetStatusCondition(&override.Status.Conditions, condition) || changed
+}
+
+// checkOverrideTargets reports the overrides of a namespace that target no
+// workload, StatefulSets being only considered when they are scaled
+func (r *ReplicasOverrideReconciler) checkOverrideTargets(ctx context.Context, namespace string, overrides []dynamicscalingv1.ReplicasOverride, deployments []appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, now time.Time) {
+	log := log.FromContext(ctx)
+	cfg := r.configFor(ctx, namespace)
+	if cfg == nil {
+		return
+	}
+
+	var statefulSets []appsv1.StatefulSet
+	if cfg.StatefulSets.Enabled {
+		list := &appsv1.StatefulSetList{}
+		if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			log.Error(err, "Failed to list StatefulSets", "namespace", namespace)
+			return
+		}
+		statefulSets = list.Items
+	}
+
+	for i := range overrides {
+		override := &overrides[i]
+		// Targets of other namespaces are reported by the ReferenceGranted condition
+		if !override.DeletionTimestamp.IsZero() || crossNamespaceRef(override) != "" {
+			continue
+		}
+		matched := overrideMatches(override, deployments, hpas, statefulSets)
+		if !trackTargetsMatched(override, matched, now, cfg.GetUnmatchedTargetsWarning()) {
+			continue
+		}
+
+		if condition := meta.FindStatusCondition(override.Status.Conditions, TargetsMatchedConditionType); condition.Status == metav1.ConditionFalse {
+			log.Info("Override matches no workload",
+				"override", override.Name,
+				"namespace", override.Namespace,
+				"since", override.Status.UnmatchedSince)
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
+
+// ignoreReferencesCondition returns the ReferencesResolved condition of an
+// ignore rule, False when it names namespaces or Deployments that do not exist
+func ignoreReferencesCondition(ignore *dynamicscalingv1.GlobalReplicasIgnore, namespaces []corev1.Namespace, deployments []appsv1.Deployment) metav1.Condition {
+	existing := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		existing[namespace.Name] = true
+	}
+
+	var missing []string
+	for _, namespace := range ignore.Spec.IgnoreNamespaces {
+		if !existing[namespace] {
+			missing = append(missing, fmt.Sprintf("namespace %s", namespace))
+		}
+	}
+	for _, resource := range ignore.Spec.IgnoreResources {
+		if resource.Namespace != "" && !existing[resource.Namespace] {
+			missing = append(missing, fmt.Sprintf("namespace %s of %s %s", resource.Namespace, resource.Kind, resource.Name))
+			continue
+		}
+		// StatefulSets are only readable when they are scaled
+		if resource.Kind == "Deployment" && !deploymentExists(deployments, resource) {
+			missing = append(missing, fmt.Sprintf("Deployment %s", resourceName(resource)))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               ReferencesResolvedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Resolved",
+		Message:            "Every referenced namespace and Deployment exists",
+		ObservedGeneration: ignore.Generation,
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		condition.Status, condition.Reason = metav1.ConditionFalse, "NotFound"
+		condition.Message = fmt.Sprintf("Not found, check for typos: %s", strings.Join(missing, ", "))
+	}
+	return condition
+}
+
+// deploymentExists returns true if a deployment is named by resource, in any
+// namespace when resource has none
+func deploymentExists(deployments []appsv1.Deployment, resource dynamicscalingv1.IgnoredResource) bool {
+	for _, deployment := range deployments {
+		if deployment.Name == resource.Name && (resource.Namespace == "" || resource.Namespace == deployment.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceName returns the namespace/name of resource, or its name without namespace
+func resourceName(resource dynamicscalingv1.IgnoredResource) string {
+	if resource.Namespace == "" {
+		return resource.Name
+	}
+	return resource.Namespace + "/" + resource.Name
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestUnmatchedReferences(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 50,
+			Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"app": "wbe"}},
+		},
+	}
+	deployments := []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "web"}}}}
+	if overrideMatches(override, deployments, nil, nil) {
+		t.Fatal("overrideMatches() = true for a selector with a typo")
+	}
+
+	if !trackTargetsMatched(override, false, now, time.Hour) {
+		t.Fatal("trackTargetsMatched() = false on the first unmatched pass")
+	}
+	if condition := meta.FindStatusCondition(override.Status.Conditions, TargetsMatchedConditionType); condition.Status != metav1.ConditionTrue {
+		t.Errorf("condition before the warning delay = %s, want True", condition.Status)
+	}
+	if trackTargetsMatched(override, false, now.Add(30*time.Minute), time.Hour) {
+		t.Error("trackTargetsMatched() = true without any change")
+	}
+	trackTargetsMatched(override, false, now.Add(time.Hour), time.Hour)
+	if condition := meta.FindStatusCondition(override.Status.Conditions, TargetsMatchedConditionType); condition.Status != metav1.ConditionFalse || condition.Reason != "NoTargets" {
+		t.Errorf("condition after the warning delay = %s/%s, want False/NoTargets", condition.Status, condition.Reason)
+	}
+
+	override.Spec.Selector.MatchLabels["app"] = "web"
+	if !overrideMatches(override, deployments, nil, nil) {
+		t.Fatal("overrideMatches() = false for a matching selector")
+	}
+	trackTargetsMatched(override, true, now.Add(2*time.Hour), time.Hour)
+	if override.Status.UnmatchedSince != nil {
+		t.Error("UnmatchedSince kept once the override matched")
+	}
+	if condition := meta.FindStatusCondition(override.Status.Conditions, TargetsMatchedConditionType); condition.Status != metav1.ConditionTrue {
+		t.Errorf("condition once matched = %s, want True", condition.Status)
+	}
+
+	ignore := &dynamicscalingv1.GlobalReplicasIgnore{
+		Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+			IgnoreNamespaces: []string{"shop", "kube-sytem"},
+			IgnoreResources: []dynamicscalingv1.IgnoredResource{
+				{Kind: "Deployment", Name: "web", Namespace: "shop"},
+				{Kind: "Deployment", Name: "api", Namespace: "shop"},
+				{Kind: "Deployment", Name: "db", Namespace: "payments"},
+			},
+		},
+	}
+	namespaces := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}}
+	condition := ignoreReferencesCondition(ignore, namespaces, deployments)
+	want := "Not found, check for typos: Deployment shop/api, namespace kube-sytem, namespace payments of Deployment db"
+	if condition.Status != metav1.ConditionFalse || condition.Message != want {
+		t.Errorf("ignoreReferencesCondition() = %s %q, want False %q", condition.Status, condition.Message, want)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// recordReplicaChange appends the change of the replicas of deployment from
+// previous to the history of affected, attributed by its change-reason
+// annotation, and keeps the last limit changes
+func recordReplicaChange(affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment, previous int32, now time.Time, limit int) {
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas == previous {
+		return
+	}
+	change := dynamicscalingv1.ReplicaChange{
+		Time: metav1.NewTime(now),
+		From: previous,
+		To:   *deployment.Spec.Replicas,
+	}
+	var reason changeReason
+	if err := json.Unmarshal([]byte(deployment.Annotations[utils.ChangeReasonAnnotation]), &reason); err == nil {
+		change.Override, change.Trigger, change.Percentage = reason.Override, reason.Trigger, reason.Percentage
+	}
+
+	history := append(affected.History, change)
+	if len(history) > limit {
+		history = append([]dynamicscalingv1.ReplicaChange{}, history[len(history)-limit:]...)
+	}
+	affected.History = history
+}
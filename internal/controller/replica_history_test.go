@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestReplicaHistory(t *testing.T) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+			OverrideType:       "override",
+			ReplicasPercentage: 200,
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+		Build()
+	cfg := config.DefaultConfig()
+	cfg.ReplicaHistoryLimit = 2
+	r := newTestReconciler(c, cfg)
+	reconcile := func(percentage int32) []dynamicscalingv1.ReplicaChange {
+		t.Helper()
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+			t.Fatal(err)
+		}
+		override.Spec.ReplicasPercentage = percentage
+		if err := c.Update(ctx, override); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+			t.Fatal(err)
+		}
+		if len(override.Status.AffectedDeployments) != 1 {
+			t.Fatalf("affected deployments = %+v, want api", override.Status.AffectedDeployments)
+		}
+		return override.Status.AffectedDeployments[0].History
+	}
+
+	history := reconcile(200)
+	if len(history) != 1 || history[0].From != 3 || history[0].To != 6 {
+		t.Fatalf("history = %+v, want 3->6", history)
+	}
+	if change := history[0]; change.Override != "api-sale" || change.Trigger != "override" || change.Percentage == nil || *change.Percentage != 200 || change.Time.IsZero() {
+		t.Errorf("change = %+v, want attributed to the override at 200%%", change)
+	}
+
+	// A pass leaving the replicas unchanged records nothing
+	if history := reconcile(200); len(history) != 1 {
+		t.Errorf("history after an unchanged pass = %+v, want one change", history)
+	}
+
+	// The oldest changes are dropped beyond the limit
+	reconcile(300)
+	history = reconcile(100)
+	if len(history) != 2 || history[0].From != 6 || history[0].To != 9 || history[1].From != 9 || history[1].To != 3 {
+		t.Errorf("history = %+v, want 6->9 then 9->3", history)
+	}
+}
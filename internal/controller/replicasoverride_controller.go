@@ -18,26 +18,48 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"strconv"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/authorizer"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/blackout"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/carbon"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/cost"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/disruption"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/manifests"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/notify"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/pressure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/report"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/resolver"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/tracing"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/trigger"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 )
 
 // ReplicasOverrideReconciler reconciles a ReplicasOverride object
@@ -45,6 +67,46 @@ type ReplicasOverrideReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Config *config.Manager
+	// Recorder collects scaling events for the periodic summary report (optional)
+	Recorder *report.Recorder
+	// Carbon lowers percentages of flexible workloads on high carbon intensity (optional)
+	Carbon *carbon.Monitor
+	// Pressure sheds low-priority tiers while the cluster is under pressure (optional)
+	Pressure *pressure.Monitor
+	// Triggers drive override percentages from external signals (optional)
+	Triggers *trigger.Manager
+	// Notifier delivers events to the notification targets of overrides (optional)
+	Notifier *notify.Notifier
+	// Disruption tracks workloads on drained or interrupted nodes (optional)
+	Disruption *disruption.Tracker
+	// LegacyWorkloads also scales ReplicationControllers and bare ReplicaSets
+	LegacyWorkloads bool
+	// JobParallelism also scales the parallelism of running Jobs
+	JobParallelism bool
+	// Resolvers map custom resources of other operators to replica counts, next
+	// to the workloadResolvers of the global config (optional)
+	Resolvers *resolver.Registry
+	// Checkpoint lets a new leader skip re-applying what the previous one applied (optional)
+	Checkpoint *Checkpoint
+	// Decisions are the stages deciding percentages and replicas, DefaultDecisionChain when nil
+	Decisions *decision.Chain
+	// Pager lists the deployments of the cluster a page at a time (optional)
+	Pager *utils.Pager
+	// Budget caps the replica changes per window of the scaleBudget of the global config (optional)
+	Budget *ScaleBudget
+	// Rolling paces the changes of overrides with a Rolling applyStrategy, applied at once when nil (optional)
+	Rolling *RollingApply
+	// Authorizer submits replica changes to the decisionWebhook of the global config (optional)
+	Authorizer *authorizer.Authorizer
+	// Audit checks the managed workloads once the leader started, disabled when nil (optional)
+	Audit *StartupAudit
+	// ManifestPuller downloads the OCI artifacts of simulation previews, disabled when nil (optional)
+	ManifestPuller *manifests.Puller
+	// Startup holds the scaling until the config and ignore rules were read once, not held when nil (optional)
+	Startup *StartupBarrier
+	// StartedAt is when the controller started, the startupGracePeriod of the
+	// global config counting from it. Set by SetupWithManager when zero.
+	StartedAt time.Time
 }
 
 // +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=replicasoverrides,verbs=get;list;watch;create;update;patch;delete
@@ -56,12 +118,45 @@ type ReplicasOverrideReconciler struct {
 
 // Reconcile handles the reconciliation of ReplicasOverride resources
 func (r *ReplicasOverrideReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// Each pass is a span, whose trace ID the scaling metrics carry as exemplar
+	ctx, span := tracing.Tracer().Start(ctx, "ReplicasOverride.Reconcile")
+	defer span.End()
 	log := log.FromContext(ctx)
 
+	// Nothing is scaled before the config and ignore rules of the cluster were read
+	if !r.Startup.Passed() {
+		return ctrl.Result{RequeueAfter: startupRetryInterval}, nil
+	}
+
+	// Drop the work of namespaces being deleted instead of racing their teardown
+	if req.Namespace != "" {
+		gone, err := r.namespaceGone(ctx, req.Namespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if gone {
+			r.forgetNamespace(ctx, req.Namespace)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// A new leader trusts the checkpoint of the previous one instead of re-applying everything
+	var state string
+	if r.Checkpoint != nil {
+		var err error
+		if state, err = r.checkpointState(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+		if skip, until := r.Checkpoint.handoff(ctx, state, time.Now()); skip {
+			return ctrl.Result{RequeueAfter: time.Until(until)}, nil
+		}
+	}
+
 	// 1. First, get the list of ignored deployments
 	ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
 	if err := r.List(ctx, ignoreList); err != nil {
 		log.Error(err, "Failed to list ignore rules")
+		recordReconcileFailure("", err)
 		return ctrl.Result{}, err
 	}
 
@@ -92,13 +187,10 @@ func (r *ReplicasOverrideReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 		// Verifies by labels
 		if len(ignore.Spec.IgnoreLabels) > 0 {
-			deployments := &appsv1.DeploymentList{}
-			if err := r.List(ctx, deployments, client.MatchingLabels(ignore.Spec.IgnoreLabels)); err != nil {
-				continue
-			}
-			for _, deployment := range deployments.Items {
-				ignoredDeployments[deployment.Namespace+"/"+deployment.Name] = true
-			}
+			_ = r.Pager.EachListItem(ctx, r.Client, &appsv1.DeploymentList{}, func(deployment client.Object) error {
+				ignoredDeployments[deployment.GetNamespace()+"/"+deployment.GetName()] = true
+				return nil
+			}, client.MatchingLabels(ignore.Spec.IgnoreLabels))
 		}
 	}
 
@@ -106,111 +198,451 @@ func (r *ReplicasOverrideReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	namespaces := &corev1.NamespaceList{}
 	if err := r.List(ctx, namespaces); err != nil {
 		log.Error(err, "Failed to list namespaces")
+		recordReconcileFailure("", err)
 		return ctrl.Result{}, err
 	}
 
-	// Create a map of ignored namespaces for quick access
+	// Create a map of ignored namespaces for quick access, terminating ones included
 	ignoredNamespaces := make(map[string]bool)
 	for _, ignore := range ignoreList.Items {
 		for _, namespace := range ignore.Spec.IgnoreNamespaces {
 			ignoredNamespaces[namespace] = true
 		}
 	}
+	terminating := make(map[string]bool)
+	for i := range namespaces.Items {
+		if namespaceTerminating(&namespaces.Items[i]) {
+			ignoredNamespaces[namespaces.Items[i].Name] = true
+			terminating[namespaces.Items[i].Name] = true
+		}
+	}
+
+	// Restore the targets of overrides annotated for rollback before anything else
+	allOverrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, allOverrides); err != nil {
+		log.Error(err, "Failed to list overrides")
+		recordReconcileFailure("", err)
+		return ctrl.Result{}, err
+	}
+	activeOverrides := allOverrides.Items[:0]
+	for _, override := range allOverrides.Items {
+		if !terminating[override.Namespace] {
+			activeOverrides = append(activeOverrides, override)
+		}
+	}
+	allOverrides.Items = activeOverrides
+
+	// Overrides may only target deployments of other namespaces granted by a ScalingGrant there
+	grants, err := r.scalingGrants(ctx)
+	if err != nil {
+		log.Error(err, "Failed to list scaling grants")
+		recordReconcileFailure("", err)
+		return ctrl.Result{}, err
+	}
+	r.checkReferenceGrants(ctx, allOverrides.Items, grants)
+	r.checkPercentageExpressions(ctx, allOverrides.Items)
+	grantedByTarget := grantedOverrides(allOverrides.Items, grants)
+
+	// Overrides of a group are rolled back, held and applied as a unit
+	groups := overrideGroups(allOverrides.Items)
+	for i := range allOverrides.Items {
+		if rollbackRequested(&allOverrides.Items[i]) || groupRollbackRequested(groups[allOverrides.Items[i].Spec.Group]) {
+			if err := r.rollbackOverride(ctx, &allOverrides.Items[i]); err != nil {
+				log.Error(err, "Failed to roll back override",
+					"override", allOverrides.Items[i].Name,
+					"namespace", allOverrides.Items[i].Namespace)
+			}
+		}
+	}
+
+	heldGroups := make(map[string]string, len(groups))
+	for name, members := range groups {
+		heldGroups[name] = groupHold(members, time.Now())
+	}
+	pass := newGroupPass()
+
+	// Requeue no later than the end of the earliest active blackout window
+	nextCheck := time.Now().Add(5 * time.Minute)
+
+	// Until the global config is enabled, workloads only it would scale are left alone
+	if active, readyAt := r.globalScalingActive(time.Now()); !active {
+		log.V(1).Info("Global scaling is not active, leaving unmanaged workloads without rules alone", "readyAt", readyAt)
+		if !readyAt.IsZero() && readyAt.Before(nextCheck) {
+			nextCheck = readyAt
+		}
+	}
+
+	// HPAs referenced by hpaRef that do not scale a Deployment are scaled on their own
+	nextCheck = r.processHPARefs(ctx, allOverrides.Items, ignoreList, nextCheck)
+
+	// Replicas the overrides in Monitor mode would set, by override
+	monitored := make(map[types.NamespacedName][]dynamicscalingv1.MonitoredTarget)
+	// Overrides with a deployment that failed in this pass, whose Synced condition stays False
+	failedOverrides := make(map[types.UID]bool)
+	// Annotations of the deployments listed in this pass, and the overrides that
+	// scaled one, whose cost is estimated once all namespaces were processed
+	listedAnnotations := make(map[types.NamespacedName]map[string]string)
+	scaledOverrides := make(map[types.UID]*dynamicscalingv1.ReplicasOverride)
 
 	// 3. For each namespace not ignored, list and process the deployments
 	for _, namespace := range namespaces.Items {
-		// Skips if the namespace is in the ignored list
-		if ignoredNamespaces[namespace.Name] {
+		// Skips if the namespace is in the ignored list, unless an override may scale its deployments anyway
+		namespaceIgnored := ignoredNamespaces[namespace.Name]
+		if namespaceIgnored && (terminating[namespace.Name] || !allowsIgnored(allOverrides.Items, namespace.Name)) {
 			continue
 		}
 
+		if !namespaceIgnored {
+			nextCheck = r.syncNamespaceDefault(ctx, namespace.Name, nextCheck)
+
+			// StatefulSets are opt-in, those scaled by an HPA are scaled through it
+			if cfg := r.Config.GetConfig(); cfg != nil && cfg.StatefulSets.Enabled {
+				nextCheck = r.processStatefulSets(ctx, namespace.Name, ignoreList, nextCheck)
+			}
+			// HPAs of StatefulSets, Rollouts and scalable custom resources
+			if cfg := r.Config.GetConfig(); cfg != nil {
+				nextCheck = r.processHPATargets(ctx, cfg, namespace.Name, ignoreList, nextCheck)
+				// Custom resources mapped to replica counts by workload resolvers
+				nextCheck = r.processResolvedWorkloads(ctx, cfg, namespace.Name, ignoreList, nextCheck)
+			}
+			if r.LegacyWorkloads {
+				nextCheck = r.processLegacyWorkloads(ctx, namespace.Name, ignoreList, nextCheck)
+			}
+			if r.JobParallelism {
+				nextCheck = r.processJobs(ctx, namespace.Name, ignoreList, nextCheck)
+			}
+		}
+
 		// List all deployments in the namespace
 		deployments := &appsv1.DeploymentList{}
 		if err := r.List(ctx, deployments, client.InNamespace(namespace.Name)); err != nil {
 			log.Error(err, "Failed to list deployments in namespace", "namespace", namespace.Name)
+			recordReconcileFailure(namespace.Name, err)
 			continue
 		}
+		deployments.Items = withoutPlaceholders(deployments.Items)
+		for i := range deployments.Items {
+			listedAnnotations[client.ObjectKeyFromObject(&deployments.Items[i])] = deployments.Items[i].Annotations
+		}
+
+		hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := r.List(ctx, hpaList, client.InNamespace(namespace.Name)); err != nil {
+			log.Error(err, "Failed to list HPAs in namespace", "namespace", namespace.Name)
+			recordReconcileFailure(namespace.Name, err)
+			continue
+		}
+
+		// Report workloads targeted by several overrides, only the first one is applied
+		overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+		if err := r.List(ctx, overrideList, client.InNamespace(namespace.Name)); err != nil {
+			log.Error(err, "Failed to list overrides")
+			recordReconcileFailure(namespace.Name, err)
+			continue
+		}
+		r.reportConflicts(ctx, overrideList.Items, findConflicts(overrideList.Items, hpaList.Items, deployments.Items, ignoredDeployments))
+		r.checkOverrideTargets(ctx, namespace.Name, overrideList.Items, deployments.Items, hpaList.Items, time.Now())
+
+		// Ignored deployments are only scaled by an override whose ignorePolicy is override
+		ignored := func(deployment *appsv1.Deployment) bool {
+			return namespaceIgnored || ignoredDeployments[deployment.Namespace+"/"+deployment.Name]
+		}
+		exceptions := findIgnoreExceptions(overrideList.Items, hpaList.Items, deployments.Items, ignoreList.Items, ignored)
+		r.reportIgnoreExceptions(ctx, overrideList.Items, exceptions)
+
+		// Canary-scoped overrides only apply while a canary runs its analysis
+		canaries := r.analyzingCanaries(ctx, namespace.Name)
+
+		// Rolling overrides change the targets of the highest PriorityClass first
+		if hasRollingOverride(overrideList.Items) {
+			r.sortByPriority(ctx, deployments.Items)
+		}
 
 		// 4. For each deployment, check if it should be processed
+		keptPlaceholders := make(map[string]bool)
+		var namespaceTargets int32
 		for _, deployment := range deployments.Items {
-			// Skips if it's in the ignored list
-			if ignoredDeployments[deployment.Namespace+"/"+deployment.Name] {
-				continue
-			}
-
-			// 5. Check if there's a specific override
+			// 5. Check if there's a specific override, the first one targeting the deployment wins
 			var override *dynamicscalingv1.ReplicasOverride
 			overrideList := &dynamicscalingv1.ReplicasOverrideList{}
 			if err := r.List(ctx, overrideList, client.InNamespace(deployment.Namespace)); err != nil {
 				log.Error(err, "Failed to list overrides")
 				continue
 			}
+			// Overrides of the namespace take precedence over those granted from other namespaces
+			overrideList.Items = append(overrideList.Items, grantedByTarget[client.ObjectKeyFromObject(&deployment)]...)
+			matches := canaryOverrides(overrideList.Items, &deployment, deployments.Items, canaries)
+			if matches = append(matches, deploymentOverrides(overrideList.Items, hpaList.Items, &deployment)...); len(matches) > 0 {
+				override = matches[0]
+			}
+			// Skips if it's in the ignored list, unless its override takes precedence over the ignore rules
+			if ignored(&deployment) && (override == nil || override.Spec.IgnorePolicy != dynamicscalingv1.IgnorePolicyOverride) {
+				continue
+			}
+			if override != nil && override.Spec.Placeholder != nil {
+				keptPlaceholders[placeholderName(&deployment)] = true
+			}
+			// Overrides in Monitor mode only report the replicas they would set
+			if monitoring(override) {
+				r.monitorDeployment(ctx, override, &deployment, hpaList.Items, monitored)
+				continue
+			}
+			if override == nil {
+				namespaceTargets++
+				if r.globalScalingHeld(ctx, &deployment, override) {
+					continue
+				}
+			}
 
-			// Search for an override that matches the deployment
-			for _, o := range overrideList.Items {
-				if o.Spec.DeploymentRef != nil {
-					if o.Spec.DeploymentRef.Name == deployment.Name &&
-						(o.Spec.DeploymentRef.Namespace == "" || o.Spec.DeploymentRef.Namespace == deployment.Namespace) {
-						override = &o
-						break
-					}
-				} else if o.Spec.Selector != nil && len(o.Spec.Selector.MatchLabels) > 0 {
-					matches := true
-					for key, value := range o.Spec.Selector.MatchLabels {
-						if deployment.Labels[key] != value {
-							matches = false
-							break
-						}
-					}
-					if matches {
-						override = &o
-						break
+			// Paused overrides and overrides in a blackout window must not change replicas at all
+			if override != nil {
+				if isPaused(override) || rollbackRequested(override) || heldGroups[override.Spec.Group] != "" {
+					continue
+				}
+				// A deployment recreated with the same name starts over, the status of the previous one is stale
+				if forgetRecreated(override, &deployment) {
+					log.Info("Deployment was recreated, recording its originals again",
+						"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+						"override", override.Name)
+				}
+				if frozen, until := r.checkBlackout(ctx, override); frozen {
+					if until.Before(nextCheck) {
+						nextCheck = until
 					}
+					continue
+				}
+
+				// Boost deployments warming up after a rollout, and revert them once the boost expires
+				if until := trackWarmUp(override, &deployment, time.Now()); !until.IsZero() && until.Before(nextCheck) {
+					nextCheck = until
+				}
+
+				// A scale-down reverted by its verification is held until the override changes
+				if verificationHeld(override, deploymentBackup(override, deployment.Namespace, deployment.Name)) {
+					continue
+				}
+			}
+
+			// Leave brand-new deployments alone until their rollout settled and their HPA collected metrics
+			if young, until := r.workloadTooYoung(ctx, "Deployment", &deployment, override, time.Now()); young {
+				if until.Before(nextCheck) {
+					nextCheck = until
 				}
+				continue
 			}
 
 			// 6. Process the deployment with the override or global configuration
-			if err := r.processDeployment(ctx, &deployment, override); err != nil {
+			var previousReplicas int32
+			if deployment.Spec.Replicas != nil {
+				previousReplicas = *deployment.Spec.Replicas
+			}
+			var placeholderPods int32
+			var err error
+			grouped := override != nil && override.Spec.Group != ""
+			var target groupTarget
+			if grouped {
+				target = snapshotGroupTarget(override, &deployment, hpaList.Items)
+			}
+			if override != nil && override.Spec.Placeholder != nil {
+				// Reserve the capacity of the override with placeholder pods instead of scaling the deployment
+				placeholderPods, err = r.processPlaceholder(ctx, &deployment, override)
+			} else {
+				err = r.processDeployment(ctx, &deployment, override)
+			}
+			if grouped && !errors.Is(err, errKindNotTargeted) && !errors.Is(err, errRolloutInProgress) && !changeDeferred(err) && !errors.Is(err, errDownscaleDraining) {
+				pass.record(target, err)
+			}
+			if errors.Is(err, errKindNotTargeted) || changeDeferred(err) {
+				continue
+			} else if errors.Is(err, errRolloutInProgress) {
+				if until := time.Now().Add(rolloutRecheckInterval); until.Before(nextCheck) {
+					nextCheck = until
+				}
+				continue
+			} else if errors.Is(err, errDownscaleDraining) {
+				if until := drainUntil(&deployment); !until.IsZero() && until.Before(nextCheck) {
+					nextCheck = until
+				}
+				continue
+			} else if err != nil {
+				reason := recordFailure(deployment.Namespace, metrics.TargetKindDeployment, override, err)
 				log.Error(err, "Failed to process deployment",
 					"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
-					"hasOverride", override != nil)
+					"hasOverride", override != nil,
+					"reason", reason)
+				if override != nil {
+					failedOverrides[override.UID] = true
+					r.reportSyncFailure(ctx, override, &deployment, reason, err)
+				}
 				continue
 			}
 
+			// Keep cluster-autoscaler from undoing a scale-up while its protection window lasts
+			if until := r.protectFromScaleDown(ctx, override, &deployment, time.Now()); !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+
+			// Give the deployment traffic as its replicas become ready
+			if pending, err := r.shiftTraffic(ctx, override, &deployment, *deployment.Spec.Replicas); err != nil {
+				log.Error(err, "Failed to shift traffic",
+					"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+			} else if until := time.Now().Add(rolloutRecheckInterval); pending && until.Before(nextCheck) {
+				nextCheck = until
+			}
+
 			// Update the override status with the affected deployment
 			if override != nil {
+				previous := override.Status.DeepCopy()
+				originalReplicas := utils.GetOriginalReplicas(&deployment)
+
 				// Check if the deployment already exists in the status
-				deploymentExists := false
-				for _, affected := range override.Status.AffectedDeployments {
-					if affected.Name == deployment.Name && affected.Namespace == deployment.Namespace {
-						deploymentExists = true
-						affected.CurrentReplicas = *deployment.Spec.Replicas
+				var affected *dynamicscalingv1.AffectedDeployment
+				for i := range override.Status.AffectedDeployments {
+					if override.Status.AffectedDeployments[i].Name == deployment.Name && override.Status.AffectedDeployments[i].Namespace == deployment.Namespace {
+						affected = &override.Status.AffectedDeployments[i]
 						break
 					}
 				}
 
 				// If it doesn't exist, add to the status
-				if !deploymentExists {
+				if affected == nil {
 					override.Status.AffectedDeployments = append(override.Status.AffectedDeployments, dynamicscalingv1.AffectedDeployment{
-						Name:            deployment.Name,
-						Namespace:       deployment.Namespace,
-						CurrentReplicas: *deployment.Spec.Replicas,
+						Name:      deployment.Name,
+						Namespace: deployment.Namespace,
 					})
+					affected = &override.Status.AffectedDeployments[len(override.Status.AffectedDeployments)-1]
+				}
+				affected.UID = deployment.UID
+				affected.OriginalReplicas = originalReplicas
+				r.backupHPAOriginals(ctx, affected, &deployment, hpaList.Items)
+				affected.CurrentReplicas = *deployment.Spec.Replicas
+				affected.CurrentPercentage = overridePercentage(override, deployment.Labels)
+				affected.BrokenHPA = deployment.Annotations[utils.BrokenHPAAnnotation]
+				affected.PlaceholderReplicas = placeholderPods
+				affected.DecisionStages = decisionStages(&deployment)
+
+				// Follow the new replicas of a scale-up until they are Ready
+				now := time.Now()
+				r.observeReadiness(ctx, affected, &deployment, previousReplicas, now)
+				if cfg := r.Config.GetConfig(); cfg != nil {
+					condition, stallsAt := scaleUpStalledCondition(override, cfg.GetScaleUpReadyTimeout(), now)
+					meta.SetStatusCondition(&override.Status.Conditions, condition)
+					if !stallsAt.IsZero() && stallsAt.Before(nextCheck) {
+						nextCheck = stallsAt
+					}
 				}
 
-				// Update the override status
-				if err := r.Status().Update(ctx, override); err != nil {
+				// Keep the last replica changes, so the status tells what changed the deployment and when
+				historyLimit := r.Config.GetConfig().GetReplicaHistoryLimit()
+				recordReplicaChange(affected, &deployment, previousReplicas, now, historyLimit)
+				applied := *deployment.Spec.Replicas
+
+				// Check availability after a scale-down and revert it if it dropped
+				if next := r.verifyScaleDown(ctx, override, affected, &deployment, previousReplicas, now); !next.IsZero() && next.Before(nextCheck) {
+					nextCheck = next
+				}
+				recordReplicaChange(affected, &deployment, applied, now, historyLimit)
+				setScaleDownVerifiedCondition(override)
+				clearInvalidState(override)
+				if !failedOverrides[override.UID] {
+					setSyncedCondition(override)
+				}
+				if override.Spec.RatioOf != nil {
+					meta.SetStatusCondition(&override.Status.Conditions, r.ratioOfCondition(ctx, override))
+				}
+				scaledOverrides[override.UID] = override
+
+				// Update the override status, only when it changed to spare the API server a write per pass
+				if equality.Semantic.DeepEqual(previous, &override.Status) {
+					log.V(1).Info("Override status unchanged, skipping update",
+						"override", override.Name,
+						"namespace", override.Namespace)
+				} else if err := r.Status().Update(ctx, override); err != nil {
 					log.Error(err, "Failed to update override status",
 						"override", override.Name,
 						"namespace", override.Namespace)
 				}
 			}
 		}
+		r.prunePlaceholders(ctx, namespace.Name, keptPlaceholders)
+		if !namespaceIgnored {
+			r.syncNamespaceOverride(ctx, namespace.Name, namespaceTargets)
+		}
 	}
 
-	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+	// Estimate the cost of each override once, from the deployments listed above
+	for _, override := range scaledOverrides {
+		previous := override.Status.DeepCopy()
+		r.updateCostEstimate(ctx, override, listedAnnotations)
+		if equality.Semantic.DeepEqual(previous, &override.Status) {
+			continue
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override cost estimate",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+
+	r.reportMonitoredTargets(ctx, allOverrides.Items, monitored)
+	r.settleGroups(ctx, groups, heldGroups, pass, time.Now())
+
+	// Retry the changes deferred by the scale budget or Rolling overrides once they allow them
+	if until := r.budgetRecheck(time.Now()); !until.IsZero() && until.Before(nextCheck) {
+		nextCheck = until
+	}
+	if until := r.Rolling.paced(time.Now()); !until.IsZero() && until.Before(nextCheck) {
+		nextCheck = until
+	}
+
+	r.Checkpoint.save(ctx, state)
+	return ctrl.Result{RequeueAfter: time.Until(nextCheck)}, nil
 }
 
+// checkBlackout reports whether override is inside a blackout window, and until
+// when, keeping its Blackout condition up to date
+func (r *ReplicasOverrideReconciler) checkBlackout(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) (bool, time.Time) {
+	log := log.FromContext(ctx)
+
+	condition := metav1.Condition{
+		Type:               blackout.ConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "OutsideBlackoutWindow",
+		ObservedGeneration: override.Generation,
+	}
+	window, until, err := blackout.Active(override.Spec.BlackoutWindows, time.Now())
+	if err != nil {
+		log.Error(err, "Ignoring invalid blackout windows", "override", override.Name, "namespace", override.Namespace)
+		condition.Reason, condition.Message = "InvalidBlackoutWindow", err.Error()
+	}
+	if window != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InsideBlackoutWindow"
+		condition.Message = fmt.Sprintf("Replica changes are frozen by window %q until %s", window.Name, until.UTC().Format(time.RFC3339))
+	}
+
+	if len(override.Spec.BlackoutWindows) > 0 && meta.SetStatusCondition(&override.Status.Conditions, condition) {
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+	if window == nil {
+		return false, time.Time{}
+	}
+
+	log.V(1).Info("Override is in a blackout window, skipping",
+		"override", override.Name,
+		"namespace", override.Namespace,
+		"window", window.Name,
+		"until", until)
+	return true, until
+}
+
+// errKindNotTargeted is returned by processDeployment when the targetKinds of
+// the override exclude how the deployment is scaled
+var errKindNotTargeted = errors.New("kind not targeted by the override")
+
 // processDeployment handles the scaling of a single deployment
 func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) error {
 	log := log.FromContext(ctx)
@@ -221,6 +653,18 @@ func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, depl
 		return err
 	}
 
+	// Defer to KEDA, Argo Rollouts and custom scalers according to the configured mode
+	if cfg := r.configFor(ctx, deployment.Namespace); cfg != nil {
+		if scaler := detectExternalScaler(cfg, deployment, hpaList.Items, r.rolloutForDeployment(ctx, deployment)); scaler != nil {
+			switch scaler.mode {
+			case config.ScalerModeIgnore:
+				return nil
+			case config.ScalerModeLimitsOnly:
+				return r.processExternalScaler(ctx, cfg, deployment, scaler, hpaList.Items, override)
+			}
+		}
+	}
+
 	var existingHPA *autoscalingv2.HorizontalPodAutoscaler
 	for _, hpa := range hpaList.Items {
 		if hpa.Spec.ScaleTargetRef.Kind == "Deployment" &&
@@ -231,20 +675,71 @@ func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, depl
 		}
 	}
 
+	// Scale the replicas directly while the HPA cannot scale, e.g. without metrics-server
+	broken := brokenHPA(r.configFor(ctx, deployment.Namespace), existingHPA, deployment.Annotations[utils.BrokenHPAAnnotation], time.Now())
+	if previous := deployment.Annotations[utils.BrokenHPAAnnotation]; broken == "" && previous != "" && existingHPA != nil {
+		log.Info("HPA recovered, handing the deployment back to it",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"hpa", previous)
+	} else if broken != "" && previous == "" {
+		log.Info("HPA cannot scale, taking over the replicas of the deployment",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"hpa", broken)
+	}
+	if broken != "" {
+		existingHPA = nil
+	}
+
+	// The override may be restricted to HPA limits or to raw replicas
+	if existingHPA != nil && !targetsKind(override, metrics.TargetKindHPA) ||
+		existingHPA == nil && !targetsKind(override, metrics.TargetKindDeployment) {
+		return errKindNotTargeted
+	}
+
+	// Leave intentionally scaled-to-zero workloads parked unless explicitly targeted
+	if existingHPA == nil && isProtectedAtZero(r.configFor(ctx, deployment.Namespace), deployment, override) {
+		log.V(1).Info("Deployment is scaled to zero, skipping",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+		return nil
+	}
+
 	// Get current annotations or initialize empty map
 	if deployment.Annotations == nil {
 		deployment.Annotations = make(map[string]string)
 	}
 
-	// Store original replicas if not already stored
+	// Store original replicas if not already stored, or restore them from the override status if the annotation was deleted or corrupt
+	repaired := r.repairOriginals(ctx, "Deployment", deployment, override)
+	modeChanged := deployment.Annotations[utils.BrokenHPAAnnotation] != broken
+	if broken != "" {
+		deployment.Annotations[utils.BrokenHPAAnnotation] = broken
+	} else {
+		delete(deployment.Annotations, utils.BrokenHPAAnnotation)
+	}
 	if _, exists := deployment.Annotations[utils.OriginalReplicasAnnotation]; !exists {
-		if existingHPA != nil {
+		if backup := deploymentBackup(override, deployment.Namespace, deployment.Name); backup != nil {
+			log.Info("Restoring original replicas from the override status",
+				"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+				"original", backup.OriginalReplicas)
+			deployment.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(backup.OriginalReplicas), 10)
+		} else if existingHPA != nil {
 			// If HPA exists, use its minReplicas as the original replicas
 			deployment.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(*existingHPA.Spec.MinReplicas), 10)
 		} else {
 			deployment.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(*deployment.Spec.Replicas), 10)
 		}
 	}
+	// Tie the originals to this deployment, a recreated one records its own
+	if deployment.UID != "" {
+		deployment.Annotations[utils.OriginalsUIDAnnotation] = string(deployment.UID)
+	}
+
+	// Re-capture the original replicas of a deployment resized by another field manager
+	var baselineChanged bool
+	if existingHPA == nil && deployment.Spec.Replicas != nil {
+		baselineChanged = r.refreshTargetBaseline(ctx, "Deployment", deployment, override,
+			baselineField{field: "replicas", annotation: utils.OriginalReplicasAnnotation, value: *deployment.Spec.Replicas})
+	}
 
 	// Mark as managed by us
 	if override != nil {
@@ -257,77 +752,113 @@ func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, depl
 	// Add management mode annotation for troubleshooting
 	if existingHPA != nil {
 		deployment.Annotations[utils.ManagementModeAnnotation] = "hpa"
-		// Update the deployment first with retry
-		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			// Get the latest version before attempting to update
-			latest := &appsv1.Deployment{}
-			if err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, latest); err != nil {
-				return err
-			}
-			// Copy annotations to latest version
-			if latest.Annotations == nil {
-				latest.Annotations = make(map[string]string)
-			}
-			latest.Annotations[utils.ManagementModeAnnotation] = "hpa"
-			latest.Annotations[utils.GlobalConfigManagedAnnotation] = "true"
-			latest.Annotations[utils.OriginalReplicasAnnotation] = deployment.Annotations[utils.OriginalReplicasAnnotation]
-			return r.Update(ctx, latest)
+		// Update the deployment first
+		err := r.updateDeploymentAnnotations(ctx, deployment, map[string]string{
+			utils.ManagementModeAnnotation:      "hpa",
+			utils.BrokenHPAAnnotation:           "",
+			utils.GlobalConfigManagedAnnotation: "true",
+			utils.OriginalReplicasAnnotation:    deployment.Annotations[utils.OriginalReplicasAnnotation],
+			utils.OriginalsUIDAnnotation:        deployment.Annotations[utils.OriginalsUIDAnnotation],
 		})
 		if err != nil {
 			return err
 		}
 		// Then process the HPA
-		return r.processHPA(ctx, existingHPA, override)
+		return r.processHPA(ctx, existingHPA, deployment, &deployment.Spec.Template, override)
 	} else {
 		deployment.Annotations[utils.ManagementModeAnnotation] = "direct"
 	}
 
 	// Get global config
-	config := r.Config.GetConfig()
+	config := r.configFor(ctx, deployment.Namespace)
 	if config == nil {
 		return fmt.Errorf("global config not found")
 	}
 
-	// Get original replicas
-	originalReplicas, _ := strconv.ParseInt(deployment.Annotations[utils.OriginalReplicasAnnotation], 10, 32)
-	var percentage int32
+	// Get original replicas, falling back to the spec if the annotation is not a valid count
+	originalReplicas := utils.GetOriginalReplicas(deployment)
 
-	if override != nil {
-		// Use override percentage
-		percentage = override.Spec.ReplicasPercentage
-	} else {
-		// Use global percentage
-		percentage = config.GlobalPercentage
-	}
+	// Calculate target replicas based on percentage, within the min/max limits from config
+	targetReplicas, explanation := r.desiredReplicas(ctx, config, deployment, &deployment.Spec.Template, override, scalingBase(deployment, override), specReplicas(deployment.Spec.Replicas))
+	percentage, trigger, clamped := explanation.Percentage, explanation.Trigger, explanation.Clamped
 
-	// Calculate target replicas based on percentage
-	targetReplicas := int32(float64(originalReplicas) * float64(percentage) / 100.0)
+	// If HPA exists, let it manage the replicas
+	if existingHPA != nil {
+		// Only update the HPA
+		return r.processHPA(ctx, existingHPA, deployment, &deployment.Spec.Template, override)
+	}
 
-	// Apply min/max limits from config
-	if targetReplicas < config.MinReplicas {
-		targetReplicas = config.MinReplicas
+	// Hold a change landing in the middle of a rollout, or apply it in one write
+	hold, atomic := rolloutChange(config, deployment, targetReplicas)
+	if hold {
+		log.Info("Holding replica change until the rollout completes",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"target", targetReplicas)
+		return errRolloutInProgress
 	}
-	if targetReplicas > config.MaxReplicas {
-		targetReplicas = config.MaxReplicas
+
+	// Remove replicas only as fast as the rollout strategy and PDBs allow
+	if config.ScaleDown.Stepped && !atomic && deployment.Spec.Replicas != nil && targetReplicas < *deployment.Spec.Replicas {
+		pdbAllowed, err := r.pdbDisruptionsAllowed(ctx, deployment.Namespace, deployment.Spec.Template.Labels)
+		if err != nil {
+			return err
+		}
+		if step, pending := scaleDownStep(deployment, targetReplicas, pdbAllowed); pending {
+			log.V(1).Info("Scaling deployment down in steps",
+				"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+				"target", targetReplicas,
+				"step", step)
+			targetReplicas = step
+		}
 	}
 
-	// If HPA exists, let it manage the replicas
-	if existingHPA != nil {
-		// Only update the HPA
-		return r.processHPA(ctx, existingHPA, override)
+	// Give the pods a scale-down removes the preDownscaleDelay of the override to drain
+	draining, drainChanged, err := r.drainBeforeScaleDown(ctx, deployment, override, targetReplicas, time.Now())
+	if err != nil {
+		return err
+	}
+	if draining {
+		if drainChanged {
+			if err := r.Update(ctx, deployment); err != nil {
+				return err
+			}
+		}
+		return errDownscaleDraining
 	}
 
 	// Check if update is needed
+	explanation.SetReplicas(targetReplicas, clamped)
 	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == targetReplicas {
 		log.V(1).Info("Deployment already at desired replicas, skipping update",
 			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
 			"replicas", targetReplicas)
-		return nil
+		if baselineChanged || repaired || modeChanged || drainChanged {
+			deployment.Annotations[utils.ExplainAnnotation] = explanation.JSON()
+			return r.Update(ctx, deployment)
+		}
+		return r.updateExplanation(ctx, deployment, explanation)
 	}
 
 	// Update replicas only if no HPA exists
+	var previousReplicas int32
+	if deployment.Spec.Replicas != nil {
+		previousReplicas = *deployment.Spec.Replicas
+	}
+	labels := scalingLabels(deployment.Namespace, metrics.TargetKindDeployment, override)
+	labels.Trigger = trigger
+	if err := r.paceChange(ctx, labels, override, deployment.Name); err != nil {
+		return err
+	}
+	if targetReplicas, err = r.authorizeChange(ctx, labels, deployment.Name, previousReplicas, targetReplicas); err != nil {
+		return err
+	}
+	explanation.SetReplicas(targetReplicas, clamped)
+	if err := r.spendBudget(ctx, labels, deployment.Name, previousReplicas, targetReplicas); err != nil {
+		return err
+	}
 	deployment.Spec.Replicas = &targetReplicas
 	deployment.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	deployment.Annotations[utils.ExplainAnnotation] = explanation.JSON()
 
 	log.Info("Updating deployment replicas",
 		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
@@ -337,12 +868,20 @@ func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, depl
 		"mode", deployment.Annotations[utils.ManagementModeAnnotation])
 
 	// Update the deployment
-	err := r.Update(ctx, deployment)
+	reasonPercentage := &percentage
+	if enforcesBounds(override) {
+		reasonPercentage = nil
+	}
+	err = r.Update(ctx, deployment, newChangeReason(labels, reasonPercentage).record(deployment))
 	if err != nil {
 		log.Error(err, "Failed to update deployment",
 			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(deployment.Namespace, deployment.Name, labels, originalReplicas, previousReplicas, targetReplicas, clamped, err)
 		return err
 	}
+	metrics.RecordScaling(ctx, labels, previousReplicas, targetReplicas, percentage, clamped)
+	r.recordEvent(deployment.Namespace, deployment.Name, labels, originalReplicas, previousReplicas, targetReplicas, clamped, nil)
 
 	log.Info("Successfully updated deployment replicas",
 		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
@@ -351,13 +890,149 @@ func (r *ReplicasOverrideReconciler) processDeployment(ctx context.Context, depl
 	return nil
 }
 
+// isProtectedAtZero returns true if the deployment is at zero replicas, the
+// config protects such workloads and the override does not target it by name.
+// Deployments with original replicas above zero, recorded in their annotation
+// or in the override status, were parked by the controller and are not protected.
+func isProtectedAtZero(cfg *config.GlobalConfig, deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) bool {
+	if cfg == nil || !cfg.ProtectScaledToZero {
+		return false
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		return false
+	}
+	if original, ok := utils.ParseReplicas(deployment.Annotations[utils.OriginalReplicasAnnotation]); ok && original > 0 {
+		return false
+	}
+	if backup := deploymentBackup(override, deployment.Namespace, deployment.Name); backup != nil && backup.OriginalReplicas > 0 {
+		return false
+	}
+	explicit := override != nil && override.Spec.DeploymentRef != nil &&
+		override.Spec.DeploymentRef.Name == deployment.Name &&
+		(override.Spec.DeploymentRef.Namespace == "" || override.Spec.DeploymentRef.Namespace == deployment.Namespace)
+	return !explicit
+}
+
 func calculateTargetReplicas(deployment *appsv1.Deployment, percentage int32) int32 {
-	originalReplicas, _ := strconv.ParseInt(deployment.Annotations[utils.OriginalReplicasAnnotation], 10, 32)
-	return int32(float64(originalReplicas) * float64(percentage) / 100.0)
+	return utils.PercentOf(utils.GetOriginalReplicas(deployment), percentage)
 }
 
-// processHPA handles updating an HPA's min/max replicas
-func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler, override *dynamicscalingv1.ReplicasOverride) error {
+// resolvePercentage resolves the percentage of a workload through the stages
+// of the decision chain that also decide HPA limits: the global config,
+// namespace default, override and workload annotation, in increasing order of
+// precedence, then the trigger, expression, carbon and pressure adjustments.
+// The explanation records every step.
+func (r *ReplicasOverrideReconciler) resolvePercentage(ctx context.Context, workload metav1.Object, template *corev1.PodTemplateSpec, override *dynamicscalingv1.ReplicasOverride) precedence.Explanation {
+	return r.decisionChain().Percentage(ctx, &decision.Input{
+		Workload: workload,
+		Template: template,
+		Override: override,
+		Config:   r.configFor(ctx, workload.GetNamespace()),
+	})
+}
+
+// disruptionBoost returns the percentage points added to a workload whose pods
+// ran on a cordoned, drained or interrupted node, or 0
+func (r *ReplicasOverrideReconciler) disruptionBoost(cfg *config.GlobalConfig, workload metav1.Object) int32 {
+	if !cfg.NodeDisruption.Enabled || cfg.NodeDisruption.BoostPercentage <= 0 {
+		return 0
+	}
+	if !r.Disruption.IsAffected(types.NamespacedName{Name: workload.GetName(), Namespace: workload.GetNamespace()}) {
+		return 0
+	}
+	return cfg.NodeDisruption.BoostPercentage
+}
+
+// scalingLabels builds the metric labels for a target scaled by override, or by the global config when override is nil
+func scalingLabels(namespace, targetKind string, override *dynamicscalingv1.ReplicasOverride) metrics.ScalingLabels {
+	labels := metrics.ScalingLabels{
+		Override:   metrics.GlobalOverride,
+		Namespace:  namespace,
+		TargetKind: targetKind,
+		Trigger:    metrics.TriggerGlobal,
+	}
+	if override != nil {
+		labels.Override = override.Name
+		labels.Trigger = metrics.TriggerOverride
+	}
+	return labels
+}
+
+// updateCostEstimate sets the estimated hourly cost delta of the override from its affected deployments,
+// taking their annotations from listed and getting only those not listed
+func (r *ReplicasOverrideReconciler) updateCostEstimate(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, listed map[types.NamespacedName]map[string]string) {
+	estimator := cost.NewEstimator(r.Config.GetConfig().Cost)
+	if !estimator.Enabled() {
+		override.Status.EstimatedCost = nil
+		return
+	}
+
+	var delta float64
+	for _, affected := range override.Status.AffectedDeployments {
+		key := types.NamespacedName{Name: affected.Name, Namespace: affected.Namespace}
+		annotations, ok := listed[key]
+		if !ok {
+			deployment := &appsv1.Deployment{}
+			if err := r.Get(ctx, key, deployment); err == nil {
+				annotations = deployment.Annotations
+			}
+		}
+		delta += estimator.HourlyDelta(affected.Namespace, annotations, affected.OriginalReplicas, affected.CurrentReplicas)
+	}
+
+	override.Status.EstimatedCost = &dynamicscalingv1.CostEstimate{
+		HourlyDelta: cost.FormatAmount(delta),
+		Currency:    estimator.Currency(),
+	}
+	metrics.SetEstimatedHourlyCostDelta(scalingLabels(override.Namespace, metrics.TargetKindDeployment, override), delta)
+}
+
+// recordEvent adds a scaling event to the report recorder
+func (r *ReplicasOverrideReconciler) recordEvent(namespace, name string, labels metrics.ScalingLabels, original, previous, target int32, clamped bool, err error) {
+	event := report.Event{
+		Kind:             labels.TargetKind,
+		Namespace:        namespace,
+		Name:             name,
+		OriginalReplicas: original,
+		PreviousReplicas: previous,
+		TargetReplicas:   target,
+		Clamped:          clamped,
+	}
+	if labels.Override != metrics.GlobalOverride {
+		event.Override = labels.Override
+	}
+	if err != nil {
+		event.Error = err.Error()
+		event.Reason = string(failure.Classify(err))
+	}
+	r.Recorder.Record(event)
+
+	if event.Override != "" {
+		notification := notify.Event{
+			Type:      dynamicscalingv1.NotificationScaled,
+			Override:  event.Override,
+			Namespace: namespace,
+			Kind:      labels.TargetKind,
+			Name:      name,
+			Previous:  previous,
+			Target:    target,
+			Trigger:   labels.Trigger,
+			Error:     event.Error,
+			Reason:    event.Reason,
+		}
+		if err != nil {
+			notification.Type = dynamicscalingv1.NotificationFailed
+		} else if labels.Trigger == metrics.TriggerRollback || labels.Trigger == metrics.TriggerVerification {
+			notification.Type = dynamicscalingv1.NotificationRolledBack
+		}
+		r.Notifier.Notify(notification)
+	}
+}
+
+// processHPA handles updating an HPA's min/max replicas. The percentage is
+// resolved for workload, the object scaled by the HPA, or the HPA itself when
+// the controller does not manage the kind it scales.
+func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler, workload metav1.Object, template *corev1.PodTemplateSpec, override *dynamicscalingv1.ReplicasOverride) error {
 	log := log.FromContext(ctx)
 
 	// Get current annotations or initialize empty map
@@ -365,9 +1040,25 @@ func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autosc
 		hpa.Annotations = make(map[string]string)
 	}
 
-	// Store original min/max if not already stored
+	// Store original min/max if not already stored, or restore them from the override status if the annotations were deleted or corrupt
+	r.repairOriginals(ctx, "HorizontalPodAutoscaler", hpa, override)
+	if backup := hpaBackup(override, hpa); backup != nil {
+		if _, exists := hpa.Annotations[utils.OriginalMinReplicasAnnotation]; !exists {
+			log.Info("Restoring original HPA limits from the override status",
+				"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name))
+			hpa.Annotations[utils.OriginalMinReplicasAnnotation] = strconv.FormatInt(int64(*backup.OriginalHPAMinReplicas), 10)
+		}
+		if _, exists := hpa.Annotations[utils.OriginalMaxReplicasAnnotation]; !exists {
+			hpa.Annotations[utils.OriginalMaxReplicasAnnotation] = strconv.FormatInt(int64(*backup.OriginalHPAMaxReplicas), 10)
+		}
+	}
 	if _, exists := hpa.Annotations[utils.OriginalMinReplicasAnnotation]; !exists {
-		hpa.Annotations[utils.OriginalMinReplicasAnnotation] = strconv.FormatInt(int64(*hpa.Spec.MinReplicas), 10)
+		// Kubernetes defaults the minReplicas of an HPA to 1
+		var minReplicas int32 = 1
+		if hpa.Spec.MinReplicas != nil {
+			minReplicas = *hpa.Spec.MinReplicas
+		}
+		hpa.Annotations[utils.OriginalMinReplicasAnnotation] = strconv.FormatInt(int64(minReplicas), 10)
 	}
 	if _, exists := hpa.Annotations[utils.OriginalMaxReplicasAnnotation]; !exists {
 		hpa.Annotations[utils.OriginalMaxReplicasAnnotation] = strconv.FormatInt(int64(hpa.Spec.MaxReplicas), 10)
@@ -382,48 +1073,49 @@ func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autosc
 	}
 	hpa.Annotations[utils.HPAManagedAnnotation] = "true"
 
+	// Re-capture the original limits of an HPA changed by another field manager
+	if hpa.Spec.MinReplicas != nil {
+		r.refreshTargetBaseline(ctx, "HorizontalPodAutoscaler", hpa, override,
+			baselineField{field: "minReplicas", annotation: utils.OriginalMinReplicasAnnotation, value: *hpa.Spec.MinReplicas},
+			baselineField{field: "maxReplicas", annotation: utils.OriginalMaxReplicasAnnotation, value: hpa.Spec.MaxReplicas})
+	}
+
 	// Get global config
-	config := r.Config.GetConfig()
+	config := r.configFor(ctx, hpa.Namespace)
 	if config == nil {
 		return fmt.Errorf("global config not found")
 	}
 
 	// Calculate target min/max replicas
-	originalMinReplicas, _ := strconv.ParseInt(hpa.Annotations[utils.OriginalMinReplicasAnnotation], 10, 32)
-	originalMaxReplicas, _ := strconv.ParseInt(hpa.Annotations[utils.OriginalMaxReplicasAnnotation], 10, 32)
-
-	var targetMinReplicas, targetMaxReplicas int32
-	var percentage int32
-
-	if override != nil {
-		// Use override percentage
-		percentage = override.Spec.ReplicasPercentage
-	} else {
-		// Use global percentage
-		percentage = config.GlobalPercentage
-	}
+	originalMinReplicas, originalMaxReplicas := utils.GetOriginalHPALimits(hpa)
 
-	// Calculate new values based on percentage
-	targetMinReplicas = int32(float64(originalMinReplicas) * float64(percentage) / 100.0)
-	targetMaxReplicas = int32(float64(originalMaxReplicas) * float64(percentage) / 100.0)
+	targetMinReplicas, targetMaxReplicas, clamped, explanation := r.desiredHPALimits(ctx, config, hpa, workload, template, override, originalMinReplicas, originalMaxReplicas)
+	percentage, trigger := explanation.Percentage, explanation.Trigger
 
-	// Apply min/max limits from config
-	if targetMinReplicas < config.MinReplicas {
-		targetMinReplicas = config.MinReplicas
+	// Update HPA, limits that change count against the scale budget
+	var previousMinReplicas int32
+	if hpa.Spec.MinReplicas != nil {
+		previousMinReplicas = *hpa.Spec.MinReplicas
 	}
-	if targetMaxReplicas > config.MaxReplicas {
-		targetMaxReplicas = config.MaxReplicas
-	}
-
-	// Ensure min <= max
-	if targetMinReplicas > targetMaxReplicas {
-		targetMinReplicas = targetMaxReplicas
+	labels := scalingLabels(hpa.Namespace, metrics.TargetKindHPA, override)
+	labels.Trigger = trigger
+	if previousMinReplicas != targetMinReplicas || hpa.Spec.MaxReplicas != targetMaxReplicas {
+		if err := r.paceChange(ctx, labels, override, hpa.Name); err != nil {
+			return err
+		}
+		var err error
+		if targetMinReplicas, err = r.authorizeChange(ctx, labels, hpa.Name, previousMinReplicas, targetMinReplicas); err != nil {
+			return err
+		}
+		targetMaxReplicas = max(targetMaxReplicas, targetMinReplicas)
+		if err := r.spendBudget(ctx, labels, hpa.Name, previousMinReplicas, targetMinReplicas); err != nil {
+			return err
+		}
 	}
-
-	// Update HPA
 	hpa.Spec.MinReplicas = &targetMinReplicas
 	hpa.Spec.MaxReplicas = targetMaxReplicas
 	hpa.Annotations[utils.LastHPAUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	hpa.Annotations[utils.ExplainAnnotation] = explanation.JSON()
 
 	log.Info("Updating HPA replicas",
 		"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name),
@@ -433,12 +1125,16 @@ func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autosc
 		"target_max", targetMaxReplicas,
 		"percentage", percentage)
 
-	err := r.Update(ctx, hpa)
+	err := r.Update(ctx, hpa, newChangeReason(labels, &percentage).record(hpa))
 	if err != nil {
 		log.Error(err, "Failed to update HPA",
 			"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name))
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(hpa.Namespace, hpa.Name, labels, originalMinReplicas, previousMinReplicas, targetMinReplicas, clamped, err)
 		return err
 	}
+	metrics.RecordScaling(ctx, labels, previousMinReplicas, targetMinReplicas, percentage, clamped)
+	r.recordEvent(hpa.Namespace, hpa.Name, labels, originalMinReplicas, previousMinReplicas, targetMinReplicas, clamped, nil)
 
 	log.Info("Successfully updated HPA",
 		"hpa", fmt.Sprintf("%s/%s", hpa.Namespace, hpa.Name),
@@ -448,40 +1144,133 @@ func (r *ReplicasOverrideReconciler) processHPA(ctx context.Context, hpa *autosc
 	return nil
 }
 
-// shouldProcessDeployment determines if a deployment should be processed based on the override spec
-func shouldProcessDeployment(deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) bool {
-	// If no override is provided, this is a global config request
-	if override == nil {
-		return true
+// desiredHPALimits returns the min/max replicas of an HPA with the given
+// original limits, clamped to the global limits, whether they were clamped and
+// the explanation of the percentage used
+func (r *ReplicasOverrideReconciler) desiredHPALimits(ctx context.Context, config *config.GlobalConfig, hpa *autoscalingv2.HorizontalPodAutoscaler, workload metav1.Object, template *corev1.PodTemplateSpec, override *dynamicscalingv1.ReplicasOverride, originalMinReplicas, originalMaxReplicas int32) (int32, int32, bool, precedence.Explanation) {
+	explanation := r.resolvePercentage(ctx, workload, template, override)
+	minPercentage, maxPercentage := explanation.Percentage, explanation.Percentage
+	if boost := r.disruptionBoost(config, workload); boost > 0 {
+		// Relax the HPA during node churn, optionally only raising its ceiling
+		maxPercentage = utils.AddBounded(maxPercentage, boost)
+		if !config.NodeDisruption.RelaxHPAMaxOnly {
+			minPercentage = utils.AddBounded(minPercentage, boost)
+		}
+		explanation.Adjust("node disruption boost", metrics.TriggerNodeDisruption, maxPercentage)
+	}
+	if boost := warmUpBoost(override, workload, time.Now()); boost > 0 {
+		minPercentage = utils.AddBounded(minPercentage, boost)
+		maxPercentage = utils.AddBounded(maxPercentage, boost)
+		explanation.Adjust("warm-up after rollout", metrics.TriggerWarmUp, maxPercentage)
 	}
 
-	// If using DeploymentRef, check if this is the target deployment
-	if override.Spec.DeploymentRef != nil {
-		if override.Spec.DeploymentRef.Name == deployment.Name {
-			if override.Spec.DeploymentRef.Namespace == "" || override.Spec.DeploymentRef.Namespace == deployment.Namespace {
-				return true
-			}
+	// HPAs driven by External or Pods metrics keep their original min when
+	// configured, and the hpaPolicy of the override may keep either limit
+	adjustMin, adjustMax := hpaPolicy(override)
+	preserveMin := !adjustMin || preserveHPAMin(config, hpa, override)
+	if preserveMin {
+		minPercentage = 100
+	}
+	if !adjustMax {
+		maxPercentage = 100
+	}
+
+	// Calculate new values based on percentage
+	targetMinReplicas := utils.PercentOf(originalMinReplicas, minPercentage)
+	targetMaxReplicas := utils.PercentOf(originalMaxReplicas, maxPercentage)
+	if scalesHPADesired(override) && !preserveMin {
+		// Keep the min at the percentage of the current demand, rounding up so small HPAs still get headroom
+		demand := hpaDemand(hpa, originalMinReplicas, originalMaxReplicas)
+		targetMinReplicas = utils.PercentOfCeil(demand, minPercentage)
+	}
+	if headroom := headroomReplicas(override); headroom > 0 && !preserveMin {
+		// Keep spare replicas above the current demand, raising the max to make room for them
+		demand := hpaDemand(hpa, originalMinReplicas, originalMaxReplicas)
+		targetMinReplicas = max(targetMinReplicas, utils.AddBounded(demand, headroom))
+		if adjustMax {
+			targetMaxReplicas = max(targetMaxReplicas, targetMinReplicas)
 		}
-		return false
+		explanation.Headroom = headroom
 	}
 
-	// If using Selector, check if the deployment matches the labels
-	if override.Spec.Selector != nil && len(override.Spec.Selector.MatchLabels) > 0 {
-		for key, value := range override.Spec.Selector.MatchLabels {
-			if deployment.Labels[key] != value {
-				return false
-			}
+	// Apply min/max limits from config
+	clamped := false
+	if targetMinReplicas < config.MinReplicas {
+		targetMinReplicas = config.MinReplicas
+		clamped = true
+	}
+	if targetMaxReplicas > config.MaxReplicas {
+		targetMaxReplicas = config.MaxReplicas
+		clamped = true
+	}
+
+	// Ensure min <= max, raising max instead when only the original min is preserved
+	if targetMinReplicas > targetMaxReplicas {
+		if preserveMin && adjustMax {
+			targetMaxReplicas = targetMinReplicas
+		} else {
+			targetMinReplicas = targetMaxReplicas
 		}
-		return true
 	}
 
+	// Keep the max below the ratio of the deployment the override depends on
+	if ceiling, found, err := r.ratioCeiling(ctx, override); err == nil && found && targetMaxReplicas > max(ceiling, 1) {
+		targetMaxReplicas = max(ceiling, 1)
+		targetMinReplicas = min(targetMinReplicas, targetMaxReplicas)
+		clamped = true
+	}
+
+	explanation.SetReplicas(targetMinReplicas, clamped)
+	return targetMinReplicas, targetMaxReplicas, clamped, explanation
+}
+
+// hpaPolicy returns whether the min and max of the HPAs of override are scaled
+func hpaPolicy(override *dynamicscalingv1.ReplicasOverride) (bool, bool) {
+	if override == nil || override.Spec.HPAPolicy == nil {
+		return true, true
+	}
+	policy := override.Spec.HPAPolicy
+	return policy.AdjustMin == nil || *policy.AdjustMin, policy.AdjustMax == nil || *policy.AdjustMax
+}
+
+// preserveHPAMin returns true if the min of the HPA must not be changed because it
+// uses External or Pods metrics and the override or global config asks to preserve it
+func preserveHPAMin(cfg *config.GlobalConfig, hpa *autoscalingv2.HorizontalPodAutoscaler, override *dynamicscalingv1.ReplicasOverride) bool {
+	preserve := cfg.PreserveHPAMinForExternalMetrics
+	if override != nil && override.Spec.PreserveHPAMinForExternalMetrics != nil {
+		preserve = *override.Spec.PreserveHPAMinForExternalMetrics
+	}
+	if !preserve {
+		return false
+	}
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type == autoscalingv2.ExternalMetricSourceType || metric.Type == autoscalingv2.PodsMetricSourceType {
+			return true
+		}
+	}
 	return false
 }
 
+// shouldProcessDeployment determines if a deployment should be processed based on the override spec
+func shouldProcessDeployment(deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) bool {
+	// If no override is provided, this is a global config request
+	return override == nil || matcher.Selects(override, deploymentTarget(deployment))
+}
+
+// queueOptions returns the options of the controllers, whose work queues
+// report their depth, oldest item age and reconcile latency
+func queueOptions() controller.Options {
+	return controller.Options{NewQueue: metrics.NewQueue[reconcile.Request]}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ReplicasOverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.StartedAt.IsZero() {
+		r.StartedAt = time.Now()
+	}
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&dynamicscalingv1.ReplicasOverride{}).
+		WithOptions(queueOptions()).
 		Watches(
 			client.Object(&appsv1.Deployment{}),
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -489,53 +1278,8 @@ func (r *ReplicasOverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				if !ok {
 					return nil
 				}
-
-				// Check for ignore rules first
-				ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
-				if err := r.List(ctx, ignoreList); err != nil {
-					return nil
-				}
-
-				for _, ignore := range ignoreList.Items {
-					if shouldIgnore, _ := utils.ShouldIgnoreDeployment(deployment, &ignore); shouldIgnore {
-						return nil
-					}
-				}
-
-				// Get all ReplicasOverrides
-				overrideList := &dynamicscalingv1.ReplicasOverrideList{}
-				if err := r.List(ctx, overrideList); err != nil {
-					return nil
-				}
-
-				var requests []reconcile.Request
-				foundMatch := false
-
-				// Check each override for a match
-				for _, override := range overrideList.Items {
-					if shouldProcessDeployment(deployment, &override) {
-						requests = append(requests, reconcile.Request{
-							NamespacedName: types.NamespacedName{
-								Name:      override.Name,
-								Namespace: override.Namespace,
-							},
-						})
-						foundMatch = true
-					}
-				}
-
-				// If no specific override matches and deployment is not ignored, trigger reconciliation
-				// with an empty ReplicasOverride name to handle global config
-				if !foundMatch {
-					requests = append(requests, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      "", // Empty name to indicate global config processing
-							Namespace: deployment.Namespace,
-						},
-					})
-				}
-
-				return requests
+				// Overrides capped by its replicas are recomputed when it scales
+				return append(r.workloadRequests(ctx, deploymentTarget(deployment)), r.ratioOfRequests(ctx, deployment)...)
 			}),
 		).
 		Watches(
@@ -552,55 +1296,14 @@ func (r *ReplicasOverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					Name:      hpa.Spec.ScaleTargetRef.Name,
 					Namespace: hpa.Namespace,
 				}, deployment)
-				if err != nil {
-					return nil
-				}
-
-				// Check for ignore rules first
-				ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
-				if err := r.List(ctx, ignoreList); err != nil {
-					return nil
-				}
-
-				for _, ignore := range ignoreList.Items {
-					if shouldIgnore, _ := utils.ShouldIgnoreDeployment(deployment, &ignore); shouldIgnore {
-						return nil
-					}
-				}
-
-				// Get all ReplicasOverrides
-				overrideList := &dynamicscalingv1.ReplicasOverrideList{}
-				if err := r.List(ctx, overrideList); err != nil {
-					return nil
-				}
-
-				var requests []reconcile.Request
-				foundMatch := false
-
-				// Check each override for a match
-				for _, override := range overrideList.Items {
-					if shouldProcessDeployment(deployment, &override) {
-						requests = append(requests, reconcile.Request{
-							NamespacedName: types.NamespacedName{
-								Name:      override.Name,
-								Namespace: override.Namespace,
-							},
-						})
-						foundMatch = true
+				if err != nil || !scalesDeployment(hpa) {
+					// HPAs of other kinds are scaled through an hpaRef, or with the object they scale
+					if requests := r.hpaRefRequests(ctx, hpa); len(requests) > 0 {
+						return requests
 					}
+					return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: hpa.Namespace}}}
 				}
-
-				// If no override matches, trigger reconciliation with an empty name
-				if !foundMatch {
-					requests = append(requests, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      "", // Empty name to indicate global config processing
-							Namespace: hpa.Namespace,
-						},
-					})
-				}
-
-				return requests
+				return r.workloadRequests(ctx, deploymentTarget(deployment))
 			}),
 		).
 		Watches(
@@ -609,164 +1312,114 @@ func (r *ReplicasOverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				configMap := obj.(*corev1.ConfigMap)
 				if configMap.Name == "replicas-controller-config" && configMap.Namespace == "kubedynamicscaler-system" {
 					// When the ConfigMap changes, we need to reconcile all deployments
-					deployments := &appsv1.DeploymentList{}
-					if err := r.List(ctx, deployments); err != nil {
+					index, err := r.matcherIndex(ctx, false)
+					if err != nil {
 						return nil
 					}
 
 					var requests []reconcile.Request
-					for _, deployment := range deployments.Items {
+					err = r.Pager.EachListItem(ctx, r.Client, &appsv1.DeploymentList{}, func(obj client.Object) error {
 						// Skip deployments that should be ignored
-						ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
-						if err := r.List(ctx, ignoreList); err != nil {
-							continue
-						}
-
-						shouldProcess := true
-						for _, ignore := range ignoreList.Items {
-							if shouldIgnore, _ := utils.ShouldIgnoreDeployment(&deployment, &ignore); shouldIgnore {
-								shouldProcess = false
-								break
-							}
-						}
-
-						if shouldProcess {
+						if !index.Ignored(deploymentTarget(obj.(*appsv1.Deployment))) {
 							requests = append(requests, reconcile.Request{
 								NamespacedName: types.NamespacedName{
 									Name:      "", // Empty name to indicate global config processing
-									Namespace: deployment.Namespace,
+									Namespace: obj.GetNamespace(),
 								},
 							})
 						}
+						return nil
+					})
+					if err != nil {
+						return nil
 					}
 					return requests
 				}
+				if configMap.Name == config.ConfigMapName {
+					// The ConfigMap of a workload namespace overlays the global config there
+					return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: configMap.Namespace}}}
+				}
 				return nil
 			}),
-		).
-		Complete(r)
-}
-
-// updateDeploymentAnnotations updates deployment annotations with retry logic
-func (r *ReplicasOverrideReconciler) updateDeploymentAnnotations(ctx context.Context, deployment *appsv1.Deployment, annotations map[string]string) error {
-	log := log.FromContext(ctx)
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		// Get the latest version of the deployment
-		latestDeployment := &appsv1.Deployment{}
-		if err := r.Get(ctx, types.NamespacedName{
-			Name:      deployment.Name,
-			Namespace: deployment.Namespace,
-		}, latestDeployment); err != nil {
-			return err
-		}
-
-		// Update annotations
-		if latestDeployment.Annotations == nil {
-			latestDeployment.Annotations = make(map[string]string)
-		}
-		for key, value := range annotations {
-			latestDeployment.Annotations[key] = value
-		}
-
-		// Try to update
-		if err := r.Update(ctx, latestDeployment); err != nil {
-			if errors.IsConflict(err) {
-				log.Info("Conflict while updating deployment annotations, retrying...", "attempt", i+1)
-				continue
-			}
-			return err
-		}
-		return nil
+		)
+
+	// Drop the work of namespaces once they start terminating
+	bldr = bldr.Watches(
+		client.Object(&corev1.Namespace{}),
+		handler.EnqueueRequestsFromMapFunc(terminatingNamespaceRequests),
+	)
+
+	// Re-evaluate the namespace when its scaling defaults, namespace override or grants change
+	for _, kind := range []client.Object{&dynamicscalingv1.NamespaceScalingDefault{}, &dynamicscalingv1.NamespaceReplicasOverride{}, &dynamicscalingv1.ScalingGrant{}} {
+		bldr = bldr.Watches(
+			kind,
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "", Namespace: obj.GetNamespace()}}}
+			}),
+		)
 	}
-	return fmt.Errorf("failed to update deployment annotations after %d retries", maxRetries)
-}
 
-// findReplicasOverridesForHPA maps an HPA to a list of ReplicasOverride requests
-func (r *ReplicasOverrideReconciler) findReplicasOverridesForHPA(ctx context.Context, obj client.Object) []reconcile.Request {
-	log := log.FromContext(ctx)
-	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
-	if !ok {
-		log.Error(nil, "Expected an HPA but got something else", "object", obj)
-		return nil
+	// Re-evaluate all workloads when node disruptions start or their cooldown ends
+	if r.Disruption != nil {
+		bldr = bldr.WatchesRawSource(source.Channel(r.Disruption.Changes(),
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ""}}}
+			}),
+		))
 	}
 
-	// Get the deployment that this HPA targets
-	deployment := &appsv1.Deployment{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      hpa.Spec.ScaleTargetRef.Name,
-		Namespace: hpa.Namespace,
-	}, deployment)
-	if err != nil {
-		log.Error(err, "Failed to get deployment for HPA", "deployment", hpa.Spec.ScaleTargetRef.Name)
-		return nil
+	// Re-evaluate overrides when an external trigger changes the percentage it requests
+	if r.Triggers != nil {
+		bldr = bldr.WatchesRawSource(source.Channel(r.Triggers.Changes(),
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ""}}}
+			}),
+		))
 	}
 
-	// Check for ignore rules first
+	return bldr.Complete(r)
+}
+
+// matcherIndex indexes the ignore rules, and the overrides when withOverrides
+// is set, for mapping watched workloads to reconcile requests
+func (r *ReplicasOverrideReconciler) matcherIndex(ctx context.Context, withOverrides bool) (*matcher.Index, error) {
 	ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
 	if err := r.List(ctx, ignoreList); err != nil {
-		log.Error(err, "Failed to list ignore rules")
-		return nil
-	}
-
-	for _, ignore := range ignoreList.Items {
-		if shouldIgnore, _ := utils.ShouldIgnoreDeployment(deployment, &ignore); shouldIgnore {
-			return nil
-		}
+		return nil, err
 	}
-
-	// Get all ReplicasOverrides
 	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
-	if err := r.List(ctx, overrideList); err != nil {
-		log.Error(err, "Failed to list ReplicasOverrides")
-		return nil
-	}
-
-	var requests []reconcile.Request
-	foundMatch := false
-
-	// Check each override for a match
-	for _, override := range overrideList.Items {
-		if override.Spec.DeploymentRef != nil &&
-			override.Spec.DeploymentRef.Name == deployment.Name &&
-			override.Spec.DeploymentRef.Namespace == deployment.Namespace {
-			requests = append(requests, reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      override.Name,
-					Namespace: override.Namespace,
-				},
-			})
-			foundMatch = true
-		} else if override.Spec.Selector != nil && len(override.Spec.Selector.MatchLabels) > 0 {
-			// Check if deployment labels match the selector
-			match := true
-			for key, value := range override.Spec.Selector.MatchLabels {
-				if deployment.Labels[key] != value {
-					match = false
-					break
-				}
-			}
-			if match {
-				requests = append(requests, reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Name:      override.Name,
-						Namespace: override.Namespace,
-					},
-				})
-				foundMatch = true
-			}
+	if withOverrides {
+		if err := r.List(ctx, overrideList); err != nil {
+			return nil, err
 		}
 	}
+	return matcher.NewIndex(overrideList.Items, ignoreList.Items), nil
+}
 
-	// If no override matches, create a request for global rules
-	if !foundMatch {
-		requests = append(requests, reconcile.Request{
-			NamespacedName: types.NamespacedName{
-				Name:      hpa.Name,
-				Namespace: hpa.Namespace,
-			},
-		})
+// deploymentTarget returns the matcher target of a deployment
+func deploymentTarget(deployment *appsv1.Deployment) matcher.Target {
+	return matcher.Target{Kind: "Deployment", Namespace: deployment.Namespace, Name: deployment.Name, Labels: deployment.Labels}
+}
+
+// workloadRequests maps a watched workload to the overrides targeting it, or
+// to the global config of its namespace. Ignored workloads map to nothing.
+func (r *ReplicasOverrideReconciler) workloadRequests(ctx context.Context, target matcher.Target) []reconcile.Request {
+	index, err := r.matcherIndex(ctx, true)
+	if err != nil {
+		return nil
 	}
+	return index.Requests(target)
+}
 
-	return requests
+// updateDeploymentAnnotations sets annotations of a deployment, removing those
+// with an empty value, with a merge patch of metadata.annotations only. The
+// patch carries no resourceVersion, so it never conflicts with other writers.
+// The annotations of deployment are those the state annotation is computed from.
+func (r *ReplicasOverrideReconciler) updateDeploymentAnnotations(ctx context.Context, deployment *appsv1.Deployment, annotations map[string]string) error {
+	target := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:        deployment.Name,
+		Namespace:   deployment.Namespace,
+		Annotations: maps.Clone(deployment.Annotations),
+	}}
+	return r.Patch(ctx, target, utils.AnnotationsPatch(annotations))
 }
@@ -17,7 +17,10 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,8 +30,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
 	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
 )
 
@@ -63,6 +70,7 @@ var _ = Describe("ReplicasOverride Controller", func() {
 				},
 				Data: map[string]string{
 					"config.yaml": `
+enabled: true
 globalPercentage: 200
 minReplicas: 1
 maxReplicas: 10
@@ -275,6 +283,63 @@ maxReplicas: 10
 			Expect(k8sClient.Delete(ctx, hpa)).Should(Succeed())
 		})
 
+		It("Should restore the original replicas and pause the override when annotated for rollback", func() {
+			deploymentLookupKey := types.NamespacedName{Name: "test-deployment", Namespace: "default"}
+			overrideLookupKey := types.NamespacedName{Name: "test-override", Namespace: "default"}
+			scaledDeployment := &appsv1.Deployment{}
+
+			// Wait for the override to be applied
+			Eventually(func() int32 {
+				if err := k8sClient.Get(ctx, deploymentLookupKey, scaledDeployment); err != nil {
+					return 0
+				}
+				return *scaledDeployment.Spec.Replicas
+			}, timeout, interval).Should(Equal(int32(3)))
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, overrideLookupKey, override); err != nil {
+					return 0
+				}
+				return len(override.Status.AffectedDeployments)
+			}, timeout, interval).Should(Equal(1))
+
+			// Request the rollback
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, overrideLookupKey, override); err != nil {
+					return err
+				}
+				if override.Annotations == nil {
+					override.Annotations = map[string]string{}
+				}
+				override.Annotations[utils.RollbackAnnotation] = "true"
+				return k8sClient.Update(ctx, override)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() int32 {
+				if err := k8sClient.Get(ctx, deploymentLookupKey, scaledDeployment); err != nil {
+					return 0
+				}
+				return *scaledDeployment.Spec.Replicas
+			}, timeout, interval).Should(Equal(int32(2)), "Deployment should be restored to its 2 original replicas")
+
+			Eventually(func() map[string]string {
+				if err := k8sClient.Get(ctx, overrideLookupKey, override); err != nil {
+					return nil
+				}
+				return override.Annotations
+			}, timeout, interval).Should(And(
+				HaveKeyWithValue(utils.PausedAnnotation, "true"),
+				Not(HaveKey(utils.RollbackAnnotation)),
+			))
+
+			// The paused override must not scale the deployment again
+			Consistently(func() int32 {
+				if err := k8sClient.Get(ctx, deploymentLookupKey, scaledDeployment); err != nil {
+					return 0
+				}
+				return *scaledDeployment.Spec.Replicas
+			}, 3*time.Second, interval).Should(Equal(int32(2)))
+		})
+
 		It("Should scale deployment to 200% when using global configuration with 200% percentage", func() {
 			// Create a new deployment without any matching override
 			globalDeployment := &appsv1.Deployment{
@@ -456,3 +521,360 @@ maxReplicas: 10
 		})
 	})
 })
+
+func TestIsProtectedAtZero(t *testing.T) {
+	deployment := func(replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "parked", Namespace: "default"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		}
+	}
+	byName := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{
+		DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "parked"},
+	}}
+	bySelector := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{
+		Selector: &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web"}},
+	}}
+	protect := &config.GlobalConfig{ProtectScaledToZero: true}
+	parked := deployment(0)
+	parked.Annotations = map[string]string{utils.OriginalReplicasAnnotation: "3"}
+	backedUp := bySelector.DeepCopy()
+	backedUp.Status.AffectedDeployments = []dynamicscalingv1.AffectedDeployment{{Name: "parked", Namespace: "default", OriginalReplicas: 3}}
+
+	tests := []struct {
+		name       string
+		cfg        *config.GlobalConfig
+		deployment *appsv1.Deployment
+		override   *dynamicscalingv1.ReplicasOverride
+		want       bool
+	}{
+		{name: "policy disabled", cfg: &config.GlobalConfig{}, deployment: deployment(0), want: false},
+		{name: "global config at zero", cfg: protect, deployment: deployment(0), want: true},
+		{name: "selector override at zero", cfg: protect, deployment: deployment(0), override: bySelector, want: true},
+		{name: "explicit deploymentRef wakes the deployment", cfg: protect, deployment: deployment(0), override: byName, want: false},
+		{name: "running deployment", cfg: protect, deployment: deployment(2), want: false},
+		{name: "parked by the controller", cfg: protect, deployment: parked, override: bySelector, want: false},
+		{name: "parked with the original in the status", cfg: protect, deployment: deployment(0), override: backedUp, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isProtectedAtZero(tt.cfg, tt.deployment, tt.override); got != tt.want {
+				t.Errorf("isProtectedAtZero() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtectedAtZeroScalesBack(t *testing.T) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"tier": "web"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "night", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web"}},
+			OverrideType:       "override",
+			ReplicasPercentage: 0,
+			MinReplicas:        int32Ptr(0),
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+		Build()
+	cfg := config.DefaultConfig()
+	cfg.ProtectScaledToZero = true
+	cfg.MinReplicas = 0
+	r := newTestReconciler(c, cfg)
+
+	reconcileTo := func(percentage, want int32) {
+		t.Helper()
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+			t.Fatal(err)
+		}
+		override.Spec.ReplicasPercentage = percentage
+		if err := c.Update(ctx, override); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			t.Fatal(err)
+		}
+		if *deployment.Spec.Replicas != want {
+			t.Errorf("replicas at %d%% = %d, want %d", percentage, *deployment.Spec.Replicas, want)
+		}
+	}
+
+	// Parked at zero by the override, the deployment is not protected from scaling back up
+	reconcileTo(0, 0)
+	reconcileTo(100, 4)
+
+	// Without its annotation, the backup in the override status still records the original replicas
+	reconcileTo(0, 0)
+	delete(deployment.Annotations, utils.OriginalReplicasAnnotation)
+	if err := c.Update(ctx, deployment); err != nil {
+		t.Fatal(err)
+	}
+	reconcileTo(100, 4)
+}
+
+func TestPreserveHPAMin(t *testing.T) {
+	hpa := func(metricType autoscalingv2.MetricSourceType) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			Metrics: []autoscalingv2.MetricSpec{{Type: metricType}},
+		}}
+	}
+	enabled, disabled := true, false
+	override := func(preserve *bool) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{PreserveHPAMinForExternalMetrics: preserve}}
+	}
+	preserve := &config.GlobalConfig{PreserveHPAMinForExternalMetrics: true}
+
+	tests := []struct {
+		name     string
+		cfg      *config.GlobalConfig
+		hpa      *autoscalingv2.HorizontalPodAutoscaler
+		override *dynamicscalingv1.ReplicasOverride
+		want     bool
+	}{
+		{name: "disabled by default", cfg: &config.GlobalConfig{}, hpa: hpa(autoscalingv2.ExternalMetricSourceType), want: false},
+		{name: "external metric", cfg: preserve, hpa: hpa(autoscalingv2.ExternalMetricSourceType), want: true},
+		{name: "pods metric", cfg: preserve, hpa: hpa(autoscalingv2.PodsMetricSourceType), want: true},
+		{name: "resource metric", cfg: preserve, hpa: hpa(autoscalingv2.ResourceMetricSourceType), want: false},
+		{name: "override disables", cfg: preserve, hpa: hpa(autoscalingv2.ExternalMetricSourceType), override: override(&disabled), want: false},
+		{name: "override enables", cfg: &config.GlobalConfig{}, hpa: hpa(autoscalingv2.PodsMetricSourceType), override: override(&enabled), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := preserveHPAMin(tt.cfg, tt.hpa, tt.override); got != tt.want {
+				t.Errorf("preserveHPAMin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateDeploymentAnnotations(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{"team": "shop", utils.BrokenHPAAnnotation: "web"}},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	c := fakeclient.NewBuilder(deployment).Build()
+	r := &ReplicasOverrideReconciler{Client: c}
+
+	// Another manager changed the deployment since it was read
+	stale := deployment.DeepCopy()
+	latest := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), latest); err != nil {
+		t.Fatal(err)
+	}
+	scaled := int32(5)
+	latest.Spec.Replicas = &scaled
+	if err := c.Update(ctx, latest); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.updateDeploymentAnnotations(ctx, stale, map[string]string{utils.ManagementModeAnnotation: "hpa", utils.BrokenHPAAnnotation: ""}); err != nil {
+		t.Fatalf("updateDeploymentAnnotations() failed: %v", err)
+	}
+	got := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"team": "shop", utils.ManagementModeAnnotation: "hpa"}
+	if !reflect.DeepEqual(got.Annotations, want) {
+		t.Errorf("annotations = %v, want %v", got.Annotations, want)
+	}
+	if *got.Spec.Replicas != scaled {
+		t.Errorf("replicas = %d, want the %d set by the other manager", *got.Spec.Replicas, scaled)
+	}
+}
+
+func TestUpdateDeploymentAnnotationsV1(t *testing.T) {
+	ctx := context.Background()
+	stored := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{
+		"team":                "shop",
+		utils.StateAnnotation: `{"version":1,"originalReplicas":"4","managed":"true"}`,
+	}}}
+	inner := fakeclient.NewBuilder(stored).Build()
+	c, err := utils.NewStateClient(inner, utils.AnnotationFormatV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ReplicasOverrideReconciler{Client: c}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(stored), deployment); err != nil {
+		t.Fatal(err)
+	}
+	err = r.updateDeploymentAnnotations(ctx, deployment, map[string]string{
+		utils.ManagementModeAnnotation:   "hpa",
+		utils.OriginalReplicasAnnotation: "5",
+		utils.BrokenHPAAnnotation:        "",
+	})
+	if err != nil {
+		t.Fatalf("updateDeploymentAnnotations() failed: %v", err)
+	}
+
+	// The state annotations set are written into the state, not next to it
+	got := &appsv1.Deployment{}
+	if err := inner.Get(ctx, client.ObjectKeyFromObject(stored), got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"team":                "shop",
+		utils.StateAnnotation: `{"version":1,"originalReplicas":"5","managementMode":"hpa","managed":"true"}`,
+	}
+	if !reflect.DeepEqual(got.Annotations, want) {
+		t.Errorf("stored annotations = %v, want %v", got.Annotations, want)
+	}
+}
+
+func TestHPAPolicy(t *testing.T) {
+	keep, adjust := false, true
+	cfg := &config.GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 100}
+	r := newTestReconciler(fakeclient.NewBuilder().Build(), cfg)
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+
+	tests := []struct {
+		name       string
+		percentage int32
+		policy     *dynamicscalingv1.HPAPolicy
+		wantMin    int32
+		wantMax    int32
+	}{
+		{"both scaled by default", 200, nil, 8, 20},
+		{"min kept as an SLA floor", 200, &dynamicscalingv1.HPAPolicy{AdjustMin: &keep}, 4, 20},
+		{"min kept on scale-down", 50, &dynamicscalingv1.HPAPolicy{AdjustMin: &keep, AdjustMax: &adjust}, 4, 5},
+		{"max kept", 200, &dynamicscalingv1.HPAPolicy{AdjustMax: &keep}, 8, 10},
+		{"min capped by the kept max", 300, &dynamicscalingv1.HPAPolicy{AdjustMax: &keep}, 10, 10},
+		{"both kept", 200, &dynamicscalingv1.HPAPolicy{AdjustMin: &keep, AdjustMax: &keep}, 4, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			override := &dynamicscalingv1.ReplicasOverride{
+				Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: tt.percentage, HPAPolicy: tt.policy},
+			}
+			minReplicas, maxReplicas, _, _ := r.desiredHPALimits(context.Background(), cfg, hpa, workload, &workload.Spec.Template, override, 4, 10)
+			if minReplicas != tt.wantMin || maxReplicas != tt.wantMax {
+				t.Errorf("desiredHPALimits() = (%d, %d), want (%d, %d)", minReplicas, maxReplicas, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestStatusWrittenOnlyOnChange(t *testing.T) {
+	ctx := context.Background()
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+				OverrideType:       "override",
+				ReplicasPercentage: 200,
+			},
+		},
+		&dynamicscalingv1.GlobalReplicasIgnore{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch"},
+			Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+				IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "Deployment", Name: "batch", Namespace: "shop"}},
+			},
+		},
+	).
+		WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}, &dynamicscalingv1.GlobalReplicasIgnore{}).
+		Build()
+	r := newTestReconciler(c, nil)
+	ignoreReconciler := &GlobalReplicasIgnoreReconciler{Client: c, Scheme: c.Scheme()}
+
+	reconcile := func() (string, string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if _, err := ignoreReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "batch"}}); err != nil {
+			t.Fatalf("Reconcile() of the ignore rule failed: %v", err)
+		}
+		var override dynamicscalingv1.ReplicasOverride
+		if err := c.Get(ctx, types.NamespacedName{Name: "api-sale", Namespace: "shop"}, &override); err != nil {
+			t.Fatal(err)
+		}
+		var ignore dynamicscalingv1.GlobalReplicasIgnore
+		if err := c.Get(ctx, types.NamespacedName{Name: "batch"}, &ignore); err != nil {
+			t.Fatal(err)
+		}
+		if len(override.Status.AffectedDeployments) != 1 || len(ignore.Status.IgnoredDeployments) != 1 {
+			t.Fatalf("status not recorded: %+v, %+v", override.Status, ignore.Status)
+		}
+		return override.ResourceVersion, ignore.ResourceVersion
+	}
+
+	overrideVersion, ignoreVersion := reconcile()
+	// A pass changing nothing writes no status
+	if gotOverride, gotIgnore := reconcile(); gotOverride != overrideVersion || gotIgnore != ignoreVersion {
+		t.Errorf("unchanged status rewritten: override %s -> %s, ignore %s -> %s", overrideVersion, gotOverride, ignoreVersion, gotIgnore)
+	}
+}
+
+func TestCostEstimatedOncePerPass(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.DefaultConfig()
+	cfg.Cost = config.CostConfig{PodHourlyCost: 0.10, Currency: "USD"}
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop", Labels: map[string]string{"tier": "web"},
+				Annotations: map[string]string{utils.PodHourlyCostAnnotation: "0.5"}},
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"tier": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-sale", Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web"}},
+				OverrideType:       "override",
+				ReplicasPercentage: 200,
+			},
+		},
+	).WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}).Build()
+	r := newTestReconciler(c, cfg)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	var override dynamicscalingv1.ReplicasOverride
+	if err := c.Get(ctx, types.NamespacedName{Name: "web-sale", Namespace: "shop"}, &override); err != nil {
+		t.Fatal(err)
+	}
+	// 2 more pods at 0.5 for api and 2 more at 0.10 for web
+	if override.Status.EstimatedCost == nil || override.Status.EstimatedCost.HourlyDelta != "1.20" {
+		t.Fatalf("EstimatedCost = %+v, want an hourly delta of 1.20", override.Status.EstimatedCost)
+	}
+
+	// The annotations of listed deployments are used without getting them again
+	listed := map[types.NamespacedName]map[string]string{
+		{Namespace: "shop", Name: "api"}: {utils.PodHourlyCostAnnotation: "1"},
+		{Namespace: "shop", Name: "web"}: nil,
+	}
+	r.updateCostEstimate(ctx, &override, listed)
+	if got := override.Status.EstimatedCost.HourlyDelta; got != "2.20" {
+		t.Errorf("HourlyDelta from listed annotations = %s, want 2.20", got)
+	}
+}
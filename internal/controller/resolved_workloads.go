@@ -0,0 +1,287 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/resolver"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// processResolvedWorkloads scales the sub-resources of the custom resources of
+// a namespace mapped by the registered and configured workload resolvers,
+// matched by an override selector or by the global config. Custom resources
+// of a paused or rolled back override get their original replicas back. Kinds
+// that are not installed, or the controller may not list, are skipped. It
+// returns nextCheck, moved earlier if a blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processResolvedWorkloads(ctx context.Context, cfg *config.GlobalConfig, namespace string, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	registry, err := r.Resolvers.WithConfig(cfg.WorkloadResolvers)
+	if err != nil {
+		log.Error(err, "Ignoring invalid workload resolvers")
+		return nextCheck
+	}
+	resolvers := registry.Resolvers()
+	if len(resolvers) == 0 {
+		return nextCheck
+	}
+
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return nextCheck
+	}
+
+	for _, res := range resolvers {
+		gvk := res.GroupVersionKind()
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			if !apierrors.IsForbidden(err) && !meta.IsNoMatchError(err) {
+				log.Error(err, "Failed to list custom resources in namespace", "kind", gvk.Kind, "namespace", namespace)
+			}
+			continue
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if isWorkloadIgnored(gvk.Kind, obj, ignoreList.Items) {
+				continue
+			}
+
+			override := selectorOverride(overrideList.Items, obj)
+			if override != nil && (isPaused(override) || rollbackRequested(override)) {
+				if err := r.restoreResolvedWorkload(ctx, res, override, obj); err != nil {
+					log.Error(err, "Failed to restore custom resource",
+						"kind", gvk.Kind,
+						"workload", fmt.Sprintf("%s/%s", namespace, obj.GetName()))
+				}
+				continue
+			}
+			if !targetsKind(override, gvk.Kind) || r.globalScalingHeld(ctx, obj, override) {
+				continue
+			}
+			if skip, until := r.overrideFrozen(ctx, override); skip {
+				if !until.IsZero() && until.Before(nextCheck) {
+					nextCheck = until
+				}
+				continue
+			}
+
+			if err := r.processResolvedWorkload(ctx, res, obj, override); err != nil && !changeDeferred(err) {
+				log.Error(err, "Failed to process custom resource",
+					"kind", gvk.Kind,
+					"workload", fmt.Sprintf("%s/%s", namespace, obj.GetName()),
+					"hasOverride", override != nil,
+					"reason", recordFailure(namespace, gvk.Kind, override, err))
+			}
+		}
+	}
+	return nextCheck
+}
+
+// processResolvedWorkload scales every sub-resource of a custom resource by
+// the percentage of its override, or of the global config, in a single patch
+func (r *ReplicasOverrideReconciler) processResolvedWorkload(ctx context.Context, res resolver.Resolver, obj *unstructured.Unstructured, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+
+	cfg := r.configFor(ctx, obj.GetNamespace())
+	if cfg == nil {
+		return fmt.Errorf("global config not found")
+	}
+	subs, err := res.Resolve(obj)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+	kind := res.GroupVersionKind().Kind
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	originals, err := subResourceOriginals(obj)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if _, recorded := originals[sub.Name]; !recorded {
+			originals[sub.Name] = sub.Replicas
+		}
+	}
+
+	labels := scalingLabels(obj.GetNamespace(), kind, override)
+	type change struct {
+		sub     resolver.SubResource
+		desired int32
+	}
+	var changes []change
+	var explanation precedence.Explanation
+	var denied error
+	for _, sub := range subs {
+		var desired int32
+		desired, explanation = r.desiredReplicas(ctx, cfg, obj, &corev1.PodTemplateSpec{}, override, originals[sub.Name], sub.Replicas)
+		labels.Trigger = explanation.Trigger
+		desired = max(desired, sub.MinReplicas)
+		if desired == sub.Replicas {
+			continue
+		}
+		// A denied sub-resource keeps its replicas, the others are still submitted on their own
+		desired, err := r.authorizeChange(ctx, labels, obj.GetName()+"/"+sub.Name, sub.Replicas, desired)
+		if err != nil {
+			denied = err
+			continue
+		}
+		if err := r.spendBudget(ctx, labels, obj.GetName()+"/"+sub.Name, sub.Replicas, desired); err != nil {
+			if len(changes) == 0 {
+				return err
+			}
+			// Apply the changes the budget allowed, the others are retried later
+			break
+		}
+		if err := res.SetReplicas(obj, sub.Name, desired); err != nil {
+			return err
+		}
+		changes = append(changes, change{sub: sub, desired: desired})
+	}
+
+	percentage, clamped := explanation.Percentage, explanation.Clamped
+	if len(changes) == 0 && denied != nil {
+		return denied
+	}
+	if len(changes) == 0 {
+		log.V(1).Info("Custom resource already at desired replicas, skipping update",
+			"kind", kind,
+			"workload", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()))
+		return r.updateExplanation(ctx, obj, explanation)
+	}
+
+	// Record the original replicas and management annotations on the custom resource itself
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	data, _ := json.Marshal(originals)
+	annotations[utils.OriginalSubResourceReplicasAnnotation] = string(data)
+	if override != nil {
+		annotations[utils.OverrideControllerAnnotation] = "true"
+		annotations[utils.ManagedAnnotation] = "true"
+	} else {
+		annotations[utils.GlobalConfigManagedAnnotation] = "true"
+	}
+	annotations[utils.ManagementModeAnnotation] = "direct"
+	annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	annotations[utils.ExplainAnnotation] = explanation.JSON()
+	obj.SetAnnotations(annotations)
+	owner := newChangeReason(labels, &percentage).record(obj)
+
+	for _, c := range changes {
+		log.Info("Updating custom resource replicas",
+			"kind", kind,
+			"workload", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()),
+			"subResource", c.sub.Name,
+			"original", originals[c.sub.Name],
+			"previous", c.sub.Replicas,
+			"target", c.desired,
+			"percentage", percentage)
+	}
+	err = r.Patch(ctx, obj, patch, owner)
+	for _, c := range changes {
+		name := obj.GetName() + "/" + c.sub.Name
+		if err != nil {
+			metrics.RecordScalingError(ctx, labels)
+		} else {
+			metrics.RecordScaling(ctx, labels, c.sub.Replicas, c.desired, percentage, clamped)
+		}
+		r.recordEvent(obj.GetNamespace(), name, labels, originals[c.sub.Name], c.sub.Replicas, c.desired, clamped, err)
+	}
+	return err
+}
+
+// restoreResolvedWorkload sets the sub-resources of a custom resource back to
+// their original replicas and forgets them, so a later scaling starts from
+// the replicas the owner set
+func (r *ReplicasOverrideReconciler) restoreResolvedWorkload(ctx context.Context, res resolver.Resolver, override *dynamicscalingv1.ReplicasOverride, obj *unstructured.Unstructured) error {
+	if _, exists := obj.GetAnnotations()[utils.OriginalSubResourceReplicasAnnotation]; !exists {
+		return nil
+	}
+
+	labels := scalingLabels(obj.GetNamespace(), res.GroupVersionKind().Kind, override)
+	labels.Trigger = metrics.TriggerRollback
+	var subs []resolver.SubResource
+	var originals map[string]int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &unstructured.Unstructured{}
+		latest.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+			return err
+		}
+		var err error
+		if originals, err = subResourceOriginals(latest); err != nil {
+			return err
+		}
+		if subs, err = res.Resolve(latest); err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if original, recorded := originals[sub.Name]; recorded {
+				if err := res.SetReplicas(latest, sub.Name, original); err != nil {
+					return err
+				}
+			}
+		}
+		annotations := latest.GetAnnotations()
+		delete(annotations, utils.OriginalSubResourceReplicasAnnotation)
+		annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		latest.SetAnnotations(annotations)
+		return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+	})
+
+	for _, sub := range subs {
+		if original, recorded := originals[sub.Name]; recorded {
+			r.recordRollback(ctx, obj.GetNamespace(), obj.GetName()+"/"+sub.Name, labels, original, sub.Replicas, err)
+		}
+	}
+	return err
+}
+
+// subResourceOriginals returns the original replicas recorded on a custom
+// resource, by sub-resource
+func subResourceOriginals(obj *unstructured.Unstructured) (map[string]int32, error) {
+	originals := make(map[string]int32)
+	if recorded := obj.GetAnnotations()[utils.OriginalSubResourceReplicasAnnotation]; recorded != "" {
+		if err := json.Unmarshal([]byte(recorded), &originals); err != nil {
+			return nil, failure.Wrap(failure.InvalidConfig, fmt.Errorf("invalid %s annotation of %s %s/%s: %w",
+				utils.OriginalSubResourceReplicasAnnotation, obj.GetKind(), obj.GetNamespace(), obj.GetName(), err))
+		}
+	}
+	return originals, nil
+}
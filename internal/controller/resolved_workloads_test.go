@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestResolvedWorkloads(t *testing.T) {
+	ctx := context.Background()
+
+	kafka := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "events", "namespace": "streaming", "labels": map[string]interface{}{"app": "events"}},
+		"spec": map[string]interface{}{
+			"kafka":     map[string]interface{}{"replicas": int64(6)},
+			"zookeeper": map[string]interface{}{"replicas": int64(3)},
+		},
+	}}
+	kafka.SetGroupVersionKind(schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"})
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "events-off-hours", Namespace: "streaming"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"app": "events"}},
+			OverrideType:       "override",
+			ReplicasPercentage: 50,
+		},
+	}
+	c := fakeclient.NewBuilder(kafka, override).Build()
+	cfg := config.DefaultConfig()
+	cfg.WorkloadResolvers = []config.WorkloadResolverConfig{{
+		APIVersion: "kafka.strimzi.io/v1beta2",
+		Kind:       "Kafka",
+		SubResources: []config.ResolvedSubResource{
+			{Name: "brokers", ReplicasPath: "spec.kafka.replicas"},
+			{Name: "zookeeper", ReplicasPath: "spec.zookeeper.replicas", MinReplicas: 3},
+		},
+	}}
+	r := newTestReconciler(c, cfg)
+	replicas := func() (int64, int64) {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(kafka.GroupVersionKind())
+		if err := c.Get(ctx, types.NamespacedName{Name: "events", Namespace: "streaming"}, got); err != nil {
+			t.Fatal(err)
+		}
+		brokers, _, _ := unstructured.NestedInt64(got.Object, "spec", "kafka", "replicas")
+		zookeeper, _, _ := unstructured.NestedInt64(got.Object, "spec", "zookeeper", "replicas")
+		return brokers, zookeeper
+	}
+
+	// Each sub-resource is scaled on its own, the quorum is kept
+	r.processResolvedWorkloads(ctx, cfg, "streaming", &dynamicscalingv1.GlobalReplicasIgnoreList{}, time.Now().Add(time.Hour))
+	if brokers, zookeeper := replicas(); brokers != 3 || zookeeper != 3 {
+		t.Errorf("replicas = %d brokers, %d zookeeper, want 3 and 3", brokers, zookeeper)
+	}
+
+	// A paused override gives the custom resource its original replicas back
+	if err := c.Get(ctx, client.ObjectKeyFromObject(override), override); err != nil {
+		t.Fatal(err)
+	}
+	override.Annotations = map[string]string{utils.PausedAnnotation: "true"}
+	if err := c.Update(ctx, override); err != nil {
+		t.Fatal(err)
+	}
+	r.processResolvedWorkloads(ctx, cfg, "streaming", &dynamicscalingv1.GlobalReplicasIgnoreList{}, time.Now().Add(time.Hour))
+	if brokers, zookeeper := replicas(); brokers != 6 || zookeeper != 3 {
+		t.Errorf("replicas after pausing the override = %d brokers, %d zookeeper, want 6 and 3", brokers, zookeeper)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// RolledBackConditionType is the ReplicasOverride condition reporting a rollback
+const RolledBackConditionType = "RolledBack"
+
+// isPaused returns true if the override must not change its targets
+func isPaused(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override.Annotations[utils.PausedAnnotation] == "true"
+}
+
+// rollbackRequested returns true if the override is annotated for rollback
+func rollbackRequested(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override.Annotations[utils.RollbackAnnotation] == "true"
+}
+
+// rollbackOverride restores every target of the override to its original
+// replicas (or HPA limits), then pauses the override. The rollback annotation is
+// kept when a target could not be restored so the next reconcile retries it.
+func (r *ReplicasOverrideReconciler) rollbackOverride(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) error {
+	log := log.FromContext(ctx)
+	log.Info("Rolling back override", "override", override.Name, "namespace", override.Namespace)
+
+	var failed []string
+	for i := range override.Status.AffectedDeployments {
+		affected := &override.Status.AffectedDeployments[i]
+		restored, err := r.restoreTarget(ctx, override, affected.Namespace, affected.Name)
+		if err != nil {
+			log.Error(err, "Failed to roll back deployment",
+				"deployment", fmt.Sprintf("%s/%s", affected.Namespace, affected.Name))
+			failed = append(failed, affected.Namespace+"/"+affected.Name)
+			continue
+		}
+		affected.CurrentReplicas = restored
+		affected.CurrentPercentage = 100
+	}
+	restoredTargets := len(override.Status.AffectedDeployments)
+
+	// Give the targets back their original share of traffic
+	if err := r.restoreTrafficWeights(ctx, override); err != nil {
+		log.Error(err, "Failed to restore traffic weights", "httpRoute", override.Spec.TrafficShift.HTTPRoute)
+		failed = append(failed, override.Namespace+"/"+override.Spec.TrafficShift.HTTPRoute)
+	}
+
+	// An HPA referenced directly is not listed among the affected deployments
+	if restored, err := r.restoreReferencedHPA(ctx, override); err != nil {
+		log.Error(err, "Failed to roll back referenced HPA", "hpa", override.Spec.HPARef.Name)
+		failed = append(failed, hpaRefKey(override).String())
+	} else if restored {
+		restoredTargets++
+	}
+
+	condition := metav1.Condition{
+		Type:               RolledBackConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RollbackCompleted",
+		Message:            fmt.Sprintf("Restored %d targets to their original values, override paused", restoredTargets),
+		ObservedGeneration: override.Generation,
+	}
+	if len(failed) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RollbackFailed"
+		condition.Message = fmt.Sprintf("Failed to restore %v", failed)
+	}
+	meta.SetStatusCondition(&override.Status.Conditions, condition)
+	r.updateCostEstimate(ctx, override, nil)
+	if err := r.Status().Update(ctx, override); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to roll back %d targets", len(failed))
+	}
+
+	// Pause the override and consume the rollback request
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &dynamicscalingv1.ReplicasOverride{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(override), latest); err != nil {
+			return err
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		delete(latest.Annotations, utils.RollbackAnnotation)
+		latest.Annotations[utils.PausedAnnotation] = "true"
+		return r.Update(ctx, latest)
+	})
+}
+
+// restoreTarget restores a deployment, or the HPA or external scaler managing
+// it, to its original values and returns the restored replicas (minimum of the
+// HPA or scaler)
+func (r *ReplicasOverrideReconciler) restoreTarget(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, namespace, name string) (int32, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	// The replicas of deployments driven by an external scaler were never changed
+	if deployment.Annotations[utils.ManagementModeAnnotation] == config.ScalerModeLimitsOnly {
+		return r.restoreExternalScaler(ctx, override, deployment)
+	}
+
+	if deployment.Annotations[utils.ManagementModeAnnotation] == "hpa" {
+		hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+			return 0, err
+		}
+		for i := range hpaList.Items {
+			hpa := &hpaList.Items[i]
+			if hpa.Spec.ScaleTargetRef.Kind == "Deployment" && hpa.Spec.ScaleTargetRef.Name == name {
+				return r.restoreHPA(ctx, override, hpa)
+			}
+		}
+	}
+
+	// Originals of a previous deployment of the same name are not restored onto this one
+	dropStaleOriginals(deployment)
+	original := utils.GetOriginalReplicas(deployment)
+	if _, exists := deployment.Annotations[utils.OriginalReplicasAnnotation]; !exists {
+		if backup := deploymentBackup(override, namespace, name); backup != nil && recordedFor(backup, deployment) {
+			original = backup.OriginalReplicas
+		}
+	}
+	labels := scalingLabels(namespace, metrics.TargetKindDeployment, override)
+	labels.Trigger = metrics.TriggerRollback
+	var previous int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &appsv1.Deployment{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(deployment), latest); err != nil {
+			return err
+		}
+		if latest.Spec.Replicas != nil {
+			previous = *latest.Spec.Replicas
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		latest.Spec.Replicas = &original
+		latest.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+	})
+
+	r.recordRollback(ctx, namespace, name, labels, original, previous, err)
+	return original, err
+}
+
+// restoreHPA restores the original min/max replicas of an HPA
+func (r *ReplicasOverrideReconciler) restoreHPA(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, hpa *autoscalingv2.HorizontalPodAutoscaler) (int32, error) {
+	originalMin, originalMax := originalHPALimits(override, hpa)
+	labels := scalingLabels(hpa.Namespace, metrics.TargetKindHPA, override)
+	labels.Trigger = metrics.TriggerRollback
+	var previous int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &autoscalingv2.HorizontalPodAutoscaler{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(hpa), latest); err != nil {
+			return err
+		}
+		if latest.Spec.MinReplicas != nil {
+			previous = *latest.Spec.MinReplicas
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		latest.Spec.MinReplicas = &originalMin
+		latest.Spec.MaxReplicas = originalMax
+		latest.Annotations[utils.LastHPAUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		return r.Update(ctx, latest, newChangeReason(labels, nil).record(latest))
+	})
+
+	r.recordRollback(ctx, hpa.Namespace, hpa.Name, labels, originalMin, previous, err)
+	return originalMin, err
+}
+
+// restoreExternalScaler restores the limits processExternalScaler changed on
+// the KEDA ScaledObject or Argo Rollouts HPA of a deployment in limits-only
+// mode and returns the restored minimum. The replicas are left to the scaler.
+func (r *ReplicasOverrideReconciler) restoreExternalScaler(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment) (int32, error) {
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(deployment.Namespace)); err != nil {
+		return 0, err
+	}
+	cfg := r.configFor(ctx, deployment.Namespace)
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	scaler := detectExternalScaler(cfg, deployment, hpaList.Items, r.rolloutForDeployment(ctx, deployment))
+	switch {
+	case scaler == nil:
+	case scaler.name == scalerKEDA:
+		return r.restoreScaledObject(ctx, override, deployment.Namespace, scaler.scaledObject)
+	case scaler.name == scalerArgoRollouts:
+		for i := range hpaList.Items {
+			hpa := &hpaList.Items[i]
+			if hpa.Spec.ScaleTargetRef.Kind == "Rollout" && hpa.Spec.ScaleTargetRef.Name == scaler.rollout {
+				return r.restoreHPA(ctx, override, hpa)
+			}
+		}
+	}
+
+	// Custom scalers have no known limits, nothing was changed
+	if deployment.Spec.Replicas == nil {
+		return 1, nil
+	}
+	return *deployment.Spec.Replicas, nil
+}
+
+// restoreScaledObject restores the original min/max replica counts of a KEDA ScaledObject
+func (r *ReplicasOverrideReconciler) restoreScaledObject(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, namespace, name string) (int32, error) {
+	labels := scalingLabels(namespace, targetKindScaledObject, override)
+	labels.Trigger = metrics.TriggerRollback
+	var originalMin, previous int32
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scaledObject := &unstructured.Unstructured{}
+		scaledObject.SetGroupVersionKind(scaledObjectGVK)
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, scaledObject); err != nil {
+			return err
+		}
+
+		r.repairOriginals(ctx, targetKindScaledObject, scaledObject, override)
+		annotations := scaledObject.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		previous = nestedInt32(scaledObject, "minReplicaCount", kedaDefaultMinReplicas)
+		originalMin = previous
+		if parsed, ok := utils.ParseReplicas(annotations[utils.OriginalMinReplicasAnnotation]); ok {
+			originalMin = parsed
+		}
+		originalMax := nestedInt32(scaledObject, "maxReplicaCount", kedaDefaultMaxReplicas)
+		if parsed, ok := utils.ParseReplicas(annotations[utils.OriginalMaxReplicasAnnotation]); ok {
+			originalMax = parsed
+		}
+
+		annotations[utils.LastHPAUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		scaledObject.SetAnnotations(annotations)
+		if err := unstructured.SetNestedField(scaledObject.Object, int64(originalMin), "spec", "minReplicaCount"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(scaledObject.Object, int64(originalMax), "spec", "maxReplicaCount"); err != nil {
+			return err
+		}
+		return r.Update(ctx, scaledObject, newChangeReason(labels, nil).record(scaledObject))
+	})
+	if errors.IsNotFound(err) {
+		return 0, nil
+	}
+
+	r.recordRollback(ctx, namespace, name, labels, originalMin, previous, err)
+	return originalMin, err
+}
+
+// recordRollback reports a restore in metrics and the scaling report
+func (r *ReplicasOverrideReconciler) recordRollback(ctx context.Context, namespace, name string, labels metrics.ScalingLabels, original, previous int32, err error) {
+	if err != nil {
+		metrics.RecordScalingError(ctx, labels)
+	} else {
+		metrics.RecordScaling(ctx, labels, previous, original, 100, false)
+	}
+	r.recordEvent(namespace, name, labels, original, previous, original, false, err)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestRollbackLimitsOnly(t *testing.T) {
+	ctx := context.Background()
+	limitsOnly := func(scaler string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "shop", Annotations: map[string]string{
+				utils.ManagementModeAnnotation: config.ScalerModeLimitsOnly,
+				utils.ExternalScalerAnnotation: scaler,
+			}},
+			// Scaled by the external scaler, within the limits set by the override
+			Spec: appsv1.DeploymentSpec{Replicas: int32Ptr(7)},
+		}
+	}
+	original := map[string]string{utils.OriginalMinReplicasAnnotation: "2", utils.OriginalMaxReplicasAnnotation: "20"}
+	hpa := func(name, kind, target string, labels map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: labels, Annotations: original},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{Kind: kind, Name: target},
+				MinReplicas:    int32Ptr(4),
+				MaxReplicas:    40,
+			},
+		}
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop", Annotations: map[string]string{utils.RollbackAnnotation: "true"}},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "worker"}, ReplicasPercentage: 200},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+			{Name: "worker", Namespace: "shop", OriginalReplicas: 2, CurrentReplicas: 4},
+		}},
+	}
+	scheme := fakeclient.Scheme()
+	scheme.AddKnownTypeWithName(scaledObjectGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(rolloutListGVK.GroupVersion().WithKind("Rollout"), &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(rolloutListGVK, &unstructured.UnstructuredList{})
+
+	t.Run("keda", func(t *testing.T) {
+		deployment := limitsOnly(scalerKEDA)
+		scaledObject := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"minReplicaCount": int64(4), "maxReplicaCount": int64(40)},
+		}}
+		scaledObject.SetGroupVersionKind(scaledObjectGVK)
+		scaledObject.SetName("worker-so")
+		scaledObject.SetNamespace("shop")
+		scaledObject.SetAnnotations(original)
+		c := fakeclient.NewBuilder(deployment, override.DeepCopy(), scaledObject,
+			hpa("keda-hpa-worker", "Deployment", "worker", map[string]string{kedaScaledObjectLabel: "worker-so"})).
+			WithScheme(scheme).
+			Build()
+		r := newTestReconciler(c, nil)
+
+		latest := &dynamicscalingv1.ReplicasOverride{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), latest); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.rollbackOverride(ctx, latest); err != nil {
+			t.Fatalf("rollbackOverride() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(scaledObject), scaledObject); err != nil {
+			t.Fatal(err)
+		}
+		if minReplicas, maxReplicas := nestedInt32(scaledObject, "minReplicaCount", 0), nestedInt32(scaledObject, "maxReplicaCount", 0); minReplicas != 2 || maxReplicas != 20 {
+			t.Errorf("ScaledObject limits = [%d, %d], want the original [2, 20]", minReplicas, maxReplicas)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			t.Fatal(err)
+		}
+		if *deployment.Spec.Replicas != 7 {
+			t.Errorf("replicas = %d, want 7 left to KEDA", *deployment.Spec.Replicas)
+		}
+	})
+
+	t.Run("argo rollouts", func(t *testing.T) {
+		deployment := limitsOnly(scalerArgoRollouts)
+		rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"workloadRef": map[string]interface{}{"kind": "Deployment", "name": "worker"}},
+		}}
+		rollout.SetGroupVersionKind(rolloutListGVK.GroupVersion().WithKind("Rollout"))
+		rollout.SetName("worker-rollout")
+		rollout.SetNamespace("shop")
+		rolloutHPA := hpa("worker-rollout", "Rollout", "worker-rollout", nil)
+		c := fakeclient.NewBuilder(deployment, override.DeepCopy(), rollout, rolloutHPA).
+			WithScheme(scheme).
+			Build()
+		r := newTestReconciler(c, nil)
+
+		latest := &dynamicscalingv1.ReplicasOverride{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(override), latest); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.rollbackOverride(ctx, latest); err != nil {
+			t.Fatalf("rollbackOverride() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(rolloutHPA), rolloutHPA); err != nil {
+			t.Fatal(err)
+		}
+		if *rolloutHPA.Spec.MinReplicas != 2 || rolloutHPA.Spec.MaxReplicas != 20 {
+			t.Errorf("HPA limits = [%d, %d], want the original [2, 20]", *rolloutHPA.Spec.MinReplicas, rolloutHPA.Spec.MaxReplicas)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			t.Fatal(err)
+		}
+		if *deployment.Spec.Replicas != 7 {
+			t.Errorf("replicas = %d, want 7 left to the Rollout", *deployment.Spec.Replicas)
+		}
+	})
+}
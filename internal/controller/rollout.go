@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// rolloutRecheckInterval is how often a replica change held by a rollout is retried
+	rolloutRecheckInterval = 15 * time.Second
+
+	// progressDeadlineExceededReason is the reason of the Progressing condition of a stuck rollout
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// errRolloutInProgress is returned by processDeployment when a replica change
+// is held until the rollout of the deployment completes
+var errRolloutInProgress = errors.New("replica change held until the rollout completes")
+
+// rollingOut returns true while the deployment controller moves pods from old
+// ReplicaSets to the new one, or has not observed the latest spec yet. A
+// rollout stuck past its progress deadline no longer counts, so it cannot
+// hold replica changes forever.
+func rollingOut(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == progressDeadlineExceededReason {
+			return false
+		}
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return true
+	}
+	return deployment.Status.UpdatedReplicas < deployment.Status.Replicas
+}
+
+// rolloutChange returns whether a change of the replicas of deployment to
+// target is held until its rollout completes, or applied in a single write
+// without scale-down steps, according to the rollouts config
+func rolloutChange(cfg *config.GlobalConfig, deployment *appsv1.Deployment, target int32) (hold, atomic bool) {
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == target || !rollingOut(deployment) {
+		return false, false
+	}
+	switch cfg.Rollouts.ReplicaChanges {
+	case config.RolloutChangeWait:
+		return true, false
+	case config.RolloutChangeAtomic:
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestRolloutChange(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(4)
+	rollingOutDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			// One surge pod of the new ReplicaSet next to the four old ones
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 5, UpdatedReplicas: 1, ReadyReplicas: 4},
+		}
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"},
+		Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 50},
+	}
+
+	stuck := rollingOutDeployment()
+	stuck.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason,
+	}}
+	if rollingOut(stuck) {
+		t.Error("rollingOut() = true for a rollout past its progress deadline")
+	}
+
+	tests := []struct {
+		mode    string
+		wantErr error
+		want    int32
+	}{
+		{mode: "", want: 3},
+		{mode: config.RolloutChangeWait, wantErr: errRolloutInProgress, want: 4},
+		{mode: config.RolloutChangeAtomic, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run("mode "+tt.mode, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.ScaleDown.Stepped = true
+			cfg.Rollouts.ReplicaChanges = tt.mode
+			deployment := rollingOutDeployment()
+			c := fakeclient.NewBuilder(deployment).Build()
+			r := newTestReconciler(c, cfg)
+
+			if err := r.processDeployment(ctx, deployment, override); err != tt.wantErr {
+				t.Fatalf("processDeployment() = %v, want %v", err, tt.wantErr)
+			}
+			got := &appsv1.Deployment{}
+			if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, got); err != nil {
+				t.Fatal(err)
+			}
+			if *got.Spec.Replicas != tt.want {
+				t.Errorf("replicas = %d, want %d", *got.Spec.Replicas, tt.want)
+			}
+		})
+	}
+}
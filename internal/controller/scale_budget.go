@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+// ScaleBudgetConditionType is the inventory condition reporting whether the
+// scale budget defers replica changes
+const ScaleBudgetConditionType = "Throttled"
+
+// errScaleBudgetExceeded is returned when a replica change is deferred
+// because the scale budget of the window is spent
+var errScaleBudgetExceeded = failure.New(failure.CapacityExceeded, "replica change deferred, the scale budget is exhausted")
+
+// budgetSpend is a replica change counted against the scale budget
+type budgetSpend struct {
+	at       time.Time
+	replicas int32
+}
+
+// ScaleBudget counts the replica changes of the controller over the sliding
+// window of the scaleBudget of the global config, and defers further changes
+// once its operations or replicas are spent
+type ScaleBudget struct {
+	mu     sync.Mutex
+	spends []budgetSpend
+	// throttledUntil is when the last deferred change fits the budget again
+	throttledUntil time.Time
+}
+
+// NewScaleBudget returns an empty scale budget
+func NewScaleBudget() *ScaleBudget {
+	return &ScaleBudget{}
+}
+
+// spend counts a change of replicas against the budget and returns true, or
+// returns false and when the change fits again if it exceeds the budget. A nil
+// budget or one without limits allows everything.
+func (b *ScaleBudget) spend(cfg config.ScaleBudgetConfig, replicas int32, now time.Time) (bool, time.Time) {
+	if b == nil || !cfg.Enabled() {
+		return true, time.Time{}
+	}
+	if replicas < 0 {
+		replicas = -replicas
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire(now.Add(-cfg.GetWindow()))
+
+	var changed int32
+	for _, spend := range b.spends {
+		changed += spend.replicas
+	}
+	if len(b.spends) == 0 ||
+		(cfg.MaxOperations <= 0 || int32(len(b.spends)) < cfg.MaxOperations) &&
+			(cfg.MaxReplicasChanged <= 0 || changed+replicas <= cfg.MaxReplicasChanged) {
+		// A single change larger than the whole budget still goes through on an empty window
+		b.spends = append(b.spends, budgetSpend{at: now, replicas: replicas})
+		metrics.SetScaleBudgetThrottled(now.Before(b.throttledUntil))
+		return true, time.Time{}
+	}
+
+	// The change fits once enough of the oldest spends left the window
+	until := b.spends[0].at.Add(cfg.GetWindow())
+	for i, spend := range b.spends {
+		changed -= spend.replicas
+		operations := int32(len(b.spends) - i - 1)
+		if (cfg.MaxOperations <= 0 || operations < cfg.MaxOperations) &&
+			(cfg.MaxReplicasChanged <= 0 || changed+replicas <= cfg.MaxReplicasChanged) {
+			until = spend.at.Add(cfg.GetWindow())
+			break
+		}
+	}
+	if until.After(b.throttledUntil) {
+		b.throttledUntil = until
+	}
+	metrics.SetScaleBudgetThrottled(true)
+	return false, until
+}
+
+// expire drops the spends made before since
+func (b *ScaleBudget) expire(since time.Time) {
+	kept := 0
+	for kept < len(b.spends) && b.spends[kept].at.Before(since) {
+		kept++
+	}
+	b.spends = b.spends[kept:]
+}
+
+// throttled returns whether changes were deferred lately, and until when
+func (b *ScaleBudget) throttled(now time.Time) (bool, time.Time) {
+	if b == nil {
+		return false, time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return now.Before(b.throttledUntil), b.throttledUntil
+}
+
+// spendBudget counts a replica change of a target from previous to target
+// against the scale budget, returning errScaleBudgetExceeded if it is deferred
+func (r *ReplicasOverrideReconciler) spendBudget(ctx context.Context, labels metrics.ScalingLabels, name string, previous, target int32) error {
+	cfg := r.Config.GetConfig()
+	if cfg == nil {
+		return nil
+	}
+	allowed, until := r.Budget.spend(cfg.ScaleBudget, target-previous, time.Now())
+	if allowed {
+		return nil
+	}
+	log.FromContext(ctx).Info("Scale budget exhausted, deferring replica change",
+		"kind", labels.TargetKind,
+		"target", fmt.Sprintf("%s/%s", labels.Namespace, name),
+		"previous", previous,
+		"desired", target,
+		"until", until)
+	metrics.RecordDeferred(labels)
+	return errScaleBudgetExceeded
+}
+
+// budgetRecheck returns when replica changes deferred by the scale budget are
+// retried, zero when none are
+func (r *ReplicasOverrideReconciler) budgetRecheck(now time.Time) time.Time {
+	throttled, until := r.Budget.throttled(now)
+	metrics.SetScaleBudgetThrottled(throttled)
+	if !throttled {
+		return time.Time{}
+	}
+	return until
+}
+
+// scaleBudgetCondition returns the Throttled condition of the scale budget
+func (r *ReplicasOverrideReconciler) scaleBudgetCondition(now time.Time) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    ScaleBudgetConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "WithinBudget",
+		Message: "Replica changes are applied as they are decided",
+	}
+	if throttled, until := r.Budget.throttled(now); throttled {
+		condition.Status, condition.Reason = metav1.ConditionTrue, "ScaleBudgetExhausted"
+		condition.Message = fmt.Sprintf("The scale budget is exhausted, replica changes are deferred until %s", until.UTC().Format(time.RFC3339))
+	}
+	return condition
+}
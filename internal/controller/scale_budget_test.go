@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestScaleBudget(t *testing.T) {
+	start := time.Date(2025, 11, 28, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		cfg       config.ScaleBudgetConfig
+		changes   []int32
+		wantSpent int
+		wantUntil time.Duration
+	}{
+		{name: "no limits", changes: []int32{50, 50, 50}, wantSpent: 3},
+		{name: "operations spent", cfg: config.ScaleBudgetConfig{MaxOperations: 2}, changes: []int32{1, -1, 1}, wantSpent: 2, wantUntil: 10 * time.Minute},
+		{name: "replicas spent", cfg: config.ScaleBudgetConfig{MaxReplicasChanged: 10, Window: 5 * time.Minute}, changes: []int32{4, -4, 4}, wantSpent: 2, wantUntil: 5 * time.Minute},
+		{name: "oversized change on an empty window", cfg: config.ScaleBudgetConfig{MaxReplicasChanged: 10}, changes: []int32{30, 1}, wantSpent: 1, wantUntil: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := NewScaleBudget()
+			spent := 0
+			var until time.Time
+			for i, change := range tt.changes {
+				allowed, next := budget.spend(tt.cfg, change, start.Add(time.Duration(i)*time.Second))
+				if allowed {
+					spent++
+				} else {
+					until = next
+				}
+			}
+			if spent != tt.wantSpent {
+				t.Errorf("spent %d changes, want %d", spent, tt.wantSpent)
+			}
+			if tt.wantUntil == 0 && !until.IsZero() || tt.wantUntil != 0 && !until.Equal(start.Add(tt.wantUntil)) {
+				t.Errorf("deferred until %v, want %v after the start", until, tt.wantUntil)
+			}
+		})
+	}
+
+	// Spends leave the window and free the budget again
+	budget := NewScaleBudget()
+	cfg := config.ScaleBudgetConfig{MaxOperations: 1}
+	if allowed, _ := budget.spend(cfg, 1, start); !allowed {
+		t.Fatal("first change deferred")
+	}
+	if allowed, _ := budget.spend(cfg, 1, start.Add(time.Minute)); allowed {
+		t.Fatal("second change within the window allowed")
+	}
+	if throttled, _ := budget.throttled(start.Add(time.Minute)); !throttled {
+		t.Error("budget not throttled after deferring a change")
+	}
+	if allowed, _ := budget.spend(cfg, 1, start.Add(11*time.Minute)); !allowed {
+		t.Error("change after the window deferred")
+	}
+}
+
+func TestScaleBudgetDefersDeployments(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		}
+	}
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		deployment("api"), deployment("web"),
+	).Build()
+	cfg := &config.GlobalConfig{Enabled: true, GlobalPercentage: 200, MinReplicas: 1, MaxReplicas: 100,
+		ScaleBudget: config.ScaleBudgetConfig{MaxOperations: 1}}
+	r := newTestReconciler(c, cfg)
+	r.Budget = NewScaleBudget()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	scaled := 0
+	for _, name := range []string{"api", "web"} {
+		var d appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, &d); err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if *d.Spec.Replicas == 4 {
+			scaled++
+		}
+	}
+	if scaled != 1 {
+		t.Errorf("scaled %d deployments, want 1 within the budget", scaled)
+	}
+	if condition := r.scaleBudgetCondition(time.Now()); condition.Status != metav1.ConditionTrue {
+		t.Errorf("Throttled condition = %s/%s, want True", condition.Status, condition.Reason)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultMaxUnavailable is the maxUnavailable of a RollingUpdate Deployment that does not set it
+var defaultMaxUnavailable = intstr.FromString("25%")
+
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list
+
+// pdbDisruptionsAllowed returns the fewest disruptions allowed by the
+// PodDisruptionBudgets selecting pods with the given labels, or -1 if none does
+func (r *ReplicasOverrideReconciler) pdbDisruptionsAllowed(ctx context.Context, namespace string, podLabels map[string]string) (int32, error) {
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := r.List(ctx, pdbs, client.InNamespace(namespace)); err != nil {
+		return 0, err
+	}
+
+	allowed := int32(-1)
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+		if allowed < 0 || pdb.Status.DisruptionsAllowed < allowed {
+			allowed = pdb.Status.DisruptionsAllowed
+		}
+	}
+	return allowed, nil
+}
+
+// scaleDownStep returns the replicas to set on deployment on the way down to
+// target, and whether target is still out of reach. Unready pods are removed
+// first by the ReplicaSet controller and cost no capacity; on top of them a
+// step removes at most maxUnavailable Ready pods, and no more than the
+// disruptions the PodDisruptionBudgets allow (pdbAllowed, -1 without PDB).
+// Steps wait for the Deployment controller to observe the previous one.
+func scaleDownStep(deployment *appsv1.Deployment, target, pdbAllowed int32) (int32, bool) {
+	if deployment.Spec.Replicas == nil || target >= *deployment.Spec.Replicas {
+		return target, false
+	}
+	current := *deployment.Spec.Replicas
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return current, true
+	}
+
+	budget := int32(-1)
+	if strategy := deployment.Spec.Strategy; strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		maxUnavailable := &defaultMaxUnavailable
+		if strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = strategy.RollingUpdate.MaxUnavailable
+		}
+		value, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(current), false)
+		if err != nil || value < 1 {
+			value = 1
+		}
+		budget = int32(value)
+	}
+	if pdbAllowed >= 0 && (budget < 0 || pdbAllowed < budget) {
+		budget = pdbAllowed
+	}
+	if budget < 0 {
+		return target, false
+	}
+
+	unready := current - deployment.Status.ReadyReplicas
+	if unready < 0 {
+		unready = 0
+	}
+	next := current - unready - budget
+	if next <= target {
+		return target, false
+	}
+	return next, true
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestScaleDownStep(t *testing.T) {
+	deployment := func(replicas, ready int32, strategy appsv1.DeploymentStrategy) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Strategy: strategy},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, ReadyReplicas: ready},
+		}
+	}
+	maxUnavailable := func(value intstr.IntOrString) appsv1.DeploymentStrategy {
+		return appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType, RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &value}}
+	}
+	recreate := appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	unobserved := deployment(10, 10, appsv1.DeploymentStrategy{})
+	unobserved.Generation = 3
+
+	tests := []struct {
+		name        string
+		deployment  *appsv1.Deployment
+		target      int32
+		pdbAllowed  int32
+		want        int32
+		wantPending bool
+	}{
+		{name: "scale-up is not stepped", deployment: deployment(4, 4, appsv1.DeploymentStrategy{}), target: 8, pdbAllowed: -1, want: 8},
+		{name: "default 25% maxUnavailable", deployment: deployment(20, 20, appsv1.DeploymentStrategy{}), target: 5, pdbAllowed: -1, want: 15, wantPending: true},
+		{name: "absolute maxUnavailable", deployment: deployment(20, 20, maxUnavailable(intstr.FromInt32(2))), target: 5, pdbAllowed: -1, want: 18, wantPending: true},
+		{name: "zero maxUnavailable still moves", deployment: deployment(20, 20, maxUnavailable(intstr.FromInt32(0))), target: 5, pdbAllowed: -1, want: 19, wantPending: true},
+		{name: "unready pods go first", deployment: deployment(20, 17, maxUnavailable(intstr.FromInt32(2))), target: 5, pdbAllowed: -1, want: 15, wantPending: true},
+		{name: "PDB is stricter", deployment: deployment(20, 20, appsv1.DeploymentStrategy{}), target: 5, pdbAllowed: 1, want: 19, wantPending: true},
+		{name: "PDB allows no disruption", deployment: deployment(20, 20, appsv1.DeploymentStrategy{}), target: 5, pdbAllowed: 0, want: 20, wantPending: true},
+		{name: "last step reaches target", deployment: deployment(6, 6, appsv1.DeploymentStrategy{}), target: 5, pdbAllowed: -1, want: 5},
+		{name: "recreate without PDB is not stepped", deployment: deployment(20, 20, recreate), target: 5, pdbAllowed: -1, want: 5},
+		{name: "recreate with PDB", deployment: deployment(20, 20, recreate), target: 5, pdbAllowed: 3, want: 17, wantPending: true},
+		{name: "waits for the previous step", deployment: unobserved, target: 5, pdbAllowed: -1, want: 10, wantPending: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pending := scaleDownStep(tt.deployment, tt.target, tt.pdbAllowed)
+			if got != tt.want || pending != tt.wantPending {
+				t.Errorf("scaleDownStep() = (%d, %v), want (%d, %v)", got, pending, tt.want, tt.wantPending)
+			}
+		})
+	}
+}
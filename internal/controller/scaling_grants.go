@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// +kubebuilder:rbac:groups=kubedynamicscaler.io,resources=scalinggrants,verbs=get;list;watch
+
+// ReferenceGrantedConditionType is the ReplicasOverride condition reporting
+// whether a ScalingGrant lets its deploymentRef target another namespace
+const ReferenceGrantedConditionType = "ReferenceGranted"
+
+// crossNamespaceRef returns the namespace of the deployment referenced by
+// override if it is not the namespace of the override, or ""
+func crossNamespaceRef(override *dynamicscalingv1.ReplicasOverride) string {
+	if override.Spec.DeploymentRef == nil || override.Spec.DeploymentRef.Namespace == "" ||
+		override.Spec.DeploymentRef.Namespace == override.Namespace {
+		return ""
+	}
+	return override.Spec.DeploymentRef.Namespace
+}
+
+// granted returns the grant of grants letting override target the deployment
+// namespace/name, or nil
+func granted(grants []dynamicscalingv1.ScalingGrant, override *dynamicscalingv1.ReplicasOverride, namespace, name string) *dynamicscalingv1.ScalingGrant {
+	for i := range grants {
+		grant := &grants[i]
+		if grant.Namespace != namespace {
+			continue
+		}
+		if len(grant.Spec.Deployments) > 0 && !slices.Contains(grant.Spec.Deployments, name) {
+			continue
+		}
+		for _, from := range grant.Spec.From {
+			if from.Namespace == override.Namespace && (len(from.Overrides) == 0 || slices.Contains(from.Overrides, override.Name)) {
+				return grant
+			}
+		}
+	}
+	return nil
+}
+
+// referenceGrantedCondition reports whether a grant lets the deploymentRef of
+// override target another namespace
+func referenceGrantedCondition(override *dynamicscalingv1.ReplicasOverride, grant *dynamicscalingv1.ScalingGrant) metav1.Condition {
+	ref := override.Spec.DeploymentRef
+	condition := metav1.Condition{
+		Type:               ReferenceGrantedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Granted",
+		ObservedGeneration: override.Generation,
+	}
+	if grant == nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotGranted"
+		condition.Message = fmt.Sprintf("No ScalingGrant of namespace %s grants deployment %s to this override", ref.Namespace, ref.Name)
+	} else {
+		condition.Message = fmt.Sprintf("ScalingGrant %s/%s grants deployment %s/%s", grant.Namespace, grant.Name, ref.Namespace, ref.Name)
+	}
+	return condition
+}
+
+// scalingGrants lists the grants of every namespace, none when the
+// ScalingGrant CRD is not installed
+func (r *ReplicasOverrideReconciler) scalingGrants(ctx context.Context) ([]dynamicscalingv1.ScalingGrant, error) {
+	grants := &dynamicscalingv1.ScalingGrantList{}
+	if err := r.List(ctx, grants); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return grants.Items, nil
+}
+
+// checkReferenceGrants sets the ReferenceGranted condition of the overrides
+// whose deploymentRef targets another namespace
+func (r *ReplicasOverrideReconciler) checkReferenceGrants(ctx context.Context, overrides []dynamicscalingv1.ReplicasOverride, grants []dynamicscalingv1.ScalingGrant) {
+	log := log.FromContext(ctx)
+
+	for i := range overrides {
+		override := &overrides[i]
+		namespace := crossNamespaceRef(override)
+		if namespace == "" || !override.DeletionTimestamp.IsZero() {
+			continue
+		}
+		grant := granted(grants, override, namespace, override.Spec.DeploymentRef.Name)
+		if !meta.SetStatusCondition(&override.Status.Conditions, referenceGrantedCondition(override, grant)) {
+			continue
+		}
+		if grant == nil {
+			log.Info("Override references a deployment of another namespace without a grant",
+				"override", override.Name,
+				"namespace", override.Namespace,
+				"deployment", fmt.Sprintf("%s/%s", namespace, override.Spec.DeploymentRef.Name))
+		}
+		if err := r.Status().Update(ctx, override); err != nil {
+			log.Error(err, "Failed to update override status",
+				"override", override.Name,
+				"namespace", override.Namespace)
+		}
+	}
+}
+
+// grantedOverrides indexes the overrides of other namespaces whose
+// deploymentRef is granted by the namespace and name of the deployment they
+// target, so each deployment looks them up instead of listing every override
+func grantedOverrides(overrides []dynamicscalingv1.ReplicasOverride, grants []dynamicscalingv1.ScalingGrant) map[types.NamespacedName][]dynamicscalingv1.ReplicasOverride {
+	found := make(map[types.NamespacedName][]dynamicscalingv1.ReplicasOverride)
+	for i := range overrides {
+		override := &overrides[i]
+		namespace := crossNamespaceRef(override)
+		if namespace == "" || granted(grants, override, namespace, override.Spec.DeploymentRef.Name) == nil {
+			continue
+		}
+		target := types.NamespacedName{Namespace: namespace, Name: override.Spec.DeploymentRef.Name}
+		found[target] = append(found[target], *override)
+	}
+	return found
+}
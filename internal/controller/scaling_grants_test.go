@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestScalingGrants(t *testing.T) {
+	ctx := context.Background()
+	deployment := func(namespace, name string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		}
+	}
+	override := func(name, namespace, target string, percentage int32) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: target, Namespace: "shop"},
+				OverrideType:       "override",
+				ReplicasPercentage: percentage,
+			},
+		}
+	}
+	// The shop team only lets the sre namespace scale its api
+	grant := &dynamicscalingv1.ScalingGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "sre", Namespace: "shop"},
+		Spec: dynamicscalingv1.ScalingGrantSpec{
+			From:        []dynamicscalingv1.ScalingGrantFrom{{Namespace: "sre"}},
+			Deployments: []string{"api"},
+		},
+	}
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sre"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "blog"}},
+		deployment("shop", "api"), deployment("shop", "web"), deployment("shop", "worker"), grant,
+		override("api-sale", "sre", "api", 200),
+		override("web-sale", "sre", "web", 200),
+		override("worker-sale", "blog", "worker", 200),
+		// Overrides of the namespace itself win over granted ones
+		override("api-local", "shop", "api", 150),
+	).
+		Build()
+	r := newTestReconciler(c, nil)
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	for name, want := range map[string]int32{"api": 3, "web": 2, "worker": 2} {
+		var d appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, &d); err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if *d.Spec.Replicas != want {
+			t.Errorf("replicas of %s = %d, want %d", name, *d.Spec.Replicas, want)
+		}
+	}
+
+	// Without the local override the granted one applies
+	if err := c.Delete(ctx, override("api-local", "shop", "api", 150)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	var api appsv1.Deployment
+	if err := c.Get(ctx, types.NamespacedName{Name: "api", Namespace: "shop"}, &api); err != nil {
+		t.Fatal(err)
+	}
+	if *api.Spec.Replicas != 4 {
+		t.Errorf("replicas of api = %d, want 4", *api.Spec.Replicas)
+	}
+
+	for key, want := range map[types.NamespacedName]metav1.ConditionStatus{
+		{Name: "api-sale", Namespace: "sre"}:     metav1.ConditionTrue,
+		{Name: "web-sale", Namespace: "sre"}:     metav1.ConditionFalse,
+		{Name: "worker-sale", Namespace: "blog"}: metav1.ConditionFalse,
+	} {
+		var got dynamicscalingv1.ReplicasOverride
+		if err := c.Get(ctx, key, &got); err != nil {
+			t.Fatal(err)
+		}
+		condition := meta.FindStatusCondition(got.Status.Conditions, ReferenceGrantedConditionType)
+		if condition == nil || condition.Status != want {
+			t.Errorf("%s condition of %s = %+v, want %s", ReferenceGrantedConditionType, key, condition, want)
+		}
+		if meta.FindStatusCondition(got.Status.Conditions, TargetsMatchedConditionType) != nil {
+			t.Errorf("%s of %s reports its targets of another namespace as unmatched", TargetsMatchedConditionType, key)
+		}
+	}
+}
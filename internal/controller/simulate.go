@@ -0,0 +1,378 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/expression"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/manifests"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// SimulationPath is the path of the what-if endpoint, served by the
+	// metrics server behind the same authentication and authorization
+	SimulationPath = "/simulate"
+
+	// simulatedOverrideName names a hypothetical override submitted without a name
+	simulatedOverrideName = "simulated"
+
+	// maxSimulationRequestBytes bounds the size of a simulation request
+	maxSimulationRequestBytes = 1 << 20
+)
+
+// SimulationRequest is a hypothetical change whose effect on the Deployments
+// and their HPAs is computed without applying anything
+type SimulationRequest struct {
+	// Namespace is the namespace simulated, so a simulation reveals no more
+	// than the workloads of one namespace. It defaults to the namespace of
+	// Override.
+	Namespace string `json:"namespace,omitempty"`
+
+	// GlobalPercentage replaces the percentage of the global config
+	GlobalPercentage *int32 `json:"globalPercentage,omitempty"`
+
+	// Override is evaluated before the existing overrides of its namespace,
+	// replacing the one with the same name, if any
+	Override *dynamicscalingv1.ReplicasOverride `json:"override,omitempty"`
+
+	// Manifests replaces the live Deployments and HPAs with those of rendered
+	// manifests, previewing a Git revision before it is merged. Only the
+	// workloads of the manifests are simulated.
+	Manifests *ManifestsRef `json:"manifests,omitempty"`
+}
+
+// SimulatedWorkload is the outcome of a simulation for one Deployment
+type SimulatedWorkload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// HPA is the name of the HPA scaling the Deployment, whose limits are changed instead
+	HPA string `json:"hpa,omitempty"`
+
+	// Override is the override applied to the Deployment, empty for the global config
+	Override string `json:"override,omitempty"`
+
+	// CurrentReplicas and Replicas are the replicas, or the HPA minReplicas,
+	// now and after the change
+	CurrentReplicas int32 `json:"currentReplicas"`
+	Replicas        int32 `json:"replicas"`
+
+	// GitReplicas and GitMaxReplicas are the replicas, or the HPA limits,
+	// declared by the manifests of a preview
+	GitReplicas    *int32 `json:"gitReplicas,omitempty"`
+	GitMaxReplicas int32  `json:"gitMaxReplicas,omitempty"`
+
+	// CurrentMaxReplicas and MaxReplicas are the HPA maxReplicas now and after the change
+	CurrentMaxReplicas int32 `json:"currentMaxReplicas,omitempty"`
+	MaxReplicas        int32 `json:"maxReplicas,omitempty"`
+
+	// Changed is true if the change would scale the Deployment or its HPA
+	Changed bool `json:"changed"`
+
+	// Clamped is true if the replicas were limited by the min/max replicas
+	Clamped bool `json:"clamped,omitempty"`
+
+	// PlaceholderReplicas is the number of placeholder pods reserving capacity
+	// for the Deployment when its override is in placeholder mode
+	PlaceholderReplicas int32 `json:"placeholderReplicas,omitempty"`
+
+	// Explanation tells which rule produced the replicas, or why they are left alone
+	Explanation string `json:"explanation"`
+
+	// decision is the decision chain behind Replicas, when they were computed
+	decision *precedence.Explanation
+}
+
+// SimulationResponse lists the outcome of a simulation for every Deployment in scope
+type SimulationResponse struct {
+	// Revision is the Git revision of the manifests previewed, if any
+	Revision  string              `json:"revision,omitempty"`
+	Workloads []SimulatedWorkload `json:"workloads"`
+}
+
+// validate checks the request and defaults the namespace and override name
+func (req *SimulationRequest) validate() error {
+	if req.GlobalPercentage != nil && (*req.GlobalPercentage < 0 || *req.GlobalPercentage > expression.MaxPercentage) {
+		return fmt.Errorf("globalPercentage must be between 0 and %d", expression.MaxPercentage)
+	}
+	if req.Manifests != nil {
+		if err := req.Manifests.validate(); err != nil {
+			return err
+		}
+	}
+	if req.Override == nil {
+		if req.Namespace == "" {
+			return fmt.Errorf("namespace is required")
+		}
+		return nil
+	}
+
+	o := req.Override
+	if o.Namespace == "" {
+		o.Namespace = req.Namespace
+	}
+	if req.Namespace == "" {
+		req.Namespace = o.Namespace
+	}
+	if o.Namespace == "" {
+		return fmt.Errorf("namespace is required")
+	}
+	if o.Namespace != req.Namespace {
+		return fmt.Errorf("override namespace %q does not match namespace %q", o.Namespace, req.Namespace)
+	}
+	if o.Name == "" {
+		o.Name = simulatedOverrideName
+	}
+	if o.Spec.ReplicasPercentage < 0 || o.Spec.ReplicasPercentage > expression.MaxPercentage {
+		return fmt.Errorf("override replicasPercentage must be between 0 and %d", expression.MaxPercentage)
+	}
+	if o.Spec.DeploymentRef == nil && o.Spec.HPARef == nil && o.Spec.Environments == nil && (o.Spec.Selector == nil || len(o.Spec.Selector.MatchLabels) == 0) {
+		return fmt.Errorf("override must have a selector, environments, deploymentRef or hpaRef")
+	}
+	if environments := o.Spec.Environments; environments != nil {
+		if environments.Label == "" || len(environments.Values) == 0 {
+			return fmt.Errorf("override environments need a label and values")
+		}
+		for _, environment := range environments.Values {
+			if environment.ReplicasPercentage < 0 || environment.ReplicasPercentage > expression.MaxPercentage {
+				return fmt.Errorf("environment %q replicasPercentage must be between 0 and %d", environment.Value, expression.MaxPercentage)
+			}
+		}
+	}
+	return nil
+}
+
+// withOverride returns overrides with o evaluated first, replacing the override of the same name
+func withOverride(overrides []dynamicscalingv1.ReplicasOverride, o dynamicscalingv1.ReplicasOverride) []dynamicscalingv1.ReplicasOverride {
+	result := []dynamicscalingv1.ReplicasOverride{o}
+	for _, existing := range overrides {
+		if existing.Name != o.Name {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
+
+// Simulate returns the replicas every Deployment of the namespace of req, or
+// its HPA, would be scaled to if the hypothetical change of req were applied. Nothing is
+// written to the cluster. Stepped scale-downs report their final target, and
+// Deployments deferred to an external scaler are reported but not simulated.
+// With manifests, the Deployments and HPAs they declare in the namespace are
+// simulated instead of the live ones and compared to the live replicas.
+func (r *ReplicasOverrideReconciler) Simulate(ctx context.Context, req SimulationRequest) (*SimulationResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := r.Config.GetConfig()
+	if cfg == nil {
+		return nil, fmt.Errorf("global config not found")
+	}
+	sim := *r
+	if req.GlobalPercentage != nil {
+		hypothetical := *cfg
+		hypothetical.GlobalPercentage = *req.GlobalPercentage
+		sim.Config = r.Config.WithConfig(&hypothetical)
+	}
+
+	ignoreList := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := r.List(ctx, ignoreList); err != nil {
+		return nil, err
+	}
+	var rendered *manifests.Set
+	if req.Manifests != nil {
+		var err error
+		if rendered, err = r.loadManifests(ctx, req.Manifests, req.Namespace); err != nil {
+			return nil, fmt.Errorf("failed to load manifests: %w", err)
+		}
+	}
+
+	response := &SimulationResponse{Workloads: []SimulatedWorkload{}}
+	if req.Manifests != nil {
+		response.Revision = req.Manifests.Revision
+	}
+	namespace := req.Namespace
+	deployments := &appsv1.DeploymentList{}
+	if err := r.List(ctx, deployments, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	deployments.Items = withoutPlaceholders(deployments.Items)
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	overrides := overrideList.Items
+	if req.Override != nil {
+		overrides = withOverride(overrides, *req.Override)
+	}
+	live, liveHPAs := deployments.Items, hpaList.Items
+	if rendered != nil {
+		deployments.Items, hpaList.Items = renderedWorkloads(rendered, namespace)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if isWorkloadIgnored("Deployment", deployment, ignoreList.Items) || deployment.Spec.Replicas == nil {
+			continue
+		}
+		workload, err := sim.simulateDeployment(ctx, deployment, hpaList.Items, overrides)
+		if err != nil {
+			return nil, err
+		}
+		if rendered != nil {
+			// Compare the outcome of the manifests with what runs now
+			gitReplicas := workload.CurrentReplicas
+			workload.GitReplicas, workload.GitMaxReplicas = &gitReplicas, workload.CurrentMaxReplicas
+			workload.CurrentReplicas, workload.CurrentMaxReplicas = liveReplicas(live, liveHPAs, deployment.Name)
+			workload.Changed = workload.Replicas != workload.CurrentReplicas || workload.MaxReplicas != workload.CurrentMaxReplicas
+		}
+		response.Workloads = append(response.Workloads, workload)
+	}
+	return response, nil
+}
+
+// simulateDeployment computes the outcome of a simulation for one Deployment
+// the same way processDeployment and processHPA scale it
+func (r *ReplicasOverrideReconciler) simulateDeployment(ctx context.Context, deployment *appsv1.Deployment, hpas []autoscalingv2.HorizontalPodAutoscaler, overrides []dynamicscalingv1.ReplicasOverride) (SimulatedWorkload, error) {
+	workload := SimulatedWorkload{
+		Kind:            metrics.TargetKindDeployment,
+		Namespace:       deployment.Namespace,
+		Name:            deployment.Name,
+		CurrentReplicas: *deployment.Spec.Replicas,
+	}
+
+	var override *dynamicscalingv1.ReplicasOverride
+	if matches := deploymentOverrides(overrides, hpas, deployment); len(matches) > 0 {
+		override = matches[0]
+		workload.Override = override.Name
+	}
+
+	cfg := r.configFor(ctx, deployment.Namespace)
+	if cfg == nil {
+		return workload, fmt.Errorf("global config not found")
+	}
+
+	var hpa *autoscalingv2.HorizontalPodAutoscaler
+	for i := range hpas {
+		if scalesDeployment(&hpas[i]) && hpas[i].Spec.ScaleTargetRef.Name == deployment.Name {
+			hpa = &hpas[i]
+			break
+		}
+	}
+	if brokenHPA(cfg, hpa, deployment.Annotations[utils.BrokenHPAAnnotation], time.Now()) != "" {
+		hpa = nil
+	}
+	if hpa != nil {
+		workload.HPA = hpa.Name
+		workload.CurrentReplicas = 1
+		if hpa.Spec.MinReplicas != nil {
+			workload.CurrentReplicas = *hpa.Spec.MinReplicas
+		}
+		workload.CurrentMaxReplicas = hpa.Spec.MaxReplicas
+		workload.MaxReplicas = hpa.Spec.MaxReplicas
+	}
+	workload.Replicas = workload.CurrentReplicas
+
+	kind := metrics.TargetKindDeployment
+	if hpa != nil {
+		kind = metrics.TargetKindHPA
+	}
+	scaler := detectExternalScaler(cfg, deployment, hpas, r.rolloutForDeployment(ctx, deployment))
+	switch frozen, _ := r.overrideFrozen(ctx, override); {
+	case frozen:
+		workload.Explanation = fmt.Sprintf("left alone: override %s is paused, monitoring, rolled back or in a blackout window", override.Name)
+	case !cfg.Enabled && r.onlyGlobalScaling(ctx, deployment, override):
+		workload.Explanation = "left alone: the global config is not enabled"
+	case time.Now().Before(workloadSettlesAt(cfg, override, deployment)):
+		workload.Explanation = "left alone: younger than minWorkloadAge"
+	case override != nil && override.Spec.Placeholder != nil:
+		replicas, explanation := r.desiredReplicas(ctx, cfg, deployment, &deployment.Spec.Template, override, utils.GetOriginalReplicas(deployment), workload.CurrentReplicas)
+		workload.PlaceholderReplicas = placeholderReplicas(deployment, replicas)
+		workload.decision = &explanation
+		workload.Explanation = fmt.Sprintf("left alone: %d placeholder pods reserve its capacity, %s", workload.PlaceholderReplicas, explanation.String())
+	case scaler != nil && (scaler.mode == config.ScalerModeIgnore || scaler.mode == config.ScalerModeLimitsOnly):
+		workload.Explanation = fmt.Sprintf("not simulated: deferred to %s", scaler.name)
+	case !targetsKind(override, kind):
+		workload.Explanation = fmt.Sprintf("left alone: override %s does not target %s", override.Name, kind)
+	case hpa != nil:
+		originalMin, originalMax := utils.GetOriginalHPALimits(hpa)
+		minReplicas, maxReplicas, clamped, explanation := r.desiredHPALimits(ctx, cfg, hpa, deployment, &deployment.Spec.Template, override, originalMin, originalMax)
+		workload.Replicas, workload.MaxReplicas, workload.Clamped = minReplicas, maxReplicas, clamped
+		workload.Explanation, workload.decision = explanation.String(), &explanation
+	case isProtectedAtZero(cfg, deployment, override):
+		workload.Explanation = "left alone: scaled to zero"
+	default:
+		replicas, explanation := r.desiredReplicas(ctx, cfg, deployment, &deployment.Spec.Template, override, scalingBase(deployment, override), workload.CurrentReplicas)
+		workload.Replicas, workload.Clamped = replicas, explanation.Clamped
+		workload.Explanation, workload.decision = explanation.String(), &explanation
+	}
+	workload.Changed = workload.Replicas != workload.CurrentReplicas || workload.MaxReplicas != workload.CurrentMaxReplicas
+	return workload, nil
+}
+
+// SimulationHandler serves Simulate on POST requests carrying a JSON SimulationRequest
+func (r *ReplicasOverrideReconciler) SimulationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "simulations must be submitted with POST", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request SimulationRequest
+		decoder := json.NewDecoder(io.LimitReader(req.Body, maxSimulationRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid simulation request: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := request.validate(); err != nil {
+			http.Error(w, fmt.Sprintf("invalid simulation request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		response, err := r.Simulate(req.Context(), request)
+		if err != nil {
+			log.FromContext(req.Context()).Error(err, "Failed to simulate scaling")
+			http.Error(w, fmt.Sprintf("simulation failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestSimulate(t *testing.T) {
+	c := fakeclient.NewBuilder(
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"team": "web"}},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MinReplicas:    int32Ptr(2),
+				MaxReplicas:    10,
+			},
+		},
+	).Build()
+	cfg := config.DefaultConfig()
+	cfg.Enabled = true
+	r := newTestReconciler(c, cfg)
+
+	response, err := r.Simulate(context.Background(), SimulationRequest{
+		Override: &dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"team": "web"}},
+				ReplicasPercentage: 150,
+			},
+		},
+		GlobalPercentage: int32Ptr(50),
+	})
+	if err != nil {
+		t.Fatalf("Simulate() failed: %v", err)
+	}
+
+	got := make(map[string]SimulatedWorkload)
+	for _, w := range response.Workloads {
+		got[w.Name] = w
+	}
+	if web := got["web"]; web.Override != simulatedOverrideName || web.Replicas != 6 || !web.Changed {
+		t.Errorf("web = %+v, want 6 replicas from the simulated override", web)
+	}
+	if api := got["api"]; api.HPA != "api" || api.Replicas != 1 || api.MaxReplicas != 5 || api.Override != "" {
+		t.Errorf("api = %+v, want its HPA limits halved by the global percentage", api)
+	}
+
+	var unchanged appsv1.Deployment
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "web", Namespace: "shop"}, &unchanged); err != nil || *unchanged.Spec.Replicas != 4 {
+		t.Errorf("Simulate() changed the deployment: %v", err)
+	}
+
+	if _, err := r.Simulate(context.Background(), SimulationRequest{Override: &dynamicscalingv1.ReplicasOverride{}}); err == nil {
+		t.Error("expected an override without namespace to be rejected")
+	}
+	if _, err := r.Simulate(context.Background(), SimulationRequest{GlobalPercentage: int32Ptr(50)}); err == nil {
+		t.Error("expected a simulation of every namespace to be rejected")
+	}
+}
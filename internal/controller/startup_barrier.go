@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+)
+
+const (
+	// startupRetryInterval is how often a reconcile arriving before the
+	// startup barrier passed is retried
+	startupRetryInterval = time.Second
+
+	// maxStartupBackoff bounds the wait between two attempts to read the
+	// startup state while the API server fails
+	maxStartupBackoff = 30 * time.Second
+)
+
+// StartupBarrier holds every scaling write until the global config, the
+// namespace configs and the GlobalReplicasIgnore rules were read once, so the
+// first reconciles after a start cannot scale workloads with the default
+// config or that an ignore rule excludes
+type StartupBarrier struct {
+	passed chan struct{}
+	once   sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewStartupBarrier returns a barrier holding the scaling until its runnable synced
+func NewStartupBarrier() *StartupBarrier {
+	return &StartupBarrier{passed: make(chan struct{})}
+}
+
+// Passed returns true once the startup state was read, always without a barrier
+func (b *StartupBarrier) Passed() bool {
+	if b == nil {
+		return true
+	}
+	select {
+	case <-b.passed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the barrier passed or ctx is done
+func (b *StartupBarrier) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	select {
+	case <-b.passed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Checker is a readiness check failing until the barrier passed, with the
+// error of the last attempt to read the startup state if any
+func (b *StartupBarrier) Checker(_ *http.Request) error {
+	if b.Passed() {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return fmt.Errorf("startup state not read yet: %w", b.err)
+	}
+	return errors.New("startup state not read yet")
+}
+
+// open lets the scaling through
+func (b *StartupBarrier) open() {
+	b.once.Do(func() { close(b.passed) })
+}
+
+// setError records the error of the last attempt to read the startup state
+func (b *StartupBarrier) setError(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+}
+
+// startupRunnable reads the startup state on every replica, so standbys are
+// ready to scale as soon as they are elected
+type startupRunnable struct {
+	r *ReplicasOverrideReconciler
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (startupRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reads the startup state, retrying with a backoff while it fails, and
+// opens the barrier
+func (s startupRunnable) Start(ctx context.Context) error {
+	barrier := s.r.Startup
+	if barrier == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx).WithName("startup")
+	backoff := startupRetryInterval
+	for {
+		err := s.r.syncStartupState(ctx)
+		if err == nil {
+			barrier.setError(nil)
+			barrier.open()
+			logger.Info("Startup state read, scaling enabled")
+			return nil
+		}
+		barrier.setError(err)
+		logger.Error(err, "Failed to read the startup state, scaling stays on hold", "retryIn", backoff)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxStartupBackoff)
+	}
+}
+
+// StartupRunnable returns the runnable opening the startup barrier once the
+// caches synced and the startup state was read
+func (r *ReplicasOverrideReconciler) StartupRunnable() manager.Runnable {
+	return startupRunnable{r: r}
+}
+
+// syncStartupState reads, in order, the global config, the namespace configs
+// and the GlobalReplicasIgnore rules. A missing or invalid config keeps the
+// default one like a reload does, failing reads are returned to be retried.
+func (r *ReplicasOverrideReconciler) syncStartupState(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("startup")
+	if err := r.Config.RefreshConfig(ctx); err != nil {
+		switch failure.Classify(err) {
+		case failure.TargetNotFound, failure.InvalidConfig:
+			logger.Error(err, "Using the default global config")
+		default:
+			return fmt.Errorf("failed to read the global config: %w", err)
+		}
+	}
+	if err := r.Config.LoadNamespaceConfigs(ctx); err != nil {
+		return err
+	}
+	// Listing through the cache waits for the informer of the rules to sync
+	ignores := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := r.List(ctx, ignores); err != nil {
+		return fmt.Errorf("failed to list ignore rules: %w", err)
+	}
+	logger.Info("Startup state read", "ignoreRules", len(ignores.Items))
+	return nil
+}
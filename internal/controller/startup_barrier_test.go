@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestStartupBarrier(t *testing.T) {
+	ctx := context.Background()
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "shop"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(4)},
+		},
+		&dynamicscalingv1.GlobalReplicasIgnore{
+			ObjectMeta: metav1.ObjectMeta{Name: "frozen"},
+			Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+				IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "Deployment", Name: "legacy", Namespace: "shop"}},
+			},
+		},
+	).Build()
+
+	// The config is read through its own client, until then the default one applies
+	t.Setenv(config.EnvConfigNamespace, config.DefaultConfigMapNamespace)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	configClient := fakeclient.NewBuilder().WithRESTMapper(mapper).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.ConfigMapName, Namespace: config.DefaultConfigMapNamespace},
+		Data:       map[string]string{config.ConfigMapKey: "enabled: true\nglobalPercentage: 50\nminReplicas: 1\nmaxReplicas: 100\n"},
+	}).Build()
+	r := &ReplicasOverrideReconciler{Client: c, Scheme: c.Scheme(), Config: config.NewManager(configClient), Startup: NewStartupBarrier()}
+
+	deploymentReplicas := func(name string) int32 {
+		var deployment appsv1.Deployment
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, &deployment); err != nil {
+			t.Fatal(err)
+		}
+		return *deployment.Spec.Replicas
+	}
+
+	// Reconciles arriving before the startup state was read write nothing
+	result, err := r.Reconcile(ctx, ctrl.Request{})
+	if err != nil || result.RequeueAfter != startupRetryInterval {
+		t.Fatalf("Reconcile() before the barrier = %+v, %v, want a retry after %v", result, err, startupRetryInterval)
+	}
+	if got := deploymentReplicas("web"); got != 4 {
+		t.Errorf("web scaled to %d before the barrier passed, want 4", got)
+	}
+	if err := r.Startup.Checker(nil); err == nil {
+		t.Error("readiness check passed before the startup state was read")
+	}
+
+	if err := r.StartupRunnable().Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !r.Startup.Passed() || r.Startup.Checker(nil) != nil {
+		t.Fatal("startup barrier did not pass once the state was read")
+	}
+	if got := r.Config.GetConfig().GlobalPercentage; got != 50 {
+		t.Errorf("global percentage = %d after startup, want the 50 of the ConfigMap", got)
+	}
+
+	// The first scaling applies the config read and leaves the ignored deployment alone
+	if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := deploymentReplicas("web"); got != 2 {
+		t.Errorf("web = %d replicas, want 2 from the global config read at startup", got)
+	}
+	if got := deploymentReplicas("legacy"); got != 4 {
+		t.Errorf("legacy = %d replicas, want 4 left alone by its ignore rule", got)
+	}
+}
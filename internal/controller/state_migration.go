@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// AnnotationMigration returns the runnable migrating the annotations of every
+// kind the controller scales to format once the leader started, instead of
+// waiting for the next write of each object. Objects are listed with reader,
+// uncached so kinds of disabled features do not start informers, in pages
+// with a non-nil pager.
+func AnnotationMigration(reader client.Reader, pager *utils.Pager, writer client.Writer, format string) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		migrated, err := utils.MigrateState(ctx, pager, reader, writer, format,
+			&appsv1.DeploymentList{},
+			&autoscalingv2.HorizontalPodAutoscalerList{},
+			&appsv1.StatefulSetList{},
+			&appsv1.ReplicaSetList{},
+			&corev1.ReplicationControllerList{},
+			&batchv1.JobList{},
+		)
+		if err != nil {
+			// Objects left behind are migrated on their next write
+			log.FromContext(ctx).Error(err, "Failed to migrate annotations", "format", format, "migrated", migrated)
+			return nil
+		}
+		log.FromContext(ctx).Info("Migrated annotations", "format", format, "migrated", migrated)
+		return nil
+	})
+}
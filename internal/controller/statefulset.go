@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// statefulSetStepInterval is how soon an OrderedReady StatefulSet still
+// stepping towards its target is checked again
+const statefulSetStepInterval = 30 * time.Second
+
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;update;patch
+
+// processStatefulSets scales the StatefulSets of a namespace matched by an
+// override selector, or by the global config. It returns nextCheck, moved
+// earlier if a StatefulSet is still stepping or a blackout window ends sooner.
+func (r *ReplicasOverrideReconciler) processStatefulSets(ctx context.Context, namespace string, ignoreList *dynamicscalingv1.GlobalReplicasIgnoreList, nextCheck time.Time) time.Time {
+	log := log.FromContext(ctx)
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := r.List(ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list statefulsets in namespace", "namespace", namespace)
+		return nextCheck
+	}
+	if len(statefulSets.Items) == 0 {
+		return nextCheck
+	}
+
+	overrideList := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := r.List(ctx, overrideList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list overrides")
+		return nextCheck
+	}
+	hpaList := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpaList, client.InNamespace(namespace)); err != nil {
+		log.Error(err, "Failed to list HPAs in namespace", "namespace", namespace)
+		return nextCheck
+	}
+
+	for i := range statefulSets.Items {
+		sts := &statefulSets.Items[i]
+		if isWorkloadIgnored("StatefulSet", sts, ignoreList.Items) || hasHPAFor(hpaList.Items, "StatefulSet", sts.Name) {
+			continue
+		}
+
+		// StatefulSets are matched by selector only, deploymentRef names a Deployment
+		override := selectorOverride(overrideList.Items, sts)
+		if !targetsKind(override, metrics.TargetKindStatefulSet) || r.globalScalingHeld(ctx, sts, override) {
+			continue
+		}
+		if skip, until := r.overrideFrozen(ctx, override); skip {
+			if !until.IsZero() && until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		if young, until := r.workloadTooYoung(ctx, "StatefulSet", sts, override, time.Now()); young {
+			if until.Before(nextCheck) {
+				nextCheck = until
+			}
+			continue
+		}
+
+		pending, err := r.processStatefulSet(ctx, sts, override)
+		if changeDeferred(err) {
+			continue
+		} else if err != nil {
+			log.Error(err, "Failed to process statefulset",
+				"statefulset", fmt.Sprintf("%s/%s", sts.Namespace, sts.Name),
+				"hasOverride", override != nil,
+				"reason", recordFailure(sts.Namespace, metrics.TargetKindStatefulSet, override, err))
+			continue
+		}
+		if step := time.Now().Add(statefulSetStepInterval); pending && step.Before(nextCheck) {
+			nextCheck = step
+		}
+	}
+	return nextCheck
+}
+
+// processStatefulSet scales a single StatefulSet. It returns true if the
+// StatefulSet was only moved one step and has not reached its target yet.
+func (r *ReplicasOverrideReconciler) processStatefulSet(ctx context.Context, sts *appsv1.StatefulSet, override *dynamicscalingv1.ReplicasOverride) (bool, error) {
+	log := log.FromContext(ctx)
+
+	cfg := r.configFor(ctx, sts.Namespace)
+	if cfg == nil {
+		return false, fmt.Errorf("global config not found")
+	}
+
+	if sts.Annotations == nil {
+		sts.Annotations = make(map[string]string)
+	}
+	var current int32 = 1
+	if sts.Spec.Replicas != nil {
+		current = *sts.Spec.Replicas
+	}
+	repaired := r.repairOriginals(ctx, "StatefulSet", sts, override)
+	if _, exists := sts.Annotations[utils.OriginalReplicasAnnotation]; !exists {
+		sts.Annotations[utils.OriginalReplicasAnnotation] = strconv.FormatInt(int64(current), 10)
+	}
+	originalReplicas, _ := utils.ParseReplicas(sts.Annotations[utils.OriginalReplicasAnnotation])
+
+	desired, explanation := r.desiredReplicas(ctx, cfg, sts, &sts.Spec.Template, override, originalReplicas, current)
+	percentage, trigger, clamped := explanation.Percentage, explanation.Trigger, explanation.Clamped
+	target, pending := statefulSetTarget(sts, desired, cfg.StatefulSets.StepOrderedReady)
+	if target != desired {
+		clamped = true
+	}
+	explanation.SetReplicas(target, clamped)
+	if target == current {
+		log.V(1).Info("StatefulSet already at desired replicas, skipping update",
+			"statefulset", fmt.Sprintf("%s/%s", sts.Namespace, sts.Name),
+			"replicas", current,
+			"pending", pending)
+		if repaired {
+			sts.Annotations[utils.ExplainAnnotation] = explanation.JSON()
+			return pending, r.Update(ctx, sts)
+		}
+		return pending, r.updateExplanation(ctx, sts, explanation)
+	}
+
+	labels := scalingLabels(sts.Namespace, metrics.TargetKindStatefulSet, override)
+	labels.Trigger = trigger
+	if err := r.paceChange(ctx, labels, override, sts.Name); err != nil {
+		return false, err
+	}
+	var err error
+	if target, err = r.authorizeChange(ctx, labels, sts.Name, current, target); err != nil {
+		return false, err
+	}
+	explanation.SetReplicas(target, clamped)
+	if err := r.spendBudget(ctx, labels, sts.Name, current, target); err != nil {
+		return false, err
+	}
+
+	if override != nil {
+		sts.Annotations[utils.OverrideControllerAnnotation] = "true"
+		sts.Annotations[utils.ManagedAnnotation] = "true"
+	} else {
+		sts.Annotations[utils.GlobalConfigManagedAnnotation] = "true"
+	}
+	sts.Annotations[utils.ManagementModeAnnotation] = "direct"
+	sts.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	sts.Annotations[utils.ExplainAnnotation] = explanation.JSON()
+	sts.Spec.Replicas = &target
+
+	log.Info("Updating statefulset replicas",
+		"statefulset", fmt.Sprintf("%s/%s", sts.Namespace, sts.Name),
+		"original", originalReplicas,
+		"previous", current,
+		"target", target,
+		"desired", desired,
+		"percentage", percentage)
+
+	if err := r.Update(ctx, sts, newChangeReason(labels, &percentage).record(sts)); err != nil {
+		metrics.RecordScalingError(ctx, labels)
+		r.recordEvent(sts.Namespace, sts.Name, labels, originalReplicas, current, target, clamped, err)
+		return false, err
+	}
+	metrics.RecordScaling(ctx, labels, current, target, percentage, clamped)
+	r.recordEvent(sts.Namespace, sts.Name, labels, originalReplicas, current, target, clamped, nil)
+	return pending, nil
+}
+
+// statefulSetTarget returns the replicas to set on sts on the way to desired,
+// and whether desired is still out of reach. Replicas never go below the
+// rolling update partition, so pods held back at the old revision are kept,
+// and with step set OrderedReady sets move one replica at a time once all
+// their pods are ready.
+func statefulSetTarget(sts *appsv1.StatefulSet, desired int32, step bool) (int32, bool) {
+	var current int32 = 1
+	if sts.Spec.Replicas != nil {
+		current = *sts.Spec.Replicas
+	}
+
+	target := desired
+	if strategy := sts.Spec.UpdateStrategy; strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil {
+		if partition := *strategy.RollingUpdate.Partition; target < partition {
+			target = partition
+		}
+	}
+
+	ordered := sts.Spec.PodManagementPolicy == "" || sts.Spec.PodManagementPolicy == appsv1.OrderedReadyPodManagement
+	if !step || !ordered || target == current {
+		return target, false
+	}
+
+	// Wait for the previous step to settle before taking the next one
+	if sts.Status.ObservedGeneration < sts.Generation ||
+		sts.Status.Replicas != current || sts.Status.ReadyReplicas != current {
+		return current, true
+	}
+	if target > current {
+		return current + 1, current+1 != target
+	}
+	return current - 1, current-1 != target
+}
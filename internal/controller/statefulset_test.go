@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestStatefulSetTarget(t *testing.T) {
+	statefulSet := func(replicas, ready int32, policy appsv1.PodManagementPolicyType, partition *int32) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:            &replicas,
+				PodManagementPolicy: policy,
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: replicas, ReadyReplicas: ready},
+		}
+		if partition != nil {
+			sts.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{Partition: partition}
+		}
+		return sts
+	}
+	partition := int32(3)
+
+	tests := []struct {
+		name        string
+		sts         *appsv1.StatefulSet
+		desired     int32
+		step        bool
+		want        int32
+		wantPending bool
+	}{
+		{name: "jumps to desired without stepping", sts: statefulSet(2, 2, "", nil), desired: 5, want: 5},
+		{name: "never below the partition", sts: statefulSet(5, 5, appsv1.ParallelPodManagement, &partition), desired: 1, want: 3},
+		{name: "steps up one replica when ready", sts: statefulSet(2, 2, "", nil), desired: 5, step: true, want: 3, wantPending: true},
+		{name: "last step is not pending", sts: statefulSet(4, 4, appsv1.OrderedReadyPodManagement, nil), desired: 5, step: true, want: 5},
+		{name: "steps down one replica when ready", sts: statefulSet(4, 4, "", nil), desired: 2, step: true, want: 3, wantPending: true},
+		{name: "waits for pods to be ready", sts: statefulSet(3, 2, "", nil), desired: 5, step: true, want: 3, wantPending: true},
+		{name: "parallel sets do not step", sts: statefulSet(2, 1, appsv1.ParallelPodManagement, nil), desired: 5, step: true, want: 5},
+		{name: "stepping stops at the partition", sts: statefulSet(3, 3, "", &partition), desired: 1, step: true, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pending := statefulSetTarget(tt.sts, tt.desired, tt.step)
+			if got != tt.want || pending != tt.wantPending {
+				t.Errorf("statefulSetTarget() = (%d, %v), want (%d, %v)", got, pending, tt.want, tt.wantPending)
+			}
+		})
+	}
+}
@@ -115,7 +115,8 @@ var _ = BeforeSuite(func() {
 			Namespace: config.DefaultConfigMapNamespace,
 		},
 		Data: map[string]string{
-			config.ConfigMapKey: `globalPercentage: 200
+			config.ConfigMapKey: `enabled: true
+globalPercentage: 200
 maxReplicas: 100
 minReplicas: 1`,
 		},
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestReconcileRecordsTraceExemplars(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	provider := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	}()
+
+	c := fakeclient.NewBuilder(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "traced"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "traced"},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		},
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-sale", Namespace: "traced"},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+				OverrideType:       "override",
+				ReplicasPercentage: 200,
+			},
+		},
+	).WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}).Build()
+	if _, err := newTestReconciler(c, nil).Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+
+	collected := make(chan prometheus.Metric, 100)
+	metrics.ScalingOperationsTotal.Collect(collected)
+	close(collected)
+	for metric := range collected {
+		m := &dto.Metric{}
+		if err := metric.Write(m); err != nil {
+			t.Fatal(err)
+		}
+		for _, label := range m.GetLabel() {
+			if label.GetName() != metrics.LabelNamespace || label.GetValue() != "traced" {
+				continue
+			}
+			if exemplar := m.GetCounter().GetExemplar(); exemplar == nil || len(exemplar.GetLabel()) == 0 {
+				t.Errorf("scaling operation recorded without a trace exemplar: %v", m)
+			}
+			return
+		}
+	}
+	t.Error("no scaling operation recorded in namespace traced")
+}
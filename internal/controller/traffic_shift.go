@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// defaultTrafficSettleDuration is how long a lowered weight is given to
+	// take effect before a scale-down removes replicas
+	defaultTrafficSettleDuration = 30 * time.Second
+
+	// maxBackendWeight is the highest backendRef weight the Gateway API accepts
+	maxBackendWeight = 1000000
+)
+
+// httpRouteGVK is the Gateway API HTTPRoute, handled as unstructured so the Gateway API is not a build dependency
+var httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// trafficSettleDuration returns how long a shift of traffic is given before replicas are removed
+func trafficSettleDuration(shift *dynamicscalingv1.TrafficShift) time.Duration {
+	if shift.SettleDuration != nil {
+		return shift.SettleDuration.Duration
+	}
+	return defaultTrafficSettleDuration
+}
+
+// trafficBackend returns the name of the backendRef serving a deployment
+func trafficBackend(shift *dynamicscalingv1.TrafficShift, deployment *appsv1.Deployment) string {
+	if shift.BackendName != "" {
+		return shift.BackendName
+	}
+	return deployment.Name
+}
+
+// backendWeight returns the weight of a backend at capacity replicas, in
+// proportion to its original weight at its original replicas. A backend with
+// capacity keeps a weight of at least 1.
+func backendWeight(original int64, capacity, originalReplicas int32) int64 {
+	if originalReplicas <= 0 {
+		return original
+	}
+	weight := (original*int64(capacity) + int64(originalReplicas)/2) / int64(originalReplicas)
+	if weight == 0 && capacity > 0 && original > 0 {
+		weight = 1
+	}
+	return min(weight, maxBackendWeight)
+}
+
+// servesBackend returns true if a backendRef of a route in routeNamespace is
+// the Service named backend in namespace
+func servesBackend(ref map[string]any, routeNamespace, namespace, backend string) bool {
+	group, _, _ := unstructured.NestedString(ref, "group")
+	kind, _, _ := unstructured.NestedString(ref, "kind")
+	name, _, _ := unstructured.NestedString(ref, "name")
+	refNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+	if refNamespace == "" {
+		refNamespace = routeNamespace
+	}
+	return group == "" && (kind == "" || kind == "Service") && name == backend && refNamespace == namespace
+}
+
+// originalWeights returns the weights recorded before the route was first shifted
+func originalWeights(route *unstructured.Unstructured) (map[string]int64, error) {
+	originals := make(map[string]int64)
+	if recorded := route.GetAnnotations()[utils.OriginalWeightsAnnotation]; recorded != "" {
+		if err := json.Unmarshal([]byte(recorded), &originals); err != nil {
+			return nil, failure.Wrap(failure.InvalidConfig, fmt.Errorf("invalid %s annotation of HTTPRoute %s/%s: %w",
+				utils.OriginalWeightsAnnotation, route.GetNamespace(), route.GetName(), err))
+		}
+	}
+	return originals, nil
+}
+
+// setBackendWeights sets the weight of the backendRefs of a route serving
+// backend in namespace to weight(original), recording the original weights
+// of those shifted first. It returns true if the route changed.
+func setBackendWeights(route *unstructured.Unstructured, originals map[string]int64, namespace, backend string, weight func(original int64) int64) bool {
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	changed := false
+	for i := range rules {
+		rule, ok := rules[i].(map[string]any)
+		if !ok {
+			continue
+		}
+		refs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for j := range refs {
+			ref, ok := refs[j].(map[string]any)
+			if !ok || !servesBackend(ref, route.GetNamespace(), namespace, backend) {
+				continue
+			}
+			// The Gateway API gives a backendRef without weight a weight of 1
+			current, found, _ := unstructured.NestedInt64(ref, "weight")
+			if !found {
+				current = 1
+			}
+			key := fmt.Sprintf("%d/%s", i, backend)
+			original, recorded := originals[key]
+			if !recorded {
+				original = current
+				originals[key] = original
+				changed = true
+			}
+			if desired := weight(original); desired != current || !found {
+				ref["weight"] = desired
+				changed = true
+			}
+		}
+		rule["backendRefs"] = refs
+	}
+	if changed {
+		_ = unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+	}
+	return changed
+}
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;update
+
+// shiftTraffic sets the weight of a deployment among the backends of the
+// HTTPRoute of the trafficShift of its override to its capacity: its ready
+// replicas, bounded by its replicas and limit. It returns true while replicas
+// are still becoming ready, for the weight to follow them.
+func (r *ReplicasOverrideReconciler) shiftTraffic(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, limit int32) (bool, error) {
+	if override == nil || override.Spec.TrafficShift == nil || deployment.Annotations[utils.ManagementModeAnnotation] != "direct" {
+		return false, nil
+	}
+	shift := override.Spec.TrafficShift
+	capacity := deployment.Status.ReadyReplicas
+	if deployment.Spec.Replicas != nil {
+		limit = min(limit, *deployment.Spec.Replicas)
+	}
+	pending := capacity < limit
+	capacity = min(capacity, limit)
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: override.Namespace, Name: shift.HTTPRoute}, route); err != nil {
+		return false, fmt.Errorf("failed to get HTTPRoute %s/%s: %w", override.Namespace, shift.HTTPRoute, err)
+	}
+	originals, err := originalWeights(route)
+	if err != nil {
+		return false, err
+	}
+	originalReplicas := utils.GetOriginalReplicas(deployment)
+	backend := trafficBackend(shift, deployment)
+	if !setBackendWeights(route, originals, deployment.Namespace, backend, func(original int64) int64 {
+		return backendWeight(original, capacity, originalReplicas)
+	}) {
+		return pending, nil
+	}
+
+	if err := writeOriginalWeights(route, originals); err != nil {
+		return false, err
+	}
+	if err := r.Update(ctx, route); err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Shifted traffic to the capacity of deployment",
+		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+		"httpRoute", fmt.Sprintf("%s/%s", route.GetNamespace(), route.GetName()),
+		"backend", backend,
+		"capacity", capacity,
+		"original", originalReplicas)
+	return pending, nil
+}
+
+// restoreTrafficWeights puts the backends of the HTTPRoute of the trafficShift
+// of an override back to their original weights
+func (r *ReplicasOverrideReconciler) restoreTrafficWeights(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) error {
+	shift := override.Spec.TrafficShift
+	if shift == nil {
+		return nil
+	}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: override.Namespace, Name: shift.HTTPRoute}, route); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	originals, err := originalWeights(route)
+	if err != nil || len(originals) == 0 {
+		return err
+	}
+
+	for i := range override.Status.AffectedDeployments {
+		affected := &override.Status.AffectedDeployments[i]
+		backend := shift.BackendName
+		if backend == "" {
+			backend = affected.Name
+		}
+		setBackendWeights(route, originals, affected.Namespace, backend, func(original int64) int64 { return original })
+	}
+	annotations := route.GetAnnotations()
+	delete(annotations, utils.OriginalWeightsAnnotation)
+	route.SetAnnotations(annotations)
+	return r.Update(ctx, route)
+}
+
+// writeOriginalWeights records the original weights of the shifted backends on the route
+func writeOriginalWeights(route *unstructured.Unstructured, originals map[string]int64) error {
+	data, err := json.Marshal(originals)
+	if err != nil {
+		return err
+	}
+	annotations := route.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[utils.OriginalWeightsAnnotation] = string(data)
+	route.SetAnnotations(annotations)
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestTrafficShift(t *testing.T) {
+	ctx := context.Background()
+	replicas := int32(4)
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "checkout", "namespace": "shop"},
+		"spec": map[string]interface{}{"rules": []interface{}{map[string]interface{}{
+			"backendRefs": []interface{}{
+				map[string]interface{}{"name": "checkout", "port": int64(8080), "weight": int64(100)},
+				map[string]interface{}{"name": "checkout-us", "port": int64(8080), "weight": int64(100)},
+			},
+		}}},
+	}}
+	route.SetGroupVersionKind(httpRouteGVK)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "shop"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "checkout"}},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 4},
+	}
+	c := fakeclient.NewBuilder(route, deployment).Build()
+	r := newTestReconciler(c, nil)
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-off-hours", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 50,
+			TrafficShift:       &dynamicscalingv1.TrafficShift{HTTPRoute: "checkout"},
+		},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{
+			AffectedDeployments: []dynamicscalingv1.AffectedDeployment{{Name: "checkout", Namespace: "shop", OriginalReplicas: 4}},
+		},
+	}
+	get := func() *appsv1.Deployment {
+		got := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: "checkout", Namespace: "shop"}, got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	weights := func() []int64 {
+		got := &unstructured.Unstructured{}
+		got.SetGroupVersionKind(httpRouteGVK)
+		if err := c.Get(ctx, types.NamespacedName{Name: "checkout", Namespace: "shop"}, got); err != nil {
+			t.Fatal(err)
+		}
+		rules, _, _ := unstructured.NestedSlice(got.Object, "spec", "rules")
+		refs, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "backendRefs")
+		var weights []int64
+		for _, ref := range refs {
+			weight, _, _ := unstructured.NestedInt64(ref.(map[string]interface{}), "weight")
+			weights = append(weights, weight)
+		}
+		return weights
+	}
+
+	// The weight is lowered before the replicas are
+	if err := r.processDeployment(ctx, get(), override); !errors.Is(err, errDownscaleDraining) {
+		t.Fatalf("processDeployment() = %v, want %v", err, errDownscaleDraining)
+	}
+	if held := get(); *held.Spec.Replicas != 4 {
+		t.Errorf("replicas while traffic settles = %d, want 4", *held.Spec.Replicas)
+	}
+	if got := weights(); !reflect.DeepEqual(got, []int64{50, 100}) {
+		t.Errorf("weights before the scale-down = %v, want [50 100]", got)
+	}
+
+	// A scale-up only gets traffic as its replicas become ready
+	scaledUp := get()
+	upscaled := int32(6)
+	scaledUp.Spec.Replicas = &upscaled
+	scaledUp.Status.ReadyReplicas = 5
+	if pending, err := r.shiftTraffic(ctx, override, scaledUp, 6); err != nil || !pending {
+		t.Fatalf("shiftTraffic() = %v, %v, want true while replicas become ready", pending, err)
+	}
+	if got := weights(); !reflect.DeepEqual(got, []int64{125, 100}) {
+		t.Errorf("weights with 5 of 6 replicas ready = %v, want [125 100]", got)
+	}
+
+	// A rollback restores the original weights
+	if err := r.restoreTrafficWeights(ctx, override); err != nil {
+		t.Fatalf("restoreTrafficWeights() failed: %v", err)
+	}
+	if got := weights(); !reflect.DeepEqual(got, []int64{100, 100}) {
+		t.Errorf("weights after a rollback = %v, want [100 100]", got)
+	}
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// ScaleDownVerifiedConditionType is the ReplicasOverride condition reporting
+// scale-downs reverted because availability dropped
+const ScaleDownVerifiedConditionType = "ScaleDownVerified"
+
+// DegradedConditionType is the ReplicasOverride condition reporting that a
+// scale-down hurt the availability or the error budget of a target and was reverted
+const DegradedConditionType = "Degraded"
+
+const (
+	// defaultVerificationWindow is how long availability is checked after a scale-down
+	defaultVerificationWindow = 5 * time.Minute
+
+	// verificationInterval is how often availability is checked during the window
+	verificationInterval = 15 * time.Second
+
+	// defaultProbeTimeout is the timeout of the HTTP probe
+	defaultProbeTimeout = 5 * time.Second
+)
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list;watch
+
+// verificationHeld returns true if a scale-down of the affected deployment was
+// reverted under the current generation of the override, which must not
+// retry it until it changes
+func verificationHeld(override *dynamicscalingv1.ReplicasOverride, affected *dynamicscalingv1.AffectedDeployment) bool {
+	return override.Spec.Verification != nil && affected != nil && affected.RevertedGeneration == override.Generation
+}
+
+// trackScaleDown starts the verification of the affected deployment when its
+// replicas were lowered from previous, and stops it once the window passed.
+// It returns true while the scale-down is being verified.
+func trackScaleDown(affected *dynamicscalingv1.AffectedDeployment, verification *dynamicscalingv1.ScaleDownVerification, previous int32, now time.Time) bool {
+	if affected.CurrentReplicas < previous {
+		start := metav1.NewTime(now)
+		affected.VerificationStartTime = &start
+		affected.VerifiedFromReplicas = previous
+	}
+	if affected.VerificationStartTime == nil {
+		return false
+	}
+	if !now.Before(affected.VerificationStartTime.Add(verificationWindow(verification))) {
+		affected.VerificationStartTime = nil
+		affected.VerifiedFromReplicas = 0
+		return false
+	}
+	return true
+}
+
+// verificationWindow returns the verification window or its default
+func verificationWindow(verification *dynamicscalingv1.ScaleDownVerification) time.Duration {
+	if verification.Window == nil || verification.Window.Duration <= 0 {
+		return defaultVerificationWindow
+	}
+	return verification.Window.Duration
+}
+
+// verifyScaleDown checks the availability of the affected deployment while a
+// scale-down is being verified, and reverts the scale-down if it dropped. It
+// returns when availability must be checked again, or zero.
+func (r *ReplicasOverrideReconciler) verifyScaleDown(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment, previous int32, now time.Time) time.Time {
+	verification := override.Spec.Verification
+	if verification == nil {
+		affected.VerificationStartTime, affected.VerifiedFromReplicas = nil, 0
+		affected.RevertedGeneration, affected.VerificationFailure = 0, ""
+		return time.Time{}
+	}
+	if !trackScaleDown(affected, verification, previous, now) {
+		return time.Time{}
+	}
+
+	problem, err := r.availabilityProblem(ctx, verification, deployment)
+	if err == nil && problem == "" && verification.ErrorRate != nil {
+		problem, err = r.errorRateProblem(ctx, override.Namespace, verification.ErrorRate)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to check availability after scale-down",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name))
+	} else if problem != "" {
+		r.revertScaleDown(ctx, override, affected, deployment, problem)
+		return time.Time{}
+	}
+
+	next := now.Add(verificationInterval)
+	if end := affected.VerificationStartTime.Add(verificationWindow(verification)); end.Before(next) {
+		next = end
+	}
+	return next
+}
+
+// availabilityProblem returns why the deployment is not available, or "" if
+// every Service selecting its pods keeps enough ready endpoints and the HTTP
+// probe succeeds
+func (r *ReplicasOverrideReconciler) availabilityProblem(ctx context.Context, verification *dynamicscalingv1.ScaleDownVerification, deployment *appsv1.Deployment) (string, error) {
+	minReady := int32(1)
+	if verification.MinReadyEndpoints != nil {
+		minReady = *verification.MinReadyEndpoints
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.List(ctx, services, client.InNamespace(deployment.Namespace)); err != nil {
+		return "", err
+	}
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) == 0 || !matcher.LabelsMatch(deployment.Spec.Template.Labels, service.Spec.Selector) {
+			continue
+		}
+		slices := &discoveryv1.EndpointSliceList{}
+		if err := r.List(ctx, slices, client.InNamespace(service.Namespace),
+			client.MatchingLabels{discoveryv1.LabelServiceName: service.Name}); err != nil {
+			return "", err
+		}
+		if ready := readyEndpoints(slices.Items); ready < minReady {
+			return fmt.Sprintf("Service %s has %d ready endpoints, want at least %d", service.Name, ready, minReady), nil
+		}
+	}
+
+	if verification.HTTPProbe != nil {
+		if err := r.Config.GetConfig().URLAllowed(verification.HTTPProbe.URL); err != nil {
+			return fmt.Sprintf("HTTP probe is refused: %v", err), nil
+		}
+		return probeHTTP(ctx, verification.HTTPProbe), nil
+	}
+	return "", nil
+}
+
+// readyEndpoints counts the distinct ready endpoint addresses of slices. A
+// missing ready condition is interpreted as ready, as the API mandates.
+func readyEndpoints(slices []discoveryv1.EndpointSlice) int32 {
+	ready := make(map[string]bool)
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			for _, address := range endpoint.Addresses {
+				ready[address] = true
+			}
+		}
+	}
+	return int32(len(ready))
+}
+
+// probeHTTP requests the probe URL, without following redirects, and
+// returns why it failed, or ""
+func probeHTTP(ctx context.Context, probe *dynamicscalingv1.HTTPProbe) string {
+	timeout := defaultProbeTimeout
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return fmt.Sprintf("HTTP probe %s is invalid: %v", probe.URL, err)
+	}
+	resp, err := config.NewHTTPClient(0).Do(req)
+	if err != nil {
+		return fmt.Sprintf("HTTP probe %s failed: %v", probe.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return fmt.Sprintf("HTTP probe %s returned %s", probe.URL, resp.Status)
+	}
+	return ""
+}
+
+// revertScaleDown restores the replicas of the deployment before the
+// scale-down being verified, and holds it there until the override changes
+func (r *ReplicasOverrideReconciler) revertScaleDown(ctx context.Context, override *dynamicscalingv1.ReplicasOverride, affected *dynamicscalingv1.AffectedDeployment, deployment *appsv1.Deployment, problem string) {
+	log := log.FromContext(ctx)
+	restored := affected.VerifiedFromReplicas
+	current := affected.CurrentReplicas
+	labels := scalingLabels(deployment.Namespace, metrics.TargetKindDeployment, override)
+	labels.Trigger = metrics.TriggerVerification
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &appsv1.Deployment{}
+		if err := r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, latest); err != nil {
+			return err
+		}
+		if latest.Annotations == nil {
+			latest.Annotations = make(map[string]string)
+		}
+		latest.Spec.Replicas = &restored
+		latest.Annotations[utils.LastUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		if err := r.Update(ctx, latest, newChangeReason(labels, nil).record(latest)); err != nil {
+			return err
+		}
+		*deployment = *latest
+		return nil
+	})
+
+	r.recordEvent(deployment.Namespace, deployment.Name, labels, affected.OriginalReplicas, current, restored, false, err)
+	if err != nil {
+		metrics.RecordScalingError(ctx, labels)
+		log.Error(err, "Failed to revert scale-down",
+			"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+			"reason", problem)
+		return
+	}
+	metrics.RecordScaling(ctx, labels, current, restored, affected.CurrentPercentage, false)
+
+	log.Info("Reverted scale-down after availability dropped",
+		"deployment", fmt.Sprintf("%s/%s", deployment.Namespace, deployment.Name),
+		"from", current,
+		"to", restored,
+		"reason", problem)
+	affected.CurrentReplicas = restored
+	affected.VerificationStartTime, affected.VerifiedFromReplicas = nil, 0
+	affected.RevertedGeneration = override.Generation
+	affected.VerificationFailure = problem
+}
+
+// scaleDownVerifiedCondition returns the ScaleDownVerified condition of an
+// override verifying its scale-downs
+func scaleDownVerifiedCondition(override *dynamicscalingv1.ReplicasOverride) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ScaleDownVerifiedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Available",
+		ObservedGeneration: override.Generation,
+	}
+
+	var reverted []string
+	for _, affected := range override.Status.AffectedDeployments {
+		switch {
+		case verificationHeld(override, &affected):
+			reverted = append(reverted, fmt.Sprintf("%s/%s back to %d replicas: %s", affected.Namespace, affected.Name, affected.CurrentReplicas, affected.VerificationFailure))
+		case affected.VerificationStartTime != nil:
+			condition.Reason = "Verifying"
+		}
+	}
+	if len(reverted) > 0 {
+		sort.Strings(reverted)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ScaleDownReverted"
+		condition.Message = fmt.Sprintf("Reverted until the override changes: %s", strings.Join(reverted, "; "))
+	}
+	return condition
+}
+
+// degradedCondition returns the Degraded condition matching the
+// ScaleDownVerified condition of an override
+func degradedCondition(override *dynamicscalingv1.ReplicasOverride, verified metav1.Condition) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               DegradedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Healthy",
+		ObservedGeneration: override.Generation,
+	}
+	if verified.Status == metav1.ConditionFalse {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionTrue, verified.Reason, verified.Message
+	}
+	return condition
+}
+
+// setScaleDownVerifiedCondition sets or removes the ScaleDownVerified and Degraded conditions
+func setScaleDownVerifiedCondition(override *dynamicscalingv1.ReplicasOverride) {
+	if override.Spec.Verification == nil {
+		meta.RemoveStatusCondition(&override.Status.Conditions, ScaleDownVerifiedConditionType)
+		meta.RemoveStatusCondition(&override.Status.Conditions, DegradedConditionType)
+		return
+	}
+	verified := scaleDownVerifiedCondition(override)
+	meta.SetStatusCondition(&override.Status.Conditions, verified)
+	meta.SetStatusCondition(&override.Status.Conditions, degradedCondition(override, verified))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+func TestScaleDownVerification(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	healthy := true
+	probe := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer probe.Close()
+	errorRate, lastQuery := "0", ""
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lastQuery = req.URL.Query().Get("query")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"value":[0,"` + errorRate + `"]}]}}`))
+	}))
+	defer prometheus.Close()
+
+	ready, notReady := true, false
+	objects := func() []client.Object {
+		replicas := int32(6)
+		serviceLevel := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "web", "namespace": "shop"},
+			"spec": map[string]interface{}{"slos": []interface{}{map[string]interface{}{
+				"name":      "requests-availability",
+				"objective": 99.9,
+				"sli": map[string]interface{}{"events": map[string]interface{}{
+					"errorQuery": `sum(rate(http_requests_total{code=~"5.."}[{{.window}}]))`,
+					"totalQuery": `sum(rate(http_requests_total[{{.window}}]))`,
+				}},
+			}}},
+		}}
+		serviceLevel.SetGroupVersionKind(prometheusServiceLevelGVK)
+		return []client.Object{
+			serviceLevel,
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: &replicas,
+					Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web", "version": "v2"}}},
+				},
+			},
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+				Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+			},
+			&discoveryv1.EndpointSlice{
+				ObjectMeta:  metav1.ObjectMeta{Name: "web-abc", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Endpoints: []discoveryv1.Endpoint{
+					{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+					{Addresses: []string{"10.0.0.2"}},
+					{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+				},
+			},
+		}
+	}
+
+	sloRef := &dynamicscalingv1.ErrorRateCheck{SLORef: &dynamicscalingv1.SLOReference{Name: "web"}}
+	query := &dynamicscalingv1.ErrorRateCheck{Query: "errors:ratio", MaxErrorRate: "0.01"}
+
+	tests := []struct {
+		name         string
+		verification *dynamicscalingv1.ScaleDownVerification
+		unhealthy    bool
+		errorRate    string
+		allowedURLs  []string
+		wantReverted bool
+	}{
+		{name: "enough ready endpoints", verification: &dynamicscalingv1.ScaleDownVerification{MinReadyEndpoints: int32Ptr(2)}},
+		{name: "too few ready endpoints", verification: &dynamicscalingv1.ScaleDownVerification{MinReadyEndpoints: int32Ptr(3)}, wantReverted: true},
+		{name: "probe succeeds", verification: &dynamicscalingv1.ScaleDownVerification{HTTPProbe: &dynamicscalingv1.HTTPProbe{URL: probe.URL}}, allowedURLs: []string{probe.URL}},
+		{name: "probe fails", verification: &dynamicscalingv1.ScaleDownVerification{HTTPProbe: &dynamicscalingv1.HTTPProbe{URL: probe.URL}}, allowedURLs: []string{probe.URL}, unhealthy: true, wantReverted: true},
+		{name: "probe outside allowedURLs", verification: &dynamicscalingv1.ScaleDownVerification{HTTPProbe: &dynamicscalingv1.HTTPProbe{URL: probe.URL}}, wantReverted: true},
+		{name: "error rate within the SLO budget", verification: &dynamicscalingv1.ScaleDownVerification{ErrorRate: sloRef}, errorRate: "0.0005"},
+		{name: "error rate burns the SLO budget", verification: &dynamicscalingv1.ScaleDownVerification{ErrorRate: sloRef}, errorRate: "0.002", wantReverted: true},
+		{name: "error rate above the query budget", verification: &dynamicscalingv1.ScaleDownVerification{ErrorRate: query}, errorRate: "0.02", wantReverted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthy, errorRate = !tt.unhealthy, tt.errorRate
+			cfg := config.DefaultConfig()
+			cfg.ErrorBudget.PrometheusURL = prometheus.URL
+			cfg.AllowedURLs = tt.allowedURLs
+			r := newTestReconciler(fakeclient.NewBuilder(objects()...).Build(), cfg)
+
+			override := &dynamicscalingv1.ReplicasOverride{
+				ObjectMeta: metav1.ObjectMeta{Name: "night", Namespace: "shop", Generation: 3},
+				Spec:       dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 50, Verification: tt.verification},
+				Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+					{Name: "web", Namespace: "shop", OriginalReplicas: 6, CurrentReplicas: 3},
+				}},
+			}
+			affected := &override.Status.AffectedDeployments[0]
+			deployment := &appsv1.Deployment{}
+			if err := r.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, deployment); err != nil {
+				t.Fatal(err)
+			}
+
+			next := r.verifyScaleDown(ctx, override, affected, deployment, 6, now)
+			setScaleDownVerifiedCondition(override)
+			condition := meta.FindStatusCondition(override.Status.Conditions, ScaleDownVerifiedConditionType)
+
+			if reverted := verificationHeld(override, affected); reverted != tt.wantReverted {
+				t.Fatalf("verificationHeld() = %v, want %v (failure %q)", reverted, tt.wantReverted, affected.VerificationFailure)
+			}
+			if !tt.wantReverted {
+				if !next.Equal(now.Add(verificationInterval)) || condition.Reason != "Verifying" {
+					t.Errorf("verifyScaleDown() = (%s, %s), want the next check while verifying", next, condition.Reason)
+				}
+				if trackScaleDown(affected, tt.verification, 3, now.Add(defaultVerificationWindow)) || affected.VerificationStartTime != nil {
+					t.Error("expected the verification to end with its window")
+				}
+				return
+			}
+
+			if affected.CurrentReplicas != 6 || *deployment.Spec.Replicas != 6 || condition.Status != metav1.ConditionFalse {
+				t.Errorf("after the revert got %d replicas in status, %d on the deployment and condition %s, want 6, 6 and False",
+					affected.CurrentReplicas, *deployment.Spec.Replicas, condition.Status)
+			}
+			if degraded := meta.FindStatusCondition(override.Status.Conditions, DegradedConditionType); degraded == nil || degraded.Status != metav1.ConditionTrue {
+				t.Errorf("Degraded condition = %+v, want True after the revert", degraded)
+			}
+			if tt.verification.ErrorRate == sloRef {
+				if want := `(sum(rate(http_requests_total{code=~"5.."}[2m]))) / (sum(rate(http_requests_total[2m])))`; lastQuery != want {
+					t.Errorf("Prometheus query = %s, want %s", lastQuery, want)
+				}
+			}
+			override.Generation++
+			if verificationHeld(override, affected) {
+				t.Error("expected a change of the override to release the deployment")
+			}
+		})
+	}
+}
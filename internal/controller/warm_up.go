@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+const (
+	// revisionAnnotation is the revision the deployment controller gives each rollout
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// newReplicaSetAvailableReason is the reason of the Progressing condition of a completed rollout
+	newReplicaSetAvailableReason = "NewReplicaSetAvailable"
+)
+
+// rolloutCompletedAt returns when the current rollout of a deployment
+// completed, or false while it is still in progress
+func rolloutCompletedAt(deployment *appsv1.Deployment) (time.Time, bool) {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return time.Time{}, false
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == newReplicaSetAvailableReason {
+			return condition.LastUpdateTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// trackWarmUp starts the warm-up boost of the affected deployment once a new
+// revision completed its rollout, and returns when the boost ends if it is
+// still active at now. The boost is tied to the revision rather than to the
+// Progressing condition, which the deployment controller also updates when
+// the boost itself scales the deployment.
+func trackWarmUp(override *dynamicscalingv1.ReplicasOverride, deployment *appsv1.Deployment, now time.Time) time.Time {
+	if override == nil || override.Spec.WarmUp == nil {
+		return time.Time{}
+	}
+	affected := deploymentBackup(override, deployment.Namespace, deployment.Name)
+	if affected == nil {
+		return time.Time{}
+	}
+
+	revision := deployment.Annotations[revisionAnnotation]
+	if revision != "" && revision != affected.WarmUpRevision {
+		if completed, ok := rolloutCompletedAt(deployment); ok {
+			until := metav1.NewTime(completed.Add(override.Spec.WarmUp.Duration.Duration))
+			affected.WarmUpRevision, affected.WarmUpUntil = revision, &until
+		}
+	}
+	if affected.WarmUpUntil == nil || !now.Before(affected.WarmUpUntil.Time) {
+		return time.Time{}
+	}
+	return affected.WarmUpUntil.Time
+}
+
+// warmUpBoost returns the percentage added to a deployment warming up after a rollout
+func warmUpBoost(override *dynamicscalingv1.ReplicasOverride, workload metav1.Object, now time.Time) int32 {
+	if override == nil || override.Spec.WarmUp == nil {
+		return 0
+	}
+	if _, ok := workload.(*appsv1.Deployment); !ok {
+		return 0
+	}
+	affected := deploymentBackup(override, workload.GetNamespace(), workload.GetName())
+	if affected == nil || affected.WarmUpUntil == nil || !now.Before(affected.WarmUpUntil.Time) {
+		return 0
+	}
+	return override.Spec.WarmUp.BoostPercentage
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestWarmUp(t *testing.T) {
+	completed := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	override := &dynamicscalingv1.ReplicasOverride{
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			WarmUp: &dynamicscalingv1.WarmUpBoost{BoostPercentage: 30, Duration: metav1.Duration{Duration: 10 * time.Minute}},
+		},
+		Status: dynamicscalingv1.ReplicasOverrideStatus{
+			AffectedDeployments: []dynamicscalingv1.AffectedDeployment{{Name: "web", Namespace: "shop", WarmUpRevision: "1"}},
+		},
+	}
+	deployment := func(revision, reason string, updated time.Time) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{revisionAnnotation: revision}},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{{
+					Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: reason, LastUpdateTime: metav1.NewTime(updated),
+				}},
+			},
+		}
+	}
+
+	// A rollout still in progress does not start the boost
+	if until := trackWarmUp(override, deployment("2", "ReplicaSetUpdated", completed), completed); !until.IsZero() {
+		t.Errorf("trackWarmUp() during the rollout = %v, want no boost", until)
+	}
+
+	web := deployment("2", newReplicaSetAvailableReason, completed)
+	if until := trackWarmUp(override, web, completed.Add(time.Minute)); !until.Equal(completed.Add(10 * time.Minute)) {
+		t.Errorf("trackWarmUp() = %v, want the end of the boost", until)
+	}
+	if boost := warmUpBoost(override, web, completed.Add(time.Minute)); boost != 30 {
+		t.Errorf("warmUpBoost() = %d, want 30", boost)
+	}
+
+	// The condition updated by the boost scaling the deployment does not extend it
+	if until := trackWarmUp(override, deployment("2", newReplicaSetAvailableReason, completed.Add(5*time.Minute)), completed.Add(6*time.Minute)); !until.Equal(completed.Add(10 * time.Minute)) {
+		t.Errorf("trackWarmUp() after scaling = %v, want the boost of the revision", until)
+	}
+
+	if boost := warmUpBoost(override, web, completed.Add(10*time.Minute)); boost != 0 {
+		t.Errorf("warmUpBoost() after the boost = %d, want 0", boost)
+	}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+	if boost := warmUpBoost(override, sts, completed.Add(time.Minute)); boost != 0 {
+		t.Errorf("warmUpBoost() of a StatefulSet = %d, want 0", boost)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/decision"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/expression"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/matcher"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// selectorOverride returns the override whose selector or environments match
+// the labels of workload, the oldest one like for deployments when several do.
+// Overrides with a deploymentRef only ever target a Deployment.
+func selectorOverride(overrides []dynamicscalingv1.ReplicasOverride, workload metav1.Object) *dynamicscalingv1.ReplicasOverride {
+	var matches []*dynamicscalingv1.ReplicasOverride
+	for i := range overrides {
+		o := &overrides[i]
+		if o.Spec.DeploymentRef == nil && o.Spec.CanaryScope == "" && matcher.SelectsLabels(o, workload.GetLabels()) {
+			matches = append(matches, o)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	oldestFirst(workload.GetNamespace(), matches)
+	return matches[0]
+}
+
+// overridePercentage returns the percentage of override for a workload with
+// the given labels, the one of its environment when it has environments
+func overridePercentage(override *dynamicscalingv1.ReplicasOverride, labels map[string]string) int32 {
+	if environment := matcher.Environment(override, labels); environment != nil {
+		return environment.ReplicasPercentage
+	}
+	return override.Spec.ReplicasPercentage
+}
+
+// targetsKind returns true if override scales objects of the given kind. The
+// global config, a nil override, scales every kind. Bounds overrides only
+// scale deployments without HPA, the HPA enforcing its own limits.
+func targetsKind(override *dynamicscalingv1.ReplicasOverride, kind string) bool {
+	if enforcesBounds(override) && kind != metrics.TargetKindDeployment {
+		return false
+	}
+	if override == nil || len(override.Spec.TargetKinds) == 0 {
+		return true
+	}
+	for _, k := range override.Spec.TargetKinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// enforcesBounds returns true if override applies no percentage and only
+// keeps the replicas of its targets within its min/max replicas
+func enforcesBounds(override *dynamicscalingv1.ReplicasOverride) bool {
+	return override != nil && override.Spec.OverrideType == dynamicscalingv1.OverrideTypeBounds
+}
+
+// scalingBase returns the replicas the percentage of override applies to for
+// a deployment: its original replicas, or its current ones for a bounds
+// override clamping whatever others set
+func scalingBase(deployment *appsv1.Deployment, override *dynamicscalingv1.ReplicasOverride) int32 {
+	if enforcesBounds(override) && deployment.Spec.Replicas != nil {
+		return *deployment.Spec.Replicas
+	}
+	return utils.GetOriginalReplicas(deployment)
+}
+
+// overrideFrozen returns true if override must not change its targets because
+// it is paused, in Monitor mode, being rolled back or in a blackout window,
+// and when the window ends
+func (r *ReplicasOverrideReconciler) overrideFrozen(ctx context.Context, override *dynamicscalingv1.ReplicasOverride) (bool, time.Time) {
+	if override == nil {
+		return false, time.Time{}
+	}
+	if isPaused(override) || monitoring(override) || rollbackRequested(override) {
+		return true, time.Time{}
+	}
+	return r.checkBlackout(ctx, override)
+}
+
+// workloadSettlesAt returns when a workload becomes old enough to be scaled
+// under the minWorkloadAge of the override, or of the global config if the
+// override sets none. It is zero when no minimum age applies.
+func workloadSettlesAt(cfg *config.GlobalConfig, override *dynamicscalingv1.ReplicasOverride, workload metav1.Object) time.Time {
+	var minAge time.Duration
+	if override != nil && override.Spec.MinWorkloadAge != nil {
+		minAge = override.Spec.MinWorkloadAge.Duration
+	} else if cfg != nil {
+		minAge = cfg.MinWorkloadAge
+	}
+	created := workload.GetCreationTimestamp()
+	if minAge <= 0 || created.IsZero() {
+		return time.Time{}
+	}
+	return created.Add(minAge)
+}
+
+// workloadTooYoung returns true if the workload must not be scaled yet, and
+// when it will be old enough
+func (r *ReplicasOverrideReconciler) workloadTooYoung(ctx context.Context, kind string, workload metav1.Object, override *dynamicscalingv1.ReplicasOverride, now time.Time) (bool, time.Time) {
+	settlesAt := workloadSettlesAt(r.configFor(ctx, workload.GetNamespace()), override, workload)
+	if !now.Before(settlesAt) {
+		return false, time.Time{}
+	}
+	log.FromContext(ctx).V(1).Info("Workload is younger than the minimum age, skipping",
+		"kind", kind,
+		"workload", fmt.Sprintf("%s/%s", workload.GetNamespace(), workload.GetName()),
+		"until", settlesAt)
+	return true, settlesAt
+}
+
+// desiredReplicas returns the replicas of a workload with the given original
+// replicas, decided by every stage of the decision chain, with the
+// explanation of the percentage used
+func (r *ReplicasOverrideReconciler) desiredReplicas(ctx context.Context, cfg *config.GlobalConfig, workload metav1.Object, template *corev1.PodTemplateSpec, override *dynamicscalingv1.ReplicasOverride, original, current int32) (int32, precedence.Explanation) {
+	explanation := r.decisionChain().Decide(ctx, &decision.Input{
+		Workload: workload,
+		Template: template,
+		Override: override,
+		Config:   cfg,
+		Original: original,
+		Current:  current,
+	})
+	if explanation.Replicas == nil {
+		explanation.SetReplicas(utils.PercentOf(original, explanation.Percentage), false)
+	}
+	return *explanation.Replicas, explanation
+}
+
+// specReplicas returns the replicas of a workload spec, 0 when unset
+func specReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 0
+	}
+	return *replicas
+}
+
+// headroomReplicas returns the number of spare replicas the override keeps on top of the demand
+func headroomReplicas(override *dynamicscalingv1.ReplicasOverride) int32 {
+	if override == nil {
+		return 0
+	}
+	return override.Spec.HeadroomReplicas
+}
+
+// percentageFromWorkload reads the percentage of a workload from the label or
+// annotation selected by source. It returns false if the workload has neither.
+func percentageFromWorkload(source *dynamicscalingv1.PercentageSource, workload metav1.Object) (int32, bool, error) {
+	var value string
+	var found bool
+	if source.LabelKey != "" {
+		value, found = workload.GetLabels()[source.LabelKey]
+	}
+	if !found && source.AnnotationKey != "" {
+		value, found = workload.GetAnnotations()[source.AnnotationKey]
+	}
+	if !found {
+		return 0, false, nil
+	}
+
+	percentage, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), "%"), 10, 32)
+	if err != nil || percentage < 0 || percentage > expression.MaxPercentage {
+		return 0, false, fmt.Errorf("percentage %q must be an integer between 0 and %d", value, expression.MaxPercentage)
+	}
+	return int32(percentage), true, nil
+}
+
+// isWorkloadIgnored returns true if an ignore rule matches the workload of the
+// given kind by namespace, resource or labels
+func isWorkloadIgnored(kind string, workload metav1.Object, ignores []dynamicscalingv1.GlobalReplicasIgnore) bool {
+	for _, ignore := range ignores {
+		for _, namespace := range ignore.Spec.IgnoreNamespaces {
+			if namespace == workload.GetNamespace() {
+				return true
+			}
+		}
+		for _, resource := range ignore.Spec.IgnoreResources {
+			namespace := resource.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			if resource.Kind == kind && resource.Name == workload.GetName() && namespace == workload.GetNamespace() {
+				return true
+			}
+		}
+		if len(ignore.Spec.IgnoreLabels) > 0 && matcher.LabelsMatch(workload.GetLabels(), ignore.Spec.IgnoreLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHPAFor returns true if one of hpas scales the apps/v1 target of the given kind and name
+func hasHPAFor(hpas []autoscalingv2.HorizontalPodAutoscaler, kind, name string) bool {
+	for _, hpa := range hpas {
+		if hpa.Spec.ScaleTargetRef.Kind == kind && hpa.Spec.ScaleTargetRef.Name == name &&
+			hpa.Spec.ScaleTargetRef.APIVersion == "apps/v1" {
+			return true
+		}
+	}
+	return false
+}
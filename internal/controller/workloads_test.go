@@ -0,0 +1,367 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestIsWorkloadIgnored(t *testing.T) {
+	workload := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:      "legacy",
+		Namespace: "default",
+		Labels:    map[string]string{"team": "core"},
+	}}
+	ignore := func(spec dynamicscalingv1.GlobalReplicasIgnoreSpec) []dynamicscalingv1.GlobalReplicasIgnore {
+		return []dynamicscalingv1.GlobalReplicasIgnore{{Spec: spec}}
+	}
+
+	tests := []struct {
+		name    string
+		kind    string
+		ignores []dynamicscalingv1.GlobalReplicasIgnore
+		want    bool
+	}{
+		{name: "no rules", kind: "ReplicaSet", want: false},
+		{name: "ignored namespace", kind: "ReplicaSet", ignores: ignore(dynamicscalingv1.GlobalReplicasIgnoreSpec{IgnoreNamespaces: []string{"default"}}), want: true},
+		{name: "ignored resource of the same kind", kind: "ReplicaSet", ignores: ignore(dynamicscalingv1.GlobalReplicasIgnoreSpec{
+			IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "ReplicaSet", Name: "legacy"}},
+		}), want: true},
+		{name: "resource of another kind", kind: "ReplicationController", ignores: ignore(dynamicscalingv1.GlobalReplicasIgnoreSpec{
+			IgnoreResources: []dynamicscalingv1.IgnoredResource{{Kind: "ReplicaSet", Name: "legacy"}},
+		}), want: false},
+		{name: "ignored labels", kind: "ReplicaSet", ignores: ignore(dynamicscalingv1.GlobalReplicasIgnoreSpec{IgnoreLabels: map[string]string{"team": "core"}}), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWorkloadIgnored(tt.kind, workload, tt.ignores); got != tt.want {
+				t.Errorf("isWorkloadIgnored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentageFromWorkload(t *testing.T) {
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Labels:      map[string]string{"scaling/weight": "120", "scaling/broken": "lots"},
+		Annotations: map[string]string{"scaling/weight": "80%", "scaling/too-high": "5000"},
+	}}
+
+	tests := []struct {
+		name      string
+		source    dynamicscalingv1.PercentageSource
+		want      int32
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "label", source: dynamicscalingv1.PercentageSource{LabelKey: "scaling/weight"}, want: 120, wantFound: true},
+		{name: "label wins over annotation", source: dynamicscalingv1.PercentageSource{LabelKey: "scaling/weight", AnnotationKey: "scaling/weight"}, want: 120, wantFound: true},
+		{name: "annotation with percent sign", source: dynamicscalingv1.PercentageSource{AnnotationKey: "scaling/weight"}, want: 80, wantFound: true},
+		{name: "falls back to annotation", source: dynamicscalingv1.PercentageSource{LabelKey: "scaling/missing", AnnotationKey: "scaling/weight"}, want: 80, wantFound: true},
+		{name: "missing", source: dynamicscalingv1.PercentageSource{LabelKey: "scaling/missing"}},
+		{name: "not a number", source: dynamicscalingv1.PercentageSource{LabelKey: "scaling/broken"}, wantErr: true},
+		{name: "out of range", source: dynamicscalingv1.PercentageSource{AnnotationKey: "scaling/too-high"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := percentageFromWorkload(&tt.source, workload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("percentageFromWorkload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("percentageFromWorkload() = (%d, %v), want (%d, %v)", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestTargetsKind(t *testing.T) {
+	hpaOnly := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{
+		TargetKinds: []dynamicscalingv1.TargetKind{dynamicscalingv1.TargetKindHPA},
+	}}
+
+	if !targetsKind(nil, metrics.TargetKindDeployment) {
+		t.Error("expected the global config to scale every kind")
+	}
+	if !targetsKind(&dynamicscalingv1.ReplicasOverride{}, targetKindJob) {
+		t.Error("expected an override without targetKinds to scale every kind")
+	}
+	if !targetsKind(hpaOnly, metrics.TargetKindHPA) {
+		t.Error("expected an HPA-only override to scale HPAs")
+	}
+	if targetsKind(hpaOnly, metrics.TargetKindDeployment) {
+		t.Error("expected an HPA-only override not to scale raw replicas")
+	}
+}
+
+func TestHeadroomReplicas(t *testing.T) {
+	r := newTestReconciler(fakeclient.NewBuilder().Build(), nil)
+	override := &dynamicscalingv1.ReplicasOverride{
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 100, HeadroomReplicas: 2},
+	}
+	workload := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+
+	replicas, explanation := r.desiredReplicas(context.Background(), config.DefaultConfig(), workload, &workload.Spec.Template, override, 4, 4)
+	if replicas != 6 || explanation.Headroom != 2 {
+		t.Errorf("desiredReplicas() = (%d, headroom %d), want (6, headroom 2)", replicas, explanation.Headroom)
+	}
+
+	// Demand of 8 replicas at 80% CPU with a 50% target, the max of 9 leaves no room for 2 spares
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: int32Ptr(2),
+			MaxReplicas: 12,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: int32Ptr(50)},
+				},
+			}},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 5,
+			CurrentMetrics: []autoscalingv2.MetricStatus{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: int32Ptr(80)},
+				},
+			}},
+		},
+	}
+	minReplicas, maxReplicas, _, _ := r.desiredHPALimits(context.Background(), config.DefaultConfig(), hpa, workload, &workload.Spec.Template, override, 2, 9)
+	if minReplicas != 10 || maxReplicas != 10 {
+		t.Errorf("desiredHPALimits() = (%d, %d), want the max raised to the demand plus 2 spares (10, 10)", minReplicas, maxReplicas)
+	}
+}
+
+func TestWorkloadSettlesAt(t *testing.T) {
+	created := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", CreationTimestamp: metav1.Time{Time: created}}}
+	cfg := &config.GlobalConfig{MinWorkloadAge: 10 * time.Minute}
+	withAge := func(age time.Duration) *dynamicscalingv1.ReplicasOverride {
+		return &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{MinWorkloadAge: &metav1.Duration{Duration: age}}}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *config.GlobalConfig
+		override *dynamicscalingv1.ReplicasOverride
+		want     time.Time
+	}{
+		{name: "no minimum age", cfg: &config.GlobalConfig{}, want: time.Time{}},
+		{name: "global config", cfg: cfg, want: created.Add(10 * time.Minute)},
+		{name: "override without age uses the global config", cfg: cfg, override: &dynamicscalingv1.ReplicasOverride{}, want: created.Add(10 * time.Minute)},
+		{name: "override wins", cfg: cfg, override: withAge(30 * time.Minute), want: created.Add(30 * time.Minute)},
+		{name: "override disables the gate", cfg: cfg, override: withAge(0), want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workloadSettlesAt(tt.cfg, tt.override, deployment); !got.Equal(tt.want) {
+				t.Errorf("workloadSettlesAt() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDesiredReplicasBounds(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler(fakeclient.NewBuilder().Build(), nil)
+	cfg := config.DefaultConfig()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+
+	tests := []struct {
+		name     string
+		original int32
+		spec     dynamicscalingv1.ReplicasOverrideSpec
+		want     int32
+	}{
+		{name: "largest original at the maximum percentage", original: math.MaxInt32, spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 1000}, want: cfg.MaxReplicas},
+		{name: "largest headroom", original: math.MaxInt32, spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 100, HeadroomReplicas: math.MaxInt32}, want: cfg.MaxReplicas},
+		{name: "zero original", original: 0, spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 1000}, want: cfg.MinReplicas},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			override := &dynamicscalingv1.ReplicasOverride{ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop"}, Spec: tt.spec}
+			if got, _ := r.desiredReplicas(ctx, cfg, deployment, &deployment.Spec.Template, override, tt.original, tt.original); got != tt.want {
+				t.Errorf("desiredReplicas() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironments(t *testing.T) {
+	ctx := context.Background()
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "stack", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			ReplicasPercentage: 100,
+			Environments: &dynamicscalingv1.EnvironmentTargeting{
+				Label:  "env",
+				Values: []dynamicscalingv1.EnvironmentPercentage{{Value: "prod", ReplicasPercentage: 150}, {Value: "staging", ReplicasPercentage: 50}},
+			},
+		},
+	}
+	withSelector := override.DeepCopy()
+	withSelector.Spec.Selector = &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"app": "web"}}
+	deployment := func(labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: labels}}
+	}
+
+	r := &ReplicasOverrideReconciler{
+		Client: fakeclient.NewBuilder().Build(),
+		Config: config.NewStaticManager(&config.GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 50}),
+	}
+
+	tests := []struct {
+		name           string
+		override       *dynamicscalingv1.ReplicasOverride
+		labels         map[string]string
+		wantTargeted   bool
+		wantPercentage int32
+	}{
+		{"prod", override, map[string]string{"env": "prod"}, true, 150},
+		{"staging", override, map[string]string{"env": "staging"}, true, 50},
+		{"unlisted environment", override, map[string]string{"env": "dev"}, false, 0},
+		{"no environment label", override, map[string]string{"app": "web"}, false, 0},
+		{"selector and environment", withSelector, map[string]string{"app": "web", "env": "prod"}, true, 150},
+		{"selector without environment", withSelector, map[string]string{"app": "web"}, false, 0},
+		{"environment without selector", withSelector, map[string]string{"app": "api", "env": "prod"}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := deployment(tt.labels)
+			if got := shouldProcessDeployment(d, tt.override); got != tt.wantTargeted {
+				t.Fatalf("shouldProcessDeployment() = %v, want %v", got, tt.wantTargeted)
+			}
+			if got := selectorOverride([]dynamicscalingv1.ReplicasOverride{*tt.override}, d) != nil; got != tt.wantTargeted {
+				t.Errorf("selectorOverride() matched = %v, want %v", got, tt.wantTargeted)
+			}
+			if !tt.wantTargeted {
+				return
+			}
+			explanation := precedence.Resolve(r.precedenceRules(ctx, d, tt.override)...)
+			if explanation.Percentage != tt.wantPercentage {
+				t.Errorf("precedence percentage = %d, want %d", explanation.Percentage, tt.wantPercentage)
+			}
+		})
+	}
+}
+
+func TestSelectorOverrideOldestFirst(t *testing.T) {
+	override := func(name string, created time.Time) dynamicscalingv1.ReplicasOverride {
+		return dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", CreationTimestamp: metav1.NewTime(created)},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"app": "web"}},
+				ReplicasPercentage: 100,
+			},
+		}
+	}
+	now := time.Now()
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Labels: map[string]string{"app": "web"}}}
+
+	// The cache lists the newer override first, the older one still wins
+	overrides := []dynamicscalingv1.ReplicasOverride{override("newer", now), override("older", now.Add(-time.Hour))}
+	if got := selectorOverride(overrides, sts); got == nil || got.Name != "older" {
+		t.Errorf("selectorOverride() = %v, want the older override", got)
+	}
+}
+
+func TestBoundsOverride(t *testing.T) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-guardrail", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef:      &dynamicscalingv1.DeploymentReference{Name: "api"},
+			OverrideType:       dynamicscalingv1.OverrideTypeBounds,
+			ReplicasPercentage: 200,
+			MinReplicas:        int32Ptr(2),
+			MaxReplicas:        int32Ptr(6),
+		},
+	}
+	c := fakeclient.NewBuilder(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}, deployment, override).
+		Build()
+	r := newTestReconciler(c, nil)
+	// scale sets the replicas of the deployment as another controller would
+	// and returns them after a pass
+	scale := func(n int32) int32 {
+		t.Helper()
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			t.Fatal(err)
+		}
+		deployment.Spec.Replicas = int32Ptr(n)
+		if err := c.Update(ctx, deployment); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Reconcile(ctx, ctrl.Request{}); err != nil {
+			t.Fatalf("Reconcile() failed: %v", err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			t.Fatal(err)
+		}
+		return *deployment.Spec.Replicas
+	}
+
+	// The percentage is not applied, replicas within the bounds are left alone
+	if got := scale(3); got != 3 {
+		t.Errorf("replicas = %d, want 3 left alone", got)
+	}
+	if got := scale(10); got != 6 {
+		t.Errorf("replicas set to 10 = %d, want the ceiling 6", got)
+	}
+	if want := `{"override":"api-guardrail","trigger":"override"}`; deployment.Annotations[utils.ChangeReasonAnnotation] != want {
+		t.Errorf("%s annotation = %s, want %s", utils.ChangeReasonAnnotation, deployment.Annotations[utils.ChangeReasonAnnotation], want)
+	}
+	if got := scale(1); got != 2 {
+		t.Errorf("replicas set to 1 = %d, want the floor 2", got)
+	}
+	if got := scale(5); got != 5 {
+		t.Errorf("replicas set to 5 = %d, want 5 left alone", got)
+	}
+
+	// Deployments with an HPA are left to it
+	if targetsKind(override, metrics.TargetKindHPA) || targetsKind(override, metrics.TargetKindStatefulSet) {
+		t.Errorf("bounds override targets HPAs or StatefulSets")
+	}
+}
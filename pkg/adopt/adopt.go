@@ -0,0 +1,254 @@
+// Package adopt onboards an existing cluster: it records the current replicas
+// of its workloads as their originals and suggests the ReplicasOverrides and
+// GlobalReplicasIgnore matching how the workloads are organized today.
+package adopt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/snapshot"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// DefaultGroupLabel groups the deployments of a namespace into one suggested override
+	DefaultGroupLabel = "app.kubernetes.io/part-of"
+
+	// IgnoreRuleName is the name of the suggested GlobalReplicasIgnore
+	IgnoreRuleName = "adopted-ignores"
+
+	// ManifestsKey is the key of the suggested manifests in the ConfigMap
+	ManifestsKey = "suggested.yaml"
+)
+
+// DefaultExcludeNamespaces are the system namespaces suggested for the ignore rule
+var DefaultExcludeNamespaces = []string{"kube-system", "kube-public", "kube-node-lease", "kubedynamicscaler-system"}
+
+// Options configures a scan
+type Options struct {
+	// GroupLabel is the label whose values group deployments into suggested overrides
+	GroupLabel string
+	// ExcludeNamespaces are left alone and suggested for the ignore rule when they exist
+	ExcludeNamespaces []string
+}
+
+// Plan is the outcome of a scan
+type Plan struct {
+	// Originals are the current values of the objects without recorded originals
+	Originals []snapshot.Original
+	// IgnoreRules and Overrides are the suggested manifests
+	IgnoreRules []dynamicscalingv1.GlobalReplicasIgnore
+	Overrides   []dynamicscalingv1.ReplicasOverride
+}
+
+// Scan reads the deployments, StatefulSets and HPAs of the cluster and plans their adoption
+func Scan(ctx context.Context, c client.Reader, opts Options) (*Plan, error) {
+	if opts.GroupLabel == "" {
+		opts.GroupLabel = DefaultGroupLabel
+	}
+	excluded := make(map[string]bool, len(opts.ExcludeNamespaces))
+	for _, namespace := range opts.ExcludeNamespaces {
+		excluded[namespace] = true
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments); err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, statefulSets); err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	hpas := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := c.List(ctx, hpas); err != nil {
+		return nil, fmt.Errorf("failed to list horizontal pod autoscalers: %w", err)
+	}
+
+	plan := &Plan{}
+	ignore := dynamicscalingv1.GlobalReplicasIgnore{
+		TypeMeta:   metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "GlobalReplicasIgnore"},
+		ObjectMeta: metav1.ObjectMeta{Name: IgnoreRuleName},
+	}
+	for _, namespace := range namespaces.Items {
+		if excluded[namespace.Name] {
+			ignore.Spec.IgnoreNamespaces = append(ignore.Spec.IgnoreNamespaces, namespace.Name)
+		}
+	}
+
+	// The controller takes the min of the HPA as the original replicas of the deployment it scales
+	hpaMins := make(map[string]int32)
+	for _, hpa := range hpas.Items {
+		if excluded[hpa.Namespace] {
+			continue
+		}
+		minReplicas := int32(1)
+		if hpa.Spec.MinReplicas != nil {
+			minReplicas = *hpa.Spec.MinReplicas
+		}
+		if hpa.Spec.ScaleTargetRef.Kind == "Deployment" {
+			hpaMins[hpa.Namespace+"/"+hpa.Spec.ScaleTargetRef.Name] = minReplicas
+		}
+		if _, ok := hpa.Annotations[utils.OriginalMinReplicasAnnotation]; !ok {
+			plan.Originals = append(plan.Originals, original("autoscaling/v2", "HorizontalPodAutoscaler", hpa.ObjectMeta, map[string]string{
+				utils.OriginalMinReplicasAnnotation: strconv.FormatInt(int64(minReplicas), 10),
+				utils.OriginalMaxReplicasAnnotation: strconv.FormatInt(int64(hpa.Spec.MaxReplicas), 10),
+			}))
+		}
+	}
+
+	groups := make(map[string][]string)
+	for _, deployment := range deployments.Items {
+		if excluded[deployment.Namespace] {
+			continue
+		}
+		replicas := replicasOf(deployment.Spec.Replicas)
+		if minReplicas, ok := hpaMins[deployment.Namespace+"/"+deployment.Name]; ok {
+			replicas = minReplicas
+		} else if replicas == 0 {
+			// Parked workloads stay parked
+			ignore.Spec.IgnoreResources = append(ignore.Spec.IgnoreResources, ignoredResource("Deployment", deployment.ObjectMeta))
+			continue
+		}
+		if _, ok := deployment.Annotations[utils.OriginalReplicasAnnotation]; !ok {
+			plan.Originals = append(plan.Originals, original("apps/v1", "Deployment", deployment.ObjectMeta, map[string]string{
+				utils.OriginalReplicasAnnotation: strconv.FormatInt(int64(replicas), 10),
+			}))
+		}
+		if group := deployment.Labels[opts.GroupLabel]; group != "" {
+			key := deployment.Namespace + "/" + group
+			groups[key] = append(groups[key], deployment.Name)
+		}
+	}
+
+	for _, sts := range statefulSets.Items {
+		if excluded[sts.Namespace] {
+			continue
+		}
+		replicas := replicasOf(sts.Spec.Replicas)
+		if replicas == 0 {
+			ignore.Spec.IgnoreResources = append(ignore.Spec.IgnoreResources, ignoredResource("StatefulSet", sts.ObjectMeta))
+			continue
+		}
+		if _, ok := sts.Annotations[utils.OriginalReplicasAnnotation]; !ok {
+			plan.Originals = append(plan.Originals, original("apps/v1", "StatefulSet", sts.ObjectMeta, map[string]string{
+				utils.OriginalReplicasAnnotation: strconv.FormatInt(int64(replicas), 10),
+			}))
+		}
+	}
+
+	if len(ignore.Spec.IgnoreNamespaces) > 0 || len(ignore.Spec.IgnoreResources) > 0 {
+		plan.IgnoreRules = append(plan.IgnoreRules, ignore)
+	}
+	plan.Overrides = suggestOverrides(groups, opts.GroupLabel)
+	return plan, nil
+}
+
+// suggestOverrides returns one neutral override per group of deployments,
+// paused so that applying it changes nothing until it is reviewed
+func suggestOverrides(groups map[string][]string, label string) []dynamicscalingv1.ReplicasOverride {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var overrides []dynamicscalingv1.ReplicasOverride
+	for _, key := range keys {
+		namespace, group, _ := strings.Cut(key, "/")
+		members := groups[key]
+		sort.Strings(members)
+		overrides = append(overrides, dynamicscalingv1.ReplicasOverride{
+			TypeMeta: metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "ReplicasOverride"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      group,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					utils.PausedAnnotation:           "true",
+					utils.AdoptedWorkloadsAnnotation: strings.Join(members, ","),
+				},
+			},
+			Spec: dynamicscalingv1.ReplicasOverrideSpec{
+				Selector:           &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{label: group}},
+				OverrideType:       "override",
+				ReplicasPercentage: 100,
+			},
+		})
+	}
+	return overrides
+}
+
+// Record writes the planned originals on the objects, keeping the values
+// recorded since the scan
+func Record(ctx context.Context, c client.Client, plan *Plan, dryRun bool) (*snapshot.Result, error) {
+	s := &snapshot.Snapshot{APIVersion: snapshot.APIVersion, Kind: snapshot.Kind, Originals: plan.Originals}
+	return snapshot.Import(ctx, c, s, snapshot.ImportOptions{DryRun: dryRun})
+}
+
+// Manifests returns the suggested ignore rules and overrides as a multi-document YAML manifest
+func (p *Plan) Manifests() ([]byte, error) {
+	var objects []any
+	for i := range p.IgnoreRules {
+		objects = append(objects, &p.IgnoreRules[i])
+	}
+	for i := range p.Overrides {
+		objects = append(objects, &p.Overrides[i])
+	}
+
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// ConfigMap returns the suggested manifests in a ConfigMap, for clusters
+// reviewed without direct access
+func (p *Plan) ConfigMap(name, namespace string) (*corev1.ConfigMap, error) {
+	manifests, err := p.Manifests()
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string]string{ManifestsKey: string(manifests)},
+	}, nil
+}
+
+func original(apiVersion, kind string, meta metav1.ObjectMeta, annotations map[string]string) snapshot.Original {
+	return snapshot.Original{APIVersion: apiVersion, Kind: kind, Namespace: meta.Namespace, Name: meta.Name, Annotations: annotations}
+}
+
+func ignoredResource(kind string, meta metav1.ObjectMeta) dynamicscalingv1.IgnoredResource {
+	return dynamicscalingv1.IgnoredResource{Kind: kind, Name: meta.Name, Namespace: meta.Namespace}
+}
+
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
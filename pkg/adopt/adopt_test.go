@@ -0,0 +1,102 @@
+package adopt
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func deployment(namespace, name string, replicas int32, labels, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Annotations: annotations},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+func TestScanAndRecord(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = dynamicscalingv1.AddToScheme(scheme)
+	minReplicas := int32(3)
+	shop := map[string]string{DefaultGroupLabel: "shop"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		deployment("kube-system", "coredns", 2, nil, nil),
+		deployment("shop", "web", 4, shop, nil),
+		deployment("shop", "api", 2, shop, nil),
+		deployment("shop", "cart", 6, shop, map[string]string{utils.OriginalReplicasAnnotation: "5"}),
+		deployment("shop", "legacy", 0, nil, nil),
+		&autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "api"},
+				MinReplicas:    &minReplicas,
+				MaxReplicas:    10,
+			},
+		},
+	).Build()
+
+	plan, err := Scan(ctx, c, Options{ExcludeNamespaces: DefaultExcludeNamespaces})
+	if err != nil {
+		t.Fatalf("Scan() failed: %v", err)
+	}
+
+	originals := make(map[string]map[string]string)
+	for _, o := range plan.Originals {
+		originals[o.Kind+"/"+o.Name] = o.Annotations
+	}
+	if len(originals) != 3 || originals["Deployment/web"][utils.OriginalReplicasAnnotation] != "4" ||
+		originals["Deployment/api"][utils.OriginalReplicasAnnotation] != "3" ||
+		originals["HorizontalPodAutoscaler/api"][utils.OriginalMaxReplicasAnnotation] != "10" {
+		t.Errorf("Scan() originals = %v, want web, api from its HPA min and the HPA", originals)
+	}
+
+	if len(plan.IgnoreRules) != 1 {
+		t.Fatalf("Scan() suggested %d ignore rules, want 1", len(plan.IgnoreRules))
+	}
+	ignore := plan.IgnoreRules[0].Spec
+	if len(ignore.IgnoreNamespaces) != 1 || ignore.IgnoreNamespaces[0] != "kube-system" ||
+		len(ignore.IgnoreResources) != 1 || ignore.IgnoreResources[0].Name != "legacy" {
+		t.Errorf("Scan() ignore rule = %+v, want kube-system and the parked deployment", ignore)
+	}
+
+	if len(plan.Overrides) != 1 {
+		t.Fatalf("Scan() suggested %d overrides, want 1", len(plan.Overrides))
+	}
+	override := plan.Overrides[0]
+	if override.Name != "shop" || override.Annotations[utils.PausedAnnotation] != "true" ||
+		override.Annotations[utils.AdoptedWorkloadsAnnotation] != "api,cart,web" || override.Spec.ReplicasPercentage != 100 {
+		t.Errorf("Scan() override = %+v, want a paused neutral override of the shop group", override.ObjectMeta)
+	}
+
+	manifests, err := plan.Manifests()
+	if err != nil {
+		t.Fatalf("Manifests() failed: %v", err)
+	}
+	if strings.Count(string(manifests), "---\n") != 1 || !strings.Contains(string(manifests), "kind: GlobalReplicasIgnore") {
+		t.Errorf("Manifests() = %s", manifests)
+	}
+
+	result, err := Record(ctx, c, plan, false)
+	if err != nil || len(result.Errors) != 0 || result.Updated != 3 {
+		t.Fatalf("Record() = (%+v, %v), want 3 objects updated", result, err)
+	}
+	var web appsv1.Deployment
+	if err := c.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, &web); err != nil || web.Annotations[utils.OriginalReplicasAnnotation] != "4" {
+		t.Errorf("web annotations = %v, want the current replicas recorded", web.Annotations)
+	}
+}
@@ -0,0 +1,137 @@
+// Package authorizer submits the replica changes decided by the controller to
+// an external decision webhook before they are written, so organizations can
+// enforce custom guardrails, e.g. change windows or capacity quotas, without
+// changing the controller.
+package authorizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// maxResponseBytes bounds the size of a webhook response
+const maxResponseBytes = 1 << 20
+
+// Request is the replica change submitted to the webhook
+type Request struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Override is the override deciding the change, "global" for the global config
+	Override string `json:"override"`
+	// Trigger is what produced the change, e.g. override, schedule or carbon
+	Trigger string `json:"trigger"`
+	// CurrentReplicas and ProposedReplicas are the replicas, or the HPA
+	// minReplicas, now and after the change
+	CurrentReplicas  int32 `json:"currentReplicas"`
+	ProposedReplicas int32 `json:"proposedReplicas"`
+}
+
+// Response is the verdict of the webhook
+type Response struct {
+	// Allowed applies the change, with Replicas instead of the proposed ones if set
+	Allowed  bool   `json:"allowed"`
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Reason explains a denial or modification
+	Reason string `json:"reason,omitempty"`
+}
+
+// Verdicts of a decision
+const (
+	VerdictAllowed  = "allowed"
+	VerdictDenied   = "denied"
+	VerdictModified = "modified"
+	VerdictError    = "error"
+)
+
+// Decision is the outcome of submitting a change
+type Decision struct {
+	// Verdict is allowed, denied, modified or error
+	Verdict string
+	// Allowed is true if the change may be written
+	Allowed bool
+	// Replicas are the replicas to apply when the change is allowed
+	Replicas int32
+	// Reason is the reason given by the webhook, or the error calling it
+	Reason string
+}
+
+// Authorizer calls the decision webhook of the global config. A nil
+// Authorizer, or one whose config sets no URL, allows every change.
+type Authorizer struct {
+	http *http.Client
+}
+
+// New creates an authorizer
+func New() *Authorizer {
+	return &Authorizer{http: &http.Client{}}
+}
+
+// Authorize submits req to the webhook of cfg and returns its decision.
+// When the webhook fails, the change is allowed or denied by the failure
+// policy of cfg, with the error as reason and the error verdict.
+func (a *Authorizer) Authorize(ctx context.Context, cfg config.DecisionWebhookConfig, req Request) Decision {
+	if a == nil || !cfg.Enabled() {
+		return Decision{Verdict: VerdictAllowed, Allowed: true, Replicas: req.ProposedReplicas}
+	}
+
+	resp, err := a.call(ctx, cfg, req)
+	if err != nil {
+		// Unknown failure policies fail closed like the default
+		ignore := cfg.FailurePolicy == config.DecisionFailurePolicyIgnore
+		return Decision{Verdict: VerdictError, Allowed: ignore, Replicas: req.ProposedReplicas, Reason: err.Error()}
+	}
+
+	switch {
+	case !resp.Allowed:
+		return Decision{Verdict: VerdictDenied, Replicas: req.CurrentReplicas, Reason: resp.Reason}
+	case resp.Replicas != nil && *resp.Replicas != req.ProposedReplicas:
+		return Decision{Verdict: VerdictModified, Allowed: true, Replicas: *resp.Replicas, Reason: resp.Reason}
+	default:
+		return Decision{Verdict: VerdictAllowed, Allowed: true, Replicas: req.ProposedReplicas, Reason: resp.Reason}
+	}
+}
+
+// call posts req to the webhook and decodes its response
+func (a *Authorizer) call(ctx context.Context, cfg config.DecisionWebhookConfig, req Request) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.GetTimeout())
+	defer cancel()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if token := config.Credential(cfg.TokenSecretRef, cfg.TokenEnv); token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := a.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call decision webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("decision webhook returned %s", resp.Status)
+	}
+
+	verdict := &Response{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode decision webhook response: %w", err)
+	}
+	if verdict.Replicas != nil && *verdict.Replicas < 0 {
+		return nil, fmt.Errorf("decision webhook returned negative replicas %d", *verdict.Replicas)
+	}
+	return verdict, nil
+}
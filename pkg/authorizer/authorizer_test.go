@@ -0,0 +1,90 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestAuthorize(t *testing.T) {
+	// The response of the webhook, read by the server while a timed out call still runs
+	var verdict atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req := Request{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name != "api" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := verdict.Load().(string)
+		switch response {
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+	t.Setenv("DECISION_TOKEN", "secret")
+
+	req := Request{Kind: "Deployment", Namespace: "shop", Name: "api", Override: "sale", Trigger: "override", CurrentReplicas: 3, ProposedReplicas: 6}
+	tests := []struct {
+		name          string
+		response      string
+		failurePolicy string
+		want          Decision
+	}{
+		{name: "allowed", response: `{"allowed": true}`, want: Decision{Verdict: VerdictAllowed, Allowed: true, Replicas: 6}},
+		{name: "denied", response: `{"allowed": false, "reason": "change freeze"}`, want: Decision{Verdict: VerdictDenied, Replicas: 3, Reason: "change freeze"}},
+		{name: "modified", response: `{"allowed": true, "replicas": 5, "reason": "quota"}`, want: Decision{Verdict: VerdictModified, Allowed: true, Replicas: 5, Reason: "quota"}},
+		{name: "same replicas are allowed", response: `{"allowed": true, "replicas": 6}`, want: Decision{Verdict: VerdictAllowed, Allowed: true, Replicas: 6}},
+		{name: "failure fails closed", response: "broken", want: Decision{Verdict: VerdictError, Replicas: 6}},
+		{name: "failure ignored", response: "broken", failurePolicy: config.DecisionFailurePolicyIgnore, want: Decision{Verdict: VerdictError, Allowed: true, Replicas: 6}},
+		{name: "timeout fails closed", response: "slow", want: Decision{Verdict: VerdictError, Replicas: 6}},
+		{name: "negative replicas are an error", response: `{"allowed": true, "replicas": -1}`, want: Decision{Verdict: VerdictError, Replicas: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict.Store(tt.response)
+			cfg := config.DecisionWebhookConfig{
+				URL:           server.URL,
+				TokenEnv:      "DECISION_TOKEN",
+				Timeout:       50 * time.Millisecond,
+				FailurePolicy: tt.failurePolicy,
+			}
+			got := New().Authorize(context.Background(), cfg, req)
+			if tt.want.Verdict == VerdictError {
+				// The reason is the error calling the webhook
+				got.Reason = ""
+			}
+			if got != tt.want {
+				t.Errorf("Authorize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeDisabled(t *testing.T) {
+	req := Request{CurrentReplicas: 3, ProposedReplicas: 6}
+	want := Decision{Verdict: VerdictAllowed, Allowed: true, Replicas: 6}
+	if got := New().Authorize(context.Background(), config.DecisionWebhookConfig{}, req); got != want {
+		t.Errorf("Authorize() without URL = %+v, want %+v", got, want)
+	}
+	var a *Authorizer
+	if got := a.Authorize(context.Background(), config.DecisionWebhookConfig{URL: "http://127.0.0.1:1"}, req); got != want {
+		t.Errorf("nil Authorize() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package blackout
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// ConditionType is the ReplicasOverride condition reporting an active blackout window
+const ConditionType = "Blackout"
+
+// parser accepts standard five-field cron expressions and descriptors such as @daily
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Active returns the first window containing now and the time it ends.
+// Invalid windows are reported as an error and never block scaling.
+func Active(windows []dynamicscalingv1.BlackoutWindow, now time.Time) (*dynamicscalingv1.BlackoutWindow, time.Time, error) {
+	var errs []error
+	for i := range windows {
+		window := &windows[i]
+		end, active, err := activeUntil(window, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if active {
+			return window, end, nil
+		}
+	}
+	if len(errs) > 0 {
+		return nil, time.Time{}, fmt.Errorf("invalid blackout windows: %v", errs)
+	}
+	return nil, time.Time{}, nil
+}
+
+// activeUntil returns whether window contains now and when it ends
+func activeUntil(window *dynamicscalingv1.BlackoutWindow, now time.Time) (time.Time, bool, error) {
+	if window.Schedule == "" {
+		if window.Start == nil || window.End == nil {
+			return time.Time{}, false, fmt.Errorf("window %q: either schedule and duration or start and end are required", window.Name)
+		}
+		active := !now.Before(window.Start.Time) && now.Before(window.End.Time)
+		return window.End.Time, active, nil
+	}
+
+	if window.Duration == nil || window.Duration.Duration <= 0 {
+		return time.Time{}, false, fmt.Errorf("window %q: a positive duration is required with schedule", window.Name)
+	}
+	schedule, location, err := parseSchedule(window)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// The latest start within the last duration, if any, opens the window
+	duration := window.Duration.Duration
+	start := schedule.Next(now.In(location).Add(-duration))
+	if start.After(now) {
+		return time.Time{}, false, nil
+	}
+	return start.Add(duration), true, nil
+}
+
+// NextStart returns the earliest time after now one of the windows opens,
+// zero if none opens again. Invalid windows are skipped, Active reports them.
+func NextStart(windows []dynamicscalingv1.BlackoutWindow, now time.Time) time.Time {
+	var next time.Time
+	for i := range windows {
+		window := &windows[i]
+		var start time.Time
+		if window.Schedule == "" {
+			if window.Start == nil || !window.Start.After(now) {
+				continue
+			}
+			start = window.Start.Time
+		} else {
+			schedule, location, err := parseSchedule(window)
+			if err != nil {
+				continue
+			}
+			start = schedule.Next(now.In(location))
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next
+}
+
+// parseSchedule parses the cron schedule of window and loads its time zone
+func parseSchedule(window *dynamicscalingv1.BlackoutWindow) (cron.Schedule, *time.Location, error) {
+	schedule, err := parser.Parse(window.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("window %q: %w", window.Name, err)
+	}
+	location := time.UTC
+	if window.TimeZone != "" {
+		if location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("window %q: %w", window.Name, err)
+		}
+	}
+	return schedule, location, nil
+}
@@ -0,0 +1,112 @@
+package blackout
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestActive(t *testing.T) {
+	// Friday 2025-01-03
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 3, hour, minute, 0, 0, time.UTC)
+	}
+	weekendFreeze := dynamicscalingv1.BlackoutWindow{
+		Name:     "weekend-freeze",
+		Schedule: "0 18 * * 5",
+		Duration: &metav1.Duration{Duration: 62 * time.Hour},
+	}
+	release := dynamicscalingv1.BlackoutWindow{
+		Name:  "release",
+		Start: &metav1.Time{Time: friday(10, 0)},
+		End:   &metav1.Time{Time: friday(12, 0)},
+	}
+
+	tests := []struct {
+		name      string
+		windows   []dynamicscalingv1.BlackoutWindow
+		now       time.Time
+		wantName  string
+		wantUntil time.Time
+		wantErr   bool
+	}{
+		{name: "before recurring window", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze}, now: friday(17, 59)},
+		{name: "at recurring window start", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze}, now: friday(18, 0), wantName: "weekend-freeze", wantUntil: friday(18, 0).Add(62 * time.Hour)},
+		{name: "inside recurring window", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze}, now: friday(18, 0).Add(48 * time.Hour), wantName: "weekend-freeze", wantUntil: friday(18, 0).Add(62 * time.Hour)},
+		{name: "after recurring window", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze}, now: friday(18, 0).Add(62 * time.Hour)},
+		{name: "inside one-off window", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze, release}, now: friday(11, 0), wantName: "release", wantUntil: friday(12, 0)},
+		{name: "end of one-off window is exclusive", windows: []dynamicscalingv1.BlackoutWindow{release}, now: friday(12, 0)},
+		{name: "invalid window is reported", windows: []dynamicscalingv1.BlackoutWindow{{Name: "broken", Schedule: "not a cron"}}, now: friday(12, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, until, err := Active(tt.windows, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Active() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			name := ""
+			if window != nil {
+				name = window.Name
+			}
+			if name != tt.wantName || !until.Equal(tt.wantUntil) {
+				t.Errorf("Active() = (%q, %v), want (%q, %v)", name, until, tt.wantName, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestActiveHonorsTimeZone(t *testing.T) {
+	window := dynamicscalingv1.BlackoutWindow{
+		Name:     "nightly",
+		Schedule: "0 22 * * *",
+		Duration: &metav1.Duration{Duration: 2 * time.Hour},
+		TimeZone: "America/New_York",
+	}
+	// 22:30 in New York is 03:30 UTC the next day in winter
+	now := time.Date(2025, 1, 4, 3, 30, 0, 0, time.UTC)
+	if active, _, err := Active([]dynamicscalingv1.BlackoutWindow{window}, now); err != nil || active == nil {
+		t.Errorf("Active() = (%v, %v), want window active", active, err)
+	}
+}
+
+func TestNextStart(t *testing.T) {
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 3, hour, minute, 0, 0, time.UTC)
+	}
+	weekendFreeze := dynamicscalingv1.BlackoutWindow{
+		Name:     "weekend-freeze",
+		Schedule: "0 18 * * 5",
+		Duration: &metav1.Duration{Duration: 62 * time.Hour},
+	}
+	release := dynamicscalingv1.BlackoutWindow{
+		Name:  "release",
+		Start: &metav1.Time{Time: friday(10, 0)},
+		End:   &metav1.Time{Time: friday(12, 0)},
+	}
+	broken := dynamicscalingv1.BlackoutWindow{Name: "broken", Schedule: "not a cron"}
+
+	tests := []struct {
+		name    string
+		windows []dynamicscalingv1.BlackoutWindow
+		now     time.Time
+		want    time.Time
+	}{
+		{name: "earliest start", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze, release}, now: friday(9, 0), want: friday(10, 0)},
+		{name: "one-off window started", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze, release}, now: friday(11, 0), want: friday(18, 0)},
+		{name: "next recurrence", windows: []dynamicscalingv1.BlackoutWindow{weekendFreeze}, now: friday(18, 0), want: friday(18, 0).Add(7 * 24 * time.Hour)},
+		{name: "invalid window is skipped", windows: []dynamicscalingv1.BlackoutWindow{broken, release}, now: friday(9, 0), want: friday(10, 0)},
+		{name: "nothing opens again", windows: []dynamicscalingv1.BlackoutWindow{release}, now: friday(11, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextStart(tt.windows, tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package carbon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestReduce(t *testing.T) {
+	cfg := config.CarbonAwareConfig{
+		HighIntensityThreshold: 400,
+		ReductionPercentage:    30,
+		MinPercentage:          60,
+	}
+
+	tests := []struct {
+		name        string
+		intensity   float64
+		percentage  int32
+		want        int32
+		wantLowered bool
+	}{
+		{name: "below threshold keeps percentage", intensity: 350, percentage: 100, want: 100},
+		{name: "above threshold lowers percentage", intensity: 450, percentage: 200, want: 140, wantLowered: true},
+		{name: "reduction is bounded by min percentage", intensity: 450, percentage: 80, want: 60, wantLowered: true},
+		{name: "never raises a percentage below the floor", intensity: 450, percentage: 50, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lowered := Reduce(cfg, tt.intensity, tt.percentage)
+			if got != tt.want || lowered != tt.wantLowered {
+				t.Errorf("Reduce() = (%d, %v), want (%d, %v)", got, lowered, tt.want, tt.wantLowered)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderReadsNestedField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"value": 512.5}}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("CARBON_TOKEN", "secret")
+	provider, err := NewProvider(config.CarbonAwareConfig{
+		Provider: ProviderHTTP,
+		URL:      server.URL,
+		Field:    "data.value",
+		TokenEnv: "CARBON_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	intensity, err := provider.Intensity(context.Background())
+	if err != nil {
+		t.Fatalf("Intensity() failed: %v", err)
+	}
+	if intensity != 512.5 {
+		t.Errorf("Intensity() = %v, want 512.5", intensity)
+	}
+}
+
+func TestNewProviderValidatesConfig(t *testing.T) {
+	if _, err := NewProvider(config.CarbonAwareConfig{Provider: ProviderElectricityMaps}); err == nil {
+		t.Error("expected an error when the zone is missing")
+	}
+	if _, err := NewProvider(config.CarbonAwareConfig{Provider: "unknown"}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
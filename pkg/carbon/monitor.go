@@ -0,0 +1,121 @@
+package carbon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// Monitor polls the configured carbon intensity provider and lowers the
+// percentage of flexible workloads while the grid intensity is high.
+// It implements manager.Runnable; a nil Monitor never adjusts percentages.
+type Monitor struct {
+	config *config.Manager
+
+	mutex     sync.RWMutex
+	intensity float64
+	updated   time.Time
+	now       func() time.Time
+}
+
+// NewMonitor creates a monitor reading its settings from the config manager
+func NewMonitor(configManager *config.Manager) *Monitor {
+	return &Monitor{
+		config: configManager,
+		now:    time.Now,
+	}
+}
+
+// Start polls the provider until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("carbon.Monitor")
+
+	var lastPoll time.Time
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		cfg := m.config.GetConfig().CarbonAware
+		if cfg.Enabled && m.now().Sub(lastPoll) >= cfg.GetRefreshInterval() {
+			lastPoll = m.now()
+			if err := m.poll(ctx, cfg); err != nil {
+				log.Error(err, "Failed to refresh carbon intensity", "provider", cfg.Provider)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica keeps its own reading
+func (m *Monitor) NeedLeaderElection() bool {
+	return false
+}
+
+// poll fetches the current intensity from the provider
+func (m *Monitor) poll(ctx context.Context, cfg config.CarbonAwareConfig) error {
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return err
+	}
+	intensity, err := provider.Intensity(ctx)
+	if err != nil {
+		return err
+	}
+	m.SetIntensity(intensity)
+	log.FromContext(ctx).V(1).Info("Refreshed carbon intensity", "intensity", intensity)
+	return nil
+}
+
+// SetIntensity records the current intensity
+func (m *Monitor) SetIntensity(intensity float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.intensity = intensity
+	m.updated = m.now()
+}
+
+// AdjustPercentage returns the percentage to apply to a workload with the given
+// labels and whether it was lowered because of high carbon intensity
+func (m *Monitor) AdjustPercentage(labels map[string]string, percentage int32) (int32, bool) {
+	if m == nil {
+		return percentage, false
+	}
+	cfg := m.config.GetConfig().CarbonAware
+	if !cfg.Enabled || labels[cfg.GetFlexibleLabel()] != "true" {
+		return percentage, false
+	}
+
+	m.mutex.RLock()
+	intensity, updated := m.intensity, m.updated
+	m.mutex.RUnlock()
+
+	// Ignore readings that are missing or too old to be trusted
+	if updated.IsZero() || m.now().Sub(updated) > 3*cfg.GetRefreshInterval() {
+		return percentage, false
+	}
+	return Reduce(cfg, intensity, percentage)
+}
+
+// Reduce applies the carbon-aware reduction to percentage for the given intensity.
+// The result never goes below MinPercentage and never raises the percentage.
+func Reduce(cfg config.CarbonAwareConfig, intensity float64, percentage int32) (int32, bool) {
+	if intensity < cfg.HighIntensityThreshold || cfg.ReductionPercentage <= 0 {
+		return percentage, false
+	}
+	reduced := int32(int64(percentage) * int64(100-cfg.ReductionPercentage) / 100)
+	if reduced < cfg.MinPercentage {
+		reduced = cfg.MinPercentage
+	}
+	if reduced >= percentage {
+		return percentage, false
+	}
+	return reduced, true
+}
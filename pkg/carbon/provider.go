@@ -0,0 +1,145 @@
+package carbon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// ProviderElectricityMaps reads the latest intensity of a zone from the Electricity Maps API
+	ProviderElectricityMaps = "electricitymaps"
+	// ProviderHTTP reads a numeric field from an arbitrary JSON endpoint (e.g. a WattTime proxy)
+	ProviderHTTP = "http"
+	// ProviderStatic always reports the configured static intensity, useful for testing policies
+	ProviderStatic = "static"
+
+	// electricityMapsURL is the default Electricity Maps endpoint
+	electricityMapsURL = "https://api.electricitymap.org/v3/carbon-intensity/latest"
+
+	// providerTimeout bounds a single intensity request
+	providerTimeout = 10 * time.Second
+)
+
+// Provider returns the current grid carbon intensity in gCO2eq/kWh
+type Provider interface {
+	Intensity(ctx context.Context) (float64, error)
+}
+
+// NewProvider creates the provider selected by cfg
+func NewProvider(cfg config.CarbonAwareConfig) (Provider, error) {
+	client := &http.Client{Timeout: providerTimeout}
+	token := config.Credential(cfg.TokenSecretRef, cfg.TokenEnv)
+
+	switch cfg.Provider {
+	case ProviderElectricityMaps:
+		if cfg.Zone == "" {
+			return nil, fmt.Errorf("carbon-aware provider %s requires a zone", cfg.Provider)
+		}
+		endpoint := cfg.URL
+		if endpoint == "" {
+			endpoint = electricityMapsURL
+		}
+		return &httpProvider{
+			client: client,
+			url:    endpoint + "?zone=" + url.QueryEscape(cfg.Zone),
+			header: "auth-token",
+			token:  token,
+			field:  "carbonIntensity",
+		}, nil
+	case ProviderHTTP:
+		if cfg.URL == "" || cfg.Field == "" {
+			return nil, fmt.Errorf("carbon-aware provider %s requires url and field", cfg.Provider)
+		}
+		return &httpProvider{
+			client: client,
+			url:    cfg.URL,
+			header: "Authorization",
+			token:  bearer(token),
+			field:  cfg.Field,
+		}, nil
+	case ProviderStatic:
+		return staticProvider(cfg.StaticIntensity), nil
+	default:
+		return nil, fmt.Errorf("unknown carbon-aware provider %q", cfg.Provider)
+	}
+}
+
+// staticProvider reports a fixed intensity
+type staticProvider float64
+
+// Intensity implements Provider
+func (p staticProvider) Intensity(context.Context) (float64, error) {
+	return float64(p), nil
+}
+
+// httpProvider reads a numeric field from a JSON document
+type httpProvider struct {
+	client *http.Client
+	url    string
+	header string
+	token  string
+	field  string
+}
+
+// Intensity implements Provider
+func (p *httpProvider) Intensity(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	if p.token != "" {
+		req.Header.Set(p.header, p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query carbon intensity: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, fmt.Errorf("carbon intensity API returned %s", resp.Status)
+	}
+
+	var document map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return 0, fmt.Errorf("failed to decode carbon intensity response: %w", err)
+	}
+	return lookupNumber(document, p.field)
+}
+
+// lookupNumber resolves a dot-separated field path to a number
+func lookupNumber(document map[string]interface{}, path string) (float64, error) {
+	var current interface{} = document
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return 0, fmt.Errorf("field %q not found in carbon intensity response", path)
+		}
+		current = object[key]
+	}
+	switch value := current.(type) {
+	case float64:
+		return value, nil
+	case string:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return 0, fmt.Errorf("field %q is not a number", path)
+	}
+}
+
+func bearer(token string) string {
+	if token == "" {
+		return ""
+	}
+	return "Bearer " + token
+}
@@ -0,0 +1,315 @@
+// Package chaos injects artificial conflicts, API errors and slow responses
+// into the requests of the controller, so its retries, failure reasons and
+// partial failure statuses can be exercised in CI and staging. It is only
+// wired when the controller runs with --enable-fault-injection.
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Path is the path of the fault injection endpoint, served by the metrics
+// server behind the same authentication and authorization
+const Path = "/fault-injection"
+
+// maxRequestBytes bounds the size of the faults submitted to the endpoint
+const maxRequestBytes = 1 << 16
+
+// Kinds of faults, counted in Status
+const (
+	FaultConflict = "conflict"
+	FaultError    = "error"
+	FaultSlow     = "slow"
+)
+
+// Faults configures the faults injected into the requests of the controller.
+// The zero value injects nothing.
+type Faults struct {
+	// ConflictRate is the fraction of updates and patches failing with 409 Conflict
+	ConflictRate float64 `json:"conflictRate,omitempty"`
+
+	// ErrorRate is the fraction of requests failing with 503 Service Unavailable
+	ErrorRate float64 `json:"errorRate,omitempty"`
+
+	// SlowRate is the fraction of requests delayed by Latency
+	SlowRate float64         `json:"slowRate,omitempty"`
+	Latency  metav1.Duration `json:"latency,omitempty"`
+
+	// Kinds and Namespaces restrict the faults to the objects of these kinds,
+	// e.g. Deployment, and namespaces, all when empty
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Seed seeds the random source when set, so a CI run injects the same faults
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// validate checks the rates are fractions and slow requests have a latency
+func (f *Faults) validate() error {
+	for name, rate := range map[string]float64{"conflictRate": f.ConflictRate, "errorRate": f.ErrorRate, "slowRate": f.SlowRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s %v must be between 0 and 1", name, rate)
+		}
+	}
+	if f.Latency.Duration < 0 {
+		return fmt.Errorf("latency %v must not be negative", f.Latency.Duration)
+	}
+	if f.SlowRate > 0 && f.Latency.Duration == 0 {
+		return errors.New("slowRate requires a latency")
+	}
+	return nil
+}
+
+// Status is the faults configured and the number injected of each kind
+type Status struct {
+	Faults   Faults           `json:"faults"`
+	Injected map[string]int64 `json:"injected"`
+}
+
+// Injector decides which requests fail or are delayed. It is safe for
+// concurrent use, the faults being replaced while the controller runs.
+type Injector struct {
+	mutex    sync.Mutex
+	faults   Faults
+	random   *rand.Rand
+	injected map[string]int64
+}
+
+// NewInjector returns an injector injecting nothing until faults are set
+func NewInjector() *Injector {
+	return &Injector{
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		injected: make(map[string]int64),
+	}
+}
+
+// Set replaces the faults injected and resets the counts
+func (i *Injector) Set(faults Faults) error {
+	if err := faults.validate(); err != nil {
+		return err
+	}
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.faults = faults
+	i.injected = make(map[string]int64)
+	if faults.Seed != 0 {
+		i.random = rand.New(rand.NewSource(faults.Seed))
+	}
+	return nil
+}
+
+// Status returns the faults configured and the number injected of each kind
+func (i *Injector) Status() Status {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	injected := make(map[string]int64, len(i.injected))
+	for fault, count := range i.injected {
+		injected[fault] = count
+	}
+	return Status{Faults: i.faults, Injected: injected}
+}
+
+// decide returns the error a request of verb on an object of kind in
+// namespace fails with, if any, and how long to delay it
+func (i *Injector) decide(verb, kind, namespace, name string) (time.Duration, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	f := &i.faults
+	if len(f.Kinds) > 0 && !slices.Contains(f.Kinds, kind) {
+		return 0, nil
+	}
+	if len(f.Namespaces) > 0 && !slices.Contains(f.Namespaces, namespace) {
+		return 0, nil
+	}
+
+	var delay time.Duration
+	if f.SlowRate > 0 && i.random.Float64() < f.SlowRate {
+		delay = f.Latency.Duration
+		i.injected[FaultSlow]++
+	}
+	if f.ErrorRate > 0 && i.random.Float64() < f.ErrorRate {
+		i.injected[FaultError]++
+		return delay, apierrors.NewServiceUnavailable(fmt.Sprintf("injected fault: %s %s %s/%s", verb, kind, namespace, name))
+	}
+	if (verb == "update" || verb == "patch") && f.ConflictRate > 0 && i.random.Float64() < f.ConflictRate {
+		i.injected[FaultConflict]++
+		resource := schema.GroupResource{Resource: strings.ToLower(kind)}
+		return delay, apierrors.NewConflict(resource, name, errors.New("injected fault"))
+	}
+	return delay, nil
+}
+
+// inject delays the request then returns its injected error, if any
+func (i *Injector) inject(ctx context.Context, verb, kind, namespace, name string) error {
+	delay, err := i.decide(verb, kind, namespace, name)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// Handler serves the status of the injector on GET requests, and replaces
+// its faults with the JSON Faults of PUT requests
+func (i *Injector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var faults Faults
+			decoder := json.NewDecoder(io.LimitReader(req.Body, maxRequestBytes))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&faults); err != nil {
+				http.Error(w, fmt.Sprintf("invalid faults: %v", err), http.StatusBadRequest)
+				return
+			}
+			if err := i.Set(faults); err != nil {
+				http.Error(w, fmt.Sprintf("invalid faults: %v", err), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "faults are read with GET and replaced with PUT", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(i.Status())
+	})
+}
+
+// faultyClient injects the faults of its injector before each request
+type faultyClient struct {
+	client.Client
+	injector *Injector
+}
+
+// NewClient returns a client injecting the faults of injector into the
+// requests of c, status and scale writes included
+func NewClient(c client.Client, injector *Injector) client.Client {
+	return &faultyClient{Client: c, injector: injector}
+}
+
+// kind returns the kind of obj, or of the items of a list
+func (c *faultyClient) kind(obj runtime.Object) string {
+	return strings.TrimSuffix(objectKind(c.Client, obj), "List")
+}
+
+// Get implements client.Client
+func (c *faultyClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.injector.inject(ctx, "get", c.kind(obj), key.Namespace, key.Name); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Client
+func (c *faultyClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := (&client.ListOptions{}).ApplyOptions(opts)
+	if err := c.injector.inject(ctx, "list", c.kind(list), listOptions.Namespace, ""); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// Create implements client.Client
+func (c *faultyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.injector.inject(ctx, "create", c.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// Update implements client.Client
+func (c *faultyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.injector.inject(ctx, "update", c.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.Client
+func (c *faultyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.injector.inject(ctx, "patch", c.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete implements client.Client
+func (c *faultyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.injector.inject(ctx, "delete", c.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+// Status implements client.Client
+func (c *faultyClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+// SubResource implements client.Client
+func (c *faultyClient) SubResource(subResource string) client.SubResourceClient {
+	return &faultySubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c}
+}
+
+// faultySubResourceClient injects the faults of its client into the writes
+// of a subresource
+type faultySubResourceClient struct {
+	client.SubResourceClient
+	client *faultyClient
+}
+
+// Create implements client.SubResourceWriter
+func (c *faultySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := c.client.injector.inject(ctx, "create", c.client.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+// Update implements client.SubResourceWriter
+func (c *faultySubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := c.client.injector.inject(ctx, "update", c.client.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.SubResourceWriter
+func (c *faultySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := c.client.injector.inject(ctx, "patch", c.client.kind(obj), obj.GetNamespace(), obj.GetName()); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
+
+// objectKind returns the kind of obj from the scheme of c, empty if unknown
+func objectKind(c client.Client, obj runtime.Object) string {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return ""
+	}
+	return gvk.Kind
+}
@@ -0,0 +1,133 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shop"}}
+	injector := NewInjector()
+	c := NewClient(fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(deployment, configMap).Build(), injector)
+
+	// Nothing is injected until faults are set
+	if err := c.Update(ctx, deployment); err != nil {
+		t.Fatalf("Update() without faults error = %v", err)
+	}
+
+	if err := injector.Set(Faults{ConflictRate: 1, Kinds: []string{"Deployment"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, deployment); failure.Classify(err) != failure.Conflict {
+		t.Errorf("Update() error = %v, want an injected conflict", err)
+	}
+	if err := c.Status().Update(ctx, deployment); failure.Classify(err) != failure.Conflict {
+		t.Errorf("Status().Update() error = %v, want an injected conflict", err)
+	}
+	// Reads never conflict, and other kinds are left alone
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err != nil {
+		t.Errorf("Get() error = %v, want no conflict on reads", err)
+	}
+	if err := c.Update(ctx, configMap); err != nil {
+		t.Errorf("Update() of a ConfigMap error = %v, want it left alone", err)
+	}
+
+	if err := injector.Set(Faults{ErrorRate: 1, Namespaces: []string{"shop"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.List(ctx, &appsv1.DeploymentList{}, client.InNamespace("shop")); failure.Classify(err) != failure.Unavailable {
+		t.Errorf("List() error = %v, want an injected server error", err)
+	}
+	if err := c.List(ctx, &appsv1.DeploymentList{}, client.InNamespace("other")); err != nil {
+		t.Errorf("List() of another namespace error = %v, want it left alone", err)
+	}
+
+	if err := injector.Set(Faults{SlowRate: 1, Latency: metav1.Duration{Duration: 20 * time.Millisecond}}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := c.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Get() took %v, want it delayed by the latency", elapsed)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := c.Get(canceled, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{}); err != context.Canceled {
+		t.Errorf("Get() with a canceled context error = %v, want it canceled", err)
+	}
+	if got := injector.Status().Injected[FaultSlow]; got != 2 {
+		t.Errorf("slow requests counted = %d, want 2", got)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	decisions := func() []bool {
+		injector := NewInjector()
+		if err := injector.Set(Faults{ErrorRate: 0.5, Seed: 42}); err != nil {
+			t.Fatal(err)
+		}
+		var failed []bool
+		for range 20 {
+			_, err := injector.decide("get", "Deployment", "shop", "web")
+			failed = append(failed, err != nil)
+		}
+		return failed
+	}
+	first, second := decisions(), decisions()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("request %d failed %t then %t, want the same faults with the same seed", i, first[i], second[i])
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	injector := NewInjector()
+	handler := injector.Handler()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{name: "read", method: http.MethodGet, want: http.StatusOK},
+		{name: "set", method: http.MethodPut, body: `{"conflictRate": 0.2, "errorRate": 0.1, "slowRate": 0.5, "latency": "2s"}`, want: http.StatusOK},
+		{name: "rate above 1", method: http.MethodPut, body: `{"errorRate": 1.5}`, want: http.StatusBadRequest},
+		{name: "slow without latency", method: http.MethodPut, body: `{"slowRate": 0.5}`, want: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPut, body: `{"dropRate": 0.5}`, want: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodPost, body: `{}`, want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, Path, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	// Invalid faults leave the valid ones in place
+	faults := injector.Status().Faults
+	if faults.ConflictRate != 0.2 || faults.ErrorRate != 0.1 || faults.Latency.Duration != 2*time.Second {
+		t.Errorf("faults = %+v, want those of the last valid request", faults)
+	}
+}
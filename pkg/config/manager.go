@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"sync"
 
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/failure"
 )
 
 const (
@@ -27,12 +31,20 @@ const (
 	EnvConfigNamespace = "CONFIG_NAMESPACE"
 )
 
-// Manager manages the global configuration
+// Listener is notified with the new configuration every time it is reloaded
+type Listener func(config *GlobalConfig)
+
+// Manager manages the global configuration and the namespace overlays
 type Manager struct {
 	client    client.Client
 	config    *GlobalConfig
 	namespace string
 	mutex     sync.RWMutex
+	listeners []Listener
+	// namespaces holds the overlays of the ConfigMaps in workload namespaces
+	namespaces map[string]*NamespaceConfig
+	// secrets reads the Secrets referenced by the configuration, client when nil
+	secrets client.Reader
 }
 
 // NewManager creates a new configuration manager
@@ -44,10 +56,43 @@ func NewManager(client client.Client) *Manager {
 	log := log.Log.WithName("config.Manager")
 	log.Info("Creating new ConfigManager", "namespace", namespace)
 	return &Manager{
-		client:    client,
-		config:    DefaultConfig(),
-		namespace: namespace,
+		client:     client,
+		config:     DefaultConfig(),
+		namespace:  namespace,
+		namespaces: make(map[string]*NamespaceConfig),
+	}
+}
+
+// NewStaticManager returns a manager serving cfg, which is never reloaded.
+// It is used to evaluate a hypothetical configuration.
+func NewStaticManager(cfg *GlobalConfig) *Manager {
+	return &Manager{config: cfg}
+}
+
+// WithConfig returns a static manager serving cfg with the namespace overlays of m
+func (m *Manager) WithConfig(cfg *GlobalConfig) *Manager {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	static := NewStaticManager(cfg)
+	static.namespaces = make(map[string]*NamespaceConfig, len(m.namespaces))
+	for namespace, overlay := range m.namespaces {
+		static.namespaces[namespace] = overlay
 	}
+	return static
+}
+
+// SetSecretReader sets the reader of the Secrets referenced by the
+// configuration, typically the API reader so Secrets are not cached
+func (m *Manager) SetSecretReader(reader client.Reader) {
+	m.secrets = reader
+}
+
+// secretReader returns the reader of the Secrets referenced by the configuration
+func (m *Manager) secretReader() client.Reader {
+	if m.secrets != nil {
+		return m.secrets
+	}
+	return m.client
 }
 
 // SetupWithManager sets up the manager with the Manager.
@@ -55,16 +100,10 @@ func (m *Manager) SetupWithManager(mgr manager.Manager) error {
 	// Create a new controller for watching ConfigMap changes
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.ConfigMap{}).
-		WithEventFilter(predicate.And(
-			predicate.NewPredicateFuncs(func(obj client.Object) bool {
-				// Only watch our specific ConfigMap in our namespace
-				return obj.GetName() == ConfigMapName && obj.GetNamespace() == m.namespace
-			}),
-			// Only watch ConfigMaps in our namespace
-			predicate.NewPredicateFuncs(func(obj client.Object) bool {
-				return obj.GetNamespace() == m.namespace
-			}),
-		)).
+		// Only watch our ConfigMap, in our namespace or overlaying it in a workload namespace
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == ConfigMapName
+		})).
 		Complete(m)
 }
 
@@ -73,11 +112,22 @@ func (m *Manager) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 	log := log.FromContext(ctx)
 	log.Info("ConfigMap changed, reloading configuration", "name", req.Name, "namespace", req.Namespace)
 
+	if req.Namespace != m.namespace {
+		if err := m.loadNamespaceConfig(ctx, req.Namespace); err != nil {
+			log.Error(err, "Failed to reload namespace configuration")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
 	if err := m.loadConfig(ctx); err != nil {
 		log.Error(err, "Failed to reload configuration")
 		return ctrl.Result{}, err
 	}
 
+	// Read the referenced Secrets again, so rotated credentials apply
+	if len(m.GetConfig().SecretRefs()) > 0 {
+		return ctrl.Result{RequeueAfter: SecretRefreshInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
@@ -102,6 +152,41 @@ func (m *Manager) GetConfig() *GlobalConfig {
 	return m.config
 }
 
+// ForNamespace returns the configuration of the workloads of a namespace: the
+// global config with the overlay of the namespace on top when namespace
+// configs are enabled
+func (m *Manager) ForNamespace(namespace string) *GlobalConfig {
+	cfg := m.GetConfig()
+	if overlay := m.NamespaceConfig(namespace); overlay != nil {
+		return overlay.Apply(cfg)
+	}
+	return cfg
+}
+
+// NamespaceConfig returns the overlay of a namespace, or nil when it has none
+// or namespace configs are disabled
+func (m *Manager) NamespaceConfig(namespace string) *NamespaceConfig {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	if m.config == nil || !m.config.NamespaceConfigs.Enabled {
+		return nil
+	}
+	return m.namespaces[namespace]
+}
+
+// Namespace returns the namespace holding the controller configuration
+func (m *Manager) Namespace() string {
+	return m.namespace
+}
+
+// AddListener registers a function called after every configuration reload.
+// Listeners must be added before the manager is started.
+func (m *Manager) AddListener(listener Listener) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.listeners = append(m.listeners, listener)
+}
+
 // loadConfig loads the configuration from the ConfigMap
 func (m *Manager) loadConfig(ctx context.Context) error {
 	log := log.FromContext(ctx)
@@ -120,21 +205,35 @@ func (m *Manager) loadConfig(ctx context.Context) error {
 
 	configData, ok := cm.Data[ConfigMapKey]
 	if !ok {
-		return fmt.Errorf("ConfigMap key %s not found", ConfigMapKey)
+		return failure.New(failure.InvalidConfig, fmt.Sprintf("ConfigMap key %s not found", ConfigMapKey))
 	}
 
 	config := &GlobalConfig{}
 	if err := yaml.Unmarshal([]byte(configData), config); err != nil {
-		return fmt.Errorf("failed to unmarshal config: %w", err)
+		return failure.Wrap(failure.InvalidConfig, fmt.Errorf("failed to unmarshal config: %w", err))
+	}
+	if err := resolveSecrets(ctx, m.secretReader(), m.namespace, config); err != nil {
+		// The integrations missing their credentials fail on their own
+		log.Error(err, "Failed to resolve the Secrets referenced by the configuration")
 	}
 
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+	previous := m.GetConfig()
+	if reflect.DeepEqual(previous, config) {
+		// Periodic reloads refreshing the Secrets do not notify the listeners
+		log.V(1).Info("Configuration unchanged")
+		return nil
+	}
+
+	// Warn once per change rather than on every periodic reload
+	if deprecated := config.DeprecatedCredentials(); len(deprecated) > 0 {
+		log.Info("Reading credentials from environment variables is deprecated, use the *SecretRef fields instead",
+			"fields", deprecated)
+	}
 
 	// Only log if configuration actually changed
-	if m.config.GlobalPercentage != config.GlobalPercentage ||
-		m.config.MaxReplicas != config.MaxReplicas ||
-		m.config.MinReplicas != config.MinReplicas {
+	if previous.GlobalPercentage != config.GlobalPercentage ||
+		previous.MaxReplicas != config.MaxReplicas ||
+		previous.MinReplicas != config.MinReplicas {
 		log.Info("Configuration updated",
 			"global_percentage", config.GlobalPercentage,
 			"max_replicas", config.MaxReplicas,
@@ -143,10 +242,82 @@ func (m *Manager) loadConfig(ctx context.Context) error {
 		log.V(1).Info("Configuration unchanged")
 	}
 
-	m.config = config
+	m.SetConfig(config)
+	return nil
+}
+
+// loadNamespaceConfig loads the overlay of a namespace from its ConfigMap, or
+// drops it once the ConfigMap is deleted. An invalid overlay keeps the previous one.
+func (m *Manager) loadNamespaceConfig(ctx context.Context, namespace string) error {
+	cm := &corev1.ConfigMap{}
+	err := m.client.Get(ctx, types.NamespacedName{Name: ConfigMapName, Namespace: namespace}, cm)
+	if apierrors.IsNotFound(err) {
+		m.SetNamespaceConfig(namespace, nil)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap: %w", err)
+	}
+
+	configData, ok := cm.Data[ConfigMapKey]
+	if !ok {
+		return fmt.Errorf("ConfigMap key %s not found", ConfigMapKey)
+	}
+	overlay, err := ParseNamespaceConfig(configData)
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Namespace configuration updated", "namespace", namespace)
+	m.SetNamespaceConfig(namespace, overlay)
 	return nil
 }
 
+// LoadNamespaceConfigs loads the overlays of every workload namespace with a
+// ConfigMap, as their reloads would. Invalid overlays are logged and skipped.
+func (m *Manager) LoadNamespaceConfigs(ctx context.Context) error {
+	configMaps := &corev1.ConfigMapList{}
+	if err := m.client.List(ctx, configMaps); err != nil {
+		return fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	for _, cm := range configMaps.Items {
+		if cm.Name != ConfigMapName || cm.Namespace == m.namespace {
+			continue
+		}
+		if err := m.loadNamespaceConfig(ctx, cm.Namespace); err != nil {
+			log.FromContext(ctx).Error(err, "Ignoring invalid namespace configuration", "namespace", cm.Namespace)
+		}
+	}
+	return nil
+}
+
+// SetNamespaceConfig replaces the overlay of a namespace, nil removes it
+func (m *Manager) SetNamespaceConfig(namespace string, overlay *NamespaceConfig) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if overlay == nil {
+		delete(m.namespaces, namespace)
+		return
+	}
+	if m.namespaces == nil {
+		m.namespaces = make(map[string]*NamespaceConfig)
+	}
+	m.namespaces[namespace] = overlay
+}
+
+// SetConfig replaces the configuration and notifies the listeners, as a
+// reload of the ConfigMap does. Tests use it to change a static configuration.
+func (m *Manager) SetConfig(config *GlobalConfig) {
+	m.mutex.Lock()
+	m.config = config
+	listeners := m.listeners
+	m.mutex.Unlock()
+
+	for _, listener := range listeners {
+		listener(config)
+	}
+}
+
 // RefreshConfig forces a refresh of the configuration
 func (m *Manager) RefreshConfig(ctx context.Context) error {
 	return m.loadConfig(ctx)
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NamespaceConfig is the part of the configuration a replicas-controller-config
+// ConfigMap in a workload namespace may set. Set fields win over the global
+// config for the workloads of the namespace; cluster-wide settings such as
+// triggers or metrics sinks stay with the global config.
+type NamespaceConfig struct {
+	// GlobalPercentage replaces the global percentage in the namespace
+	GlobalPercentage *int32 `yaml:"globalPercentage,omitempty"`
+	// MaxReplicas replaces the global maximum number of replicas
+	MaxReplicas *int32 `yaml:"maxReplicas,omitempty"`
+	// MinReplicas replaces the global minimum number of replicas
+	MinReplicas *int32 `yaml:"minReplicas,omitempty"`
+	// ProtectScaledToZero replaces the global protectScaledToZero
+	ProtectScaledToZero *bool `yaml:"protectScaledToZero,omitempty"`
+	// PreserveHPAMinForExternalMetrics replaces the global preserveHPAMinForExternalMetrics
+	PreserveHPAMinForExternalMetrics *bool `yaml:"preserveHPAMinForExternalMetrics,omitempty"`
+	// MinWorkloadAge replaces the global minWorkloadAge
+	MinWorkloadAge *time.Duration `yaml:"minWorkloadAge,omitempty"`
+	// ScaleDown replaces the global scaleDown settings
+	ScaleDown *ScaleDownConfig `yaml:"scaleDown,omitempty"`
+	// Rollouts replaces the global rollouts settings
+	Rollouts *RolloutConfig `yaml:"rollouts,omitempty"`
+}
+
+// ParseNamespaceConfig parses the configuration of a namespace ConfigMap.
+// Keys a namespace may not set are rejected.
+func ParseNamespaceConfig(data string) (*NamespaceConfig, error) {
+	decoder := yaml.NewDecoder(bytes.NewBufferString(data))
+	decoder.KnownFields(true)
+	overlay := &NamespaceConfig{}
+	if err := decoder.Decode(overlay); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to unmarshal namespace config: %w", err)
+	}
+	return overlay, nil
+}
+
+// Apply returns a copy of cfg with the fields set by the namespace config
+func (n *NamespaceConfig) Apply(cfg *GlobalConfig) *GlobalConfig {
+	layered := *cfg
+	if n.GlobalPercentage != nil {
+		layered.GlobalPercentage = *n.GlobalPercentage
+	}
+	if n.MaxReplicas != nil {
+		layered.MaxReplicas = *n.MaxReplicas
+	}
+	if n.MinReplicas != nil {
+		layered.MinReplicas = *n.MinReplicas
+	}
+	if n.ProtectScaledToZero != nil {
+		layered.ProtectScaledToZero = *n.ProtectScaledToZero
+	}
+	if n.PreserveHPAMinForExternalMetrics != nil {
+		layered.PreserveHPAMinForExternalMetrics = *n.PreserveHPAMinForExternalMetrics
+	}
+	if n.MinWorkloadAge != nil {
+		layered.MinWorkloadAge = *n.MinWorkloadAge
+	}
+	if n.ScaleDown != nil {
+		layered.ScaleDown = *n.ScaleDown
+	}
+	if n.Rollouts != nil {
+		layered.Rollouts = *n.Rollouts
+	}
+	return &layered
+}
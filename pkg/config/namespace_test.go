@@ -0,0 +1,89 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseNamespaceConfig(t *testing.T) {
+	overlay, err := ParseNamespaceConfig("globalPercentage: 50\nmaxReplicas: 10\nminWorkloadAge: 10m\n")
+	if err != nil {
+		t.Fatalf("ParseNamespaceConfig() error = %v", err)
+	}
+	if *overlay.GlobalPercentage != 50 || *overlay.MaxReplicas != 10 || *overlay.MinWorkloadAge != 10*time.Minute || overlay.MinReplicas != nil {
+		t.Errorf("ParseNamespaceConfig() = %+v", overlay)
+	}
+	if _, err := ParseNamespaceConfig("triggers: []\n"); err == nil {
+		t.Error("ParseNamespaceConfig() accepted a cluster-wide setting")
+	}
+	if overlay, err := ParseNamespaceConfig(""); err != nil || overlay.GlobalPercentage != nil {
+		t.Errorf("ParseNamespaceConfig() of an empty config = (%+v, %v), want an empty overlay", overlay, err)
+	}
+}
+
+func TestForNamespace(t *testing.T) {
+	percentage := int32(50)
+	cfg := &GlobalConfig{GlobalPercentage: 100, MinReplicas: 1, MaxReplicas: 20}
+	m := NewStaticManager(cfg)
+	m.SetNamespaceConfig("staging", &NamespaceConfig{GlobalPercentage: &percentage})
+
+	if got := m.ForNamespace("staging"); got.GlobalPercentage != 100 {
+		t.Errorf("ForNamespace() with namespace configs disabled = %d%%, want the global 100%%", got.GlobalPercentage)
+	}
+
+	cfg.NamespaceConfigs.Enabled = true
+	if got := m.ForNamespace("staging"); got.GlobalPercentage != 50 || got.MaxReplicas != 20 {
+		t.Errorf("ForNamespace() = (%d%%, max %d), want the overlay 50%% with the global max 20", got.GlobalPercentage, got.MaxReplicas)
+	}
+	if got := m.ForNamespace("prod"); got != cfg {
+		t.Errorf("ForNamespace() of a namespace without overlay = %+v, want the global config", got)
+	}
+	if cfg.GlobalPercentage != 100 {
+		t.Errorf("ForNamespace() changed the global config to %d%%", cfg.GlobalPercentage)
+	}
+
+	hypothetical := *cfg
+	hypothetical.GlobalPercentage = 200
+	if got := m.WithConfig(&hypothetical).ForNamespace("staging"); got.GlobalPercentage != 50 {
+		t.Errorf("WithConfig() lost the overlay, ForNamespace() = %d%%", got.GlobalPercentage)
+	}
+
+	m.SetNamespaceConfig("staging", nil)
+	if got := m.ForNamespace("staging"); got.GlobalPercentage != 100 {
+		t.Errorf("ForNamespace() after removing the overlay = %d%%, want 100%%", got.GlobalPercentage)
+	}
+}
+
+func TestLoadNamespaceConfigs(t *testing.T) {
+	t.Setenv(EnvConfigNamespace, DefaultConfigMapNamespace)
+	configMap := func(namespace, data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: namespace},
+			Data:       map[string]string{ConfigMapKey: data},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(
+		configMap(DefaultConfigMapNamespace, "namespaceConfigs:\n  enabled: true\n"),
+		configMap("shop", "globalPercentage: 50\n"),
+		configMap("broken", "triggers: []\n"),
+	).Build()
+	m := NewManager(c)
+	m.SetConfig(&GlobalConfig{GlobalPercentage: 100, NamespaceConfigs: NamespaceConfigsConfig{Enabled: true}})
+
+	// An invalid overlay does not keep the others from loading
+	if err := m.LoadNamespaceConfigs(context.Background()); err != nil {
+		t.Fatalf("LoadNamespaceConfigs() error = %v", err)
+	}
+	if got := m.ForNamespace("shop").GlobalPercentage; got != 50 {
+		t.Errorf("shop percentage = %d, want 50 from its overlay", got)
+	}
+	if m.NamespaceConfig("broken") != nil || m.NamespaceConfig(DefaultConfigMapNamespace) != nil {
+		t.Error("LoadNamespaceConfigs() loaded an invalid overlay or the global config as an overlay")
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// WorkloadResolverConfig maps the custom resources of a kind, e.g. a Kafka
+// cluster or a game-server fleet managed by an operator, to the replica counts
+// they hold, so override selectors and the global config scale them by
+// percentage like Deployments
+type WorkloadResolverConfig struct {
+	// APIVersion of the custom resource, e.g. kafka.strimzi.io/v1beta2
+	APIVersion string `yaml:"apiVersion"`
+	// Kind of the custom resource, e.g. Kafka
+	Kind string `yaml:"kind"`
+	// SubResources are the replica counts of the custom resource, each scaled on its own
+	SubResources []ResolvedSubResource `yaml:"subResources"`
+}
+
+// ResolvedSubResource is a replica count held by a field of a custom resource
+type ResolvedSubResource struct {
+	// Name identifies the sub-resource in annotations, metrics and logs, e.g. brokers
+	Name string `yaml:"name"`
+	// ReplicasPath is the dot-separated path of the replica count, e.g. spec.kafka.replicas
+	ReplicasPath string `yaml:"replicasPath"`
+	// MinReplicas is the lowest count the sub-resource is scaled to, e.g. to keep a quorum
+	MinReplicas int32 `yaml:"minReplicas,omitempty"`
+}
@@ -0,0 +1,107 @@
+package config
+
+import "time"
+
+// Management modes of targets driven by an external autoscaler
+const (
+	// ScalerModeLimitsOnly adjusts only the min/max limits of the external scaler
+	// and never writes the replicas of the target
+	ScalerModeLimitsOnly = "limits-only"
+	// ScalerModeIgnore leaves the target and its scaler untouched
+	ScalerModeIgnore = "ignore"
+	// ScalerModeManage scales the target as if no external scaler was present
+	ScalerModeManage = "manage"
+)
+
+// ExternalScalersConfig configures the detection of external autoscalers
+type ExternalScalersConfig struct {
+	// KEDA detects deployments scaled by a KEDA ScaledObject
+	KEDA ExternalScalerDetection `yaml:"keda,omitempty"`
+	// ArgoRollouts detects deployments owned by an Argo Rollout, whose HPAs target the Rollout
+	ArgoRollouts ExternalScalerDetection `yaml:"argoRollouts,omitempty"`
+	// Custom detects other scalers by well-known labels or annotations on the deployment.
+	// Custom scalers expose no known limits, so limits-only leaves their targets untouched.
+	Custom []CustomScalerDetection `yaml:"custom,omitempty"`
+}
+
+// ExternalScalerDetection configures a built-in detection
+type ExternalScalerDetection struct {
+	// Mode is limits-only (default), ignore or manage
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// CustomScalerDetection detects a custom scaler from deployment metadata
+type CustomScalerDetection struct {
+	// Name identifies the scaler in annotations and logs
+	Name string `yaml:"name"`
+	// Labels that must all be present on the deployment
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// Annotations that must all be present on the deployment
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	// Mode is limits-only (default), ignore or manage
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// GetMode returns the management mode or its default
+func (d ExternalScalerDetection) GetMode() string {
+	return scalerMode(d.Mode)
+}
+
+// GetMode returns the management mode or its default
+func (d CustomScalerDetection) GetMode() string {
+	return scalerMode(d.Mode)
+}
+
+// Matches returns true if the labels and annotations contain every configured value
+func (d CustomScalerDetection) Matches(labels, annotations map[string]string) bool {
+	if len(d.Labels) == 0 && len(d.Annotations) == 0 {
+		return false
+	}
+	for key, value := range d.Labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	for key, value := range d.Annotations {
+		if annotations[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func scalerMode(mode string) string {
+	if mode == "" {
+		return ScalerModeLimitsOnly
+	}
+	return mode
+}
+
+// Modes of deployments whose HPA cannot scale
+const (
+	// BrokenHPAModeDefer keeps scaling the limits of the HPA
+	BrokenHPAModeDefer = "defer"
+	// BrokenHPAModeDirect scales the replicas of the deployment as if it had no HPA
+	BrokenHPAModeDirect = "direct"
+)
+
+// BrokenHPAConfig configures deployments whose HPA reports ScalingActive=False,
+// typically because metrics-server or the custom metrics APIs are not installed
+type BrokenHPAConfig struct {
+	// Mode is defer (default) or direct
+	Mode string `yaml:"mode,omitempty"`
+	// TakeOverAfter is how long an HPA must have been failing before direct
+	// mode takes over its deployment (default: at once)
+	TakeOverAfter time.Duration `yaml:"takeOverAfter,omitempty"`
+	// HandBackAfter is how long a recovered HPA must have been scaling again
+	// before it gets its deployment back, so a flapping HPA does not flip the mode
+	HandBackAfter time.Duration `yaml:"handBackAfter,omitempty"`
+}
+
+// GetMode returns the mode of deployments with a broken HPA or its default
+func (c BrokenHPAConfig) GetMode() string {
+	if c.Mode == "" {
+		return BrokenHPAModeDefer
+	}
+	return c.Mode
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretRefreshInterval is how often the Secrets referenced by the
+// configuration are read again, so rotated credentials apply without a restart
+const SecretRefreshInterval = time.Minute
+
+// SecretKeyRef selects a key of a Secret in the namespace of the controller
+// configuration holding a credential of an integration. The Manager reads it
+// when the configuration is loaded and every SecretRefreshInterval.
+type SecretKeyRef struct {
+	// Name of the Secret
+	Name string `yaml:"name"`
+	// Key of the credential in the Secret
+	Key string `yaml:"key"`
+
+	// value is the credential read by the Manager
+	value string
+}
+
+// Credential returns the credential of ref when set, otherwise the value of
+// the environment variable env, if any. Reading credentials from the
+// environment is deprecated, see DeprecatedCredentials.
+func Credential(ref *SecretKeyRef, env string) string {
+	if ref != nil {
+		return ref.value
+	}
+	if env != "" {
+		return os.Getenv(env)
+	}
+	return ""
+}
+
+// SetValue sets the credential of ref, as the Manager does when it reads the
+// Secret. Tests use it to configure credentials.
+func (r *SecretKeyRef) SetValue(value string) {
+	r.value = value
+}
+
+// SecretRefs returns the Secret references of every integration of c
+func (c *GlobalConfig) SecretRefs() []*SecretKeyRef {
+	refs := []*SecretKeyRef{
+		c.ErrorBudget.TokenSecretRef,
+		c.CarbonAware.TokenSecretRef,
+		c.DecisionWebhook.TokenSecretRef,
+	}
+	for i := range c.Triggers {
+		trigger := &c.Triggers[i]
+		if trigger.SQS != nil {
+			refs = append(refs, trigger.SQS.AccessKeyIDSecretRef, trigger.SQS.SecretAccessKeySecretRef, trigger.SQS.SessionTokenSecretRef)
+		}
+		if trigger.RabbitMQ != nil {
+			refs = append(refs, trigger.RabbitMQ.UsernameSecretRef, trigger.RabbitMQ.PasswordSecretRef)
+		}
+		if trigger.Istio != nil {
+			refs = append(refs, trigger.Istio.TokenSecretRef)
+		}
+	}
+
+	set := refs[:0]
+	for _, ref := range refs {
+		if ref != nil {
+			set = append(set, ref)
+		}
+	}
+	return set
+}
+
+// DeprecatedCredentials returns the fields of c reading a credential from an
+// environment variable of the controller instead of a Secret. They are
+// deprecated: the variables are baked into the controller Deployment, shared
+// by every integration and only rotated by a restart.
+func (c *GlobalConfig) DeprecatedCredentials() []string {
+	var fields []string
+	if c.ErrorBudget.TokenEnv != "" {
+		fields = append(fields, "errorBudget.tokenEnv")
+	}
+	if c.CarbonAware.TokenEnv != "" {
+		fields = append(fields, "carbonAware.tokenEnv")
+	}
+	if c.DecisionWebhook.TokenEnv != "" {
+		fields = append(fields, "decisionWebhook.tokenEnv")
+	}
+	for i := range c.Triggers {
+		trigger := &c.Triggers[i]
+		prefix := fmt.Sprintf("triggers[%s].", trigger.Name)
+		if trigger.SQS != nil && (trigger.SQS.AccessKeyIDSecretRef == nil || trigger.SQS.SecretAccessKeySecretRef == nil) {
+			fields = append(fields, prefix+"sqs (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)")
+		}
+		if trigger.RabbitMQ != nil && trigger.RabbitMQ.UsernameEnv != "" {
+			fields = append(fields, prefix+"rabbitmq.usernameEnv")
+		}
+		if trigger.RabbitMQ != nil && trigger.RabbitMQ.PasswordEnv != "" {
+			fields = append(fields, prefix+"rabbitmq.passwordEnv")
+		}
+		if trigger.Istio != nil && trigger.Istio.TokenEnv != "" {
+			fields = append(fields, prefix+"istio.tokenEnv")
+		}
+	}
+	return fields
+}
+
+// resolveSecrets reads the credentials referenced by cfg from the Secrets of
+// namespace. A reference that cannot be read is left empty, so the
+// integration fails with its own error while the others keep working.
+func resolveSecrets(ctx context.Context, reader client.Reader, namespace string, cfg *GlobalConfig) error {
+	secrets := make(map[string]*corev1.Secret)
+	var errs []string
+	for _, ref := range cfg.SecretRefs() {
+		secret, read := secrets[ref.Name]
+		if !read {
+			secret = &corev1.Secret{}
+			if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+				secret = nil
+				errs = append(errs, fmt.Sprintf("secret %s/%s: %v", namespace, ref.Name, err))
+			}
+			secrets[ref.Name] = secret
+		}
+		if secret == nil {
+			continue
+		}
+		value, found := secret.Data[ref.Key]
+		if !found {
+			errs = append(errs, fmt.Sprintf("key %q not found in secret %s/%s", ref.Key, namespace, ref.Name))
+			continue
+		}
+		ref.value = strings.TrimSpace(string(value))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read credentials: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSecretCredentials(t *testing.T) {
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: DefaultConfigMapNamespace},
+		Data: map[string]string{ConfigMapKey: `
+decisionWebhook:
+  url: https://decisions.example.com
+  tokenSecretRef: {name: integrations, key: decision-token}
+errorBudget:
+  prometheusURL: https://prometheus.example.com
+  tokenSecretRef: {name: integrations, key: missing}
+`},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "integrations", Namespace: DefaultConfigMapNamespace},
+		Data:       map[string][]byte{"decision-token": []byte("first\n")},
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).WithObjects(configMap, secret).Build()
+	m := NewManager(c)
+
+	reloads := 0
+	m.AddListener(func(*GlobalConfig) { reloads++ })
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: ConfigMapName, Namespace: DefaultConfigMapNamespace}}
+	result, err := m.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != SecretRefreshInterval {
+		t.Errorf("Reconcile() requeues after %v, want %v to refresh the Secrets", result.RequeueAfter, SecretRefreshInterval)
+	}
+	cfg := m.GetConfig()
+	if got := Credential(cfg.DecisionWebhook.TokenSecretRef, cfg.DecisionWebhook.TokenEnv); got != "first" {
+		t.Errorf("decision webhook token = %q, want the trimmed Secret value", got)
+	}
+	// A missing key leaves the credential empty without failing the reload
+	if got := Credential(cfg.ErrorBudget.TokenSecretRef, "HOME"); got != "" {
+		t.Errorf("error budget token = %q, want empty for a missing key", got)
+	}
+
+	// An unchanged Secret does not notify the listeners again
+	if _, err := m.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if reloads != 1 {
+		t.Errorf("listeners notified %d times, want once while nothing changed", reloads)
+	}
+
+	secret.Data["decision-token"] = []byte("rotated")
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	cfg = m.GetConfig()
+	if got := Credential(cfg.DecisionWebhook.TokenSecretRef, ""); got != "rotated" || reloads != 2 {
+		t.Errorf("after rotation token = %q with %d reloads, want the rotated token and a reload", got, reloads)
+	}
+}
+
+func TestCredentialFromEnvironment(t *testing.T) {
+	t.Setenv("INTEGRATION_TOKEN", "from-env")
+	if got := Credential(nil, "INTEGRATION_TOKEN"); got != "from-env" {
+		t.Errorf("Credential() without ref = %q, want the environment variable", got)
+	}
+	ref := &SecretKeyRef{Name: "integrations", Key: "token"}
+	ref.SetValue("from-secret")
+	if got := Credential(ref, "INTEGRATION_TOKEN"); got != "from-secret" {
+		t.Errorf("Credential() = %q, want the Secret value over the environment", got)
+	}
+	if got := Credential(nil, ""); got != "" {
+		t.Errorf("Credential() without ref nor env = %q, want empty", got)
+	}
+}
+
+func TestDeprecatedCredentials(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ErrorBudget.TokenEnv = "PROMETHEUS_TOKEN"
+	cfg.DecisionWebhook.TokenSecretRef = &SecretKeyRef{Name: "integrations", Key: "decision-token"}
+	cfg.Triggers = []TriggerConfig{
+		{Name: "jobs", RabbitMQ: &RabbitMQTriggerConfig{
+			PasswordEnv:       "RABBITMQ_PASSWORD",
+			UsernameSecretRef: &SecretKeyRef{Name: "rabbitmq", Key: "username"},
+		}},
+		{Name: "orders", SQS: &SQSTriggerConfig{}},
+		{Name: "signed", SQS: &SQSTriggerConfig{
+			AccessKeyIDSecretRef:     &SecretKeyRef{Name: "aws", Key: "id"},
+			SecretAccessKeySecretRef: &SecretKeyRef{Name: "aws", Key: "secret"},
+		}},
+	}
+
+	want := []string{
+		"errorBudget.tokenEnv",
+		"triggers[jobs].rabbitmq.passwordEnv",
+		"triggers[orders].sqs (AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)",
+	}
+	if got := cfg.DeprecatedCredentials(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedCredentials() = %v, want %v", got, want)
+	}
+}
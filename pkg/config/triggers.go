@@ -0,0 +1,189 @@
+package config
+
+import "time"
+
+// TriggerConfig configures an external signal that drives the percentage of
+// the overrides it is mapped to
+type TriggerConfig struct {
+	// Name identifies the trigger in logs and status
+	Name string `yaml:"name"`
+	// Type is the signal source: kafka, sqs, rabbitmq, ingress or istio
+	Type string `yaml:"type"`
+	// Overrides lists the ReplicasOverrides driven by the trigger as "namespace/name"
+	Overrides []string `yaml:"overrides"`
+	// Interval is how often the source is polled (default 30s)
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// Steps map the signal value to a percentage; the step with the highest
+	// threshold at or below the value applies. Below every threshold the
+	// override percentage is kept.
+	Steps []TriggerStep `yaml:"steps"`
+	// Behavior stabilizes the percentage so it does not flap with every reading
+	Behavior *TriggerBehavior `yaml:"behavior,omitempty"`
+
+	// Kafka reads consumer group lag from a Burrow-compatible HTTP endpoint
+	Kafka *KafkaTriggerConfig `yaml:"kafka,omitempty"`
+	// SQS reads the approximate number of visible messages of a queue
+	SQS *SQSTriggerConfig `yaml:"sqs,omitempty"`
+	// RabbitMQ reads the queue depth from the management HTTP API
+	RabbitMQ *RabbitMQTriggerConfig `yaml:"rabbitmq,omitempty"`
+	// Ingress reads the request rate of a backend from ingress or gateway controller metrics
+	Ingress *IngressTriggerConfig `yaml:"ingress,omitempty"`
+	// Istio reads service mesh golden signals of a destination service from Prometheus
+	Istio *IstioTriggerConfig `yaml:"istio,omitempty"`
+}
+
+// TriggerStep maps a signal threshold to a percentage
+type TriggerStep struct {
+	// Threshold is the signal value from which the step applies
+	Threshold float64 `yaml:"threshold"`
+	// Percentage is applied to the mapped overrides while the step is active
+	Percentage int32 `yaml:"percentage"`
+}
+
+// TriggerBehavior mirrors the HPA behavior semantics for trigger-driven percentages
+type TriggerBehavior struct {
+	// ScaleUpStabilization is the window over which the lowest recommendation is
+	// used when the percentage rises (default 0, rise immediately)
+	ScaleUpStabilization *time.Duration `yaml:"scaleUpStabilization,omitempty"`
+	// ScaleDownStabilization is the window over which the highest recommendation is
+	// used when the percentage falls (default 5m)
+	ScaleDownStabilization *time.Duration `yaml:"scaleDownStabilization,omitempty"`
+	// Tolerance is the relative change, in percent, below which the percentage is kept (default 0)
+	Tolerance float64 `yaml:"tolerance,omitempty"`
+}
+
+// GetScaleUpStabilization returns the scale up window or its default
+func (c TriggerConfig) GetScaleUpStabilization() time.Duration {
+	if c.Behavior == nil || c.Behavior.ScaleUpStabilization == nil {
+		return DefaultTriggerScaleUpStabilization
+	}
+	return *c.Behavior.ScaleUpStabilization
+}
+
+// GetScaleDownStabilization returns the scale down window or its default
+func (c TriggerConfig) GetScaleDownStabilization() time.Duration {
+	if c.Behavior == nil || c.Behavior.ScaleDownStabilization == nil {
+		return DefaultTriggerScaleDownStabilization
+	}
+	return *c.Behavior.ScaleDownStabilization
+}
+
+// GetTolerance returns the tolerance band in percent
+func (c TriggerConfig) GetTolerance() float64 {
+	if c.Behavior == nil {
+		return 0
+	}
+	return c.Behavior.Tolerance
+}
+
+// KafkaTriggerConfig configures consumer lag read from Burrow
+type KafkaTriggerConfig struct {
+	// BurrowURL is the base URL of the Burrow HTTP API
+	BurrowURL string `yaml:"burrowURL"`
+	// Cluster is the Kafka cluster name configured in Burrow
+	Cluster string `yaml:"cluster"`
+	// ConsumerGroup is the consumer group whose total lag is read
+	ConsumerGroup string `yaml:"consumerGroup"`
+}
+
+// SQSTriggerConfig configures an Amazon SQS queue depth source. Credentials are
+// read from the Secrets referenced, or from AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, which is deprecated.
+type SQSTriggerConfig struct {
+	// QueueURL is the URL of the queue
+	QueueURL string `yaml:"queueURL"`
+	// Region is the AWS region of the queue
+	Region string `yaml:"region"`
+	// IncludeInFlight adds messages received but not yet deleted to the depth
+	IncludeInFlight bool `yaml:"includeInFlight,omitempty"`
+	// AccessKeyIDSecretRef, SecretAccessKeySecretRef and SessionTokenSecretRef
+	// hold the credentials instead of the environment
+	AccessKeyIDSecretRef     *SecretKeyRef `yaml:"accessKeyIDSecretRef,omitempty"`
+	SecretAccessKeySecretRef *SecretKeyRef `yaml:"secretAccessKeySecretRef,omitempty"`
+	SessionTokenSecretRef    *SecretKeyRef `yaml:"sessionTokenSecretRef,omitempty"`
+}
+
+// RabbitMQTriggerConfig configures a RabbitMQ queue depth source
+type RabbitMQTriggerConfig struct {
+	// URL is the base URL of the management API (e.g. http://rabbitmq:15672)
+	URL string `yaml:"url"`
+	// VHost is the virtual host of the queue (default "/")
+	VHost string `yaml:"vhost,omitempty"`
+	// Queue is the name of the queue
+	Queue string `yaml:"queue"`
+	// UsernameEnv and PasswordEnv are the environment variables holding the credentials
+	//
+	// Deprecated: use UsernameSecretRef and PasswordSecretRef.
+	UsernameEnv string `yaml:"usernameEnv,omitempty"`
+	PasswordEnv string `yaml:"passwordEnv,omitempty"`
+	// UsernameSecretRef and PasswordSecretRef hold the credentials instead
+	UsernameSecretRef *SecretKeyRef `yaml:"usernameSecretRef,omitempty"`
+	PasswordSecretRef *SecretKeyRef `yaml:"passwordSecretRef,omitempty"`
+}
+
+// IngressTriggerConfig configures a request-rate source scraped from the
+// Prometheus endpoints of an ingress or Gateway API controller
+type IngressTriggerConfig struct {
+	// Provider selects the default metric: ingress-nginx or envoy-gateway
+	Provider string `yaml:"provider"`
+	// MetricsURLs are the metrics endpoints of every controller replica; their rates are summed
+	MetricsURLs []string `yaml:"metricsURLs"`
+	// Metric overrides the request counter of the provider
+	Metric string `yaml:"metric,omitempty"`
+	// Labels select the backend series, e.g. {namespace: shop, ingress: web} for ingress-nginx
+	// or {envoy_cluster_name: httproute/shop/web/rule/0} for envoy-gateway
+	Labels map[string]string `yaml:"labels"`
+}
+
+// IstioTriggerConfig configures a golden-signal source built from Istio
+// standard metrics stored in Prometheus
+type IstioTriggerConfig struct {
+	// PrometheusURL is the base URL of the Prometheus HTTP API
+	PrometheusURL string `yaml:"prometheusURL"`
+	// TokenEnv is the environment variable holding a bearer token for Prometheus
+	//
+	// Deprecated: use TokenSecretRef.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// TokenSecretRef holds the bearer token instead, read again when it is rotated
+	TokenSecretRef *SecretKeyRef `yaml:"tokenSecretRef,omitempty"`
+	// Service and Namespace identify the destination service
+	Service   string `yaml:"service"`
+	Namespace string `yaml:"namespace"`
+	// Signal is requests (per second), latency (milliseconds) or errors (5xx percentage)
+	Signal string `yaml:"signal"`
+	// Quantile is the latency quantile (default 0.99)
+	Quantile float64 `yaml:"quantile,omitempty"`
+	// Window is the rate window (default 1m)
+	Window time.Duration `yaml:"window,omitempty"`
+}
+
+// GetInterval returns the polling interval or its default
+func (c TriggerConfig) GetInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultTriggerInterval
+	}
+	return c.Interval
+}
+
+// Percentage returns the percentage of the step matching value, and false when
+// the value is below every threshold
+func (c TriggerConfig) Percentage(value float64) (int32, bool) {
+	var percentage int32
+	var threshold float64
+	found := false
+	for _, step := range c.Steps {
+		if value >= step.Threshold && (!found || step.Threshold > threshold) {
+			percentage, threshold, found = step.Percentage, step.Threshold, true
+		}
+	}
+	return percentage, found
+}
+
+const (
+	// DefaultTriggerInterval is the default trigger polling interval
+	DefaultTriggerInterval = 30 * time.Second
+	// DefaultTriggerScaleUpStabilization is the default scale up window, as for the HPA
+	DefaultTriggerScaleUpStabilization = 0
+	// DefaultTriggerScaleDownStabilization is the default scale down window, as for the HPA
+	DefaultTriggerScaleDownStabilization = 5 * time.Minute
+)
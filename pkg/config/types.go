@@ -1,15 +1,583 @@
 package config
 
+import "time"
+
 // GlobalConfig represents the global configuration for the controller
 type GlobalConfig struct {
+	// Enabled lets the global config scale workloads no override or namespace
+	// rule targets. Off by default so an installation does not start rewriting
+	// replicas cluster-wide before it is explicitly activated.
+	Enabled bool `yaml:"enabled"`
+	// StartupGracePeriod holds the global config for this long after the
+	// controller started, giving operators time to review before it acts
+	StartupGracePeriod time.Duration `yaml:"startupGracePeriod,omitempty"`
 	// GlobalPercentage is the default percentage to scale replicas
 	GlobalPercentage int32 `yaml:"globalPercentage"`
 	// MaxReplicas is the maximum number of replicas allowed
 	MaxReplicas int32 `yaml:"maxReplicas"`
 	// MinReplicas is the minimum number of replicas allowed
 	MinReplicas int32 `yaml:"minReplicas"`
+	// ProtectScaledToZero never scales up deployments at zero replicas (parked or idled apps)
+	// unless an override targets them explicitly by deploymentRef
+	ProtectScaledToZero bool `yaml:"protectScaledToZero,omitempty"`
+	// PreserveHPAMinForExternalMetrics keeps the original minReplicas of HPAs using External
+	// or Pods metrics, where it often encodes a business SLA, and only scales maxReplicas
+	PreserveHPAMinForExternalMetrics bool `yaml:"preserveHPAMinForExternalMetrics,omitempty"`
+	// MinWorkloadAge leaves Deployments and StatefulSets created less than this long ago untouched
+	MinWorkloadAge time.Duration `yaml:"minWorkloadAge,omitempty"`
+	// BaselineRefresh controls when original replicas and HPA limits are re-captured
+	BaselineRefresh BaselineRefreshConfig `yaml:"baselineRefresh,omitempty"`
+	// ExternalScalers configures how targets driven by autoscalers other than a plain HPA are managed
+	ExternalScalers ExternalScalersConfig `yaml:"externalScalers,omitempty"`
+	// BrokenHPAs configures deployments whose HPA cannot scale, e.g. without metrics-server
+	BrokenHPAs BrokenHPAConfig `yaml:"brokenHPAs,omitempty"`
+	// StatefulSets enables scaling StatefulSets matched by overrides or the global config
+	StatefulSets StatefulSetConfig `yaml:"statefulSets,omitempty"`
+	// WorkloadResolvers map custom resources of other operators to the replica counts they hold
+	WorkloadResolvers []WorkloadResolverConfig `yaml:"workloadResolvers,omitempty"`
+	// ScaleDown configures how replicas are removed from Deployments
+	ScaleDown ScaleDownConfig `yaml:"scaleDown,omitempty"`
+	// Rollouts configures replica changes of Deployments in the middle of a rollout
+	Rollouts RolloutConfig `yaml:"rollouts,omitempty"`
+	// Metrics configures additional metrics sinks next to the Prometheus endpoint
+	Metrics MetricsConfig `yaml:"metrics,omitempty"`
+	// Report configures the periodic scaling summary report
+	Report ReportConfig `yaml:"report,omitempty"`
+	// Cost configures the static per-pod prices used to estimate cost deltas
+	Cost CostConfig `yaml:"cost,omitempty"`
+	// CarbonAware lowers percentages of flexible workloads while grid carbon intensity is high
+	CarbonAware CarbonAwareConfig `yaml:"carbonAware,omitempty"`
+	// NodeDisruption temporarily boosts workloads whose nodes are drained or interrupted
+	NodeDisruption NodeDisruptionConfig `yaml:"nodeDisruption,omitempty"`
+	// NodePressure lowers percentages of low-priority tiers while the cluster is under pressure
+	NodePressure NodePressureConfig `yaml:"nodePressure,omitempty"`
+	// PriorityTiers maps PriorityClass values to shedding tiers for workloads without a tier label
+	PriorityTiers []PriorityTierMapping `yaml:"priorityTiers,omitempty"`
+	// Triggers drive override percentages from external signals such as queue lag
+	Triggers []TriggerConfig `yaml:"triggers,omitempty"`
+	// ErrorBudget configures the Prometheus queried by the errorRate checks of scale-down verifications
+	ErrorBudget ErrorBudgetConfig `yaml:"errorBudget,omitempty"`
+	// ScaleUpReadyTimeout is how long the new replicas of a scale-up may take to
+	// become Ready before the override reports ScaleUpStalled (default 10m)
+	ScaleUpReadyTimeout time.Duration `yaml:"scaleUpReadyTimeout,omitempty"`
+	// UnmatchedTargetsWarning is how long an override may target no workload
+	// before its TargetsMatched condition turns False (default 1h)
+	UnmatchedTargetsWarning time.Duration `yaml:"unmatchedTargetsWarning,omitempty"`
+	// ReplicaHistoryLimit is how many replica changes of each deployment the
+	// status of its override keeps (default 10, at most 50)
+	ReplicaHistoryLimit int `yaml:"replicaHistoryLimit,omitempty"`
+	// NamespaceConfigs lets replicas-controller-config ConfigMaps in workload namespaces overlay this config
+	NamespaceConfigs NamespaceConfigsConfig `yaml:"namespaceConfigs,omitempty"`
+	// ScaleBudget caps the scale operations and replicas changed across the cluster per window
+	ScaleBudget ScaleBudgetConfig `yaml:"scaleBudget,omitempty"`
+	// DecisionWebhook asks an external service to allow, deny or modify every replica change
+	DecisionWebhook DecisionWebhookConfig `yaml:"decisionWebhook,omitempty"`
+	// Endpoints secures the HTTP endpoints of the controller with client certificates and Kubernetes authorization
+	Endpoints EndpointsConfig `yaml:"endpoints,omitempty"`
+	// AllowedURLs are the URL prefixes, e.g. https://hooks.example.com/services/,
+	// the notification webhooks, HTTP probes, drain webhooks and OCI registries,
+	// with their redirects and token realms, declared by overrides may request.
+	// Other URLs are refused, so namespace users cannot make the controller
+	// call arbitrary in-cluster or cloud metadata endpoints.
+	AllowedURLs []string `yaml:"allowedURLs,omitempty"`
+}
+
+// ScaleBudgetConfig is the last safety valve against a config mistake
+// thrashing the cluster: once a limit is reached within the window, further
+// replica changes are deferred until older ones leave it. Restores of
+// original replicas (rollbacks, reverted verifications) are never deferred.
+type ScaleBudgetConfig struct {
+	// MaxOperations is the maximum number of replica changes per window, 0 for no limit
+	MaxOperations int32 `yaml:"maxOperations,omitempty"`
+	// MaxReplicasChanged is the maximum number of replicas added or removed per window, 0 for no limit
+	MaxReplicasChanged int32 `yaml:"maxReplicasChanged,omitempty"`
+	// Window is the sliding window the limits apply to (default 10m)
+	Window time.Duration `yaml:"window,omitempty"`
+}
+
+// Enabled returns true if any limit of the budget is set
+func (c ScaleBudgetConfig) Enabled() bool {
+	return c.MaxOperations > 0 || c.MaxReplicasChanged > 0
+}
+
+// GetWindow returns the budget window or its default
+func (c ScaleBudgetConfig) GetWindow() time.Duration {
+	if c.Window <= 0 {
+		return DefaultScaleBudgetWindow
+	}
+	return c.Window
+}
+
+// DefaultScaleBudgetWindow is the default window of the scale budget
+const DefaultScaleBudgetWindow = 10 * time.Minute
+
+// Authorization modes of EndpointsConfig
+const (
+	// EndpointAuthorizationNone serves every client the TLS handshake accepted
+	EndpointAuthorizationNone = "None"
+	// EndpointAuthorizationSubjectAccessReview requires RBAC on the path of the request
+	EndpointAuthorizationSubjectAccessReview = "SubjectAccessReview"
+)
+
+// EndpointsConfig secures the HTTP endpoints the controller serves, on the
+// metrics server (metrics, /simulate, /inventory, /explain) and the webhook server
+// (/external-data). It is read again on every connection and request, so
+// certificates rotated by cert-manager and changes of the config apply
+// without a restart.
+type EndpointsConfig struct {
+	// ClientCA is the path of the PEM CA bundle client certificates are
+	// verified against, e.g. the ca.crt of a mounted cert-manager Secret.
+	// A verified certificate authenticates its common name as user and its
+	// organizations as groups, like the API server does.
+	ClientCA string `yaml:"clientCA,omitempty"`
+	// RequireClientCert rejects clients without a certificate signed by ClientCA (mTLS)
+	RequireClientCert bool `yaml:"requireClientCert,omitempty"`
+	// Authorization is None (default) or SubjectAccessReview to require the
+	// user of the client certificate, or of the bearer token verified with a
+	// TokenReview, to be granted the verb of the request on its path
+	// (nonResourceURLs). Requests of the metrics server are always reviewed
+	// when it serves securely.
+	Authorization string `yaml:"authorization,omitempty"`
+}
+
+// Failure policies of DecisionWebhookConfig
+const (
+	// DecisionFailurePolicyFail defers the change when the webhook cannot be reached
+	DecisionFailurePolicyFail = "Fail"
+	// DecisionFailurePolicyIgnore applies the change when the webhook cannot be reached
+	DecisionFailurePolicyIgnore = "Ignore"
+)
+
+// DecisionWebhookConfig configures the external service called before each
+// replica change, centralizing custom guardrails outside the controller.
+// Restores of original replicas (rollbacks, reverted verifications) are never
+// submitted.
+type DecisionWebhookConfig struct {
+	// URL receives the proposed change as a JSON POST, disabled when empty
+	URL string `yaml:"url,omitempty"`
+	// TokenEnv is the environment variable holding a bearer token sent to the webhook
+	//
+	// Deprecated: use TokenSecretRef.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// TokenSecretRef holds the bearer token instead, read again when it is rotated
+	TokenSecretRef *SecretKeyRef `yaml:"tokenSecretRef,omitempty"`
+	// Timeout bounds a single call (default 5s)
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// FailurePolicy is Fail (default) to defer the change, or Ignore to apply
+	// it, when the webhook fails or times out
+	FailurePolicy string `yaml:"failurePolicy,omitempty"`
+}
+
+// Enabled returns true if a webhook URL is set
+func (c DecisionWebhookConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// GetTimeout returns the call timeout or its default
+func (c DecisionWebhookConfig) GetTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultDecisionWebhookTimeout
+	}
+	return c.Timeout
+}
+
+// DefaultDecisionWebhookTimeout is the default timeout of a decision webhook call
+const DefaultDecisionWebhookTimeout = 5 * time.Second
+
+// NamespaceConfigsConfig configures the per-namespace configuration overlays
+type NamespaceConfigsConfig struct {
+	// Enabled applies the replicas-controller-config ConfigMap of a namespace
+	// on top of the global config for its workloads, so namespace admins can
+	// tune their limits without cluster-level access
+	Enabled bool `yaml:"enabled"`
+}
+
+// GetScaleUpReadyTimeout returns the scale-up readiness timeout or its default
+func (c *GlobalConfig) GetScaleUpReadyTimeout() time.Duration {
+	if c.ScaleUpReadyTimeout <= 0 {
+		return DefaultScaleUpReadyTimeout
+	}
+	return c.ScaleUpReadyTimeout
+}
+
+// DefaultScaleUpReadyTimeout is the default time new replicas have to become Ready
+const DefaultScaleUpReadyTimeout = 10 * time.Minute
+
+// GetUnmatchedTargetsWarning returns the time an override may match nothing or its default
+func (c *GlobalConfig) GetUnmatchedTargetsWarning() time.Duration {
+	if c.UnmatchedTargetsWarning <= 0 {
+		return DefaultUnmatchedTargetsWarning
+	}
+	return c.UnmatchedTargetsWarning
+}
+
+// DefaultUnmatchedTargetsWarning is the default time an override may match nothing
+const DefaultUnmatchedTargetsWarning = time.Hour
+
+// GetReplicaHistoryLimit returns the replica changes kept per deployment, its
+// default when unset and bounded so the status stays small
+func (c *GlobalConfig) GetReplicaHistoryLimit() int {
+	if c.ReplicaHistoryLimit <= 0 {
+		return DefaultReplicaHistoryLimit
+	}
+	return min(c.ReplicaHistoryLimit, MaxReplicaHistoryLimit)
+}
+
+// DefaultReplicaHistoryLimit and MaxReplicaHistoryLimit bound the replica changes kept per deployment
+const (
+	DefaultReplicaHistoryLimit = 10
+	MaxReplicaHistoryLimit     = 50
+)
+
+// Policies of BaselineRefreshConfig, the values of the ReplicasOverride baselineRefresh policy
+const (
+	BaselineRefreshNever        = "Never"
+	BaselineRefreshOnSpecChange = "OnSpecChangeByOtherManager"
+	BaselineRefreshPeriodic     = "Periodic"
+)
+
+// BaselineRefreshConfig configures when the originals of targets are re-captured
+type BaselineRefreshConfig struct {
+	// Policy is Never (default), OnSpecChangeByOtherManager or Periodic
+	Policy string `yaml:"policy,omitempty"`
+	// IntervalDays is the minimum age of a baseline before Periodic refreshes it (default 30)
+	IntervalDays int32 `yaml:"intervalDays,omitempty"`
+}
+
+// GetInterval returns the Periodic refresh interval or its default
+func (c BaselineRefreshConfig) GetInterval() time.Duration {
+	if c.IntervalDays <= 0 {
+		return DefaultBaselineRefreshInterval
+	}
+	return time.Duration(c.IntervalDays) * 24 * time.Hour
+}
+
+// DefaultBaselineRefreshInterval is the default minimum age of a baseline refreshed periodically
+const DefaultBaselineRefreshInterval = 30 * 24 * time.Hour
+
+// StatefulSetConfig configures how StatefulSets are scaled
+type StatefulSetConfig struct {
+	// Enabled turns on StatefulSet scaling; StatefulSets are never touched otherwise
+	Enabled bool `yaml:"enabled"`
+	// StepOrderedReady moves OrderedReady StatefulSets one replica at a time,
+	// waiting for all pods to be ready before each step
+	StepOrderedReady bool `yaml:"stepOrderedReady,omitempty"`
+}
+
+// ScaleDownConfig configures how replicas are removed from Deployments
+type ScaleDownConfig struct {
+	// Stepped removes only as many replicas per step as the rolling update
+	// maxUnavailable and the PodDisruptionBudgets of the pods allow, so Ready
+	// capacity never drops below their contract
+	Stepped bool `yaml:"stepped,omitempty"`
+}
+
+// Replica change modes of RolloutConfig
+const (
+	RolloutChangeImmediate = "Immediate"
+	RolloutChangeWait      = "Wait"
+	RolloutChangeAtomic    = "Atomic"
+)
+
+// RolloutConfig configures how replica changes interact with the surge math
+// of a Deployment rolling out a new ReplicaSet
+type RolloutConfig struct {
+	// ReplicaChanges is Immediate (default), which changes replicas right away
+	// and steps scale-downs as configured, Wait, which holds changes until the
+	// rollout completed or exceeded its progress deadline, or Atomic, which
+	// applies the whole change in a single write without stepping so the
+	// Deployment controller splits it once between the old and new ReplicaSets
+	ReplicaChanges string `yaml:"replicaChanges,omitempty"`
 }
 
+// ErrorBudgetConfig configures where the error rates of services are read
+// after a scale-down
+type ErrorBudgetConfig struct {
+	// PrometheusURL is the base URL of the Prometheus HTTP API
+	PrometheusURL string `yaml:"prometheusURL,omitempty"`
+	// TokenEnv is the environment variable holding a bearer token for Prometheus
+	//
+	// Deprecated: use TokenSecretRef.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// TokenSecretRef holds the bearer token instead, read again when it is rotated
+	TokenSecretRef *SecretKeyRef `yaml:"tokenSecretRef,omitempty"`
+}
+
+// MetricsConfig configures the optional push-based metrics sinks
+type MetricsConfig struct {
+	// PushInterval is how often metrics are pushed to the configured sinks
+	PushInterval time.Duration `yaml:"pushInterval,omitempty"`
+	// StatsD enables pushing metrics to a StatsD or DogStatsD agent
+	StatsD *StatsDConfig `yaml:"statsd,omitempty"`
+	// OTLP enables pushing metrics to an OpenTelemetry collector
+	OTLP *OTLPConfig `yaml:"otlp,omitempty"`
+}
+
+// StatsDConfig configures the StatsD/DogStatsD sink
+type StatsDConfig struct {
+	// Address is the host:port of the StatsD agent (UDP)
+	Address string `yaml:"address"`
+	// Prefix is prepended to every metric name
+	Prefix string `yaml:"prefix,omitempty"`
+	// DogStatsD sends metric labels as DogStatsD tags instead of encoding them in the name
+	DogStatsD bool `yaml:"dogstatsd,omitempty"`
+	// Tags are extra tags added to every metric when DogStatsD is enabled (e.g. "env:prod")
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// OTLPConfig configures the OTLP/HTTP metrics sink
+type OTLPConfig struct {
+	// Endpoint is the collector URL, e.g. http://otel-collector:4318 (the /v1/metrics path is added if missing)
+	Endpoint string `yaml:"endpoint"`
+	// Headers are added to every export request
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// ResourceAttributes are added to the exported resource next to service.name
+	ResourceAttributes map[string]string `yaml:"resourceAttributes,omitempty"`
+}
+
+// CostConfig configures cost estimation of the current scaling state
+type CostConfig struct {
+	// PodHourlyCost is the default hourly price of one pod
+	PodHourlyCost float64 `yaml:"podHourlyCost,omitempty"`
+	// NamespacePodHourlyCost overrides the pod price per namespace
+	NamespacePodHourlyCost map[string]float64 `yaml:"namespacePodHourlyCost,omitempty"`
+	// Currency is reported next to every estimate (e.g. "USD")
+	Currency string `yaml:"currency,omitempty"`
+}
+
+// CarbonAwareConfig configures carbon-aware scaling of flexible workloads
+type CarbonAwareConfig struct {
+	// Enabled turns on carbon-aware scaling
+	Enabled bool `yaml:"enabled"`
+	// Provider is the intensity source: electricitymaps, http or static
+	Provider string `yaml:"provider"`
+	// URL overrides the provider endpoint (required for the http provider)
+	URL string `yaml:"url,omitempty"`
+	// Zone is the grid zone queried from Electricity Maps (e.g. "DE")
+	Zone string `yaml:"zone,omitempty"`
+	// Field is the dot-separated JSON path of the intensity for the http provider
+	Field string `yaml:"field,omitempty"`
+	// TokenEnv is the environment variable holding the API token
+	//
+	// Deprecated: use TokenSecretRef.
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+	// TokenSecretRef holds the API token instead, read again when it is rotated
+	TokenSecretRef *SecretKeyRef `yaml:"tokenSecretRef,omitempty"`
+	// StaticIntensity is the intensity reported by the static provider
+	StaticIntensity float64 `yaml:"staticIntensity,omitempty"`
+	// RefreshInterval is how often the intensity is polled (default 15m)
+	RefreshInterval time.Duration `yaml:"refreshInterval,omitempty"`
+	// HighIntensityThreshold in gCO2eq/kWh at or above which percentages are lowered
+	HighIntensityThreshold float64 `yaml:"highIntensityThreshold"`
+	// ReductionPercentage is how much the percentage is lowered, e.g. 30 turns 100% into 70%
+	ReductionPercentage int32 `yaml:"reductionPercentage"`
+	// MinPercentage is the lowest percentage carbon-aware scaling may apply
+	MinPercentage int32 `yaml:"minPercentage,omitempty"`
+	// FlexibleLabel is the workload label (set to "true") marking flexible workloads
+	FlexibleLabel string `yaml:"flexibleLabel,omitempty"`
+}
+
+// GetRefreshInterval returns the polling interval or its default
+func (c CarbonAwareConfig) GetRefreshInterval() time.Duration {
+	if c.RefreshInterval <= 0 {
+		return DefaultCarbonRefreshInterval
+	}
+	return c.RefreshInterval
+}
+
+// GetFlexibleLabel returns the flexible workload label or its default
+func (c CarbonAwareConfig) GetFlexibleLabel() string {
+	if c.FlexibleLabel == "" {
+		return DefaultCarbonFlexibleLabel
+	}
+	return c.FlexibleLabel
+}
+
+const (
+	// DefaultCarbonRefreshInterval is the default carbon intensity polling interval
+	DefaultCarbonRefreshInterval = 15 * time.Minute
+	// DefaultCarbonFlexibleLabel marks workloads that may be scaled down on high carbon intensity
+	DefaultCarbonFlexibleLabel = "kubedynamicscaler.io/carbon-flexible"
+)
+
+// NodeDisruptionConfig configures the capacity boost applied while nodes are
+// cordoned, drained or reclaimed (spot interruptions)
+type NodeDisruptionConfig struct {
+	// Enabled turns on the node disruption response
+	Enabled bool `yaml:"enabled"`
+	// BoostPercentage is added to the percentage of affected workloads, e.g. 20 turns 100% into 120%
+	BoostPercentage int32 `yaml:"boostPercentage"`
+	// RelaxHPAMaxOnly boosts only the maxReplicas of HPA-managed workloads, leaving minReplicas untouched
+	RelaxHPAMaxOnly bool `yaml:"relaxHPAMaxOnly,omitempty"`
+	// IgnoreCordon reacts only to interruption taints, not to plain cordons
+	IgnoreCordon bool `yaml:"ignoreCordon,omitempty"`
+	// TaintKeys are the node taints signalling an imminent interruption (defaults to common spot/autoscaler taints)
+	TaintKeys []string `yaml:"taintKeys,omitempty"`
+	// Cooldown is how long the boost is kept after the node is replaced or uncordoned (default 10m)
+	Cooldown time.Duration `yaml:"cooldown,omitempty"`
+}
+
+// GetTaintKeys returns the interruption taint keys or their defaults
+func (c NodeDisruptionConfig) GetTaintKeys() []string {
+	if len(c.TaintKeys) == 0 {
+		return DefaultNodeDisruptionTaintKeys
+	}
+	return c.TaintKeys
+}
+
+// GetCooldown returns the boost cooldown or its default
+func (c NodeDisruptionConfig) GetCooldown() time.Duration {
+	if c.Cooldown <= 0 {
+		return DefaultNodeDisruptionCooldown
+	}
+	return c.Cooldown
+}
+
+// DefaultNodeDisruptionCooldown is the default time the boost outlives the disruption
+const DefaultNodeDisruptionCooldown = 10 * time.Minute
+
+// DefaultNodeDisruptionTaintKeys are taints set by spot interruption handlers and node autoscalers
+var DefaultNodeDisruptionTaintKeys = []string{
+	"aws-node-termination-handler/spot-itn",
+	"aws-node-termination-handler/asg-lifecycle-termination",
+	"cloud.google.com/impending-node-termination",
+	"karpenter.sh/disrupted",
+	"ToBeDeletedByClusterAutoscaler",
+}
+
+// NodePressureConfig configures tiered downscaling while nodes report pressure,
+// pods are evicted or cluster allocation stays high
+type NodePressureConfig struct {
+	// Enabled turns on the node pressure trigger
+	Enabled bool `yaml:"enabled"`
+	// Tiers are shed in order, lowest priority first; each sustained pressure period sheds one more tier
+	Tiers []PressureTier `yaml:"tiers"`
+	// TierLabel is the workload label holding the tier name (default "kubedynamicscaler.io/tier")
+	TierLabel string `yaml:"tierLabel,omitempty"`
+	// HighAllocationThreshold is the requested/allocatable percentage at or above which the cluster is under pressure
+	HighAllocationThreshold float64 `yaml:"highAllocationThreshold"`
+	// LowAllocationThreshold is the percentage below which the cluster is considered relieved
+	LowAllocationThreshold float64 `yaml:"lowAllocationThreshold"`
+	// SustainFor is how long pressure must last before the next tier is shed (default 5m)
+	SustainFor time.Duration `yaml:"sustainFor,omitempty"`
+	// RecoverAfter is how long relief must last before the last shed tier is restored (default 10m)
+	RecoverAfter time.Duration `yaml:"recoverAfter,omitempty"`
+	// CheckInterval is how often cluster pressure is evaluated (default 1m)
+	CheckInterval time.Duration `yaml:"checkInterval,omitempty"`
+}
+
+// PressureTier is a group of workloads shed together under node pressure
+type PressureTier struct {
+	// Name matches the value of the tier label on workloads
+	Name string `yaml:"name"`
+	// ReductionPercentage is how much the percentage is lowered, e.g. 50 turns 100% into 50%
+	ReductionPercentage int32 `yaml:"reductionPercentage"`
+	// MinPercentage is the lowest percentage applied to the tier
+	MinPercentage int32 `yaml:"minPercentage,omitempty"`
+}
+
+// PriorityTierMapping assigns workloads to a shedding tier by the value of their PriorityClass
+type PriorityTierMapping struct {
+	// Tier is the name of a tier in NodePressureConfig.Tiers
+	Tier string `yaml:"tier"`
+	// MaxPriority is the highest PriorityClass value belonging to the tier
+	MaxPriority int32 `yaml:"maxPriority"`
+}
+
+// TierForPriority returns the tier of a workload with the given PriorityClass value,
+// the mapping with the lowest MaxPriority at or above it, or "" when none applies
+func (c *GlobalConfig) TierForPriority(priority int32) string {
+	tier, found := "", false
+	var bound int32
+	for _, mapping := range c.PriorityTiers {
+		if priority <= mapping.MaxPriority && (!found || mapping.MaxPriority < bound) {
+			tier, bound, found = mapping.Tier, mapping.MaxPriority, true
+		}
+	}
+	return tier
+}
+
+// GetTierLabel returns the tier label or its default
+func (c NodePressureConfig) GetTierLabel() string {
+	if c.TierLabel == "" {
+		return DefaultPressureTierLabel
+	}
+	return c.TierLabel
+}
+
+// GetSustainFor returns the escalation delay or its default
+func (c NodePressureConfig) GetSustainFor() time.Duration {
+	if c.SustainFor <= 0 {
+		return DefaultPressureSustainFor
+	}
+	return c.SustainFor
+}
+
+// GetRecoverAfter returns the recovery delay or its default
+func (c NodePressureConfig) GetRecoverAfter() time.Duration {
+	if c.RecoverAfter <= 0 {
+		return DefaultPressureRecoverAfter
+	}
+	return c.RecoverAfter
+}
+
+// GetCheckInterval returns the evaluation interval or its default
+func (c NodePressureConfig) GetCheckInterval() time.Duration {
+	if c.CheckInterval <= 0 {
+		return DefaultPressureCheckInterval
+	}
+	return c.CheckInterval
+}
+
+const (
+	// DefaultPressureTierLabel is the default workload label holding the tier name
+	DefaultPressureTierLabel = "kubedynamicscaler.io/tier"
+	// DefaultPressureSustainFor is the default time pressure must last before shedding a tier
+	DefaultPressureSustainFor = 5 * time.Minute
+	// DefaultPressureRecoverAfter is the default time relief must last before restoring a tier
+	DefaultPressureRecoverAfter = 10 * time.Minute
+	// DefaultPressureCheckInterval is the default cluster pressure evaluation interval
+	DefaultPressureCheckInterval = time.Minute
+)
+
+// ReportConfig configures the periodic scaling summary report
+type ReportConfig struct {
+	// Enabled turns on report generation
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often a report is published (default 24h)
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// ConfigMapName is the ConfigMap in the controller namespace holding the latest report
+	ConfigMapName string `yaml:"configMapName,omitempty"`
+	// WebhookURL optionally receives the report as a JSON payload
+	WebhookURL string `yaml:"webhookURL,omitempty"`
+}
+
+// GetInterval returns the report interval or its default
+func (c ReportConfig) GetInterval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultReportInterval
+	}
+	return c.Interval
+}
+
+// GetConfigMapName returns the report ConfigMap name or its default
+func (c ReportConfig) GetConfigMapName() string {
+	if c.ConfigMapName == "" {
+		return DefaultReportConfigMapName
+	}
+	return c.ConfigMapName
+}
+
+const (
+	// DefaultReportInterval is the default interval between scaling reports
+	DefaultReportInterval = 24 * time.Hour
+	// DefaultReportConfigMapName is the default name of the report ConfigMap
+	DefaultReportConfigMapName = "kubedynamicscaler-scaling-report"
+)
+
+// DefaultPushInterval is the default interval for push-based metrics sinks
+const DefaultPushInterval = 30 * time.Second
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *GlobalConfig {
 	return &GlobalConfig{
@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestTierForPriority(t *testing.T) {
+	cfg := &GlobalConfig{
+		PriorityTiers: []PriorityTierMapping{
+			{Tier: "standard", MaxPriority: 100000},
+			{Tier: "batch", MaxPriority: 0},
+		},
+	}
+
+	tests := []struct {
+		priority int32
+		want     string
+	}{
+		{priority: -10, want: "batch"},
+		{priority: 0, want: "batch"},
+		{priority: 1000, want: "standard"},
+		{priority: 100000, want: "standard"},
+		{priority: 2000000000, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.TierForPriority(tt.priority); got != tt.want {
+			t.Errorf("TierForPriority(%d) = %q, want %q", tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestURLAllowed(t *testing.T) {
+	cfg := &GlobalConfig{AllowedURLs: []string{
+		"https://hooks.example.com/services/",
+		"http://probe.shop.svc:8080",
+	}}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://hooks.example.com/services/T000/B000", want: true},
+		{url: "https://hooks.example.com/services", want: true},
+		{url: "https://hooks.example.com/servicesx", want: false},
+		{url: "https://hooks.example.com/other", want: false},
+		{url: "http://hooks.example.com/services/T000", want: false},
+		{url: "https://hooks.example.com@169.254.169.254/services/", want: false},
+		{url: "http://probe.shop.svc:8080/healthz", want: true},
+		{url: "http://probe.shop.svc/healthz", want: false},
+		{url: "file:///etc/passwd", want: false},
+		{url: "://invalid", want: false},
+	}
+
+	for _, tt := range tests {
+		if err := cfg.URLAllowed(tt.url); (err == nil) != tt.want {
+			t.Errorf("URLAllowed(%q) = %v, want allowed %v", tt.url, err, tt.want)
+		}
+	}
+
+	if err := (&GlobalConfig{}).URLAllowed("https://hooks.example.com/"); err == nil {
+		t.Error("URLAllowed() without allowedURLs = nil, want an error")
+	}
+}
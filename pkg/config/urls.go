@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// URLAllowed returns an error unless raw matches one of the allowedURLs
+// prefixes: same scheme and host, and a path under the prefix path. URLs
+// declared by overrides or their Secrets are requested by the controller
+// from inside the cluster, so they are denied when allowedURLs is empty.
+func (c *GlobalConfig) URLAllowed(raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("URL scheme %q is not http or https", target.Scheme)
+	}
+	for _, prefix := range c.AllowedURLs {
+		allowed, err := url.Parse(prefix)
+		if err != nil || allowed.Host == "" {
+			continue
+		}
+		if !strings.EqualFold(target.Scheme, allowed.Scheme) || !strings.EqualFold(target.Host, allowed.Host) {
+			continue
+		}
+		if pathUnder(target.EscapedPath(), allowed.EscapedPath()) {
+			return nil
+		}
+	}
+	return fmt.Errorf("URL %s is not in allowedURLs of the global config", redact(target))
+}
+
+// pathUnder returns true if path is prefix or one of its sub-paths
+func pathUnder(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// redact drops the query and credentials of a URL, which webhook URLs often carry
+func redact(u *url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+}
+
+// NewHTTPClient returns a client for the URLs checked by URLAllowed. It does
+// not follow redirects, which would send the request outside the allowlist;
+// redirect responses are returned as they are.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
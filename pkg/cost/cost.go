@@ -0,0 +1,54 @@
+package cost
+
+import (
+	"strconv"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// Estimator computes the cost impact of scaling decisions from the static
+// per-pod prices configured in the global config
+type Estimator struct {
+	cfg config.CostConfig
+}
+
+// NewEstimator creates an estimator from the cost configuration
+func NewEstimator(cfg config.CostConfig) Estimator {
+	return Estimator{cfg: cfg}
+}
+
+// Enabled returns true when at least one price is configured
+func (e Estimator) Enabled() bool {
+	return e.cfg.PodHourlyCost > 0 || len(e.cfg.NamespacePodHourlyCost) > 0
+}
+
+// Currency returns the configured currency
+func (e Estimator) Currency() string {
+	return e.cfg.Currency
+}
+
+// PodHourlyCost returns the hourly price of one pod of a workload. The workload
+// annotation wins over the namespace price, which wins over the global price.
+func (e Estimator) PodHourlyCost(namespace string, annotations map[string]string) float64 {
+	if val, exists := annotations[utils.PodHourlyCostAnnotation]; exists {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	if price, exists := e.cfg.NamespacePodHourlyCost[namespace]; exists {
+		return price
+	}
+	return e.cfg.PodHourlyCost
+}
+
+// HourlyDelta returns the estimated hourly cost difference between running
+// current replicas and the original replicas of a workload
+func (e Estimator) HourlyDelta(namespace string, annotations map[string]string, original, current int32) float64 {
+	return float64(current-original) * e.PodHourlyCost(namespace, annotations)
+}
+
+// FormatAmount formats a cost with two decimals for status fields and annotations
+func FormatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,62 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func TestHourlyDeltaPricePrecedence(t *testing.T) {
+	estimator := NewEstimator(config.CostConfig{
+		PodHourlyCost:          0.10,
+		NamespacePodHourlyCost: map[string]float64{"gpu": 2.50},
+		Currency:               "USD",
+	})
+
+	tests := []struct {
+		name        string
+		namespace   string
+		annotations map[string]string
+		original    int32
+		current     int32
+		want        float64
+	}{
+		{name: "global price scale up", namespace: "shop", original: 4, current: 8, want: 0.40},
+		{name: "namespace price scale down", namespace: "gpu", original: 4, current: 2, want: -5.00},
+		{
+			name:        "annotation wins",
+			namespace:   "gpu",
+			annotations: map[string]string{utils.PodHourlyCostAnnotation: "1"},
+			original:    2,
+			current:     5,
+			want:        3,
+		},
+		{
+			name:        "invalid annotation falls back",
+			namespace:   "shop",
+			annotations: map[string]string{utils.PodHourlyCostAnnotation: "cheap"},
+			original:    2,
+			current:     3,
+			want:        0.10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimator.HourlyDelta(tt.namespace, tt.annotations, tt.original, tt.current)
+			if FormatAmount(got) != FormatAmount(tt.want) {
+				t.Errorf("HourlyDelta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	if NewEstimator(config.CostConfig{}).Enabled() {
+		t.Error("estimator without prices should be disabled")
+	}
+	if !NewEstimator(config.CostConfig{NamespacePodHourlyCost: map[string]float64{"a": 1}}).Enabled() {
+		t.Error("estimator with a namespace price should be enabled")
+	}
+}
@@ -0,0 +1,161 @@
+// Package decision computes the percentage and replicas of a workload through
+// a chain of named stages, e.g. schedule, trigger, boost and clamps, so new
+// constraints are inserted between them instead of rewriting the callers.
+// Every stage changing the decision is recorded in the explanation.
+package decision
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+)
+
+// Names of the stages of the controller, in order
+const (
+	// StageSchedule resolves the layers, including the active namespace schedule
+	StageSchedule = "schedule"
+	// StageTrigger applies the triggers and the percentage expression of the override
+	StageTrigger = "trigger"
+	// StageCarbon lowers flexible workloads while grid carbon intensity is high
+	StageCarbon = "carbon"
+	// StagePressure sheds low-priority tiers while nodes are under pressure
+	StagePressure = "pressure"
+	// StageBoost adds the node disruption and warm-up boosts
+	StageBoost = "boost"
+	// StageReplicas turns the percentage into replicas, headroom included
+	StageReplicas = "replicas"
+	// StageRatio caps the replicas at the ratio of the deployment the workload depends on
+	StageRatio = "ratio"
+	// StagePolicyClamp bounds the replicas to the min/max replicas of the config
+	StagePolicyClamp = "policy-clamp"
+	// StageQuotaClamp caps a scale-up at the pods the ResourceQuotas of the namespace admit
+	StageQuotaClamp = "quota-clamp"
+)
+
+// Input is the workload a decision is made for
+type Input struct {
+	Workload metav1.Object
+	// Template is the pod template of the workload, empty when it has none
+	Template *corev1.PodTemplateSpec
+	// Override is the override targeting the workload, nil for the global config
+	Override *dynamicscalingv1.ReplicasOverride
+	// Config is the global config layered with the defaults of the namespace
+	Config *config.GlobalConfig
+	// Original is the replica count the percentage applies to, the current
+	// replicas for an override only enforcing bounds
+	Original int32
+	// Current is the replica count of the workload now, scale-ups start from it
+	Current int32
+}
+
+// Stage is a step of the chain refining the explanation of the previous ones
+type Stage struct {
+	Name string
+	// Direct stages only run for workloads whose replicas are scaled directly,
+	// once the percentage is known; the others also decide HPA limits
+	Direct bool
+	Decide func(ctx context.Context, in *Input, e *precedence.Explanation)
+}
+
+// Chain runs its stages in order
+type Chain struct {
+	stages []Stage
+}
+
+// NewChain returns a chain running stages in the given order
+func NewChain(stages ...Stage) *Chain {
+	return &Chain{stages: append([]Stage(nil), stages...)}
+}
+
+// Names returns the names of the stages in order
+func (c *Chain) Names() []string {
+	names := make([]string, 0, len(c.stages))
+	for _, stage := range c.stages {
+		names = append(names, stage.Name)
+	}
+	return names
+}
+
+// Register appends stage to the chain
+func (c *Chain) Register(stage Stage) {
+	c.stages = append(c.stages, stage)
+}
+
+// InsertBefore inserts stage before the stage called name
+func (c *Chain) InsertBefore(name string, stage Stage) error {
+	i, err := c.index(name)
+	if err != nil {
+		return err
+	}
+	c.stages = append(c.stages[:i], append([]Stage{stage}, c.stages[i:]...)...)
+	return nil
+}
+
+// InsertAfter inserts stage after the stage called name
+func (c *Chain) InsertAfter(name string, stage Stage) error {
+	i, err := c.index(name)
+	if err != nil {
+		return err
+	}
+	c.stages = append(c.stages[:i+1], append([]Stage{stage}, c.stages[i+1:]...)...)
+	return nil
+}
+
+// index returns the position of the stage called name
+func (c *Chain) index(name string) (int, error) {
+	for i, stage := range c.stages {
+		if stage.Name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no decision stage %q", name)
+}
+
+// Percentage runs the stages deciding the percentage, skipping direct ones
+func (c *Chain) Percentage(ctx context.Context, in *Input) precedence.Explanation {
+	return c.run(ctx, in, false)
+}
+
+// Decide runs every stage
+func (c *Chain) Decide(ctx context.Context, in *Input) precedence.Explanation {
+	return c.run(ctx, in, true)
+}
+
+// run runs the stages, recording those changing the percentage or the replicas
+func (c *Chain) run(ctx context.Context, in *Input, direct bool) precedence.Explanation {
+	var e precedence.Explanation
+	var results []precedence.StageResult
+	for _, stage := range c.stages {
+		if stage.Direct && !direct {
+			continue
+		}
+		percentage, replicas := e.Percentage, copyReplicas(e.Replicas)
+		stage.Decide(ctx, in, &e)
+		if e.Percentage != percentage || !sameReplicas(e.Replicas, replicas) {
+			results = append(results, precedence.StageResult{Stage: stage.Name, Percentage: e.Percentage, Replicas: copyReplicas(e.Replicas)})
+		}
+	}
+	e.Stages = results
+	return e
+}
+
+func sameReplicas(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func copyReplicas(replicas *int32) *int32 {
+	if replicas == nil {
+		return nil
+	}
+	value := *replicas
+	return &value
+}
@@ -0,0 +1,68 @@
+package decision
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/precedence"
+)
+
+// set returns a stage setting the percentage
+func set(name string, percentage int32) Stage {
+	return Stage{Name: name, Decide: func(_ context.Context, _ *Input, e *precedence.Explanation) {
+		e.Percentage = percentage
+	}}
+}
+
+// replicas returns a direct stage applying the percentage to the original replicas
+func replicas(name string) Stage {
+	return Stage{Name: name, Direct: true, Decide: func(_ context.Context, in *Input, e *precedence.Explanation) {
+		e.SetReplicas(in.Original*e.Percentage/100, false)
+	}}
+}
+
+func TestChainRegistration(t *testing.T) {
+	chain := NewChain(set(StageSchedule, 100), replicas(StageReplicas))
+	chain.Register(set("last", 100))
+	if err := chain.InsertBefore(StageReplicas, set("before", 100)); err != nil {
+		t.Fatalf("InsertBefore() failed: %v", err)
+	}
+	if err := chain.InsertAfter(StageSchedule, set("after", 100)); err != nil {
+		t.Fatalf("InsertAfter() failed: %v", err)
+	}
+	if err := chain.InsertBefore("missing", set("orphan", 100)); err == nil {
+		t.Error("InsertBefore() of a missing stage succeeded")
+	}
+
+	want := []string{StageSchedule, "after", "before", StageReplicas, "last"}
+	if got := chain.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestChainRecordsContributions(t *testing.T) {
+	chain := NewChain(set(StageSchedule, 150), set(StageTrigger, 150), replicas(StageReplicas), set("late", 50))
+	in := &Input{Original: 4}
+
+	e := chain.Decide(context.Background(), in)
+	if e.Percentage != 50 || e.Replicas == nil || *e.Replicas != 6 {
+		t.Fatalf("Decide() = %d%% with %v replicas, want 50%% with 6", e.Percentage, e.Replicas)
+	}
+	// The trigger stage changed nothing
+	var stages []string
+	for _, result := range e.Stages {
+		stages = append(stages, result.Stage)
+	}
+	if want := []string{StageSchedule, StageReplicas, "late"}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("Decide() recorded stages %v, want %v", stages, want)
+	}
+	if replicas := e.Stages[1].Replicas; replicas == nil || *replicas != 6 {
+		t.Errorf("replicas stage recorded %v replicas, want 6", replicas)
+	}
+
+	e = chain.Percentage(context.Background(), in)
+	if e.Percentage != 50 || e.Replicas != nil {
+		t.Errorf("Percentage() = %d%% with %v replicas, want 50%% without running direct stages", e.Percentage, e.Replicas)
+	}
+}
@@ -0,0 +1,138 @@
+package disruption
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Tracker remembers which workloads had pods on disrupted nodes (cordoned,
+// drained or about to be reclaimed) so their capacity can be boosted until the
+// nodes are replaced. It is safe for concurrent use; a nil Tracker reports no disruptions.
+type Tracker struct {
+	mutex sync.RWMutex
+	// nodes maps a disrupted node to the workloads that had pods on it
+	nodes map[string]*nodeDisruption
+	// changes notifies watchers that the set of boosted workloads changed
+	changes chan event.GenericEvent
+	now     func() time.Time
+}
+
+// nodeDisruption records one disrupted node
+type nodeDisruption struct {
+	workloads  map[types.NamespacedName]bool
+	releasedAt time.Time
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{
+		nodes:   make(map[string]*nodeDisruption),
+		changes: make(chan event.GenericEvent, 100),
+		now:     time.Now,
+	}
+}
+
+// Changes returns the channel notified when boosted workloads change
+func (t *Tracker) Changes() chan event.GenericEvent {
+	return t.changes
+}
+
+// MarkDisrupted records that node is disrupted and hosted the given workloads.
+// Workloads already recorded for the node are kept, since drained pods disappear.
+// It returns true if the set of boosted workloads changed.
+func (t *Tracker) MarkDisrupted(node string, workloads []types.NamespacedName) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	disruption, exists := t.nodes[node]
+	if !exists {
+		disruption = &nodeDisruption{workloads: make(map[types.NamespacedName]bool)}
+		t.nodes[node] = disruption
+	}
+	changed := !exists || !disruption.releasedAt.IsZero()
+	disruption.releasedAt = time.Time{}
+	for _, workload := range workloads {
+		if !disruption.workloads[workload] {
+			disruption.workloads[workload] = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// MarkReleased records that node is no longer disrupted (uncordoned or replaced).
+// The boost is kept for the cooldown so replacement capacity can become ready.
+func (t *Tracker) MarkReleased(node string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	disruption, exists := t.nodes[node]
+	if !exists || !disruption.releasedAt.IsZero() {
+		return false
+	}
+	disruption.releasedAt = t.now()
+	return true
+}
+
+// Expire forgets released nodes whose cooldown has passed and returns true if any was removed
+func (t *Tracker) Expire(cooldown time.Duration) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	expired := false
+	for node, disruption := range t.nodes {
+		if !disruption.releasedAt.IsZero() && t.now().Sub(disruption.releasedAt) >= cooldown {
+			delete(t.nodes, node)
+			expired = true
+		}
+	}
+	return expired
+}
+
+// ForgetNamespace drops the workloads of a deleted namespace and returns true if any was recorded
+func (t *Tracker) ForgetNamespace(namespace string) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	forgotten := false
+	for _, disruption := range t.nodes {
+		for workload := range disruption.workloads {
+			if workload.Namespace == namespace {
+				delete(disruption.workloads, workload)
+				forgotten = true
+			}
+		}
+	}
+	return forgotten
+}
+
+// IsAffected returns true if the workload had pods on a node that is disrupted
+// or still within its cooldown
+func (t *Tracker) IsAffected(workload types.NamespacedName) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	for _, disruption := range t.nodes {
+		if disruption.workloads[workload] {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify signals watchers without blocking when the channel is full
+func (t *Tracker) Notify(evt event.GenericEvent) {
+	select {
+	case t.changes <- evt:
+	default:
+	}
+}
@@ -0,0 +1,69 @@
+package disruption
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestTrackerLifecycle(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+
+	web := types.NamespacedName{Namespace: "shop", Name: "web"}
+	api := types.NamespacedName{Namespace: "shop", Name: "api"}
+
+	if !tracker.MarkDisrupted("node-a", []types.NamespacedName{web}) {
+		t.Fatal("first disruption should report a change")
+	}
+	// Pods are evicted while draining; the workload must stay recorded
+	if tracker.MarkDisrupted("node-a", nil) {
+		t.Error("repeated disruption without new workloads should not report a change")
+	}
+	if !tracker.IsAffected(web) || tracker.IsAffected(api) {
+		t.Fatalf("IsAffected(web, api) = (%v, %v), want (true, false)", tracker.IsAffected(web), tracker.IsAffected(api))
+	}
+
+	if !tracker.MarkReleased("node-a") {
+		t.Fatal("release should report a change")
+	}
+	if tracker.MarkReleased("node-a") {
+		t.Error("second release should not report a change")
+	}
+
+	now = now.Add(5 * time.Minute)
+	if tracker.Expire(10*time.Minute) || !tracker.IsAffected(web) {
+		t.Error("workload should stay boosted during the cooldown")
+	}
+
+	now = now.Add(5 * time.Minute)
+	if !tracker.Expire(10*time.Minute) || tracker.IsAffected(web) {
+		t.Error("workload should no longer be boosted after the cooldown")
+	}
+}
+
+func TestTrackerForgetNamespace(t *testing.T) {
+	tracker := NewTracker()
+	web := types.NamespacedName{Namespace: "shop", Name: "web"}
+	job := types.NamespacedName{Namespace: "batch", Name: "job"}
+	tracker.MarkDisrupted("node-a", []types.NamespacedName{web, job})
+
+	if !tracker.ForgetNamespace("shop") {
+		t.Fatal("forgetting a namespace with disrupted workloads should report a change")
+	}
+	if tracker.IsAffected(web) || !tracker.IsAffected(job) {
+		t.Errorf("IsAffected(web, job) = (%v, %v), want (false, true)", tracker.IsAffected(web), tracker.IsAffected(job))
+	}
+	if tracker.ForgetNamespace("shop") {
+		t.Error("forgetting the namespace again should not report a change")
+	}
+}
+
+func TestNilTrackerIsNotAffected(t *testing.T) {
+	var tracker *Tracker
+	if tracker.IsAffected(types.NamespacedName{Namespace: "shop", Name: "web"}) {
+		t.Error("nil tracker should report no disruption")
+	}
+}
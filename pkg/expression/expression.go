@@ -0,0 +1,168 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+const (
+	// CostLimit bounds the runtime cost of a single evaluation so an expression
+	// can never stall reconciliation
+	CostLimit = 10000
+
+	// MaxPercentage is the highest percentage an expression may return, as for replicasPercentage
+	MaxPercentage = 1000
+
+	// maxPrograms bounds the compiled program cache
+	maxPrograms = 512
+)
+
+// Variables are the values an expression can read
+type Variables struct {
+	// Now is the evaluation time, available as `now`
+	Now time.Time
+	// Percentage is the replicasPercentage of the override, available as `percentage`
+	Percentage int32
+	// Namespace and Name identify the workload, available as `namespace` and `name`
+	Namespace string
+	Name      string
+	// Labels and Annotations of the workload, available as `labels` and `annotations`
+	Labels      map[string]string
+	Annotations map[string]string
+	// Triggers are the latest values of the triggers mapped to the override, by trigger name
+	Triggers map[string]float64
+}
+
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	mutex    sync.Mutex
+	programs = make(map[string]cel.Program)
+)
+
+// newEnv declares the expression variables and the hour/minute/weekday helpers.
+// The helpers use UTC, CEL's getHours("Europe/Berlin") etc. accept a time zone.
+func newEnv() (*cel.Env, error) {
+	timePart := func(name string, part func(time.Time) int) cel.EnvOption {
+		return cel.Function(name, cel.Overload(name+"_timestamp", []*cel.Type{cel.TimestampType}, cel.IntType,
+			cel.UnaryBinding(func(value ref.Val) ref.Val {
+				ts, ok := value.(types.Timestamp)
+				if !ok {
+					return types.MaybeNoSuchOverloadErr(value)
+				}
+				return types.Int(part(ts.Time.UTC()))
+			})))
+	}
+
+	return cel.NewEnv(
+		cel.Variable("now", cel.TimestampType),
+		cel.Variable("percentage", cel.IntType),
+		cel.Variable("namespace", cel.StringType),
+		cel.Variable("name", cel.StringType),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("annotations", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("triggers", cel.MapType(cel.StringType, cel.DoubleType)),
+		timePart("hour", time.Time.Hour),
+		timePart("minute", time.Time.Minute),
+		timePart("weekday", func(t time.Time) int { return int(t.Weekday()) }),
+	)
+}
+
+// Compile checks that expr is a valid expression returning a number
+func Compile(expr string) error {
+	_, err := program(expr)
+	return err
+}
+
+// Evaluate returns the percentage computed by expr, bounded to [0, MaxPercentage]
+func Evaluate(expr string, vars Variables) (int32, error) {
+	prg, err := program(expr)
+	if err != nil {
+		return 0, err
+	}
+
+	activation := map[string]any{
+		"now":         vars.Now,
+		"percentage":  int64(vars.Percentage),
+		"namespace":   vars.Namespace,
+		"name":        vars.Name,
+		"labels":      orEmpty(vars.Labels),
+		"annotations": orEmpty(vars.Annotations),
+		"triggers":    vars.Triggers,
+	}
+	if vars.Triggers == nil {
+		activation["triggers"] = map[string]float64{}
+	}
+
+	out, _, err := prg.Eval(activation)
+	if err != nil {
+		return 0, fmt.Errorf("failed to evaluate expression: %w", err)
+	}
+
+	var result float64
+	switch value := out.Value().(type) {
+	case int64:
+		result = float64(value)
+	case uint64:
+		result = float64(value)
+	case float64:
+		result = value
+	default:
+		return 0, fmt.Errorf("expression returned %s, want a number", out.Type().TypeName())
+	}
+	if math.IsNaN(result) {
+		return 0, fmt.Errorf("expression returned NaN")
+	}
+	return int32(math.Round(math.Max(0, math.Min(MaxPercentage, result)))), nil
+}
+
+// program returns the compiled program of expr, compiling it on first use
+func program(expr string) (cel.Program, error) {
+	envOnce.Do(func() { env, envErr = newEnv() })
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if prg, ok := programs[expr]; ok {
+		return prg, nil
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression: %w", issues.Err())
+	}
+	switch ast.OutputType() {
+	case cel.IntType, cel.UintType, cel.DoubleType, cel.DynType:
+	default:
+		return nil, fmt.Errorf("expression returns %s, want a number", ast.OutputType())
+	}
+	prg, err := env.Program(ast, cel.CostLimit(CostLimit), cel.InterruptCheckFrequency(100))
+	if err != nil {
+		return nil, err
+	}
+
+	// Overrides rarely change their expression, start over rather than track usage
+	if len(programs) >= maxPrograms {
+		programs = make(map[string]cel.Program)
+	}
+	programs[expr] = prg
+	return prg, nil
+}
+
+// orEmpty returns m, or an empty map when m is nil
+func orEmpty(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
@@ -0,0 +1,59 @@
+package expression
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	morning := time.Date(2025, 11, 28, 6, 30, 0, 0, time.UTC) // a Friday
+	vars := Variables{
+		Now:        morning,
+		Percentage: 150,
+		Namespace:  "shop",
+		Name:       "web",
+		Labels:     map[string]string{"tier": "frontend"},
+		Triggers:   map[string]float64{"orders-lag": 12000},
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		vars    Variables
+		want    int32
+		wantErr string
+	}{
+		{name: "time of day", expr: "hour(now) < 8 ? 50 : 100", vars: vars, want: 50},
+		{name: "weekday", expr: "weekday(now) == 5 ? 200 : 100", vars: vars, want: 200},
+		{name: "time zone getter", expr: `now.getHours("Asia/Tokyo") >= 12 ? 120 : 80`, vars: vars, want: 120},
+		{name: "labels", expr: `labels.tier == "frontend" ? percentage : 100`, vars: vars, want: 150},
+		{name: "missing label", expr: `"team" in labels ? 10 : 90`, vars: vars, want: 90},
+		{name: "trigger values", expr: `"orders-lag" in triggers && triggers["orders-lag"] > 10000.0 ? 300 : percentage`, vars: vars, want: 300},
+		{name: "double result is rounded", expr: "double(percentage) * 0.333", vars: vars, want: 50},
+		{name: "bounded to the maximum", expr: "100000", vars: vars, want: MaxPercentage},
+		{name: "bounded to zero", expr: "-5", vars: vars, want: 0},
+		{name: "nil maps are empty", expr: "size(labels) + size(triggers)", vars: Variables{Now: morning}, want: 0},
+		{name: "not a number", expr: `"fifty"`, vars: vars, wantErr: "want a number"},
+		{name: "syntax error", expr: "hour(now) <", vars: vars, wantErr: "invalid expression"},
+		{name: "cost limit", expr: "[1,2,3,4,5,6,7,8,9,10].all(a, [1,2,3,4,5,6,7,8,9,10].all(b, [1,2,3,4,5,6,7,8,9,10].all(c, [1,2,3,4,5,6,7,8,9,10].all(d, a+b+c+d > 0)))) ? 1 : 0", vars: vars, wantErr: "cost limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(tt.expr, tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Evaluate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Evaluate() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Evaluate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package failure classifies the errors of the controller into a small set of
+// typed reasons, reused as the reason of status conditions, scaling and
+// notification events and the reason label of reconcile_errors_total, so
+// dashboards and alerts can tell transient noise from problems needing an
+// operator.
+package failure
+
+import (
+	"errors"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Reason is the class of a failure
+type Reason string
+
+// Failure reasons
+const (
+	// TargetNotFound is a target or object referenced that does not exist (anymore)
+	TargetNotFound Reason = "TargetNotFound"
+	// Conflict is a write racing another writer, retried on the next pass
+	Conflict Reason = "Conflict"
+	// Forbidden is a write or read the controller is not allowed to make, by
+	// RBAC, an admission webhook or the decision webhook
+	Forbidden Reason = "Forbidden"
+	// QuotaExceeded is a write rejected by a ResourceQuota of the namespace
+	QuotaExceeded Reason = "QuotaExceeded"
+	// CapacityExceeded is a change beyond what the controller or the cluster
+	// may absorb, e.g. an exhausted scale budget
+	CapacityExceeded Reason = "CapacityExceeded"
+	// InvalidConfig is a configuration, object or annotation the controller cannot use
+	InvalidConfig Reason = "InvalidConfig"
+	// Unavailable is the API server or an integration timing out or throttling
+	Unavailable Reason = "Unavailable"
+	// Unknown is any other failure
+	Unknown Reason = "Unknown"
+)
+
+// Error is an error of a known reason
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+// Error implements error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the classified error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New returns an error of reason with message
+func New(reason Reason, message string) error {
+	return &Error{Reason: reason, Err: errors.New(message)}
+}
+
+// Wrap classifies err as reason, nil if err is nil
+func Wrap(reason Reason, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Reason: reason, Err: err}
+}
+
+// Classify returns the reason of err, empty when err is nil. Errors wrapped
+// with a reason keep it, API errors are classified by status.
+func Classify(err error) Reason {
+	var classified *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &classified):
+		return classified.Reason
+	case apierrors.IsNotFound(err) || apierrors.IsGone(err):
+		return TargetNotFound
+	case apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err):
+		return Conflict
+	case apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota"):
+		return QuotaExceeded
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		return Forbidden
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		return InvalidConfig
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsUnexpectedServerError(err):
+		return Unavailable
+	}
+	return Unknown
+}
@@ -0,0 +1,41 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want Reason
+	}{
+		{name: "no error", err: nil, want: ""},
+		{name: "not found", err: apierrors.NewNotFound(deployments, "api"), want: TargetNotFound},
+		{name: "conflict", err: apierrors.NewConflict(deployments, "api", errors.New("the object has been modified")), want: Conflict},
+		{name: "forbidden", err: apierrors.NewForbidden(deployments, "api", errors.New("RBAC denied")), want: Forbidden},
+		{name: "quota", err: apierrors.NewForbidden(deployments, "api", errors.New("exceeded quota: compute")), want: QuotaExceeded},
+		{name: "invalid", err: apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "api", nil), want: InvalidConfig},
+		{name: "throttled", err: apierrors.NewTooManyRequests("slow down", 1), want: Unavailable},
+		{name: "wrapped API error", err: fmt.Errorf("failed to update: %w", apierrors.NewNotFound(deployments, "api")), want: TargetNotFound},
+		{name: "classified", err: New(CapacityExceeded, "scale budget exhausted"), want: CapacityExceeded},
+		{name: "classified and wrapped", err: fmt.Errorf("deferred: %w", Wrap(InvalidConfig, errors.New("bad annotation"))), want: InvalidConfig},
+		{name: "other", err: errors.New("boom"), want: Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if Wrap(Conflict, nil) != nil {
+		t.Error("Wrap(nil) returned an error")
+	}
+}
@@ -0,0 +1,226 @@
+// Package httpauth authenticates and authorizes the requests to the HTTP
+// endpoints the controller serves, by client certificate (mTLS, e.g. issued by
+// cert-manager) or bearer token verified with a TokenReview, and authorizes
+// them with a SubjectAccessReview on the path of the request, as configured by
+// the endpoints section of the global config. Every new endpoint is wrapped by
+// a Guard.
+package httpauth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// Guard protects HTTP endpoints as configured by the endpoints section of the
+// global config. Its fields may be set after the servers were configured, but
+// before they start.
+type Guard struct {
+	// Client creates the TokenReviews and SubjectAccessReviews
+	Client client.Client
+	// Config holds the endpoints config, none applies when nil
+	Config *config.Manager
+
+	mu sync.Mutex
+	// caPath, caModTime and caPEM cache the last client CA bundle read
+	caPath    string
+	caModTime time.Time
+	caPEM     []byte
+}
+
+// endpoints returns the current endpoints config
+func (g *Guard) endpoints() config.EndpointsConfig {
+	if g.Config == nil {
+		return config.EndpointsConfig{}
+	}
+	cfg := g.Config.GetConfig()
+	if cfg == nil {
+		return config.EndpointsConfig{}
+	}
+	return cfg.Endpoints
+}
+
+// TLSOption makes a server verify the client certificates against the
+// clientCA of the endpoints config, in addition to the client CAs it already
+// trusts. The bundle is read again when it changed on disk.
+func (g *Guard) TLSOption(base *tls.Config) {
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		endpoints := g.endpoints()
+		if endpoints.ClientCA == "" {
+			// Serve with the base config
+			return nil, nil
+		}
+		caPEM, err := g.clientCA(endpoints.ClientCA)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		if cfg.ClientCAs == nil {
+			cfg.ClientCAs = x509.NewCertPool()
+		} else {
+			cfg.ClientCAs = cfg.ClientCAs.Clone()
+		}
+		if !cfg.ClientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificate found in client CA %s", endpoints.ClientCA)
+		}
+		clientAuth := tls.VerifyClientCertIfGiven
+		if endpoints.RequireClientCert {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+		cfg.ClientAuth = max(cfg.ClientAuth, clientAuth)
+		return cfg, nil
+	}
+}
+
+// clientCA returns the PEM bundle at path, read again when it changed on disk
+func (g *Guard) clientCA(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if path == g.caPath && info.ModTime().Equal(g.caModTime) {
+		return g.caPEM, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+	g.caPath, g.caModTime, g.caPEM = path, info.ModTime(), data
+	return data, nil
+}
+
+// CertificateUser returns the user authenticated by the verified client
+// certificate of req, nil if it has none: its common name is the user name
+// and its organizations the groups
+func CertificateUser(req *http.Request) *authenticationv1.UserInfo {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	leaf := req.TLS.VerifiedChains[0][0]
+	return &authenticationv1.UserInfo{Username: leaf.Subject.CommonName, Groups: leaf.Subject.Organization}
+}
+
+// Handler serves next to the requests the endpoints config lets through
+func (g *Guard) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		endpoints := g.endpoints()
+		user := CertificateUser(req)
+		if user == nil && endpoints.RequireClientCert {
+			http.Error(w, "a client certificate is required", http.StatusUnauthorized)
+			return
+		}
+		if endpoints.Authorization != config.EndpointAuthorizationSubjectAccessReview {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if user == nil {
+			var err error
+			if user, err = g.tokenUser(req.Context(), req); err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		g.serveAuthorized(w, req, user, next)
+	})
+}
+
+// CertificateFilter wraps the filter of the metrics server, which only
+// authenticates bearer tokens, so requests with a verified client certificate
+// are authorized for its user instead
+func (g *Guard) CertificateFilter(byToken metricsserver.Filter) metricsserver.Filter {
+	return func(log logr.Logger, next http.Handler) (http.Handler, error) {
+		tokenHandler, err := byToken(log, next)
+		if err != nil {
+			return nil, err
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			user := CertificateUser(req)
+			if user == nil {
+				if g.endpoints().RequireClientCert {
+					http.Error(w, "a client certificate is required", http.StatusUnauthorized)
+					return
+				}
+				tokenHandler.ServeHTTP(w, req)
+				return
+			}
+			g.serveAuthorized(w, req, user, next)
+		}), nil
+	}
+}
+
+// serveAuthorized serves next to req if user is granted its verb on its path
+func (g *Guard) serveAuthorized(w http.ResponseWriter, req *http.Request, user *authenticationv1.UserInfo, next http.Handler) {
+	allowed, reason, err := g.authorize(req.Context(), req, user)
+	if err != nil {
+		http.Error(w, "authorization failed", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		message := fmt.Sprintf("%s may not %s %s", user.Username, strings.ToLower(req.Method), req.URL.Path)
+		if reason != "" {
+			message += ": " + reason
+		}
+		http.Error(w, message, http.StatusForbidden)
+		return
+	}
+	next.ServeHTTP(w, req)
+}
+
+// tokenUser authenticates the bearer token of req with a TokenReview
+func (g *Guard) tokenUser(ctx context.Context, req *http.Request) (*authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("a client certificate or bearer token is required")
+	}
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(token)}}
+	if err := g.Client.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, fmt.Errorf("invalid bearer token")
+	}
+	return &review.Status.User, nil
+}
+
+// authorize reviews whether user may use the verb of req, its lowercase
+// method, on its path
+func (g *Guard) authorize(ctx context.Context, req *http.Request, user *authenticationv1.UserInfo) (bool, string, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: req.URL.Path,
+			Verb: strings.ToLower(req.Method),
+		},
+	}}
+	if err := g.Client.Create(ctx, review); err != nil {
+		return false, "", err
+	}
+	return review.Status.Allowed, review.Status.Reason, nil
+}
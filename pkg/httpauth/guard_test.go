@@ -0,0 +1,154 @@
+package httpauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// reviewingClient authenticates the token "alice-token" as alice and only
+// grants alice and the gatekeeper group a post on /external-data
+func reviewingClient() client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if review.Spec.Token == "alice-token" {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+				}
+			case *authorizationv1.SubjectAccessReview:
+				attrs := review.Spec.NonResourceAttributes
+				member := review.Spec.User == "alice"
+				for _, group := range review.Spec.Groups {
+					member = member || group == "gatekeeper"
+				}
+				review.Status.Allowed = member && attrs.Path == "/external-data" && attrs.Verb == "post"
+			}
+			return nil
+		},
+	}).Build()
+}
+
+// withCertificate returns req as if it was sent with a verified client certificate
+func withCertificate(req *http.Request, commonName string, organizations ...string) *http.Request {
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+		{Subject: pkix.Name{CommonName: commonName, Organization: organizations}},
+	}}}
+	return req
+}
+
+func TestHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	post := func(path, token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name      string
+		endpoints config.EndpointsConfig
+		req       *http.Request
+		want      int
+	}{
+		{name: "open by default", req: post("/external-data", ""), want: http.StatusOK},
+		{name: "client certificate required", endpoints: config.EndpointsConfig{RequireClientCert: true}, req: post("/external-data", ""), want: http.StatusUnauthorized},
+		{name: "client certificate presented", endpoints: config.EndpointsConfig{RequireClientCert: true}, req: withCertificate(post("/external-data", ""), "gatekeeper"), want: http.StatusOK},
+		{name: "token required", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: post("/external-data", ""), want: http.StatusUnauthorized},
+		{name: "invalid token", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: post("/external-data", "mallory-token"), want: http.StatusUnauthorized},
+		{name: "token granted", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: post("/external-data", "alice-token"), want: http.StatusOK},
+		{name: "token not granted the path", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: post("/simulate", "alice-token"), want: http.StatusForbidden},
+		{name: "certificate group granted", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: withCertificate(post("/external-data", ""), "gk-1", "gatekeeper"), want: http.StatusOK},
+		{name: "certificate not granted", endpoints: config.EndpointsConfig{Authorization: config.EndpointAuthorizationSubjectAccessReview}, req: withCertificate(post("/external-data", ""), "bob"), want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.Endpoints = tt.endpoints
+			guard := &Guard{Client: reviewingClient(), Config: config.NewStaticManager(cfg)}
+			w := httptest.NewRecorder()
+			guard.Handler(ok).ServeHTTP(w, tt.req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestTLSOption(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "endpoints-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caPath := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	guard := &Guard{Config: config.NewStaticManager(cfg)}
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	guard.TLSOption(base)
+
+	// Without a client CA the base config serves
+	if got, err := base.GetConfigForClient(&tls.ClientHelloInfo{}); err != nil || got != nil {
+		t.Fatalf("GetConfigForClient() = %v, %v, want the base config", got, err)
+	}
+
+	cfg.Endpoints = config.EndpointsConfig{ClientCA: caPath, RequireClientCert: true}
+	got, err := base.GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetConfigForClient() failed: %v", err)
+	}
+	if got.ClientAuth != tls.RequireAndVerifyClientCert || got.ClientCAs == nil || got.MinVersion != tls.VersionTLS12 {
+		t.Errorf("GetConfigForClient() = clientAuth %v, clientCAs %v, minVersion %v", got.ClientAuth, got.ClientCAs, got.MinVersion)
+	}
+	if base.ClientAuth != tls.NoClientCert {
+		t.Errorf("base config changed to clientAuth %v", base.ClientAuth)
+	}
+
+	cfg.Endpoints.ClientCA = filepath.Join(t.TempDir(), "missing.crt")
+	if _, err := base.GetConfigForClient(&tls.ClientHelloInfo{}); err == nil {
+		t.Errorf("GetConfigForClient() with a missing client CA succeeded")
+	}
+}
@@ -0,0 +1,149 @@
+// Package manifests reads the Deployments and HPAs of rendered manifests,
+// e.g. the output of kustomize build or helm template for a Git revision,
+// from YAML documents or from the layers of an OCI artifact.
+package manifests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxManifestBytes bounds the size of the manifests read from one source
+const maxManifestBytes = 32 << 20
+
+// Set holds the workloads declared by rendered manifests
+type Set struct {
+	Deployments []appsv1.Deployment
+	HPAs        []autoscalingv2.HorizontalPodAutoscaler
+}
+
+// Parse adds the Deployments and HPAs of YAML or JSON documents, including
+// the items of List documents, to the set. Other kinds are skipped.
+func (s *Set) Parse(data []byte) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(object.Object) == 0 {
+			continue
+		}
+		if object.IsList() {
+			if err := object.EachListItem(func(item runtime.Object) error {
+				return s.add(item.(*unstructured.Unstructured))
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.add(object); err != nil {
+			return err
+		}
+	}
+}
+
+// add adds object to the set if it is a Deployment or an HPA
+func (s *Set) add(object *unstructured.Unstructured) error {
+	gvk := object.GroupVersionKind()
+	switch {
+	case gvk.Group == "apps" && gvk.Kind == "Deployment":
+		deployment := appsv1.Deployment{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &deployment); err != nil {
+			return fmt.Errorf("Deployment %s: %w", object.GetName(), err)
+		}
+		if deployment.Spec.Replicas == nil {
+			// The API server defaults the replicas of a Deployment to 1
+			replicas := int32(1)
+			deployment.Spec.Replicas = &replicas
+		}
+		s.Deployments = append(s.Deployments, deployment)
+	case gvk.Group == "autoscaling" && gvk.Kind == "HorizontalPodAutoscaler":
+		if gvk.Version != "v2" {
+			return fmt.Errorf("HorizontalPodAutoscaler %s: only autoscaling/v2 is supported, got %s", object.GetName(), gvk.Version)
+		}
+		hpa := autoscalingv2.HorizontalPodAutoscaler{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &hpa); err != nil {
+			return fmt.Errorf("HorizontalPodAutoscaler %s: %w", object.GetName(), err)
+		}
+		s.HPAs = append(s.HPAs, hpa)
+	}
+	return nil
+}
+
+// ParseArchive adds the manifests of an OCI layer to the set: plain YAML, or
+// the .yaml, .yml and .json files of a tar archive, gzipped or not, as
+// pushed by flux push artifact or oras push
+func (s *Set) ParseArchive(data []byte) error {
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		data, err = io.ReadAll(io.LimitReader(reader, maxManifestBytes))
+		if err != nil {
+			return err
+		}
+	}
+	if !isTar(data) {
+		return s.Parse(data)
+	}
+
+	archive := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		switch strings.ToLower(path.Ext(header.Name)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		file, err := io.ReadAll(io.LimitReader(archive, maxManifestBytes))
+		if err != nil {
+			return err
+		}
+		if err := s.Parse(file); err != nil {
+			return fmt.Errorf("%s: %w", header.Name, err)
+		}
+	}
+}
+
+// isTar returns true if data starts with a ustar header
+func isTar(data []byte) bool {
+	return len(data) >= 262 && string(data[257:262]) == "ustar"
+}
+
+// Default sets the namespace of the workloads declaring none
+func (s *Set) Default(namespace string) {
+	for i := range s.Deployments {
+		if s.Deployments[i].Namespace == "" {
+			s.Deployments[i].Namespace = namespace
+		}
+	}
+	for i := range s.HPAs {
+		if s.HPAs[i].Namespace == "" {
+			s.HPAs[i].Namespace = namespace
+		}
+	}
+}
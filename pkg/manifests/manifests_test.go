@@ -0,0 +1,196 @@
+package manifests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const rendered = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 6
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: worker
+- apiVersion: autoscaling/v2
+  kind: HorizontalPodAutoscaler
+  metadata:
+    name: web
+    namespace: shop
+  spec:
+    scaleTargetRef:
+      apiVersion: apps/v1
+      kind: Deployment
+      name: web
+    minReplicas: 2
+    maxReplicas: 10
+`
+
+func TestParse(t *testing.T) {
+	set := &Set{}
+	if err := set.Parse([]byte(rendered)); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	set.Default("batch")
+
+	if len(set.Deployments) != 2 || len(set.HPAs) != 1 {
+		t.Fatalf("Parse() = %d deployments and %d HPAs, want 2 and 1", len(set.Deployments), len(set.HPAs))
+	}
+	if web := set.Deployments[0]; web.Name != "web" || *web.Spec.Replicas != 6 {
+		t.Errorf("deployment web = %s with %d replicas, want 6", web.Name, *web.Spec.Replicas)
+	}
+	if worker := set.Deployments[1]; worker.Namespace != "batch" || *worker.Spec.Replicas != 1 {
+		t.Errorf("deployment worker = %s/%s with %d replicas, want batch/worker with the default of 1", worker.Namespace, worker.Name, *worker.Spec.Replicas)
+	}
+
+	if err := (&Set{}).Parse([]byte("apiVersion: autoscaling/v1\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: old\n")); err == nil {
+		t.Error("expected autoscaling/v1 HPAs to be rejected")
+	}
+}
+
+// archive returns files as a gzipped tar, as pushed by flux push artifact
+func archive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		want      Reference
+	}{
+		{"oci://ghcr.io/org/app-manifests:pr-42", Reference{Registry: "ghcr.io", Repository: "org/app-manifests", Tag: "pr-42"}},
+		{"localhost:5000/manifests", Reference{Registry: "localhost:5000", Repository: "manifests", Tag: "latest"}},
+		{"org/manifests@sha256:abc", Reference{Registry: dockerHub, Repository: "org/manifests", Tag: "sha256:abc"}},
+		{"manifests:v1", Reference{Registry: dockerHub, Repository: "library/manifests", Tag: "v1"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.reference)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, %v, want %+v", tt.reference, got, err, tt.want)
+		}
+	}
+
+	for _, reference := range []string{"", "ghcr.io/org/../app:v1", "ghcr.io/org/app:v1?x=1", "ghcr.io/org/app@sha256:../x", "ghcr.io/Org/app"} {
+		if _, err := ParseReference(reference); err == nil {
+			t.Errorf("ParseReference(%q) succeeded, want an error", reference)
+		}
+	}
+}
+
+func TestPull(t *testing.T) {
+	layer := archive(t, map[string]string{"deploy/app.yaml": rendered, "README.md": "not a manifest"})
+	sum := sha256.Sum256(layer)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/token":
+			if req.URL.Query().Get("scope") != "repository:org/app:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "anonymous"})
+		case req.Header.Get("Authorization") != "Bearer anonymous":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:org/app:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case req.URL.Path == "/v2/org/app/manifests/pr-42":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"layers":    []map[string]string{{"mediaType": "application/vnd.cncf.flux.content.v1.tar+gzip", "digest": digest}},
+			})
+		case req.URL.Path == "/v2/org/app/blobs/"+digest:
+			http.Redirect(w, req, "/cdn/"+digest, http.StatusTemporaryRedirect)
+		case req.URL.Path == "/cdn/"+digest:
+			_, _ = w.Write(layer)
+		case req.URL.Path == "/v2/org/metadata/manifests/pr-42":
+			http.Redirect(w, req, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case req.URL.Path == "/v2/org/traversal/manifests/pr-42":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"layers": []map[string]string{{"digest": "sha256:../../../token"}},
+			})
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	allowed := func(raw string) error {
+		if !strings.HasPrefix(raw, server.URL+"/") {
+			return fmt.Errorf("URL %s is not allowed", raw)
+		}
+		return nil
+	}
+	puller := &Puller{Client: client, Allowed: allowed}
+	set, err := puller.Pull(context.Background(), "oci://"+strings.TrimPrefix(server.URL, "https://")+"/org/app:pr-42")
+	if err != nil {
+		t.Fatalf("Pull() failed: %v", err)
+	}
+	if len(set.Deployments) != 2 || len(set.HPAs) != 1 {
+		t.Errorf("Pull() = %d deployments and %d HPAs, want 2 and 1", len(set.Deployments), len(set.HPAs))
+	}
+
+	if _, err := puller.Pull(context.Background(), strings.TrimPrefix(server.URL, "https://")+"/org/missing:pr-42"); err == nil {
+		t.Error("expected a missing artifact to fail")
+	}
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	for _, reference := range []string{host + "/org/metadata:pr-42", host + "/org/traversal:pr-42", "registry.example.com/org/app:pr-42"} {
+		if _, err := puller.Pull(context.Background(), reference); err == nil {
+			t.Errorf("Pull(%q) succeeded, want a refused URL or digest", reference)
+		}
+	}
+
+	// The token realm is checked like the registry
+	registryOnly := &Puller{Client: client, Allowed: func(raw string) error {
+		if !strings.HasPrefix(raw, server.URL+"/v2/") {
+			return fmt.Errorf("URL %s is not allowed", raw)
+		}
+		return nil
+	}}
+	if _, err := registryOnly.Pull(context.Background(), host+"/org/app:pr-42"); err == nil || !strings.Contains(err.Error(), "realm") {
+		t.Errorf("Pull() with a refused token realm = %v, want a realm error", err)
+	}
+}
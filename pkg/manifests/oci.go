@@ -0,0 +1,295 @@
+package manifests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// pullTimeout bounds the download of an artifact
+	pullTimeout = time.Minute
+
+	// dockerHub is the registry of references without a registry host
+	dockerHub = "registry-1.docker.io"
+
+	// maxRedirects bounds the redirects followed for one request, e.g. to the CDN serving blobs
+	maxRedirects = 5
+)
+
+var (
+	// repositoryPattern matches the path components of a repository name
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)*$`)
+	// tagPattern matches a tag
+	tagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]{0,127}$`)
+	// digestPattern matches a digest reference
+	digestPattern = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[A-Za-z0-9=_-]+$`)
+	// layerDigestPattern matches the sha256 digests of the layers the puller verifies
+	layerDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// manifestMediaTypes are the accepted media types of artifact manifests
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference is a parsed OCI artifact reference, e.g. ghcr.io/org/app:pr-42
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag or digest of the artifact
+	Tag string
+}
+
+// ParseReference parses an artifact reference with an optional oci:// prefix.
+// References without a registry host are looked up on Docker Hub and
+// references without a tag or digest default to latest.
+func ParseReference(reference string) (Reference, error) {
+	ref := strings.TrimPrefix(reference, "oci://")
+	if ref == "" {
+		return Reference{}, fmt.Errorf("empty OCI reference")
+	}
+
+	var parsed Reference
+	name := ref
+	digest := false
+	if i := strings.Index(ref, "@"); i >= 0 {
+		name, parsed.Tag, digest = ref[:i], ref[i+1:], true
+	} else if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, parsed.Tag = ref[:i], ref[i+1:]
+	}
+	if parsed.Tag == "" {
+		parsed.Tag = "latest"
+	}
+
+	if host, repository, found := strings.Cut(name, "/"); found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		parsed.Registry, parsed.Repository = host, repository
+	} else {
+		parsed.Registry, parsed.Repository = dockerHub, name
+		if !strings.Contains(name, "/") {
+			parsed.Repository = "library/" + name
+		}
+	}
+	if !repositoryPattern.MatchString(parsed.Repository) {
+		return Reference{}, fmt.Errorf("invalid OCI reference %q", reference)
+	}
+	if digest && !digestPattern.MatchString(parsed.Tag) || !digest && !tagPattern.MatchString(parsed.Tag) {
+		return Reference{}, fmt.Errorf("invalid tag or digest in OCI reference %q", reference)
+	}
+	return parsed, nil
+}
+
+// Puller downloads the manifests of OCI artifacts, anonymously or with the
+// bearer token a registry hands out to anonymous clients
+type Puller struct {
+	Client *http.Client
+	// Allowed returns an error for the registry, redirect and token realm URLs
+	// the puller must not request; nil allows every URL
+	Allowed func(raw string) error
+}
+
+// NewPuller returns a puller with a bounded timeout that follows redirects
+// only to the URLs allowed
+func NewPuller(allowed func(raw string) error) *Puller {
+	return &Puller{Client: config.NewHTTPClient(pullTimeout), Allowed: allowed}
+}
+
+// allowed checks target against the Allowed hook
+func (p *Puller) allowed(target string) error {
+	if p.Allowed == nil {
+		return nil
+	}
+	return p.Allowed(target)
+}
+
+// artifactManifest is the part of an OCI manifest listing its layers
+type artifactManifest struct {
+	MediaType string `json:"mediaType"`
+	Layers    []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+}
+
+// Pull returns the Deployments and HPAs of every layer of the artifact
+func (p *Puller) Pull(ctx context.Context, reference string) (*Set, error) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	base := fmt.Sprintf("https://%s/v2/%s", ref.Registry, ref.Repository)
+
+	var token string
+	data, err := p.get(ctx, base+"/manifests/"+ref.Tag, strings.Join(manifestMediaTypes, ", "), &token)
+	if err != nil {
+		return nil, err
+	}
+	var manifest artifactManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of %s: %w", reference, err)
+	}
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("%s is not an artifact with layers (media type %q)", reference, manifest.MediaType)
+	}
+
+	set := &Set{}
+	for _, layer := range manifest.Layers {
+		if !layerDigestPattern.MatchString(layer.Digest) {
+			return nil, fmt.Errorf("unsupported layer digest %q in %s", layer.Digest, reference)
+		}
+		blob, err := p.get(ctx, base+"/blobs/"+layer.Digest, "", &token)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyDigest(blob, layer.Digest); err != nil {
+			return nil, err
+		}
+		if err := set.ParseArchive(blob); err != nil {
+			return nil, fmt.Errorf("layer %s of %s: %w", layer.Digest, reference, err)
+		}
+	}
+	return set, nil
+}
+
+// get fetches target, requesting an anonymous token once when challenged and
+// following redirects to allowed URLs. The token is only sent to the registry.
+func (p *Puller) get(ctx context.Context, target, accept string, token *string) ([]byte, error) {
+	registry, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	challenged := false
+	for redirects := 0; ; {
+		if err := p.allowed(target); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if *token != "" && strings.EqualFold(req.URL.Host, registry.Host) {
+			req.Header.Set("Authorization", "Bearer "+*token)
+		}
+		resp, err := p.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestBytes))
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if location := resp.Header.Get("Location"); location != "" && isRedirect(resp.StatusCode) {
+			if redirects++; redirects > maxRedirects {
+				return nil, fmt.Errorf("GET %s: too many redirects", target)
+			}
+			next, err := req.URL.Parse(location)
+			if err != nil {
+				return nil, fmt.Errorf("GET %s: invalid redirect: %w", target, err)
+			}
+			target = next.String()
+			continue
+		}
+		if resp.StatusCode == http.StatusUnauthorized && !challenged {
+			challenged = true
+			if *token, err = p.anonymousToken(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", target, resp.Status)
+		}
+		return data, nil
+	}
+}
+
+// isRedirect returns true for the status codes redirecting a GET
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// anonymousToken requests a token from the realm of a Bearer challenge
+func (p *Puller) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported registry authentication %q, only anonymous pulls are supported", scheme)
+	}
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		if key, value, found := strings.Cut(strings.TrimSpace(param), "="); found {
+			values[key] = strings.Trim(value, `"`)
+		}
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid registry authentication realm %q", values["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+	if err := p.allowed(realm.String()); err != nil {
+		return "", fmt.Errorf("registry authentication realm: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("anonymous registry token: %s", resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// verifyDigest checks a blob against its sha256 digest
+func verifyDigest(blob []byte, digest string) error {
+	algorithm, expected, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest %q", digest)
+	}
+	sum := sha256.Sum256(blob)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("blob %s does not match its digest", digest)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package matcher
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// Target is a workload whose changes are mapped to the overrides targeting it
+type Target struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Labels    map[string]string
+}
+
+// Index matches targets against a snapshot of the overrides and ignore rules,
+// looking up direct references by name instead of scanning every override
+type Index struct {
+	overrides []dynamicscalingv1.ReplicasOverride
+	// refs holds the overrides with a deploymentRef by referenced namespace
+	// and name, an empty namespace matching the target in any namespace
+	refs map[types.NamespacedName][]int
+	// selectors holds the overrides matching targets by labels
+	selectors []int
+	ignores   []dynamicscalingv1.GlobalReplicasIgnore
+}
+
+// NewIndex indexes overrides and ignore rules. Overrides without a
+// deploymentRef, a non-empty selector or environments never match a target.
+func NewIndex(overrides []dynamicscalingv1.ReplicasOverride, ignores []dynamicscalingv1.GlobalReplicasIgnore) *Index {
+	index := &Index{overrides: overrides, refs: make(map[types.NamespacedName][]int), ignores: ignores}
+	for i := range overrides {
+		o := &overrides[i]
+		switch {
+		case o.Spec.DeploymentRef != nil:
+			ref := types.NamespacedName{Name: o.Spec.DeploymentRef.Name, Namespace: o.Spec.DeploymentRef.Namespace}
+			index.refs[ref] = append(index.refs[ref], i)
+		case o.Spec.Selector != nil && len(o.Spec.Selector.MatchLabels) > 0, o.Spec.Environments != nil:
+			index.selectors = append(index.selectors, i)
+		}
+	}
+	return index
+}
+
+// Ignored returns true if an ignore rule matches the namespace, the kind and
+// name or one of the labels of target
+func (i *Index) Ignored(target Target) bool {
+	workload := &metav1.ObjectMeta{Name: target.Name, Namespace: target.Namespace, Labels: target.Labels}
+	for j := range i.ignores {
+		if ignored, _ := utils.ShouldIgnoreWorkload(target.Kind, workload, &i.ignores[j]); ignored {
+			return true
+		}
+	}
+	return false
+}
+
+// Overrides returns the overrides targeting target, by reference or by labels.
+// References only ever name a Deployment.
+func (i *Index) Overrides(target Target) []types.NamespacedName {
+	var matched []types.NamespacedName
+	for _, o := range i.matches(target) {
+		matched = append(matched, types.NamespacedName{Name: o.Name, Namespace: o.Namespace})
+	}
+	return matched
+}
+
+// matches returns the overrides targeting target
+func (i *Index) matches(target Target) []*dynamicscalingv1.ReplicasOverride {
+	var candidates []int
+	if target.Kind == "Deployment" {
+		candidates = append(candidates, i.refs[types.NamespacedName{Name: target.Name, Namespace: target.Namespace}]...)
+		if target.Namespace != "" {
+			candidates = append(candidates, i.refs[types.NamespacedName{Name: target.Name}]...)
+		}
+	}
+	candidates = append(candidates, i.selectors...)
+
+	var matched []*dynamicscalingv1.ReplicasOverride
+	for _, j := range candidates {
+		if o := &i.overrides[j]; Selects(o, target) {
+			matched = append(matched, o)
+		}
+	}
+	return matched
+}
+
+// Requests returns the reconcile requests of the overrides targeting target,
+// or the request of the global config of its namespace, which has an empty
+// name, when none does. Ignored targets only map to the overrides with
+// ignorePolicy override targeting them.
+func (i *Index) Requests(target Target) []reconcile.Request {
+	matched := i.matches(target)
+	ignored := i.Ignored(target)
+	if len(matched) == 0 && !ignored {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: target.Namespace}}}
+	}
+	var requests []reconcile.Request
+	for _, o := range matched {
+		if !ignored || o.Spec.IgnorePolicy == dynamicscalingv1.IgnorePolicyOverride {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: o.Name, Namespace: o.Namespace}})
+		}
+	}
+	return requests
+}
+
+// Selects returns true if override targets target: a Deployment by its
+// deploymentRef, or any kind by its selector, with the environment of the
+// target listed when the override has environments. An override with no
+// deploymentRef, selector or environments selects nothing.
+func Selects(override *dynamicscalingv1.ReplicasOverride, target Target) bool {
+	if ref := override.Spec.DeploymentRef; ref != nil {
+		return target.Kind == "Deployment" && ref.Name == target.Name &&
+			(ref.Namespace == "" || ref.Namespace == target.Namespace) &&
+			environmentMatches(override, target.Labels)
+	}
+	return SelectsLabels(override, target.Labels)
+}
+
+// SelectsLabels returns true if the selector and the environments of override
+// match labels. An override with neither selects nothing.
+func SelectsLabels(override *dynamicscalingv1.ReplicasOverride, labels map[string]string) bool {
+	selector := override.Spec.Selector
+	if selector == nil || len(selector.MatchLabels) == 0 {
+		return override.Spec.Environments != nil && Environment(override, labels) != nil
+	}
+	return LabelsMatch(labels, selector.MatchLabels) && environmentMatches(override, labels)
+}
+
+// Environment returns the environment of override matching the environment
+// label of a workload with the given labels, or nil
+func Environment(override *dynamicscalingv1.ReplicasOverride, labels map[string]string) *dynamicscalingv1.EnvironmentPercentage {
+	environments := override.Spec.Environments
+	if environments == nil {
+		return nil
+	}
+	value, found := labels[environments.Label]
+	if !found {
+		return nil
+	}
+	for i := range environments.Values {
+		if environments.Values[i].Value == value {
+			return &environments.Values[i]
+		}
+	}
+	return nil
+}
+
+// environmentMatches returns true if override has no environments or lists
+// the environment of a workload with the given labels
+func environmentMatches(override *dynamicscalingv1.ReplicasOverride, labels map[string]string) bool {
+	return override.Spec.Environments == nil || Environment(override, labels) != nil
+}
+
+// LabelsMatch returns true if labels contain every key/value of selector
+func LabelsMatch(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package matcher
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func override(name string, spec dynamicscalingv1.ReplicasOverrideSpec) dynamicscalingv1.ReplicasOverride {
+	return dynamicscalingv1.ReplicasOverride{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}, Spec: spec}
+}
+
+func TestOverrides(t *testing.T) {
+	index := NewIndex([]dynamicscalingv1.ReplicasOverride{
+		override("by-ref", dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web", Namespace: "shop"}}),
+		override("by-ref-any-namespace", dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web"}}),
+		override("by-labels", dynamicscalingv1.ReplicasOverrideSpec{Selector: &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web", "team": "shop"}}}),
+		override("empty-selector", dynamicscalingv1.ReplicasOverrideSpec{Selector: &dynamicscalingv1.TargetSelector{}}),
+		override("by-environment", dynamicscalingv1.ReplicasOverrideSpec{Environments: &dynamicscalingv1.EnvironmentTargeting{
+			Label:  "env",
+			Values: []dynamicscalingv1.EnvironmentPercentage{{Value: "prod", ReplicasPercentage: 150}, {Value: "staging", ReplicasPercentage: 50}},
+		}}),
+		// A reference wins over the selector of the same override
+		override("ref-and-labels", dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "api", Namespace: "shop"},
+			Selector:      &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web"}},
+		}),
+		// The environments of a reference are checked like those of a selector
+		override("ref-with-environment", dynamicscalingv1.ReplicasOverrideSpec{
+			DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "worker", Namespace: "shop"},
+			Environments: &dynamicscalingv1.EnvironmentTargeting{
+				Label:  "stage",
+				Values: []dynamicscalingv1.EnvironmentPercentage{{Value: "live", ReplicasPercentage: 150}},
+			},
+		}),
+	}, nil)
+	key := func(name string) types.NamespacedName { return types.NamespacedName{Name: name, Namespace: "shop"} }
+	labels := map[string]string{"tier": "web", "team": "shop", "version": "v2"}
+
+	tests := []struct {
+		name   string
+		target Target
+		want   []types.NamespacedName
+	}{
+		{"references and labels", Target{Kind: "Deployment", Namespace: "shop", Name: "web", Labels: labels},
+			[]types.NamespacedName{key("by-ref"), key("by-ref-any-namespace"), key("by-labels")}},
+		{"reference without namespace", Target{Kind: "Deployment", Namespace: "blog", Name: "web"},
+			[]types.NamespacedName{key("by-ref-any-namespace")}},
+		{"partial labels", Target{Kind: "Deployment", Namespace: "shop", Name: "cart", Labels: map[string]string{"tier": "web"}}, nil},
+		{"environment", Target{Kind: "Deployment", Namespace: "shop", Name: "cart", Labels: map[string]string{"env": "staging"}},
+			[]types.NamespacedName{key("by-environment")}},
+		{"unlisted environment", Target{Kind: "Deployment", Namespace: "shop", Name: "cart", Labels: map[string]string{"env": "dev"}}, nil},
+		{"references only name deployments", Target{Kind: "StatefulSet", Namespace: "shop", Name: "web", Labels: labels},
+			[]types.NamespacedName{key("by-labels")}},
+		{"reference with environment", Target{Kind: "Deployment", Namespace: "shop", Name: "worker", Labels: map[string]string{"stage": "live"}},
+			[]types.NamespacedName{key("ref-with-environment")}},
+		{"reference with unlisted environment", Target{Kind: "Deployment", Namespace: "shop", Name: "worker", Labels: map[string]string{"stage": "test"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := index.Overrides(tt.target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Overrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	index := NewIndex(nil, []dynamicscalingv1.GlobalReplicasIgnore{{Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+		IgnoreNamespaces: []string{"kube-system"},
+		IgnoreResources: []dynamicscalingv1.IgnoredResource{
+			{Kind: "Deployment", Name: "batch", Namespace: "shop"},
+			{Kind: "StatefulSet", Name: "db"},
+		},
+		IgnoreLabels: map[string]string{"scaling": "off"},
+	}}})
+
+	tests := []struct {
+		name   string
+		target Target
+		want   bool
+	}{
+		{"namespace", Target{Kind: "Deployment", Namespace: "kube-system", Name: "coredns"}, true},
+		{"resource", Target{Kind: "Deployment", Namespace: "shop", Name: "batch"}, true},
+		{"resource of another namespace", Target{Kind: "Deployment", Namespace: "blog", Name: "batch"}, false},
+		{"resource in any namespace", Target{Kind: "StatefulSet", Namespace: "blog", Name: "db"}, true},
+		{"resource of another kind", Target{Kind: "Deployment", Namespace: "shop", Name: "db"}, false},
+		{"label", Target{Kind: "Deployment", Namespace: "shop", Name: "web", Labels: map[string]string{"scaling": "off"}}, true},
+		{"not ignored", Target{Kind: "Deployment", Namespace: "shop", Name: "web"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := index.Ignored(tt.target); got != tt.want {
+				t.Errorf("Ignored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequests(t *testing.T) {
+	index := NewIndex([]dynamicscalingv1.ReplicasOverride{
+		override("by-ref", dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web", Namespace: "shop"}}),
+	}, []dynamicscalingv1.GlobalReplicasIgnore{{Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+		IgnoreLabels: map[string]string{"scaling": "off"},
+	}}})
+
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "by-ref", Namespace: "shop"}}}
+	if got := index.Requests(Target{Kind: "Deployment", Namespace: "shop", Name: "web"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests() of a targeted deployment = %v, want %v", got, want)
+	}
+	want = []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "shop"}}}
+	if got := index.Requests(Target{Kind: "Deployment", Namespace: "shop", Name: "cart"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests() of an untargeted deployment = %v, want the global config %v", got, want)
+	}
+	if got := index.Requests(Target{Kind: "Deployment", Namespace: "shop", Name: "web", Labels: map[string]string{"scaling": "off"}}); got != nil {
+		t.Errorf("Requests() of an ignored deployment = %v, want none", got)
+	}
+
+	// An override taking precedence over the ignore rules still gets the changes of its ignored targets
+	index = NewIndex([]dynamicscalingv1.ReplicasOverride{
+		override("by-ref", dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web", Namespace: "shop"}}),
+		override("overriding-ignores", dynamicscalingv1.ReplicasOverrideSpec{
+			Selector:     &dynamicscalingv1.TargetSelector{MatchLabels: map[string]string{"tier": "web"}},
+			IgnorePolicy: dynamicscalingv1.IgnorePolicyOverride,
+		}),
+	}, []dynamicscalingv1.GlobalReplicasIgnore{{Spec: dynamicscalingv1.GlobalReplicasIgnoreSpec{
+		IgnoreLabels: map[string]string{"scaling": "off"},
+	}}})
+	want = []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "overriding-ignores", Namespace: "shop"}}}
+	ignored := Target{Kind: "Deployment", Namespace: "shop", Name: "web", Labels: map[string]string{"scaling": "off", "tier": "web"}}
+	if got := index.Requests(ignored); !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests() of an ignored deployment with ignorePolicy override = %v, want %v", got, want)
+	}
+	if got := index.Requests(Target{Kind: "Deployment", Namespace: "shop", Name: "cart", Labels: map[string]string{"scaling": "off"}}); got != nil {
+		t.Errorf("Requests() of an ignored deployment without such override = %v, want none", got)
+	}
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// AlertOptions configures the alerts of the generated PrometheusRule
+type AlertOptions struct {
+	// Name and Namespace of the PrometheusRule object
+	Name      string
+	Namespace string
+	// Labels are added to the object, e.g. the ruleSelector labels of the Prometheus instance
+	Labels map[string]string
+
+	// ReplicasPerHour is the number of replicas added or removed in a namespace
+	// within an hour above which scaling is considered runaway
+	ReplicasPerHour int
+	// ClampedPerHour is the number of clamped scaling operations in a namespace
+	// within an hour above which the min/max replicas are likely too tight
+	ClampedPerHour int
+	// ErrorsPerHour is the number of failed scaling operations, or of failures
+	// reconciling targets other than transient conflicts and API server
+	// unavailability, within an hour above which the controller is considered failing
+	ErrorsPerHour int
+	// QueueDepth is the number of items waiting in the work queue of a
+	// controller above which it is considered falling behind cluster churn
+	QueueDepth int
+	// OldestItemAgeSeconds is how long the oldest item of a work queue may
+	// wait before the controller is considered falling behind cluster churn
+	OldestItemAgeSeconds int
+	// For is how long a condition must hold before the alert fires
+	For string
+}
+
+// DefaultAlertOptions returns the options used by --print-prometheus-rule
+func DefaultAlertOptions() AlertOptions {
+	return AlertOptions{
+		Name:                 "kubedynamicscaler-alerts",
+		Namespace:            "kubedynamicscaler-system",
+		ReplicasPerHour:      200,
+		ClampedPerHour:       50,
+		ErrorsPerHour:        10,
+		QueueDepth:           100,
+		OldestItemAgeSeconds: 120,
+		For:                  "15m",
+	}
+}
+
+// PrometheusRule returns a monitoring.coreos.com/v1 PrometheusRule alerting on
+// runaway scaling, frequent clamping, scaling and reconcile errors and
+// controllers falling behind their work queue
+func PrometheusRule(opts AlertOptions) *unstructured.Unstructured {
+	alert := func(name, expr, severity, summary string) map[string]any {
+		return map[string]any{
+			"alert": name,
+			"expr":  expr,
+			"for":   opts.For,
+			"labels": map[string]any{
+				"severity": severity,
+			},
+			"annotations": map[string]any{
+				"summary": summary,
+			},
+		}
+	}
+
+	rules := []any{
+		alert("KubeDynamicScalerRunawayScaleUp",
+			fmt.Sprintf("sum by (%s) (increase(%s_replicas_added_total[1h])) > %d", LabelNamespace, Namespace, opts.ReplicasPerHour),
+			"warning",
+			fmt.Sprintf("More than %d replicas added in namespace {{ $labels.%s }} within an hour", opts.ReplicasPerHour, LabelNamespace)),
+		alert("KubeDynamicScalerRunawayScaleDown",
+			fmt.Sprintf("sum by (%s) (increase(%s_replicas_removed_total[1h])) > %d", LabelNamespace, Namespace, opts.ReplicasPerHour),
+			"warning",
+			fmt.Sprintf("More than %d replicas removed in namespace {{ $labels.%s }} within an hour", opts.ReplicasPerHour, LabelNamespace)),
+		alert("KubeDynamicScalerFrequentClamping",
+			fmt.Sprintf("sum by (%s, %s) (increase(%s_clamped_operations_total[1h])) > %d", LabelNamespace, LabelOverride, Namespace, opts.ClampedPerHour),
+			"info",
+			fmt.Sprintf("Override {{ $labels.%s }} keeps hitting the min/max replicas in namespace {{ $labels.%s }}", LabelOverride, LabelNamespace)),
+		alert("KubeDynamicScalerScalingErrors",
+			fmt.Sprintf("sum by (%s, %s) (increase(%s_scaling_errors_total[1h])) > %d", LabelNamespace, LabelOverride, Namespace, opts.ErrorsPerHour),
+			"critical",
+			fmt.Sprintf("Scaling operations of override {{ $labels.%s }} are failing in namespace {{ $labels.%s }}", LabelOverride, LabelNamespace)),
+		alert("KubeDynamicScalerReconcileErrors",
+			fmt.Sprintf(`sum by (%s, %s, %s) (increase(%s_reconcile_errors_total{%s!~"Conflict|Unavailable"}[1h])) > %d`,
+				LabelNamespace, LabelOverride, LabelReason, Namespace, LabelReason, opts.ErrorsPerHour),
+			"warning",
+			fmt.Sprintf("Targets of override {{ $labels.%s }} fail to reconcile with {{ $labels.%s }} in namespace {{ $labels.%s }}", LabelOverride, LabelReason, LabelNamespace)),
+		alert("KubeDynamicScalerQueueBacklog",
+			fmt.Sprintf("max by (%s) (%s_workqueue_depth) > %d", LabelController, Namespace, opts.QueueDepth),
+			"warning",
+			fmt.Sprintf("More than %d items wait in the work queue of controller {{ $labels.%s }}", opts.QueueDepth, LabelController)),
+		alert("KubeDynamicScalerFallingBehind",
+			fmt.Sprintf("max by (%s) (%s_workqueue_oldest_item_age_seconds) > %d", LabelController, Namespace, opts.OldestItemAgeSeconds),
+			"warning",
+			fmt.Sprintf("Controller {{ $labels.%s }} takes more than %ds to pick up changes", LabelController, opts.OldestItemAgeSeconds)),
+	}
+
+	rule := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"groups": []any{
+				map[string]any{
+					"name":  "kubedynamicscaler.rules",
+					"rules": rules,
+				},
+			},
+		},
+	}}
+	rule.SetAPIVersion("monitoring.coreos.com/v1")
+	rule.SetKind("PrometheusRule")
+	rule.SetName(opts.Name)
+	rule.SetNamespace(opts.Namespace)
+	if len(opts.Labels) > 0 {
+		rule.SetLabels(opts.Labels)
+	}
+	return rule
+}
+
+// PrometheusRuleYAML returns the PrometheusRule of opts as a YAML manifest
+func PrometheusRuleYAML(opts AlertOptions) ([]byte, error) {
+	return yaml.Marshal(PrometheusRule(opts).Object)
+}
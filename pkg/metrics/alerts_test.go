@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrometheusRule(t *testing.T) {
+	opts := DefaultAlertOptions()
+	opts.Labels = map[string]string{"release": "prometheus"}
+	rule := PrometheusRule(opts)
+
+	if rule.GetKind() != "PrometheusRule" || rule.GetNamespace() != opts.Namespace || rule.GetLabels()["release"] != "prometheus" {
+		t.Fatalf("unexpected object metadata: %s %s/%s %v", rule.GetKind(), rule.GetNamespace(), rule.GetName(), rule.GetLabels())
+	}
+
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if len(groups) != 1 {
+		t.Fatalf("got %d rule groups, want 1", len(groups))
+	}
+	rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]any), "rules")
+	exprs := make(map[string]string)
+	for _, r := range rules {
+		alert := r.(map[string]any)
+		exprs[alert["alert"].(string)] = alert["expr"].(string)
+	}
+	want := map[string]string{
+		"KubeDynamicScalerRunawayScaleUp":   "increase(kubedynamicscaler_replicas_added_total[1h])) > 200",
+		"KubeDynamicScalerRunawayScaleDown": "increase(kubedynamicscaler_replicas_removed_total[1h])) > 200",
+		"KubeDynamicScalerFrequentClamping": "increase(kubedynamicscaler_clamped_operations_total[1h])) > 50",
+		"KubeDynamicScalerScalingErrors":    "increase(kubedynamicscaler_scaling_errors_total[1h])) > 10",
+		"KubeDynamicScalerReconcileErrors":  `increase(kubedynamicscaler_reconcile_errors_total{reason!~"Conflict|Unavailable"}[1h])) > 10`,
+		"KubeDynamicScalerQueueBacklog":     "(kubedynamicscaler_workqueue_depth) > 100",
+		"KubeDynamicScalerFallingBehind":    "(kubedynamicscaler_workqueue_oldest_item_age_seconds) > 120",
+	}
+	for name, fragment := range want {
+		if !strings.Contains(exprs[name], fragment) {
+			t.Errorf("alert %s expr = %q, want it to contain %q", name, exprs[name], fragment)
+		}
+	}
+
+	data, err := PrometheusRuleYAML(opts)
+	if err != nil {
+		t.Fatalf("PrometheusRuleYAML() failed: %v", err)
+	}
+	if !strings.Contains(string(data), "kind: PrometheusRule") {
+		t.Errorf("PrometheusRuleYAML() = %s", data)
+	}
+}
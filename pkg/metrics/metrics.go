@@ -0,0 +1,376 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// Namespace is the prefix for all metrics exported by the controller
+	Namespace = "kubedynamicscaler"
+
+	// OpenMetricsPath is the path on the metrics server that serves the OpenMetrics
+	// exposition format, which is required for exemplars to be visible
+	OpenMetricsPath = "/metrics/openmetrics"
+
+	// Stable label names shared by every scaling metric
+	LabelOverride   = "override"
+	LabelNamespace  = "namespace"
+	LabelTargetKind = "target_kind"
+	LabelTrigger    = "trigger"
+
+	// LabelReason is the reason label of NotificationsSuppressedTotal and ReconcileErrorsTotal
+	LabelReason = "reason"
+
+	// LabelCheck and LabelResult are the labels of StartupAuditInconsistencies,
+	// LabelResult also of DecisionWebhookDecisionsTotal
+	LabelCheck  = "check"
+	LabelResult = "result"
+
+	// Result label values of StartupAuditInconsistencies
+	AuditResultFound = "found"
+	AuditResultFixed = "fixed"
+
+	// GlobalOverride is the override label value used when the global config applies
+	GlobalOverride = "global"
+
+	// Trigger label values
+	TriggerGlobal         = "global"
+	TriggerOverride       = "override"
+	TriggerCarbon         = "carbon"
+	TriggerNodeDisruption = "node-disruption"
+	TriggerNodePressure   = "node-pressure"
+	TriggerRollback       = "rollback"
+	TriggerExpression     = "expression"
+	TriggerAnnotation     = "annotation"
+	TriggerWarmUp         = "warm-up"
+	TriggerVerification   = "verification"
+	TriggerGroup          = "group"
+	TriggerNamespace      = "namespace-override"
+
+	// Target kind label values
+	TargetKindDeployment  = "Deployment"
+	TargetKindHPA         = "HorizontalPodAutoscaler"
+	TargetKindStatefulSet = "StatefulSet"
+
+	// traceIDExemplarKey is the exemplar label linking a sample to a trace
+	traceIDExemplarKey = "trace_id"
+)
+
+// stableLabels is the label set shared by every scaling metric so that
+// per-override dashboards can be built without relabeling.
+var stableLabels = []string{LabelOverride, LabelNamespace, LabelTargetKind, LabelTrigger}
+
+var (
+	// ScalingOperationsTotal counts replica writes performed by the controller
+	ScalingOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "scaling_operations_total",
+			Help:      "Total number of scaling operations applied to targets",
+		},
+		stableLabels,
+	)
+
+	// ScalingErrorsTotal counts failed scaling operations
+	ScalingErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "scaling_errors_total",
+			Help:      "Total number of scaling operations that failed",
+		},
+		stableLabels,
+	)
+
+	// ReplicaDelta observes the replica change applied by each scaling operation
+	ReplicaDelta = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "replica_delta",
+			Help:      "Replica change applied by each scaling operation (target minus previous)",
+			Buckets:   []float64{-100, -50, -20, -10, -5, -2, -1, 0, 1, 2, 5, 10, 20, 50, 100},
+		},
+		stableLabels,
+	)
+
+	// ReplicasAddedTotal counts the replicas added by scaling operations, so
+	// increase(...[1h]) gives the replicas added per hour
+	ReplicasAddedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "replicas_added_total",
+			Help:      "Total number of replicas added by scaling operations",
+		},
+		stableLabels,
+	)
+
+	// ReplicasRemovedTotal counts the replicas removed by scaling operations
+	ReplicasRemovedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "replicas_removed_total",
+			Help:      "Total number of replicas removed by scaling operations",
+		},
+		stableLabels,
+	)
+
+	// ClampedOperationsTotal counts scaling operations whose target was bound by the min/max replicas
+	ClampedOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "clamped_operations_total",
+			Help:      "Total number of scaling operations clamped to the minimum or maximum replicas",
+		},
+		stableLabels,
+	)
+
+	// NotificationsSuppressedTotal counts override notifications that were not
+	// delivered on their own. Scaling operations are still counted in full.
+	NotificationsSuppressedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "notifications_suppressed_total",
+			Help:      "Total number of override notifications dropped as duplicates, rate limited or lost to a full queue",
+		},
+		[]string{LabelOverride, LabelNamespace, LabelReason},
+	)
+
+	// EffectivePercentage reports the percentage currently applied per override and target kind
+	EffectivePercentage = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "effective_percentage",
+			Help:      "Percentage currently applied to targets",
+		},
+		stableLabels,
+	)
+
+	// EstimatedHourlyCostDelta reports the estimated hourly cost difference of the
+	// current scaling state against the original replicas
+	EstimatedHourlyCostDelta = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "estimated_hourly_cost_delta",
+			Help:      "Estimated hourly cost delta of the current scaling state compared to the original replicas",
+		},
+		stableLabels,
+	)
+
+	// MonitoredReplicaDelta reports the replicas overrides in Monitor mode
+	// would add, or remove when negative, if they were enforced
+	MonitoredReplicaDelta = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "monitored_replica_delta",
+			Help:      "Replicas an override in Monitor mode would add (positive) or remove (negative) if it were enforced",
+		},
+		stableLabels,
+	)
+
+	// ScaleBudgetThrottled is 1 while the scale budget of the global config is
+	// exhausted and replica changes are deferred
+	ScaleBudgetThrottled = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "scale_budget_throttled",
+			Help:      "Whether replica changes are deferred because the scale budget is exhausted (1) or not (0)",
+		},
+	)
+
+	// ReconcileErrorsTotal counts the failures of reconcile passes and of the
+	// targets they process, by failure reason
+	ReconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "reconcile_errors_total",
+			Help:      "Total number of failures reconciling targets, by reason: TargetNotFound, Conflict, Forbidden, QuotaExceeded, CapacityExceeded, InvalidConfig, Unavailable or Unknown",
+		},
+		append(append([]string{}, stableLabels...), LabelReason),
+	)
+
+	// DeferredOperationsTotal counts replica changes deferred by the scale budget
+	DeferredOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "deferred_operations_total",
+			Help:      "Total number of scaling operations deferred because the scale budget was exhausted",
+		},
+		stableLabels,
+	)
+
+	// DecisionWebhookDecisionsTotal counts the replica changes submitted to the
+	// decision webhook by result: allowed, denied, modified or error
+	DecisionWebhookDecisionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "decision_webhook_decisions_total",
+			Help:      "Total number of replica changes submitted to the decision webhook, by result",
+		},
+		append(append([]string{}, stableLabels...), LabelResult),
+	)
+
+	// StartupAuditInconsistencies reports the inconsistencies between
+	// annotations, overrides and live replicas the startup audit found and fixed
+	StartupAuditInconsistencies = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "startup_audit_inconsistencies",
+			Help:      "Inconsistencies between annotations, overrides and live replicas found and fixed by the startup audit, per check",
+		},
+		[]string{LabelCheck, LabelResult},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ScalingOperationsTotal,
+		ScalingErrorsTotal,
+		ReconcileErrorsTotal,
+		ReplicaDelta,
+		ReplicasAddedTotal,
+		ReplicasRemovedTotal,
+		ClampedOperationsTotal,
+		NotificationsSuppressedTotal,
+		EffectivePercentage,
+		EstimatedHourlyCostDelta,
+		MonitoredReplicaDelta,
+		ScaleBudgetThrottled,
+		DeferredOperationsTotal,
+		DecisionWebhookDecisionsTotal,
+		StartupAuditInconsistencies,
+	)
+}
+
+// ScalingLabels holds the stable label values describing a scaling operation
+type ScalingLabels struct {
+	// Override is the name of the ReplicasOverride, or GlobalOverride
+	Override string
+	// Namespace is the namespace of the target
+	Namespace string
+	// TargetKind is the kind of the scaled object
+	TargetKind string
+	// Trigger is what caused the percentage to be applied
+	Trigger string
+}
+
+// values returns the label values in the order of stableLabels
+func (l ScalingLabels) values() []string {
+	override := l.Override
+	if override == "" {
+		override = GlobalOverride
+	}
+	return []string{override, l.Namespace, l.TargetKind, l.Trigger}
+}
+
+// RecordScaling records a successful scaling operation from previous to target
+// replicas, clamped if the target was bound by the min/max replicas
+func RecordScaling(ctx context.Context, labels ScalingLabels, previous, target, percentage int32, clamped bool) {
+	values := labels.values()
+	exemplar := exemplarFromContext(ctx)
+
+	counter := ScalingOperationsTotal.WithLabelValues(values...)
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && exemplar != nil {
+		adder.AddWithExemplar(1, exemplar)
+	} else {
+		counter.Inc()
+	}
+
+	delta := float64(target - previous)
+	observer := ReplicaDelta.WithLabelValues(values...)
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok && exemplar != nil {
+		eo.ObserveWithExemplar(delta, exemplar)
+	} else {
+		observer.Observe(delta)
+	}
+	if delta > 0 {
+		ReplicasAddedTotal.WithLabelValues(values...).Add(delta)
+	} else if delta < 0 {
+		ReplicasRemovedTotal.WithLabelValues(values...).Add(-delta)
+	}
+	if clamped {
+		ClampedOperationsTotal.WithLabelValues(values...).Inc()
+	}
+
+	EffectivePercentage.WithLabelValues(values...).Set(float64(percentage))
+}
+
+// SetEstimatedHourlyCostDelta reports the estimated hourly cost delta for labels
+func SetEstimatedHourlyCostDelta(labels ScalingLabels, delta float64) {
+	EstimatedHourlyCostDelta.WithLabelValues(labels.values()...).Set(delta)
+}
+
+// SetMonitoredReplicaDelta reports the replicas an override in Monitor mode would change
+func SetMonitoredReplicaDelta(labels ScalingLabels, delta int32) {
+	MonitoredReplicaDelta.WithLabelValues(labels.values()...).Set(float64(delta))
+}
+
+// RecordDeferred records a scaling operation deferred by the scale budget
+func RecordDeferred(labels ScalingLabels) {
+	DeferredOperationsTotal.WithLabelValues(labels.values()...).Inc()
+}
+
+// RecordDecision records the result of a replica change submitted to the decision webhook
+func RecordDecision(labels ScalingLabels, result string) {
+	DecisionWebhookDecisionsTotal.WithLabelValues(append(labels.values(), result)...).Inc()
+}
+
+// SetScaleBudgetThrottled reports whether the scale budget defers replica changes
+func SetScaleBudgetThrottled(throttled bool) {
+	if throttled {
+		ScaleBudgetThrottled.Set(1)
+	} else {
+		ScaleBudgetThrottled.Set(0)
+	}
+}
+
+// SetAuditInconsistencies reports the inconsistencies of a check found and fixed by the startup audit
+func SetAuditInconsistencies(check string, found, fixed int) {
+	StartupAuditInconsistencies.WithLabelValues(check, AuditResultFound).Set(float64(found))
+	StartupAuditInconsistencies.WithLabelValues(check, AuditResultFixed).Set(float64(fixed))
+}
+
+// RecordNotificationSuppressed records a notification of an override not delivered for reason
+func RecordNotificationSuppressed(namespace, override, reason string) {
+	NotificationsSuppressedTotal.WithLabelValues(override, namespace, reason).Inc()
+}
+
+// RecordScalingError records a failed scaling operation
+func RecordScalingError(ctx context.Context, labels ScalingLabels) {
+	counter := ScalingErrorsTotal.WithLabelValues(labels.values()...)
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok {
+		if exemplar := exemplarFromContext(ctx); exemplar != nil {
+			adder.AddWithExemplar(1, exemplar)
+			return
+		}
+	}
+	counter.Inc()
+}
+
+// RecordReconcileError records a failure of reason reconciling the target of labels
+func RecordReconcileError(labels ScalingLabels, reason string) {
+	ReconcileErrorsTotal.WithLabelValues(append(labels.values(), reason)...).Inc()
+}
+
+// exemplarFromContext returns an exemplar carrying the trace ID of the span in ctx,
+// or nil if ctx carries no valid span
+func exemplarFromContext(ctx context.Context) prometheus.Labels {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return nil
+	}
+	return prometheus.Labels{traceIDExemplarKey: spanContext.TraceID().String()}
+}
+
+// OpenMetricsHandler returns a handler serving the controller registry in the
+// OpenMetrics format so that exemplars are exposed to Prometheus and Grafana
+func OpenMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: true,
+	})
+}
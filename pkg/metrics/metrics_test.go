@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecordScalingUsesStableLabels(t *testing.T) {
+	labels := ScalingLabels{
+		Namespace:  "shop",
+		TargetKind: TargetKindDeployment,
+		Trigger:    TriggerGlobal,
+	}
+
+	RecordScaling(context.Background(), labels, 2, 4, 200, true)
+
+	if got := testutil.ToFloat64(ScalingOperationsTotal.WithLabelValues(GlobalOverride, "shop", TargetKindDeployment, TriggerGlobal)); got != 1 {
+		t.Errorf("scaling_operations_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(ReplicasAddedTotal.WithLabelValues(GlobalOverride, "shop", TargetKindDeployment, TriggerGlobal)); got != 2 {
+		t.Errorf("replicas_added_total = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(ClampedOperationsTotal.WithLabelValues(GlobalOverride, "shop", TargetKindDeployment, TriggerGlobal)); got != 1 {
+		t.Errorf("clamped_operations_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(EffectivePercentage.WithLabelValues(GlobalOverride, "shop", TargetKindDeployment, TriggerGlobal)); got != 200 {
+		t.Errorf("effective_percentage = %v, want 200", got)
+	}
+}
+
+func TestExemplarFromContext(t *testing.T) {
+	if exemplar := exemplarFromContext(context.Background()); exemplar != nil {
+		t.Errorf("expected no exemplar without a span, got %v", exemplar)
+	}
+
+	traceID := trace.TraceID{0x01, 0x02, 0x03}
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{0x01},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	exemplar := exemplarFromContext(ctx)
+	if exemplar[traceIDExemplarKey] != traceID.String() {
+		t.Errorf("exemplar trace_id = %q, want %q", exemplar[traceIDExemplarKey], traceID.String())
+	}
+}
@@ -0,0 +1,324 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// otlpExporterName is the pusher key of the OTLP sink
+	otlpExporterName = "otlp"
+
+	// otlpMetricsPath is the default OTLP/HTTP metrics path
+	otlpMetricsPath = "/v1/metrics"
+
+	// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+	otlpCumulative = 2
+
+	// otlpTimeout bounds a single export request
+	otlpTimeout = 10 * time.Second
+)
+
+// processStart is the start time of the cumulative series, which the registry
+// counts from the start of the process, whenever the exporter was created
+var processStart = unixNano(time.Now())
+
+// OTLPExporter pushes metric snapshots to an OpenTelemetry collector using
+// OTLP/HTTP with JSON encoding. Prometheus counters and histograms are sent
+// as cumulative sums and histograms.
+type OTLPExporter struct {
+	client    *http.Client
+	endpoint  string
+	headers   map[string]string
+	resource  otlpResource
+	startTime string
+}
+
+// NewOTLPExporter creates an exporter sending to cfg.Endpoint
+func NewOTLPExporter(cfg config.OTLPConfig) (*OTLPExporter, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", cfg.Endpoint, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", cfg.Endpoint)
+	}
+	if endpoint.Path == "" || endpoint.Path == "/" {
+		endpoint.Path = otlpMetricsPath
+	}
+
+	attributes := []otlpAttribute{stringAttribute("service.name", Namespace)}
+	for key, value := range cfg.ResourceAttributes {
+		attributes = append(attributes, stringAttribute(key, value))
+	}
+
+	return &OTLPExporter{
+		client:    &http.Client{Timeout: otlpTimeout},
+		endpoint:  endpoint.String(),
+		headers:   cfg.Headers,
+		resource:  otlpResource{Attributes: attributes},
+		startTime: processStart,
+	}, nil
+}
+
+// Export implements Exporter
+func (e *OTLPExporter) Export(ctx context.Context, families []*dto.MetricFamily) error {
+	now := unixNano(time.Now())
+	var metrics []otlpMetric
+	for _, family := range families {
+		if metric, ok := e.convert(family, now); ok {
+			metrics = append(metrics, metric)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: e.resource,
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: Namespace},
+				Metrics: metrics,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push OTLP metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close implements Exporter
+func (e *OTLPExporter) Close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// convert maps a Prometheus metric family to an OTLP metric
+func (e *OTLPExporter) convert(family *dto.MetricFamily, now string) (otlpMetric, bool) {
+	metric := otlpMetric{Name: family.GetName(), Description: family.GetHelp()}
+
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		sum := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+		for _, m := range family.GetMetric() {
+			sum.DataPoints = append(sum.DataPoints, e.numberPoint(m, m.GetCounter().GetValue(), now))
+		}
+		metric.Sum = sum
+	case dto.MetricType_GAUGE:
+		gauge := &otlpGauge{}
+		for _, m := range family.GetMetric() {
+			gauge.DataPoints = append(gauge.DataPoints, e.numberPoint(m, m.GetGauge().GetValue(), now))
+		}
+		metric.Gauge = gauge
+	case dto.MetricType_UNTYPED:
+		gauge := &otlpGauge{}
+		for _, m := range family.GetMetric() {
+			gauge.DataPoints = append(gauge.DataPoints, e.numberPoint(m, m.GetUntyped().GetValue(), now))
+		}
+		metric.Gauge = gauge
+	case dto.MetricType_HISTOGRAM:
+		histogram := &otlpHistogram{AggregationTemporality: otlpCumulative}
+		for _, m := range family.GetMetric() {
+			histogram.DataPoints = append(histogram.DataPoints, e.histogramPoint(m, now))
+		}
+		metric.Histogram = histogram
+	case dto.MetricType_SUMMARY:
+		summary := &otlpSummary{}
+		for _, m := range family.GetMetric() {
+			s := m.GetSummary()
+			point := otlpSummaryPoint{
+				Attributes:        labelAttributes(m.GetLabel()),
+				StartTimeUnixNano: e.startTime,
+				TimeUnixNano:      now,
+				Count:             strconv.FormatUint(s.GetSampleCount(), 10),
+				Sum:               s.GetSampleSum(),
+			}
+			for _, q := range s.GetQuantile() {
+				point.QuantileValues = append(point.QuantileValues, otlpQuantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+			}
+			summary.DataPoints = append(summary.DataPoints, point)
+		}
+		metric.Summary = summary
+	default:
+		return otlpMetric{}, false
+	}
+	return metric, true
+}
+
+// numberPoint builds a sum/gauge data point
+func (e *OTLPExporter) numberPoint(m *dto.Metric, value float64, now string) otlpNumberPoint {
+	return otlpNumberPoint{
+		Attributes:        labelAttributes(m.GetLabel()),
+		StartTimeUnixNano: e.startTime,
+		TimeUnixNano:      now,
+		AsDouble:          value,
+	}
+}
+
+// histogramPoint converts cumulative Prometheus buckets into OTLP per-bucket counts
+func (e *OTLPExporter) histogramPoint(m *dto.Metric, now string) otlpHistogramPoint {
+	h := m.GetHistogram()
+	point := otlpHistogramPoint{
+		Attributes:        labelAttributes(m.GetLabel()),
+		StartTimeUnixNano: e.startTime,
+		TimeUnixNano:      now,
+		Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+		Sum:               h.GetSampleSum(),
+	}
+
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(bucket.GetCumulativeCount()-previous, 10))
+		previous = bucket.GetCumulativeCount()
+	}
+	// The implicit +Inf bucket holds whatever is left
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(h.GetSampleCount()-previous, 10))
+	return point
+}
+
+// labelAttributes converts Prometheus labels to OTLP attributes
+func labelAttributes(labels []*dto.LabelPair) []otlpAttribute {
+	attributes := make([]otlpAttribute, 0, len(labels))
+	for _, label := range labels {
+		attributes = append(attributes, stringAttribute(label.GetName(), label.GetValue()))
+	}
+	return attributes
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// The types below mirror the JSON encoding of the OTLP ExportMetricsServiceRequest.
+// 64-bit integers are encoded as strings as required by the protobuf JSON mapping.
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Summary     *otlpSummary   `json:"summary,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberPoint `json:"dataPoints"`
+	AggregationTemporality int               `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberPoint `json:"dataPoints"`
+}
+
+type otlpNumberPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramPoint `json:"dataPoints"`
+	AggregationTemporality int                  `json:"aggregationTemporality"`
+}
+
+type otlpHistogramPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryPoint `json:"dataPoints"`
+}
+
+type otlpSummaryPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	QuantileValues    []otlpQuantile  `json:"quantileValues,omitempty"`
+}
+
+type otlpQuantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestOTLPExporterPostsJSON(t *testing.T) {
+	var received otlpRequest
+	var path, header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		header = r.Header.Get("X-Tenant")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	exporter, err := NewOTLPExporter(config.OTLPConfig{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"X-Tenant": "platform"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+
+	histogram := &dto.MetricFamily{
+		Name: proto.String("delta"),
+		Type: dto.MetricType_HISTOGRAM.Enum(),
+		Metric: []*dto.Metric{{
+			Histogram: &dto.Histogram{
+				SampleCount: proto.Uint64(3),
+				SampleSum:   proto.Float64(4),
+				Bucket: []*dto.Bucket{
+					{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(1)},
+					{UpperBound: proto.Float64(2), CumulativeCount: proto.Uint64(2)},
+				},
+			},
+		}},
+	}
+	if err := exporter.Export(context.Background(), []*dto.MetricFamily{counterFamily("ops_total", 7), histogram}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	if path != otlpMetricsPath {
+		t.Errorf("path = %q, want %q", path, otlpMetricsPath)
+	}
+	if header != "platform" {
+		t.Errorf("X-Tenant header = %q, want platform", header)
+	}
+	metrics := received.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 2 {
+		t.Fatalf("got %d metrics, want 2", len(metrics))
+	}
+	if sum := metrics[0].Sum; sum == nil || !sum.IsMonotonic || sum.DataPoints[0].AsDouble != 7 {
+		t.Errorf("unexpected counter conversion: %+v", metrics[0])
+	}
+	buckets := metrics[1].Histogram.DataPoints[0].BucketCounts
+	if want := []string{"1", "1", "1"}; len(buckets) != 3 || buckets[0] != want[0] || buckets[1] != want[1] || buckets[2] != want[2] {
+		t.Errorf("bucketCounts = %v, want %v", buckets, want)
+	}
+}
+
+func TestNewOTLPExporterRejectsInvalidScheme(t *testing.T) {
+	if _, err := NewOTLPExporter(config.OTLPConfig{Endpoint: "grpc://collector:4317"}); err == nil {
+		t.Error("expected an error for a non-HTTP endpoint")
+	}
+}
+
+func TestOTLPExporterStartTimeSurvivesRecreation(t *testing.T) {
+	first, err := NewOTLPExporter(config.OTLPConfig{Endpoint: "http://collector:4318"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	// A config reload recreating the exporter must not restart the cumulative series
+	second, err := NewOTLPExporter(config.OTLPConfig{Endpoint: "http://collector:4318", Headers: map[string]string{"x-team": "shop"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.startTime != second.startTime {
+		t.Errorf("startTime changed from %s to %s", first.startTime, second.startTime)
+	}
+}
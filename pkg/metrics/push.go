@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// exportedPrefixes selects the metric families forwarded to push-based sinks:
+// the scaling metrics plus the controller-runtime reconcile and workqueue metrics.
+var exportedPrefixes = []string{
+	Namespace + "_",
+	"controller_runtime_reconcile_",
+	"workqueue_",
+}
+
+// Exporter pushes gathered metric families to an external system
+type Exporter interface {
+	// Export sends one snapshot of the metric families
+	Export(ctx context.Context, families []*dto.MetricFamily) error
+	// Close releases the resources held by the exporter
+	Close() error
+}
+
+// Pusher periodically gathers the controller registry and forwards the
+// snapshot to the configured exporters. It implements manager.Runnable.
+type Pusher struct {
+	gatherer  prometheus.Gatherer
+	mutex     sync.Mutex
+	exporters map[string]Exporter
+	// applied holds the config each exporter was created from
+	applied  map[string]any
+	interval time.Duration
+	reset    chan struct{}
+}
+
+// NewPusher creates a pusher gathering from the controller-runtime registry
+func NewPusher() *Pusher {
+	return &Pusher{
+		gatherer:  ctrlmetrics.Registry,
+		exporters: make(map[string]Exporter),
+		applied:   make(map[string]any),
+		interval:  config.DefaultPushInterval,
+		reset:     make(chan struct{}, 1),
+	}
+}
+
+// SetExporter installs the exporter under name, replacing and closing any
+// previous one. A nil exporter removes the sink.
+func (p *Pusher) SetExporter(name string, exporter Exporter) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if previous, ok := p.exporters[name]; ok {
+		_ = previous.Close()
+		delete(p.exporters, name)
+		delete(p.applied, name)
+	}
+	if exporter != nil {
+		p.exporters[name] = exporter
+	}
+}
+
+// configured returns true if the exporter under name was created from cfg,
+// so a reload keeps it and its state, e.g. the last StatsD counter values
+func (p *Pusher) configured(name string, cfg any) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	_, ok := p.exporters[name]
+	return ok && reflect.DeepEqual(p.applied[name], cfg)
+}
+
+// setConfiguredExporter installs exporter under name, recording the config it was created from
+func (p *Pusher) setConfiguredExporter(name string, exporter Exporter, cfg any) {
+	p.SetExporter(name, exporter)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.applied[name] = cfg
+}
+
+// ApplyConfig reconfigures the sinks from the global configuration.
+// It is meant to be registered as a config.Listener.
+func (p *Pusher) ApplyConfig(cfg *config.GlobalConfig) {
+	log := log.Log.WithName("metrics.Pusher")
+
+	interval := cfg.Metrics.PushInterval
+	if interval <= 0 {
+		interval = config.DefaultPushInterval
+	}
+	p.mutex.Lock()
+	changed := p.interval != interval
+	p.interval = interval
+	p.mutex.Unlock()
+	if changed {
+		select {
+		case p.reset <- struct{}{}:
+		default:
+		}
+	}
+
+	// Exporters are only replaced when their own config changed
+	if cfg.Metrics.OTLP == nil || cfg.Metrics.OTLP.Endpoint == "" {
+		p.SetExporter(otlpExporterName, nil)
+	} else if !p.configured(otlpExporterName, *cfg.Metrics.OTLP) {
+		exporter, err := NewOTLPExporter(*cfg.Metrics.OTLP)
+		if err != nil {
+			log.Error(err, "Failed to configure OTLP exporter", "endpoint", cfg.Metrics.OTLP.Endpoint)
+			p.SetExporter(otlpExporterName, nil)
+		} else {
+			p.setConfiguredExporter(otlpExporterName, exporter, *cfg.Metrics.OTLP)
+		}
+	}
+
+	if cfg.Metrics.StatsD == nil || cfg.Metrics.StatsD.Address == "" {
+		p.SetExporter(statsDExporterName, nil)
+	} else if !p.configured(statsDExporterName, *cfg.Metrics.StatsD) {
+		exporter, err := NewStatsDExporter(*cfg.Metrics.StatsD)
+		if err != nil {
+			log.Error(err, "Failed to configure StatsD exporter", "address", cfg.Metrics.StatsD.Address)
+			p.SetExporter(statsDExporterName, nil)
+		} else {
+			p.setConfiguredExporter(statsDExporterName, exporter, *cfg.Metrics.StatsD)
+		}
+	}
+}
+
+// Start runs the push loop until ctx is cancelled
+func (p *Pusher) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("metrics.Pusher")
+
+	p.mutex.Lock()
+	ticker := time.NewTicker(p.interval)
+	p.mutex.Unlock()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.closeAll()
+			return nil
+		case <-p.reset:
+			p.mutex.Lock()
+			ticker.Reset(p.interval)
+			p.mutex.Unlock()
+		case <-ticker.C:
+			if err := p.push(ctx); err != nil {
+				log.Error(err, "Failed to push metrics")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica pushes its own metrics
+func (p *Pusher) NeedLeaderElection() bool {
+	return false
+}
+
+// push gathers one snapshot and sends it to every exporter
+func (p *Pusher) push(ctx context.Context) error {
+	p.mutex.Lock()
+	exporters := make(map[string]Exporter, len(p.exporters))
+	for name, exporter := range p.exporters {
+		exporters[name] = exporter
+	}
+	p.mutex.Unlock()
+
+	if len(exporters) == 0 {
+		return nil
+	}
+
+	gathered, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	families := filterFamilies(gathered)
+
+	var firstErr error
+	for _, exporter := range exporters {
+		if err := exporter.Export(ctx, families); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// closeAll closes and removes every exporter
+func (p *Pusher) closeAll() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for name, exporter := range p.exporters {
+		_ = exporter.Close()
+		delete(p.exporters, name)
+		delete(p.applied, name)
+	}
+}
+
+// filterFamilies keeps only the metric families listed in exportedPrefixes
+func filterFamilies(families []*dto.MetricFamily) []*dto.MetricFamily {
+	var filtered []*dto.MetricFamily
+	for _, family := range families {
+		for _, prefix := range exportedPrefixes {
+			if strings.HasPrefix(family.GetName(), prefix) {
+				filtered = append(filtered, family)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// LabelController is the controller label of the work queue metrics
+const LabelController = "controller"
+
+var (
+	// queueDepthDesc describes the number of items waiting in the work queue of a controller
+	queueDepthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "workqueue", "depth"),
+		"Number of items waiting in the work queue of a controller",
+		[]string{LabelController}, nil,
+	)
+
+	// queueOldestItemAgeDesc describes how long the oldest waiting item has been ready
+	queueOldestItemAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(Namespace, "workqueue", "oldest_item_age_seconds"),
+		"Seconds the oldest item waiting in the work queue of a controller has been ready to be reconciled",
+		[]string{LabelController}, nil,
+	)
+
+	// ReconcileLatency observes the time from an item being ready in the work
+	// queue of a controller to the end of its reconcile
+	ReconcileLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "reconcile_latency_seconds",
+			Help:      "Seconds from an item being ready in the work queue of a controller to the end of its reconcile",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 15),
+		},
+		[]string{LabelController},
+	)
+
+	// queues holds the queues reported by the work queue collector
+	queues = &queueCollector{queues: make(map[string]ageReporter)}
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(queues, ReconcileLatency)
+}
+
+// ageReporter is a work queue reporting its depth and the age of its oldest item
+type ageReporter interface {
+	Len() int
+	oldestItemAge(now time.Time) time.Duration
+}
+
+// queueCollector reports the depth and oldest item age of the queue of every controller
+type queueCollector struct {
+	mu     sync.Mutex
+	queues map[string]ageReporter
+}
+
+// Describe implements prometheus.Collector
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- queueOldestItemAgeDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for name, queue := range c.queues {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(queue.Len()), name)
+		ch <- prometheus.MustNewConstMetric(queueOldestItemAgeDesc, prometheus.GaugeValue, queue.oldestItemAge(now).Seconds(), name)
+	}
+}
+
+// NewQueue returns the default rate limited work queue of a controller,
+// reporting its depth, the age of its oldest item and the latency of its
+// items. It is meant for the NewQueue option of controllers.
+func NewQueue[T comparable](controllerName string, rateLimiter workqueue.TypedRateLimiter[T]) workqueue.TypedRateLimitingInterface[T] {
+	q := newAgedQueue(controllerName, rateLimiter, workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter,
+		workqueue.TypedRateLimitingQueueConfig[T]{Name: controllerName}), time.Now)
+	queues.mu.Lock()
+	queues.queues[controllerName] = q
+	queues.mu.Unlock()
+	return q
+}
+
+// itemTimes is when an item became ready to be reconciled, and when it
+// becomes ready again after a delayed add
+type itemTimes struct {
+	ready   time.Time
+	waiting time.Time
+}
+
+// agedQueue wraps a rate limited work queue to track when its items became ready
+type agedQueue[T comparable] struct {
+	workqueue.TypedRateLimitingInterface[T]
+	name        string
+	rateLimiter workqueue.TypedRateLimiter[T]
+	now         func() time.Time
+
+	mu sync.Mutex
+	// items are the items added and not handed out yet
+	items map[T]*itemTimes
+	// processing holds when the items handed out were ready
+	processing map[T]time.Time
+}
+
+func newAgedQueue[T comparable](name string, rateLimiter workqueue.TypedRateLimiter[T], queue workqueue.TypedRateLimitingInterface[T], now func() time.Time) *agedQueue[T] {
+	return &agedQueue[T]{
+		TypedRateLimitingInterface: queue,
+		name:                       name,
+		rateLimiter:                rateLimiter,
+		now:                        now,
+		items:                      make(map[T]*itemTimes),
+		processing:                 make(map[T]time.Time),
+	}
+}
+
+// track records that item is ready at the given time, keeping the earliest
+// time of each kind like the queue deduplicates items
+func (q *agedQueue[T]) track(item T, at time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	times, ok := q.items[item]
+	if !ok {
+		times = &itemTimes{}
+		q.items[item] = times
+	}
+	field := &times.ready
+	if at.After(q.now()) {
+		field = &times.waiting
+	}
+	if field.IsZero() || at.Before(*field) {
+		*field = at
+	}
+}
+
+// promote moves the delayed adds whose time came to the ready items
+func (q *agedQueue[T]) promote(now time.Time) {
+	for item, times := range q.items {
+		if !times.waiting.IsZero() && !times.waiting.After(now) {
+			if times.ready.IsZero() || times.waiting.Before(times.ready) {
+				times.ready = times.waiting
+			}
+			times.waiting = time.Time{}
+		}
+		if times.ready.IsZero() && times.waiting.IsZero() {
+			delete(q.items, item)
+		}
+	}
+}
+
+// Add implements workqueue.TypedInterface
+func (q *agedQueue[T]) Add(item T) {
+	q.track(item, q.now())
+	q.TypedRateLimitingInterface.Add(item)
+}
+
+// AddAfter implements workqueue.TypedDelayingInterface
+func (q *agedQueue[T]) AddAfter(item T, duration time.Duration) {
+	q.track(item, q.now().Add(duration))
+	q.TypedRateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited implements workqueue.TypedRateLimitingInterface, asking the
+// rate limiter for the delay like the wrapped queue does
+func (q *agedQueue[T]) AddRateLimited(item T) {
+	q.AddAfter(item, q.rateLimiter.When(item))
+}
+
+// Get implements workqueue.TypedInterface
+func (q *agedQueue[T]) Get() (T, bool) {
+	item, shutdown := q.TypedRateLimitingInterface.Get()
+	if shutdown {
+		return item, shutdown
+	}
+	now := q.now()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.promote(now)
+	ready := now
+	if times, ok := q.items[item]; ok {
+		if !times.ready.IsZero() {
+			ready = times.ready
+		}
+		times.ready = time.Time{}
+		if times.waiting.IsZero() {
+			delete(q.items, item)
+		}
+	}
+	q.processing[item] = ready
+	return item, shutdown
+}
+
+// Done implements workqueue.TypedInterface
+func (q *agedQueue[T]) Done(item T) {
+	q.mu.Lock()
+	if ready, ok := q.processing[item]; ok {
+		ReconcileLatency.WithLabelValues(q.name).Observe(q.now().Sub(ready).Seconds())
+		delete(q.processing, item)
+	}
+	q.mu.Unlock()
+	q.TypedRateLimitingInterface.Done(item)
+}
+
+// oldestItemAge returns how long the oldest item waiting in the queue has been ready
+func (q *agedQueue[T]) oldestItemAge(now time.Time) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.promote(now)
+	var oldest time.Duration
+	for _, times := range q.items {
+		if !times.ready.IsZero() && now.Sub(times.ready) > oldest {
+			oldest = now.Sub(times.ready)
+		}
+	}
+	return oldest
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestAgedQueue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	rateLimiter := workqueue.DefaultTypedControllerRateLimiter[string]()
+	q := newAgedQueue("test-queue", rateLimiter, workqueue.NewTypedRateLimitingQueue(rateLimiter), clock)
+	defer q.ShutDown()
+
+	q.Add("shop/web")
+	q.AddAfter("shop/cart", time.Hour)
+	now = now.Add(30 * time.Second)
+	q.Add("shop/web")
+	if got := q.oldestItemAge(now); got != 30*time.Second {
+		t.Errorf("oldestItemAge() = %v, want 30s from the first add", got)
+	}
+	if got := q.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1 item ready", got)
+	}
+
+	item, _ := q.Get()
+	if item != "shop/web" {
+		t.Fatalf("Get() = %q, want shop/web", item)
+	}
+	now = now.Add(5 * time.Second)
+	q.Done(item)
+	if got := q.oldestItemAge(now); got != 0 {
+		t.Errorf("oldestItemAge() = %v, want 0 once the ready item is handed out", got)
+	}
+
+	metric := &dto.Metric{}
+	if err := ReconcileLatency.WithLabelValues("test-queue").(prometheus.Histogram).Write(metric); err != nil {
+		t.Fatalf("failed to read the latency histogram: %v", err)
+	}
+	if got := metric.GetHistogram(); got.GetSampleCount() != 1 || got.GetSampleSum() != 35 {
+		t.Errorf("reconcile_latency_seconds = %d samples summing %vs, want 1 of 35s", got.GetSampleCount(), got.GetSampleSum())
+	}
+
+	// The delayed add is ready an hour after it was made
+	now = now.Add(2 * time.Hour)
+	if got := q.oldestItemAge(now); got != time.Hour+35*time.Second {
+		t.Errorf("oldestItemAge() = %v, want the age of the delayed add", got)
+	}
+}
@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// statsDExporterName is the pusher key of the StatsD sink
+	statsDExporterName = "statsd"
+
+	// maxStatsDPacketSize keeps UDP packets below the common Ethernet MTU
+	maxStatsDPacketSize = 1432
+)
+
+// StatsDExporter pushes metric snapshots to a StatsD or DogStatsD agent over UDP.
+// Counters are sent as deltas since the previous push, gauges as absolute values,
+// and histograms/summaries as their count and sum.
+type StatsDExporter struct {
+	conn      net.Conn
+	prefix    string
+	dogStatsD bool
+	tags      []string
+	previous  map[string]float64
+}
+
+// NewStatsDExporter creates an exporter sending to cfg.Address
+func NewStatsDExporter(cfg config.StatsDConfig) (*StatsDExporter, error) {
+	conn, err := net.Dial("udp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial StatsD agent %s: %w", cfg.Address, err)
+	}
+	prefix := cfg.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, ".") {
+		prefix += "."
+	}
+	return &StatsDExporter{
+		conn:      conn,
+		prefix:    prefix,
+		dogStatsD: cfg.DogStatsD,
+		tags:      cfg.Tags,
+		previous:  make(map[string]float64),
+	}, nil
+}
+
+// Export implements Exporter
+func (e *StatsDExporter) Export(_ context.Context, families []*dto.MetricFamily) error {
+	var lines []string
+	for _, family := range families {
+		name := family.GetName()
+		for _, metric := range family.GetMetric() {
+			labels := metric.GetLabel()
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				lines = e.appendCounter(lines, name, labels, metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = append(lines, e.line(name, labels, metric.GetGauge().GetValue(), "g"))
+			case dto.MetricType_HISTOGRAM:
+				h := metric.GetHistogram()
+				lines = e.appendCounter(lines, name+"_count", labels, float64(h.GetSampleCount()))
+				lines = e.appendCounter(lines, name+"_sum", labels, h.GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				s := metric.GetSummary()
+				lines = e.appendCounter(lines, name+"_count", labels, float64(s.GetSampleCount()))
+				lines = e.appendCounter(lines, name+"_sum", labels, s.GetSampleSum())
+			default:
+				lines = append(lines, e.line(name, labels, metric.GetUntyped().GetValue(), "g"))
+			}
+		}
+	}
+	return e.send(lines)
+}
+
+// Close implements Exporter
+func (e *StatsDExporter) Close() error {
+	return e.conn.Close()
+}
+
+// appendCounter appends the delta of a cumulative value since the previous push.
+// The first observation of a series only records the baseline.
+func (e *StatsDExporter) appendCounter(lines []string, name string, labels []*dto.LabelPair, value float64) []string {
+	key := seriesKey(name, labels)
+	previous, seen := e.previous[key]
+	e.previous[key] = value
+	if !seen {
+		return lines
+	}
+	delta := value - previous
+	if delta <= 0 {
+		// Zero deltas carry no information, negative ones mean the process restarted
+		return lines
+	}
+	return append(lines, e.line(name, labels, delta, "c"))
+}
+
+// line formats one StatsD line, encoding labels as tags (DogStatsD) or name segments
+func (e *StatsDExporter) line(name string, labels []*dto.LabelPair, value float64, metricType string) string {
+	var b strings.Builder
+	b.WriteString(e.prefix)
+	b.WriteString(name)
+	if !e.dogStatsD {
+		for _, label := range labels {
+			b.WriteString(".")
+			b.WriteString(sanitizeStatsD(label.GetValue()))
+		}
+	}
+	b.WriteString(":")
+	b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	b.WriteString("|")
+	b.WriteString(metricType)
+
+	if e.dogStatsD {
+		tags := make([]string, 0, len(labels)+len(e.tags))
+		for _, label := range labels {
+			tags = append(tags, label.GetName()+":"+sanitizeStatsD(label.GetValue()))
+		}
+		tags = append(tags, e.tags...)
+		if len(tags) > 0 {
+			b.WriteString("|#")
+			b.WriteString(strings.Join(tags, ","))
+		}
+	}
+	return b.String()
+}
+
+// send writes the lines in packets no larger than maxStatsDPacketSize
+func (e *StatsDExporter) send(lines []string) error {
+	var packet strings.Builder
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := e.conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > maxStatsDPacketSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteString("\n")
+		}
+		packet.WriteString(line)
+	}
+	return flush()
+}
+
+// seriesKey identifies a series by name and sorted label pairs
+func seriesKey(name string, labels []*dto.LabelPair) string {
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, label.GetName()+"="+label.GetValue())
+	}
+	sort.Strings(pairs)
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+// sanitizeStatsD replaces characters that have a meaning in the StatsD line protocol
+func sanitizeStatsD(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', ',', '#', '@', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, value)
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func counterFamily(name string, value float64) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: proto.String(name),
+		Type: dto.MetricType_COUNTER.Enum(),
+		Metric: []*dto.Metric{{
+			Label:   []*dto.LabelPair{{Name: proto.String(LabelNamespace), Value: proto.String("shop")}},
+			Counter: &dto.Counter{Value: proto.Float64(value)},
+		}},
+	}
+}
+
+func TestStatsDExporterSendsCounterDeltas(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	exporter, err := NewStatsDExporter(config.StatsDConfig{
+		Address:   listener.LocalAddr().String(),
+		Prefix:    "k8s",
+		DogStatsD: true,
+		Tags:      []string{"env:test"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create exporter: %v", err)
+	}
+	defer exporter.Close()
+
+	ctx := context.Background()
+	// The first push only records the baseline
+	if err := exporter.Export(ctx, []*dto.MetricFamily{counterFamily("ops_total", 3)}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if err := exporter.Export(ctx, []*dto.MetricFamily{counterFamily("ops_total", 5)}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	buf := make([]byte, maxStatsDPacketSize)
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+
+	want := "k8s.ops_total:2|c|#namespace:shop,env:test"
+	if got := strings.TrimSpace(string(buf[:n])); got != want {
+		t.Errorf("packet = %q, want %q", got, want)
+	}
+}
+
+func TestStatsDLineEncodesLabelsInNameWithoutDogStatsD(t *testing.T) {
+	exporter := &StatsDExporter{previous: map[string]float64{}}
+	labels := []*dto.LabelPair{{Name: proto.String(LabelOverride), Value: proto.String("black:friday")}}
+
+	if got, want := exporter.line("pct", labels, 150, "g"), "pct.black_friday:150|g"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
+
+func TestApplyConfigKeepsUnchangedStatsDExporter(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	pusher := NewPusher()
+	defer pusher.closeAll()
+	cfg := config.DefaultConfig()
+	cfg.Metrics.StatsD = &config.StatsDConfig{Address: listener.LocalAddr().String(), Tags: []string{"env:test"}}
+
+	pusher.ApplyConfig(cfg)
+	exporter := pusher.exporters[statsDExporterName]
+	if exporter == nil {
+		t.Fatal("StatsD exporter not configured")
+	}
+	// A reload with the same StatsD config keeps the previous counter values
+	reloaded := config.DefaultConfig()
+	reloaded.MinReplicas++
+	reloaded.Metrics.StatsD = &config.StatsDConfig{Address: listener.LocalAddr().String(), Tags: []string{"env:test"}}
+	pusher.ApplyConfig(reloaded)
+	if pusher.exporters[statsDExporterName] != exporter {
+		t.Error("unchanged StatsD config recreated the exporter")
+	}
+
+	reloaded.Metrics.StatsD.Prefix = "k8s"
+	pusher.ApplyConfig(reloaded)
+	if pusher.exporters[statsDExporterName] == exporter {
+		t.Error("changed StatsD config kept the exporter")
+	}
+}
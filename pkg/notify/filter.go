@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"time"
+)
+
+const (
+	// DefaultDedupWindow is how long an event identical to the last one
+	// delivered for the same target is dropped
+	DefaultDedupWindow = 10 * time.Minute
+
+	// DefaultBurst is the number of events an override can deliver at once
+	DefaultBurst = 10
+
+	// DefaultRefillInterval is how often an override regains one event of its burst
+	DefaultRefillInterval = time.Minute
+
+	// flushInterval is how often suppressed events are summarized
+	flushInterval = time.Minute
+
+	// Reasons an event was not delivered on its own
+	ReasonDuplicate   = "duplicate"
+	ReasonRateLimited = "rate-limited"
+	ReasonQueueFull   = "queue-full"
+)
+
+// filter de-duplicates events per target and rate limits them per override
+// with a token bucket, in the spirit of the client-go event spam filter.
+// Rate limited events are folded into a summary delivered once the override
+// has tokens again. It is only used from the Notifier loop and is not safe
+// for concurrent use.
+type filter struct {
+	window time.Duration
+	burst  float64
+	refill time.Duration
+
+	// last is the last event delivered per target
+	last map[string]Event
+	// buckets hold the tokens and suppressed events per override
+	buckets map[string]*bucket
+}
+
+// bucket is the token bucket of one override
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// pending is the latest rate limited event, suppressed counts the others
+	pending    *Event
+	suppressed int32
+}
+
+func newFilter(window time.Duration, burst int, refill time.Duration) *filter {
+	return &filter{
+		window:  window,
+		burst:   float64(burst),
+		refill:  refill,
+		last:    make(map[string]Event),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// targetKey identifies the target of an event
+func (e Event) targetKey() string {
+	return e.Namespace + "/" + e.Override + "/" + e.Kind + "/" + e.Name
+}
+
+// overrideKey identifies the override of an event
+func (e Event) overrideKey() string {
+	return e.Namespace + "/" + e.Override
+}
+
+// sameChange returns true if e reports the same change as other
+func (e Event) sameChange(other Event) bool {
+	return e.Type == other.Type && e.Previous == other.Previous && e.Target == other.Target && e.Error == other.Error
+}
+
+// allow returns true if evt must be delivered now, or the reason it is not
+func (f *filter) allow(evt Event) (bool, string) {
+	if last, ok := f.last[evt.targetKey()]; ok && last.sameChange(evt) && evt.Time.Sub(last.Time) < f.window {
+		return false, ReasonDuplicate
+	}
+
+	b := f.bucket(evt.overrideKey(), evt.Time)
+	if b.tokens < 1 {
+		if b.pending != nil {
+			b.suppressed++
+		}
+		pending := evt
+		b.pending = &pending
+		return false, ReasonRateLimited
+	}
+	b.tokens--
+	f.last[evt.targetKey()] = evt
+	return true, ""
+}
+
+// flush returns a summary of the rate limited events of every override that
+// regained a token: the latest event with the number of others suppressed.
+// It also forgets targets and overrides that went quiet.
+func (f *filter) flush(now time.Time) []Event {
+	var summaries []Event
+	for key, b := range f.buckets {
+		f.fill(b, now)
+		if b.pending != nil && b.tokens >= 1 {
+			b.tokens--
+			summary := *b.pending
+			summary.Suppressed = b.suppressed
+			summaries = append(summaries, summary)
+			f.last[summary.targetKey()] = summary
+			b.pending, b.suppressed = nil, 0
+		}
+		if b.pending == nil && b.tokens >= f.burst {
+			delete(f.buckets, key)
+		}
+	}
+	for key, last := range f.last {
+		if now.Sub(last.Time) >= f.window {
+			delete(f.last, key)
+		}
+	}
+	return summaries
+}
+
+// bucket returns the refilled bucket of an override, full for a new one
+func (f *filter) bucket(key string, now time.Time) *bucket {
+	b, ok := f.buckets[key]
+	if !ok {
+		b = &bucket{tokens: f.burst, updated: now}
+		f.buckets[key] = b
+	}
+	f.fill(b, now)
+	return b
+}
+
+// fill adds the tokens earned since the last update, up to the burst
+func (f *filter) fill(b *bucket, now time.Time) {
+	if !now.After(b.updated) {
+		return
+	}
+	if f.refill > 0 {
+		b.tokens += float64(now.Sub(b.updated)) / float64(f.refill)
+		if b.tokens > f.burst {
+			b.tokens = f.burst
+		}
+	}
+	b.updated = now
+}
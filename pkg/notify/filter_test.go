@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+func TestFilterDropsDuplicates(t *testing.T) {
+	now := time.Date(2025, 11, 28, 6, 0, 0, 0, time.UTC)
+	f := newFilter(10*time.Minute, 10, time.Minute)
+	evt := Event{Type: dynamicscalingv1.NotificationFailed, Override: "sale", Namespace: "shop", Kind: "Deployment", Name: "web", Target: 6, Error: "conflict", Time: now}
+
+	if ok, _ := f.allow(evt); !ok {
+		t.Fatal("expected the first event to be delivered")
+	}
+	evt.Time = now.Add(time.Minute)
+	if ok, reason := f.allow(evt); ok || reason != ReasonDuplicate {
+		t.Errorf("allow() = (%v, %q), want a duplicate", ok, reason)
+	}
+
+	other := evt
+	other.Name = "api"
+	if ok, _ := f.allow(other); !ok {
+		t.Error("expected the same change of another target to be delivered")
+	}
+
+	evt.Time = now.Add(11 * time.Minute)
+	if ok, _ := f.allow(evt); !ok {
+		t.Error("expected a repeated event to be delivered after the window")
+	}
+}
+
+func TestFilterRateLimitsAndSummarizes(t *testing.T) {
+	now := time.Date(2025, 11, 28, 6, 0, 0, 0, time.UTC)
+	f := newFilter(10*time.Minute, 3, time.Minute)
+
+	delivered := 0
+	for i := 0; i < 10; i++ {
+		evt := Event{Type: dynamicscalingv1.NotificationScaled, Override: "sale", Namespace: "shop", Kind: "Deployment", Name: string(rune('a' + i)), Target: 4, Trigger: "override", Time: now}
+		if ok, reason := f.allow(evt); ok {
+			delivered++
+		} else if reason != ReasonRateLimited {
+			t.Errorf("allow() reason = %q, want %q", reason, ReasonRateLimited)
+		}
+	}
+	if delivered != 3 {
+		t.Errorf("delivered %d events, want the burst of 3", delivered)
+	}
+
+	// Another override has its own bucket
+	if ok, _ := f.allow(Event{Override: "other", Namespace: "shop", Kind: "Deployment", Name: "a", Time: now}); !ok {
+		t.Error("expected another override not to be rate limited")
+	}
+
+	if summaries := f.flush(now.Add(30 * time.Second)); len(summaries) != 0 {
+		t.Errorf("flush() before a token is regained = %v, want none", summaries)
+	}
+	summaries := f.flush(now.Add(time.Minute))
+	if len(summaries) != 1 {
+		t.Fatalf("flush() returned %d summaries, want 1", len(summaries))
+	}
+	if summaries[0].Name != "j" || summaries[0].Suppressed != 6 {
+		t.Errorf("summary = %s/%d suppressed, want the last event with 6 suppressed", summaries[0].Name, summaries[0].Suppressed)
+	}
+	if got := summaries[0].Message(); got != "[sale] Scaled Deployment shop/j from 0 to 4 replicas (trigger: override) and 6 more events" {
+		t.Errorf("Message() = %q", got)
+	}
+
+	if summaries := f.flush(now.Add(2 * time.Minute)); len(summaries) != 0 {
+		t.Errorf("flush() after the summary = %v, want none", summaries)
+	}
+}
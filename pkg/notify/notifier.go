@@ -0,0 +1,264 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/metrics"
+)
+
+const (
+	// Notification target types
+	TypeSlack   = "slack"
+	TypeWebhook = "webhook"
+
+	// DefaultSlackURL is the Slack Web API method used to post messages
+	DefaultSlackURL = "https://slack.com/api/chat.postMessage"
+
+	// queueSize bounds the events waiting for delivery; newer events are dropped when full
+	queueSize = 256
+
+	// sendTimeout bounds a single delivery
+	sendTimeout = 10 * time.Second
+)
+
+// Event is a change made by an override to one of its targets
+type Event struct {
+	Type      dynamicscalingv1.NotificationEvent `json:"type"`
+	Override  string                             `json:"override"`
+	Namespace string                             `json:"namespace"`
+	Kind      string                             `json:"kind"`
+	Name      string                             `json:"name"`
+	Previous  int32                              `json:"previousReplicas"`
+	Target    int32                              `json:"targetReplicas"`
+	Trigger   string                             `json:"trigger,omitempty"`
+	Error     string                             `json:"error,omitempty"`
+	Reason    string                             `json:"reason,omitempty"`
+	Time      time.Time                          `json:"time"`
+	// Suppressed is the number of other events of the override folded into this one by rate limiting
+	Suppressed int32 `json:"suppressed,omitempty"`
+}
+
+// Message renders the event as a single human readable line
+func (e Event) Message() string {
+	target := fmt.Sprintf("%s %s/%s", e.Kind, e.Namespace, e.Name)
+	var message string
+	switch e.Type {
+	case dynamicscalingv1.NotificationFailed:
+		message = fmt.Sprintf("[%s] Failed to scale %s to %d replicas: %s", e.Override, target, e.Target, e.Error)
+		if e.Reason != "" {
+			message = fmt.Sprintf("[%s] Failed to scale %s to %d replicas (%s): %s", e.Override, target, e.Target, e.Reason, e.Error)
+		}
+	case dynamicscalingv1.NotificationRolledBack:
+		message = fmt.Sprintf("[%s] Rolled back %s from %d to %d replicas", e.Override, target, e.Previous, e.Target)
+	default:
+		message = fmt.Sprintf("[%s] Scaled %s from %d to %d replicas (trigger: %s)", e.Override, target, e.Previous, e.Target, e.Trigger)
+	}
+	if e.Suppressed > 0 {
+		message += fmt.Sprintf(" and %d more events", e.Suppressed)
+	}
+	return message
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Notifier delivers override events to the notification targets declared by
+// each override. Events are queued and sent in the background so a slow
+// destination never delays reconciliation. Repeated events of a target are
+// dropped and bursts of an override are summarized, so an override covering
+// hundreds of workloads cannot flood its targets; the scaling metrics still
+// count every operation. Webhook URLs must be in allowedURLs of the global
+// config. It implements manager.Runnable; a nil Notifier drops every event.
+type Notifier struct {
+	client    client.Reader
+	apiReader client.Reader
+	config    *config.Manager
+	http      *http.Client
+	slackURL  string
+	queue     chan Event
+	filter    *filter
+}
+
+// NewNotifier creates a notifier reading overrides from c, Secrets from
+// apiReader, so Secrets are not cached cluster-wide, and the allowed webhook
+// URLs from configManager
+func NewNotifier(c client.Reader, apiReader client.Reader, configManager *config.Manager) *Notifier {
+	return &Notifier{
+		client:    c,
+		apiReader: apiReader,
+		config:    configManager,
+		http:      config.NewHTTPClient(sendTimeout),
+		slackURL:  DefaultSlackURL,
+		queue:     make(chan Event, queueSize),
+		filter:    newFilter(DefaultDedupWindow, DefaultBurst, DefaultRefillInterval),
+	}
+}
+
+// Notify queues evt for delivery to the targets of its override
+func (n *Notifier) Notify(evt Event) {
+	if n == nil || evt.Override == "" {
+		return
+	}
+	if evt.Time.IsZero() {
+		evt.Time = time.Now()
+	}
+	select {
+	case n.queue <- evt:
+	default:
+		metrics.RecordNotificationSuppressed(evt.Namespace, evt.Override, ReasonQueueFull)
+	}
+}
+
+// Start delivers queued events until ctx is cancelled
+func (n *Notifier) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("notify.Notifier")
+	send := func(evt Event) {
+		if err := n.deliver(ctx, evt); err != nil {
+			log.Error(err, "Failed to deliver notification",
+				"override", evt.Override,
+				"namespace", evt.Namespace,
+				"event", evt.Type)
+		}
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case evt := <-n.queue:
+			if ok, reason := n.filter.allow(evt); !ok {
+				metrics.RecordNotificationSuppressed(evt.Namespace, evt.Override, reason)
+				continue
+			}
+			send(evt)
+		case now := <-ticker.C:
+			for _, summary := range n.filter.flush(now) {
+				send(summary)
+			}
+		}
+	}
+}
+
+// deliver sends evt to every target of its override subscribed to the event type
+func (n *Notifier) deliver(ctx context.Context, evt Event) error {
+	override := &dynamicscalingv1.ReplicasOverride{}
+	if err := n.client.Get(ctx, types.NamespacedName{Name: evt.Override, Namespace: evt.Namespace}, override); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	var errs []string
+	for _, target := range override.Spec.Notifications {
+		if !Subscribed(target, evt.Type) {
+			continue
+		}
+		if err := n.send(ctx, override.Namespace, target, evt); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", target.Type, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to notify %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// send delivers evt to a single target
+func (n *Notifier) send(ctx context.Context, namespace string, target dynamicscalingv1.NotificationTarget, evt Event) error {
+	secret, err := n.secretValue(ctx, namespace, target.SecretRef)
+	if err != nil {
+		return err
+	}
+
+	switch target.Type {
+	case TypeSlack:
+		if target.Channel == "" {
+			return fmt.Errorf("slack channel is required")
+		}
+		return n.post(ctx, n.slackURL, secret, map[string]string{"channel": target.Channel, "text": evt.Message()}, true)
+	case TypeWebhook:
+		if err := n.config.GetConfig().URLAllowed(secret); err != nil {
+			return err
+		}
+		return n.post(ctx, secret, "", evt, false)
+	default:
+		return fmt.Errorf("unknown notification type %q", target.Type)
+	}
+}
+
+// secretValue reads the selected key of a Secret
+func (n *Notifier) secretValue(ctx context.Context, namespace string, ref corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := n.apiReader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("key %q not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+// post sends payload as JSON, authenticated with token when set. Slack reports
+// failures in the response body rather than the status code.
+func (n *Notifier) post(ctx context.Context, url, token string, payload any, slack bool) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification returned %s", resp.Status)
+	}
+	if slack {
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return err
+		}
+		if !result.OK {
+			return fmt.Errorf("slack returned %s", result.Error)
+		}
+	}
+	return nil
+}
+
+// Subscribed returns true if target wants events of the given type
+func Subscribed(target dynamicscalingv1.NotificationTarget, eventType dynamicscalingv1.NotificationEvent) bool {
+	if len(target.Events) == 0 {
+		return true
+	}
+	for _, e := range target.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestSubscribed(t *testing.T) {
+	all := dynamicscalingv1.NotificationTarget{}
+	failures := dynamicscalingv1.NotificationTarget{Events: []dynamicscalingv1.NotificationEvent{dynamicscalingv1.NotificationFailed}}
+
+	if !Subscribed(all, dynamicscalingv1.NotificationScaled) {
+		t.Error("expected a target without events to receive every event")
+	}
+	if Subscribed(failures, dynamicscalingv1.NotificationScaled) {
+		t.Error("expected a failures-only target not to receive scaled events")
+	}
+	if !Subscribed(failures, dynamicscalingv1.NotificationFailed) {
+		t.Error("expected a failures-only target to receive failed events")
+	}
+}
+
+func TestDeliverToSlackAndWebhook(t *testing.T) {
+	var slackMessage map[string]string
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+			fmt.Fprint(w, `{"ok": false, "error": "invalid_auth"}`)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&slackMessage)
+		fmt.Fprint(w, `{"ok": true}`)
+	}))
+	defer slack.Close()
+
+	var webhookEvent Event
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&webhookEvent)
+	}))
+	defer webhook.Close()
+
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "black-friday", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{Notifications: []dynamicscalingv1.NotificationTarget{
+			{
+				Type:      TypeSlack,
+				Channel:   "#shop-oncall",
+				SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "notify"}, Key: "slack-token"},
+			},
+			{
+				Type:      TypeWebhook,
+				SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "notify"}, Key: "webhook-url"},
+				Events:    []dynamicscalingv1.NotificationEvent{dynamicscalingv1.NotificationScaled},
+			},
+		}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "shop"},
+		Data: map[string][]byte{
+			"slack-token": []byte("xoxb-token\n"),
+			"webhook-url": []byte(webhook.URL),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dynamicscalingv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(override, secret).Build()
+
+	n := NewNotifier(c, c, config.NewStaticManager(&config.GlobalConfig{AllowedURLs: []string{webhook.URL}}))
+	n.slackURL = slack.URL
+	evt := Event{
+		Type:      dynamicscalingv1.NotificationScaled,
+		Override:  "black-friday",
+		Namespace: "shop",
+		Kind:      "Deployment",
+		Name:      "web",
+		Previous:  3,
+		Target:    6,
+		Trigger:   "override",
+	}
+	if err := n.deliver(context.Background(), evt); err != nil {
+		t.Fatalf("deliver() failed: %v", err)
+	}
+
+	if slackMessage["channel"] != "#shop-oncall" || !strings.Contains(slackMessage["text"], "Deployment shop/web from 3 to 6") {
+		t.Errorf("unexpected slack message %v", slackMessage)
+	}
+	if webhookEvent.Name != "web" || webhookEvent.Target != 6 {
+		t.Errorf("unexpected webhook event %+v", webhookEvent)
+	}
+}
+
+func TestDeliverIgnoresDeletedOverride(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = dynamicscalingv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	n := NewNotifier(c, c, config.NewStaticManager(config.DefaultConfig()))
+	if err := n.deliver(context.Background(), Event{Override: "gone", Namespace: "shop"}); err != nil {
+		t.Errorf("deliver() = %v, want nil", err)
+	}
+}
+
+func TestDeliverRefusesWebhookOutsideAllowedURLs(t *testing.T) {
+	called := false
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer webhook.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, webhook.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+
+	override := &dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: "black-friday", Namespace: "shop"},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{Notifications: []dynamicscalingv1.NotificationTarget{
+			{Type: TypeWebhook, SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "notify"}, Key: "webhook-url"}},
+			{Type: TypeWebhook, SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "notify"}, Key: "redirect-url"}},
+		}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "notify", Namespace: "shop"},
+		Data: map[string][]byte{
+			"webhook-url":  []byte(webhook.URL),
+			"redirect-url": []byte(redirect.URL),
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = dynamicscalingv1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(override, secret).Build()
+
+	// Only the redirecting URL is allowed, and its redirect is not followed
+	n := NewNotifier(c, c, config.NewStaticManager(&config.GlobalConfig{AllowedURLs: []string{redirect.URL}}))
+	err := n.deliver(context.Background(), Event{Type: dynamicscalingv1.NotificationScaled, Override: "black-friday", Namespace: "shop"})
+	if err == nil || !strings.Contains(err.Error(), "allowedURLs") || !strings.Contains(err.Error(), "307") {
+		t.Errorf("deliver() = %v, want the webhook refused and the redirect returned", err)
+	}
+	if called {
+		t.Error("expected the webhook outside allowedURLs not to be called")
+	}
+}
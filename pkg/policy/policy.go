@@ -0,0 +1,387 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy generates admission policies enforcing the behavior of the
+// controller, derived from its configuration so they stay in sync with it:
+// manual edits of replicas and HPA limits the controller manages are denied,
+// and ReplicasOverrides of production namespaces must bound their replicas.
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// Engine is the policy engine policies are generated for
+type Engine string
+
+// Supported policy engines
+const (
+	EngineKyverno    Engine = "kyverno"
+	EngineGatekeeper Engine = "gatekeeper"
+)
+
+// Names of the generated policies
+const (
+	ManualReplicaEditsPolicy  = "kds-deny-manual-replica-edits"
+	OverrideMaxReplicasPolicy = "kds-require-override-max-replicas"
+)
+
+// Options are the settings of the generated policies not found in the
+// controller configuration
+type Options struct {
+	// ControllerNamespace and ControllerServiceAccount identify the
+	// controller, whose own writes are always allowed
+	ControllerNamespace      string
+	ControllerServiceAccount string
+	// ProductionSelector selects by label the namespaces whose overrides must
+	// set maxReplicas; the policy is not generated when empty
+	ProductionSelector map[string]string
+}
+
+// managedKinds are the kinds whose replicas the controller manages, by API group
+func managedKinds(cfg *config.GlobalConfig) map[string][]string {
+	kinds := map[string][]string{
+		"apps":        {"Deployment"},
+		"autoscaling": {"HorizontalPodAutoscaler"},
+	}
+	if cfg.StatefulSets.Enabled {
+		kinds["apps"] = append(kinds["apps"], "StatefulSet")
+	}
+	return kinds
+}
+
+// Generate returns the policies of an engine for a controller configuration,
+// as objects ready to be marshaled
+func Generate(engine Engine, cfg *config.GlobalConfig, opts Options) ([]map[string]interface{}, error) {
+	if opts.ControllerNamespace == "" || opts.ControllerServiceAccount == "" {
+		return nil, fmt.Errorf("the controller namespace and service account are required")
+	}
+	switch engine {
+	case EngineKyverno:
+		return kyvernoPolicies(cfg, opts), nil
+	case EngineGatekeeper:
+		return gatekeeperPolicies(cfg, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown policy engine %q, expected %s or %s", engine, EngineKyverno, EngineGatekeeper)
+	}
+}
+
+// maxReplicasMessage describes the bound overrides of production namespaces must set
+func maxReplicasMessage(cfg *config.GlobalConfig) string {
+	if cfg.MaxReplicas > 0 {
+		return fmt.Sprintf("ReplicasOverrides of production namespaces must set maxReplicas, at most the global maxReplicas of %d", cfg.MaxReplicas)
+	}
+	return "ReplicasOverrides of production namespaces must set maxReplicas"
+}
+
+// objectMeta returns the metadata of a generated policy object
+func objectMeta(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"labels": map[string]interface{}{
+			"app.kubernetes.io/part-of":    "kubedynamicscaler",
+			"app.kubernetes.io/managed-by": "kubectl-kds",
+		},
+	}
+}
+
+// stringMap converts labels to an object field
+func stringMap(labels map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(labels))
+	for key, value := range labels {
+		result[key] = value
+	}
+	return result
+}
+
+// sortedGroups returns the API groups of kinds in a stable order
+func sortedGroups(kinds map[string][]string) []string {
+	groups := make([]string, 0, len(kinds))
+	for group := range kinds {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+func kyvernoPolicies(cfg *config.GlobalConfig, opts Options) []map[string]interface{} {
+	kinds := managedKinds(cfg)
+	exclude := map[string]interface{}{
+		"any": []interface{}{map[string]interface{}{
+			"subjects": []interface{}{map[string]interface{}{
+				"kind":      "ServiceAccount",
+				"name":      opts.ControllerServiceAccount,
+				"namespace": opts.ControllerNamespace,
+			}},
+		}},
+	}
+	// Annotations are read loose, or from the state annotation of the v1 annotation format
+	annotation := func(key string) string {
+		return fmt.Sprintf(`{{ request.oldObject.metadata.annotations."%s" || parse_json(request.oldObject.metadata.annotations."%s" || '{}').%s || '' }}`,
+			key, utils.StateAnnotation, utils.StateField(key))
+	}
+	changed := func(fields ...string) []interface{} {
+		var conditions []interface{}
+		for _, field := range fields {
+			conditions = append(conditions, map[string]interface{}{
+				"key":      fmt.Sprintf("{{ request.object.spec.%s || `0` }}", field),
+				"operator": "NotEquals",
+				"value":    fmt.Sprintf("{{ request.oldObject.spec.%s || `0` }}", field),
+			})
+		}
+		return conditions
+	}
+
+	rules := []interface{}{
+		map[string]interface{}{
+			"name": "deny-replica-edits",
+			"match": map[string]interface{}{"any": []interface{}{map[string]interface{}{
+				"resources": map[string]interface{}{"kinds": toInterfaces(kinds["apps"]), "operations": []interface{}{"UPDATE"}},
+			}}},
+			"exclude": exclude,
+			// Replicas of workloads scaled through their HPA are the HPA's to change
+			"preconditions": map[string]interface{}{"all": []interface{}{
+				map[string]interface{}{"key": annotation(utils.OriginalReplicasAnnotation), "operator": "NotEquals", "value": ""},
+				map[string]interface{}{"key": annotation(utils.ManagementModeAnnotation), "operator": "AnyNotIn", "value": []interface{}{"hpa", config.ScalerModeLimitsOnly}},
+			}},
+			"validate": map[string]interface{}{
+				"message": "replicas of {{ request.object.kind }} {{ request.object.metadata.name }} are managed by KubeDynamicScaler, change its ReplicasOverride instead",
+				"deny":    map[string]interface{}{"conditions": map[string]interface{}{"any": changed("replicas")}},
+			},
+		},
+		map[string]interface{}{
+			"name": "deny-hpa-limit-edits",
+			"match": map[string]interface{}{"any": []interface{}{map[string]interface{}{
+				"resources": map[string]interface{}{"kinds": toInterfaces(kinds["autoscaling"]), "operations": []interface{}{"UPDATE"}},
+			}}},
+			"exclude": exclude,
+			"preconditions": map[string]interface{}{"all": []interface{}{
+				map[string]interface{}{"key": annotation(utils.HPAManagedAnnotation), "operator": "Equals", "value": "true"},
+			}},
+			"validate": map[string]interface{}{
+				"message": "limits of HorizontalPodAutoscaler {{ request.object.metadata.name }} are managed by KubeDynamicScaler, change its ReplicasOverride instead",
+				"deny":    map[string]interface{}{"conditions": map[string]interface{}{"any": changed("minReplicas", "maxReplicas")}},
+			},
+		},
+	}
+	policies := []map[string]interface{}{kyvernoClusterPolicy(ManualReplicaEditsPolicy,
+		"Denies manual edits of replicas and HPA limits managed by KubeDynamicScaler, which it would revert", rules)}
+
+	if len(opts.ProductionSelector) > 0 {
+		bound := ">=1"
+		if cfg.MaxReplicas > 0 {
+			bound = fmt.Sprintf("1-%d", cfg.MaxReplicas)
+		}
+		policies = append(policies, kyvernoClusterPolicy(OverrideMaxReplicasPolicy, maxReplicasMessage(cfg), []interface{}{
+			map[string]interface{}{
+				"name": "require-max-replicas",
+				"match": map[string]interface{}{"any": []interface{}{map[string]interface{}{
+					"resources": map[string]interface{}{
+						"kinds":             []interface{}{"kubedynamicscaler.io/v1/ReplicasOverride"},
+						"operations":        []interface{}{"CREATE", "UPDATE"},
+						"namespaceSelector": map[string]interface{}{"matchLabels": stringMap(opts.ProductionSelector)},
+					},
+				}}},
+				"validate": map[string]interface{}{
+					"message": maxReplicasMessage(cfg),
+					"pattern": map[string]interface{}{"spec": map[string]interface{}{"maxReplicas": bound}},
+				},
+			},
+		}))
+	}
+	return policies
+}
+
+// kyvernoClusterPolicy returns an enforced Kyverno ClusterPolicy. Rules read
+// the request, so they cannot run in background scans.
+func kyvernoClusterPolicy(name, description string, rules []interface{}) map[string]interface{} {
+	metadata := objectMeta(name)
+	metadata["annotations"] = map[string]interface{}{"policies.kyverno.io/description": description}
+	return map[string]interface{}{
+		"apiVersion": "kyverno.io/v1",
+		"kind":       "ClusterPolicy",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"validationFailureAction": "Enforce",
+			"background":              false,
+			"rules":                   rules,
+		},
+	}
+}
+
+// manualReplicaEditsRego denies replica and HPA limit changes of managed
+// objects by anyone but the controller
+var manualReplicaEditsRego = fmt.Sprintf(`package kdsdenymanualreplicaedits
+
+state(obj) := value {
+  value := json.unmarshal(object.get(obj, ["metadata", "annotations", %[5]q], "{}"))
+} else := {}
+
+# Annotations are read loose, or from the state annotation of the v1 annotation format
+annotation(obj, key, field) := value {
+  value := object.get(obj, ["metadata", "annotations", key], "")
+  value != ""
+} else := value {
+  value := object.get(state(obj), field, "")
+}
+
+scaled_by_hpa(obj) {
+  {"hpa", %[3]q}[annotation(obj, %[2]q, %[7]q)]
+}
+
+changed(field) {
+  object.get(input.review.object.spec, field, 0) != object.get(input.review.oldObject.spec, field, 0)
+}
+
+limits_changed {
+  changed("minReplicas")
+}
+
+limits_changed {
+  changed("maxReplicas")
+}
+
+violation[{"msg": msg}] {
+  input.review.operation == "UPDATE"
+  input.review.userInfo.username != input.parameters.controller
+  input.review.kind.kind != "HorizontalPodAutoscaler"
+  old := input.review.oldObject
+  annotation(old, %[1]q, %[6]q) != ""
+  not scaled_by_hpa(old)
+  changed("replicas")
+  msg := sprintf("replicas of %%s %%s are managed by KubeDynamicScaler, change its ReplicasOverride instead", [input.review.kind.kind, old.metadata.name])
+}
+
+violation[{"msg": msg}] {
+  input.review.operation == "UPDATE"
+  input.review.userInfo.username != input.parameters.controller
+  input.review.kind.kind == "HorizontalPodAutoscaler"
+  old := input.review.oldObject
+  annotation(old, %[4]q, %[8]q) == "true"
+  limits_changed
+  msg := sprintf("limits of HorizontalPodAutoscaler %%s are managed by KubeDynamicScaler, change its ReplicasOverride instead", [old.metadata.name])
+}
+`, utils.OriginalReplicasAnnotation, utils.ManagementModeAnnotation, config.ScalerModeLimitsOnly, utils.HPAManagedAnnotation,
+	utils.StateAnnotation, utils.StateField(utils.OriginalReplicasAnnotation), utils.StateField(utils.ManagementModeAnnotation),
+	utils.StateField(utils.HPAManagedAnnotation))
+
+// overrideMaxReplicasRego requires overrides to set maxReplicas, bounded by
+// the global maxReplicas when positive
+const overrideMaxReplicasRego = `package kdsrequireoverridemaxreplicas
+
+violation[{"msg": msg}] {
+  not input.review.object.spec.maxReplicas
+  msg := sprintf("ReplicasOverride %s must set maxReplicas", [input.review.object.metadata.name])
+}
+
+violation[{"msg": msg}] {
+  input.parameters.maxReplicas > 0
+  input.review.object.spec.maxReplicas > input.parameters.maxReplicas
+  msg := sprintf("maxReplicas of ReplicasOverride %s must be at most the global maxReplicas of %d", [input.review.object.metadata.name, input.parameters.maxReplicas])
+}
+`
+
+func gatekeeperPolicies(cfg *config.GlobalConfig, opts Options) []map[string]interface{} {
+	kinds := managedKinds(cfg)
+	var match []interface{}
+	for _, group := range sortedGroups(kinds) {
+		match = append(match, map[string]interface{}{"apiGroups": []interface{}{group}, "kinds": toInterfaces(kinds[group])})
+	}
+
+	controller := fmt.Sprintf("system:serviceaccount:%s:%s", opts.ControllerNamespace, opts.ControllerServiceAccount)
+	policies := []map[string]interface{}{
+		gatekeeperTemplate(ManualReplicaEditsPolicy, manualReplicaEditsRego, map[string]interface{}{
+			"controller": map[string]interface{}{"type": "string"},
+		}),
+		gatekeeperConstraint(ManualReplicaEditsPolicy, map[string]interface{}{"kinds": match},
+			map[string]interface{}{"controller": controller}),
+	}
+
+	if len(opts.ProductionSelector) > 0 {
+		policies = append(policies,
+			gatekeeperTemplate(OverrideMaxReplicasPolicy, overrideMaxReplicasRego, map[string]interface{}{
+				"maxReplicas": map[string]interface{}{"type": "integer"},
+			}),
+			gatekeeperConstraint(OverrideMaxReplicasPolicy, map[string]interface{}{
+				"kinds": []interface{}{map[string]interface{}{
+					"apiGroups": []interface{}{"kubedynamicscaler.io"},
+					"kinds":     []interface{}{"ReplicasOverride"},
+				}},
+				"namespaceSelector": map[string]interface{}{"matchLabels": stringMap(opts.ProductionSelector)},
+			}, map[string]interface{}{"maxReplicas": int64(cfg.MaxReplicas)}),
+		)
+	}
+	return policies
+}
+
+// gatekeeperKind is the constraint kind of a policy, e.g. KdsDenyManualReplicaEdits
+func gatekeeperKind(name string) string {
+	var kind strings.Builder
+	for _, word := range strings.Split(name, "-") {
+		kind.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return kind.String()
+}
+
+// gatekeeperTemplate returns the ConstraintTemplate of a policy
+func gatekeeperTemplate(name, rego string, parameters map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "templates.gatekeeper.sh/v1",
+		"kind":       "ConstraintTemplate",
+		// Gatekeeper requires the template to be named after the lowercased constraint kind
+		"metadata": objectMeta(strings.ToLower(gatekeeperKind(name))),
+		"spec": map[string]interface{}{
+			"crd": map[string]interface{}{"spec": map[string]interface{}{
+				"names": map[string]interface{}{"kind": gatekeeperKind(name)},
+				"validation": map[string]interface{}{"openAPIV3Schema": map[string]interface{}{
+					"type":       "object",
+					"properties": parameters,
+				}},
+			}},
+			"targets": []interface{}{map[string]interface{}{
+				"target": "admission.k8s.gatekeeper.sh",
+				"rego":   rego,
+			}},
+		},
+	}
+}
+
+// gatekeeperConstraint returns the Constraint of a policy
+func gatekeeperConstraint(name string, match, parameters map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+		"kind":       gatekeeperKind(name),
+		"metadata":   objectMeta(name),
+		"spec": map[string]interface{}{
+			"enforcementAction": "deny",
+			"match":             match,
+			"parameters":        parameters,
+		},
+	}
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
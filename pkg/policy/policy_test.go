@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+var testOptions = Options{ControllerNamespace: "kds", ControllerServiceAccount: "controller"}
+
+// kinds returns the kind and name of every generated object
+func kinds(policies []map[string]interface{}) []string {
+	var result []string
+	for _, object := range policies {
+		name := object["metadata"].(map[string]interface{})["name"]
+		result = append(result, object["kind"].(string)+"/"+name.(string))
+	}
+	return result
+}
+
+func TestGenerateKyverno(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StatefulSets.Enabled = true
+	cfg.MaxReplicas = 50
+	opts := testOptions
+	opts.ProductionSelector = map[string]string{"env": "prod"}
+
+	policies, err := Generate(EngineKyverno, cfg, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ClusterPolicy/" + ManualReplicaEditsPolicy, "ClusterPolicy/" + OverrideMaxReplicasPolicy}
+	if got := kinds(policies); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() = %v, want %v", got, want)
+	}
+
+	data, err := yaml.Marshal(policies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"- StatefulSet",
+		`request.oldObject.metadata.annotations."kubedynamicscaler.io/original-replicas"`,
+		// Objects written with the v1 annotation format hold it in the state annotation
+		`parse_json(request.oldObject.metadata.annotations."kubedynamicscaler.io/state"`,
+		").originalReplicas",
+		").hpaManaged",
+		"name: controller",
+		"maxReplicas: 1-50",
+		"env: prod",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("expected the policies to contain %q, got:\n%s", expected, data)
+		}
+	}
+}
+
+func TestGenerateGatekeeper(t *testing.T) {
+	policies, err := Generate(EngineGatekeeper, config.DefaultConfig(), testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ConstraintTemplate/kdsdenymanualreplicaedits", "KdsDenyManualReplicaEdits/" + ManualReplicaEditsPolicy}
+	if got := kinds(policies); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Generate() without a production selector = %v, want %v", got, want)
+	}
+
+	constraint := policies[1]["spec"].(map[string]interface{})
+	if controller := constraint["parameters"].(map[string]interface{})["controller"]; controller != "system:serviceaccount:kds:controller" {
+		t.Errorf("controller parameter = %v", controller)
+	}
+	rego, _ := yaml.Marshal(policies[0]["spec"])
+	for _, expected := range []string{`"kubedynamicscaler.io/state"`, `"kubedynamicscaler.io/original-replicas", "originalReplicas"`} {
+		if !strings.Contains(string(rego), expected) {
+			t.Errorf("expected the template to read %s, got:\n%s", expected, rego)
+		}
+	}
+	data, _ := yaml.Marshal(constraint["match"])
+	if strings.Contains(string(data), "StatefulSet") {
+		t.Errorf("StatefulSets must not be matched while disabled, got:\n%s", data)
+	}
+}
+
+func TestGenerateRejectsInvalidOptions(t *testing.T) {
+	if _, err := Generate("opa", config.DefaultConfig(), testOptions); err == nil {
+		t.Error("expected an unknown engine to be rejected")
+	}
+	if _, err := Generate(EngineKyverno, config.DefaultConfig(), Options{}); err == nil {
+		t.Error("expected a missing controller service account to be rejected")
+	}
+}
@@ -0,0 +1,163 @@
+package precedence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Layer is a level of scaling rules. A higher layer wins over lower ones for
+// every field it sets: global config < namespace default < namespace override <
+// override < annotation.
+type Layer int
+
+const (
+	// LayerGlobal is the global config
+	LayerGlobal Layer = iota
+	// LayerNamespaceDefault is the NamespaceScalingDefault of the namespace
+	LayerNamespaceDefault
+	// LayerNamespaceOverride is the NamespaceReplicasOverride of the namespace
+	LayerNamespaceOverride
+	// LayerOverride is the ReplicasOverride targeting the workload
+	LayerOverride
+	// LayerAnnotation is the percentage annotation on the workload itself
+	LayerAnnotation
+	// LayerAdjustment marks changes applied after the layers, such as triggers or pressure shedding
+	LayerAdjustment
+)
+
+// String returns the name of the layer used in explanations
+func (l Layer) String() string {
+	switch l {
+	case LayerGlobal:
+		return "global"
+	case LayerNamespaceDefault:
+		return "namespace-default"
+	case LayerNamespaceOverride:
+		return "namespace-override"
+	case LayerOverride:
+		return "override"
+	case LayerAnnotation:
+		return "annotation"
+	case LayerAdjustment:
+		return "adjustment"
+	default:
+		return fmt.Sprintf("layer-%d", int(l))
+	}
+}
+
+// Rule is the contribution of one layer. Nil fields leave the value of lower layers.
+type Rule struct {
+	Layer Layer
+	// Source identifies the rule, e.g. "ReplicasOverride shop/black-friday"
+	Source string
+	// Trigger is the metrics trigger label reported when this rule sets the percentage
+	Trigger     string
+	Percentage  *int32
+	MinReplicas *int32
+	MaxReplicas *int32
+}
+
+// Step records a rule or an adjustment that set the percentage
+type Step struct {
+	Layer      string `json:"layer"`
+	Source     string `json:"source"`
+	Percentage int32  `json:"percentage"`
+}
+
+// StageResult records the percentage and replicas after a stage of the
+// decision chain that changed them
+type StageResult struct {
+	Stage      string `json:"stage"`
+	Percentage int32  `json:"percentage"`
+	Replicas   *int32 `json:"replicas,omitempty"`
+}
+
+// Explanation is the resolved percentage and limits of a workload with the
+// steps that produced them, lowest layer first
+type Explanation struct {
+	Percentage  int32  `json:"percentage"`
+	Steps       []Step `json:"steps"`
+	MinReplicas int32  `json:"minReplicas"`
+	MaxReplicas int32  `json:"maxReplicas"`
+	LimitsFrom  string `json:"limitsFrom,omitempty"`
+	// Replicas is the replica count applied with the explanation, set by the caller
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Clamped is set when the replicas were bound by MinReplicas or MaxReplicas
+	Clamped bool `json:"clamped,omitempty"`
+	// Headroom is the number of spare replicas kept on top of the percentage, set by the caller
+	Headroom int32 `json:"headroom,omitempty"`
+	// Stages are the stages of the decision chain that changed the percentage or the replicas
+	Stages []StageResult `json:"stages,omitempty"`
+
+	// Trigger is the metrics trigger label of the last step
+	Trigger string `json:"-"`
+	// Layer is the layer of the last rule that set the percentage, adjustments excluded
+	Layer Layer `json:"-"`
+}
+
+// Resolve applies rules in layer order, rules of the same layer in the given order
+func Resolve(rules ...Rule) Explanation {
+	sorted := append([]Rule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Layer < sorted[j].Layer })
+
+	var e Explanation
+	for _, rule := range sorted {
+		if rule.Percentage != nil {
+			e.Percentage, e.Trigger, e.Layer = *rule.Percentage, rule.Trigger, rule.Layer
+			e.Steps = append(e.Steps, Step{Layer: rule.Layer.String(), Source: rule.Source, Percentage: *rule.Percentage})
+		}
+		if rule.MinReplicas != nil || rule.MaxReplicas != nil {
+			e.LimitsFrom = rule.Source
+		}
+		if rule.MinReplicas != nil {
+			e.MinReplicas = *rule.MinReplicas
+		}
+		if rule.MaxReplicas != nil {
+			e.MaxReplicas = *rule.MaxReplicas
+		}
+	}
+	return e
+}
+
+// Adjust records an adjustment of the percentage made after the layers were resolved
+func (e *Explanation) Adjust(source, trigger string, percentage int32) {
+	e.Percentage, e.Trigger = percentage, trigger
+	e.Steps = append(e.Steps, Step{Layer: LayerAdjustment.String(), Source: source, Percentage: percentage})
+}
+
+// SetReplicas records the replicas applied with the explanation
+func (e *Explanation) SetReplicas(replicas int32, clamped bool) {
+	e.Replicas, e.Clamped = &replicas, clamped
+}
+
+// JSON returns the explanation as compact JSON, as stored in the explain annotation
+func (e Explanation) JSON() string {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// String returns a single line summary, e.g.
+// "150% from override ReplicasOverride shop/sale (over global 100%)"
+func (e Explanation) String() string {
+	if len(e.Steps) == 0 {
+		return fmt.Sprintf("%d%%", e.Percentage)
+	}
+	last := e.Steps[len(e.Steps)-1]
+	summary := fmt.Sprintf("%d%% from %s %s", e.Percentage, last.Layer, last.Source)
+	if len(e.Steps) > 1 {
+		var previous []string
+		for i := len(e.Steps) - 2; i >= 0; i-- {
+			previous = append(previous, fmt.Sprintf("%s %d%%", e.Steps[i].Layer, e.Steps[i].Percentage))
+		}
+		summary += fmt.Sprintf(" (over %s)", strings.Join(previous, ", "))
+	}
+	if e.Headroom > 0 {
+		summary += fmt.Sprintf(" + %d spare replicas", e.Headroom)
+	}
+	return summary
+}
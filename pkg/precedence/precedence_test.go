@@ -0,0 +1,78 @@
+package precedence
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func ptr(v int32) *int32 {
+	return &v
+}
+
+func TestResolve(t *testing.T) {
+	global := Rule{Layer: LayerGlobal, Source: "global config", Trigger: "global", Percentage: ptr(100), MinReplicas: ptr(1), MaxReplicas: ptr(100)}
+	namespace := Rule{Layer: LayerNamespaceDefault, Source: "NamespaceScalingDefault shop/defaults", Trigger: "global", Percentage: ptr(80), MaxReplicas: ptr(20)}
+	limitsOnly := Rule{Layer: LayerNamespaceDefault, Source: "NamespaceScalingDefault shop/limits", MinReplicas: ptr(2)}
+	namespaceOverride := Rule{Layer: LayerNamespaceOverride, Source: "NamespaceReplicasOverride shop/staging", Trigger: "namespace-override", Percentage: ptr(50), MinReplicas: ptr(3)}
+	override := Rule{Layer: LayerOverride, Source: "ReplicasOverride shop/sale", Trigger: "override", Percentage: ptr(150)}
+	annotation := Rule{Layer: LayerAnnotation, Source: "Deployment shop/web", Trigger: "annotation", Percentage: ptr(50)}
+
+	tests := []struct {
+		name           string
+		rules          []Rule
+		wantPercentage int32
+		wantTrigger    string
+		wantLayer      Layer
+		wantMin        int32
+		wantMax        int32
+		wantSteps      int
+	}{
+		{name: "global only", rules: []Rule{global}, wantPercentage: 100, wantTrigger: "global", wantLayer: LayerGlobal, wantMin: 1, wantMax: 100, wantSteps: 1},
+		{name: "namespace default over global", rules: []Rule{global, namespace}, wantPercentage: 80, wantTrigger: "global", wantLayer: LayerNamespaceDefault, wantMin: 1, wantMax: 20, wantSteps: 2},
+		{name: "order of rules does not matter", rules: []Rule{override, namespace, global}, wantPercentage: 150, wantTrigger: "override", wantLayer: LayerOverride, wantMin: 1, wantMax: 20, wantSteps: 3},
+		{name: "annotation wins", rules: []Rule{global, namespace, override, annotation}, wantPercentage: 50, wantTrigger: "annotation", wantLayer: LayerAnnotation, wantMin: 1, wantMax: 20, wantSteps: 4},
+		{name: "namespace override over namespace default", rules: []Rule{global, namespace, namespaceOverride}, wantPercentage: 50, wantTrigger: "namespace-override", wantLayer: LayerNamespaceOverride, wantMin: 3, wantMax: 20, wantSteps: 3},
+		{name: "override over namespace override", rules: []Rule{global, override, namespaceOverride}, wantPercentage: 150, wantTrigger: "override", wantLayer: LayerOverride, wantMin: 3, wantMax: 100, wantSteps: 3},
+		{name: "limits without percentage", rules: []Rule{global, limitsOnly}, wantPercentage: 100, wantTrigger: "global", wantLayer: LayerGlobal, wantMin: 2, wantMax: 100, wantSteps: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Resolve(tt.rules...)
+			if e.Percentage != tt.wantPercentage || e.Trigger != tt.wantTrigger || e.Layer != tt.wantLayer {
+				t.Errorf("Resolve() = (%d, %q, %s), want (%d, %q, %s)", e.Percentage, e.Trigger, e.Layer, tt.wantPercentage, tt.wantTrigger, tt.wantLayer)
+			}
+			if e.MinReplicas != tt.wantMin || e.MaxReplicas != tt.wantMax {
+				t.Errorf("Resolve() limits = [%d, %d], want [%d, %d]", e.MinReplicas, e.MaxReplicas, tt.wantMin, tt.wantMax)
+			}
+			if len(e.Steps) != tt.wantSteps {
+				t.Errorf("Resolve() recorded %d steps, want %d", len(e.Steps), tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestExplanationOutput(t *testing.T) {
+	e := Resolve(
+		Rule{Layer: LayerGlobal, Source: "global config", Trigger: "global", Percentage: ptr(100)},
+		Rule{Layer: LayerOverride, Source: "ReplicasOverride shop/sale", Trigger: "override", Percentage: ptr(150)},
+	)
+	e.Adjust("node pressure tier batch", "node-pressure", 75)
+	e.SetReplicas(3, false)
+
+	if e.Layer != LayerOverride || e.Trigger != "node-pressure" {
+		t.Errorf("Adjust() = (%s, %q), want (override, node-pressure)", e.Layer, e.Trigger)
+	}
+	want := "75% from adjustment node pressure tier batch (over override 150%, global 100%)"
+	if got := e.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	var decoded Explanation
+	if err := json.Unmarshal([]byte(e.JSON()), &decoded); err != nil {
+		t.Fatalf("JSON() is not valid JSON: %v", err)
+	}
+	if decoded.Percentage != 75 || len(decoded.Steps) != 3 || decoded.Replicas == nil || *decoded.Replicas != 3 {
+		t.Errorf("JSON() round trip = %+v", decoded)
+	}
+}
@@ -0,0 +1,302 @@
+package pressure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// pressureConditions are the node conditions signalling resource pressure
+var pressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// Signal is a snapshot of the cluster pressure indicators
+type Signal struct {
+	// PressuredNodes is the number of nodes reporting a pressure condition
+	PressuredNodes int
+	// NewEvictions is the number of pods evicted since the previous snapshot
+	NewEvictions int
+	// AllocationPercentage is the highest requested/allocatable ratio of CPU and memory
+	AllocationPercentage float64
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
+
+// Monitor evaluates node pressure, evictions and cluster allocation and sheds
+// workload tiers, lowest priority first, with hysteresis between escalation and
+// recovery. It implements manager.Runnable; a nil Monitor never adjusts percentages.
+type Monitor struct {
+	client    client.Reader
+	apiReader client.Reader
+	config    *config.Manager
+
+	mutex       sync.RWMutex
+	level       int
+	highSince   time.Time
+	calmSince   time.Time
+	seenEvicted map[types.UID]bool
+	now         func() time.Time
+}
+
+// NewMonitor creates a monitor reading nodes from c and pods from apiReader
+func NewMonitor(c client.Reader, apiReader client.Reader, configManager *config.Manager) *Monitor {
+	return &Monitor{
+		client:      c,
+		apiReader:   apiReader,
+		config:      configManager,
+		seenEvicted: make(map[types.UID]bool),
+		now:         time.Now,
+	}
+}
+
+// Start evaluates cluster pressure until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("pressure.Monitor")
+
+	var lastCheck time.Time
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		cfg := m.config.GetConfig().NodePressure
+		if cfg.Enabled && m.now().Sub(lastCheck) >= cfg.GetCheckInterval() {
+			lastCheck = m.now()
+			signal, err := m.collect(ctx)
+			if err != nil {
+				log.Error(err, "Failed to evaluate node pressure")
+			} else if previous, level := m.Observe(cfg, signal); level != previous {
+				log.Info("Node pressure level changed",
+					"previous", previous,
+					"level", level,
+					"pressured_nodes", signal.PressuredNodes,
+					"new_evictions", signal.NewEvictions,
+					"allocation", signal.AllocationPercentage)
+			}
+		} else if !cfg.Enabled {
+			m.reset()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica keeps its own level
+func (m *Monitor) NeedLeaderElection() bool {
+	return false
+}
+
+// collect builds a pressure signal from the nodes and pods of the cluster
+func (m *Monitor) collect(ctx context.Context) (Signal, error) {
+	var signal Signal
+
+	nodes := &corev1.NodeList{}
+	if err := m.client.List(ctx, nodes); err != nil {
+		return signal, err
+	}
+	allocatableCPU, allocatableMemory := resource.Quantity{}, resource.Quantity{}
+	schedulable := make(map[string]bool, len(nodes.Items))
+	for _, node := range nodes.Items {
+		if hasPressure(&node) {
+			signal.PressuredNodes++
+		}
+		if node.Spec.Unschedulable {
+			continue
+		}
+		schedulable[node.Name] = true
+		allocatableCPU.Add(node.Status.Allocatable[corev1.ResourceCPU])
+		allocatableMemory.Add(node.Status.Allocatable[corev1.ResourceMemory])
+	}
+
+	pods := &corev1.PodList{}
+	if err := m.apiReader.List(ctx, pods); err != nil {
+		return signal, err
+	}
+	requestedCPU, requestedMemory := resource.Quantity{}, resource.Quantity{}
+	evicted := make(map[types.UID]bool)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted" {
+			evicted[pod.UID] = true
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || !schedulable[pod.Spec.NodeName] {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			requestedCPU.Add(container.Resources.Requests[corev1.ResourceCPU])
+			requestedMemory.Add(container.Resources.Requests[corev1.ResourceMemory])
+		}
+	}
+
+	m.mutex.Lock()
+	for uid := range evicted {
+		if !m.seenEvicted[uid] {
+			signal.NewEvictions++
+		}
+	}
+	m.seenEvicted = evicted
+	m.mutex.Unlock()
+
+	signal.AllocationPercentage = max(
+		ratio(requestedCPU.MilliValue(), allocatableCPU.MilliValue()),
+		ratio(requestedMemory.Value(), allocatableMemory.Value()),
+	)
+	return signal, nil
+}
+
+// Observe feeds a pressure signal into the hysteresis state machine and returns
+// the previous and current level. The level rises by one tier after pressure has
+// lasted SustainFor and falls by one tier after relief has lasted RecoverAfter;
+// allocation between the low and high thresholds holds the current level.
+func (m *Monitor) Observe(cfg config.NodePressureConfig, signal Signal) (int, int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := m.now()
+	previous := m.level
+	underPressure := signal.PressuredNodes > 0 || signal.NewEvictions > 0 ||
+		(cfg.HighAllocationThreshold > 0 && signal.AllocationPercentage >= cfg.HighAllocationThreshold)
+	relieved := signal.PressuredNodes == 0 && signal.NewEvictions == 0 &&
+		signal.AllocationPercentage < cfg.LowAllocationThreshold
+
+	switch {
+	case underPressure:
+		m.calmSince = time.Time{}
+		if m.highSince.IsZero() {
+			m.highSince = now
+		}
+		if now.Sub(m.highSince) >= cfg.GetSustainFor() && m.level < len(cfg.Tiers) {
+			m.level++
+			m.highSince = now
+		}
+	case relieved:
+		m.highSince = time.Time{}
+		if m.calmSince.IsZero() {
+			m.calmSince = now
+		}
+		if now.Sub(m.calmSince) >= cfg.GetRecoverAfter() && m.level > 0 {
+			m.level--
+			m.calmSince = now
+		}
+	default:
+		m.highSince, m.calmSince = time.Time{}, time.Time{}
+	}
+	return previous, m.level
+}
+
+// Level returns the number of tiers currently shed
+func (m *Monitor) Level() int {
+	if m == nil {
+		return 0
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.level
+}
+
+// reset restores all tiers
+func (m *Monitor) reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.level = 0
+	m.highSince, m.calmSince = time.Time{}, time.Time{}
+}
+
+// AdjustPercentage returns the percentage to apply to a workload with the given
+// labels and PriorityClass and whether it was lowered because its tier is currently shed
+func (m *Monitor) AdjustPercentage(ctx context.Context, labels map[string]string, priorityClassName string, percentage int32) (int32, bool) {
+	if m == nil {
+		return percentage, false
+	}
+	cfg := m.config.GetConfig()
+	if !cfg.NodePressure.Enabled {
+		return percentage, false
+	}
+	level := m.Level()
+	if level == 0 {
+		return percentage, false
+	}
+	tier := m.tier(ctx, cfg, labels, priorityClassName)
+	if tier == "" {
+		return percentage, false
+	}
+	return Shed(cfg.NodePressure, level, tier, percentage)
+}
+
+// tier returns the shedding tier of a workload: its tier label, or the tier
+// mapped from the value of its PriorityClass
+func (m *Monitor) tier(ctx context.Context, cfg *config.GlobalConfig, labels map[string]string, priorityClassName string) string {
+	if tier, ok := labels[cfg.NodePressure.GetTierLabel()]; ok {
+		return tier
+	}
+	if len(cfg.PriorityTiers) == 0 {
+		return ""
+	}
+
+	// Pods without a PriorityClass run at priority 0
+	var priority int32
+	if priorityClassName != "" {
+		priorityClass := &schedulingv1.PriorityClass{}
+		if err := m.client.Get(ctx, types.NamespacedName{Name: priorityClassName}, priorityClass); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to get PriorityClass, workload is not shed",
+				"priorityClass", priorityClassName, "error", err.Error())
+			return ""
+		}
+		priority = priorityClass.Value
+	}
+	return cfg.TierForPriority(priority)
+}
+
+// Shed lowers percentage if tier is among the first level tiers of cfg.
+// The result never goes below the tier MinPercentage and never raises the percentage.
+func Shed(cfg config.NodePressureConfig, level int, tier string, percentage int32) (int32, bool) {
+	for i := 0; i < level && i < len(cfg.Tiers); i++ {
+		if cfg.Tiers[i].Name != tier {
+			continue
+		}
+		reduced := int32(int64(percentage) * int64(100-cfg.Tiers[i].ReductionPercentage) / 100)
+		if reduced < cfg.Tiers[i].MinPercentage {
+			reduced = cfg.Tiers[i].MinPercentage
+		}
+		if reduced >= percentage {
+			return percentage, false
+		}
+		return reduced, true
+	}
+	return percentage, false
+}
+
+// hasPressure returns true if the node reports any pressure condition
+func hasPressure(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		for _, pressure := range pressureConditions {
+			if condition.Type == pressure && condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ratio(requested, allocatable int64) float64 {
+	if allocatable <= 0 {
+		return 0
+	}
+	return float64(requested) * 100 / float64(allocatable)
+}
@@ -0,0 +1,75 @@
+package pressure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+var testConfig = config.NodePressureConfig{
+	Enabled: true,
+	Tiers: []config.PressureTier{
+		{Name: "batch", ReductionPercentage: 50},
+		{Name: "standard", ReductionPercentage: 20, MinPercentage: 90},
+	},
+	HighAllocationThreshold: 90,
+	LowAllocationThreshold:  75,
+	SustainFor:              5 * time.Minute,
+	RecoverAfter:            10 * time.Minute,
+}
+
+func TestObserveHysteresis(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := &Monitor{now: func() time.Time { return now }}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		signal  Signal
+		want    int
+	}{
+		{name: "pressure starts", signal: Signal{AllocationPercentage: 95}, want: 0},
+		{name: "pressure sustained sheds first tier", advance: 5 * time.Minute, signal: Signal{AllocationPercentage: 95}, want: 1},
+		{name: "node pressure sheds second tier", advance: 5 * time.Minute, signal: Signal{PressuredNodes: 1}, want: 2},
+		{name: "no tier left to shed", advance: 5 * time.Minute, signal: Signal{NewEvictions: 3}, want: 2},
+		{name: "between thresholds holds level", advance: 20 * time.Minute, signal: Signal{AllocationPercentage: 80}, want: 2},
+		{name: "relief starts", advance: time.Minute, signal: Signal{AllocationPercentage: 70}, want: 2},
+		{name: "short relief keeps level", advance: 5 * time.Minute, signal: Signal{AllocationPercentage: 70}, want: 2},
+		{name: "sustained relief restores one tier", advance: 5 * time.Minute, signal: Signal{AllocationPercentage: 70}, want: 1},
+		{name: "further relief restores all tiers", advance: 10 * time.Minute, signal: Signal{AllocationPercentage: 70}, want: 0},
+	}
+
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		if _, got := m.Observe(testConfig, step.signal); got != step.want {
+			t.Fatalf("%s: level = %d, want %d", step.name, got, step.want)
+		}
+	}
+}
+
+func TestShed(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      int
+		tier       string
+		percentage int32
+		want       int32
+		wantShed   bool
+	}{
+		{name: "no pressure", level: 0, tier: "batch", percentage: 100, want: 100},
+		{name: "lowest tier shed first", level: 1, tier: "batch", percentage: 100, want: 50, wantShed: true},
+		{name: "higher tier untouched at level one", level: 1, tier: "standard", percentage: 100, want: 100},
+		{name: "higher tier bounded by min percentage", level: 2, tier: "standard", percentage: 100, want: 90, wantShed: true},
+		{name: "unknown tier untouched", level: 2, tier: "critical", percentage: 100, want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, shed := Shed(testConfig, tt.level, tt.tier, tt.percentage)
+			if got != tt.want || shed != tt.wantShed {
+				t.Errorf("Shed() = (%d, %v), want (%d, %v)", got, shed, tt.want, tt.wantShed)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Package rbac renders the ClusterRole of the controller limited to the
+// features an installation uses, for installs where every permission is
+// security reviewed. With every feature enabled it matches the role
+// generated by controller-gen from the kubebuilder markers.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// DefaultRoleName is the name of the ClusterRole bound to the controller
+const DefaultRoleName = "manager-role"
+
+// Feature is an optional part of the controller needing extra permissions
+type Feature string
+
+// Optional features
+const (
+	// FeatureStatefulSets scales StatefulSets (statefulSets.enabled)
+	FeatureStatefulSets Feature = "statefulsets"
+	// FeatureLegacyWorkloads scales ReplicationControllers and bare ReplicaSets (--enable-legacy-workloads)
+	FeatureLegacyWorkloads Feature = "legacy-workloads"
+	// FeatureJobParallelism scales the parallelism of Jobs (--enable-job-parallelism)
+	FeatureJobParallelism Feature = "job-parallelism"
+	// FeatureKEDA manages the limits of KEDA ScaledObjects
+	FeatureKEDA Feature = "keda"
+	// FeatureArgoRollouts detects deployments owned by Argo Rollouts
+	FeatureArgoRollouts Feature = "argo-rollouts"
+	// FeatureFlagger applies canary-scoped overrides during Flagger analyses
+	FeatureFlagger Feature = "flagger"
+	// FeatureSteppedScaleDown bounds scale-down steps by PodDisruptionBudgets (scaleDown.stepped)
+	FeatureSteppedScaleDown Feature = "stepped-scale-down"
+	// FeatureVerification verifies availability after scale-downs (spec.verification of overrides)
+	FeatureVerification Feature = "verification"
+	// FeatureClusterAutoscalerProtection marks the pods of scale-ups not safe to evict (spec.clusterAutoscalerProtection of overrides)
+	FeatureClusterAutoscalerProtection Feature = "cluster-autoscaler-protection"
+	// FeaturePreDownscaleDelay marks the pods a scale-down removes for draining (spec.preDownscaleDelay of overrides)
+	FeaturePreDownscaleDelay Feature = "pre-downscale-delay"
+	// FeatureTrafficShift shifts the HTTPRoute weights of scaled deployments (spec.trafficShift of overrides)
+	FeatureTrafficShift Feature = "traffic-shift"
+	// FeatureRollingApply orders the targets of Rolling overrides by PriorityClass (spec.applyStrategy of overrides)
+	FeatureRollingApply Feature = "rolling-apply"
+	// FeaturePlaceholders maintains overprovisioning placeholder Deployments (spec.placeholder of overrides)
+	FeaturePlaceholders Feature = "placeholders"
+	// FeatureNotifications reads the credentials of override notification targets
+	FeatureNotifications Feature = "notifications"
+	// FeatureNodeDisruption boosts workloads on disrupted nodes (nodeDisruption.enabled)
+	FeatureNodeDisruption Feature = "node-disruption"
+	// FeatureNodePressure sheds low-priority tiers under node pressure (nodePressure.enabled)
+	FeatureNodePressure Feature = "node-pressure"
+	// FeatureReport publishes the scaling report to a ConfigMap (report.enabled)
+	FeatureReport Feature = "report"
+	// FeatureErrorBudget reads Sloth SLOs for the errorRate checks of verifications (errorBudget.prometheusURL)
+	FeatureErrorBudget Feature = "error-budget"
+	// FeatureSecretCredentials reads the integration credentials referenced by the global config (*SecretRef fields)
+	FeatureSecretCredentials Feature = "secret-credentials"
+)
+
+var allVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// coreRules are the permissions of the controller whatever the configuration
+var coreRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"namespaces", "nodes", "resourcequotas"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+	{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+	{APIGroups: []string{"apps"}, Resources: []string{"daemonsets"}, Verbs: []string{"list", "watch"}},
+	{APIGroups: []string{"autoscaling"}, Resources: []string{"horizontalpodautoscalers"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+	{APIGroups: []string{"kubedynamicscaler.io"}, Resources: []string{"replicasoverrides", "globalreplicasignores"}, Verbs: allVerbs},
+	{APIGroups: []string{"kubedynamicscaler.io"}, Resources: []string{"replicasoverrides/status", "globalreplicasignores/status", "namespacescalingdefaults/status", "namespacereplicasoverrides/status"}, Verbs: []string{"get", "update", "patch"}},
+	{APIGroups: []string{"kubedynamicscaler.io"}, Resources: []string{"replicasoverrides/finalizers", "globalreplicasignores/finalizers"}, Verbs: []string{"update"}},
+	{APIGroups: []string{"kubedynamicscaler.io"}, Resources: []string{"namespacescalingdefaults", "namespacereplicasoverrides", "scalinggrants"}, Verbs: []string{"get", "list", "watch"}},
+}
+
+// featureRules are the permissions only needed by each optional feature
+var featureRules = map[Feature][]rbacv1.PolicyRule{
+	FeatureStatefulSets: {
+		{APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+	},
+	FeatureLegacyWorkloads: {
+		{APIGroups: []string{""}, Resources: []string{"replicationcontrollers"}, Verbs: []string{"get", "list", "watch", "patch"}},
+		{APIGroups: []string{""}, Resources: []string{"replicationcontrollers/scale"}, Verbs: []string{"get", "update"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"replicasets"}, Verbs: []string{"get", "list", "watch", "patch"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"replicasets/scale"}, Verbs: []string{"get", "update"}},
+	},
+	FeatureJobParallelism: {
+		{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+	},
+	FeatureKEDA: {
+		{APIGroups: []string{"keda.sh"}, Resources: []string{"scaledobjects"}, Verbs: []string{"get", "list", "watch", "update", "patch"}},
+	},
+	FeatureArgoRollouts: {
+		{APIGroups: []string{"argoproj.io"}, Resources: []string{"rollouts"}, Verbs: []string{"get", "list", "watch"}},
+	},
+	FeatureFlagger: {
+		{APIGroups: []string{"flagger.app"}, Resources: []string{"canaries"}, Verbs: []string{"get", "list", "watch"}},
+	},
+	FeatureSteppedScaleDown: {
+		{APIGroups: []string{"policy"}, Resources: []string{"poddisruptionbudgets"}, Verbs: []string{"list"}},
+	},
+	FeatureVerification: {
+		{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"list", "watch"}},
+		{APIGroups: []string{"discovery.k8s.io"}, Resources: []string{"endpointslices"}, Verbs: []string{"list", "watch"}},
+	},
+	FeatureClusterAutoscalerProtection: {
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"patch"}},
+	},
+	FeaturePreDownscaleDelay: {
+		{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"patch"}},
+	},
+	FeatureTrafficShift: {
+		{APIGroups: []string{"gateway.networking.k8s.io"}, Resources: []string{"httproutes"}, Verbs: []string{"get", "update"}},
+	},
+	FeaturePlaceholders: {
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"create", "delete"}},
+	},
+	FeatureRollingApply: {
+		{APIGroups: []string{"scheduling.k8s.io"}, Resources: []string{"priorityclasses"}, Verbs: []string{"get", "list", "watch"}},
+	},
+	FeatureNotifications: {
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+	},
+	FeatureNodeDisruption: {
+		{APIGroups: []string{"apps"}, Resources: []string{"replicasets"}, Verbs: []string{"get"}},
+	},
+	FeatureNodePressure: {
+		{APIGroups: []string{"scheduling.k8s.io"}, Resources: []string{"priorityclasses"}, Verbs: []string{"get", "list", "watch"}},
+	},
+	FeatureReport: {
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create", "update", "patch"}},
+	},
+	FeatureErrorBudget: {
+		{APIGroups: []string{"sloth.slok.dev"}, Resources: []string{"prometheusservicelevels"}, Verbs: []string{"get"}},
+	},
+	FeatureSecretCredentials: {
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}},
+	},
+}
+
+// Features returns every optional feature, sorted
+func Features() []Feature {
+	features := make([]Feature, 0, len(featureRules))
+	for feature := range featureRules {
+		features = append(features, feature)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// ParseFeatures parses a comma-separated list of features, "all" selecting every feature
+func ParseFeatures(list string) ([]Feature, error) {
+	var features []Feature
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case name == "all":
+			features = append(features, Features()...)
+		case featureRules[Feature(name)] != nil:
+			features = append(features, Feature(name))
+		default:
+			return nil, fmt.Errorf("unknown feature %q, expected one of %v", name, Features())
+		}
+	}
+	return features, nil
+}
+
+// FeaturesFromConfig returns the features turned on by the global config.
+// Integrations with other controllers are on when their external scaler is
+// configured; features enabled by controller flags or by overrides are not
+// visible in the config and must be requested explicitly.
+func FeaturesFromConfig(cfg *config.GlobalConfig) []Feature {
+	var features []Feature
+	if cfg.StatefulSets.Enabled {
+		features = append(features, FeatureStatefulSets)
+	}
+	if cfg.ScaleDown.Stepped {
+		features = append(features, FeatureSteppedScaleDown)
+	}
+	if cfg.ExternalScalers.KEDA.Mode != "" {
+		features = append(features, FeatureKEDA)
+	}
+	if cfg.ExternalScalers.ArgoRollouts.Mode != "" {
+		features = append(features, FeatureArgoRollouts)
+	}
+	if cfg.NodeDisruption.Enabled {
+		features = append(features, FeatureNodeDisruption)
+	}
+	if cfg.NodePressure.Enabled {
+		features = append(features, FeatureNodePressure)
+	}
+	if cfg.Report.Enabled {
+		features = append(features, FeatureReport)
+	}
+	if cfg.ErrorBudget.PrometheusURL != "" {
+		features = append(features, FeatureErrorBudget)
+	}
+	if len(cfg.SecretRefs()) > 0 {
+		features = append(features, FeatureSecretCredentials)
+	}
+	return features
+}
+
+// ClusterRole returns the ClusterRole granting the core permissions of the
+// controller and those of the features. Rules are merged and sorted like the
+// role generated by controller-gen.
+func ClusterRole(name string, features []Feature) *rbacv1.ClusterRole {
+	rules := append([]rbacv1.PolicyRule{}, coreRules...)
+	for _, feature := range features {
+		rules = append(rules, featureRules[feature]...)
+	}
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      normalize(rules),
+	}
+}
+
+// groupResource is a resource of an API group
+type groupResource struct {
+	group    string
+	resource string
+}
+
+// normalize merges the verbs of each resource, then groups the resources of
+// an API group sharing the same verbs into one rule
+func normalize(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	verbs := make(map[groupResource]map[string]bool)
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				key := groupResource{group: group, resource: resource}
+				if verbs[key] == nil {
+					verbs[key] = make(map[string]bool)
+				}
+				for _, verb := range rule.Verbs {
+					verbs[key][verb] = true
+				}
+			}
+		}
+	}
+
+	merged := make(map[string]*rbacv1.PolicyRule)
+	for key, set := range verbs {
+		ruleVerbs := make([]string, 0, len(set))
+		for verb := range set {
+			ruleVerbs = append(ruleVerbs, verb)
+		}
+		sort.Strings(ruleVerbs)
+		id := key.group + "|" + strings.Join(ruleVerbs, ",")
+		if merged[id] == nil {
+			merged[id] = &rbacv1.PolicyRule{APIGroups: []string{key.group}, Verbs: ruleVerbs}
+		}
+		merged[id].Resources = append(merged[id].Resources, key.resource)
+	}
+
+	result := make([]rbacv1.PolicyRule, 0, len(merged))
+	for _, rule := range merged {
+		sort.Strings(rule.Resources)
+		result = append(result, *rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].APIGroups[0] != result[j].APIGroups[0] {
+			return result[i].APIGroups[0] < result[j].APIGroups[0]
+		}
+		return strings.Join(result[i].Resources, ",") < strings.Join(result[j].Resources, ",")
+	})
+	return result
+}
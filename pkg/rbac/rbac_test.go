@@ -0,0 +1,87 @@
+package rbac
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// The role generated from the kubebuilder markers must be the role of every
+// feature, so a new permission cannot be added without assigning it a feature
+func TestAllFeaturesMatchGeneratedRole(t *testing.T) {
+	data, err := os.ReadFile("../../config/rbac/role.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, generated); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ClusterRole(generated.Name, Features())
+	if !reflect.DeepEqual(got.Rules, generated.Rules) {
+		gotData, _ := yaml.Marshal(got.Rules)
+		t.Errorf("rules of every feature differ from config/rbac/role.yaml, got:\n%s", gotData)
+	}
+}
+
+func TestClusterRoleOmitsDisabledFeatures(t *testing.T) {
+	role := ClusterRole(DefaultRoleName, []Feature{FeatureStatefulSets})
+
+	granted := make(map[string][]string)
+	for _, rule := range role.Rules {
+		for _, resource := range rule.Resources {
+			granted[rule.APIGroups[0]+"/"+resource] = rule.Verbs
+		}
+	}
+	for _, resource := range []string{"apps/statefulsets", "apps/deployments", "autoscaling/horizontalpodautoscalers"} {
+		if granted[resource] == nil {
+			t.Errorf("expected %s to be granted", resource)
+		}
+	}
+	for _, resource := range []string{"keda.sh/scaledobjects", "argoproj.io/rollouts", "batch/jobs", "/secrets", "apps/replicasets"} {
+		if granted[resource] != nil {
+			t.Errorf("expected %s not to be granted, got %v", resource, granted[resource])
+		}
+	}
+	if verbs := granted["/configmaps"]; !reflect.DeepEqual(verbs, []string{"get", "list", "watch"}) {
+		t.Errorf("configmaps verbs = %v, want read-only without the report", verbs)
+	}
+}
+
+func TestFeaturesFromConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if features := FeaturesFromConfig(cfg); len(features) != 0 {
+		t.Errorf("default config features = %v, want none", features)
+	}
+
+	cfg.StatefulSets.Enabled = true
+	cfg.ExternalScalers.KEDA.Mode = config.ScalerModeIgnore
+	cfg.Report.Enabled = true
+	cfg.DecisionWebhook.TokenSecretRef = &config.SecretKeyRef{Name: "decision-webhook", Key: "token"}
+	want := []Feature{FeatureStatefulSets, FeatureKEDA, FeatureReport, FeatureSecretCredentials}
+	if features := FeaturesFromConfig(cfg); !reflect.DeepEqual(features, want) {
+		t.Errorf("FeaturesFromConfig() = %v, want %v", features, want)
+	}
+}
+
+func TestParseFeatures(t *testing.T) {
+	features, err := ParseFeatures("keda, flagger,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Feature{FeatureKEDA, FeatureFlagger}; !reflect.DeepEqual(features, want) {
+		t.Errorf("ParseFeatures() = %v, want %v", features, want)
+	}
+	if features, _ := ParseFeatures("all"); len(features) != len(featureRules) {
+		t.Errorf("ParseFeatures(all) = %v, want every feature", features)
+	}
+	if _, err := ParseFeatures("kafka"); err == nil {
+		t.Error("expected an error for an unknown feature")
+	}
+}
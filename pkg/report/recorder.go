@@ -0,0 +1,217 @@
+package report
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRetention is how long scaling events are kept for reporting
+const DefaultRetention = 24 * time.Hour
+
+// topWorkloadsLimit is the number of workloads listed in a summary
+const topWorkloadsLimit = 10
+
+// Event describes one scaling decision applied (or attempted) on a workload
+type Event struct {
+	// Time the event happened
+	Time time.Time
+	// Kind of the scaled object (Deployment, HorizontalPodAutoscaler)
+	Kind string
+	// Namespace of the scaled object
+	Namespace string
+	// Name of the scaled object
+	Name string
+	// Override that produced the change, empty for the global config
+	Override string
+	// OriginalReplicas is the stored baseline of the workload
+	OriginalReplicas int32
+	// PreviousReplicas is the value before the change
+	PreviousReplicas int32
+	// TargetReplicas is the value written by the controller
+	TargetReplicas int32
+	// Clamped is true when min/max limits changed the computed target
+	Clamped bool
+	// Error is set when the write failed
+	Error string
+	// Reason classifies Error, e.g. Conflict or QuotaExceeded
+	Reason string
+}
+
+// key identifies the workload of the event
+func (e Event) key() string {
+	return e.Kind + "/" + e.Namespace + "/" + e.Name
+}
+
+// Recorder keeps a bounded journal of scaling events used to build summaries.
+// It is safe for concurrent use; a nil Recorder ignores all calls.
+type Recorder struct {
+	mutex     sync.Mutex
+	events    []Event
+	baselines map[string]Event
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewRecorder creates a recorder keeping events for DefaultRetention
+func NewRecorder() *Recorder {
+	return &Recorder{
+		baselines: make(map[string]Event),
+		retention: DefaultRetention,
+		now:       time.Now,
+	}
+}
+
+// Record adds an event to the journal
+func (r *Recorder) Record(event Event) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if event.Time.IsZero() {
+		event.Time = r.now()
+	}
+	r.events = append(r.events, event)
+	r.prune()
+}
+
+// ForgetNamespace drops the events and baselines of the workloads of a deleted
+// namespace, so summaries stop accounting replicas that no longer exist
+func (r *Recorder) ForgetNamespace(namespace string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	events := r.events[:0]
+	for _, event := range r.events {
+		if event.Namespace != namespace {
+			events = append(events, event)
+		}
+	}
+	r.events = events
+	for key, baseline := range r.baselines {
+		if baseline.Namespace == namespace {
+			delete(r.baselines, key)
+		}
+	}
+}
+
+// prune drops events older than the retention, remembering the last successful
+// one per workload so replica-hours can be integrated from the window start
+func (r *Recorder) prune() {
+	cutoff := r.now().Add(-r.retention)
+	i := 0
+	for ; i < len(r.events) && r.events[i].Time.Before(cutoff); i++ {
+		if r.events[i].Error == "" {
+			r.baselines[r.events[i].key()] = r.events[i]
+		}
+	}
+	r.events = r.events[i:]
+}
+
+// Summary aggregates the journal over the retention window
+func (r *Recorder) Summary() Summary {
+	if r == nil {
+		return Summary{}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.prune()
+
+	end := r.now()
+	summary := Summary{
+		WindowStart: end.Add(-r.retention),
+		WindowEnd:   end,
+	}
+
+	workloads := make(map[string]*WorkloadSummary)
+	lastState := make(map[string]Event, len(r.baselines))
+	lastTime := make(map[string]time.Time, len(r.baselines))
+	for key, baseline := range r.baselines {
+		lastState[key] = baseline
+		lastTime[key] = summary.WindowStart
+	}
+
+	workload := func(event Event) *WorkloadSummary {
+		key := event.key()
+		if w, ok := workloads[key]; ok {
+			return w
+		}
+		w := &WorkloadSummary{Kind: event.Kind, Namespace: event.Namespace, Name: event.Name, Override: event.Override}
+		workloads[key] = w
+		return w
+	}
+
+	accumulate := func(key string, until time.Time) {
+		state, ok := lastState[key]
+		if !ok {
+			return
+		}
+		hours := until.Sub(lastTime[key]).Hours() * float64(state.TargetReplicas-state.OriginalReplicas)
+		if hours > 0 {
+			summary.ReplicaHoursAdded += hours
+		} else {
+			summary.ReplicaHoursSaved -= hours
+		}
+		workload(state).ReplicaHours += hours
+	}
+
+	for _, event := range r.events {
+		w := workload(event)
+		w.Override = event.Override
+		if event.Error != "" {
+			summary.Failures++
+			w.Failures++
+			if event.Reason != "" {
+				if summary.FailuresByReason == nil {
+					summary.FailuresByReason = make(map[string]int)
+				}
+				summary.FailuresByReason[event.Reason]++
+			}
+			continue
+		}
+		summary.ScalingOperations++
+		w.Operations++
+		if event.Clamped {
+			summary.ClampEvents++
+		}
+
+		key := event.key()
+		accumulate(key, event.Time)
+		lastState[key] = event
+		lastTime[key] = event.Time
+	}
+	for key := range lastState {
+		accumulate(key, end)
+	}
+
+	for _, w := range workloads {
+		summary.TopWorkloads = append(summary.TopWorkloads, *w)
+	}
+	sort.Slice(summary.TopWorkloads, func(i, j int) bool {
+		a, b := summary.TopWorkloads[i], summary.TopWorkloads[j]
+		if abs(a.ReplicaHours) != abs(b.ReplicaHours) {
+			return abs(a.ReplicaHours) > abs(b.ReplicaHours)
+		}
+		if a.Operations != b.Operations {
+			return a.Operations > b.Operations
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	if len(summary.TopWorkloads) > topWorkloadsLimit {
+		summary.TopWorkloads = summary.TopWorkloads[:topWorkloadsLimit]
+	}
+
+	return summary
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
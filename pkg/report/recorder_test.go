@@ -0,0 +1,99 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummaryIntegratesReplicaHours(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder()
+	recorder.now = func() time.Time { return now }
+
+	// Scaled up by 2 replicas for the last 3 hours
+	recorder.Record(Event{
+		Time: now.Add(-3 * time.Hour), Kind: "Deployment", Namespace: "shop", Name: "checkout",
+		OriginalReplicas: 2, PreviousReplicas: 2, TargetReplicas: 4,
+	})
+	// Scaled down by 5 replicas for the last hour, clamped by min replicas
+	recorder.Record(Event{
+		Time: now.Add(-1 * time.Hour), Kind: "Deployment", Namespace: "batch", Name: "worker", Override: "night",
+		OriginalReplicas: 10, PreviousReplicas: 10, TargetReplicas: 5, Clamped: true,
+	})
+	recorder.Record(Event{
+		Time: now.Add(-30 * time.Minute), Kind: "Deployment", Namespace: "batch", Name: "worker",
+		Error: "conflict", Reason: "Conflict",
+	})
+
+	summary := recorder.Summary()
+
+	if summary.ScalingOperations != 2 || summary.Failures != 1 || summary.ClampEvents != 1 {
+		t.Errorf("unexpected counts: %+v", summary)
+	}
+	if summary.FailuresByReason["Conflict"] != 1 {
+		t.Errorf("FailuresByReason = %v, want one Conflict", summary.FailuresByReason)
+	}
+	if summary.ReplicaHoursAdded != 6 {
+		t.Errorf("ReplicaHoursAdded = %v, want 6", summary.ReplicaHoursAdded)
+	}
+	if summary.ReplicaHoursSaved != 5 {
+		t.Errorf("ReplicaHoursSaved = %v, want 5", summary.ReplicaHoursSaved)
+	}
+	if len(summary.TopWorkloads) != 2 || summary.TopWorkloads[0].Name != "checkout" {
+		t.Errorf("unexpected top workloads: %+v", summary.TopWorkloads)
+	}
+}
+
+func TestSummaryUsesBaselineFromPrunedEvents(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder()
+	recorder.now = func() time.Time { return now }
+
+	// Older than the window: only the state it established counts
+	recorder.Record(Event{
+		Time: now.Add(-48 * time.Hour), Kind: "Deployment", Namespace: "shop", Name: "api",
+		OriginalReplicas: 4, PreviousReplicas: 4, TargetReplicas: 2,
+	})
+
+	summary := recorder.Summary()
+	if summary.ScalingOperations != 0 {
+		t.Errorf("ScalingOperations = %d, want 0", summary.ScalingOperations)
+	}
+	if summary.ReplicaHoursSaved != 48 {
+		t.Errorf("ReplicaHoursSaved = %v, want 48", summary.ReplicaHoursSaved)
+	}
+}
+
+func TestForgetNamespace(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	recorder := NewRecorder()
+	recorder.now = func() time.Time { return now }
+
+	recorder.Record(Event{
+		Time: now.Add(-48 * time.Hour), Kind: "Deployment", Namespace: "shop", Name: "api",
+		OriginalReplicas: 4, PreviousReplicas: 4, TargetReplicas: 2,
+	})
+	recorder.Record(Event{
+		Time: now.Add(-1 * time.Hour), Kind: "Deployment", Namespace: "shop", Name: "web",
+		OriginalReplicas: 2, PreviousReplicas: 2, TargetReplicas: 4,
+	})
+	recorder.Record(Event{
+		Time: now.Add(-1 * time.Hour), Kind: "Deployment", Namespace: "batch", Name: "worker",
+		OriginalReplicas: 2, PreviousReplicas: 2, TargetReplicas: 3,
+	})
+	recorder.Summary()
+
+	recorder.ForgetNamespace("shop")
+	summary := recorder.Summary()
+	if summary.ScalingOperations != 1 || summary.ReplicaHoursSaved != 0 || summary.ReplicaHoursAdded != 1 {
+		t.Errorf("summary after forgetting shop = %+v, want only the batch worker", summary)
+	}
+}
+
+func TestNilRecorderIsNoop(t *testing.T) {
+	var recorder *Recorder
+	recorder.Record(Event{Name: "ignored"})
+	if summary := recorder.Summary(); summary.ScalingOperations != 0 {
+		t.Errorf("expected empty summary, got %+v", summary)
+	}
+}
@@ -0,0 +1,170 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// SummaryKey is the ConfigMap key holding the latest summary
+	SummaryKey = "summary.yaml"
+
+	// checkInterval is how often the reporter checks whether a report is due
+	checkInterval = time.Minute
+
+	// webhookTimeout bounds a single webhook delivery
+	webhookTimeout = 10 * time.Second
+)
+
+// Summary is the periodic scaling report
+type Summary struct {
+	// WindowStart is the beginning of the reporting window
+	WindowStart time.Time `yaml:"windowStart" json:"windowStart"`
+	// WindowEnd is the end of the reporting window
+	WindowEnd time.Time `yaml:"windowEnd" json:"windowEnd"`
+	// ScalingOperations is the number of successful writes
+	ScalingOperations int `yaml:"scalingOperations" json:"scalingOperations"`
+	// Failures is the number of failed writes
+	Failures int `yaml:"failures" json:"failures"`
+	// FailuresByReason counts the failed writes by failure reason, e.g. Conflict
+	FailuresByReason map[string]int `yaml:"failuresByReason,omitempty" json:"failuresByReason,omitempty"`
+	// ClampEvents is the number of writes where min/max limits changed the target
+	ClampEvents int `yaml:"clampEvents" json:"clampEvents"`
+	// ReplicaHoursAdded is the replica-hours run above the original replicas
+	ReplicaHoursAdded float64 `yaml:"replicaHoursAdded" json:"replicaHoursAdded"`
+	// ReplicaHoursSaved is the replica-hours run below the original replicas
+	ReplicaHoursSaved float64 `yaml:"replicaHoursSaved" json:"replicaHoursSaved"`
+	// TopWorkloads lists the workloads with the largest replica-hour impact
+	TopWorkloads []WorkloadSummary `yaml:"topWorkloads,omitempty" json:"topWorkloads,omitempty"`
+}
+
+// WorkloadSummary is the contribution of a single workload to the report
+type WorkloadSummary struct {
+	Kind         string  `yaml:"kind" json:"kind"`
+	Namespace    string  `yaml:"namespace" json:"namespace"`
+	Name         string  `yaml:"name" json:"name"`
+	Override     string  `yaml:"override,omitempty" json:"override,omitempty"`
+	Operations   int     `yaml:"operations" json:"operations"`
+	Failures     int     `yaml:"failures,omitempty" json:"failures,omitempty"`
+	ReplicaHours float64 `yaml:"replicaHours" json:"replicaHours"`
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+
+// Reporter periodically publishes the recorder summary to a ConfigMap and,
+// optionally, a webhook. It implements manager.Runnable and only runs on the leader.
+type Reporter struct {
+	client    client.Client
+	config    *config.Manager
+	recorder  *Recorder
+	namespace string
+	http      *http.Client
+	lastRun   time.Time
+}
+
+// NewReporter creates a reporter writing to the controller namespace
+func NewReporter(c client.Client, configManager *config.Manager, recorder *Recorder) *Reporter {
+	return &Reporter{
+		client:    c,
+		config:    configManager,
+		recorder:  recorder,
+		namespace: configManager.Namespace(),
+		http:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Start runs the report loop until ctx is cancelled
+func (r *Reporter) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("report.Reporter")
+	r.lastRun = time.Now()
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			cfg := r.config.GetConfig().Report
+			if !cfg.Enabled || now.Sub(r.lastRun) < cfg.GetInterval() {
+				continue
+			}
+			r.lastRun = now
+			if err := r.Publish(ctx, cfg); err != nil {
+				log.Error(err, "Failed to publish scaling report")
+			}
+		}
+	}
+}
+
+// Publish writes the current summary to the report ConfigMap and webhook
+func (r *Reporter) Publish(ctx context.Context, cfg config.ReportConfig) error {
+	log := log.FromContext(ctx)
+	summary := r.recorder.Summary()
+
+	data, err := yaml.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfg.GetConfigMapName(),
+			Namespace: r.namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.client, cm, func() error {
+		cm.Data = map[string]string{SummaryKey: string(data)}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to write report ConfigMap: %w", err)
+	}
+
+	log.Info("Published scaling report",
+		"configmap", fmt.Sprintf("%s/%s", cm.Namespace, cm.Name),
+		"operations", summary.ScalingOperations,
+		"failures", summary.Failures,
+		"replica_hours_added", summary.ReplicaHoursAdded,
+		"replica_hours_saved", summary.ReplicaHoursSaved)
+
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	return r.sendWebhook(ctx, cfg.WebhookURL, summary)
+}
+
+// sendWebhook posts the summary as JSON
+func (r *Reporter) sendWebhook(ctx context.Context, url string, summary Summary) error {
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("report webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Package resolver maps custom resources of platforms run by operators, e.g.
+// a Kafka cluster or a game-server fleet, to the replica counts they hold, so
+// they are scaled by percentage like Deployments. Resolvers are registered in
+// code, or configured as fields of the custom resource in the global config.
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// SubResource is a replica count of a custom resource, scaled on its own
+type SubResource struct {
+	// Name identifies the sub-resource among those of its custom resource
+	Name string
+	// Replicas is the current replica count
+	Replicas int32
+	// MinReplicas is the lowest count the sub-resource may be scaled to
+	MinReplicas int32
+}
+
+// Resolver maps the custom resources of a kind to their scalable sub-resources
+type Resolver interface {
+	// GroupVersionKind is the kind of the custom resources the resolver maps
+	GroupVersionKind() schema.GroupVersionKind
+	// Resolve returns the sub-resources of obj. Sub-resources not set on obj
+	// yet, e.g. left to the defaults of the operator, are omitted.
+	Resolve(obj *unstructured.Unstructured) ([]SubResource, error)
+	// SetReplicas sets the replica count of the named sub-resource of obj
+	SetReplicas(obj *unstructured.Unstructured, name string, replicas int32) error
+}
+
+// Registry holds a resolver per kind
+type Registry struct {
+	resolvers map[schema.GroupVersionKind]Resolver
+}
+
+// NewRegistry returns a registry holding resolvers
+func NewRegistry(resolvers ...Resolver) *Registry {
+	r := &Registry{resolvers: make(map[schema.GroupVersionKind]Resolver, len(resolvers))}
+	for _, resolver := range resolvers {
+		r.Register(resolver)
+	}
+	return r
+}
+
+// Register adds resolver, replacing the one registered for the same kind
+func (r *Registry) Register(resolver Resolver) {
+	r.resolvers[resolver.GroupVersionKind()] = resolver
+}
+
+// Resolvers returns the resolvers ordered by kind. A nil registry has none.
+func (r *Registry) Resolvers() []Resolver {
+	if r == nil {
+		return nil
+	}
+	resolvers := make([]Resolver, 0, len(r.resolvers))
+	for _, resolver := range r.resolvers {
+		resolvers = append(resolvers, resolver)
+	}
+	sort.Slice(resolvers, func(i, j int) bool {
+		return resolvers[i].GroupVersionKind().String() < resolvers[j].GroupVersionKind().String()
+	})
+	return resolvers
+}
+
+// WithConfig returns a registry holding the resolvers of r and a FieldResolver
+// per configured kind. Resolvers registered in code win over configured ones
+// for the same kind.
+func (r *Registry) WithConfig(configs []config.WorkloadResolverConfig) (*Registry, error) {
+	merged := NewRegistry()
+	for _, cfg := range configs {
+		resolver, err := NewFieldResolver(cfg)
+		if err != nil {
+			return nil, err
+		}
+		merged.Register(resolver)
+	}
+	for _, resolver := range r.Resolvers() {
+		merged.Register(resolver)
+	}
+	return merged, nil
+}
+
+// FieldResolver resolves the sub-resources of a kind from configured fields
+type FieldResolver struct {
+	gvk    schema.GroupVersionKind
+	fields map[string]config.ResolvedSubResource
+	names  []string
+}
+
+// NewFieldResolver returns the resolver of a configured kind
+func NewFieldResolver(cfg config.WorkloadResolverConfig) (*FieldResolver, error) {
+	gv, err := schema.ParseGroupVersion(cfg.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion of workload resolver %s: %w", cfg.Kind, err)
+	}
+	if cfg.Kind == "" || len(cfg.SubResources) == 0 {
+		return nil, fmt.Errorf("workload resolver %s needs a kind and sub-resources", cfg.APIVersion)
+	}
+	resolver := &FieldResolver{gvk: gv.WithKind(cfg.Kind), fields: make(map[string]config.ResolvedSubResource)}
+	for _, sub := range cfg.SubResources {
+		if sub.Name == "" || sub.ReplicasPath == "" {
+			return nil, fmt.Errorf("sub-resource of workload resolver %s needs a name and a replicasPath", cfg.Kind)
+		}
+		if _, duplicate := resolver.fields[sub.Name]; duplicate {
+			return nil, fmt.Errorf("workload resolver %s has several sub-resources named %s", cfg.Kind, sub.Name)
+		}
+		resolver.fields[sub.Name] = sub
+		resolver.names = append(resolver.names, sub.Name)
+	}
+	return resolver, nil
+}
+
+// GroupVersionKind implements Resolver
+func (f *FieldResolver) GroupVersionKind() schema.GroupVersionKind {
+	return f.gvk
+}
+
+// Resolve implements Resolver
+func (f *FieldResolver) Resolve(obj *unstructured.Unstructured) ([]SubResource, error) {
+	var subs []SubResource
+	for _, name := range f.names {
+		field := f.fields[name]
+		replicas, found, err := unstructured.NestedFieldNoCopy(obj.Object, fieldPath(field.ReplicasPath)...)
+		if err != nil || !found {
+			continue
+		}
+		count, ok := replicaCount(replicas)
+		if !ok {
+			return nil, fmt.Errorf("%s of %s %s/%s is not a replica count", field.ReplicasPath, f.gvk.Kind, obj.GetNamespace(), obj.GetName())
+		}
+		subs = append(subs, SubResource{Name: name, Replicas: count, MinReplicas: field.MinReplicas})
+	}
+	return subs, nil
+}
+
+// SetReplicas implements Resolver
+func (f *FieldResolver) SetReplicas(obj *unstructured.Unstructured, name string, replicas int32) error {
+	field, found := f.fields[name]
+	if !found {
+		return fmt.Errorf("unknown sub-resource %s of %s", name, f.gvk.Kind)
+	}
+	return unstructured.SetNestedField(obj.Object, int64(replicas), fieldPath(field.ReplicasPath)...)
+}
+
+// fieldPath splits a dot-separated path
+func fieldPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
+
+// replicaCount returns a decoded JSON number as a replica count
+func replicaCount(value any) (int32, bool) {
+	switch v := value.(type) {
+	case int64:
+		return int32(v), v >= 0
+	case int32:
+		return v, v >= 0
+	case int:
+		return int32(v), v >= 0
+	case float64:
+		return int32(v), v >= 0 && v == float64(int32(v))
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+var kafkaConfig = config.WorkloadResolverConfig{
+	APIVersion: "kafka.strimzi.io/v1beta2",
+	Kind:       "Kafka",
+	SubResources: []config.ResolvedSubResource{
+		{Name: "brokers", ReplicasPath: "spec.kafka.replicas"},
+		{Name: "zookeeper", ReplicasPath: "spec.zookeeper.replicas", MinReplicas: 3},
+		{Name: "cruise-control", ReplicasPath: "spec.cruiseControl.replicas"},
+	},
+}
+
+// fleetResolver is a resolver registered in code
+type fleetResolver struct{ FieldResolver }
+
+func TestFieldResolver(t *testing.T) {
+	resolver, err := NewFieldResolver(kafkaConfig)
+	if err != nil {
+		t.Fatalf("NewFieldResolver() failed: %v", err)
+	}
+	kafka := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"kafka":     map[string]any{"replicas": int64(6)},
+			"zookeeper": map[string]any{"replicas": int64(3)},
+		},
+	}}
+
+	// Sub-resources left to the defaults of the operator are omitted
+	subs, err := resolver.Resolve(kafka)
+	if err != nil {
+		t.Fatalf("Resolve() failed: %v", err)
+	}
+	want := []SubResource{{Name: "brokers", Replicas: 6}, {Name: "zookeeper", Replicas: 3, MinReplicas: 3}}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("Resolve() = %+v, want %+v", subs, want)
+	}
+
+	if err := resolver.SetReplicas(kafka, "brokers", 9); err != nil {
+		t.Fatalf("SetReplicas() failed: %v", err)
+	}
+	if replicas, _, _ := unstructured.NestedInt64(kafka.Object, "spec", "kafka", "replicas"); replicas != 9 {
+		t.Errorf("brokers replicas = %d, want 9", replicas)
+	}
+	if err := resolver.SetReplicas(kafka, "connect", 1); err == nil {
+		t.Error("SetReplicas() of an unknown sub-resource succeeded")
+	}
+
+	_ = unstructured.SetNestedField(kafka.Object, "three", "spec", "zookeeper", "replicas")
+	if _, err := resolver.Resolve(kafka); err == nil {
+		t.Error("Resolve() of a replica count that is not a number succeeded")
+	}
+
+	for _, invalid := range []config.WorkloadResolverConfig{
+		{APIVersion: "kafka.strimzi.io/v1beta2/x", Kind: "Kafka", SubResources: kafkaConfig.SubResources},
+		{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka"},
+		{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka", SubResources: []config.ResolvedSubResource{{Name: "brokers"}}},
+	} {
+		if _, err := NewFieldResolver(invalid); err == nil {
+			t.Errorf("NewFieldResolver(%+v) succeeded", invalid)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	fleetGVK := schema.GroupVersionKind{Group: "agones.dev", Version: "v1", Kind: "Fleet"}
+	fleet := &fleetResolver{FieldResolver{gvk: fleetGVK}}
+	kafka, _ := NewFieldResolver(kafkaConfig)
+	registry := NewRegistry(fleet)
+
+	// Resolvers registered in code win over configured ones
+	configured := config.WorkloadResolverConfig{APIVersion: "agones.dev/v1", Kind: "Fleet",
+		SubResources: []config.ResolvedSubResource{{Name: "servers", ReplicasPath: "spec.replicas"}}}
+	merged, err := registry.WithConfig([]config.WorkloadResolverConfig{kafkaConfig, configured})
+	if err != nil {
+		t.Fatalf("WithConfig() failed: %v", err)
+	}
+	resolvers := merged.Resolvers()
+	if len(resolvers) != 2 || resolvers[0] != Resolver(fleet) || resolvers[1].GroupVersionKind() != kafka.GroupVersionKind() {
+		t.Errorf("Resolvers() = %v, want the registered Fleet resolver and the configured Kafka one", resolvers)
+	}
+	if got := registry.Resolvers(); len(got) != 1 {
+		t.Errorf("WithConfig() changed the registry, it holds %d resolvers", len(got))
+	}
+
+	var none *Registry
+	if merged, err := none.WithConfig(nil); err != nil || len(merged.Resolvers()) != 0 {
+		t.Errorf("WithConfig() of a nil registry = %v, %v, want no resolver", merged, err)
+	}
+}
@@ -0,0 +1,331 @@
+// Package snapshot exports the scaling state of a cluster to a portable
+// manifest and imports it into another one, so the original values recorded
+// by the controller survive a disaster recovery.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+const (
+	// APIVersion and Kind identify a snapshot manifest. It is not a Kubernetes
+	// object and must be restored with an import, not applied.
+	APIVersion = "snapshot.kubedynamicscaler.io/v1"
+	Kind       = "ScalingSnapshot"
+)
+
+// originalAnnotations are the annotations holding the values of a scaled
+// object before the controller first changed it
+var originalAnnotations = []string{
+	utils.OriginalReplicasAnnotation,
+	utils.OriginalMinReplicasAnnotation,
+	utils.OriginalMaxReplicasAnnotation,
+	utils.OriginalParallelismAnnotation,
+}
+
+// trackedKinds are the kinds the controller records original values on.
+// Kinds whose API is not installed, like KEDA ScaledObjects, are skipped.
+var trackedKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "", Version: "v1", Kind: "ReplicationController"},
+	{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"},
+}
+
+// Snapshot is the scaling state of a cluster
+type Snapshot struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// CreatedAt is when the snapshot was exported
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	// Overrides, NamespaceDefaults, NamespaceOverrides and IgnoreRules are the
+	// custom resources of the controller, with their status
+	Overrides          []dynamicscalingv1.ReplicasOverride          `json:"overrides,omitempty"`
+	NamespaceDefaults  []dynamicscalingv1.NamespaceScalingDefault   `json:"namespaceDefaults,omitempty"`
+	NamespaceOverrides []dynamicscalingv1.NamespaceReplicasOverride `json:"namespaceOverrides,omitempty"`
+	IgnoreRules        []dynamicscalingv1.GlobalReplicasIgnore      `json:"ignoreRules,omitempty"`
+
+	// Originals are the original values recorded on the scaled objects
+	Originals []Original `json:"originals,omitempty"`
+}
+
+// Original holds the original values the controller recorded on a scaled object
+type Original struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+
+	// Annotations are the original-value annotations of the object
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Export reads the scaling state of the cluster
+func Export(ctx context.Context, c client.Reader, now time.Time) (*Snapshot, error) {
+	s := &Snapshot{APIVersion: APIVersion, Kind: Kind, CreatedAt: metav1.NewTime(now.UTC())}
+
+	overrides := &dynamicscalingv1.ReplicasOverrideList{}
+	if err := c.List(ctx, overrides); err != nil {
+		return nil, fmt.Errorf("failed to list overrides: %w", err)
+	}
+	for _, o := range overrides.Items {
+		o.TypeMeta = metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "ReplicasOverride"}
+		o.ObjectMeta = portableMeta(o.ObjectMeta)
+		s.Overrides = append(s.Overrides, o)
+	}
+
+	defaults := &dynamicscalingv1.NamespaceScalingDefaultList{}
+	if err := c.List(ctx, defaults); err != nil {
+		return nil, fmt.Errorf("failed to list namespace defaults: %w", err)
+	}
+	for _, d := range defaults.Items {
+		d.TypeMeta = metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "NamespaceScalingDefault"}
+		d.ObjectMeta = portableMeta(d.ObjectMeta)
+		s.NamespaceDefaults = append(s.NamespaceDefaults, d)
+	}
+
+	namespaceOverrides := &dynamicscalingv1.NamespaceReplicasOverrideList{}
+	if err := c.List(ctx, namespaceOverrides); err != nil {
+		return nil, fmt.Errorf("failed to list namespace overrides: %w", err)
+	}
+	for _, o := range namespaceOverrides.Items {
+		o.TypeMeta = metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "NamespaceReplicasOverride"}
+		o.ObjectMeta = portableMeta(o.ObjectMeta)
+		s.NamespaceOverrides = append(s.NamespaceOverrides, o)
+	}
+
+	ignores := &dynamicscalingv1.GlobalReplicasIgnoreList{}
+	if err := c.List(ctx, ignores); err != nil {
+		return nil, fmt.Errorf("failed to list ignore rules: %w", err)
+	}
+	for _, i := range ignores.Items {
+		i.TypeMeta = metav1.TypeMeta{APIVersion: dynamicscalingv1.GroupVersion.String(), Kind: "GlobalReplicasIgnore"}
+		i.ObjectMeta = portableMeta(i.ObjectMeta)
+		s.IgnoreRules = append(s.IgnoreRules, i)
+	}
+
+	for _, gvk := range trackedKinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list); err != nil {
+			if meta.IsNoMatchError(err) || runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+		}
+		for _, obj := range list.Items {
+			if annotations := originalsOf(obj.GetAnnotations()); len(annotations) > 0 {
+				s.Originals = append(s.Originals, Original{
+					APIVersion:  gvk.GroupVersion().String(),
+					Kind:        gvk.Kind,
+					Namespace:   obj.GetNamespace(),
+					Name:        obj.GetName(),
+					Annotations: annotations,
+				})
+			}
+		}
+	}
+	return s, nil
+}
+
+// portableMeta keeps the metadata of an object that is meaningful in another cluster
+func portableMeta(m metav1.ObjectMeta) metav1.ObjectMeta {
+	annotations := make(map[string]string)
+	for key, value := range m.Annotations {
+		if key != "kubectl.kubernetes.io/last-applied-configuration" {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	return metav1.ObjectMeta{Name: m.Name, Namespace: m.Namespace, Labels: m.Labels, Annotations: annotations}
+}
+
+// originalsOf returns the original-value annotations among annotations
+func originalsOf(annotations map[string]string) map[string]string {
+	originals := make(map[string]string)
+	for _, key := range originalAnnotations {
+		if value, ok := annotations[key]; ok {
+			originals[key] = value
+		}
+	}
+	return originals
+}
+
+// ImportOptions configures an import
+type ImportOptions struct {
+	// Overwrite replaces existing custom resources and original values,
+	// which are otherwise kept
+	Overwrite bool
+	// DryRun validates every write on the API server without persisting it
+	DryRun bool
+}
+
+// Result counts what an import did
+type Result struct {
+	Created int
+	Updated int
+	// Skipped objects already existed, or already had their original values
+	Skipped int
+	// Missing scaled objects do not exist in the cluster, yet
+	Missing int
+	// Errors of the objects that could not be imported
+	Errors []error
+}
+
+// Import restores a snapshot: the ignore rules first, so nothing ignored is
+// scaled, then the original values, so the controller scales from the
+// recorded baselines, then the namespace defaults and overrides.
+func Import(ctx context.Context, c client.Client, s *Snapshot, opts ImportOptions) (*Result, error) {
+	if s.APIVersion != APIVersion || s.Kind != Kind {
+		return nil, fmt.Errorf("not a %s %s: %s %s", APIVersion, Kind, s.APIVersion, s.Kind)
+	}
+
+	result := &Result{}
+	for i := range s.IgnoreRules {
+		result.record(importObject(ctx, c, &s.IgnoreRules[i], &dynamicscalingv1.GlobalReplicasIgnore{}, opts))
+	}
+	for _, original := range s.Originals {
+		result.record(importOriginal(ctx, c, original, opts))
+	}
+	for i := range s.NamespaceDefaults {
+		result.record(importObject(ctx, c, &s.NamespaceDefaults[i], &dynamicscalingv1.NamespaceScalingDefault{}, opts))
+	}
+	for i := range s.NamespaceOverrides {
+		result.record(importObject(ctx, c, &s.NamespaceOverrides[i], &dynamicscalingv1.NamespaceReplicasOverride{}, opts))
+	}
+	for i := range s.Overrides {
+		result.record(importObject(ctx, c, &s.Overrides[i], &dynamicscalingv1.ReplicasOverride{}, opts))
+	}
+	return result, nil
+}
+
+// outcome is what happened to one imported object
+type outcome int
+
+const (
+	outcomeCreated outcome = iota
+	outcomeUpdated
+	outcomeSkipped
+	outcomeMissing
+	outcomeFailed
+)
+
+func (r *Result) record(o outcome, err error) {
+	switch o {
+	case outcomeCreated:
+		r.Created++
+	case outcomeUpdated:
+		r.Updated++
+	case outcomeSkipped:
+		r.Skipped++
+	case outcomeMissing:
+		r.Missing++
+	case outcomeFailed:
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+// importObject creates obj with its status, or replaces the existing one,
+// read into existing, when overwriting
+func importObject(ctx context.Context, c client.Client, obj, existing client.Object, opts ImportOptions) (outcome, error) {
+	var createOpts []client.CreateOption
+	var updateOpts []client.UpdateOption
+	if opts.DryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+		updateOpts = append(updateOpts, client.DryRunAll)
+	}
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	name := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+
+	// The status is written separately and would be dropped by the create
+	status := obj.DeepCopyObject().(client.Object)
+
+	err := c.Get(ctx, name, existing)
+	switch {
+	case errors.IsNotFound(err):
+		if err := c.Create(ctx, obj, createOpts...); err != nil {
+			return outcomeFailed, fmt.Errorf("failed to create %s %s: %w", kind, name, err)
+		}
+		if opts.DryRun {
+			return outcomeCreated, nil
+		}
+		status.SetResourceVersion(obj.GetResourceVersion())
+		if err := c.Status().Update(ctx, status); err != nil {
+			return outcomeFailed, fmt.Errorf("failed to restore the status of %s %s: %w", kind, name, err)
+		}
+		return outcomeCreated, nil
+	case err != nil:
+		return outcomeFailed, fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	case !opts.Overwrite:
+		return outcomeSkipped, nil
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if err := c.Update(ctx, obj, updateOpts...); err != nil {
+		return outcomeFailed, fmt.Errorf("failed to update %s %s: %w", kind, name, err)
+	}
+	return outcomeUpdated, nil
+}
+
+// importOriginal records the original values on an existing scaled object.
+// Values already recorded are kept unless overwriting.
+func importOriginal(ctx context.Context, c client.Client, original Original, opts ImportOptions) (outcome, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(original.APIVersion)
+	obj.SetKind(original.Kind)
+	name := types.NamespacedName{Name: original.Name, Namespace: original.Namespace}
+	if err := c.Get(ctx, name, obj); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return outcomeMissing, nil
+		}
+		return outcomeFailed, fmt.Errorf("failed to get %s %s: %w", original.Kind, name, err)
+	}
+
+	patch := client.MergeFrom(obj.DeepCopy())
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	changed := false
+	for key, value := range original.Annotations {
+		if current, ok := annotations[key]; ok && (current == value || !opts.Overwrite) {
+			continue
+		}
+		annotations[key] = value
+		changed = true
+	}
+	if !changed {
+		return outcomeSkipped, nil
+	}
+	obj.SetAnnotations(annotations)
+
+	var patchOpts []client.PatchOption
+	if opts.DryRun {
+		patchOpts = append(patchOpts, client.DryRunAll)
+	}
+	if err := c.Patch(ctx, obj, patch, patchOpts...); err != nil {
+		return outcomeFailed, fmt.Errorf("failed to record the original values of %s %s: %w", original.Kind, name, err)
+	}
+	return outcomeUpdated, nil
+}
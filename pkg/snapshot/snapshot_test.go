@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = dynamicscalingv1.AddToScheme(scheme)
+	return scheme
+}
+
+func newClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(newScheme()).
+		WithStatusSubresource(&dynamicscalingv1.ReplicasOverride{}).
+		WithObjects(objs...).Build()
+}
+
+func deployment(name string, replicas int32, annotations map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Annotations: annotations},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	source := newClient(
+		deployment("web", 8, map[string]string{utils.OriginalReplicasAnnotation: "4", utils.ManagedAnnotation: "true"}),
+		deployment("api", 2, nil),
+		&dynamicscalingv1.ReplicasOverride{
+			ObjectMeta: metav1.ObjectMeta{Name: "sale", Namespace: "shop", ResourceVersion: "7", UID: "old"},
+			Spec:       dynamicscalingv1.ReplicasOverrideSpec{DeploymentRef: &dynamicscalingv1.DeploymentReference{Name: "web"}, ReplicasPercentage: 200},
+			Status: dynamicscalingv1.ReplicasOverrideStatus{AffectedDeployments: []dynamicscalingv1.AffectedDeployment{
+				{Name: "web", Namespace: "shop", OriginalReplicas: 4, CurrentReplicas: 8},
+			}},
+		},
+	)
+
+	s, err := Export(ctx, source, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	if len(s.Overrides) != 1 || s.Overrides[0].UID != "" || s.Overrides[0].Kind != "ReplicasOverride" {
+		t.Errorf("Export() overrides = %+v, want one with portable metadata", s.Overrides)
+	}
+	if len(s.Originals) != 1 || s.Originals[0].Name != "web" || len(s.Originals[0].Annotations) != 1 {
+		t.Fatalf("Export() originals = %+v, want only the original replicas of web", s.Originals)
+	}
+
+	// The manifest round-trips through YAML
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Snapshot
+	if err := yaml.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rebuilt cluster where web came back with its manifest replicas
+	target := newClient(deployment("web", 6, nil))
+	result, err := Import(ctx, target, &restored, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if result.Created != 1 || result.Updated != 1 || len(result.Errors) != 0 {
+		t.Errorf("Import() = %+v, want the override created and web annotated", result)
+	}
+
+	web := &appsv1.Deployment{}
+	if err := target.Get(ctx, types.NamespacedName{Name: "web", Namespace: "shop"}, web); err != nil {
+		t.Fatal(err)
+	}
+	if web.Annotations[utils.OriginalReplicasAnnotation] != "4" {
+		t.Errorf("web annotations = %v, want the original replicas restored", web.Annotations)
+	}
+	override := &dynamicscalingv1.ReplicasOverride{}
+	if err := target.Get(ctx, types.NamespacedName{Name: "sale", Namespace: "shop"}, override); err != nil {
+		t.Fatal(err)
+	}
+	if override.Spec.ReplicasPercentage != 200 || len(override.Status.AffectedDeployments) != 1 {
+		t.Errorf("imported override = %+v, want its spec and status", override)
+	}
+
+	// Importing again keeps what exists
+	result, err = Import(ctx, target, &restored, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Skipped != 2 || result.Created != 0 || result.Updated != 0 {
+		t.Errorf("second Import() = %+v, want everything skipped", result)
+	}
+
+	if _, err := Import(ctx, target, &Snapshot{APIVersion: "v1", Kind: "List"}, ImportOptions{}); err == nil {
+		t.Error("expected a manifest of another kind to be rejected")
+	}
+}
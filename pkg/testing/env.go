@@ -0,0 +1,122 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// CRDDirectory returns the directory holding the CRD manifests of the
+// module, found next to the sources of this package in the module cache
+func CRDDirectory() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "crd", "bases")
+}
+
+// Environment is an envtest control plane with the CRDs of the controller installed
+type Environment struct {
+	// Env is the underlying envtest environment
+	Env *envtest.Environment
+	// Config connects to the API server
+	Config *rest.Config
+	// Client is a direct, uncached client of the API server
+	Client client.Client
+
+	cancel context.CancelFunc
+}
+
+// StartEnvironment starts an API server and etcd with the CRDs installed.
+// The binaries are looked up in binaryAssetsDirectory, or in the directory
+// of the KUBEBUILDER_ASSETS environment variable if empty, e.g. as set up by
+// setup-envtest.
+func StartEnvironment(binaryAssetsDirectory string) (*Environment, error) {
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{CRDDirectory()},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: binaryAssetsDirectory,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the test environment: %w", err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: Scheme()})
+	if err != nil {
+		_ = env.Stop()
+		return nil, fmt.Errorf("failed to create a client: %w", err)
+	}
+	return &Environment{Env: env, Config: cfg, Client: c}, nil
+}
+
+// WriteConfig creates or updates the ConfigMap of the controller, with its
+// namespace, holding cfg
+func (e *Environment) WriteConfig(ctx context.Context, cfg *config.GlobalConfig) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := e.Client.Create(ctx, Namespace(config.DefaultConfigMapNamespace)); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: config.ConfigMapName, Namespace: config.DefaultConfigMapNamespace}}
+	_, err = ctrl.CreateOrUpdate(ctx, e.Client, cm, func() error {
+		cm.Data = map[string]string{config.ConfigMapKey: string(data)}
+		return nil
+	})
+	return err
+}
+
+// StartController runs the ReplicasOverride and GlobalReplicasIgnore
+// controllers against the environment until Stop, with the configuration
+// served by manager
+func (e *Environment) StartController(manager *config.Manager) error {
+	mgr, err := ctrl.NewManager(e.Config, ctrl.Options{
+		Scheme:  Scheme(),
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := (&controller.ReplicasOverrideReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Config: manager,
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+	if err := (&controller.GlobalReplicasIgnoreReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	go func() {
+		_ = mgr.Start(ctx)
+	}()
+	return nil
+}
+
+// Stop stops the controller, if started, and the control plane
+func (e *Environment) Stop() error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	return e.Env.Stop()
+}
@@ -0,0 +1,72 @@
+package testing
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/internal/controller"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing/fakeclient"
+)
+
+// Scheme returns a scheme with the built-in kinds and the CRDs of the controller
+func Scheme() *runtime.Scheme {
+	return fakeclient.Scheme()
+}
+
+// NewFakeClient returns a fake client holding objects, with the status
+// subresource of the CRDs enabled like on a real API server
+func NewFakeClient(objects ...client.Object) client.Client {
+	return fakeclient.NewBuilder(objects...).Build()
+}
+
+// NewConfigManager returns a config manager serving cfg, or the default
+// configuration if nil, without any ConfigMap. Change it with SetConfig.
+func NewConfigManager(cfg *config.GlobalConfig) *config.Manager {
+	if cfg == nil {
+		cfg = config.DefaultConfig()
+	}
+	return config.NewStaticManager(cfg)
+}
+
+// Reconcile runs one reconciliation of the ReplicasOverrides of namespace
+// against c, as the controller would after a change in the namespace
+func Reconcile(ctx context.Context, c client.Client, manager *config.Manager, namespace string) (ctrl.Result, error) {
+	r := &controller.ReplicasOverrideReconciler{
+		Client: c,
+		Scheme: c.Scheme(),
+		Config: manager,
+	}
+	return r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace}})
+}
+
+// Replicas returns the replicas of a deployment
+func Replicas(ctx context.Context, c client.Reader, namespace, name string) (int32, error) {
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment); err != nil {
+		return 0, err
+	}
+	if deployment.Spec.Replicas == nil {
+		return 1, nil
+	}
+	return *deployment.Spec.Replicas, nil
+}
+
+// HPALimits returns the min and max replicas of an HPA
+func HPALimits(ctx context.Context, c client.Reader, namespace, name string) (int32, int32, error) {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, hpa); err != nil {
+		return 0, 0, err
+	}
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	return minReplicas, hpa.Spec.MaxReplicas, nil
+}
@@ -0,0 +1,93 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	c := NewFakeClient(
+		Namespace("shop"),
+		Deployment("shop", "web", 4, map[string]string{"tier": "frontend"}),
+		Deployment("shop", "api", 2, nil),
+		HPA("shop", "api", 2, 10),
+		Deployment("shop", "legacy", 3, map[string]string{"tier": "frontend"}),
+		Override("shop", "sale").Percentage(150).Selector(map[string]string{"tier": "frontend"}).Build(),
+		Override("shop", "api").Percentage(200).Deployment("api").Build(),
+		Ignore("keep-legacy").Resource("Deployment", "shop", "legacy").Build(),
+	)
+	manager := NewConfigManager(nil)
+
+	if _, err := Reconcile(ctx, c, manager, "shop"); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if replicas, err := Replicas(ctx, c, "shop", "web"); err != nil || replicas != 6 {
+		t.Errorf("web replicas = (%d, %v), want 150%% of 4", replicas, err)
+	}
+	if replicas, err := Replicas(ctx, c, "shop", "legacy"); err != nil || replicas != 3 {
+		t.Errorf("legacy replicas = (%d, %v), want the ignored deployment untouched", replicas, err)
+	}
+	if minReplicas, maxReplicas, err := HPALimits(ctx, c, "shop", "api"); err != nil || minReplicas != 4 || maxReplicas != 20 {
+		t.Errorf("api HPA limits = (%d, %d, %v), want 200%% of [2, 10]", minReplicas, maxReplicas, err)
+	}
+
+	// The global limits of a new configuration apply at the next reconciliation
+	cfg := config.DefaultConfig()
+	cfg.MaxReplicas = 5
+	manager.SetConfig(cfg)
+	if _, err := Reconcile(ctx, c, manager, "shop"); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if replicas, _ := Replicas(ctx, c, "shop", "web"); replicas != 5 {
+		t.Errorf("web replicas = %d, want the new maxReplicas", replicas)
+	}
+}
+
+func TestNamespaceScalingDefaultStatus(t *testing.T) {
+	ctx := context.Background()
+	percentage := int32(50)
+	c := NewFakeClient(
+		Namespace("shop"),
+		Deployment("shop", "web", 4, nil),
+		&dynamicscalingv1.NamespaceScalingDefault{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "shop"},
+			Spec:       dynamicscalingv1.NamespaceScalingDefaultSpec{ReplicasPercentage: &percentage},
+		},
+	)
+
+	if _, err := Reconcile(ctx, c, NewConfigManager(nil), "shop"); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	def := &dynamicscalingv1.NamespaceScalingDefault{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "shop", Name: "defaults"}, def); err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if def.Status.EffectivePercentage != 50 || len(def.Status.Conditions) == 0 {
+		t.Errorf("status = %+v, want the effective percentage and the Ready condition", def.Status)
+	}
+	if replicas, _ := Replicas(ctx, c, "shop", "web"); replicas != 2 {
+		t.Errorf("web replicas = %d, want 50%% of 4", replicas)
+	}
+}
+
+func TestOverrideBuilder(t *testing.T) {
+	override := Override("shop", "sale").Additive().Limits(2, 8).
+		TargetKinds(dynamicscalingv1.TargetKindDeployment).Paused().
+		Spec(func(spec *dynamicscalingv1.ReplicasOverrideSpec) { spec.HeadroomReplicas = 1 }).
+		Build()
+
+	if override.Spec.OverrideType != "additive" || *override.Spec.MinReplicas != 2 || *override.Spec.MaxReplicas != 8 ||
+		len(override.Spec.TargetKinds) != 1 || override.Spec.HeadroomReplicas != 1 || override.Spec.ReplicasPercentage != 100 {
+		t.Errorf("Build() spec = %+v", override.Spec)
+	}
+	if override.Annotations["kubedynamicscaler.io/paused"] != "true" {
+		t.Errorf("Build() annotations = %v, want the override paused", override.Annotations)
+	}
+}
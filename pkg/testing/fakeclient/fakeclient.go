@@ -0,0 +1,41 @@
+// Package fakeclient builds the fake clients of the tests of the controller
+// and of pkg/testing. It does not import the controller, so the tests of the
+// controller package can share it.
+package fakeclient
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+)
+
+// Scheme returns a scheme with the built-in kinds and the CRDs of the controller
+func Scheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(dynamicscalingv1.AddToScheme(scheme))
+	return scheme
+}
+
+// statusSubresources returns an object of every CRD with a status subresource
+func statusSubresources() []client.Object {
+	return []client.Object{
+		&dynamicscalingv1.ReplicasOverride{},
+		&dynamicscalingv1.GlobalReplicasIgnore{},
+		&dynamicscalingv1.NamespaceScalingDefault{},
+		&dynamicscalingv1.NamespaceReplicasOverride{},
+	}
+}
+
+// NewBuilder returns a fake client builder holding objects, with the status
+// subresource of the CRDs enabled like on a real API server
+func NewBuilder(objects ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithScheme(Scheme()).
+		WithStatusSubresource(statusSubresources()...).
+		WithObjects(objects...)
+}
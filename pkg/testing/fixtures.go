@@ -0,0 +1,170 @@
+// Package testing helps platform teams test their own scaling policies: it
+// builds fixtures of the CRDs and workloads, runs the reconciler against a
+// fake client with a static configuration, and starts an envtest control
+// plane with the CRDs installed. Import it under another name, e.g.
+// kdstesting, next to the standard testing package.
+package testing
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// OverrideBuilder builds ReplicasOverride fixtures
+type OverrideBuilder struct {
+	override dynamicscalingv1.ReplicasOverride
+}
+
+// Override starts a ReplicasOverride applying 100% in the override mode
+func Override(namespace, name string) *OverrideBuilder {
+	return &OverrideBuilder{override: dynamicscalingv1.ReplicasOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: dynamicscalingv1.ReplicasOverrideSpec{
+			OverrideType:       "override",
+			ReplicasPercentage: 100,
+		},
+	}}
+}
+
+// Percentage sets the replicas percentage
+func (b *OverrideBuilder) Percentage(percentage int32) *OverrideBuilder {
+	b.override.Spec.ReplicasPercentage = percentage
+	return b
+}
+
+// Additive switches the override to the additive mode
+func (b *OverrideBuilder) Additive() *OverrideBuilder {
+	b.override.Spec.OverrideType = "additive"
+	return b
+}
+
+// Selector targets the deployments with the given labels
+func (b *OverrideBuilder) Selector(matchLabels map[string]string) *OverrideBuilder {
+	b.override.Spec.Selector = &dynamicscalingv1.TargetSelector{MatchLabels: matchLabels}
+	return b
+}
+
+// Deployment targets a single deployment of the namespace of the override
+func (b *OverrideBuilder) Deployment(name string) *OverrideBuilder {
+	b.override.Spec.DeploymentRef = &dynamicscalingv1.DeploymentReference{Name: name, Namespace: b.override.Namespace}
+	return b
+}
+
+// Limits sets the min and max replicas of the override
+func (b *OverrideBuilder) Limits(minReplicas, maxReplicas int32) *OverrideBuilder {
+	b.override.Spec.MinReplicas = &minReplicas
+	b.override.Spec.MaxReplicas = &maxReplicas
+	return b
+}
+
+// TargetKinds restricts the override to the given kinds
+func (b *OverrideBuilder) TargetKinds(kinds ...dynamicscalingv1.TargetKind) *OverrideBuilder {
+	b.override.Spec.TargetKinds = kinds
+	return b
+}
+
+// Paused marks the override as paused
+func (b *OverrideBuilder) Paused() *OverrideBuilder {
+	return b.Annotation(utils.PausedAnnotation, "true")
+}
+
+// Annotation sets an annotation of the override
+func (b *OverrideBuilder) Annotation(key, value string) *OverrideBuilder {
+	if b.override.Annotations == nil {
+		b.override.Annotations = make(map[string]string)
+	}
+	b.override.Annotations[key] = value
+	return b
+}
+
+// Spec lets the caller set any other field of the spec
+func (b *OverrideBuilder) Spec(mutate func(spec *dynamicscalingv1.ReplicasOverrideSpec)) *OverrideBuilder {
+	mutate(&b.override.Spec)
+	return b
+}
+
+// Build returns a copy of the override built so far
+func (b *OverrideBuilder) Build() *dynamicscalingv1.ReplicasOverride {
+	return b.override.DeepCopy()
+}
+
+// IgnoreBuilder builds GlobalReplicasIgnore fixtures
+type IgnoreBuilder struct {
+	ignore dynamicscalingv1.GlobalReplicasIgnore
+}
+
+// Ignore starts an empty GlobalReplicasIgnore
+func Ignore(name string) *IgnoreBuilder {
+	return &IgnoreBuilder{ignore: dynamicscalingv1.GlobalReplicasIgnore{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}}
+}
+
+// Namespaces ignores every workload of the given namespaces
+func (b *IgnoreBuilder) Namespaces(namespaces ...string) *IgnoreBuilder {
+	b.ignore.Spec.IgnoreNamespaces = append(b.ignore.Spec.IgnoreNamespaces, namespaces...)
+	return b
+}
+
+// Resource ignores a single workload
+func (b *IgnoreBuilder) Resource(kind, namespace, name string) *IgnoreBuilder {
+	b.ignore.Spec.IgnoreResources = append(b.ignore.Spec.IgnoreResources, dynamicscalingv1.IgnoredResource{
+		Kind: kind, Namespace: namespace, Name: name,
+	})
+	return b
+}
+
+// Labels ignores the workloads carrying all the given labels
+func (b *IgnoreBuilder) Labels(labels map[string]string) *IgnoreBuilder {
+	b.ignore.Spec.IgnoreLabels = labels
+	return b
+}
+
+// Build returns a copy of the ignore rule built so far
+func (b *IgnoreBuilder) Build() *dynamicscalingv1.GlobalReplicasIgnore {
+	return b.ignore.DeepCopy()
+}
+
+// Namespace returns a Namespace fixture
+func Namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+// Deployment returns a Deployment fixture whose selector and pod template
+// carry labels, with a single container
+func Deployment(namespace, name string, replicas int32, labels map[string]string) *appsv1.Deployment {
+	podLabels := map[string]string{"app": name}
+	for key, value := range labels {
+		podLabels[key] = value
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: name, Image: "registry.k8s.io/pause:3.10"}},
+				},
+			},
+		},
+	}
+}
+
+// HPA returns an HPA fixture scaling a deployment between the given limits
+func HPA(namespace, deployment string, minReplicas, maxReplicas int32) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: deployment, Namespace: namespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: deployment},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    maxReplicas,
+		},
+	}
+}
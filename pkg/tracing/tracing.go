@@ -0,0 +1,70 @@
+// Package tracing traces the reconcile passes of the controller over OTLP, so
+// the trace ID exemplars of the scaling metrics link to the pass that scaled
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name resource attribute of the spans
+const ServiceName = "kubedynamicscaler"
+
+// endpointVariables enable tracing when one of them is set
+var endpointVariables = []string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"}
+
+// Tracer returns the tracer of the controller. Its spans carry no trace ID,
+// and the metrics no exemplars, until Setup installed a provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(ServiceName)
+}
+
+// Provider exports the spans of the controller. It implements manager.Runnable,
+// flushing the pending spans when the manager stops.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Setup installs a tracer provider exporting spans over OTLP/gRPC when an
+// OTLP endpoint is set, configured by the standard OTEL_EXPORTER_OTLP_*
+// variables. It returns nil when tracing is disabled.
+func Setup(ctx context.Context) (*Provider, error) {
+	enabled := false
+	for _, variable := range endpointVariables {
+		if os.Getenv(variable) != "" {
+			enabled = true
+		}
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return &Provider{provider: provider}, nil
+}
+
+// Start waits for ctx to be cancelled, then flushes and stops the exporter
+func (p *Provider) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return p.provider.Shutdown(context.WithoutCancel(ctx))
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica traces its own passes
+func (p *Provider) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,24 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetupDisabledWithoutEndpoint(t *testing.T) {
+	for _, variable := range endpointVariables {
+		t.Setenv(variable, "")
+	}
+	provider, err := Setup(context.Background())
+	if err != nil || provider != nil {
+		t.Errorf("Setup() = %v, %v, want tracing disabled", provider, err)
+	}
+	// Without a provider the spans carry no trace ID, so no exemplar is recorded
+	ctx, span := Tracer().Start(context.Background(), "pass")
+	defer span.End()
+	if trace.SpanContextFromContext(ctx).HasTraceID() {
+		t.Error("span of the default provider has a trace ID")
+	}
+}
@@ -0,0 +1,156 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// Supported ingress providers
+const (
+	IngressProviderNginx        = "ingress-nginx"
+	IngressProviderEnvoyGateway = "envoy-gateway"
+	nginxRequestsMetric         = "nginx_ingress_controller_requests"
+	envoyUpstreamRequestsMetric = "envoy_cluster_upstream_rq_total"
+)
+
+// errBaseline is returned while the first counter scrape is collected
+var errBaseline = errors.New("collecting baseline request counters")
+
+// counterSample is a counter total scraped at a point in time
+type counterSample struct {
+	total float64
+	at    time.Time
+}
+
+// ingressSource computes the request rate of a backend from the request
+// counters exposed by every ingress controller replica. It keeps the previous
+// scrape of each endpoint, so the first read only establishes a baseline.
+type ingressSource struct {
+	client *http.Client
+	cfg    config.IngressTriggerConfig
+	metric string
+	now    func() time.Time
+
+	mutex    sync.Mutex
+	previous map[string]counterSample
+}
+
+// newIngressSource validates cfg and resolves the provider metric
+func newIngressSource(client *http.Client, name string, cfg config.IngressTriggerConfig) (*ingressSource, error) {
+	if len(cfg.MetricsURLs) == 0 || len(cfg.Labels) == 0 {
+		return nil, fmt.Errorf("trigger %q: ingress requires metricsURLs and labels", name)
+	}
+	metric := cfg.Metric
+	if metric == "" {
+		switch cfg.Provider {
+		case IngressProviderNginx:
+			metric = nginxRequestsMetric
+		case IngressProviderEnvoyGateway:
+			metric = envoyUpstreamRequestsMetric
+		default:
+			return nil, fmt.Errorf("trigger %q: unknown ingress provider %q", name, cfg.Provider)
+		}
+	}
+	return &ingressSource{
+		client:   client,
+		cfg:      cfg,
+		metric:   metric,
+		now:      time.Now,
+		previous: make(map[string]counterSample),
+	}, nil
+}
+
+// Value implements Source and returns requests per second
+func (s *ingressSource) Value(ctx context.Context) (float64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var rate float64
+	baseline := false
+	for _, url := range s.cfg.MetricsURLs {
+		total, err := s.scrape(ctx, url)
+		if err != nil {
+			return 0, err
+		}
+		now := s.now()
+		previous, ok := s.previous[url]
+		s.previous[url] = counterSample{total: total, at: now}
+		if !ok {
+			baseline = true
+			continue
+		}
+
+		elapsed := now.Sub(previous.at).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+		delta := total - previous.total
+		if delta < 0 {
+			// The counter was reset by a controller restart
+			delta = total
+		}
+		rate += delta / elapsed
+	}
+	if baseline {
+		return 0, errBaseline
+	}
+	return rate, nil
+}
+
+// scrape returns the sum of the selected request counters at url
+func (s *ingressSource) scrape(ctx context.Context, url string) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scrape %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to scrape %s: %s", url, resp.Status)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse metrics from %s: %w", url, err)
+	}
+	family, ok := families[s.metric]
+	if !ok {
+		return 0, nil
+	}
+
+	var total float64
+	for _, m := range family.GetMetric() {
+		if matchesLabels(m.GetLabel(), s.cfg.Labels) {
+			total += m.GetCounter().GetValue() + m.GetUntyped().GetValue()
+		}
+	}
+	return total, nil
+}
+
+// matchesLabels returns true if the series carries every selected label value
+func matchesLabels(labels []*dto.LabelPair, selector map[string]string) bool {
+	matched := 0
+	for _, label := range labels {
+		if value, ok := selector[label.GetName()]; ok {
+			if value != label.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(selector)
+}
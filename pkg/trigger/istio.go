@@ -0,0 +1,132 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// Supported Istio signals
+const (
+	IstioSignalRequests = "requests"
+	IstioSignalLatency  = "latency"
+	IstioSignalErrors   = "errors"
+
+	defaultIstioQuantile = 0.99
+	defaultIstioWindow   = time.Minute
+)
+
+// istioSource evaluates a golden-signal query for a destination service
+type istioSource struct {
+	client *http.Client
+	cfg    config.IstioTriggerConfig
+	query  string
+}
+
+// newIstioSource validates cfg and builds its PromQL query
+func newIstioSource(client *http.Client, name string, cfg config.IstioTriggerConfig) (*istioSource, error) {
+	if cfg.PrometheusURL == "" || cfg.Service == "" || cfg.Namespace == "" {
+		return nil, fmt.Errorf("trigger %q: istio requires prometheusURL, service and namespace", name)
+	}
+	query, err := istioQuery(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("trigger %q: %w", name, err)
+	}
+	return &istioSource{client: client, cfg: cfg, query: query}, nil
+}
+
+// istioQuery returns the PromQL expression of the configured signal
+func istioQuery(cfg config.IstioTriggerConfig) (string, error) {
+	window := cfg.Window
+	if window <= 0 {
+		window = defaultIstioWindow
+	}
+	quantile := cfg.Quantile
+	if quantile <= 0 || quantile >= 1 {
+		quantile = defaultIstioQuantile
+	}
+	selector := fmt.Sprintf(`reporter="destination",destination_service_name=%q,destination_service_namespace=%q`,
+		cfg.Service, cfg.Namespace)
+	rangeSelector := model.Duration(window).String()
+
+	switch cfg.Signal {
+	case IstioSignalRequests:
+		return fmt.Sprintf(`sum(rate(istio_requests_total{%s}[%s]))`, selector, rangeSelector), nil
+	case IstioSignalLatency:
+		return fmt.Sprintf(`histogram_quantile(%s, sum by (le) (rate(istio_request_duration_milliseconds_bucket{%s}[%s])))`,
+			strconv.FormatFloat(quantile, 'f', -1, 64), selector, rangeSelector), nil
+	case IstioSignalErrors:
+		return fmt.Sprintf(`100 * sum(rate(istio_requests_total{%s,response_code=~"5.."}[%s])) / sum(rate(istio_requests_total{%s}[%s]))`,
+			selector, rangeSelector, selector, rangeSelector), nil
+	default:
+		return "", fmt.Errorf("unknown istio signal %q", cfg.Signal)
+	}
+}
+
+// prometheusResponse is the subset of the Prometheus instant query response used
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value [2]interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Value implements Source; an empty result (no traffic) reads as 0
+func (s *istioSource) Value(ctx context.Context) (float64, error) {
+	token := config.Credential(s.cfg.TokenSecretRef, s.cfg.TokenEnv)
+	return QueryPrometheus(ctx, s.client, s.cfg.PrometheusURL, token, s.query)
+}
+
+// QueryPrometheus runs an instant query against the Prometheus HTTP API at
+// baseURL and returns the value of its first sample, with an optional bearer
+// token. An empty result and NaN, e.g. from an empty denominator, read as 0.
+func QueryPrometheus(ctx context.Context, client *http.Client, baseURL, token, query string) (float64, error) {
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var response prometheusResponse
+	if err := getJSON(client, req, &response); err != nil {
+		return 0, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("unexpected prometheus result type %q", response.Data.ResultType)
+	}
+	if len(response.Data.Result) == 0 {
+		return 0, nil
+	}
+
+	raw, ok := response.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected prometheus sample value %v", response.Data.Result[0].Value[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid prometheus sample value %q", raw)
+	}
+	// NaN results come from empty denominators and histograms without traffic
+	if value != value {
+		return 0, nil
+	}
+	return value, nil
+}
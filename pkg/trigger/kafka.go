@@ -0,0 +1,47 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// kafkaSource reads the total lag of a consumer group from the Burrow v3 API
+type kafkaSource struct {
+	client *http.Client
+	cfg    config.KafkaTriggerConfig
+}
+
+// burrowLagResponse is the subset of the Burrow consumer lag response used
+type burrowLagResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+	Status  struct {
+		TotalLag float64 `json:"totallag"`
+	} `json:"status"`
+}
+
+// Value implements Source
+func (s *kafkaSource) Value(ctx context.Context) (float64, error) {
+	endpoint := fmt.Sprintf("%s/v3/kafka/%s/consumer/%s/lag",
+		strings.TrimSuffix(s.cfg.BurrowURL, "/"),
+		url.PathEscape(s.cfg.Cluster),
+		url.PathEscape(s.cfg.ConsumerGroup))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var response burrowLagResponse
+	if err := getJSON(s.client, req, &response); err != nil {
+		return 0, fmt.Errorf("failed to read consumer lag: %w", err)
+	}
+	if response.Error {
+		return 0, fmt.Errorf("burrow error: %s", response.Message)
+	}
+	return response.Status.TotalLag, nil
+}
@@ -0,0 +1,211 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// tickInterval is how often the manager checks which triggers are due
+const tickInterval = 5 * time.Second
+
+// reading is the latest value read from a trigger source and the stabilized
+// percentage it currently requests (0 when inactive)
+type reading struct {
+	value      float64
+	percentage int32
+	history    []recommendation
+	updated    time.Time
+}
+
+// cachedSource keeps a stateful source for as long as its config is unchanged
+type cachedSource struct {
+	cfg    config.TriggerConfig
+	source Source
+}
+
+// Manager polls the configured triggers and resolves the percentage they
+// request for each mapped override. It implements manager.Runnable; a nil
+// Manager never resolves a percentage.
+type Manager struct {
+	config *config.Manager
+
+	mutex    sync.RWMutex
+	readings map[string]reading
+	sources  map[string]cachedSource
+	polled   map[string]time.Time
+	changes  chan event.GenericEvent
+	now      func() time.Time
+}
+
+// NewManager creates a trigger manager reading its settings from the config manager
+func NewManager(configManager *config.Manager) *Manager {
+	return &Manager{
+		config:   configManager,
+		readings: make(map[string]reading),
+		sources:  make(map[string]cachedSource),
+		polled:   make(map[string]time.Time),
+		changes:  make(chan event.GenericEvent, 100),
+		now:      time.Now,
+	}
+}
+
+// Changes returns the channel notified when a trigger changes the percentage it requests
+func (m *Manager) Changes() chan event.GenericEvent {
+	return m.changes
+}
+
+// Start polls due triggers until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("trigger.Manager")
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	for {
+		for _, cfg := range m.config.GetConfig().Triggers {
+			if m.now().Sub(m.polled[cfg.Name]) < cfg.GetInterval() {
+				continue
+			}
+			m.polled[cfg.Name] = m.now()
+			if err := m.poll(ctx, cfg); err != nil {
+				log.Error(err, "Failed to read trigger", "trigger", cfg.Name, "type", cfg.Type)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; every replica keeps its own readings
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// poll reads the source of cfg and records its value
+func (m *Manager) poll(ctx context.Context, cfg config.TriggerConfig) error {
+	source, err := m.source(cfg)
+	if err != nil {
+		return err
+	}
+	value, err := source.Value(ctx)
+	if errors.Is(err, errBaseline) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).V(1).Info("Read trigger", "trigger", cfg.Name, "value", value)
+	m.SetValue(cfg, value)
+	return nil
+}
+
+// source returns the cached source of cfg, creating it when the config changed
+func (m *Manager) source(cfg config.TriggerConfig) (Source, error) {
+	if cached, ok := m.sources[cfg.Name]; ok && reflect.DeepEqual(cached.cfg, cfg) {
+		return cached.source, nil
+	}
+	source, err := NewSource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.sources[cfg.Name] = cachedSource{cfg: cfg, source: source}
+	return source, nil
+}
+
+// SetValue records the value of a trigger, stabilizes the percentage it requests
+// and notifies watchers when that percentage changes
+func (m *Manager) SetValue(cfg config.TriggerConfig, value float64) {
+	now := m.now()
+	recommended, _ := cfg.Percentage(value)
+
+	m.mutex.Lock()
+	previous, existed := m.readings[cfg.Name]
+	history := pruneHistory(cfg, append(previous.history, recommendation{percentage: recommended, at: now}), now)
+	percentage := stabilize(cfg, history, previous.percentage, now)
+	m.readings[cfg.Name] = reading{value: value, percentage: percentage, history: history, updated: now}
+	m.mutex.Unlock()
+
+	if !existed || percentage != previous.percentage {
+		select {
+		case m.changes <- event.GenericEvent{Object: &dynamicscalingv1.ReplicasOverride{}}:
+		default:
+		}
+	}
+}
+
+// Percentage returns the highest percentage requested by the triggers mapped to
+// the override, the type of the trigger requesting it, and false if no trigger is active
+func (m *Manager) Percentage(namespace, name string) (int32, string, bool) {
+	if m == nil {
+		return 0, "", false
+	}
+	key := namespace + "/" + name
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var percentage int32
+	var triggerType string
+	found := false
+	for _, cfg := range m.config.GetConfig().Triggers {
+		if !mapsOverride(cfg, key) {
+			continue
+		}
+		// Ignore readings that are missing or too old to be trusted
+		reading, ok := m.readings[cfg.Name]
+		if !ok || m.now().Sub(reading.updated) > 3*cfg.GetInterval() {
+			continue
+		}
+		if reading.percentage > 0 && (!found || reading.percentage > percentage) {
+			percentage, triggerType, found = reading.percentage, cfg.Type, true
+		}
+	}
+	return percentage, triggerType, found
+}
+
+// Values returns the latest value of each trigger mapped to the override, by trigger name
+func (m *Manager) Values(namespace, name string) map[string]float64 {
+	if m == nil {
+		return nil
+	}
+	key := namespace + "/" + name
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	values := make(map[string]float64)
+	for _, cfg := range m.config.GetConfig().Triggers {
+		if !mapsOverride(cfg, key) {
+			continue
+		}
+		// Ignore readings that are missing or too old to be trusted
+		reading, ok := m.readings[cfg.Name]
+		if !ok || m.now().Sub(reading.updated) > 3*cfg.GetInterval() {
+			continue
+		}
+		values[cfg.Name] = reading.value
+	}
+	return values
+}
+
+// mapsOverride returns true if the trigger drives the override "namespace/name"
+func mapsOverride(cfg config.TriggerConfig, key string) bool {
+	for _, override := range cfg.Overrides {
+		if override == key {
+			return true
+		}
+	}
+	return false
+}
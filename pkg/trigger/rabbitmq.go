@@ -0,0 +1,47 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// rabbitMQSource reads the number of messages of a queue from the management API
+type rabbitMQSource struct {
+	client *http.Client
+	cfg    config.RabbitMQTriggerConfig
+}
+
+// rabbitMQQueue is the subset of the management API queue response used
+type rabbitMQQueue struct {
+	Messages float64 `json:"messages"`
+}
+
+// Value implements Source
+func (s *rabbitMQSource) Value(ctx context.Context) (float64, error) {
+	vhost := s.cfg.VHost
+	if vhost == "" {
+		vhost = "/"
+	}
+	endpoint := fmt.Sprintf("%s/api/queues/%s/%s",
+		strings.TrimSuffix(s.cfg.URL, "/"),
+		url.PathEscape(vhost),
+		url.PathEscape(s.cfg.Queue))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	if s.cfg.UsernameSecretRef != nil || s.cfg.UsernameEnv != "" {
+		req.SetBasicAuth(config.Credential(s.cfg.UsernameSecretRef, s.cfg.UsernameEnv), config.Credential(s.cfg.PasswordSecretRef, s.cfg.PasswordEnv))
+	}
+
+	var queue rabbitMQQueue
+	if err := getJSON(s.client, req, &queue); err != nil {
+		return 0, fmt.Errorf("failed to read queue depth: %w", err)
+	}
+	return queue.Messages, nil
+}
@@ -0,0 +1,77 @@
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// Supported trigger types
+const (
+	TypeKafka    = "kafka"
+	TypeSQS      = "sqs"
+	TypeRabbitMQ = "rabbitmq"
+	TypeIngress  = "ingress"
+	TypeIstio    = "istio"
+)
+
+// requestTimeout bounds a single source request
+const requestTimeout = 10 * time.Second
+
+// Source reads the current value of an external signal
+type Source interface {
+	Value(ctx context.Context) (float64, error)
+}
+
+// NewSource creates the source described by cfg
+func NewSource(cfg config.TriggerConfig) (Source, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	switch cfg.Type {
+	case TypeKafka:
+		if cfg.Kafka == nil || cfg.Kafka.BurrowURL == "" || cfg.Kafka.Cluster == "" || cfg.Kafka.ConsumerGroup == "" {
+			return nil, fmt.Errorf("trigger %q: kafka requires burrowURL, cluster and consumerGroup", cfg.Name)
+		}
+		return &kafkaSource{client: client, cfg: *cfg.Kafka}, nil
+	case TypeSQS:
+		if cfg.SQS == nil || cfg.SQS.QueueURL == "" || cfg.SQS.Region == "" {
+			return nil, fmt.Errorf("trigger %q: sqs requires queueURL and region", cfg.Name)
+		}
+		return &sqsSource{client: client, cfg: *cfg.SQS, now: time.Now}, nil
+	case TypeRabbitMQ:
+		if cfg.RabbitMQ == nil || cfg.RabbitMQ.URL == "" || cfg.RabbitMQ.Queue == "" {
+			return nil, fmt.Errorf("trigger %q: rabbitmq requires url and queue", cfg.Name)
+		}
+		return &rabbitMQSource{client: client, cfg: *cfg.RabbitMQ}, nil
+	case TypeIngress:
+		if cfg.Ingress == nil {
+			return nil, fmt.Errorf("trigger %q: ingress requires metricsURLs and labels", cfg.Name)
+		}
+		return newIngressSource(client, cfg.Name, *cfg.Ingress)
+	case TypeIstio:
+		if cfg.Istio == nil {
+			return nil, fmt.Errorf("trigger %q: istio requires prometheusURL, service and namespace", cfg.Name)
+		}
+		return newIstioSource(client, cfg.Name, *cfg.Istio)
+	default:
+		return nil, fmt.Errorf("trigger %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// getJSON performs req and decodes the JSON response into out
+func getJSON(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
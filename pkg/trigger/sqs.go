@@ -0,0 +1,161 @@
+package trigger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+const (
+	// sqsService is the SigV4 service name of SQS
+	sqsService = "sqs"
+
+	// sqsVisibleAttribute and sqsInFlightAttribute are the queue depth attributes
+	sqsVisibleAttribute  = "ApproximateNumberOfMessages"
+	sqsInFlightAttribute = "ApproximateNumberOfMessagesNotVisible"
+)
+
+// sqsSource reads the approximate depth of an SQS queue using the query API
+// signed with AWS Signature Version 4
+type sqsSource struct {
+	client *http.Client
+	cfg    config.SQSTriggerConfig
+	now    func() time.Time
+}
+
+// sqsAttributesResponse is the GetQueueAttributes XML response
+type sqsAttributesResponse struct {
+	Attributes []struct {
+		Name  string `xml:"Name"`
+		Value string `xml:"Value"`
+	} `xml:"GetQueueAttributesResult>Attribute"`
+}
+
+// Value implements Source
+func (s *sqsSource) Value(ctx context.Context) (float64, error) {
+	form := url.Values{
+		"Action":          {"GetQueueAttributes"},
+		"Version":         {"2012-11-05"},
+		"AttributeName.1": {sqsVisibleAttribute},
+	}
+	if s.cfg.IncludeInFlight {
+		form.Set("AttributeName.2", sqsInFlightAttribute)
+	}
+	body := form.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.QueueURL, strings.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := s.sign(req, body); err != nil {
+		return 0, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read queue attributes: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("sqs returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var response sqsAttributesResponse
+	if err := xml.Unmarshal(data, &response); err != nil {
+		return 0, fmt.Errorf("failed to decode queue attributes: %w", err)
+	}
+	var depth float64
+	for _, attribute := range response.Attributes {
+		value, err := strconv.ParseFloat(attribute.Value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s value %q", attribute.Name, attribute.Value)
+		}
+		depth += value
+	}
+	return depth, nil
+}
+
+// sign adds the SigV4 authorization headers to req using credentials from the
+// referenced Secrets or the environment
+func (s *sqsSource) sign(req *http.Request, body string) error {
+	accessKey := config.Credential(s.cfg.AccessKeyIDSecretRef, "AWS_ACCESS_KEY_ID")
+	secretKey := config.Credential(s.cfg.SecretAccessKeySecretRef, "AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("the access key ID and secret access key must be set, from Secrets or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if token := config.Credential(s.cfg.SessionTokenSecretRef, "AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	headerNames := []string{"content-type", "host", "x-amz-date"}
+	if req.Header.Get("X-Amz-Security-Token") != "" {
+		headerNames = append(headerNames, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.cfg.Region, sqsService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, sqsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+	return nil
+}
+
+func hashHex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package trigger
+
+import (
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+// recommendation is the percentage requested by a single reading; 0 means the
+// trigger is inactive and the override keeps its own percentage
+type recommendation struct {
+	percentage int32
+	at         time.Time
+}
+
+// stabilize returns the percentage to apply given the recommendation history
+// (oldest first, including the latest) and the currently applied percentage.
+// As for the HPA, a rise uses the lowest recommendation of the scale up window
+// and a fall the highest recommendation of the scale down window, so only
+// sustained changes are applied. Changes within the tolerance band are ignored.
+func stabilize(cfg config.TriggerConfig, history []recommendation, current int32, now time.Time) int32 {
+	if len(history) == 0 {
+		return current
+	}
+	latest := history[len(history)-1].percentage
+
+	stabilized := latest
+	switch {
+	case latest > current:
+		window := cfg.GetScaleUpStabilization()
+		for _, r := range history {
+			if now.Sub(r.at) <= window && r.percentage < stabilized {
+				stabilized = r.percentage
+			}
+		}
+		if stabilized < current {
+			stabilized = current
+		}
+	case latest < current:
+		window := cfg.GetScaleDownStabilization()
+		for _, r := range history {
+			if now.Sub(r.at) <= window && r.percentage > stabilized {
+				stabilized = r.percentage
+			}
+		}
+		if stabilized > current {
+			stabilized = current
+		}
+	}
+
+	// Activation and release are never held back by the tolerance band
+	if current > 0 && stabilized > 0 {
+		change := float64(stabilized-current) * 100 / float64(current)
+		if change < 0 {
+			change = -change
+		}
+		if change <= cfg.GetTolerance() {
+			return current
+		}
+	}
+	return stabilized
+}
+
+// pruneHistory drops recommendations older than both stabilization windows,
+// always keeping the latest one
+func pruneHistory(cfg config.TriggerConfig, history []recommendation, now time.Time) []recommendation {
+	window := max(cfg.GetScaleUpStabilization(), cfg.GetScaleDownStabilization())
+	i := 0
+	for i < len(history)-1 && now.Sub(history[i].at) > window {
+		i++
+	}
+	return history[i:]
+}
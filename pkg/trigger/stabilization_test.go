@@ -0,0 +1,61 @@
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestStabilize(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	upWindow, downWindow := 2*time.Minute, 5*time.Minute
+	cfg := config.TriggerConfig{Behavior: &config.TriggerBehavior{
+		ScaleUpStabilization:   &upWindow,
+		ScaleDownStabilization: &downWindow,
+		Tolerance:              10,
+	}}
+
+	history := func(percentages ...int32) []recommendation {
+		var h []recommendation
+		for i, p := range percentages {
+			h = append(h, recommendation{percentage: p, at: start.Add(time.Duration(i) * time.Minute)})
+		}
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		history []recommendation
+		current int32
+		want    int32
+	}{
+		{name: "activation applies the lowest recent recommendation", history: history(150, 200), current: 0, want: 150},
+		{name: "rise waits for the up window", history: history(150, 150, 200), current: 150, want: 150},
+		{name: "sustained rise is applied", history: history(150, 200, 200, 200), current: 150, want: 200},
+		{name: "fall holds the highest recommendation of the down window", history: history(200, 200, 150, 150), current: 200, want: 200},
+		{name: "release after the down window", history: history(200, 0, 0, 0, 0, 0, 0, 0), current: 200, want: 0},
+		{name: "change within tolerance is ignored", history: history(105), current: 100, want: 100},
+		{name: "change above tolerance is applied", history: history(120, 120, 120), current: 100, want: 120},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := tt.history[len(tt.history)-1].at
+			if got := stabilize(cfg, tt.history, tt.current, now); got != tt.want {
+				t.Errorf("stabilize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultBehaviorDelaysScaleDown(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	history := []recommendation{
+		{percentage: 200, at: start},
+		{percentage: 100, at: start.Add(time.Minute)},
+	}
+	if got := stabilize(config.TriggerConfig{}, history, 200, start.Add(time.Minute)); got != 200 {
+		t.Errorf("stabilize() = %d, want 200 within the default scale down window", got)
+	}
+}
@@ -0,0 +1,210 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+)
+
+func TestStepPercentage(t *testing.T) {
+	cfg := config.TriggerConfig{
+		Steps: []config.TriggerStep{
+			{Threshold: 1000, Percentage: 200},
+			{Threshold: 100, Percentage: 150},
+		},
+	}
+
+	tests := []struct {
+		value      float64
+		want       int32
+		wantActive bool
+	}{
+		{value: 50},
+		{value: 100, want: 150, wantActive: true},
+		{value: 999, want: 150, wantActive: true},
+		{value: 5000, want: 200, wantActive: true},
+	}
+
+	for _, tt := range tests {
+		got, active := cfg.Percentage(tt.value)
+		if got != tt.want || active != tt.wantActive {
+			t.Errorf("Percentage(%v) = (%d, %v), want (%d, %v)", tt.value, got, active, tt.want, tt.wantActive)
+		}
+	}
+}
+
+func TestSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.EscapedPath() == "/v3/kafka/main/consumer/orders/lag":
+			fmt.Fprint(w, `{"error": false, "status": {"totallag": 1234}}`)
+		case r.URL.EscapedPath() == "/api/queues/%2F/jobs":
+			if user, pass, _ := r.BasicAuth(); user != "guest" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"name": "jobs", "messages": 42}`)
+		case r.URL.Path == "/123456789012/tasks":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `<GetQueueAttributesResponse><GetQueueAttributesResult>`+
+				`<Attribute><Name>ApproximateNumberOfMessages</Name><Value>7</Value></Attribute>`+
+				`<Attribute><Name>ApproximateNumberOfMessagesNotVisible</Name><Value>3</Value></Attribute>`+
+				`</GetQueueAttributesResult></GetQueueAttributesResponse>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("RABBITMQ_USER", "guest")
+	t.Setenv("RABBITMQ_PASS", "secret")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+
+	tests := []struct {
+		name string
+		cfg  config.TriggerConfig
+		want float64
+	}{
+		{
+			name: "kafka",
+			cfg: config.TriggerConfig{Type: TypeKafka, Kafka: &config.KafkaTriggerConfig{
+				BurrowURL: server.URL, Cluster: "main", ConsumerGroup: "orders",
+			}},
+			want: 1234,
+		},
+		{
+			name: "rabbitmq",
+			cfg: config.TriggerConfig{Type: TypeRabbitMQ, RabbitMQ: &config.RabbitMQTriggerConfig{
+				URL: server.URL, Queue: "jobs", UsernameEnv: "RABBITMQ_USER", PasswordEnv: "RABBITMQ_PASS",
+			}},
+			want: 42,
+		},
+		{
+			name: "sqs",
+			cfg: config.TriggerConfig{Type: TypeSQS, SQS: &config.SQSTriggerConfig{
+				QueueURL: server.URL + "/123456789012/tasks", Region: "eu-west-1", IncludeInFlight: true,
+			}},
+			want: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewSource(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewSource() failed: %v", err)
+			}
+			got, err := source.Value(context.Background())
+			if err != nil {
+				t.Fatalf("Value() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQSSignatureIsDeterministic(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	fixed := func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	source := &sqsSource{cfg: config.SQSTriggerConfig{Region: "us-east-1"}, now: fixed}
+
+	sign := func() string {
+		req := httptest.NewRequest(http.MethodPost, "https://sqs.us-east-1.amazonaws.com/1/q", nil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if err := source.sign(req, "Action=GetQueueAttributes"); err != nil {
+			t.Fatalf("sign() failed: %v", err)
+		}
+		return req.Header.Get("Authorization")
+	}
+
+	first := sign()
+	if !strings.Contains(first, "Credential=AKID/20250101/us-east-1/sqs/aws4_request") {
+		t.Errorf("unexpected credential scope in %q", first)
+	}
+	if second := sign(); first != second {
+		t.Errorf("signature is not deterministic: %q != %q", first, second)
+	}
+}
+
+func TestIngressSourceComputesRate(t *testing.T) {
+	total := 100
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "# TYPE nginx_ingress_controller_requests counter")
+		fmt.Fprintf(w, "nginx_ingress_controller_requests{namespace=\"shop\",ingress=\"web\",status=\"200\"} %d\n", total)
+		fmt.Fprintf(w, "nginx_ingress_controller_requests{namespace=\"shop\",ingress=\"web\",status=\"500\"} %d\n", total/10)
+		fmt.Fprintln(w, "nginx_ingress_controller_requests{namespace=\"shop\",ingress=\"api\",status=\"200\"} 999")
+	}))
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	source, err := NewSource(config.TriggerConfig{Type: TypeIngress, Ingress: &config.IngressTriggerConfig{
+		Provider:    IngressProviderNginx,
+		MetricsURLs: []string{server.URL},
+		Labels:      map[string]string{"namespace": "shop", "ingress": "web"},
+	}})
+	if err != nil {
+		t.Fatalf("NewSource() failed: %v", err)
+	}
+	source.(*ingressSource).now = func() time.Time { return now }
+
+	if _, err := source.Value(context.Background()); !errors.Is(err, errBaseline) {
+		t.Fatalf("first Value() error = %v, want errBaseline", err)
+	}
+
+	total, now = 400, now.Add(30*time.Second)
+	got, err := source.Value(context.Background())
+	if err != nil {
+		t.Fatalf("Value() failed: %v", err)
+	}
+	// (440 - 110) requests over 30 seconds
+	if got != 11 {
+		t.Errorf("Value() = %v, want 11", got)
+	}
+}
+
+func TestIstioSourceQueriesPrometheus(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		fmt.Fprint(w, `{"status": "success", "data": {"resultType": "vector", "result": [{"metric": {}, "value": [1700000000, "245.5"]}]}}`)
+	}))
+	defer server.Close()
+
+	source, err := NewSource(config.TriggerConfig{Type: TypeIstio, Istio: &config.IstioTriggerConfig{
+		PrometheusURL: server.URL,
+		Service:       "reviews",
+		Namespace:     "bookinfo",
+		Signal:        IstioSignalLatency,
+		Quantile:      0.95,
+		Window:        5 * time.Minute,
+	}})
+	if err != nil {
+		t.Fatalf("NewSource() failed: %v", err)
+	}
+
+	got, err := source.Value(context.Background())
+	if err != nil {
+		t.Fatalf("Value() failed: %v", err)
+	}
+	if got != 245.5 {
+		t.Errorf("Value() = %v, want 245.5", got)
+	}
+	want := `histogram_quantile(0.95, sum by (le) (rate(istio_request_duration_milliseconds_bucket{reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"}[5m])))`
+	if query != want {
+		t.Errorf("query = %s, want %s", query, want)
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"encoding/json"
+	"maps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// annotationsPatch is a merge patch of metadata.annotations only. It carries
+// no resourceVersion, so it never conflicts with other writers.
+type annotationsPatch struct {
+	changes map[string]string
+	values  map[string]interface{}
+}
+
+// AnnotationsPatch returns a merge patch setting changes on the annotations
+// of an object, removing those with an empty value. The state client writing
+// the v1 format moves the state annotations it sets into the state annotation,
+// computed from the annotations of the patched object, which must hold its
+// current annotations.
+func AnnotationsPatch(changes map[string]string) client.Patch {
+	values := make(map[string]interface{}, len(changes))
+	for key, value := range changes {
+		if value == "" {
+			values[key] = nil
+		} else {
+			values[key] = value
+		}
+	}
+	return &annotationsPatch{changes: changes, values: values}
+}
+
+// Type implements client.Patch
+func (p *annotationsPatch) Type() types.PatchType {
+	return types.MergePatchType
+}
+
+// Data implements client.Patch
+func (p *annotationsPatch) Data(client.Object) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": p.values}})
+}
+
+// collapsed returns the patch writing the state annotations of p into the
+// state annotation of obj, and removing the loose ones
+func (p *annotationsPatch) collapsed(obj metav1.Object) *annotationsPatch {
+	current := &metav1.ObjectMeta{Annotations: maps.Clone(obj.GetAnnotations())}
+	if current.Annotations == nil {
+		current.Annotations = make(map[string]string)
+	}
+	// An invalid state annotation is kept, the loose ones set then win
+	_ = ExpandState(current)
+
+	values := make(map[string]interface{}, len(p.values)+1)
+	held := false
+	for key, value := range p.values {
+		if StateField(key) == "" {
+			values[key] = value
+			continue
+		}
+		held = true
+		values[key] = nil
+		if value == nil {
+			delete(current.Annotations, key)
+		} else {
+			current.Annotations[key] = value.(string)
+		}
+	}
+	if !held {
+		return p
+	}
+	CollapseState(current)
+	if state, found := current.Annotations[StateAnnotation]; found {
+		values[StateAnnotation] = state
+	} else {
+		values[StateAnnotation] = nil
+	}
+	return &annotationsPatch{changes: p.changes, values: values}
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"math"
+)
+
+// BoundReplicas converts a replica count computed in floating point to an
+// int32 within [0, MaxInt32], truncating it. NaN counts as 0.
+func BoundReplicas(replicas float64) int32 {
+	if !(replicas > 0) {
+		return 0
+	}
+	if replicas >= math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(replicas)
+}
+
+// PercentOf returns percentage of replicas truncated to an integer, within
+// [0, MaxInt32] whatever the sign or size of the inputs
+func PercentOf(replicas, percentage int32) int32 {
+	return BoundReplicas(float64(replicas) * float64(percentage) / 100.0)
+}
+
+// PercentOfCeil returns percentage of replicas rounded up, within [0, MaxInt32]
+func PercentOfCeil(replicas, percentage int32) int32 {
+	return BoundReplicas(math.Ceil(float64(replicas) * float64(percentage) / 100.0))
+}
+
+// AddBounded returns a + b saturated to the int32 range instead of wrapping
+// around, for replicas and percentages raised by boosts and headroom
+func AddBounded(a, b int32) int32 {
+	return int32(min(max(int64(a)+int64(b), math.MinInt32), math.MaxInt32))
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundedArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		got  int32
+		want int32
+	}{
+		{name: "BoundReplicas truncates", got: BoundReplicas(2.9), want: 2},
+		{name: "BoundReplicas of a negative count", got: BoundReplicas(-3), want: 0},
+		{name: "BoundReplicas of NaN", got: BoundReplicas(math.NaN()), want: 0},
+		{name: "BoundReplicas of +Inf", got: BoundReplicas(math.Inf(1)), want: math.MaxInt32},
+		{name: "PercentOf truncates", got: PercentOf(3, 50), want: 1},
+		{name: "PercentOf of the largest original at the maximum percentage", got: PercentOf(math.MaxInt32, 1000), want: math.MaxInt32},
+		{name: "PercentOf of a negative percentage", got: PercentOf(4, math.MinInt32), want: 0},
+		{name: "PercentOfCeil rounds up", got: PercentOfCeil(3, 50), want: 2},
+		{name: "PercentOfCeil of the largest values", got: PercentOfCeil(math.MaxInt32, math.MaxInt32), want: math.MaxInt32},
+		{name: "AddBounded", got: AddBounded(150, 50), want: 200},
+		{name: "AddBounded saturates up", got: AddBounded(math.MaxInt32, 1), want: math.MaxInt32},
+		{name: "AddBounded saturates down", got: AddBounded(math.MinInt32, -1), want: math.MinInt32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %d, want %d", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Pager lists objects a page at a time from the API server, so cluster-wide
+// lists of large clusters are never held in memory, or deep copied out of the
+// cache, all at once
+type Pager struct {
+	// Reader reads from the API server. The cache does not support continue
+	// tokens and silently truncates limited lists.
+	Reader client.Reader
+	// PageSize is the number of objects per page
+	PageSize int64
+}
+
+// NewPager returns a pager reading pages of pageSize objects with reader, or
+// nil if pageSize is not positive
+func NewPager(reader client.Reader, pageSize int64) *Pager {
+	if pageSize <= 0 {
+		return nil
+	}
+	return &Pager{Reader: reader, PageSize: pageSize}
+}
+
+// EachListItem lists the objects of list with opts and calls visit with each
+// of them. A nil pager lists them with fallback, typically the cached client,
+// in a single call and without deep copying them out of the cache, unless
+// fallback rewrites what it reads as the state client does. list holds
+// the last page afterwards, and visit must neither modify nor keep the objects
+// it is called with, only deep copies of them.
+func (p *Pager) EachListItem(ctx context.Context, fallback client.Reader, list client.ObjectList, visit func(client.Object) error, opts ...client.ListOption) error {
+	if p == nil {
+		fallbackOpts := append(append([]client.ListOption{}, opts...), client.UnsafeDisableDeepCopy)
+		if err := fallback.List(ctx, list, fallbackOpts...); err != nil {
+			return err
+		}
+		return eachObject(list, visit)
+	}
+
+	token := ""
+	for {
+		pageOpts := append(append([]client.ListOption{}, opts...), client.Limit(p.PageSize), client.Continue(token))
+		if err := p.Reader.List(ctx, list, pageOpts...); err != nil {
+			return err
+		}
+		if err := eachObject(list, visit); err != nil {
+			return err
+		}
+		if token = list.GetContinue(); token == "" {
+			return nil
+		}
+	}
+}
+
+// eachObject calls visit with each object of list
+func eachObject(list client.ObjectList, visit func(client.Object) error) error {
+	return meta.EachListItem(list, func(item runtime.Object) error {
+		if obj, ok := item.(client.Object); ok {
+			return visit(obj)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// pagingReader serves the deployments of its client in pages, with the
+// offset of the next page as continue token, like the API server
+type pagingReader struct {
+	client.Client
+	pages int
+}
+
+func (p *pagingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	all := &appsv1.DeploymentList{}
+	if err := p.Client.List(ctx, all, opts...); err != nil {
+		return err
+	}
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := min(start+int(listOpts.Limit), len(all.Items))
+	page := list.(*appsv1.DeploymentList)
+	page.Items = all.Items[start:end]
+	page.Continue = ""
+	if end < len(all.Items) {
+		page.Continue = strconv.Itoa(end)
+	}
+	p.pages++
+	return nil
+}
+
+func TestPager(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	var want []string
+	for i := range 5 {
+		name := fmt.Sprintf("app-%d", i)
+		builder = builder.WithObjects(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}})
+		want = append(want, "shop/"+name)
+	}
+	c := builder.Build()
+
+	for _, tt := range []struct {
+		name      string
+		pageSize  int64
+		wantPages int
+	}{
+		{name: "cached", pageSize: 0},
+		{name: "paged", pageSize: 2, wantPages: 3},
+		{name: "single page", pageSize: 10, wantPages: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &pagingReader{Client: c}
+			var got []string
+			err := NewPager(reader, tt.pageSize).EachListItem(ctx, c, &appsv1.DeploymentList{}, func(obj client.Object) error {
+				got = append(got, obj.GetNamespace()+"/"+obj.GetName())
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("EachListItem() failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("visited %v, want %v", got, want)
+			}
+			if reader.pages != tt.wantPages {
+				t.Errorf("listed %d pages, want %d", reader.pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+// cacheReader records whether lists asked the cache not to deep copy
+type cacheReader struct {
+	client.Client
+	noDeepCopy bool
+}
+
+func (c *cacheReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	c.noDeepCopy = listOpts.UnsafeDisableDeepCopy != nil && *listOpts.UnsafeDisableDeepCopy
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestPagerFallbackSkipsDeepCopy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	fallback := &cacheReader{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	var pager *Pager
+	err := pager.EachListItem(context.Background(), fallback, &appsv1.DeploymentList{}, func(client.Object) error { return nil })
+	if err != nil {
+		t.Fatalf("EachListItem() failed: %v", err)
+	}
+	if !fallback.noDeepCopy {
+		t.Error("fallback list deep copied the cached objects")
+	}
+}
@@ -3,13 +3,38 @@ package utils
 import (
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	v1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// FieldManager is the field manager of the writes of the controller, so
+// changes of other managers can be told apart in managedFields
+const FieldManager = "kubedynamicscaler"
+
+// maxFieldManagerLength is the longest field manager name the API server accepts
+const maxFieldManagerLength = 128
+
+// ChangeFieldManager returns the field manager of a write made for a trigger
+// and an override, e.g. kubedynamicscaler/override/black-friday, so audit
+// logs of the write name its reason
+func ChangeFieldManager(trigger, override string) string {
+	manager := FieldManager + "/" + trigger + "/" + override
+	if len(manager) > maxFieldManagerLength {
+		manager = manager[:maxFieldManagerLength]
+	}
+	return manager
+}
+
+// IsControllerFieldManager returns true if manager is a field manager of the controller
+func IsControllerFieldManager(manager string) bool {
+	return manager == FieldManager || strings.HasPrefix(manager, FieldManager+"/")
+}
+
 const (
 	// Domain prefix for all annotations
 	annotationDomain = "kubedynamicscaler.io"
@@ -20,7 +45,39 @@ const (
 	LastUpdateAnnotation          = annotationDomain + "/last-update"
 	ManagedAnnotation             = annotationDomain + "/managed"
 	GlobalConfigManagedAnnotation = annotationDomain + "/global-config-managed"
-	ManagementModeAnnotation      = annotationDomain + "/management-mode" // Values: "direct" or "hpa"
+	ManagementModeAnnotation      = annotationDomain + "/management-mode" // Values: "direct", "hpa" or "limits-only"
+	ExternalScalerAnnotation      = annotationDomain + "/external-scaler" // Detected external autoscaler in limits-only mode
+	PodHourlyCostAnnotation       = annotationDomain + "/pod-hourly-cost" // Overrides the configured per-pod hourly cost
+	PercentageAnnotation          = annotationDomain + "/percentage"      // Per-workload percentage, takes precedence over any override
+	ExplainAnnotation             = annotationDomain + "/explain"         // JSON record of the rules that produced the current replicas
+	BaselineRecordedAnnotation    = annotationDomain + "/baseline-at"     // When the originals were last captured, for baselineRefresh
+	BrokenHPAAnnotation           = annotationDomain + "/broken-hpa"      // HPA that cannot scale and why, while replicas are scaled directly
+	ChangeReasonAnnotation        = annotationDomain + "/change-reason"   // JSON override, trigger and percentage of the last replica change
+	OriginalsUIDAnnotation        = annotationDomain + "/originals-uid"   // UID of the object the originals were recorded on, they are dropped on another one
+
+	// ScaleDownProtectedUntilAnnotation is the end of the cluster-autoscaler protection of a scaled-up deployment, or "expired"
+	ScaleDownProtectedUntilAnnotation = annotationDomain + "/scale-down-protected-until"
+
+	// DownscaleDrainAnnotation is the target replicas and RFC3339 end of the drain period of a pending scale-down, as "<replicas>@<end>"
+	DownscaleDrainAnnotation = annotationDomain + "/downscale-drain"
+
+	// Pod annotations
+	ScaleDownProtectedAnnotation = annotationDomain + "/scale-down-protected" // "true" on pods marked not safe to evict by the controller
+	DrainUntilAnnotation         = annotationDomain + "/drain-until"          // RFC3339 end of the drain period of pods a pending scale-down removes
+
+	// Job annotations
+	OriginalParallelismAnnotation = annotationDomain + "/original-parallelism"
+
+	// Custom resource annotations
+	OriginalSubResourceReplicasAnnotation = annotationDomain + "/original-subresource-replicas" // JSON original replicas of the sub-resources of a resolved custom resource, by name
+
+	// ReplicasOverride annotations
+	RollbackAnnotation         = annotationDomain + "/rollback"          // "true" restores all targets to their original values and pauses the override
+	PausedAnnotation           = annotationDomain + "/paused"            // "true" stops the override from changing its targets
+	AdoptedWorkloadsAnnotation = annotationDomain + "/adopted-workloads" // Deployments a suggested override selected when the cluster was adopted
+
+	// HTTPRoute annotations
+	OriginalWeightsAnnotation = annotationDomain + "/original-weights" // JSON weights of the backendRefs shifted by trafficShift, by "<rule>/<backend>"
 
 	// HPA specific annotations
 	HPAManagedAnnotation          = annotationDomain + "/hpa-managed"
@@ -64,88 +121,119 @@ func InitializeHPAAnnotations(hpa *autoscalingv2.HorizontalPodAutoscaler) {
 	hpa.Annotations[LastHPAUpdateAnnotation] = time.Now().UTC().Format(time.RFC3339)
 }
 
-// GetOriginalReplicas gets the original replicas from annotations
+// ParseReplicas parses a replica count stored in an annotation, reporting
+// false for values that are not a non-negative int32
+func ParseReplicas(val string) (int32, bool) {
+	parsed, err := strconv.ParseInt(val, 10, 32)
+	if err != nil || parsed < 0 {
+		return 0, false
+	}
+	return int32(parsed), true
+}
+
+// scaleReplicas returns base scaled by percentage, rounded to the nearest
+// integer, at least 1 and without overflowing an int32
+func scaleReplicas(base int32, percentage float64) int32 {
+	return max(1, BoundReplicas(math.Round(float64(base)*percentage/100.0)))
+}
+
+// GetOriginalReplicas gets the original replicas from annotations, falling
+// back to the spec, where negative replicas count as 0
 func GetOriginalReplicas(deployment *appsv1.Deployment) int32 {
-	if val, exists := deployment.Annotations[OriginalReplicasAnnotation]; exists {
-		if parsed, err := strconv.ParseInt(val, 10, 32); err == nil {
-			return int32(parsed)
-		}
+	if parsed, ok := ParseReplicas(deployment.Annotations[OriginalReplicasAnnotation]); ok {
+		return parsed
+	}
+	if deployment.Spec.Replicas == nil {
+		// Kubernetes defaults the replicas of a Deployment to 1
+		return 1
 	}
-	return *deployment.Spec.Replicas
+	return max(0, *deployment.Spec.Replicas)
 }
 
-// GetOriginalHPALimits gets the original min and max replicas from annotations
+// GetOriginalHPALimits gets the original min and max replicas from annotations.
+// Annotations that do not hold a valid replica count fall back to the spec,
+// negative spec values count as 0.
 func GetOriginalHPALimits(hpa *autoscalingv2.HorizontalPodAutoscaler) (int32, int32) {
-	var originalMin, originalMax int32
-
-	if val, exists := hpa.Annotations[OriginalMinReplicasAnnotation]; exists {
-		if parsed, err := strconv.ParseInt(val, 10, 32); err == nil {
-			originalMin = int32(parsed)
-		}
+	originalMin := int32(1)
+	if parsed, ok := ParseReplicas(hpa.Annotations[OriginalMinReplicasAnnotation]); ok {
+		originalMin = parsed
 	} else if hpa.Spec.MinReplicas != nil {
-		originalMin = *hpa.Spec.MinReplicas
-	} else {
-		originalMin = 1
+		originalMin = max(0, *hpa.Spec.MinReplicas)
 	}
 
-	if val, exists := hpa.Annotations[OriginalMaxReplicasAnnotation]; exists {
-		if parsed, err := strconv.ParseInt(val, 10, 32); err == nil {
-			originalMax = int32(parsed)
-		}
-	} else {
-		originalMax = hpa.Spec.MaxReplicas
+	originalMax := max(0, hpa.Spec.MaxReplicas)
+	if parsed, ok := ParseReplicas(hpa.Annotations[OriginalMaxReplicasAnnotation]); ok {
+		originalMax = parsed
 	}
 
 	return originalMin, originalMax
 }
 
-// CalculateNewReplicas calculates the new number of replicas based on the override type and percentage
+// overrideLimits returns the min and max replicas of an override. Limits below
+// 1, which the CRD validation rejects, are treated as unset.
+func overrideLimits(override *v1.ReplicasOverride) (*int32, *int32) {
+	minReplicas, maxReplicas := override.Spec.MinReplicas, override.Spec.MaxReplicas
+	if minReplicas != nil && *minReplicas < 1 {
+		minReplicas = nil
+	}
+	if maxReplicas != nil && *maxReplicas < 1 {
+		maxReplicas = nil
+	}
+	return minReplicas, maxReplicas
+}
+
+// CalculateNewReplicas calculates the new number of replicas based on the override type and percentage.
+// A nil override keeps the original replicas.
 func CalculateNewReplicas(deployment *appsv1.Deployment, override *v1.ReplicasOverride) int32 {
 	// Get original replicas from annotations
 	baseReplicas := GetOriginalReplicas(deployment)
-
-	percentage := float64(override.Spec.ReplicasPercentage)
-	newReplicas := float64(baseReplicas) * (percentage / 100.0)
-
-	// Round to nearest integer and ensure it's at least 1
-	result := int32(math.Max(1, math.Round(newReplicas)))
-
-	// Cap the result at MaxInt32 to prevent overflow
-	if result > math.MaxInt32 {
-		result = math.MaxInt32
+	if override == nil {
+		return scaleReplicas(baseReplicas, 100)
 	}
 
+	// Round to nearest integer, at least 1 and capped at MaxInt32
+	result := scaleReplicas(baseReplicas, float64(override.Spec.ReplicasPercentage))
+
 	// Apply min and max limits if specified in the override
-	if override.Spec.MinReplicas != nil {
-		result = int32(math.Max(float64(*override.Spec.MinReplicas), float64(result)))
+	minReplicas, maxReplicas := overrideLimits(override)
+	if minReplicas != nil {
+		result = max(*minReplicas, result)
 	}
-	if override.Spec.MaxReplicas != nil {
-		result = int32(math.Min(float64(*override.Spec.MaxReplicas), float64(result)))
+	if maxReplicas != nil {
+		result = min(*maxReplicas, result)
 	}
 
 	return result
 }
 
-// CalculateHPALimits calculates new min and max replicas for an HPA based on the override
+// CalculateHPALimits calculates new min and max replicas for an HPA based on the override.
+// A nil override keeps the original limits.
 func CalculateHPALimits(hpa *autoscalingv2.HorizontalPodAutoscaler, override *v1.ReplicasOverride) (int32, int32) {
-	percentage := float64(override.Spec.ReplicasPercentage) / 100.0
+	percentage := float64(100)
+	if override != nil {
+		percentage = float64(override.Spec.ReplicasPercentage)
+	}
 
 	// Get original min and max from annotations
 	originalMin, originalMax := GetOriginalHPALimits(hpa)
 
 	// Calculate new min and max replicas based on percentage
-	newMin := int32(math.Max(1, math.Round(float64(originalMin)*percentage)))
-	newMax := int32(math.Max(float64(newMin), math.Round(float64(originalMax)*percentage)))
+	newMin := scaleReplicas(originalMin, percentage)
+	newMax := max(newMin, scaleReplicas(originalMax, percentage))
+	if override == nil {
+		return newMin, newMax
+	}
 
 	// Apply min and max limits if specified in the override
-	if override.Spec.MinReplicas != nil || override.Spec.MaxReplicas != nil {
-		if override.Spec.MinReplicas != nil {
+	minReplicas, maxReplicas := overrideLimits(override)
+	if minReplicas != nil || maxReplicas != nil {
+		if minReplicas != nil {
 			// If min is specified, use it directly
-			newMin = *override.Spec.MinReplicas
+			newMin = *minReplicas
 		}
-		if override.Spec.MaxReplicas != nil {
+		if maxReplicas != nil {
 			// If max is specified, use it directly
-			newMax = *override.Spec.MaxReplicas
+			newMax = *maxReplicas
 		}
 
 		// Ensure min is not greater than max
@@ -159,26 +247,31 @@ func CalculateHPALimits(hpa *autoscalingv2.HorizontalPodAutoscaler, override *v1
 
 // ShouldIgnoreDeployment checks if a deployment should be ignored based on the ignore rules
 func ShouldIgnoreDeployment(deployment *appsv1.Deployment, ignore *v1.GlobalReplicasIgnore) (bool, string) {
+	return ShouldIgnoreWorkload("Deployment", deployment, ignore)
+}
+
+// ShouldIgnoreWorkload checks if a workload of the given kind should be ignored based on the ignore rules
+func ShouldIgnoreWorkload(kind string, workload metav1.Object, ignore *v1.GlobalReplicasIgnore) (bool, string) {
 	// Check namespace
 	for _, ns := range ignore.Spec.IgnoreNamespaces {
-		if deployment.Namespace == ns {
+		if workload.GetNamespace() == ns {
 			return true, "Namespace is in ignore list"
 		}
 	}
 
 	// Check specific resources
 	for _, res := range ignore.Spec.IgnoreResources {
-		if res.Kind == "Deployment" && res.Name == deployment.Name {
-			if res.Namespace == "" || res.Namespace == deployment.Namespace {
-				return true, "Deployment is in ignore list"
+		if res.Kind == kind && res.Name == workload.GetName() {
+			if res.Namespace == "" || res.Namespace == workload.GetNamespace() {
+				return true, kind + " is in ignore list"
 			}
 		}
 	}
 
 	// Check labels
 	for key, value := range ignore.Spec.IgnoreLabels {
-		if deployment.Labels[key] == value {
-			return true, "Deployment has ignored label"
+		if workload.GetLabels()[key] == value {
+			return true, kind + " has ignored label"
 		}
 	}
 
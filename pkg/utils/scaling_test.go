@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"math"
 	"testing"
 
 	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
@@ -501,3 +502,161 @@ func TestGetOriginalHPALimits(t *testing.T) {
 		t.Errorf("GetOriginalHPALimits() = (%v, %v), want (1, 10)", gotMin, gotMax)
 	}
 }
+
+func TestScalingMathEdgeCases(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{OriginalReplicasAnnotation: "2147483647"}},
+	}
+	override := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: 1000}}
+	if got := CalculateNewReplicas(deployment, override); got != math.MaxInt32 {
+		t.Errorf("CalculateNewReplicas() of an overflowing result = %d, want %d", got, math.MaxInt32)
+	}
+
+	// Invalid originals fall back to the spec, which defaults to 1 replica
+	deployment.Annotations[OriginalReplicasAnnotation] = "-5"
+	if got := GetOriginalReplicas(deployment); got != 1 {
+		t.Errorf("GetOriginalReplicas() with a negative annotation and nil replicas = %d, want 1", got)
+	}
+	override.Spec.ReplicasPercentage = -50
+	if got := CalculateNewReplicas(deployment, override); got != 1 {
+		t.Errorf("CalculateNewReplicas() with a negative percentage = %d, want 1", got)
+	}
+	if got := CalculateNewReplicas(deployment, nil); got != 1 {
+		t.Errorf("CalculateNewReplicas() without override = %d, want 1", got)
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			OriginalMinReplicasAnnotation: "not-a-number",
+			OriginalMaxReplicasAnnotation: "99999999999",
+		}},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: int32Ptr(2), MaxReplicas: 6},
+	}
+	if gotMin, gotMax := GetOriginalHPALimits(hpa); gotMin != 2 || gotMax != 6 {
+		t.Errorf("GetOriginalHPALimits() with invalid annotations = (%d, %d), want (2, 6)", gotMin, gotMax)
+	}
+	override.Spec.ReplicasPercentage = 200
+	override.Spec.MinReplicas, override.Spec.MaxReplicas = int32Ptr(0), int32Ptr(-1)
+	if gotMin, gotMax := CalculateHPALimits(hpa, override); gotMin != 4 || gotMax != 12 {
+		t.Errorf("CalculateHPALimits() with invalid limits = (%d, %d), want (4, 12)", gotMin, gotMax)
+	}
+
+	if got := PercentOf(math.MaxInt32, math.MaxInt32); got != math.MaxInt32 {
+		t.Errorf("PercentOf() of an overflowing result = %d, want %d", got, math.MaxInt32)
+	}
+	if got := PercentOf(-4, 50); got != 0 {
+		t.Errorf("PercentOf() of negative replicas = %d, want 0", got)
+	}
+}
+
+// limitsFromFuzz returns the optional limits of an override from fuzz inputs
+func limitsFromFuzz(hasMin bool, minReplicas int32, hasMax bool, maxReplicas int32) (*int32, *int32) {
+	var minPtr, maxPtr *int32
+	if hasMin {
+		minPtr = &minReplicas
+	}
+	if hasMax {
+		maxPtr = &maxReplicas
+	}
+	return minPtr, maxPtr
+}
+
+func FuzzCalculateNewReplicas(f *testing.F) {
+	f.Add(int32(4), "", int32(150), false, int32(0), false, int32(0), false)
+	f.Add(int32(4), "3", int32(50), true, int32(2), true, int32(5), false)
+	f.Add(int32(0), "2147483647", int32(1000), false, int32(0), false, int32(0), false)
+	f.Add(int32(-3), "-7", int32(-100), true, int32(-1), true, int32(0), true)
+	f.Add(int32(1), "99999999999", int32(math.MaxInt32), true, int32(10), true, int32(3), true)
+
+	f.Fuzz(func(t *testing.T, replicas int32, annotation string, percentage int32, hasMin bool, minReplicas int32, hasMax bool, maxReplicas int32, nilReplicas bool) {
+		deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+		if nilReplicas {
+			deployment.Spec.Replicas = nil
+		}
+		if annotation != "" {
+			deployment.Annotations = map[string]string{OriginalReplicasAnnotation: annotation}
+		}
+		override := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: percentage}}
+		override.Spec.MinReplicas, override.Spec.MaxReplicas = limitsFromFuzz(hasMin, minReplicas, hasMax, maxReplicas)
+
+		got := CalculateNewReplicas(deployment, override)
+		validMax := hasMax && maxReplicas >= 1
+		validMin := hasMin && minReplicas >= 1
+		if got < 1 {
+			t.Fatalf("CalculateNewReplicas() = %d, want at least 1", got)
+		}
+		if validMax && got > maxReplicas {
+			t.Fatalf("CalculateNewReplicas() = %d above maxReplicas %d", got, maxReplicas)
+		}
+		if validMin && (!validMax || minReplicas <= maxReplicas) && got < minReplicas {
+			t.Fatalf("CalculateNewReplicas() = %d below minReplicas %d", got, minReplicas)
+		}
+
+		// Without limits, a higher percentage never gives fewer replicas
+		override.Spec.MinReplicas, override.Spec.MaxReplicas = nil, nil
+		if percentage < math.MaxInt32 {
+			lower := CalculateNewReplicas(deployment, override)
+			override.Spec.ReplicasPercentage = percentage + 1
+			if higher := CalculateNewReplicas(deployment, override); higher < lower {
+				t.Fatalf("CalculateNewReplicas() at %d%% = %d, fewer than %d at %d%%", percentage+1, higher, lower, percentage)
+			}
+		}
+	})
+}
+
+func FuzzCalculateHPALimits(f *testing.F) {
+	f.Add(int32(2), int32(5), "", "", int32(150), false, int32(0), false, int32(0), false)
+	f.Add(int32(2), int32(10), "3", "15", int32(50), true, int32(2), true, int32(5), false)
+	f.Add(int32(0), int32(0), "2147483647", "2147483647", int32(1000), false, int32(0), false, int32(0), true)
+	f.Add(int32(-1), int32(-1), "x", "-3", int32(-100), true, int32(7), true, int32(3), true)
+
+	f.Fuzz(func(t *testing.T, specMin, specMax int32, minAnnotation, maxAnnotation string, percentage int32, hasMin bool, minReplicas int32, hasMax bool, maxReplicas int32, nilMin bool) {
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}},
+			Spec:       autoscalingv2.HorizontalPodAutoscalerSpec{MinReplicas: &specMin, MaxReplicas: specMax},
+		}
+		if nilMin {
+			hpa.Spec.MinReplicas = nil
+		}
+		if minAnnotation != "" {
+			hpa.Annotations[OriginalMinReplicasAnnotation] = minAnnotation
+		}
+		if maxAnnotation != "" {
+			hpa.Annotations[OriginalMaxReplicasAnnotation] = maxAnnotation
+		}
+		override := &dynamicscalingv1.ReplicasOverride{Spec: dynamicscalingv1.ReplicasOverrideSpec{ReplicasPercentage: percentage}}
+		override.Spec.MinReplicas, override.Spec.MaxReplicas = limitsFromFuzz(hasMin, minReplicas, hasMax, maxReplicas)
+
+		gotMin, gotMax := CalculateHPALimits(hpa, override)
+		if gotMin < 1 {
+			t.Fatalf("CalculateHPALimits() min = %d, want at least 1", gotMin)
+		}
+		if gotMin > gotMax {
+			t.Fatalf("CalculateHPALimits() = (%d, %d), min above max", gotMin, gotMax)
+		}
+		if hasMax && maxReplicas >= 1 && gotMax != maxReplicas {
+			t.Fatalf("CalculateHPALimits() max = %d, want the override maxReplicas %d", gotMax, maxReplicas)
+		}
+
+		if gotMin, gotMax := CalculateHPALimits(hpa, nil); gotMin < 1 || gotMin > gotMax {
+			t.Fatalf("CalculateHPALimits() without override = (%d, %d)", gotMin, gotMax)
+		}
+	})
+}
+
+func FuzzPercentOf(f *testing.F) {
+	f.Add(int32(4), int32(150))
+	f.Add(int32(math.MaxInt32), int32(1000))
+	f.Add(int32(-4), int32(50))
+	f.Add(int32(4), int32(math.MinInt32))
+
+	f.Fuzz(func(t *testing.T, replicas, percentage int32) {
+		got := PercentOf(replicas, percentage)
+		if got < 0 {
+			t.Fatalf("PercentOf(%d, %d) = %d, want non-negative", replicas, percentage, got)
+		}
+		if replicas >= 0 && percentage >= 0 && percentage <= 100 && got > replicas {
+			t.Fatalf("PercentOf(%d, %d) = %d, more than the replicas", replicas, percentage, got)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StateAnnotation is the versioned JSON record of the state the controller
+// keeps on a scaled object, replacing the loose state annotations when the
+// v1 annotation format is written
+const StateAnnotation = annotationDomain + "/state"
+
+// StateVersion is the version of the StateAnnotation payload written by this controller
+const StateVersion = 1
+
+// Annotation formats the controller writes
+const (
+	// AnnotationFormatLegacy writes one annotation per state field
+	AnnotationFormatLegacy = "legacy"
+	// AnnotationFormatV1 writes the state fields in StateAnnotation
+	AnnotationFormatV1 = "v1"
+)
+
+// State is the payload of StateAnnotation. New fields are added with
+// omitempty so payloads of older versions still decode, and the version is
+// raised when the meaning of an existing field changes.
+type State struct {
+	Version int `json:"version"`
+
+	OriginalReplicas            string `json:"originalReplicas,omitempty"`
+	OriginalMinReplicas         string `json:"originalMinReplicas,omitempty"`
+	OriginalMaxReplicas         string `json:"originalMaxReplicas,omitempty"`
+	OriginalParallelism         string `json:"originalParallelism,omitempty"`
+	OriginalSubResourceReplicas string `json:"originalSubResourceReplicas,omitempty"`
+	ManagementMode              string `json:"managementMode,omitempty"`
+	Managed                     string `json:"managed,omitempty"`
+	OverrideController          string `json:"overrideController,omitempty"`
+	GlobalConfigManaged         string `json:"globalConfigManaged,omitempty"`
+	HPAManaged                  string `json:"hpaManaged,omitempty"`
+	LastUpdate                  string `json:"lastUpdate,omitempty"`
+	LastHPAUpdate               string `json:"lastHPAUpdate,omitempty"`
+	BaselineRecordedAt          string `json:"baselineRecordedAt,omitempty"`
+	OriginalsUID                string `json:"originalsUID,omitempty"`
+}
+
+// fields maps the loose annotations to the fields of the state holding them
+func (s *State) fields() map[string]*string {
+	return map[string]*string{
+		OriginalReplicasAnnotation:            &s.OriginalReplicas,
+		OriginalMinReplicasAnnotation:         &s.OriginalMinReplicas,
+		OriginalMaxReplicasAnnotation:         &s.OriginalMaxReplicas,
+		OriginalParallelismAnnotation:         &s.OriginalParallelism,
+		OriginalSubResourceReplicasAnnotation: &s.OriginalSubResourceReplicas,
+		ManagementModeAnnotation:              &s.ManagementMode,
+		ManagedAnnotation:                     &s.Managed,
+		OverrideControllerAnnotation:          &s.OverrideController,
+		GlobalConfigManagedAnnotation:         &s.GlobalConfigManaged,
+		HPAManagedAnnotation:                  &s.HPAManaged,
+		LastUpdateAnnotation:                  &s.LastUpdate,
+		LastHPAUpdateAnnotation:               &s.LastHPAUpdate,
+		BaselineRecordedAnnotation:            &s.BaselineRecordedAt,
+		OriginalsUIDAnnotation:                &s.OriginalsUID,
+	}
+}
+
+// StateAnnotations returns the loose annotations held by the state annotation
+func StateAnnotations() []string {
+	keys := make([]string, 0, 14)
+	for key := range (&State{}).fields() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// StateField returns the JSON field of the state annotation holding a loose
+// annotation, e.g. originalReplicas, or "" if the state does not hold it
+func StateField(key string) string {
+	state := &State{}
+	pointer, held := state.fields()[key]
+	if !held {
+		return ""
+	}
+	value := reflect.ValueOf(state).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Field(i).Addr().Interface() == pointer {
+			name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+			return name
+		}
+	}
+	return ""
+}
+
+// ExpandState replaces the state annotation of obj with the loose annotations
+// it holds, so the rest of the controller only deals with loose annotations.
+// Loose annotations already set win: they were written by a controller, or a
+// tool, using the legacy format after the state was recorded. It returns an
+// error if the state cannot be decoded, leaving obj unchanged.
+func ExpandState(obj metav1.Object) error {
+	annotations := obj.GetAnnotations()
+	recorded, exists := annotations[StateAnnotation]
+	if !exists {
+		return nil
+	}
+	state, err := parseState(recorded)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation of %s/%s: %w", StateAnnotation, obj.GetNamespace(), obj.GetName(), err)
+	}
+	for key, value := range state.fields() {
+		if _, set := annotations[key]; !set && *value != "" {
+			annotations[key] = *value
+		}
+	}
+	delete(annotations, StateAnnotation)
+	// Unstructured objects return a copy of their annotations
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// CollapseState moves the loose state annotations of obj into the state
+// annotation, removing it when none is set
+func CollapseState(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		return
+	}
+	state := State{Version: StateVersion}
+	found := false
+	for key, value := range state.fields() {
+		if loose, set := annotations[key]; set {
+			*value = loose
+			found = true
+			delete(annotations, key)
+		}
+	}
+	if found {
+		data, _ := json.Marshal(state)
+		annotations[StateAnnotation] = string(data)
+	} else {
+		delete(annotations, StateAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+}
+
+// parseState decodes a state annotation, migrating payloads of older versions
+func parseState(recorded string) (*State, error) {
+	state := &State{}
+	if err := json.Unmarshal([]byte(recorded), state); err != nil {
+		return nil, err
+	}
+	switch {
+	case state.Version > StateVersion:
+		// Written by a newer controller: the fields known here keep their meaning
+	case state.Version < 1:
+		return nil, fmt.Errorf("unsupported state version %d", state.Version)
+	}
+	return state, nil
+}
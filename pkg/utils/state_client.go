@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stateClient expands the state annotation of the objects it reads into loose
+// annotations, and writes them back in its annotation format
+type stateClient struct {
+	client.Client
+	format string
+}
+
+// NewStateClient returns a client reading both annotation formats and writing
+// the state of objects in format, AnnotationFormatV1 or AnnotationFormatLegacy.
+// Objects are migrated to format on their next write.
+func NewStateClient(c client.Client, format string) (client.Client, error) {
+	switch format {
+	case AnnotationFormatLegacy, AnnotationFormatV1:
+	default:
+		return nil, fmt.Errorf("unknown annotation format %q, want %s or %s", format, AnnotationFormatLegacy, AnnotationFormatV1)
+	}
+	return &stateClient{Client: c, format: format}, nil
+}
+
+// Get implements client.Client
+func (c *stateClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.Client.Get(ctx, key, obj, opts...); err != nil {
+		return err
+	}
+	return ExpandState(obj)
+}
+
+// List implements client.Client. Items whose state annotation is invalid keep it.
+// The items are always deep copied out of the cache, expanding them would
+// otherwise rewrite the annotations of the objects shared by the informer.
+func (c *stateClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	copied := make([]client.ListOption, 0, len(opts))
+	for _, opt := range opts {
+		if _, unsafe := opt.(client.UnsafeDisableDeepCopyOption); !unsafe {
+			copied = append(copied, opt)
+		}
+	}
+	if err := c.Client.List(ctx, list, copied...); err != nil {
+		return err
+	}
+	return meta.EachListItem(list, func(item runtime.Object) error {
+		if obj, ok := item.(metav1.Object); ok {
+			_ = ExpandState(obj)
+		}
+		return nil
+	})
+}
+
+// Create implements client.Client
+func (c *stateClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.write(obj, func() error { return c.Client.Create(ctx, obj, opts...) })
+}
+
+// Update implements client.Client
+func (c *stateClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.write(obj, func() error { return c.Client.Update(ctx, obj, opts...) })
+}
+
+// Patch implements client.Client. A merge patch computed from an expanded
+// object sets the state annotation and removes the loose ones it holds, as
+// does an AnnotationsPatch of the object.
+func (c *stateClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if annotations, ok := patch.(*annotationsPatch); ok && c.format == AnnotationFormatV1 {
+		patch = annotations.collapsed(obj)
+	}
+	return c.write(obj, func() error { return c.Client.Patch(ctx, obj, patch, opts...) })
+}
+
+// write collapses the state of obj for the v1 format while it is written,
+// then expands the object returned by the API server again
+func (c *stateClient) write(obj client.Object, write func() error) error {
+	if c.format == AnnotationFormatV1 {
+		CollapseState(obj)
+	}
+	err := write()
+	if expandErr := ExpandState(obj); err == nil {
+		err = expandErr
+	}
+	return err
+}
+
+// MigrateState rewrites the objects of lists whose annotations are not in
+// format yet, and returns how many were migrated. The objects are listed with
+// reader, typically uncached, a page at a time with a non-nil pager, and kinds
+// it is not allowed to list, or that are not installed, are skipped. Objects
+// changed since they were listed are left for their next write.
+func MigrateState(ctx context.Context, pager *Pager, reader client.Reader, writer client.Writer, format string, lists ...client.ObjectList) (int, error) {
+	migrated := 0
+	for _, list := range lists {
+		err := pager.EachListItem(ctx, reader, list, func(obj client.Object) error {
+			updated := obj.DeepCopyObject().(client.Object)
+			if err := ExpandState(updated); err != nil {
+				return err
+			}
+			if format == AnnotationFormatV1 {
+				CollapseState(updated)
+			}
+			if reflect.DeepEqual(updated.GetAnnotations(), obj.GetAnnotations()) {
+				return nil
+			}
+			err := writer.Patch(ctx, updated, client.MergeFromWithOptions(obj, client.MergeFromWithOptimisticLock{}))
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			migrated++
+			return nil
+		})
+		if apierrors.IsForbidden(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return migrated, err
+		}
+	}
+	return migrated, nil
+}
@@ -0,0 +1,248 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	loose := map[string]string{
+		OriginalReplicasAnnotation: "4",
+		ManagementModeAnnotation:   "direct",
+		ManagedAnnotation:          "true",
+		PercentageAnnotation:       "50",
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	for key, value := range loose {
+		deployment.Annotations[key] = value
+	}
+
+	CollapseState(deployment)
+	want := map[string]string{
+		PercentageAnnotation: "50",
+		StateAnnotation:      `{"version":1,"originalReplicas":"4","managementMode":"direct","managed":"true"}`,
+	}
+	if !reflect.DeepEqual(deployment.Annotations, want) {
+		t.Errorf("collapsed annotations = %v, want %v", deployment.Annotations, want)
+	}
+
+	if err := ExpandState(deployment); err != nil {
+		t.Fatalf("ExpandState() failed: %v", err)
+	}
+	if !reflect.DeepEqual(deployment.Annotations, loose) {
+		t.Errorf("expanded annotations = %v, want %v", deployment.Annotations, loose)
+	}
+}
+
+func TestStateField(t *testing.T) {
+	for key, want := range map[string]string{
+		OriginalReplicasAnnotation: "originalReplicas",
+		ManagementModeAnnotation:   "managementMode",
+		HPAManagedAnnotation:       "hpaManaged",
+		BrokenHPAAnnotation:        "",
+	} {
+		if got := StateField(key); got != want {
+			t.Errorf("StateField(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestExpandState(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "loose annotations written later win",
+			annotations: map[string]string{StateAnnotation: `{"version":1,"originalReplicas":"4","lastUpdate":"2025-06-01T00:00:00Z"}`, OriginalReplicasAnnotation: "6"},
+			want:        map[string]string{OriginalReplicasAnnotation: "6", LastUpdateAnnotation: "2025-06-01T00:00:00Z"},
+		},
+		{
+			name:        "fields unknown to an older controller are ignored",
+			annotations: map[string]string{StateAnnotation: `{"version":2,"originalReplicas":"4","managerIdentity":"kds-2"}`},
+			want:        map[string]string{OriginalReplicasAnnotation: "4"},
+		},
+		{
+			name:        "invalid state is kept",
+			annotations: map[string]string{StateAnnotation: `{"version":0}`},
+			want:        map[string]string{StateAnnotation: `{"version":0}`},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			if err := ExpandState(deployment); (err != nil) != tt.wantErr {
+				t.Errorf("ExpandState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(deployment.Annotations, tt.want) {
+				t.Errorf("annotations = %v, want %v", deployment.Annotations, tt.want)
+			}
+		})
+	}
+}
+
+func TestStateClient(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	legacy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{
+		OriginalReplicasAnnotation: "4",
+		ManagedAnnotation:          "true",
+	}}}
+	inner := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacy).Build()
+	c, err := NewStateClient(inner, AnnotationFormatV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := types.NamespacedName{Name: "web", Namespace: "shop"}
+	raw := func() map[string]string {
+		got := &appsv1.Deployment{}
+		if err := inner.Get(ctx, key, got); err != nil {
+			t.Fatal(err)
+		}
+		return got.Annotations
+	}
+
+	// A write migrates the legacy annotations, the caller keeps seeing them loose
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, key, deployment); err != nil {
+		t.Fatal(err)
+	}
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Annotations[LastUpdateAnnotation] = "2025-06-01T00:00:00Z"
+	if err := c.Patch(ctx, deployment, patch); err != nil {
+		t.Fatal(err)
+	}
+	if deployment.Annotations[OriginalReplicasAnnotation] != "4" {
+		t.Errorf("annotations after the write = %v, want loose annotations", deployment.Annotations)
+	}
+	want := map[string]string{StateAnnotation: `{"version":1,"originalReplicas":"4","managed":"true","lastUpdate":"2025-06-01T00:00:00Z"}`}
+	if got := raw(); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored annotations = %v, want %v", got, want)
+	}
+
+	// Migrating back to the legacy format restores the loose annotations
+	migrated, err := MigrateState(ctx, nil, inner, inner, AnnotationFormatLegacy, &appsv1.DeploymentList{})
+	if err != nil || migrated != 1 {
+		t.Fatalf("MigrateState() = %d, %v, want 1 migrated", migrated, err)
+	}
+	want = map[string]string{
+		OriginalReplicasAnnotation: "4",
+		ManagedAnnotation:          "true",
+		LastUpdateAnnotation:       "2025-06-01T00:00:00Z",
+	}
+	if got := raw(); !reflect.DeepEqual(got, want) {
+		t.Errorf("stored annotations after migrating back = %v, want %v", got, want)
+	}
+}
+
+// stalePagingReader pages like pagingReader, then changes the stale deployment
+// behind the back of the caller, like another writer would
+type stalePagingReader struct {
+	pagingReader
+}
+
+func (r *stalePagingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := r.pagingReader.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	stale := &appsv1.Deployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: "stale", Namespace: "shop"}, stale); err != nil {
+		return err
+	}
+	stale.Labels = map[string]string{"changed": "true"}
+	return r.Client.Update(ctx, stale)
+}
+
+func TestMigrateStatePaged(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	legacy := func(name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Annotations: map[string]string{
+			OriginalReplicasAnnotation: "4",
+		}}}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacy("api"), legacy("stale"), legacy("web")).Build()
+	reader := &stalePagingReader{pagingReader{Client: c}}
+
+	migrated, err := MigrateState(ctx, NewPager(reader, 2), c, c, AnnotationFormatV1, &appsv1.DeploymentList{})
+	if err != nil {
+		t.Fatalf("MigrateState() failed: %v", err)
+	}
+	// The deployment changed since it was listed is left for its next write
+	if migrated != 2 || reader.pages != 2 {
+		t.Errorf("MigrateState() = %d migrated in %d pages, want 2 in 2 pages", migrated, reader.pages)
+	}
+	for name, want := range map[string]string{"api": StateAnnotation, "stale": OriginalReplicasAnnotation, "web": StateAnnotation} {
+		deployment := &appsv1.Deployment{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "shop"}, deployment); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := deployment.Annotations[want]; !ok {
+			t.Errorf("annotations of %s = %v, want %s", name, deployment.Annotations, want)
+		}
+	}
+}
+
+// informerReader serves the objects of an informer store: without a deep copy
+// when asked to, the listed items then share the annotations of the store
+type informerReader struct {
+	client.Client
+	store []appsv1.Deployment
+}
+
+func (r *informerReader) List(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	items := list.(*appsv1.DeploymentList)
+	if listOpts.UnsafeDisableDeepCopy != nil && *listOpts.UnsafeDisableDeepCopy {
+		items.Items = append([]appsv1.Deployment(nil), r.store...)
+		return nil
+	}
+	items.Items = nil
+	for i := range r.store {
+		items.Items = append(items.Items, *r.store[i].DeepCopy())
+	}
+	return nil
+}
+
+func TestStateClientListKeepsCacheIntact(t *testing.T) {
+	state := `{"version":1,"originalReplicas":"4"}`
+	informer := &informerReader{store: []appsv1.Deployment{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop", Annotations: map[string]string{StateAnnotation: state}},
+	}}}
+	c, err := NewStateClient(informer, AnnotationFormatV1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pager *Pager
+	var expanded string
+	err = pager.EachListItem(context.Background(), c, &appsv1.DeploymentList{}, func(obj client.Object) error {
+		expanded = obj.GetAnnotations()[OriginalReplicasAnnotation]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachListItem() failed: %v", err)
+	}
+	if expanded != "4" {
+		t.Errorf("visited original replicas = %q, want the expanded state", expanded)
+	}
+	want := map[string]string{StateAnnotation: state}
+	if got := informer.store[0].Annotations; !reflect.DeepEqual(got, want) {
+		t.Errorf("cached annotations = %v, want %v untouched", got, want)
+	}
+}
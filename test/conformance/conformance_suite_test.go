@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dynamicscalingv1 "github.com/KubeDynamicScaler/kubedynamicscaler/api/v1"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	kdstesting "github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing"
+)
+
+var (
+	// Optional Environment Variables:
+	// - KDS_CONFORMANCE_MODIFY_CONFIG=true: also runs the specs changing the
+	//   global configuration, which affects every workload of the cluster while
+	//   they run. The original ConfigMap is restored afterwards.
+	// - CONFIG_NAMESPACE: namespace of the controller ConfigMap, if not the default one.
+	modifyConfig = os.Getenv("KDS_CONFORMANCE_MODIFY_CONFIG") == "true"
+
+	ctx       context.Context
+	k8sClient client.Client
+	namespace string
+)
+
+const (
+	// timeout is how long the controller has to converge after a change
+	timeout = 2 * time.Minute
+	// interval is how often the cluster is polled while waiting
+	interval = 2 * time.Second
+)
+
+// TestConformance validates a KubeDynamicScaler installation in the cluster of
+// the current kubeconfig context. It only creates objects in a temporary
+// namespace, so it can be run against a staging cluster before rolling the
+// controller out to production.
+func TestConformance(t *testing.T) {
+	RegisterFailHandler(Fail)
+	_, _ = fmt.Fprintf(GinkgoWriter, "Starting kubedynamicscaler conformance suite\n")
+	RunSpecs(t, "conformance suite")
+}
+
+var _ = BeforeSuite(func() {
+	ctx = context.Background()
+	SetDefaultEventuallyTimeout(timeout)
+	SetDefaultEventuallyPollingInterval(interval)
+	SetDefaultConsistentlyDuration(20 * time.Second)
+	SetDefaultConsistentlyPollingInterval(interval)
+
+	cfg, err := ctrl.GetConfig()
+	Expect(err).NotTo(HaveOccurred(), "Failed to load the kubeconfig")
+	k8sClient, err = client.New(cfg, client.Options{Scheme: kdstesting.Scheme()})
+	Expect(err).NotTo(HaveOccurred())
+
+	By("checking the CRDs are installed")
+	Expect(k8sClient.List(ctx, &dynamicscalingv1.ReplicasOverrideList{})).To(Succeed(), "The ReplicasOverride CRD is not installed")
+
+	By("creating the test namespace")
+	namespace = fmt.Sprintf("kds-conformance-%d", time.Now().Unix())
+	Expect(k8sClient.Create(ctx, kdstesting.Namespace(namespace))).To(Succeed())
+})
+
+var _ = AfterSuite(func() {
+	if namespace != "" {
+		By("deleting the test namespace")
+		_ = k8sClient.Delete(ctx, kdstesting.Namespace(namespace))
+	}
+})
+
+// controllerNamespace returns the namespace of the controller ConfigMap
+func controllerNamespace() string {
+	if ns := os.Getenv(config.EnvConfigNamespace); ns != "" {
+		return ns
+	}
+	return config.DefaultConfigMapNamespace
+}
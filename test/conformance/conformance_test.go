@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"gopkg.in/yaml.v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/config"
+	kdstesting "github.com/KubeDynamicScaler/kubedynamicscaler/pkg/testing"
+	"github.com/KubeDynamicScaler/kubedynamicscaler/pkg/utils"
+)
+
+// currentConfig returns the global configuration the controller runs with
+func currentConfig() *config.GlobalConfig {
+	cm := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: config.ConfigMapName, Namespace: controllerNamespace()}, cm)
+	if apierrors.IsNotFound(err) {
+		return config.DefaultConfig()
+	}
+	Expect(err).NotTo(HaveOccurred())
+	cfg := &config.GlobalConfig{}
+	Expect(yaml.Unmarshal([]byte(cm.Data[config.ConfigMapKey]), cfg)).To(Succeed())
+	return cfg
+}
+
+// expected returns the replicas the controller gives original replicas
+// scaled by percentage, within the global limits
+func expected(cfg *config.GlobalConfig, original, percentage int32) int32 {
+	return min(max(int32(float64(original)*float64(percentage)/100.0), cfg.MinReplicas), cfg.MaxReplicas)
+}
+
+// deploymentReplicas returns a function polling the replicas of a deployment
+func deploymentReplicas(name string) func() (int32, error) {
+	return func() (int32, error) {
+		return kdstesting.Replicas(ctx, k8sClient, namespace, name)
+	}
+}
+
+// deploymentAnnotation returns a function polling an annotation of a deployment
+func deploymentAnnotation(name, key string) func() (string, error) {
+	return func() (string, error) {
+		deployment := &appsv1.Deployment{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, deployment)
+		return deployment.Annotations[key], err
+	}
+}
+
+// create creates obj in the test namespace
+func create(obj client.Object) {
+	obj.SetNamespace(namespace)
+	ExpectWithOffset(1, k8sClient.Create(ctx, obj)).To(Succeed())
+}
+
+var _ = Describe("KubeDynamicScaler", Ordered, func() {
+	var cfg *config.GlobalConfig
+
+	BeforeAll(func() {
+		cfg = currentConfig()
+		GinkgoWriter.Printf("Global config: %d%% within [%d, %d] replicas\n", cfg.GlobalPercentage, cfg.MinReplicas, cfg.MaxReplicas)
+	})
+
+	It("scales deployments without override with the global configuration", func() {
+		if !cfg.Enabled {
+			Skip("the global config only scales deployments without override once it sets enabled: true")
+		}
+		create(kdstesting.Deployment(namespace, "global", 2, nil))
+
+		Eventually(deploymentAnnotation("global", utils.OriginalReplicasAnnotation)).Should(Equal("2"))
+		Eventually(deploymentReplicas("global")).Should(Equal(expected(cfg, 2, cfg.GlobalPercentage)))
+	})
+
+	It("applies an override over the global configuration, and a workload annotation over the override", func() {
+		create(kdstesting.Override(namespace, "frontend").Percentage(300).Selector(map[string]string{"tier": "frontend"}).Build())
+		create(kdstesting.Deployment(namespace, "web", 2, map[string]string{"tier": "frontend"}))
+		Eventually(deploymentReplicas("web")).Should(Equal(expected(cfg, 2, 300)))
+
+		deployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "web", Namespace: namespace}, deployment)).To(Succeed())
+		patch := client.MergeFrom(deployment.DeepCopy())
+		deployment.Annotations[utils.PercentageAnnotation] = "50"
+		Expect(k8sClient.Patch(ctx, deployment, patch)).To(Succeed())
+		Eventually(deploymentReplicas("web")).Should(Equal(expected(cfg, 2, 50)))
+	})
+
+	It("scales the limits of the HPA of a deployment instead of its replicas", func() {
+		create(kdstesting.Deployment(namespace, "api", 2, nil))
+		create(kdstesting.HPA(namespace, "api", 2, 4))
+		create(kdstesting.Override(namespace, "api").Percentage(200).Deployment("api").Build())
+
+		Eventually(func() ([]int32, error) {
+			minReplicas, maxReplicas, err := kdstesting.HPALimits(ctx, k8sClient, namespace, "api")
+			return []int32{minReplicas, maxReplicas}, err
+		}).Should(Equal([]int32{expected(cfg, 2, 200), expected(cfg, 4, 200)}))
+		Eventually(deploymentAnnotation("api", utils.ManagementModeAnnotation)).Should(Equal("hpa"))
+	})
+
+	It("leaves ignored deployments untouched", func() {
+		ignore := kdstesting.Ignore("conformance").Resource("Deployment", namespace, "ignored").Build()
+		create(ignore)
+		create(kdstesting.Override(namespace, "batch").Percentage(300).Selector(map[string]string{"tier": "batch"}).Build())
+		create(kdstesting.Deployment(namespace, "ignored", 2, map[string]string{"tier": "batch"}))
+
+		Consistently(deploymentReplicas("ignored")).Should(Equal(int32(2)))
+		Expect(deploymentAnnotation("ignored", utils.OriginalReplicasAnnotation)()).To(BeEmpty())
+	})
+
+	It("restores deployments to the global configuration when their override is deleted", func() {
+		override := kdstesting.Override(namespace, "restore").Percentage(300).Deployment("restore").Build()
+		create(kdstesting.Deployment(namespace, "restore", 2, nil))
+		create(override)
+		Eventually(deploymentReplicas("restore")).Should(Equal(expected(cfg, 2, 300)))
+
+		Expect(k8sClient.Delete(ctx, override)).To(Succeed())
+		Eventually(deploymentReplicas("restore")).Should(Equal(expected(cfg, 2, cfg.GlobalPercentage)))
+	})
+
+	It("applies a new global configuration without restart", func() {
+		if !modifyConfig {
+			Skip("changes the global configuration of the whole cluster, set KDS_CONFORMANCE_MODIFY_CONFIG=true to run it")
+		}
+
+		key := types.NamespacedName{Name: config.ConfigMapName, Namespace: controllerNamespace()}
+		original := &corev1.ConfigMap{}
+		err := k8sClient.Get(ctx, key, original)
+		if apierrors.IsNotFound(err) {
+			original = nil
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		DeferCleanup(func() {
+			current := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, key, current)).To(Succeed())
+			if original == nil {
+				Expect(k8sClient.Delete(ctx, current)).To(Succeed())
+				return
+			}
+			current.Data = original.Data
+			Expect(k8sClient.Update(ctx, current)).To(Succeed())
+		})
+
+		create(kdstesting.Deployment(namespace, "reload", 2, nil))
+		create(kdstesting.Override(namespace, "reload").Percentage(500).Deployment("reload").Build())
+		Eventually(deploymentReplicas("reload")).Should(Equal(expected(cfg, 2, 500)))
+
+		reloaded := *cfg
+		reloaded.MaxReplicas = 3
+		data, err := yaml.Marshal(&reloaded)
+		Expect(err).NotTo(HaveOccurred())
+		cm := &corev1.ConfigMap{}
+		cm.Name, cm.Namespace = key.Name, key.Namespace
+		cm.Data = map[string]string{config.ConfigMapKey: string(data)}
+		if original == nil {
+			Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+		} else {
+			cm.ResourceVersion = original.ResourceVersion
+			Expect(k8sClient.Update(ctx, cm)).To(Succeed())
+		}
+		Eventually(deploymentReplicas("reload")).Should(Equal(expected(&reloaded, 2, 500)))
+	})
+})